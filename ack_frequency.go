@@ -0,0 +1,102 @@
+package quic
+
+import (
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/utils"
+	"github.com/quic-go/quic-go/internal/wire"
+)
+
+const (
+	// The number of ACKs we'd like to receive per congestion window.
+	acksPerCongestionWindow = 4
+	// The maximum ack-eliciting threshold we request.
+	// Larger values delay loss detection and congestion window growth too much.
+	maxAckElicitingThreshold = 10
+	// The reordering threshold we request.
+	// It matches the packet threshold used for loss detection.
+	ackFrequencyReorderingThreshold = 3
+	// The default ack-eliciting threshold, as defined in RFC 9000.
+	defaultAckElicitingThreshold = 1
+)
+
+// The ackFrequencyManager implements the sender side of the ACK frequency extension
+// (draft-ietf-quic-ack-frequency-11).
+// For connections with a large congestion window, acknowledging every other packet
+// wastes a lot of resources on both endpoints. Instead, we ask the peer to send
+// a fixed number of ACKs per congestion window.
+type ackFrequencyManager struct {
+	peerMinAckDelay time.Duration
+	peerMaxAckDelay time.Duration
+
+	sentFrame       bool
+	nextSeq         uint64
+	lastThreshold   uint64
+	lastMaxAckDelay time.Duration
+	lastEvaluation  monotime.Time
+}
+
+func newAckFrequencyManager(peerMinAckDelay, peerMaxAckDelay time.Duration) *ackFrequencyManager {
+	peerMinAckDelay = max(peerMinAckDelay, protocol.TimerGranularity)
+	return &ackFrequencyManager{
+		peerMinAckDelay: peerMinAckDelay,
+		peerMaxAckDelay: max(peerMaxAckDelay, peerMinAckDelay),
+	}
+}
+
+// GetFrame returns an ACK_FREQUENCY frame if the values requested from the peer should be updated.
+// The values are evaluated at most once per RTT.
+func (m *ackFrequencyManager) GetFrame(
+	cwnd, maxDatagramSize protocol.ByteCount,
+	rttStats *utils.RTTStats,
+	now monotime.Time,
+) *wire.AckFrequencyFrame {
+	if !rttStats.HasMeasurement() || maxDatagramSize == 0 {
+		return nil
+	}
+	srtt := rttStats.SmoothedRTT()
+	if !m.lastEvaluation.IsZero() && now.Sub(m.lastEvaluation) < srtt {
+		return nil
+	}
+	m.lastEvaluation = now
+
+	// The peer sends an ACK once it received more than threshold ack-eliciting packets.
+	threshold := uint64(cwnd / maxDatagramSize / acksPerCongestionWindow)
+	if threshold > 0 {
+		threshold--
+	}
+	threshold = min(max(threshold, defaultAckElicitingThreshold), maxAckElicitingThreshold)
+	maxAckDelay := min(max(srtt/acksPerCongestionWindow, m.peerMinAckDelay), m.peerMaxAckDelay)
+
+	if !m.sentFrame {
+		// As long as the default values work well, there's no need to send a frame.
+		if threshold == defaultAckElicitingThreshold {
+			return nil
+		}
+	} else if threshold == m.lastThreshold && !m.ackDelayChangedSignificantly(maxAckDelay) {
+		return nil
+	}
+	m.sentFrame = true
+	m.lastThreshold = threshold
+	m.lastMaxAckDelay = maxAckDelay
+	f := &wire.AckFrequencyFrame{
+		SequenceNumber:        m.nextSeq,
+		AckElicitingThreshold: threshold,
+		RequestMaxAckDelay:    maxAckDelay,
+		ReorderingThreshold:   ackFrequencyReorderingThreshold,
+	}
+	m.nextSeq++
+	return f
+}
+
+// ackDelayChangedSignificantly says if the ack delay changed by more than 25%
+// compared to the value sent in the last ACK_FREQUENCY frame.
+func (m *ackFrequencyManager) ackDelayChangedSignificantly(d time.Duration) bool {
+	diff := d - m.lastMaxAckDelay
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff > m.lastMaxAckDelay/4
+}
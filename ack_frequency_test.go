@@ -0,0 +1,77 @@
+package quic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/utils"
+	"github.com/quic-go/quic-go/internal/wire"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckFrequencyManager(t *testing.T) {
+	const maxDatagramSize = 1000
+	m := newAckFrequencyManager(time.Millisecond, 25*time.Millisecond)
+	rttStats := utils.NewRTTStats()
+
+	now := monotime.Now()
+	// no RTT measurement yet
+	require.Nil(t, m.GetFrame(100*maxDatagramSize, maxDatagramSize, rttStats, now))
+	rttStats.UpdateRTT(40*time.Millisecond, 0)
+
+	// small congestion window: the default values work fine
+	require.Nil(t, m.GetFrame(10*maxDatagramSize, maxDatagramSize, rttStats, now))
+	// the values are only evaluated once per RTT
+	require.Nil(t, m.GetFrame(100*maxDatagramSize, maxDatagramSize, rttStats, now.Add(39*time.Millisecond)))
+
+	now = now.Add(40 * time.Millisecond)
+	require.Equal(t, &wire.AckFrequencyFrame{
+		SequenceNumber:        0,
+		AckElicitingThreshold: 4,
+		RequestMaxAckDelay:    10 * time.Millisecond,
+		ReorderingThreshold:   ackFrequencyReorderingThreshold,
+	}, m.GetFrame(20*maxDatagramSize, maxDatagramSize, rttStats, now))
+
+	// no new frame if the values didn't change
+	now = now.Add(40 * time.Millisecond)
+	require.Nil(t, m.GetFrame(20*maxDatagramSize, maxDatagramSize, rttStats, now))
+
+	// the ack-eliciting threshold is capped
+	now = now.Add(40 * time.Millisecond)
+	f := m.GetFrame(1000*maxDatagramSize, maxDatagramSize, rttStats, now)
+	require.NotNil(t, f)
+	require.Equal(t, uint64(1), f.SequenceNumber)
+	require.Equal(t, uint64(maxAckElicitingThreshold), f.AckElicitingThreshold)
+
+	// the max ack delay is capped by the peer's max_ack_delay
+	rttStats = utils.NewRTTStats()
+	rttStats.UpdateRTT(400*time.Millisecond, 0)
+	now = now.Add(400 * time.Millisecond)
+	f = m.GetFrame(1000*maxDatagramSize, maxDatagramSize, rttStats, now)
+	require.NotNil(t, f)
+	require.Equal(t, uint64(2), f.SequenceNumber)
+	require.Equal(t, 25*time.Millisecond, f.RequestMaxAckDelay)
+
+	// ... and can't be smaller than the peer's min_ack_delay
+	rttStats = utils.NewRTTStats()
+	rttStats.UpdateRTT(time.Millisecond, 0)
+	now = now.Add(400 * time.Millisecond)
+	f = m.GetFrame(1000*maxDatagramSize, maxDatagramSize, rttStats, now)
+	require.NotNil(t, f)
+	require.Equal(t, uint64(3), f.SequenceNumber)
+	require.Equal(t, time.Millisecond, f.RequestMaxAckDelay)
+}
+
+func TestAckFrequencyManagerSmallMaxAckDelay(t *testing.T) {
+	// the peer's min_ack_delay is never smaller than the timer granularity,
+	// and the requested max ack delay is never smaller than the min_ack_delay
+	m := newAckFrequencyManager(0, 0)
+	rttStats := utils.NewRTTStats()
+	rttStats.UpdateRTT(100*time.Millisecond, 0)
+	f := m.GetFrame(100*protocol.ByteCount(1000), 1000, rttStats, monotime.Now())
+	require.NotNil(t, f)
+	require.Equal(t, protocol.TimerGranularity, f.RequestMaxAckDelay)
+}
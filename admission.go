@@ -0,0 +1,270 @@
+package quic
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+)
+
+// ConnectionRateLimit limits the rate at which new connections are accepted from a source address prefix.
+// The limit is implemented as a token bucket per prefix.
+type ConnectionRateLimit struct {
+	// Rate is the number of new connections per second that are accepted from a single prefix.
+	Rate float64
+	// Burst is the number of connections that can be accepted from a single prefix at once.
+	// If not set, it defaults to the Rate, rounded up.
+	Burst int
+	// The prefix length of IPv4 addresses that are subject to a common limit.
+	// If not set, every IPv4 address has its own limit.
+	IPv4PrefixLen int
+	// The prefix length of IPv6 addresses that are subject to a common limit.
+	// If not set, it defaults to 64.
+	IPv6PrefixLen int
+}
+
+func (l *ConnectionRateLimit) validate() error {
+	if l.Rate <= 0 {
+		return errors.New("quic: invalid connection rate limit: rate must be positive")
+	}
+	if l.Burst < 0 {
+		return errors.New("quic: invalid connection rate limit: negative burst")
+	}
+	if l.IPv4PrefixLen < 0 || l.IPv4PrefixLen > 32 {
+		return errors.New("quic: invalid connection rate limit: invalid IPv4 prefix length")
+	}
+	if l.IPv6PrefixLen < 0 || l.IPv6PrefixLen > 128 {
+		return errors.New("quic: invalid connection rate limit: invalid IPv6 prefix length")
+	}
+	return nil
+}
+
+// AdmissionStats contains statistics about the admission control of incoming connections.
+// Rejections are counted per Initial packet: if the client's first flight consists of
+// multiple packets, every one of them is counted as a rejection.
+type AdmissionStats struct {
+	// Connections is the number of incoming connections, including connections that are still handshaking.
+	Connections int
+	// Handshakes is the number of incoming connections that haven't completed the handshake yet.
+	Handshakes int
+
+	// The number of connections rejected because the Transport's MaxConnections was reached.
+	RejectedMaxConnections uint64
+	// The number of connections rejected because the Transport's MaxHandshakes was reached.
+	RejectedMaxHandshakes uint64
+	// The number of connections rejected because the ConnectionRateLimit was exceeded.
+	RejectedRateLimit uint64
+	// The number of rejected connections that were sent a Retry instead of being refused.
+	Retries uint64
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastUpdate monotime.Time
+}
+
+// The admissionController enforces the limits on incoming connections configured on the Transport.
+type admissionController struct {
+	maxConns      int
+	maxHandshakes int
+	retry         bool
+
+	rate                         float64
+	burst                        float64
+	ipv4PrefixLen, ipv6PrefixLen int
+
+	mx          sync.Mutex
+	stats       AdmissionStats
+	buckets     map[netip.Prefix]*tokenBucket
+	lastCleanup monotime.Time
+}
+
+func newAdmissionController(maxConns, maxHandshakes int, rateLimit *ConnectionRateLimit, retry bool) *admissionController {
+	c := &admissionController{
+		maxConns:      maxConns,
+		maxHandshakes: maxHandshakes,
+		retry:         retry,
+	}
+	if rateLimit != nil {
+		c.rate = rateLimit.Rate
+		c.burst = float64(rateLimit.Burst)
+		if c.burst == 0 {
+			c.burst = math.Ceil(rateLimit.Rate)
+		}
+		c.ipv4PrefixLen = rateLimit.IPv4PrefixLen
+		if c.ipv4PrefixLen == 0 {
+			c.ipv4PrefixLen = 32
+		}
+		c.ipv6PrefixLen = rateLimit.IPv6PrefixLen
+		if c.ipv6PrefixLen == 0 {
+			c.ipv6PrefixLen = 64
+		}
+		c.buckets = make(map[netip.Prefix]*tokenBucket)
+	}
+	return c
+}
+
+// admit decides if a new connection from addr is accepted.
+// If it is, it is counted as a connection and as a handshake,
+// until handshakeDone and connClosed are called.
+// If it's not, the rejection is counted, and the reason is returned.
+// If sendRetry is true, the client is sent a Retry instead of being refused.
+func (c *admissionController) admit(addr net.Addr, addrVerified bool, now monotime.Time) (ok bool, reason qlog.ConnectionRejectionReason, sendRetry bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	switch {
+	case c.maxConns > 0 && c.stats.Connections >= c.maxConns:
+		reason = qlog.ConnectionRejectionMaxConnections
+		c.stats.RejectedMaxConnections++
+	case c.maxHandshakes > 0 && c.stats.Handshakes >= c.maxHandshakes:
+		reason = qlog.ConnectionRejectionMaxHandshakes
+		c.stats.RejectedMaxHandshakes++
+	case c.buckets != nil && !c.takeToken(addr, now):
+		reason = qlog.ConnectionRejectionRateLimit
+		c.stats.RejectedRateLimit++
+	default:
+		c.stats.Connections++
+		c.stats.Handshakes++
+		return true, "", false
+	}
+	// Retrying only makes sense if the client hasn't proven ownership of its address yet.
+	if c.retry && !addrVerified {
+		c.stats.Retries++
+		return false, reason, true
+	}
+	return false, reason, false
+}
+
+func (c *admissionController) takeToken(addr net.Addr, now monotime.Time) bool {
+	ap := udpAddrPort(addr)
+	if !ap.IsValid() {
+		return true
+	}
+	ip := ap.Addr().Unmap()
+	prefixLen := c.ipv6PrefixLen
+	if ip.Is4() {
+		prefixLen = c.ipv4PrefixLen
+	}
+	prefix, err := ip.Prefix(prefixLen)
+	if err != nil {
+		return true
+	}
+
+	c.maybeCleanup(now)
+	b, ok := c.buckets[prefix]
+	if !ok {
+		b = &tokenBucket{tokens: c.burst, lastUpdate: now}
+		c.buckets[prefix] = b
+	}
+	b.tokens = c.refill(b, now)
+	b.lastUpdate = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (c *admissionController) refill(b *tokenBucket, now monotime.Time) float64 {
+	return min(c.burst, b.tokens+now.Sub(b.lastUpdate).Seconds()*c.rate)
+}
+
+// maybeCleanup removes the buckets that are full again.
+// It runs at most once per time it takes to fill an empty bucket,
+// which bounds the number of buckets to the number of prefixes seen during that interval.
+func (c *admissionController) maybeCleanup(now monotime.Time) {
+	interval := time.Duration(c.burst / c.rate * float64(time.Second))
+	if now.Sub(c.lastCleanup) < interval {
+		return
+	}
+	c.lastCleanup = now
+	for prefix, b := range c.buckets {
+		if c.refill(b, now) >= c.burst {
+			delete(c.buckets, prefix)
+		}
+	}
+}
+
+// abort is called when a connection was admitted, but the server failed to create it.
+func (c *admissionController) abort() {
+	c.mx.Lock()
+	c.stats.Connections--
+	c.stats.Handshakes--
+	c.mx.Unlock()
+}
+
+func (c *admissionController) handshakeDone() {
+	c.mx.Lock()
+	c.stats.Handshakes--
+	c.mx.Unlock()
+}
+
+func (c *admissionController) connClosed() {
+	c.mx.Lock()
+	c.stats.Connections--
+	c.mx.Unlock()
+}
+
+func (c *admissionController) currentStats() AdmissionStats {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return c.stats
+}
+
+// The adaptiveRetry policy enables Retry once the number of handshakes with clients
+// whose address hasn't been validated reaches a threshold.
+// To avoid flapping, Retry is only disabled again once that number drops to half the threshold.
+type adaptiveRetry struct {
+	threshold int
+	qlogger   qlogwriter.Recorder
+
+	mx         sync.Mutex
+	handshakes int
+	enabled    bool
+}
+
+func newAdaptiveRetry(threshold int, qlogger qlogwriter.Recorder) *adaptiveRetry {
+	return &adaptiveRetry{threshold: threshold, qlogger: qlogger}
+}
+
+// shouldRetry says if a connection attempt from an unvalidated address should be sent a Retry.
+func (r *adaptiveRetry) shouldRetry() bool {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return r.enabled
+}
+
+func (r *adaptiveRetry) handshakeStarted() {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.handshakes++
+	if !r.enabled && r.handshakes >= r.threshold {
+		r.setEnabled(true)
+	}
+}
+
+func (r *adaptiveRetry) handshakeDone() {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.handshakes--
+	if r.enabled && r.handshakes <= r.threshold/2 {
+		r.setEnabled(false)
+	}
+}
+
+func (r *adaptiveRetry) setEnabled(enabled bool) {
+	r.enabled = enabled
+	if r.qlogger != nil {
+		r.qlogger.RecordEvent(qlog.RetryStateUpdated{
+			Enabled:               enabled,
+			UnvalidatedHandshakes: r.handshakes,
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package quic
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+	"github.com/quic-go/quic-go/testutils/events"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionRateLimitValidation(t *testing.T) {
+	require.NoError(t, (&ConnectionRateLimit{Rate: 0.5}).validate())
+	require.ErrorContains(t, (&ConnectionRateLimit{}).validate(), "rate must be positive")
+	require.ErrorContains(t, (&ConnectionRateLimit{Rate: 1, Burst: -1}).validate(), "negative burst")
+	require.ErrorContains(t, (&ConnectionRateLimit{Rate: 1, IPv4PrefixLen: 33}).validate(), "invalid IPv4 prefix length")
+	require.ErrorContains(t, (&ConnectionRateLimit{Rate: 1, IPv6PrefixLen: 129}).validate(), "invalid IPv6 prefix length")
+}
+
+func TestAdmissionControlMaxConnections(t *testing.T) {
+	c := newAdmissionController(2, 0, nil, false)
+	addr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1234}
+	now := monotime.Now()
+
+	for range 2 {
+		ok, _, _ := c.admit(addr, false, now)
+		require.True(t, ok)
+	}
+	ok, reason, retry := c.admit(addr, false, now)
+	require.False(t, ok)
+	require.Equal(t, qlog.ConnectionRejectionMaxConnections, reason)
+	require.False(t, retry)
+
+	// completing the handshake doesn't free up a connection slot
+	c.handshakeDone()
+	ok, _, _ = c.admit(addr, false, now)
+	require.False(t, ok)
+	c.connClosed()
+	ok, _, _ = c.admit(addr, false, now)
+	require.True(t, ok)
+
+	require.Equal(t, AdmissionStats{Connections: 2, Handshakes: 2, RejectedMaxConnections: 2}, c.currentStats())
+}
+
+func TestAdmissionControlMaxHandshakes(t *testing.T) {
+	c := newAdmissionController(0, 1, nil, true)
+	addr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1234}
+	now := monotime.Now()
+
+	ok, _, _ := c.admit(addr, false, now)
+	require.True(t, ok)
+	// clients that haven't validated their address are sent a Retry
+	ok, reason, retry := c.admit(addr, false, now)
+	require.False(t, ok)
+	require.Equal(t, qlog.ConnectionRejectionMaxHandshakes, reason)
+	require.True(t, retry)
+	// clients that have validated their address are refused
+	ok, reason, retry = c.admit(addr, true, now)
+	require.False(t, ok)
+	require.Equal(t, qlog.ConnectionRejectionMaxHandshakes, reason)
+	require.False(t, retry)
+
+	c.handshakeDone()
+	ok, _, _ = c.admit(addr, true, now)
+	require.True(t, ok)
+	// a connection that failed to be created frees up its slots
+	c.abort()
+	require.Equal(t, AdmissionStats{Connections: 1, RejectedMaxHandshakes: 2, Retries: 1}, c.currentStats())
+}
+
+func TestAdmissionControlRateLimit(t *testing.T) {
+	c := newAdmissionController(0, 0, &ConnectionRateLimit{Rate: 2, Burst: 3, IPv4PrefixLen: 24}, false)
+	now := monotime.Now()
+
+	for i := range 3 {
+		ok, _, _ := c.admit(&net.UDPAddr{IP: net.IPv4(192, 0, 2, byte(i)), Port: 1234}, false, now)
+		require.True(t, ok)
+	}
+	// the limit applies to the whole /24
+	ok, reason, _ := c.admit(&net.UDPAddr{IP: net.IPv4(192, 0, 2, 100), Port: 1234}, false, now)
+	require.False(t, ok)
+	require.Equal(t, qlog.ConnectionRejectionRateLimit, reason)
+	// IPv4-mapped IPv6 addresses are treated like IPv4 addresses
+	ok, _, _ = c.admit(&net.UDPAddr{IP: net.ParseIP("::ffff:192.0.2.200"), Port: 1234}, false, now)
+	require.False(t, ok)
+	// other prefixes are not affected
+	ok, _, _ = c.admit(&net.UDPAddr{IP: net.IPv4(192, 0, 3, 1), Port: 1234}, false, now)
+	require.True(t, ok)
+
+	// tokens are refilled at the configured rate
+	now = now.Add(499 * time.Millisecond)
+	ok, _, _ = c.admit(&net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1234}, false, now)
+	require.False(t, ok)
+	now = now.Add(10 * time.Millisecond)
+	ok, _, _ = c.admit(&net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1234}, false, now)
+	require.True(t, ok)
+	require.Equal(t, uint64(3), c.currentStats().RejectedRateLimit)
+}
+
+func TestAdmissionControlRateLimitIPv6(t *testing.T) {
+	c := newAdmissionController(0, 0, &ConnectionRateLimit{Rate: 0.5}, false)
+	now := monotime.Now()
+
+	ok, _, _ := c.admit(&net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 1234}, false, now)
+	require.True(t, ok)
+	// by default, the limit applies to the whole /64
+	ok, _, _ = c.admit(&net.UDPAddr{IP: net.ParseIP("2001:db8::ffff:1"), Port: 1234}, false, now)
+	require.False(t, ok)
+	ok, _, _ = c.admit(&net.UDPAddr{IP: net.ParseIP("2001:db8:0:1::1"), Port: 1234}, false, now)
+	require.True(t, ok)
+}
+
+func TestAdmissionControlRateLimitCleanup(t *testing.T) {
+	c := newAdmissionController(0, 0, &ConnectionRateLimit{Rate: 10}, false)
+	now := monotime.Now()
+
+	for i := range 100 {
+		ok, _, _ := c.admit(&net.UDPAddr{IP: net.IPv4(10, 0, 0, byte(i)), Port: 1234}, false, now)
+		require.True(t, ok)
+	}
+	require.Len(t, c.buckets, 100)
+
+	// it takes one second to refill a bucket
+	now = now.Add(time.Second)
+	ok, _, _ := c.admit(&net.UDPAddr{IP: net.IPv4(10, 0, 1, 1), Port: 1234}, false, now)
+	require.True(t, ok)
+	require.Len(t, c.buckets, 1)
+}
+
+func TestAdaptiveRetry(t *testing.T) {
+	var eventRecorder events.Recorder
+	r := newAdaptiveRetry(4, &eventRecorder)
+
+	for range 3 {
+		r.handshakeStarted()
+	}
+	require.False(t, r.shouldRetry())
+	r.handshakeStarted()
+	require.True(t, r.shouldRetry())
+	require.Equal(t,
+		[]qlogwriter.Event{qlog.RetryStateUpdated{Enabled: true, UnvalidatedHandshakes: 4}},
+		eventRecorder.Events(qlog.RetryStateUpdated{}),
+	)
+	eventRecorder.Clear()
+
+	// Retry stays enabled until the number of handshakes drops to half the threshold
+	r.handshakeDone()
+	require.True(t, r.shouldRetry())
+	r.handshakeDone()
+	require.False(t, r.shouldRetry())
+	require.Equal(t,
+		[]qlogwriter.Event{qlog.RetryStateUpdated{Enabled: false, UnvalidatedHandshakes: 2}},
+		eventRecorder.Events(qlog.RetryStateUpdated{}),
+	)
+	eventRecorder.Clear()
+
+	r.handshakeStarted()
+	require.False(t, r.shouldRetry())
+	require.Empty(t, eventRecorder.Events())
+}
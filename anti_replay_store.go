@@ -0,0 +1,60 @@
+package quic
+
+import (
+	"sync"
+	"time"
+)
+
+type inMemoryAntiReplayStore struct {
+	window time.Duration
+
+	mx          sync.Mutex
+	tickets     map[[32]byte]time.Time // ticket ID -> expiry
+	lastCleanup time.Time
+}
+
+var _ AntiReplayStore = &inMemoryAntiReplayStore{}
+
+// NewInMemoryAntiReplayStore creates an AntiReplayStore that accepts every session ticket for 0-RTT only once.
+// Session tickets are remembered until window has passed since they were issued.
+// 0-RTT attempts using older session tickets are rejected, since a replay couldn't be detected.
+// The store is kept in memory, so it doesn't detect replays across multiple server instances.
+func NewInMemoryAntiReplayStore(window time.Duration) AntiReplayStore {
+	return &inMemoryAntiReplayStore{
+		window:  window,
+		tickets: make(map[[32]byte]time.Time),
+	}
+}
+
+func (s *inMemoryAntiReplayStore) Accept0RTT(a ZeroRTTAttempt) bool {
+	now := time.Now()
+	expiry := a.TicketIssued.Add(s.window)
+	if !now.Before(expiry) {
+		return false
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.maybeCleanup(now)
+	if _, ok := s.tickets[a.TicketID]; ok {
+		return false
+	}
+	s.tickets[a.TicketID] = expiry
+	return true
+}
+
+// maybeCleanup removes expired tickets.
+// It runs at most once per window, which bounds the number of tickets
+// to the number of tickets used within two windows.
+func (s *inMemoryAntiReplayStore) maybeCleanup(now time.Time) {
+	if now.Sub(s.lastCleanup) < s.window {
+		return
+	}
+	s.lastCleanup = now
+	for id, expiry := range s.tickets {
+		if !now.Before(expiry) {
+			delete(s.tickets, id)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package quic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/synctest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryAntiReplayStore(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := NewInMemoryAntiReplayStore(time.Minute)
+
+		// every session ticket is only accepted once
+		require.True(t, s.Accept0RTT(ZeroRTTAttempt{TicketID: [32]byte{1}, TicketIssued: time.Now()}))
+		require.False(t, s.Accept0RTT(ZeroRTTAttempt{TicketID: [32]byte{1}, TicketIssued: time.Now()}))
+		require.True(t, s.Accept0RTT(ZeroRTTAttempt{TicketID: [32]byte{2}, TicketIssued: time.Now()}))
+
+		// session tickets issued before the window are rejected
+		require.False(t, s.Accept0RTT(ZeroRTTAttempt{TicketID: [32]byte{3}, TicketIssued: time.Now().Add(-time.Minute)}))
+		require.True(t, s.Accept0RTT(ZeroRTTAttempt{TicketID: [32]byte{3}, TicketIssued: time.Now().Add(-time.Minute + time.Second)}))
+	})
+}
+
+func TestInMemoryAntiReplayStoreCleanup(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := NewInMemoryAntiReplayStore(time.Minute).(*inMemoryAntiReplayStore)
+
+		issued := time.Now()
+		for i := range 10 {
+			require.True(t, s.Accept0RTT(ZeroRTTAttempt{TicketID: [32]byte{byte(i)}, TicketIssued: issued}))
+		}
+		require.Len(t, s.tickets, 10)
+
+		time.Sleep(time.Minute)
+		require.True(t, s.Accept0RTT(ZeroRTTAttempt{TicketID: [32]byte{42}, TicketIssued: time.Now()}))
+		require.Len(t, s.tickets, 1)
+	})
+}
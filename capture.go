@@ -0,0 +1,127 @@
+package quic
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/pcapng"
+)
+
+// capturingConn writes all packets sent and received on a rawConn to a pcapng capture.
+type capturingConn struct {
+	rawConn
+	capture *pcapng.Writer
+}
+
+var _ rawConn = &capturingConn{}
+
+func newCapturingConn(c rawConn, capture *pcapng.Writer) *capturingConn {
+	return &capturingConn{rawConn: c, capture: capture}
+}
+
+func (c *capturingConn) ReadPacket() (receivedPacket, error) {
+	p, err := c.rawConn.ReadPacket()
+	if err != nil {
+		return p, err
+	}
+	local := udpAddrPort(c.LocalAddr())
+	if p.info.addr.IsValid() {
+		local = netip.AddrPortFrom(p.info.addr, local.Port())
+	}
+	c.writePacket(udpAddrPort(p.remoteAddr), local, p.ecn, p.data)
+	return p, nil
+}
+
+func (c *capturingConn) WritePacket(b []byte, addr net.Addr, packetInfoOOB []byte, gsoSize uint16, ecn protocol.ECN) (int, error) {
+	n, err := c.rawConn.WritePacket(b, addr, packetInfoOOB, gsoSize, ecn)
+	if err != nil {
+		return n, err
+	}
+	local, remote := udpAddrPort(c.LocalAddr()), udpAddrPort(addr)
+	if gsoSize == 0 {
+		c.writePacket(local, remote, ecn, b)
+		return n, nil
+	}
+	// with GSO, b contains multiple packets of gsoSize bytes, the last packet can be shorter
+	for len(b) > 0 {
+		l := min(int(gsoSize), len(b))
+		c.writePacket(local, remote, ecn, b[:l])
+		b = b[l:]
+	}
+	return n, nil
+}
+
+func (c *capturingConn) writePacket(src, dst netip.AddrPort, ecn protocol.ECN, data []byte) {
+	if !src.IsValid() || !dst.IsValid() {
+		return
+	}
+	// When using a dual-stack socket, the local address might be of a different IP version.
+	src, dst = netip.AddrPortFrom(src.Addr().Unmap(), src.Port()), netip.AddrPortFrom(dst.Addr().Unmap(), dst.Port())
+	if src.Addr().Is4() != dst.Addr().Is4() {
+		if src.Addr().IsUnspecified() {
+			src = netip.AddrPortFrom(unspecifiedAddr(dst.Addr()), src.Port())
+		} else {
+			dst = netip.AddrPortFrom(unspecifiedAddr(src.Addr()), dst.Port())
+		}
+	}
+	var ecnBits uint8
+	if ecn != protocol.ECNUnsupported {
+		ecnBits = ecn.ToHeaderBits()
+	}
+	// Errors are sticky, and it's not possible to do anything about them here.
+	_ = c.capture.WritePacket(time.Now(), src, dst, ecnBits, data)
+}
+
+func unspecifiedAddr(a netip.Addr) netip.Addr {
+	if a.Is4() {
+		return netip.IPv4Unspecified()
+	}
+	return netip.IPv6Unspecified()
+}
+
+func udpAddrPort(addr net.Addr) netip.AddrPort {
+	if a, ok := addr.(*net.UDPAddr); ok {
+		return a.AddrPort()
+	}
+	if addr == nil {
+		return netip.AddrPort{}
+	}
+	ap, _ := netip.ParseAddrPort(addr.String())
+	return ap
+}
+
+// addKeyLogWriter makes the tls.Config write the TLS secrets to w,
+// in addition to the tls.Config's KeyLogWriter.
+func addKeyLogWriter(conf *tls.Config, w io.Writer) {
+	if conf.KeyLogWriter != nil {
+		conf.KeyLogWriter = io.MultiWriter(conf.KeyLogWriter, w)
+	} else {
+		conf.KeyLogWriter = w
+	}
+}
+
+// serverTLSConfigWithKeyLogWriter returns a copy of the tls.Config that writes the TLS secrets to w.
+// This also applies to the tls.Config returned by GetConfigForClient.
+func serverTLSConfigWithKeyLogWriter(conf *tls.Config, w io.Writer) *tls.Config {
+	// Workaround for https://github.com/golang/go/issues/60506.
+	// This initializes the session tickets _before_ cloning the config.
+	_, _ = conf.DecryptTicket(nil, tls.ConnectionState{})
+
+	conf = conf.Clone()
+	addKeyLogWriter(conf, w)
+	if conf.GetConfigForClient != nil {
+		gcfc := conf.GetConfigForClient
+		conf.GetConfigForClient = func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			c, err := gcfc(info)
+			if c != nil {
+				c = serverTLSConfigWithKeyLogWriter(c, w)
+			}
+			return c, err
+		}
+	}
+	return conf
+}
@@ -0,0 +1,160 @@
+package quic
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/pcapng"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type capturedPacket struct {
+	src, dst netip.AddrPort
+	ecn      uint8
+	payload  []byte
+}
+
+// parseCapturedIPv4Packets parses the IPv4 packets contained in the Enhanced Packet Blocks of a pcapng capture
+func parseCapturedIPv4Packets(t *testing.T, data []byte) []capturedPacket {
+	t.Helper()
+	var packets []capturedPacket
+	for len(data) > 0 {
+		blockType := binary.LittleEndian.Uint32(data)
+		blockLen := binary.LittleEndian.Uint32(data[4:])
+		if blockType == 6 { // Enhanced Packet Block
+			capLen := binary.LittleEndian.Uint32(data[20:])
+			ip := data[28 : 28+capLen]
+			require.Equal(t, uint8(0x45), ip[0])
+			packets = append(packets, capturedPacket{
+				src:     netip.AddrPortFrom(netip.AddrFrom4([4]byte(ip[12:16])), binary.BigEndian.Uint16(ip[20:])),
+				dst:     netip.AddrPortFrom(netip.AddrFrom4([4]byte(ip[16:20])), binary.BigEndian.Uint16(ip[22:])),
+				ecn:     ip[1] & 0b11,
+				payload: ip[28:],
+			})
+		}
+		data = data[blockLen:]
+	}
+	return packets
+}
+
+func TestCapturingConnReadPacket(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	rawConn := NewMockRawConn(mockCtrl)
+	var buf bytes.Buffer
+	capture, err := pcapng.NewWriter(&buf)
+	require.NoError(t, err)
+	conn := newCapturingConn(rawConn, capture)
+
+	// dual-stack socket
+	rawConn.EXPECT().LocalAddr().Return(&net.UDPAddr{IP: net.IPv6unspecified, Port: 443}).AnyTimes()
+	rawConn.EXPECT().ReadPacket().Return(receivedPacket{
+		remoteAddr: &net.UDPAddr{IP: net.IPv4(192, 0, 2, 2), Port: 1234},
+		data:       []byte("foo"),
+		ecn:        protocol.ECT1,
+	}, nil)
+	p, err := conn.ReadPacket()
+	require.NoError(t, err)
+	require.Equal(t, []byte("foo"), p.data)
+
+	// the local address is known from the packet info
+	rawConn.EXPECT().ReadPacket().Return(receivedPacket{
+		remoteAddr: &net.UDPAddr{IP: net.IPv4(192, 0, 2, 2), Port: 1234},
+		data:       []byte("bar"),
+		ecn:        protocol.ECNUnsupported,
+		info:       packetInfo{addr: netip.MustParseAddr("192.0.2.1")},
+	}, nil)
+	_, err = conn.ReadPacket()
+	require.NoError(t, err)
+
+	rawConn.EXPECT().ReadPacket().Return(receivedPacket{}, errors.New("read failed"))
+	_, err = conn.ReadPacket()
+	require.EqualError(t, err, "read failed")
+
+	packets := parseCapturedIPv4Packets(t, buf.Bytes())
+	require.Equal(t, []capturedPacket{
+		{
+			src:     netip.MustParseAddrPort("192.0.2.2:1234"),
+			dst:     netip.MustParseAddrPort("0.0.0.0:443"),
+			ecn:     0b01,
+			payload: []byte("foo"),
+		},
+		{
+			src:     netip.MustParseAddrPort("192.0.2.2:1234"),
+			dst:     netip.MustParseAddrPort("192.0.2.1:443"),
+			payload: []byte("bar"),
+		},
+	}, packets)
+}
+
+func TestCapturingConnWritePacket(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	rawConn := NewMockRawConn(mockCtrl)
+	var buf bytes.Buffer
+	capture, err := pcapng.NewWriter(&buf)
+	require.NoError(t, err)
+	conn := newCapturingConn(rawConn, capture)
+
+	rawConn.EXPECT().LocalAddr().Return(&net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 443}).AnyTimes()
+	remoteAddr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 2), Port: 1234}
+	rawConn.EXPECT().WritePacket([]byte("foobar"), remoteAddr, nil, uint16(0), protocol.ECT0).Return(6, nil)
+	n, err := conn.WritePacket([]byte("foobar"), remoteAddr, nil, 0, protocol.ECT0)
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+
+	// GSO: the last packet is shorter
+	rawConn.EXPECT().WritePacket(gomock.Any(), remoteAddr, nil, uint16(4), protocol.ECNNon).Return(10, nil)
+	_, err = conn.WritePacket([]byte("aaaabbbbcc"), remoteAddr, nil, 4, protocol.ECNNon)
+	require.NoError(t, err)
+
+	// packets that fail to be sent are not captured
+	rawConn.EXPECT().WritePacket(gomock.Any(), remoteAddr, nil, uint16(0), protocol.ECNNon).Return(0, errors.New("write failed"))
+	_, err = conn.WritePacket([]byte("foo"), remoteAddr, nil, 0, protocol.ECNNon)
+	require.EqualError(t, err, "write failed")
+
+	packets := parseCapturedIPv4Packets(t, buf.Bytes())
+	local := netip.MustParseAddrPort("192.0.2.1:443")
+	remote := netip.MustParseAddrPort("192.0.2.2:1234")
+	require.Equal(t, []capturedPacket{
+		{src: local, dst: remote, ecn: 0b10, payload: []byte("foobar")},
+		{src: local, dst: remote, payload: []byte("aaaa")},
+		{src: local, dst: remote, payload: []byte("bbbb")},
+		{src: local, dst: remote, payload: []byte("cc")},
+	}, packets)
+}
+
+func TestServerTLSConfigWithKeyLogWriter(t *testing.T) {
+	var keyLog1, keyLog2, keyLog3 bytes.Buffer
+	innerConf := &tls.Config{ServerName: "inner"}
+	conf := &tls.Config{
+		KeyLogWriter: &keyLog1,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return innerConf, nil
+		},
+	}
+	c := serverTLSConfigWithKeyLogWriter(conf, &keyLog2)
+	// the original config is not modified
+	require.Equal(t, &keyLog1, conf.KeyLogWriter)
+
+	c.KeyLogWriter.Write([]byte("foo"))
+	require.Equal(t, "foo", keyLog1.String())
+	require.Equal(t, "foo", keyLog2.String())
+
+	inner, err := c.GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	require.Equal(t, "inner", inner.ServerName)
+	require.Nil(t, innerConf.KeyLogWriter)
+	inner.KeyLogWriter.Write([]byte("bar"))
+	require.Equal(t, "foobar", keyLog2.String())
+
+	clientConf := &tls.Config{}
+	addKeyLogWriter(clientConf, &keyLog3)
+	require.Equal(t, &keyLog3, clientConf.KeyLogWriter)
+}
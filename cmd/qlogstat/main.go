@@ -0,0 +1,132 @@
+// qlogstat analyzes qlog traces written in the JSON-SEQ format (.sqlog files).
+//
+// Usage:
+//
+//	qlogstat summary <file>...
+//	qlogstat metrics <file>...
+//	qlogstat diff <file> <file>
+//
+// The summary command prints handshake timing, packet counts, losses (broken down by reason),
+// spurious losses, PTO counts and packet drops (broken down by reason) for each trace.
+// The metrics command prints the RTT, congestion window and bytes in flight timeline as CSV.
+// The diff command prints the summaries of two traces side by side.
+//
+// Files ending in .gz are decompressed.
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/quic-go/quic-go/qlogreader"
+)
+
+const usage = `Usage: qlogstat <command> <file>...
+
+Commands:
+  summary <file>...   print a summary of each trace
+  metrics <file>...   print the RTT, congestion window and bytes in flight timeline as CSV
+  diff <file> <file>  compare two traces side by side
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, w io.Writer) error {
+	if len(args) < 2 {
+		return fmt.Errorf("%s", usage)
+	}
+	cmd, files := args[0], args[1:]
+	switch cmd {
+	case "summary":
+		for i, file := range files {
+			s, err := summarizeFile(file)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			if err := s.print(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "metrics":
+		mw := newMetricsWriter(w)
+		for _, file := range files {
+			_, events, err := readFile(file)
+			if err != nil {
+				return err
+			}
+			if err := mw.write(file, events); err != nil {
+				return err
+			}
+		}
+		return mw.flush()
+	case "diff":
+		if len(files) != 2 {
+			return fmt.Errorf("diff requires exactly two files\n\n%s", usage)
+		}
+		a, err := summarizeFile(files[0])
+		if err != nil {
+			return err
+		}
+		b, err := summarizeFile(files[1])
+		if err != nil {
+			return err
+		}
+		return printDiff(w, a, b)
+	default:
+		return fmt.Errorf("unknown command: %s\n\n%s", cmd, usage)
+	}
+}
+
+func readFile(path string) (qlogreader.Header, []qlogreader.Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return qlogreader.Header{}, nil, err
+	}
+	defer f.Close()
+
+	var rd io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return qlogreader.Header{}, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		rd = gr
+	}
+	r, err := qlogreader.NewReader(rd)
+	if err != nil {
+		return qlogreader.Header{}, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	events, err := r.ReadAll()
+	if err != nil {
+		// The trace might not have been closed properly, e.g. because the process crashed.
+		// Analyze the events up to that point.
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			return qlogreader.Header{}, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s: trace is truncated\n", path)
+	}
+	return r.Header(), events, nil
+}
+
+func summarizeFile(path string) (*summary, error) {
+	hdr, events, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := summarize(hdr, events)
+	s.File = path
+	return s, nil
+}
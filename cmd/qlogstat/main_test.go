@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/synctest"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+
+	"github.com/stretchr/testify/require"
+)
+
+type nopWriteCloserImpl struct{ io.Writer }
+
+func (nopWriteCloserImpl) Close() error { return nil }
+
+// writeTrace writes a connection trace containing the events to a file, one event per second.
+func writeTrace(t *testing.T, name string, events ...qlogwriter.Event) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	synctest.Test(t, func(t *testing.T) {
+		tr := qlogwriter.NewConnectionFileSeq(
+			&nopWriteCloserImpl{Writer: &buf},
+			true,
+			protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef}),
+			[]string{qlog.EventSchema},
+		)
+		go tr.Run()
+		producer := tr.AddProducer()
+		for _, ev := range events {
+			synctest.Wait()
+			time.Sleep(time.Second)
+			producer.RecordEvent(ev)
+		}
+		producer.Close()
+	})
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	return path
+}
+
+// parseSummary parses the output of the summary command into a map of row names to values.
+func parseSummary(t *testing.T, out string) map[string]string {
+	t.Helper()
+	rows := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n")[1:] {
+		name, value, ok := strings.Cut(line, ":")
+		require.True(t, ok, "invalid line: %q", line)
+		rows[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return rows
+}
+
+func packet(pt qlog.PacketType, pn protocol.PacketNumber, length int) (qlog.PacketHeader, qlog.RawInfo) {
+	return qlog.PacketHeader{PacketType: pt, PacketNumber: pn, Version: protocol.Version1}, qlog.RawInfo{Length: length}
+}
+
+func TestSummary(t *testing.T) {
+	hdr1, raw1 := packet(qlog.PacketTypeInitial, 0, 1200)
+	hdr2, raw2 := packet(qlog.PacketTypeHandshake, 0, 1000)
+	hdr3, raw3 := packet(qlog.PacketType1RTT, 1, 100)
+	file := writeTrace(t, "trace.sqlog",
+		qlog.PacketSent{Header: hdr1, Raw: raw1},
+		qlog.PacketReceived{Header: hdr2, Raw: raw2},
+		qlog.KeyUpdated{Trigger: qlog.KeyUpdateTLS, KeyType: qlog.KeyTypeServerHandshake},
+		qlog.ALPNInformation{ChosenALPN: "h3"},
+		qlog.KeyUpdated{Trigger: qlog.KeyUpdateTLS, KeyType: qlog.KeyTypeClient1RTT},
+		qlog.PacketSent{Header: hdr3, Raw: raw3},
+		qlog.KeyDiscarded{KeyType: qlog.KeyTypeClientHandshake},
+		qlog.MetricsUpdated{MinRTT: 10 * time.Millisecond, SmoothedRTT: 15 * time.Millisecond, CongestionWindow: 12000, BytesInFlight: 3000},
+		qlog.MetricsUpdated{SmoothedRTT: 20 * time.Millisecond, BytesInFlight: 1000},
+		qlog.PacketLost{Header: hdr3, Trigger: qlog.PacketLossReorderingThreshold},
+		qlog.PacketLost{Header: hdr3, Trigger: qlog.PacketLossReorderingThreshold},
+		qlog.PacketLost{Header: hdr3, Trigger: qlog.PacketLossTimeThreshold},
+		qlog.SpuriousLoss{EncryptionLevel: protocol.Encryption1RTT, PacketNumber: 1, PacketReordering: 5, TimeReordering: 3 * time.Millisecond},
+		qlog.LossTimerUpdated{Type: qlog.LossTimerUpdateTypeExpired, TimerType: qlog.TimerTypePTO, EncLevel: protocol.Encryption1RTT},
+		qlog.PTOCountUpdated{PTOCount: 1},
+		qlog.PacketDropped{Header: hdr2, Raw: raw2, Trigger: qlog.PacketDropKeyUnavailable},
+		qlog.PacketDropped{Header: hdr2, Raw: raw2, Trigger: qlog.PacketDropDOSPrevention},
+		qlog.PacketDropped{Header: hdr2, Raw: raw2, Trigger: qlog.PacketDropKeyUnavailable},
+		qlog.ConnectionClosed{Initiator: qlog.InitiatorLocal, Trigger: qlog.ConnectionCloseTriggerIdleTimeout},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, run([]string{"summary", file}, &buf))
+	require.True(t, strings.HasPrefix(buf.String(), file+"\n"))
+	rows := parseSummary(t, buf.String())
+	require.Equal(t, "client", rows["vantage point"])
+	require.Equal(t, "deadbeef", rows["group ID"])
+	require.Equal(t, "h3", rows["ALPN"])
+	require.Equal(t, "18s", rows["duration"])
+	// handshake timing is relative to the first packet
+	require.Equal(t, "2s", rows["handshake keys"])
+	require.Equal(t, "4s", rows["1-RTT keys"])
+	require.Equal(t, "6s", rows["handshake confirmed"])
+	require.Equal(t, "2", rows["packets sent"])
+	require.Equal(t, "1300", rows["bytes sent"])
+	require.Equal(t, "1", rows["packets received"])
+	require.Equal(t, "1000", rows["bytes received"])
+	require.Equal(t, "10ms", rows["min RTT"])
+	require.Equal(t, "20ms", rows["smoothed RTT"])
+	require.Equal(t, "12000", rows["max congestion window"])
+	require.Equal(t, "3000", rows["max bytes in flight"])
+	require.Equal(t, "3", rows["packets lost"])
+	require.Equal(t, "2", rows[string(qlog.PacketLossReorderingThreshold)])
+	require.Equal(t, "1", rows[string(qlog.PacketLossTimeThreshold)])
+	require.Equal(t, "1", rows["spurious losses"])
+	require.Equal(t, "5", rows["max reordering (packets)"])
+	require.Equal(t, "3ms", rows["max reordering (time)"])
+	require.Equal(t, "1", rows["PTO expirations"])
+	require.Equal(t, "1", rows["max PTO count"])
+	require.Equal(t, "3", rows["packets dropped"])
+	require.Equal(t, "2", rows[string(qlog.PacketDropKeyUnavailable)])
+	require.Equal(t, "1", rows[string(qlog.PacketDropDOSPrevention)])
+	require.Equal(t, "local", rows["closed by"])
+	require.Equal(t, "idle_timeout", rows["close reason"])
+	require.NotContains(t, rows, "unknown events")
+}
+
+func TestSummaryHandshakeIncomplete(t *testing.T) {
+	hdr, raw := packet(qlog.PacketTypeInitial, 0, 1200)
+	file := writeTrace(t, "trace.sqlog", qlog.PacketSent{Header: hdr, Raw: raw})
+
+	var buf bytes.Buffer
+	require.NoError(t, run([]string{"summary", file}, &buf))
+	rows := parseSummary(t, buf.String())
+	require.Equal(t, "-", rows["handshake keys"])
+	require.Equal(t, "-", rows["1-RTT keys"])
+	require.Equal(t, "-", rows["handshake confirmed"])
+	require.Equal(t, "0", rows["packets lost"])
+	require.NotContains(t, rows, "closed by")
+}
+
+func TestSummaryTruncatedTrace(t *testing.T) {
+	hdr, raw := packet(qlog.PacketTypeInitial, 0, 1200)
+	file := writeTrace(t, "trace.sqlog", qlog.PacketSent{Header: hdr, Raw: raw}, qlog.PacketSent{Header: hdr, Raw: raw})
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, data[:len(data)-10], 0o644))
+
+	var buf bytes.Buffer
+	require.NoError(t, run([]string{"summary", file}, &buf))
+	require.Equal(t, "1", parseSummary(t, buf.String())["packets sent"])
+}
+
+func TestSummaryCompressedTrace(t *testing.T) {
+	hdr, raw := packet(qlog.PacketTypeInitial, 0, 1200)
+	file := writeTrace(t, "trace.sqlog", qlog.PacketSent{Header: hdr, Raw: raw})
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, os.WriteFile(file+".gz", buf.Bytes(), 0o644))
+
+	buf.Reset()
+	require.NoError(t, run([]string{"summary", file + ".gz"}, &buf))
+	require.Equal(t, "1200", parseSummary(t, buf.String())["bytes sent"])
+}
+
+func TestMetrics(t *testing.T) {
+	file1 := writeTrace(t, "trace1.sqlog",
+		qlog.MetricsUpdated{MinRTT: 10 * time.Millisecond, SmoothedRTT: 15 * time.Millisecond, CongestionWindow: 12000, BytesInFlight: 3000, PacketsInFlight: 3},
+		qlog.ALPNInformation{ChosenALPN: "h3"},
+		qlog.MetricsUpdated{SmoothedRTT: 12500 * time.Microsecond, CongestionWindow: 24000},
+	)
+	file2 := writeTrace(t, "trace2.sqlog",
+		qlog.MetricsUpdated{LatestRTT: 5 * time.Millisecond, PacingRate: 1e6},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, run([]string{"metrics", file1, file2}, &buf))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		metricsHeader,
+		{file1, "1000", "10", "15", "0", "0", "12000", "3000", "3", "0", "0"},
+		// values not contained in the event are carried forward
+		{file1, "3000", "10", "12.5", "0", "0", "24000", "3000", "3", "0", "0"},
+		{file2, "1000", "0", "0", "5", "0", "0", "0", "0", "1000000", "0"},
+	}, records)
+}
+
+func TestDiff(t *testing.T) {
+	hdr, raw := packet(qlog.PacketTypeInitial, 0, 1200)
+	file1 := writeTrace(t, "trace1.sqlog",
+		qlog.PacketSent{Header: hdr, Raw: raw},
+		qlog.KeyUpdated{Trigger: qlog.KeyUpdateTLS, KeyType: qlog.KeyTypeServerHandshake},
+		qlog.PacketLost{Header: hdr, Trigger: qlog.PacketLossTimeThreshold},
+	)
+	file2 := writeTrace(t, "trace2.sqlog",
+		qlog.PacketSent{Header: hdr, Raw: raw},
+		qlog.PacketSent{Header: hdr, Raw: raw},
+		qlog.ALPNInformation{ChosenALPN: "h3"},
+		qlog.KeyUpdated{Trigger: qlog.KeyUpdateTLS, KeyType: qlog.KeyTypeServerHandshake},
+		qlog.PacketDropped{Header: hdr, Raw: raw, Trigger: qlog.PacketDropUnknownConnectionID},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, run([]string{"diff", file1, file2}, &buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, []string{file1, file2}, strings.Fields(lines[0]))
+	rows := make(map[string][]string)
+	for _, line := range lines[1:] {
+		name, values, ok := strings.Cut(line, ":")
+		require.True(t, ok)
+		rows[strings.TrimSpace(name)] = strings.Fields(values)
+	}
+	require.Equal(t, []string{"1", "2", "+1"}, rows["packets sent"])
+	require.Equal(t, []string{"1200", "2400", "+1200"}, rows["bytes sent"])
+	require.Equal(t, []string{"1s", "3s", "+2s"}, rows["handshake keys"])
+	require.Equal(t, []string{"1", "0", "-1"}, rows[string(qlog.PacketLossTimeThreshold)])
+	require.Equal(t, []string{"0", "1", "+1"}, rows["packets dropped"])
+	// rows that only exist in one of the traces
+	require.Equal(t, []string{"-", "1"}, rows[string(qlog.PacketDropUnknownConnectionID)])
+	require.Equal(t, []string{"h3"}, rows["ALPN"])
+}
+
+func TestRunErrors(t *testing.T) {
+	require.ErrorContains(t, run(nil, io.Discard), "Usage")
+	require.ErrorContains(t, run([]string{"foo", "bar"}, io.Discard), "unknown command: foo")
+	require.ErrorContains(t, run([]string{"diff", "foo"}, io.Discard), "diff requires exactly two files")
+	require.ErrorIs(t, run([]string{"summary", filepath.Join(t.TempDir(), "missing.sqlog")}, io.Discard), os.ErrNotExist)
+}
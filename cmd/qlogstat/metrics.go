@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogreader"
+)
+
+var metricsHeader = []string{
+	"file",
+	"time_ms",
+	"min_rtt_ms",
+	"smoothed_rtt_ms",
+	"latest_rtt_ms",
+	"rtt_variance_ms",
+	"congestion_window",
+	"bytes_in_flight",
+	"packets_in_flight",
+	"pacing_rate",
+	"bandwidth_estimate",
+}
+
+// metricsWriter writes the recovery:metrics_updated timeline as CSV.
+type metricsWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newMetricsWriter(w io.Writer) *metricsWriter {
+	return &metricsWriter{w: csv.NewWriter(w)}
+}
+
+func (m *metricsWriter) write(file string, events []qlogreader.Event) error {
+	if !m.headerWritten {
+		if err := m.w.Write(metricsHeader); err != nil {
+			return err
+		}
+		m.headerWritten = true
+	}
+
+	// A metrics_updated event only contains the values that changed since the last event.
+	// The current values are therefore carried forward.
+	var cur qlog.MetricsUpdated
+	for _, ev := range events {
+		e, ok := ev.Event.(qlog.MetricsUpdated)
+		if !ok {
+			continue
+		}
+		if e.MinRTT != 0 {
+			cur.MinRTT = e.MinRTT
+		}
+		if e.SmoothedRTT != 0 {
+			cur.SmoothedRTT = e.SmoothedRTT
+		}
+		if e.LatestRTT != 0 {
+			cur.LatestRTT = e.LatestRTT
+		}
+		if e.RTTVariance != 0 {
+			cur.RTTVariance = e.RTTVariance
+		}
+		if e.CongestionWindow != 0 {
+			cur.CongestionWindow = e.CongestionWindow
+		}
+		// Since zero values are omitted from the event, it's not possible to distinguish
+		// between the bytes in flight dropping to 0 and the value not having changed.
+		if e.BytesInFlight != 0 {
+			cur.BytesInFlight = e.BytesInFlight
+		}
+		if e.PacketsInFlight != 0 {
+			cur.PacketsInFlight = e.PacketsInFlight
+		}
+		if e.PacingRate != 0 {
+			cur.PacingRate = e.PacingRate
+		}
+		if e.BandwidthEstimate != 0 {
+			cur.BandwidthEstimate = e.BandwidthEstimate
+		}
+		if err := m.w.Write([]string{
+			file,
+			formatMilliseconds(ev.RelativeTime),
+			formatMilliseconds(cur.MinRTT),
+			formatMilliseconds(cur.SmoothedRTT),
+			formatMilliseconds(cur.LatestRTT),
+			formatMilliseconds(cur.RTTVariance),
+			strconv.Itoa(cur.CongestionWindow),
+			strconv.Itoa(cur.BytesInFlight),
+			strconv.Itoa(cur.PacketsInFlight),
+			strconv.FormatUint(cur.PacingRate, 10),
+			strconv.FormatUint(cur.BandwidthEstimate, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *metricsWriter) flush() error {
+	m.w.Flush()
+	return m.w.Error()
+}
+
+func formatMilliseconds(d time.Duration) string {
+	return strconv.FormatFloat(float64(d.Nanoseconds())/1e6, 'f', -1, 64)
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogreader"
+)
+
+// A timestamp is the time of an event, relative to the first packet of the trace.
+// It is only valid if the event was found in the trace.
+type timestamp struct {
+	t     time.Duration
+	valid bool
+}
+
+func (t *timestamp) set(d time.Duration) {
+	if !t.valid {
+		t.t = d
+		t.valid = true
+	}
+}
+
+type summary struct {
+	File         string
+	VantagePoint string
+	GroupID      string
+	ALPN         string
+
+	// Duration is the time between the first and the last event.
+	Duration time.Duration
+
+	// handshake timing, relative to the first packet sent or received
+	firstPacket        timestamp
+	HandshakeKeys      timestamp
+	OneRTTKeys         timestamp
+	HandshakeConfirmed timestamp
+
+	PacketsSent     int
+	PacketsReceived int
+	BytesSent       int
+	BytesReceived   int
+
+	Losses          map[qlog.PacketLossReason]int
+	SpuriousLosses  int
+	MaxReordering   uint64
+	MaxReorderDelay time.Duration
+
+	PTOExpirations int
+	MaxPTOCount    uint32
+
+	Drops map[qlog.PacketDropReason]int
+
+	MinRTT              time.Duration
+	SmoothedRTT         time.Duration
+	MaxCongestionWindow int
+	MaxBytesInFlight    int
+
+	CloseInitiator qlog.Initiator
+	CloseReason    string
+
+	UnknownEvents int
+}
+
+func summarize(hdr qlogreader.Header, events []qlogreader.Event) *summary {
+	s := &summary{
+		VantagePoint: hdr.VantagePoint,
+		GroupID:      hdr.GroupID,
+		Losses:       make(map[qlog.PacketLossReason]int),
+		Drops:        make(map[qlog.PacketDropReason]int),
+	}
+	if len(events) > 0 {
+		s.Duration = events[len(events)-1].RelativeTime - events[0].RelativeTime
+	}
+	for _, ev := range events {
+		relTime := func() time.Duration { return ev.RelativeTime - s.firstPacket.t }
+		switch e := ev.Event.(type) {
+		case qlog.PacketSent:
+			s.firstPacket.set(ev.RelativeTime)
+			s.PacketsSent++
+			s.BytesSent += e.Raw.Length
+		case qlog.PacketReceived:
+			s.firstPacket.set(ev.RelativeTime)
+			s.PacketsReceived++
+			s.BytesReceived += e.Raw.Length
+		case qlog.ALPNInformation:
+			s.ALPN = e.ChosenALPN
+		case qlog.KeyUpdated:
+			switch e.KeyType {
+			case qlog.KeyTypeClientHandshake, qlog.KeyTypeServerHandshake:
+				s.HandshakeKeys.set(relTime())
+			case qlog.KeyTypeClient1RTT, qlog.KeyTypeServer1RTT:
+				if e.Trigger == qlog.KeyUpdateTLS {
+					s.OneRTTKeys.set(relTime())
+				}
+			}
+		case qlog.KeyDiscarded:
+			// Handshake keys are dropped when the handshake is confirmed.
+			if e.KeyType == qlog.KeyTypeClientHandshake || e.KeyType == qlog.KeyTypeServerHandshake {
+				s.HandshakeConfirmed.set(relTime())
+			}
+		case qlog.PacketLost:
+			s.Losses[e.Trigger]++
+		case qlog.SpuriousLoss:
+			s.SpuriousLosses++
+			s.MaxReordering = max(s.MaxReordering, e.PacketReordering)
+			s.MaxReorderDelay = max(s.MaxReorderDelay, e.TimeReordering)
+		case qlog.LossTimerUpdated:
+			if e.Type == qlog.LossTimerUpdateTypeExpired && e.TimerType == qlog.TimerTypePTO {
+				s.PTOExpirations++
+			}
+		case qlog.PTOCountUpdated:
+			s.MaxPTOCount = max(s.MaxPTOCount, e.PTOCount)
+		case qlog.PacketDropped:
+			s.Drops[e.Trigger]++
+		case qlog.MetricsUpdated:
+			if e.MinRTT != 0 {
+				s.MinRTT = e.MinRTT
+			}
+			if e.SmoothedRTT != 0 {
+				s.SmoothedRTT = e.SmoothedRTT
+			}
+			s.MaxCongestionWindow = max(s.MaxCongestionWindow, e.CongestionWindow)
+			s.MaxBytesInFlight = max(s.MaxBytesInFlight, e.BytesInFlight)
+		case qlog.ConnectionClosed:
+			s.CloseInitiator = e.Initiator
+			switch {
+			case e.ConnectionError != nil:
+				s.CloseReason = fmt.Sprintf("transport error %s", e.ConnectionError.String())
+			case e.ApplicationError != nil:
+				s.CloseReason = fmt.Sprintf("application error %#x", uint64(*e.ApplicationError))
+			default:
+				s.CloseReason = string(e.Trigger)
+			}
+			if e.Reason != "" {
+				s.CloseReason += fmt.Sprintf(" (%s)", e.Reason)
+			}
+		case qlogreader.RawEvent:
+			s.UnknownEvents++
+		}
+	}
+	return s
+}
+
+// A statRow is a single line of the summary.
+// The value is either an int, a time.Duration, a timestamp or a string.
+type statRow struct {
+	name  string
+	value any
+}
+
+var lossReasons = []qlog.PacketLossReason{qlog.PacketLossReorderingThreshold, qlog.PacketLossTimeThreshold}
+
+func (s *summary) rows() []statRow {
+	rows := []statRow{
+		{"vantage point", s.VantagePoint},
+		{"group ID", s.GroupID},
+		{"ALPN", s.ALPN},
+		{"duration", s.Duration},
+		{"handshake keys", s.HandshakeKeys},
+		{"1-RTT keys", s.OneRTTKeys},
+		{"handshake confirmed", s.HandshakeConfirmed},
+		{"packets sent", s.PacketsSent},
+		{"packets received", s.PacketsReceived},
+		{"bytes sent", s.BytesSent},
+		{"bytes received", s.BytesReceived},
+		{"min RTT", s.MinRTT},
+		{"smoothed RTT", s.SmoothedRTT},
+		{"max congestion window", s.MaxCongestionWindow},
+		{"max bytes in flight", s.MaxBytesInFlight},
+	}
+	var lost int
+	for _, n := range s.Losses {
+		lost += n
+	}
+	rows = append(rows, statRow{"packets lost", lost})
+	for _, reason := range lossReasons {
+		rows = append(rows, statRow{fmt.Sprintf("  %s", reason), s.Losses[reason]})
+	}
+	for _, reason := range sortedKeys(s.Losses) {
+		if !slices.Contains(lossReasons, reason) {
+			rows = append(rows, statRow{fmt.Sprintf("  %s", reason), s.Losses[reason]})
+		}
+	}
+	rows = append(rows,
+		statRow{"spurious losses", s.SpuriousLosses},
+		statRow{"  max reordering (packets)", int(s.MaxReordering)},
+		statRow{"  max reordering (time)", s.MaxReorderDelay},
+		statRow{"PTO expirations", s.PTOExpirations},
+		statRow{"max PTO count", int(s.MaxPTOCount)},
+	)
+	var dropped int
+	for _, n := range s.Drops {
+		dropped += n
+	}
+	rows = append(rows, statRow{"packets dropped", dropped})
+	for _, reason := range sortedKeys(s.Drops) {
+		rows = append(rows, statRow{fmt.Sprintf("  %s", reason), s.Drops[reason]})
+	}
+	if s.CloseInitiator != "" {
+		rows = append(rows, statRow{"closed by", string(s.CloseInitiator)}, statRow{"close reason", s.CloseReason})
+	}
+	if s.UnknownEvents > 0 {
+		rows = append(rows, statRow{"unknown events", s.UnknownEvents})
+	}
+	return rows
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case time.Duration:
+		return formatDuration(v)
+	case timestamp:
+		if !v.valid {
+			return "-"
+		}
+		return formatDuration(v.t)
+	case int:
+		return fmt.Sprintf("%d", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
+
+// formatDelta formats the difference between two numeric values.
+// It returns an empty string for values that can't be compared.
+func formatDelta(a, b any) string {
+	switch a := a.(type) {
+	case int:
+		if b, ok := b.(int); ok && a != b {
+			return fmt.Sprintf("%+d", b-a)
+		}
+	case time.Duration:
+		if b, ok := b.(time.Duration); ok && a != b {
+			return formatSignedDuration(b - a)
+		}
+	case timestamp:
+		if b, ok := b.(timestamp); ok && a.valid && b.valid && a.t != b.t {
+			return formatSignedDuration(b.t - a.t)
+		}
+	}
+	return ""
+}
+
+func formatSignedDuration(d time.Duration) string {
+	if d < 0 {
+		return formatDuration(d)
+	}
+	return "+" + formatDuration(d)
+}
+
+func (s *summary) print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\n", s.File)
+	for _, r := range s.rows() {
+		fmt.Fprintf(tw, "  %s:\t%s\n", r.name, formatValue(r.value))
+	}
+	return tw.Flush()
+}
+
+// printDiff prints the summaries of two traces side by side,
+// together with the difference for numeric values.
+func printDiff(w io.Writer, a, b *summary) error {
+	rowsA, rowsB := a.rows(), b.rows()
+	valuesB := make(map[string]any, len(rowsB))
+	for _, r := range rowsB {
+		valuesB[r.name] = r.value
+	}
+	// rows that only exist in the second trace are appended at the end
+	names := make([]string, 0, len(rowsA))
+	valuesA := make(map[string]any, len(rowsA))
+	for _, r := range rowsA {
+		names = append(names, r.name)
+		valuesA[r.name] = r.value
+	}
+	for _, r := range rowsB {
+		if _, ok := valuesA[r.name]; !ok {
+			names = append(names, r.name)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "\t%s\t%s\t\n", a.File, b.File)
+	for _, name := range names {
+		va, okA := valuesA[name]
+		vb, okB := valuesB[name]
+		sa, sb := "-", "-"
+		if okA {
+			sa = formatValue(va)
+		}
+		if okB {
+			sb = formatValue(vb)
+		}
+		fmt.Fprintf(tw, "%s:\t%s\t%s\t%s\n", name, sa, sb, formatDelta(va, vb))
+	}
+	return tw.Flush()
+}
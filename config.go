@@ -1,10 +1,14 @@
 package quic
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/quic-go/quic-go/congestion"
+	internalcongestion "github.com/quic-go/quic-go/internal/congestion"
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/wire"
 	"github.com/quic-go/quic-go/quicvarint"
 )
 
@@ -22,6 +26,22 @@ func (c *Config) maxRetryTokenAge() time.Duration {
 	return c.handshakeTimeout()
 }
 
+// congestionControl returns the function that creates the congestion controller for a path.
+// It returns nil if no CongestionControl is configured, in which case NewReno is used.
+func (c *Config) congestionControl() func(internalcongestion.Params) internalcongestion.SendAlgorithm {
+	if c.CongestionControl == nil {
+		return nil
+	}
+	return func(p internalcongestion.Params) internalcongestion.SendAlgorithm {
+		return internalcongestion.FromTimeSendAlgorithm(c.CongestionControl(congestion.Params{
+			RTTStats:                 p.RTTStats,
+			InitialMaxDatagramSize:   p.InitialMaxDatagramSize,
+			InitialBandwidthEstimate: p.InitialBandwidthEstimate,
+			Recorder:                 p.Recorder,
+		}))
+	}
+}
+
 func validateConfig(config *Config) error {
 	if config == nil {
 		return nil
@@ -39,12 +59,49 @@ func validateConfig(config *Config) error {
 	if config.MaxConnectionReceiveWindow > quicvarint.Max {
 		config.MaxConnectionReceiveWindow = quicvarint.Max
 	}
+	if config.KeyUpdatePacketInterval > protocol.KeyUpdateInterval {
+		config.KeyUpdatePacketInterval = protocol.KeyUpdateInterval
+	}
+	if config.InitialRTT < 0 {
+		config.InitialRTT = 0
+	}
 	if config.InitialPacketSize > 0 && config.InitialPacketSize < protocol.MinInitialPacketSize {
 		config.InitialPacketSize = protocol.MinInitialPacketSize
 	}
 	if config.InitialPacketSize > protocol.MaxPacketBufferSize {
 		config.InitialPacketSize = protocol.MaxPacketBufferSize
 	}
+	if pa := config.PreferredAddress; pa != nil {
+		if !pa.IPv4.IsValid() && !pa.IPv6.IsValid() {
+			return errors.New("invalid preferred address: no IPv4 or IPv6 address")
+		}
+		if pa.IPv4.IsValid() && !pa.IPv4.Addr().Is4() {
+			return fmt.Errorf("invalid preferred address: %s is not an IPv4 address", pa.IPv4)
+		}
+		if pa.IPv6.IsValid() && !pa.IPv6.Addr().Is6() {
+			return fmt.Errorf("invalid preferred address: %s is not an IPv6 address", pa.IPv6)
+		}
+		if pa.Transport == nil {
+			return errors.New("invalid preferred address: no Transport")
+		}
+	}
+	if config.DatagramSendDropPolicy > DatagramDropOldest {
+		return fmt.Errorf("invalid datagram send drop policy: %d", config.DatagramSendDropPolicy)
+	}
+	if config.DatagramReceiveDropPolicy > DatagramDropOldest {
+		return fmt.Errorf("invalid datagram receive drop policy: %d", config.DatagramReceiveDropPolicy)
+	}
+	for id := range config.AdditionalTransportParameters {
+		if id > quicvarint.Max {
+			return fmt.Errorf("invalid transport parameter ID: %d", id)
+		}
+		if wire.IsReservedTransportParameterID(id) {
+			return fmt.Errorf("transport parameter ID %#x is reserved", id)
+		}
+		if _, ok := wire.AdditionalTransportParametersClient[id]; ok {
+			return fmt.Errorf("transport parameter ID %#x is already used", id)
+		}
+	}
 	// check that all QUIC versions are actually supported
 	for _, v := range config.Versions {
 		if !protocol.IsValidVersion(v) {
@@ -100,10 +157,22 @@ func populateConfig(config *Config) *Config {
 	} else if maxIncomingUniStreams < 0 {
 		maxIncomingUniStreams = 0
 	}
+	datagramSendQueueLen := config.DatagramSendQueueLen
+	if datagramSendQueueLen <= 0 {
+		datagramSendQueueLen = protocol.DefaultDatagramSendQueueLen
+	}
+	datagramReceiveQueueLen := config.DatagramReceiveQueueLen
+	if datagramReceiveQueueLen <= 0 {
+		datagramReceiveQueueLen = protocol.DefaultDatagramReceiveQueueLen
+	}
 	initialPacketSize := config.InitialPacketSize
 	if initialPacketSize == 0 {
 		initialPacketSize = protocol.InitialPacketSize
 	}
+	keyUpdatePacketInterval := config.KeyUpdatePacketInterval
+	if keyUpdatePacketInterval == 0 {
+		keyUpdatePacketInterval = protocol.KeyUpdateInterval
+	}
 
 	return &Config{
 		GetConfigForClient:               config.GetConfigForClient,
@@ -111,6 +180,9 @@ func populateConfig(config *Config) *Config {
 		HandshakeIdleTimeout:             handshakeIdleTimeout,
 		MaxIdleTimeout:                   idleTimeout,
 		KeepAlivePeriod:                  config.KeepAlivePeriod,
+		InitialRTT:                       config.InitialRTT,
+		KeyUpdatePacketInterval:          keyUpdatePacketInterval,
+		KeyUpdateTimeInterval:            config.KeyUpdateTimeInterval,
 		InitialStreamReceiveWindow:       initialStreamReceiveWindow,
 		MaxStreamReceiveWindow:           maxStreamReceiveWindow,
 		InitialConnectionReceiveWindow:   initialConnectionReceiveWindow,
@@ -120,10 +192,21 @@ func populateConfig(config *Config) *Config {
 		MaxIncomingUniStreams:            maxIncomingUniStreams,
 		TokenStore:                       config.TokenStore,
 		EnableDatagrams:                  config.EnableDatagrams,
+		DatagramSendQueueLen:             datagramSendQueueLen,
+		DatagramSendDropPolicy:           config.DatagramSendDropPolicy,
+		DatagramReceiveQueueLen:          datagramReceiveQueueLen,
+		DatagramReceiveDropPolicy:        config.DatagramReceiveDropPolicy,
 		InitialPacketSize:                initialPacketSize,
 		DisablePathMTUDiscovery:          config.DisablePathMTUDiscovery,
+		CongestionControl:                config.CongestionControl,
+		PreferredAddress:                 config.PreferredAddress,
+		DisablePreferredAddressMigration: config.DisablePreferredAddressMigration,
 		EnableStreamResetPartialDelivery: config.EnableStreamResetPartialDelivery,
+		EnableMultipath:                  config.EnableMultipath,
+		EnableAckFrequency:               config.EnableAckFrequency,
+		AdditionalTransportParameters:    config.AdditionalTransportParameters,
 		Allow0RTT:                        config.Allow0RTT,
+		AntiReplay:                       config.AntiReplay,
 		Tracer:                           config.Tracer,
 	}
 }
@@ -2,11 +2,15 @@ package quic
 
 import (
 	"context"
+	"fmt"
+	"net/netip"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/quic-go/quic-go/congestion"
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/wire"
 	"github.com/quic-go/quic-go/qlogwriter"
 	"github.com/quic-go/quic-go/quicvarint"
 
@@ -65,6 +69,63 @@ func TestConfigValidation(t *testing.T) {
 		require.NoError(t, validateConfig(conf))
 		require.Equal(t, uint16(protocol.MaxPacketBufferSize), conf.InitialPacketSize)
 	})
+
+	t.Run("key update interval", func(t *testing.T) {
+		conf := &Config{KeyUpdatePacketInterval: protocol.KeyUpdateInterval + 1}
+		require.NoError(t, validateConfig(conf))
+		require.Equal(t, uint64(protocol.KeyUpdateInterval), conf.KeyUpdatePacketInterval)
+	})
+
+	t.Run("initial RTT", func(t *testing.T) {
+		conf := &Config{InitialRTT: -time.Second}
+		require.NoError(t, validateConfig(conf))
+		require.Zero(t, conf.InitialRTT)
+	})
+
+	t.Run("preferred address", func(t *testing.T) {
+		tr := &Transport{}
+		ipv4 := netip.MustParseAddrPort("1.2.3.4:443")
+		ipv6 := netip.MustParseAddrPort("[2001:db8::1]:443")
+		require.NoError(t, validateConfig(&Config{PreferredAddress: &PreferredAddress{IPv4: ipv4, Transport: tr}}))
+		require.NoError(t, validateConfig(&Config{PreferredAddress: &PreferredAddress{IPv6: ipv6, Transport: tr}}))
+		require.NoError(t, validateConfig(&Config{PreferredAddress: &PreferredAddress{IPv4: ipv4, IPv6: ipv6, Transport: tr}}))
+
+		err := validateConfig(&Config{PreferredAddress: &PreferredAddress{Transport: tr}})
+		require.EqualError(t, err, "invalid preferred address: no IPv4 or IPv6 address")
+		err = validateConfig(&Config{PreferredAddress: &PreferredAddress{IPv4: ipv6, Transport: tr}})
+		require.EqualError(t, err, "invalid preferred address: [2001:db8::1]:443 is not an IPv4 address")
+		err = validateConfig(&Config{PreferredAddress: &PreferredAddress{IPv6: ipv4, Transport: tr}})
+		require.EqualError(t, err, "invalid preferred address: 1.2.3.4:443 is not an IPv6 address")
+		err = validateConfig(&Config{PreferredAddress: &PreferredAddress{IPv4: ipv4}})
+		require.EqualError(t, err, "invalid preferred address: no Transport")
+	})
+
+	t.Run("datagram drop policies", func(t *testing.T) {
+		require.NoError(t, validateConfig(&Config{DatagramSendDropPolicy: DatagramDropNewest, DatagramReceiveDropPolicy: DatagramDropOldest}))
+		err := validateConfig(&Config{DatagramSendDropPolicy: 42})
+		require.EqualError(t, err, "invalid datagram send drop policy: 42")
+		err = validateConfig(&Config{DatagramReceiveDropPolicy: 42})
+		require.EqualError(t, err, "invalid datagram receive drop policy: 42")
+	})
+
+	t.Run("additional transport parameters", func(t *testing.T) {
+		require.NoError(t, validateConfig(&Config{AdditionalTransportParameters: map[uint64][]byte{0x1337: []byte("foo")}}))
+		// max_idle_timeout
+		err := validateConfig(&Config{AdditionalTransportParameters: map[uint64][]byte{0x1: nil}})
+		require.EqualError(t, err, "transport parameter ID 0x1 is reserved")
+		// greased transport parameter
+		err = validateConfig(&Config{AdditionalTransportParameters: map[uint64][]byte{31*42 + 27: nil}})
+		require.EqualError(t, err, "transport parameter ID 0x531 is reserved")
+		err = validateConfig(&Config{AdditionalTransportParameters: map[uint64][]byte{quicvarint.Max + 1: nil}})
+		require.EqualError(t, err, fmt.Sprintf("invalid transport parameter ID: %d", uint64(quicvarint.Max+1)))
+
+		// transport parameters already added by the test hook
+		origAdditionalTransportParametersClient := wire.AdditionalTransportParametersClient
+		t.Cleanup(func() { wire.AdditionalTransportParametersClient = origAdditionalTransportParametersClient })
+		wire.AdditionalTransportParametersClient = map[uint64][]byte{0x1337: []byte("foobar")}
+		err = validateConfig(&Config{AdditionalTransportParameters: map[uint64][]byte{0x1337: []byte("foo")}})
+		require.EqualError(t, err, "transport parameter ID 0x1337 is already used")
+	})
 }
 
 func TestConfigHandshakeIdleTimeout(t *testing.T) {
@@ -86,7 +147,7 @@ func configWithNonZeroNonFunctionFields(t *testing.T) *Config {
 		}
 
 		switch fn := typ.Field(i).Name; fn {
-		case "GetConfigForClient", "RequireAddressValidation", "GetLogWriter", "AllowConnectionWindowIncrease", "Tracer":
+		case "GetConfigForClient", "RequireAddressValidation", "GetLogWriter", "AllowConnectionWindowIncrease", "CongestionControl", "Tracer":
 			// Can't compare functions.
 		case "Versions":
 			f.Set(reflect.ValueOf([]Version{1, 2, 3}))
@@ -114,20 +175,46 @@ func configWithNonZeroNonFunctionFields(t *testing.T) *Config {
 			f.Set(reflect.ValueOf(int64(12)))
 		case "StatelessResetKey":
 			f.Set(reflect.ValueOf(&StatelessResetKey{1, 2, 3, 4}))
+		case "KeyUpdatePacketInterval":
+			f.Set(reflect.ValueOf(uint64(1000)))
+		case "KeyUpdateTimeInterval":
+			f.Set(reflect.ValueOf(time.Minute))
 		case "KeepAlivePeriod":
 			f.Set(reflect.ValueOf(time.Second))
 		case "EnableDatagrams":
 			f.Set(reflect.ValueOf(true))
+		case "DatagramSendQueueLen":
+			f.Set(reflect.ValueOf(13))
+		case "DatagramSendDropPolicy":
+			f.Set(reflect.ValueOf(DatagramDropOldest))
+		case "DatagramReceiveQueueLen":
+			f.Set(reflect.ValueOf(37))
+		case "DatagramReceiveDropPolicy":
+			f.Set(reflect.ValueOf(DatagramDropOldest))
 		case "DisableVersionNegotiationPackets":
 			f.Set(reflect.ValueOf(true))
+		case "InitialRTT":
+			f.Set(reflect.ValueOf(50 * time.Millisecond))
 		case "InitialPacketSize":
 			f.Set(reflect.ValueOf(uint16(1350)))
 		case "DisablePathMTUDiscovery":
 			f.Set(reflect.ValueOf(true))
+		case "PreferredAddress":
+			f.Set(reflect.ValueOf(&PreferredAddress{IPv4: netip.MustParseAddrPort("1.2.3.4:443"), Transport: &Transport{}}))
+		case "DisablePreferredAddressMigration":
+			f.Set(reflect.ValueOf(true))
+		case "AntiReplay":
+			f.Set(reflect.ValueOf(NewInMemoryAntiReplayStore(time.Minute)))
 		case "Allow0RTT":
 			f.Set(reflect.ValueOf(true))
 		case "EnableStreamResetPartialDelivery":
 			f.Set(reflect.ValueOf(true))
+		case "EnableMultipath":
+			f.Set(reflect.ValueOf(true))
+		case "EnableAckFrequency":
+			f.Set(reflect.ValueOf(true))
+		case "AdditionalTransportParameters":
+			f.Set(reflect.ValueOf(map[uint64][]byte{1337: []byte("foobar")}))
 		default:
 			t.Fatalf("all fields must be accounted for, but saw unknown field %q", fn)
 		}
@@ -137,10 +224,14 @@ func configWithNonZeroNonFunctionFields(t *testing.T) *Config {
 
 func TestConfigClone(t *testing.T) {
 	t.Run("function fields", func(t *testing.T) {
-		var calledAllowConnectionWindowIncrease, calledTracer bool
+		var calledAllowConnectionWindowIncrease, calledCongestionControl, calledTracer bool
 		c1 := &Config{
 			GetConfigForClient:            func(info *ClientInfo) (*Config, error) { return nil, assert.AnError },
 			AllowConnectionWindowIncrease: func(*Conn, uint64) bool { calledAllowConnectionWindowIncrease = true; return true },
+			CongestionControl: func(congestion.Params) congestion.SendAlgorithm {
+				calledCongestionControl = true
+				return nil
+			},
 			Tracer: func(context.Context, bool, ConnectionID) qlogwriter.Trace {
 				calledTracer = true
 				return nil
@@ -153,6 +244,8 @@ func TestConfigClone(t *testing.T) {
 		require.ErrorIs(t, err, assert.AnError)
 		c2.Tracer(context.Background(), true, protocol.ConnectionID{})
 		require.True(t, calledTracer)
+		c2.CongestionControl(congestion.Params{})
+		require.True(t, calledCongestionControl)
 	})
 
 	t.Run("non-function fields", func(t *testing.T) {
@@ -184,7 +277,11 @@ func TestConfigDefaultValues(t *testing.T) {
 	require.EqualValues(t, protocol.DefaultMaxReceiveConnectionFlowControlWindow, c.MaxConnectionReceiveWindow)
 	require.EqualValues(t, protocol.DefaultMaxIncomingStreams, c.MaxIncomingStreams)
 	require.EqualValues(t, protocol.DefaultMaxIncomingUniStreams, c.MaxIncomingUniStreams)
+	require.Equal(t, protocol.DefaultDatagramSendQueueLen, c.DatagramSendQueueLen)
+	require.Equal(t, protocol.DefaultDatagramReceiveQueueLen, c.DatagramReceiveQueueLen)
 	require.False(t, c.DisablePathMTUDiscovery)
+	require.EqualValues(t, protocol.KeyUpdateInterval, c.KeyUpdatePacketInterval)
+	require.Zero(t, c.KeyUpdateTimeInterval)
 	require.Nil(t, c.GetConfigForClient)
 }
 
@@ -0,0 +1,170 @@
+// Package congestion defines the interface between a QUIC connection and its congestion controller.
+package congestion
+
+import (
+	"time"
+
+	"github.com/quic-go/quic-go/internal/congestion"
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/qlogwriter"
+)
+
+type (
+	// ByteCount is a number of bytes.
+	ByteCount = protocol.ByteCount
+	// PacketNumber is a QUIC packet number.
+	PacketNumber = protocol.PacketNumber
+	// Bandwidth of a connection, in bits per second.
+	Bandwidth = congestion.Bandwidth
+	// A RateSample is a delivery rate sample.
+	RateSample = congestion.RateSample
+)
+
+const (
+	// BitsPerSecond is 1 bit per second.
+	BitsPerSecond = congestion.BitsPerSecond
+	// BytesPerSecond is 1 byte per second.
+	BytesPerSecond = congestion.BytesPerSecond
+)
+
+var (
+	_ SendAlgorithm                     = congestion.TimeSendAlgorithm(nil)
+	_ congestion.TimeSendAlgorithm      = SendAlgorithm(nil)
+	_ RateSampleConsumer                = congestion.TimeRateSampleConsumer(nil)
+	_ congestion.TimeRateSampleConsumer = RateSampleConsumer(nil)
+)
+
+// RTTStats provides the RTT estimates of the path that a SendAlgorithm is used on.
+type RTTStats interface {
+	// MinRTT is the minimum RTT observed on the path.
+	MinRTT() time.Duration
+	// LatestRTT is the most recent RTT sample.
+	LatestRTT() time.Duration
+	// SmoothedRTT is the smoothed RTT, as defined in RFC 9002.
+	SmoothedRTT() time.Duration
+	// MeanDeviation is the mean deviation of the RTT samples.
+	MeanDeviation() time.Duration
+}
+
+// A SendAlgorithm is a congestion controller.
+// A new SendAlgorithm is created for every connection, and every time the connection migrates to a new path.
+// It is only accessed from the connection's run loop, and doesn't need to be safe for concurrent use.
+type SendAlgorithm interface {
+	// TimeUntilSend returns the time when the next packet may be sent.
+	// A zero value means that sending is not limited by pacing.
+	TimeUntilSend(bytesInFlight ByteCount) time.Time
+	// HasPacingBudget returns whether a full-sized packet may be sent at the given time.
+	HasPacingBudget(now time.Time) bool
+	// OnPacketSent is called when a packet is sent.
+	// For ack-eliciting packets, bytesInFlight includes the packet.
+	// isRetransmittable is false for packets that only contain ACK frames.
+	OnPacketSent(sentTime time.Time, bytesInFlight ByteCount, packetNumber PacketNumber, bytes ByteCount, isRetransmittable bool)
+	// CanSend returns whether the congestion window allows sending more data.
+	CanSend(bytesInFlight ByteCount) bool
+	// MaybeExitSlowStart is called every time a new RTT sample is obtained.
+	MaybeExitSlowStart()
+	// OnPacketAcked is called for every newly acknowledged packet that was counted towards bytes in flight.
+	OnPacketAcked(number PacketNumber, ackedBytes ByteCount, priorInFlight ByteCount, eventTime time.Time)
+	// OnCongestionEvent is called when a packet is declared lost,
+	// or when the peer reports an increase in the ECN-CE count (in which case lostBytes is 0).
+	OnCongestionEvent(number PacketNumber, lostBytes ByteCount, priorInFlight ByteCount)
+	// OnRetransmissionTimeout is called when a probe timeout fires.
+	OnRetransmissionTimeout(packetsRetransmitted bool)
+	// SetMaxDatagramSize is called when the maximum datagram size increases,
+	// e.g. after Path MTU Discovery found a larger MTU.
+	SetMaxDatagramSize(ByteCount)
+	// GetCongestionWindow returns the current congestion window.
+	GetCongestionWindow() ByteCount
+	// PacingRate returns the rate at which packets are currently paced out.
+	PacingRate() Bandwidth
+}
+
+// A RateSampleConsumer is a SendAlgorithm that uses delivery rate samples.
+// OnRateSample is called once for every ACK frame that newly acknowledges packets,
+// after OnPacketAcked and OnCongestionEvent were called for all packets acknowledged and declared lost.
+type RateSampleConsumer interface {
+	OnRateSample(eventTime time.Time, bytesInFlight ByteCount, rs RateSample)
+}
+
+// A BandwidthEstimator is a SendAlgorithm that estimates the bandwidth of the path.
+// If implemented, the estimate is included in qlog metrics_updated events.
+type BandwidthEstimator interface {
+	BandwidthEstimate() Bandwidth
+}
+
+// A SlowStartThresholdReporter is a SendAlgorithm that uses a slow start threshold.
+// If implemented, the threshold is included in the connection stats.
+// A threshold of (1<<62)-1 or larger means that no threshold has been set (yet).
+type SlowStartThresholdReporter interface {
+	SlowStartThreshold() ByteCount
+}
+
+// Params are the parameters passed to the congestion controller when it is created.
+type Params struct {
+	// RTTStats are the RTT estimates of the path.
+	RTTStats RTTStats
+	// InitialMaxDatagramSize is the maximum datagram size at the start of the connection.
+	InitialMaxDatagramSize ByteCount
+	// InitialBandwidthEstimate is the bandwidth of the path saved by a previous connection
+	// in the Transport's PathMetricsCache. It is 0 if no estimate is available.
+	// The built-in congestion controllers use it to choose a larger initial congestion window.
+	InitialBandwidthEstimate Bandwidth
+	// Recorder is used to emit qlog events. It is nil if qlog is disabled.
+	Recorder qlogwriter.Recorder
+}
+
+// NewReno creates a new NewReno congestion controller.
+// This is the default congestion controller.
+func NewReno(p Params) SendAlgorithm {
+	return congestion.ToTimeSendAlgorithm(
+		congestion.NewCubicSender(congestion.DefaultClock{}, p.RTTStats, p.InitialMaxDatagramSize, p.InitialBandwidthEstimate, true, p.Recorder),
+	)
+}
+
+// NewCubic creates a new CUBIC congestion controller.
+func NewCubic(p Params) SendAlgorithm {
+	return congestion.ToTimeSendAlgorithm(
+		congestion.NewCubicSender(congestion.DefaultClock{}, p.RTTStats, p.InitialMaxDatagramSize, p.InitialBandwidthEstimate, false, p.Recorder),
+	)
+}
+
+// NewBBR creates a new BBR (version 3) congestion controller.
+func NewBBR(p Params) SendAlgorithm {
+	return congestion.ToTimeSendAlgorithm(
+		congestion.NewBBRSender(congestion.DefaultClock{}, p.RTTStats, p.InitialMaxDatagramSize, p.InitialBandwidthEstimate, p.Recorder),
+	)
+}
+
+// A Pacer implements a token bucket pacing algorithm.
+// It can be used by SendAlgorithm implementations to pace out packets.
+type Pacer struct {
+	p *congestion.Pacer
+}
+
+// NewPacer creates a new Pacer.
+// getPacingRate is called every time the pacer needs to know the current pacing rate.
+func NewPacer(getPacingRate func() Bandwidth) *Pacer {
+	return &Pacer{p: congestion.NewPacer(getPacingRate)}
+}
+
+// SentPacket is called when a packet is sent.
+func (p *Pacer) SentPacket(sendTime time.Time, size ByteCount) {
+	p.p.SentPacket(monotime.FromTime(sendTime), size)
+}
+
+// Budget returns the number of bytes that can be sent at the given time.
+func (p *Pacer) Budget(now time.Time) ByteCount {
+	return p.p.Budget(monotime.FromTime(now))
+}
+
+// TimeUntilSend returns when the next packet should be sent.
+// It returns the zero time if a packet can be sent immediately.
+func (p *Pacer) TimeUntilSend() time.Time {
+	return p.p.TimeUntilSend().ToTime()
+}
+
+// SetMaxDatagramSize sets the maximum datagram size.
+func (p *Pacer) SetMaxDatagramSize(s ByteCount) {
+	p.p.SetMaxDatagramSize(s)
+}
@@ -0,0 +1,66 @@
+package congestion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultControllers(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cc   func(Params) SendAlgorithm
+	}{
+		{name: "NewReno", cc: NewReno},
+		{name: "CUBIC", cc: NewCubic},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rttStats := utils.NewRTTStats()
+			rttStats.UpdateRTT(100*time.Millisecond, 0)
+			cc := tc.cc(Params{RTTStats: rttStats, InitialMaxDatagramSize: 1200})
+
+			cwnd := cc.GetCongestionWindow()
+			require.Equal(t, ByteCount(32*1200), cwnd)
+			require.True(t, cc.CanSend(cwnd-1))
+			require.False(t, cc.CanSend(cwnd))
+			// the pacing rate is slightly higher than the bandwidth estimate
+			require.Equal(t, Bandwidth(cwnd)*BytesPerSecond*10*5/4, cc.PacingRate())
+
+			now := time.Now()
+			require.True(t, cc.HasPacingBudget(now))
+			cc.OnPacketSent(now, 1200, 0, 1200, true)
+			cc.OnPacketAcked(0, 1200, cwnd, now.Add(100*time.Millisecond))
+			require.Equal(t, cwnd+1200, cc.GetCongestionWindow()) // slow start
+
+			require.Implements(t, (*SlowStartThresholdReporter)(nil), cc)
+			require.GreaterOrEqual(t, cc.(SlowStartThresholdReporter).SlowStartThreshold(), ByteCount(1<<62-1))
+			cc.OnCongestionEvent(0, 1200, cwnd)
+			require.Less(t, cc.GetCongestionWindow(), cwnd)
+			require.Equal(t, cc.GetCongestionWindow(), cc.(SlowStartThresholdReporter).SlowStartThreshold())
+		})
+	}
+}
+
+func TestPacer(t *testing.T) {
+	rate := 1e6 * BytesPerSecond
+	p := NewPacer(func() Bandwidth { return rate })
+	p.SetMaxDatagramSize(1500)
+	now := time.Now()
+	budget := p.Budget(now)
+	require.NotZero(t, budget)
+	p.SentPacket(now, budget)
+	require.Zero(t, p.Budget(now))
+	require.WithinDuration(t, now.Add(1500*time.Microsecond), p.TimeUntilSend(), time.Microsecond)
+}
+
+func TestBBR(t *testing.T) {
+	rttStats := utils.NewRTTStats()
+	cc := NewBBR(Params{RTTStats: rttStats, InitialMaxDatagramSize: 1200})
+	require.Implements(t, (*RateSampleConsumer)(nil), cc)
+	require.Implements(t, (*BandwidthEstimator)(nil), cc)
+	require.Equal(t, ByteCount(32*1200), cc.GetCongestionWindow())
+	require.NotZero(t, cc.PacingRate())
+}
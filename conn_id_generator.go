@@ -1,6 +1,7 @@
 package quic
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"time"
@@ -43,6 +44,12 @@ type connIDToRetire struct {
 	connID protocol.ConnectionID
 }
 
+// pathConnIDs are the connection IDs issued for a single path of a multipath connection.
+type pathConnIDs struct {
+	nextSeq uint64
+	active  map[uint64]protocol.ConnectionID
+}
+
 type connIDGenerator struct {
 	generator   ConnectionIDGenerator
 	highestSeq  uint64
@@ -52,6 +59,10 @@ type connIDGenerator struct {
 	connIDsToRetire         []connIDToRetire       // sorted by t
 	initialClientDestConnID *protocol.ConnectionID // nil for the client
 
+	// connection IDs issued for the paths of a multipath connection (path ID > 0)
+	maxPathID   protocol.PathID
+	pathConnIDs map[protocol.PathID]*pathConnIDs // initialized lazily
+
 	statelessResetter *statelessResetter
 
 	queueControlFrame func(wire.Frame)
@@ -86,8 +97,6 @@ func (m *connIDGenerator) SetMaxActiveConnIDs(limit uint64) error {
 	// connection IDs the peer will store. This limit includes the connection ID
 	// used during the handshake, and the one sent in the preferred_address
 	// transport parameter.
-	// We currently don't send the preferred_address transport parameter,
-	// so we can issue (limit - 1) connection IDs.
 	for i := uint64(len(m.activeSrcConnIDs)); i < min(limit, protocol.MaxIssuedConnectionIDs); i++ {
 		if err := m.issueNewConnID(); err != nil {
 			return err
@@ -96,6 +105,25 @@ func (m *connIDGenerator) SetMaxActiveConnIDs(limit uint64) error {
 	return nil
 }
 
+// AddPreferredAddressConnID issues the connection ID sent in the preferred_address transport parameter.
+// Packets for this connection are then also accepted on the Transport of the preferred address.
+// It must be called before any other connection ID is issued, since this connection ID
+// has the sequence number 1 (see section 5.1.1 of RFC 9000).
+func (m *connIDGenerator) AddPreferredAddressConnID(runner connRunner, r connRunnerCallbacks) (protocol.ConnectionID, protocol.StatelessResetToken, error) {
+	if m.highestSeq != 0 {
+		return protocol.ConnectionID{}, protocol.StatelessResetToken{}, errors.New("preferred address connection ID must have sequence number 1")
+	}
+	connID, err := m.generator.GenerateConnectionID()
+	if err != nil {
+		return protocol.ConnectionID{}, protocol.StatelessResetToken{}, err
+	}
+	m.highestSeq++
+	m.activeSrcConnIDs[m.highestSeq] = connID
+	m.connRunners.AddConnectionID(connID)
+	m.AddConnRunner(runner, r)
+	return connID, m.statelessResetter.GetStatelessResetToken(connID), nil
+}
+
 func (m *connIDGenerator) Retire(seq uint64, sentWithDestConnID protocol.ConnectionID, expiry monotime.Time) error {
 	if seq > m.highestSeq {
 		return &qerr.TransportError{
@@ -150,6 +178,107 @@ func (m *connIDGenerator) issueNewConnID() error {
 	return nil
 }
 
+// SetMaxPathID issues connection IDs for all paths up to (and including) maxPathID.
+// It is called once multipath was negotiated, and when the limit is increased later.
+func (m *connIDGenerator) SetMaxPathID(maxPathID protocol.PathID) error {
+	if m.pathConnIDs == nil {
+		m.pathConnIDs = make(map[protocol.PathID]*pathConnIDs)
+	}
+	for id := m.maxPathID + 1; id <= maxPathID; id++ {
+		p := &pathConnIDs{active: make(map[uint64]protocol.ConnectionID)}
+		m.pathConnIDs[id] = p
+		for i := 0; i < protocol.MaxIssuedPathConnectionIDs; i++ {
+			if err := m.issueNewPathConnID(id, p); err != nil {
+				return err
+			}
+		}
+	}
+	m.maxPathID = max(m.maxPathID, maxPathID)
+	return nil
+}
+
+func (m *connIDGenerator) issueNewPathConnID(id protocol.PathID, p *pathConnIDs) error {
+	connID, err := m.generator.GenerateConnectionID()
+	if err != nil {
+		return err
+	}
+	seq := p.nextSeq
+	p.nextSeq++
+	p.active[seq] = connID
+	m.connRunners.AddConnectionID(connID)
+	m.queueControlFrame(&wire.PathNewConnectionIDFrame{
+		PathID:              id,
+		SequenceNumber:      seq,
+		ConnectionID:        connID,
+		StatelessResetToken: m.statelessResetter.GetStatelessResetToken(connID),
+	})
+	return nil
+}
+
+// RetirePathConnID handles a PATH_RETIRE_CONNECTION_ID frame.
+func (m *connIDGenerator) RetirePathConnID(id protocol.PathID, seq uint64, sentWithDestConnID protocol.ConnectionID, expiry monotime.Time) error {
+	if id == 0 {
+		return m.Retire(seq, sentWithDestConnID, expiry)
+	}
+	p, ok := m.pathConnIDs[id]
+	if !ok {
+		if id > m.maxPathID {
+			return &qerr.TransportError{
+				ErrorCode:    qerr.ProtocolViolation,
+				ErrorMessage: fmt.Sprintf("retired connection ID for path %d (maximum path ID: %d)", id, m.maxPathID),
+			}
+		}
+		// the path was already removed
+		return nil
+	}
+	if seq >= p.nextSeq {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			ErrorMessage: fmt.Sprintf("retired connection ID %d for path %d (highest issued: %d)", seq, id, p.nextSeq-1),
+		}
+	}
+	connID, ok := p.active[seq]
+	// We might already have deleted this connection ID, if this is a duplicate frame.
+	if !ok {
+		return nil
+	}
+	if connID == sentWithDestConnID {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			ErrorMessage: fmt.Sprintf("retired connection ID %d (%s) for path %d, which was used as the Destination Connection ID on this packet", seq, connID, id),
+		}
+	}
+	m.queueConnIDForRetiring(connID, expiry)
+	delete(p.active, seq)
+	return m.issueNewPathConnID(id, p)
+}
+
+// PathIDForConnID returns the ID of the path that a connection ID was issued for.
+// It only returns true for paths other than the initial path.
+func (m *connIDGenerator) PathIDForConnID(connID protocol.ConnectionID) (protocol.PathID, bool) {
+	for id, p := range m.pathConnIDs {
+		for _, c := range p.active {
+			if c == connID {
+				return id, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// RemovePath retires all connection IDs issued for a path.
+// This happens when the path is abandoned.
+func (m *connIDGenerator) RemovePath(id protocol.PathID, expiry monotime.Time) {
+	p, ok := m.pathConnIDs[id]
+	if !ok {
+		return
+	}
+	for _, connID := range p.active {
+		m.queueConnIDForRetiring(connID, expiry)
+	}
+	delete(m.pathConnIDs, id)
+}
+
 func (m *connIDGenerator) SetHandshakeComplete(connIDExpiry monotime.Time) {
 	if m.initialClientDestConnID != nil {
 		m.queueConnIDForRetiring(*m.initialClientDestConnID, connIDExpiry)
@@ -184,6 +313,11 @@ func (m *connIDGenerator) RemoveAll() {
 	for _, connID := range m.activeSrcConnIDs {
 		m.connRunners.RemoveConnectionID(connID)
 	}
+	for _, p := range m.pathConnIDs {
+		for _, connID := range p.active {
+			m.connRunners.RemoveConnectionID(connID)
+		}
+	}
 	for _, c := range m.connIDsToRetire {
 		m.connRunners.RemoveConnectionID(c.connID)
 	}
@@ -197,6 +331,11 @@ func (m *connIDGenerator) ReplaceWithClosed(connClose []byte, expiry time.Durati
 	for _, connID := range m.activeSrcConnIDs {
 		connIDs = append(connIDs, connID)
 	}
+	for _, p := range m.pathConnIDs {
+		for _, connID := range p.active {
+			connIDs = append(connIDs, connID)
+		}
+	}
 	for _, c := range m.connIDsToRetire {
 		connIDs = append(connIDs, c.connID)
 	}
@@ -216,4 +355,9 @@ func (m *connIDGenerator) AddConnRunner(runner connRunner, r connRunnerCallbacks
 	for _, connID := range m.activeSrcConnIDs {
 		r.AddConnectionID(connID)
 	}
+	for _, p := range m.pathConnIDs {
+		for _, connID := range p.active {
+			r.AddConnectionID(connID)
+		}
+	}
 }
@@ -356,3 +356,137 @@ func TestConnIDGeneratorAddConnRunner(t *testing.T) {
 	require.NotEmpty(t, tracker1.removed)
 	require.Equal(t, tracker1.removed, tracker2.removed)
 }
+
+func TestConnIDGeneratorPreferredAddress(t *testing.T) {
+	var added1, added2, removed1, removed2 []protocol.ConnectionID
+	var queuedFrames []wire.Frame
+	sr := newStatelessResetter(&StatelessResetKey{1, 2, 3, 4})
+	initialConnID := protocol.ParseConnectionID([]byte{1, 1, 1, 1})
+	clientDestConnID := protocol.ParseConnectionID([]byte{2, 2, 2, 2})
+	g := newConnIDGenerator(
+		&packetHandlerMap{},
+		initialConnID,
+		&clientDestConnID,
+		sr,
+		connRunnerCallbacks{
+			AddConnectionID:    func(c protocol.ConnectionID) { added1 = append(added1, c) },
+			RemoveConnectionID: func(c protocol.ConnectionID) { removed1 = append(removed1, c) },
+			ReplaceWithClosed:  func([]protocol.ConnectionID, []byte, time.Duration) {},
+		},
+		func(f wire.Frame) { queuedFrames = append(queuedFrames, f) },
+		&protocol.DefaultConnectionIDGenerator{ConnLen: 5},
+	)
+
+	connID, token, err := g.AddPreferredAddressConnID(
+		&packetHandlerMap{},
+		connRunnerCallbacks{
+			AddConnectionID:    func(c protocol.ConnectionID) { added2 = append(added2, c) },
+			RemoveConnectionID: func(c protocol.ConnectionID) { removed2 = append(removed2, c) },
+			ReplaceWithClosed:  func([]protocol.ConnectionID, []byte, time.Duration) {},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 5, connID.Len())
+	require.Equal(t, sr.GetStatelessResetToken(connID), token)
+	// the connection ID is not sent in a NEW_CONNECTION_ID frame
+	require.Empty(t, queuedFrames)
+	require.Equal(t, []protocol.ConnectionID{connID}, added1)
+	// the Transport of the preferred address accepts packets for all connection IDs
+	require.ElementsMatch(t, []protocol.ConnectionID{initialConnID, clientDestConnID, connID}, added2)
+
+	// the preferred address connection ID counts towards the active_connection_id_limit
+	require.NoError(t, g.SetMaxActiveConnIDs(4))
+	require.Len(t, queuedFrames, 2)
+	for i, f := range queuedFrames {
+		ncid := f.(*wire.NewConnectionIDFrame)
+		require.EqualValues(t, i+2, ncid.SequenceNumber)
+		require.Contains(t, added2, ncid.ConnectionID)
+	}
+
+	// the preferred address connection ID can be retired
+	queuedFrames = queuedFrames[:0]
+	now := monotime.Now()
+	require.NoError(t, g.Retire(1, initialConnID, now))
+	require.Len(t, queuedFrames, 1)
+	require.EqualValues(t, 4, queuedFrames[0].(*wire.NewConnectionIDFrame).SequenceNumber)
+	g.RemoveRetiredConnIDs(now)
+	require.Equal(t, []protocol.ConnectionID{connID}, removed1)
+	require.Equal(t, []protocol.ConnectionID{connID}, removed2)
+
+	// the preferred address connection ID can only be issued as the first connection ID
+	_, _, err = g.AddPreferredAddressConnID(&packetHandlerMap{}, connRunnerCallbacks{})
+	require.Error(t, err)
+}
+
+func TestConnIDGeneratorMultipath(t *testing.T) {
+	var added, removed []protocol.ConnectionID
+	var queuedFrames []wire.Frame
+	sr := newStatelessResetter(&StatelessResetKey{1, 2, 3, 4})
+	g := newConnIDGenerator(
+		&packetHandlerMap{},
+		protocol.ParseConnectionID([]byte{1, 1, 1, 1}),
+		nil,
+		sr,
+		connRunnerCallbacks{
+			AddConnectionID:    func(c protocol.ConnectionID) { added = append(added, c) },
+			RemoveConnectionID: func(c protocol.ConnectionID) { removed = append(removed, c) },
+			ReplaceWithClosed:  func([]protocol.ConnectionID, []byte, time.Duration) {},
+		},
+		func(f wire.Frame) { queuedFrames = append(queuedFrames, f) },
+		&protocol.DefaultConnectionIDGenerator{ConnLen: 5},
+	)
+
+	require.NoError(t, g.SetMaxPathID(2))
+	require.Len(t, queuedFrames, 2*protocol.MaxIssuedPathConnectionIDs)
+	require.Len(t, added, 2*protocol.MaxIssuedPathConnectionIDs)
+	connIDs := make(map[protocol.PathID][]protocol.ConnectionID)
+	for i, f := range queuedFrames {
+		pncid := f.(*wire.PathNewConnectionIDFrame)
+		require.Equal(t, protocol.PathID(i/protocol.MaxIssuedPathConnectionIDs+1), pncid.PathID)
+		require.EqualValues(t, i%protocol.MaxIssuedPathConnectionIDs, pncid.SequenceNumber)
+		require.Equal(t, sr.GetStatelessResetToken(pncid.ConnectionID), pncid.StatelessResetToken)
+		connIDs[pncid.PathID] = append(connIDs[pncid.PathID], pncid.ConnectionID)
+
+		id, ok := g.PathIDForConnID(pncid.ConnectionID)
+		require.True(t, ok)
+		require.Equal(t, pncid.PathID, id)
+	}
+	_, ok := g.PathIDForConnID(protocol.ParseConnectionID([]byte{1, 1, 1, 1}))
+	require.False(t, ok)
+
+	// increasing the maximum path ID issues connection IDs for the new paths
+	queuedFrames = queuedFrames[:0]
+	require.NoError(t, g.SetMaxPathID(3))
+	require.Len(t, queuedFrames, protocol.MaxIssuedPathConnectionIDs)
+	require.Equal(t, protocol.PathID(3), queuedFrames[0].(*wire.PathNewConnectionIDFrame).PathID)
+	queuedFrames = queuedFrames[:0]
+
+	// it's invalid to retire connection IDs that haven't been issued yet
+	now := monotime.Now()
+	err := g.RetirePathConnID(1, protocol.MaxIssuedPathConnectionIDs, protocol.ConnectionID{}, now)
+	require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.ProtocolViolation})
+	err = g.RetirePathConnID(4, 0, protocol.ConnectionID{}, now)
+	require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.ProtocolViolation})
+	// it's invalid to retire the connection ID used on the packet
+	err = g.RetirePathConnID(1, 0, connIDs[1][0], now)
+	require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.ProtocolViolation})
+
+	// retiring a connection ID makes us issue a new one for the same path
+	require.NoError(t, g.RetirePathConnID(1, 0, connIDs[1][1], now))
+	require.Len(t, queuedFrames, 1)
+	pncid := queuedFrames[0].(*wire.PathNewConnectionIDFrame)
+	require.Equal(t, protocol.PathID(1), pncid.PathID)
+	require.EqualValues(t, protocol.MaxIssuedPathConnectionIDs, pncid.SequenceNumber)
+	g.RemoveRetiredConnIDs(now)
+	require.Equal(t, []protocol.ConnectionID{connIDs[1][0]}, removed)
+	removed = removed[:0]
+
+	// removing a path retires all of its connection IDs
+	g.RemovePath(2, now)
+	g.RemoveRetiredConnIDs(now)
+	require.ElementsMatch(t, connIDs[2], removed)
+	_, ok = g.PathIDForConnID(connIDs[2][0])
+	require.False(t, ok)
+	// connection IDs for removed paths can't be retired anymore
+	require.NoError(t, g.RetirePathConnID(2, 1, protocol.ConnectionID{}, now))
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"reflect"
 	"slices"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/quic-go/quic-go/internal/ackhandler"
+	internalcongestion "github.com/quic-go/quic-go/internal/congestion"
 	"github.com/quic-go/quic-go/internal/flowcontrol"
 	"github.com/quic-go/quic-go/internal/handshake"
 	"github.com/quic-go/quic-go/internal/monotime"
@@ -37,12 +39,17 @@ type cryptoStreamHandler interface {
 	ChangeConnectionID(protocol.ConnectionID)
 	SetLargest1RTTAcked(protocol.PacketNumber) error
 	SetHandshakeConfirmed()
+	InitiateKeyUpdate()
+	KeyUpdatePending() bool
+	NumKeyUpdates() uint64
 	GetSessionTicket() ([]byte, error)
 	NextEvent() handshake.Event
 	DiscardInitialKeys()
 	HandleMessage([]byte, protocol.EncryptionLevel) error
 	io.Closer
 	ConnectionState() handshake.ConnectionState
+	Get1RTTOpenerForPath(protocol.PathID) (handshake.ShortHeaderOpener, error)
+	Get1RTTSealerForPath(protocol.PathID) (handshake.ShortHeaderSealer, error)
 }
 
 type receivedPacket struct {
@@ -55,6 +62,8 @@ type receivedPacket struct {
 	ecn protocol.ECN
 
 	info packetInfo // only valid if the contained IP address is valid
+
+	toPreferredAddress bool // was received on the Transport of the server's preferred address
 }
 
 func (p *receivedPacket) Size() protocol.ByteCount { return protocol.ByteCount(len(p.data)) }
@@ -67,6 +76,8 @@ func (p *receivedPacket) Clone() *receivedPacket {
 		buffer:     p.buffer,
 		ecn:        p.ecn,
 		info:       p.info,
+
+		toPreferredAddress: p.toPreferredAddress,
 	}
 }
 
@@ -134,6 +145,9 @@ type Conn struct {
 	version     protocol.Version
 	config      *Config
 
+	// connMx protects conn, which is replaced when migrating to a new path.
+	// It only needs to be held when accessing conn outside of the run loop.
+	connMx    sync.Mutex
 	conn      sendConn
 	sendQueue sender
 
@@ -141,6 +155,18 @@ type Conn struct {
 	pathManager         *pathManager
 	largestRcvdAppData  protocol.PacketNumber
 	pathManagerOutgoing atomic.Pointer[pathManagerOutgoing]
+	// set on the server when the client migrated to the server's preferred address
+	onPreferredAddress bool
+
+	// only set if the multipath extension was negotiated
+	multipath           *multipathManager
+	multipathNegotiated atomic.Bool
+	pathScheduler       atomic.Pointer[PathScheduler]
+
+	// only set if the ACK frequency extension was negotiated
+	ackFrequencyManager *ackFrequencyManager
+	// the last ACK_FREQUENCY frame received from the peer
+	peerAckFrequency *wire.AckFrequencyFrame
 
 	streamsMap      *streamsMap
 	connIDManager   *connIDManager
@@ -261,6 +287,7 @@ var newConnection = func(
 	tokenGenerator *handshake.TokenGenerator,
 	clientAddressValidated bool,
 	rtt time.Duration,
+	initialBandwidthEstimate internalcongestion.Bandwidth,
 	qlogTrace qlogwriter.Trace,
 	logger utils.Logger,
 	v protocol.Version,
@@ -306,15 +333,25 @@ var newConnection = func(
 		s.queueControlFrame,
 		connIDGenerator,
 	)
+	var preferredAddress *wire.PreferredAddress
+	if conf.PreferredAddress != nil {
+		var err error
+		preferredAddress, err = s.addPreferredAddress(conf.PreferredAddress)
+		if err != nil {
+			logger.Errorf("Not sending preferred_address: %s", err)
+		}
+	}
 	s.preSetup()
 	s.rttStats.SetInitialRTT(rtt)
 	s.sentPacketHandler, s.receivedPacketHandler = ackhandler.NewAckHandler(
 		0,
 		protocol.ByteCount(s.config.InitialPacketSize),
+		initialBandwidthEstimate,
 		s.rttStats,
 		&s.connStats,
 		clientAddressValidated,
 		s.conn.capabilities().ECN,
+		s.config.congestionControl(),
 		s.perspective,
 		s.qlogger,
 		s.logger,
@@ -342,6 +379,7 @@ var newConnection = func(
 		ActiveConnectionIDLimit:   protocol.MaxActiveConnectionIDs,
 		InitialSourceConnectionID: srcConnID,
 		RetrySourceConnectionID:   retrySrcConnID,
+		PreferredAddress:          preferredAddress,
 		EnableResetStreamAt:       conf.EnableStreamResetPartialDelivery,
 	}
 	if s.config.EnableDatagrams {
@@ -349,6 +387,16 @@ var newConnection = func(
 	} else {
 		params.MaxDatagramFrameSize = protocol.InvalidByteCount
 	}
+	// Multipath requires the use of non-zero-length connection IDs.
+	if s.config.EnableMultipath && srcConnID.Len() > 0 {
+		maxPathID := protocol.PathID(protocol.MaxMultipathPathID)
+		params.InitialMaxPathID = &maxPathID
+	}
+	if s.config.EnableAckFrequency {
+		minAckDelay := protocol.MinAckDelay
+		params.MinAckDelay = &minAckDelay
+	}
+	params.AdditionalParameters = maps.Clone(s.config.AdditionalTransportParameters)
 	if s.qlogger != nil {
 		s.qlogTransportParameters(params, protocol.PerspectiveServer, false)
 	}
@@ -359,11 +407,13 @@ var newConnection = func(
 		params,
 		tlsConf,
 		conf.Allow0RTT,
+		conf.AntiReplay,
 		s.rttStats,
 		s.qlogger,
 		logger,
 		s.version,
 	)
+	cs.SetKeyUpdateIntervals(conf.KeyUpdatePacketInterval, conf.KeyUpdateTimeInterval)
 	s.cryptoStreamHandler = cs
 	s.packer = newPacketPacker(srcConnID, s.connIDManager.Get, s.initialStream, s.handshakeStream, s.sentPacketHandler, s.retransmissionQueue, cs, s.framer, s.receivedPacketHandler, s.datagramQueue, s.perspective)
 	s.unpacker = newPacketUnpacker(cs, s.srcConnIDLen)
@@ -382,6 +432,8 @@ var newClientConnection = func(
 	statelessResetter *statelessResetter,
 	conf *Config,
 	tlsConf *tls.Config,
+	initialRTT time.Duration,
+	initialBandwidthEstimate internalcongestion.Bandwidth,
 	initialPacketNumber protocol.PacketNumber,
 	enable0RTT bool,
 	hasNegotiatedVersion bool,
@@ -436,13 +488,16 @@ var newClientConnection = func(
 	)
 	s.ctx, s.ctxCancel = context.WithCancelCause(ctx)
 	s.preSetup()
+	s.rttStats.SetInitialRTT(initialRTT)
 	s.sentPacketHandler, s.receivedPacketHandler = ackhandler.NewAckHandler(
 		initialPacketNumber,
 		protocol.ByteCount(s.config.InitialPacketSize),
+		initialBandwidthEstimate,
 		s.rttStats,
 		&s.connStats,
 		false, // has no effect
 		s.conn.capabilities().ECN,
+		s.config.congestionControl(),
 		s.perspective,
 		s.qlogger,
 		s.logger,
@@ -474,6 +529,16 @@ var newClientConnection = func(
 	} else {
 		params.MaxDatagramFrameSize = protocol.InvalidByteCount
 	}
+	// Multipath requires the use of non-zero-length connection IDs.
+	if s.config.EnableMultipath && srcConnID.Len() > 0 {
+		maxPathID := protocol.PathID(protocol.MaxMultipathPathID)
+		params.InitialMaxPathID = &maxPathID
+	}
+	if s.config.EnableAckFrequency {
+		minAckDelay := protocol.MinAckDelay
+		params.MinAckDelay = &minAckDelay
+	}
+	params.AdditionalParameters = maps.Clone(s.config.AdditionalTransportParameters)
 	if s.qlogger != nil {
 		s.qlogTransportParameters(params, protocol.PerspectiveClient, false)
 	}
@@ -487,6 +552,7 @@ var newClientConnection = func(
 		logger,
 		s.version,
 	)
+	cs.SetKeyUpdateIntervals(conf.KeyUpdatePacketInterval, conf.KeyUpdateTimeInterval)
 	s.cryptoStreamHandler = cs
 	s.cryptoStreamManager = newCryptoStreamManager(s.initialStream, s.handshakeStream, oneRTTStream)
 	s.unpacker = newPacketUnpacker(cs, s.srcConnIDLen)
@@ -514,7 +580,8 @@ func (c *Conn) preSetup() {
 	c.frameParser = *wire.NewFrameParser(
 		c.config.EnableDatagrams,
 		c.config.EnableStreamResetPartialDelivery,
-		false, // ACK_FREQUENCY is not supported yet
+		c.config.EnableAckFrequency,
+		c.config.EnableMultipath,
 	)
 	c.rttStats = utils.NewRTTStats()
 	c.connFlowController = flowcontrol.NewConnectionFlowController(
@@ -549,8 +616,9 @@ func (c *Conn) preSetup() {
 	now := monotime.Now()
 	c.lastPacketReceivedTime = now
 	c.creationTime = now
+	c.connStats.HandshakeStartTime.Store(int64(now))
 
-	c.datagramQueue = newDatagramQueue(c.scheduleSending, c.logger)
+	c.datagramQueue = newDatagramQueue(c.scheduleSending, c.config, c.logger)
 	c.connState.Version = c.version
 }
 
@@ -676,6 +744,12 @@ runLoop:
 				break runLoop
 			}
 		}
+		if c.multipath != nil {
+			if err := c.onMultipathLossDetectionTimeouts(now); err != nil {
+				c.setCloseError(&closeError{err: err})
+				break runLoop
+			}
+		}
 
 		if keepAliveTime := c.nextKeepAliveTime(); !keepAliveTime.IsZero() && !now.Before(keepAliveTime) {
 			// send a PING frame since there is no activity in the connection
@@ -699,9 +773,10 @@ runLoop:
 		if c.perspective == protocol.PerspectiveClient {
 			pm := c.pathManagerOutgoing.Load()
 			if pm != nil {
-				tr, ok := pm.ShouldSwitchPath()
-				if ok {
-					c.switchToNewPath(tr, now)
+				if c.multipath != nil {
+					c.handleMultipathPathUpdates(pm, now)
+				} else if _, tr, remoteAddr, ok := pm.ShouldSwitchPath(); ok {
+					c.switchToNewPath(tr, remoteAddr, now)
 				}
 			}
 		}
@@ -773,9 +848,36 @@ func (c *Conn) ConnectionState() ConnectionState {
 	c.connState.Used0RTT = cs.Used0RTT
 	c.connState.SupportsStreamResetPartialDelivery = c.peerParams.EnableResetStreamAt
 	c.connState.GSO = c.conn.capabilities().GSO
-	return c.connState
+	state := c.connState
+	// On the client side, the map is shared with the transport parameters restored for 0-RTT.
+	// Return a copy, so that callers can't modify the connection's state.
+	if params := c.connState.AdditionalTransportParameters; params != nil {
+		state.AdditionalTransportParameters = make(map[uint64][]byte, len(params))
+		for id, val := range params {
+			state.AdditionalTransportParameters[id] = slices.Clone(val)
+		}
+	}
+	return state
 }
 
+// ECNState is the state of ECN validation of the path, see section 13.4.2 of RFC 9000.
+type ECNState = protocol.ECNState
+
+const (
+	// ECNStateDisabled means that ECN is not used,
+	// either because it was disabled, or because it is not supported on this platform.
+	ECNStateDisabled = protocol.ECNStateDisabled
+	// ECNStateTesting means that ECN validation is in progress.
+	ECNStateTesting = protocol.ECNStateTesting
+	// ECNStateUnknown means that all ECN testing packets were sent,
+	// but the ECN capability of the path hasn't been confirmed yet.
+	ECNStateUnknown = protocol.ECNStateUnknown
+	// ECNStateCapable means that the path supports ECN.
+	ECNStateCapable = protocol.ECNStateCapable
+	// ECNStateFailed means that ECN validation failed.
+	ECNStateFailed = protocol.ECNStateFailed
+)
+
 // ConnectionStats contains statistics about the QUIC connection
 type ConnectionStats struct {
 	// MinRTT is the estimate of the minimum RTT observed on the active network
@@ -813,9 +915,83 @@ type ConnectionStats struct {
 	// (does not monotonically increase, because packets that are declared lost
 	// can subsequently be received).
 	PacketsLost uint64
+
+	// DatagramsSent is the number of DATAGRAM frames sent.
+	DatagramsSent uint64
+	// DatagramsReceived is the number of DATAGRAM frames received,
+	// including those that were dropped because the receive queue was full.
+	DatagramsReceived uint64
+	// DatagramsDroppedSend is the number of datagrams that were dropped before being sent,
+	// because the send queue was full, their deadline expired, or the maximum packet size decreased.
+	DatagramsDroppedSend uint64
+	// DatagramsDroppedReceive is the number of received datagrams that were dropped
+	// because the receive queue was full.
+	DatagramsDroppedReceive uint64
+	// DatagramsTooLarge is the number of datagrams that were rejected by SendDatagram
+	// because their payload exceeded the maximum datagram payload size.
+	DatagramsTooLarge uint64
+
+	// CongestionWindow is the current congestion window, in bytes.
+	// For multipath connections, CongestionWindow, BytesInFlight, SlowStartThreshold,
+	// PacingRate, MTU and ECNState are the values of the path used during the handshake.
+	CongestionWindow uint64
+	// BytesInFlight is the number of bytes sent in ack-eliciting packets
+	// that have neither been acknowledged nor declared lost.
+	BytesInFlight uint64
+	// SlowStartThreshold is the current slow start threshold, in bytes.
+	// It is 0 if the congestion controller hasn't set a threshold (yet),
+	// or if it doesn't use a slow start threshold at all.
+	SlowStartThreshold uint64
+	// PacingRate is the rate at which packets are paced out, in bits per second.
+	PacingRate uint64
+	// PTOCount is the number of times the Probe Timeout (PTO) fired.
+	// See https://www.rfc-editor.org/rfc/rfc9002#section-6.2
+	PTOCount uint64
+	// MTU is the maximum size of QUIC packets sent on the active network path.
+	// It increases as Path MTU Discovery finds larger packet sizes.
+	// Does not include UDP or any other outer framing.
+	MTU uint64
+
+	// ECNState is the state of ECN validation of the active network path.
+	ECNState ECNState
+	// ECNCEReported is the number of packets that the peer reported as received with
+	// the ECN Congestion Experienced (CE) codepoint set.
+	ECNCEReported uint64
+	// ECNCEReceived is the number of packets received with the ECN Congestion Experienced (CE)
+	// codepoint set.
+	ECNCEReceived uint64
+
+	// StreamDataBlockedSent is the number of times a stream was blocked by stream-level flow control.
+	// This is the number of STREAM_DATA_BLOCKED frames sent.
+	StreamDataBlockedSent uint64
+	// DataBlockedSent is the number of times the connection was blocked by connection-level flow control.
+	// This is the number of DATA_BLOCKED frames sent.
+	DataBlockedSent uint64
+	// StreamDataBlockedReceived is the number of STREAM_DATA_BLOCKED frames received,
+	// i.e. how often the peer was blocked by stream-level flow control.
+	StreamDataBlockedReceived uint64
+	// DataBlockedReceived is the number of DATA_BLOCKED frames received,
+	// i.e. how often the peer was blocked by connection-level flow control.
+	DataBlockedReceived uint64
+
+	// KeyUpdates is the number of completed 1-RTT key updates, initiated by either endpoint.
+	// A key update is completed once both endpoints use the new keys.
+	KeyUpdates uint64
+
+	// HandshakeStartTime is the time when the handshake was started.
+	HandshakeStartTime time.Time
+	// HandshakeCompleteTime is the time when the handshake completed.
+	// It is the zero value if the handshake hasn't completed yet.
+	HandshakeCompleteTime time.Time
+	// HandshakeConfirmedTime is the time when the handshake was confirmed,
+	// see https://www.rfc-editor.org/rfc/rfc9001#section-4.1.2.
+	// It is the zero value if the handshake hasn't been confirmed yet.
+	HandshakeConfirmedTime time.Time
 }
 
 func (c *Conn) ConnectionStats() ConnectionStats {
+	datagramStats := c.datagramQueue.Stats()
+	streamDataBlocked, dataBlocked := c.framer.NumBlockedFrames()
 	return ConnectionStats{
 		MinRTT:        c.rttStats.MinRTT(),
 		LatestRTT:     c.rttStats.LatestRTT(),
@@ -828,7 +1004,48 @@ func (c *Conn) ConnectionStats() ConnectionStats {
 		PacketsReceived: c.connStats.PacketsReceived.Load(),
 		BytesLost:       c.connStats.BytesLost.Load(),
 		PacketsLost:     c.connStats.PacketsLost.Load(),
+
+		DatagramsSent:           datagramStats.Sent,
+		DatagramsReceived:       datagramStats.Received,
+		DatagramsDroppedSend:    datagramStats.SendDropped,
+		DatagramsDroppedReceive: datagramStats.ReceiveDropped,
+		DatagramsTooLarge:       datagramStats.TooLarge,
+
+		CongestionWindow:   c.connStats.CongestionWindow.Load(),
+		BytesInFlight:      c.connStats.BytesInFlight.Load(),
+		SlowStartThreshold: c.connStats.SlowStartThreshold.Load(),
+		PacingRate:         c.connStats.PacingRate.Load(),
+		PTOCount:           c.connStats.PTOCount.Load(),
+		MTU:                c.connStats.MaxPacketSize.Load(),
+
+		ECNState:      ECNState(c.connStats.ECNState.Load()),
+		ECNCEReported: c.connStats.ECNCEReported.Load(),
+		ECNCEReceived: c.connStats.ECNCEReceived.Load(),
+
+		StreamDataBlockedSent:     streamDataBlocked,
+		DataBlockedSent:           dataBlocked,
+		StreamDataBlockedReceived: c.connStats.StreamDataBlockedReceived.Load(),
+		DataBlockedReceived:       c.connStats.DataBlockedReceived.Load(),
+
+		KeyUpdates: c.cryptoStreamHandler.NumKeyUpdates(),
+
+		HandshakeStartTime:     monotime.Time(c.connStats.HandshakeStartTime.Load()).ToTime(),
+		HandshakeCompleteTime:  monotime.Time(c.connStats.HandshakeCompleteTime.Load()).ToTime(),
+		HandshakeConfirmedTime: monotime.Time(c.connStats.HandshakeConfirmedTime.Load()).ToTime(),
+	}
+}
+
+// savePathMetrics saves the metrics of the connection's current path to the cache.
+// It must only be called after the run loop has returned.
+func (c *Conn) savePathMetrics(cache PathMetricsCache) {
+	if cache == nil || !c.rttStats.HasMeasurement() {
+		return
 	}
+	cache.Put(c.conn.RemoteAddr(), PathMetrics{
+		SmoothedRTT:       c.rttStats.SmoothedRTT(),
+		MinRTT:            c.rttStats.MinRTT(),
+		BandwidthEstimate: c.connStats.BandwidthEstimate.Load(),
+	})
 }
 
 // Time when the connection should time out
@@ -880,6 +1097,11 @@ func (c *Conn) maybeResetTimer() {
 	if t := c.sentPacketHandler.GetLossDetectionTimeout(); !t.IsZero() && t.Before(deadline) {
 		deadline = t
 	}
+	if c.multipath != nil {
+		if t := c.nextMultipathTimeout(); !t.IsZero() && t.Before(deadline) {
+			deadline = t
+		}
+	}
 	if c.blocked == blockModeCongestionLimited {
 		c.timer.Reset(monotime.Until(deadline))
 		return
@@ -902,7 +1124,7 @@ func (c *Conn) idleTimeoutStartTime() monotime.Time {
 	return startTime
 }
 
-func (c *Conn) switchToNewPath(tr *Transport, now monotime.Time) {
+func (c *Conn) switchToNewPath(tr *Transport, remoteAddr net.Addr, now monotime.Time) {
 	initialPacketSize := protocol.ByteCount(c.config.InitialPacketSize)
 	c.sentPacketHandler.MigratedPath(now, initialPacketSize)
 	maxPacketSize := protocol.ByteCount(protocol.MaxPacketBufferSize)
@@ -910,7 +1132,30 @@ func (c *Conn) switchToNewPath(tr *Transport, now monotime.Time) {
 		maxPacketSize = c.peerParams.MaxUDPPayloadSize
 	}
 	c.mtuDiscoverer.Reset(now, initialPacketSize, maxPacketSize)
-	c.conn = newSendConn(tr.conn, c.conn.RemoteAddr(), packetInfo{}, utils.DefaultLogger) // TODO: find a better way
+	if remoteAddr == nil {
+		remoteAddr = c.conn.RemoteAddr()
+	}
+	if tr == nil {
+		c.conn.ChangeRemoteAddr(remoteAddr, packetInfo{})
+	} else {
+		c.replaceSendConn(newSendConn(tr.conn, remoteAddr, packetInfo{}, utils.DefaultLogger)) // TODO: find a better way
+	}
+	if c.qlogger != nil {
+		var remote qlog.PathEndpointInfo
+		if addr, ok := remoteAddr.(*net.UDPAddr); ok {
+			remote = toPathEndpointInfo(addr)
+		}
+		c.qlogger.RecordEvent(qlog.MigrationStateUpdated{
+			State:  qlog.MigrationStateMigrationComplete,
+			Remote: remote,
+		})
+	}
+}
+
+func (c *Conn) replaceSendConn(conn sendConn) {
+	c.connMx.Lock()
+	c.conn = conn
+	c.connMx.Unlock()
 	c.sendQueue.Close()
 	c.sendQueue = newSendQueue(c.conn)
 	go func() {
@@ -925,6 +1170,7 @@ func (c *Conn) handleHandshakeComplete(now monotime.Time) error {
 	// Once the handshake completes, we have derived 1-RTT keys.
 	// There's no point in queueing undecryptable packets for later decryption anymore.
 	c.undecryptablePackets = nil
+	c.connStats.HandshakeCompleteTime.Store(int64(now))
 
 	c.connIDManager.SetHandshakeComplete()
 	c.connIDGenerator.SetHandshakeComplete(now.Add(3 * c.rttStats.PTO(false)))
@@ -938,7 +1184,7 @@ func (c *Conn) handleHandshakeComplete(now monotime.Time) error {
 	// The server applies transport parameters right away, but the client side has to wait for handshake completion.
 	// During a 0-RTT connection, the client is only allowed to use the new transport parameters for 1-RTT packets.
 	if c.perspective == protocol.PerspectiveClient {
-		c.applyTransportParameters()
+		c.applyTransportParameters(now)
 		return nil
 	}
 
@@ -981,14 +1227,83 @@ func (c *Conn) handleHandshakeConfirmed(now monotime.Time) error {
 	}
 
 	c.handshakeConfirmed = true
+	c.connStats.HandshakeConfirmedTime.Store(int64(now))
 	c.cryptoStreamHandler.SetHandshakeConfirmed()
 
 	if !c.config.DisablePathMTUDiscovery && c.conn.capabilities().DF {
 		c.mtuDiscoverer.Start(now)
 	}
+	// The connection ID of the preferred address can only be used on the initial path,
+	// so we don't migrate if the multipath extension was negotiated.
+	if c.perspective == protocol.PerspectiveClient && !c.config.DisablePreferredAddressMigration && c.multipath == nil {
+		if pa := c.peerParams.PreferredAddress; pa != nil {
+			c.migrateToPreferredAddress(pa)
+		}
+	}
 	return nil
 }
 
+// migrateToPreferredAddress probes the path to the server's preferred address,
+// and switches to that path once it has been validated (see section 9.6 of RFC 9000).
+func (c *Conn) migrateToPreferredAddress(pa *wire.PreferredAddress) {
+	remoteAddr, ok := c.RemoteAddr().(*net.UDPAddr)
+	if !ok {
+		return
+	}
+	// Use the preferred address of the same address family as the current path.
+	addrPort := pa.IPv6
+	if remoteAddr.IP.To4() != nil {
+		addrPort = pa.IPv4
+	}
+	if !addrPort.IsValid() || addrPort.Addr().IsUnspecified() || addrPort.Port() == 0 {
+		return
+	}
+	newAddr := net.UDPAddrFromAddrPort(addrPort)
+	if addrsEqual(newAddr, remoteAddr) {
+		return
+	}
+
+	c.logger.Debugf("Probing path to the server's preferred address %s", newAddr)
+	path := c.getPathManager().NewPath(nil, newAddr, c.rttStats.PTO(true), func() {})
+	// Path validation is abandoned after three times the larger of the PTO of the current path
+	// and the PTO of the new path, see section 8.2.4 of RFC 9000.
+	// No RTT sample has been taken on the new path yet, so its PTO is derived from the configured initial RTT.
+	// The Transport's path metrics cache isn't accessible from the connection.
+	timeout := 3 * max(c.rttStats.PTO(true), initialPTOForPath(nil, newAddr, c.config))
+	if c.qlogger != nil {
+		c.qlogger.RecordEvent(qlog.MigrationStateUpdated{
+			State:  qlog.MigrationStateProbingStarted,
+			Remote: toPathEndpointInfo(newAddr),
+		})
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(c.ctx, timeout)
+		defer cancel()
+		if err := path.Probe(ctx); err != nil {
+			c.logger.Debugf("Probing the path to the preferred address failed: %s", err)
+			path.Close()
+			if c.qlogger != nil {
+				c.qlogger.RecordEvent(qlog.MigrationStateUpdated{
+					State:  qlog.MigrationStateProbingAbandoned,
+					Remote: toPathEndpointInfo(newAddr),
+				})
+			}
+			return
+		}
+		if c.qlogger != nil {
+			c.qlogger.RecordEvent(qlog.MigrationStateUpdated{
+				State:  qlog.MigrationStateProbingSuccessful,
+				Remote: toPathEndpointInfo(newAddr),
+			})
+		}
+		if err := path.Switch(); err != nil {
+			c.logger.Debugf("Switching to the path to the preferred address failed: %s", err)
+			return
+		}
+		c.scheduleSending()
+	}()
+}
+
 func (c *Conn) handlePackets() (wasProcessed bool, _ error) {
 	// Now process all packets in the receivedPackets channel.
 	// Limit the number of packets to the length of the receivedPackets channel,
@@ -1169,6 +1484,14 @@ func (c *Conn) handleShortHeaderPacket(p receivedPacket, isCoalesced bool) (wasP
 		})
 		return false, nil
 	}
+	// Packets for paths other than the initial path use connection IDs issued for that path.
+	if c.multipath != nil {
+		if id, ok := c.connIDGenerator.PathIDForConnID(destConnID); ok {
+			var wasProcessed bool
+			wasProcessed, wasQueued, err = c.handleMultipathPacket(p, id, destConnID)
+			return wasProcessed, err
+		}
+	}
 	pn, pnLen, keyPhase, data, err := c.unpacker.UnpackShortHeader(p.rcvTime, p.data)
 	if err != nil {
 		// Stateless reset packets (see RFC 9000, section 10.3):
@@ -1193,40 +1516,15 @@ func (c *Conn) handleShortHeaderPacket(p receivedPacket, isCoalesced bool) (wasP
 	}
 
 	if c.receivedPacketHandler.IsPotentiallyDuplicate(pn, protocol.Encryption1RTT) {
-		c.logger.Debugf("Dropping (potentially) duplicate packet.")
-		if c.qlogger != nil {
-			c.qlogger.RecordEvent(qlog.PacketDropped{
-				Header: qlog.PacketHeader{
-					PacketType:   qlog.PacketType1RTT,
-					PacketNumber: pn,
-				},
-				Raw:     qlog.RawInfo{Length: int(p.Size())},
-				Trigger: qlog.PacketDropDuplicate,
-			})
-		}
+		c.logDuplicateShortHeaderPacket(p, pn)
 		return false, nil
 	}
 
-	var log func([]qlog.Frame)
-	if c.qlogger != nil {
-		log = func(frames []qlog.Frame) {
-			c.qlogger.RecordEvent(qlog.PacketReceived{
-				Header: qlog.PacketHeader{
-					PacketType:       qlog.PacketType1RTT,
-					DestConnectionID: destConnID,
-					PacketNumber:     pn,
-					KeyPhaseBit:      keyPhase,
-				},
-				Raw: qlog.RawInfo{
-					Length:        int(p.Size()),
-					PayloadLength: int(p.Size() - wire.ShortHeaderLen(destConnID, pnLen)),
-				},
-				Frames: frames,
-				ECN:    toQlogECN(p.ecn),
-			})
-		}
-	}
-	isNonProbing, pathChallenge, err := c.handleUnpackedShortHeaderPacket(destConnID, pn, data, p.ecn, p.rcvTime, log)
+	isNonProbing, pathChallenge, err := c.handleUnpackedShortHeaderPacket(
+		destConnID, pn, data, p.ecn, p.rcvTime,
+		c.receivedPacketHandler,
+		c.shortHeaderPacketLogger(p, destConnID, pn, pnLen, keyPhase),
+	)
 	if err != nil {
 		return false, err
 	}
@@ -1235,7 +1533,11 @@ func (c *Conn) handleShortHeaderPacket(p receivedPacket, isCoalesced bool) (wasP
 	if c.perspective == protocol.PerspectiveClient {
 		return true, nil
 	}
-	if addrsEqual(p.remoteAddr, c.RemoteAddr()) {
+	if p.toPreferredAddress == c.onPreferredAddress && addrsEqual(p.remoteAddr, c.RemoteAddr()) {
+		return true, nil
+	}
+	// Once the client migrated to the preferred address, we don't migrate back to the original address.
+	if c.onPreferredAddress && !p.toPreferredAddress {
 		return true, nil
 	}
 
@@ -1247,7 +1549,7 @@ func (c *Conn) handleShortHeaderPacket(p receivedPacket, isCoalesced bool) (wasP
 			c.logger,
 		)
 	}
-	destConnID, frames, shouldSwitchPath := c.pathManager.HandlePacket(p.remoteAddr, p.rcvTime, pathChallenge, isNonProbing)
+	destConnID, frames, shouldSwitchPath := c.pathManager.HandlePacket(p.remoteAddr, p.toPreferredAddress, p.rcvTime, pathChallenge, isNonProbing)
 	if len(frames) > 0 {
 		probe, buf, err := c.packer.PackPathProbePacket(destConnID, frames, c.version)
 		if err != nil {
@@ -1256,14 +1558,19 @@ func (c *Conn) handleShortHeaderPacket(p receivedPacket, isCoalesced bool) (wasP
 		c.logger.Debugf("sending path probe packet to %s", p.remoteAddr)
 		c.logShortHeaderPacket(probe.DestConnID, probe.Ack, probe.Frames, probe.StreamFrames, probe.PacketNumber, probe.PacketNumberLen, probe.KeyPhase, protocol.ECNNon, buf.Len(), false)
 		c.registerPackedShortHeaderPacket(probe, protocol.ECNNon, p.rcvTime)
-		c.sendQueue.SendProbe(buf, p.remoteAddr)
+		if p.toPreferredAddress && !c.onPreferredAddress {
+			// Packets on the path to the preferred address need to be sent from the preferred address.
+			c.config.PreferredAddress.Transport.sendPathProbe(buf, p.remoteAddr)
+		} else {
+			c.sendQueue.SendProbe(buf, p.remoteAddr)
+		}
 	}
 	// We only switch paths in response to the highest-numbered non-probing packet,
 	// see section 9.3 of RFC 9000.
 	if !shouldSwitchPath || pn != c.largestRcvdAppData {
 		return true, nil
 	}
-	c.pathManager.SwitchToPath(p.remoteAddr)
+	c.pathManager.SwitchToPath(p.remoteAddr, p.toPreferredAddress)
 	c.sentPacketHandler.MigratedPath(p.rcvTime, protocol.ByteCount(c.config.InitialPacketSize))
 	maxPacketSize := protocol.ByteCount(protocol.MaxPacketBufferSize)
 	if c.peerParams.MaxUDPPayloadSize > 0 && c.peerParams.MaxUDPPayloadSize < maxPacketSize {
@@ -1274,10 +1581,60 @@ func (c *Conn) handleShortHeaderPacket(p receivedPacket, isCoalesced bool) (wasP
 		protocol.ByteCount(c.config.InitialPacketSize),
 		maxPacketSize,
 	)
+	if p.toPreferredAddress && !c.onPreferredAddress {
+		c.logger.Debugf("client migrated to the preferred address")
+		c.onPreferredAddress = true
+		c.replaceSendConn(newSendConn(c.config.PreferredAddress.Transport.conn, p.remoteAddr, p.info, c.logger))
+		return true, nil
+	}
 	c.conn.ChangeRemoteAddr(p.remoteAddr, p.info)
 	return true, nil
 }
 
+func (c *Conn) logDuplicateShortHeaderPacket(p receivedPacket, pn protocol.PacketNumber) {
+	c.logger.Debugf("Dropping (potentially) duplicate packet.")
+	if c.qlogger != nil {
+		c.qlogger.RecordEvent(qlog.PacketDropped{
+			Header: qlog.PacketHeader{
+				PacketType:   qlog.PacketType1RTT,
+				PacketNumber: pn,
+			},
+			Raw:     qlog.RawInfo{Length: int(p.Size())},
+			Trigger: qlog.PacketDropDuplicate,
+		})
+	}
+}
+
+// shortHeaderPacketLogger returns a function that logs the received packet to qlog.
+// It returns nil if qlog is disabled.
+func (c *Conn) shortHeaderPacketLogger(
+	p receivedPacket,
+	destConnID protocol.ConnectionID,
+	pn protocol.PacketNumber,
+	pnLen protocol.PacketNumberLen,
+	keyPhase protocol.KeyPhaseBit,
+) func([]qlog.Frame) {
+	if c.qlogger == nil {
+		return nil
+	}
+	return func(frames []qlog.Frame) {
+		c.qlogger.RecordEvent(qlog.PacketReceived{
+			Header: qlog.PacketHeader{
+				PacketType:       qlog.PacketType1RTT,
+				DestConnectionID: destConnID,
+				PacketNumber:     pn,
+				KeyPhaseBit:      keyPhase,
+			},
+			Raw: qlog.RawInfo{
+				Length:        int(p.Size()),
+				PayloadLength: int(p.Size() - wire.ShortHeaderLen(destConnID, pnLen)),
+			},
+			Frames: frames,
+			ECN:    toQlogECN(p.ecn),
+		})
+	}
+}
+
 func (c *Conn) handleLongHeaderPacket(p receivedPacket, hdr *wire.Header) (wasProcessed bool, _ error) {
 	var wasQueued bool
 
@@ -1704,6 +2061,9 @@ func (c *Conn) handleUnpackedLongHeaderPacket(
 	if err != nil {
 		return err
 	}
+	if ecn == protocol.ECNCE {
+		c.connStats.ECNCEReceived.Add(1)
+	}
 	return c.receivedPacketHandler.ReceivedPacket(packet.hdr.PacketNumber, ecn, packet.encryptionLevel, rcvTime, isAckEliciting)
 }
 
@@ -1713,6 +2073,7 @@ func (c *Conn) handleUnpackedShortHeaderPacket(
 	data []byte,
 	ecn protocol.ECN,
 	rcvTime monotime.Time,
+	rph ackhandler.ReceivedPacketHandler,
 	log func([]qlog.Frame),
 ) (isNonProbing bool, pathChallenge *wire.PathChallengeFrame, _ error) {
 	c.lastPacketReceivedTime = rcvTime
@@ -1723,7 +2084,10 @@ func (c *Conn) handleUnpackedShortHeaderPacket(
 	if err != nil {
 		return false, nil, err
 	}
-	if err := c.receivedPacketHandler.ReceivedPacket(pn, ecn, protocol.Encryption1RTT, rcvTime, isAckEliciting); err != nil {
+	if ecn == protocol.ECNCE {
+		c.connStats.ECNCEReceived.Add(1)
+	}
+	if err := rph.ReceivedPacket(pn, ecn, protocol.Encryption1RTT, rcvTime, isAckEliciting); err != nil {
 		return false, nil, err
 	}
 	return isNonProbing, pathChallenge, nil
@@ -1800,6 +2164,21 @@ func (c *Conn) handleFrames(
 			}
 			wire.LogFrame(c.logger, ackFrame, false)
 			handleErr = c.handleAckFrame(ackFrame, encLevel, rcvTime)
+		} else if frameType.IsPathAckFrameType() {
+			pathAckFrame, l, err := c.frameParser.ParsePathAckFrame(frameType, data, c.version)
+			if err != nil {
+				return false, false, nil, err
+			}
+			data = data[l:]
+			if log != nil {
+				frames = append(frames, toQlogFrame(pathAckFrame))
+			}
+			// an error occurred handling a previous frame, don't handle the current frame
+			if skipHandling {
+				continue
+			}
+			wire.LogFrame(c.logger, pathAckFrame, false)
+			handleErr = c.handlePathAckFrame(pathAckFrame, rcvTime)
 		} else if frameType.IsDatagramFrameType() {
 			datagramFrame, l, err := c.frameParser.ParseDatagramFrame(frameType, data, c.version)
 			if err != nil {
@@ -1883,18 +2262,20 @@ func (c *Conn) handleFrame(
 	case *wire.MaxDataFrame:
 		c.connFlowController.UpdateSendWindow(frame.MaximumData)
 	case *wire.MaxStreamDataFrame:
-		err = c.streamsMap.HandleMaxStreamDataFrame(frame)
+		err = c.streamsMap.HandleMaxStreamDataFrame(frame, rcvTime)
 	case *wire.MaxStreamsFrame:
 		c.streamsMap.HandleMaxStreamsFrame(frame)
 	case *wire.DataBlockedFrame:
+		c.connStats.DataBlockedReceived.Add(1)
 	case *wire.StreamDataBlockedFrame:
+		c.connStats.StreamDataBlockedReceived.Add(1)
 		err = c.streamsMap.HandleStreamDataBlockedFrame(frame)
 	case *wire.StreamsBlockedFrame:
 	case *wire.StopSendingFrame:
 		err = c.streamsMap.HandleStopSendingFrame(frame)
 	case *wire.PingFrame:
 	case *wire.PathChallengeFrame:
-		c.handlePathChallengeFrame(frame)
+		c.handlePathChallengeFrame(frame, destConnID)
 		pathChallenge = frame
 	case *wire.PathResponseFrame:
 		err = c.handlePathResponseFrame(frame)
@@ -1906,6 +2287,22 @@ func (c *Conn) handleFrame(
 		err = c.connIDGenerator.Retire(frame.SequenceNumber, destConnID, rcvTime.Add(3*c.rttStats.PTO(false)))
 	case *wire.HandshakeDoneFrame:
 		err = c.handleHandshakeDoneFrame(rcvTime)
+	case *wire.PathAbandonFrame:
+		err = c.handlePathAbandonFrame(frame, rcvTime)
+	case *wire.PathStatusFrame:
+		err = c.handlePathStatusFrame(frame)
+	case *wire.PathNewConnectionIDFrame:
+		err = c.handlePathNewConnectionIDFrame(frame)
+	case *wire.PathRetireConnectionIDFrame:
+		err = c.handlePathRetireConnectionIDFrame(frame, destConnID, rcvTime)
+	case *wire.MaxPathIDFrame:
+		err = c.handleMaxPathIDFrame(frame)
+	case *wire.PathsBlockedFrame:
+	case *wire.PathConnectionIDsBlockedFrame:
+	case *wire.AckFrequencyFrame:
+		err = c.handleAckFrequencyFrame(frame)
+	case *wire.ImmediateAckFrame:
+		c.handleImmediateAckFrame()
 	default:
 		err = fmt.Errorf("unexpected frame type: %s", reflect.ValueOf(&frame).Elem().Type().Name())
 	}
@@ -1980,9 +2377,9 @@ func (c *Conn) handleHandshakeEvents(now monotime.Time) error {
 			// It's advantageous to process ACK frames that might be serialized after the CRYPTO frame first.
 			c.handshakeComplete = true
 		case handshake.EventReceivedTransportParameters:
-			err = c.handleTransportParameters(ev.TransportParameters)
+			err = c.handleTransportParameters(ev.TransportParameters, now)
 		case handshake.EventRestoredTransportParameters:
-			c.restoreTransportParameters(ev.TransportParameters)
+			c.restoreTransportParameters(ev.TransportParameters, now)
 			close(c.earlyConnReadyChan)
 		case handshake.EventReceivedReadKeys:
 			// queue all previously undecryptable packets
@@ -2001,10 +2398,17 @@ func (c *Conn) handleHandshakeEvents(now monotime.Time) error {
 	}
 }
 
-func (c *Conn) handlePathChallengeFrame(f *wire.PathChallengeFrame) {
-	if c.perspective == protocol.PerspectiveClient {
-		c.queueControlFrame(&wire.PathResponseFrame{Data: f.Data})
+func (c *Conn) handlePathChallengeFrame(f *wire.PathChallengeFrame, destConnID protocol.ConnectionID) {
+	if c.perspective != protocol.PerspectiveClient {
+		return
+	}
+	// On multipath paths, the PATH_RESPONSE is sent on the path that the PATH_CHALLENGE was received on.
+	if c.multipath != nil {
+		if _, ok := c.connIDGenerator.PathIDForConnID(destConnID); ok {
+			return
+		}
 	}
+	c.queueControlFrame(&wire.PathResponseFrame{Data: f.Data})
 }
 
 func (c *Conn) handlePathResponseFrame(f *wire.PathResponseFrame) error {
@@ -2026,11 +2430,19 @@ func (c *Conn) handlePathResponseFrameClient(f *wire.PathResponseFrame) error {
 			ErrorMessage: "unexpected PATH_RESPONSE frame",
 		}
 	}
-	pm.HandlePathResponseFrame(f)
+	id, validated := pm.HandlePathResponseFrame(f)
+	if validated && c.multipath != nil {
+		if path, ok := c.multipath.paths[protocol.PathID(id)]; ok {
+			path.validated = true
+		}
+	}
 	return nil
 }
 
 func (c *Conn) handlePathResponseFrameServer(f *wire.PathResponseFrame) error {
+	if c.multipath != nil && c.handlePathResponseFrameMultipath(f) {
+		return nil
+	}
 	if c.pathManager == nil {
 		// since we didn't send PATH_CHALLENGEs yet, we don't expect PATH_RESPONSEs
 		return &qerr.TransportError{
@@ -2068,6 +2480,44 @@ func (c *Conn) handleHandshakeDoneFrame(rcvTime monotime.Time) error {
 	return nil
 }
 
+func (c *Conn) handleAckFrequencyFrame(frame *wire.AckFrequencyFrame) error {
+	if frame.RequestMaxAckDelay < protocol.MinAckDelay {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			ErrorMessage: fmt.Sprintf("requested max ack delay (%s) smaller than min_ack_delay", frame.RequestMaxAckDelay),
+		}
+	}
+	if c.peerAckFrequency != nil && frame.SequenceNumber <= c.peerAckFrequency.SequenceNumber {
+		return nil
+	}
+	c.peerAckFrequency = frame
+	c.receivedPacketHandler.HandleAckFrequencyFrame(frame)
+	if c.multipath != nil {
+		for _, path := range c.multipath.allPaths()[1:] {
+			path.receivedPacketHandler.HandleAckFrequencyFrame(frame)
+		}
+	}
+	return nil
+}
+
+func (c *Conn) handleImmediateAckFrame() {
+	c.receivedPacketHandler.HandleImmediateAckFrame()
+	if c.multipath != nil {
+		for _, path := range c.multipath.allPaths()[1:] {
+			path.receivedPacketHandler.HandleImmediateAckFrame()
+		}
+	}
+}
+
+// maybeQueueImmediateAck queues an IMMEDIATE_ACK frame, if the peer supports the ACK frequency extension.
+// It is used when sending PTO probe packets, to get feedback from the peer as quickly as possible.
+func (c *Conn) maybeQueueImmediateAck() {
+	if c.ackFrequencyManager == nil || !c.handshakeComplete {
+		return
+	}
+	c.queueControlFrame(&wire.ImmediateAckFrame{})
+}
+
 func (c *Conn) handleAckFrame(frame *wire.AckFrame, encLevel protocol.EncryptionLevel, rcvTime monotime.Time) error {
 	acked1RTTPacket, err := c.sentPacketHandler.ReceivedAck(frame, encLevel, c.lastPacketReceivedTime)
 	if err != nil {
@@ -2091,7 +2541,15 @@ func (c *Conn) handleAckFrame(frame *wire.AckFrame, encLevel protocol.Encryption
 			c.sentPacketHandler.SetMaxDatagramSize(mtu)
 		}
 	}
-	return c.cryptoStreamHandler.SetLargest1RTTAcked(frame.LargestAcked())
+	if err := c.cryptoStreamHandler.SetLargest1RTTAcked(frame.LargestAcked()); err != nil {
+		return err
+	}
+	// A key update requested by the application might only have become possible now.
+	// Make sure that a packet is sent, so that the keys are updated.
+	if c.cryptoStreamHandler.KeyUpdatePending() {
+		c.queueControlFrame(&wire.PingFrame{})
+	}
+	return nil
 }
 
 func (c *Conn) handleDatagramFrame(f *wire.DatagramFrame) error {
@@ -2272,7 +2730,7 @@ func (c *Conn) dropEncryptionLevel(encLevel protocol.EncryptionLevel, now monoti
 }
 
 // is called for the client, when restoring transport parameters saved for 0-RTT
-func (c *Conn) restoreTransportParameters(params *wire.TransportParameters) {
+func (c *Conn) restoreTransportParameters(params *wire.TransportParameters, now monotime.Time) {
 	if c.logger.Debug() {
 		c.logger.Debugf("Restoring Transport Parameters: %s", params)
 	}
@@ -2299,19 +2757,21 @@ func (c *Conn) restoreTransportParameters(params *wire.TransportParameters) {
 			InitialMaxStreamsUni:            int64(params.MaxUniStreamNum),
 			MaxDatagramFrameSize:            params.MaxDatagramFrameSize,
 			EnableResetStreamAt:             params.EnableResetStreamAt,
+			InitialMaxPathID:                params.InitialMaxPathID,
 		})
 	}
 
 	c.peerParams = params
 	c.connIDGenerator.SetMaxActiveConnIDs(params.ActiveConnectionIDLimit)
 	c.connFlowController.UpdateSendWindow(params.InitialMaxData)
-	c.streamsMap.HandleTransportParameters(params)
+	c.streamsMap.HandleTransportParameters(params, now)
 	c.connStateMutex.Lock()
 	c.connState.SupportsDatagrams = c.supportsDatagrams()
+	c.connState.AdditionalTransportParameters = params.AdditionalParameters
 	c.connStateMutex.Unlock()
 }
 
-func (c *Conn) handleTransportParameters(params *wire.TransportParameters) error {
+func (c *Conn) handleTransportParameters(params *wire.TransportParameters, now monotime.Time) error {
 	if c.qlogger != nil {
 		c.qlogTransportParameters(params, c.perspective.Opposite(), false)
 	}
@@ -2333,7 +2793,7 @@ func (c *Conn) handleTransportParameters(params *wire.TransportParameters) error
 	// On the client side we have to wait for handshake completion.
 	// During a 0-RTT connection, we are only allowed to use the new transport parameters for 1-RTT packets.
 	if c.perspective == protocol.PerspectiveServer {
-		c.applyTransportParameters()
+		c.applyTransportParameters(now)
 		// On the server side, the early connection is ready as soon as we processed
 		// the client's transport parameters.
 		close(c.earlyConnReadyChan)
@@ -2341,6 +2801,7 @@ func (c *Conn) handleTransportParameters(params *wire.TransportParameters) error
 
 	c.connStateMutex.Lock()
 	c.connState.SupportsDatagrams = c.supportsDatagrams()
+	c.connState.AdditionalTransportParameters = params.AdditionalParameters
 	c.connStateMutex.Unlock()
 	return nil
 }
@@ -2375,7 +2836,7 @@ func (c *Conn) checkTransportParameters(params *wire.TransportParameters) error
 	return nil
 }
 
-func (c *Conn) applyTransportParameters() {
+func (c *Conn) applyTransportParameters(now monotime.Time) {
 	params := c.peerParams
 	// Our local idle timeout will always be > 0.
 	c.idleTimeout = c.config.MaxIdleTimeout
@@ -2384,17 +2845,24 @@ func (c *Conn) applyTransportParameters() {
 		c.idleTimeout = min(c.idleTimeout, params.MaxIdleTimeout)
 	}
 	c.keepAliveInterval = min(c.config.KeepAlivePeriod, c.idleTimeout/2)
-	c.streamsMap.HandleTransportParameters(params)
+	c.streamsMap.HandleTransportParameters(params, now)
 	c.frameParser.SetAckDelayExponent(params.AckDelayExponent)
 	c.connFlowController.UpdateSendWindow(params.InitialMaxData)
 	c.rttStats.SetMaxAckDelay(params.MaxAckDelay)
 	c.connIDGenerator.SetMaxActiveConnIDs(params.ActiveConnectionIDLimit)
+	// Multipath requires the use of non-zero-length connection IDs by both endpoints.
+	if c.config.EnableMultipath && c.srcConnIDLen > 0 && params.InitialMaxPathID != nil {
+		c.enableMultipath(*params.InitialMaxPathID)
+	}
+	if c.config.EnableAckFrequency && params.MinAckDelay != nil {
+		c.ackFrequencyManager = newAckFrequencyManager(*params.MinAckDelay, params.MaxAckDelay)
+	}
 	if params.StatelessResetToken != nil {
 		c.connIDManager.SetStatelessResetToken(*params.StatelessResetToken)
 	}
-	// We don't support connection migration yet, so we don't have any use for the preferred_address.
+	// The connection ID in the preferred_address can be used on any path,
+	// including the path to the preferred address (see migrateToPreferredAddress).
 	if params.PreferredAddress != nil {
-		// Retire the connection ID.
 		c.connIDManager.AddFromPreferredAddress(params.PreferredAddress.ConnectionID, params.PreferredAddress.StatelessResetToken)
 	}
 	maxPacketSize := protocol.ByteCount(protocol.MaxPacketBufferSize)
@@ -2412,6 +2880,10 @@ func (c *Conn) applyTransportParameters() {
 func (c *Conn) triggerSending(now monotime.Time) error {
 	c.pacingDeadline = 0
 
+	if c.multipath != nil && c.handshakeConfirmed && (len(c.multipath.paths) > 0 || c.multipath.initialPath.abandoned) {
+		return c.triggerSendingMultipath(now)
+	}
+
 	sendMode := c.sentPacketHandler.SendMode(now)
 	switch sendMode {
 	case ackhandler.SendAny:
@@ -2450,50 +2922,13 @@ func (c *Conn) triggerSending(now monotime.Time) error {
 }
 
 func (c *Conn) sendPackets(now monotime.Time) error {
-	if c.perspective == protocol.PerspectiveClient && c.handshakeConfirmed {
-		if pm := c.pathManagerOutgoing.Load(); pm != nil {
-			connID, frame, tr, ok := pm.NextPathToProbe()
-			if ok {
-				probe, buf, err := c.packer.PackPathProbePacket(connID, []ackhandler.Frame{frame}, c.version)
-				if err != nil {
-					return err
-				}
-				c.logger.Debugf("sending path probe packet from %s", c.LocalAddr())
-				c.logShortHeaderPacket(probe.DestConnID, probe.Ack, probe.Frames, probe.StreamFrames, probe.PacketNumber, probe.PacketNumberLen, probe.KeyPhase, protocol.ECNNon, buf.Len(), false)
-				c.registerPackedShortHeaderPacket(probe, protocol.ECNNon, now)
-				tr.WriteTo(buf.Data, c.conn.RemoteAddr())
-				// There's (likely) more data to send. Loop around again.
-				c.scheduleSending()
-				return nil
-			}
-		}
-	}
-
-	// Path MTU Discovery
-	// Can't use GSO, since we need to send a single packet that's larger than our current maximum size.
-	// Performance-wise, this doesn't matter, since we only send a very small (<10) number of
-	// MTU probe packets per connection.
-	if c.handshakeConfirmed && c.mtuDiscoverer != nil && c.mtuDiscoverer.ShouldSendProbe(now) {
-		ping, size := c.mtuDiscoverer.GetPing(now)
-		p, buf, err := c.packer.PackMTUProbePacket(ping, size, c.version)
-		if err != nil {
-			return err
-		}
-		ecn := c.sentPacketHandler.ECNMode(true)
-		c.logShortHeaderPacket(p.DestConnID, p.Ack, p.Frames, p.StreamFrames, p.PacketNumber, p.PacketNumberLen, p.KeyPhase, ecn, buf.Len(), false)
-		c.registerPackedShortHeaderPacket(p, ecn, now)
-		c.sendQueue.Send(buf, 0, ecn)
-		// There's (likely) more data to send. Loop around again.
-		c.scheduleSending()
-		return nil
-	}
-
-	if offset := c.connFlowController.GetWindowUpdate(now); offset > 0 {
-		c.framer.QueueControlFrame(&wire.MaxDataFrame{MaximumData: offset})
+	if sent, err := c.maybeSendPathProbePacket(now); sent || err != nil {
+		return err
 	}
-	if cf := c.cryptoStreamManager.GetPostHandshakeData(protocol.MaxPostHandshakeCryptoFrameSize); cf != nil {
-		c.queueControlFrame(cf)
+	if sent, err := c.maybeSendMTUProbePacket(now); sent || err != nil {
+		return err
 	}
+	c.queueConnectionLevelFrames(now)
 
 	if !c.handshakeConfirmed {
 		packet, err := c.packer.PackCoalescedPacket(false, c.maxPacketSize(), now, c.version)
@@ -2520,12 +2955,90 @@ func (c *Conn) sendPackets(now monotime.Time) error {
 	return c.sendPacketsWithoutGSO(now)
 }
 
+// maybeSendPathProbePacket sends a probe packet on a path added by the client using AddPath.
+func (c *Conn) maybeSendPathProbePacket(now monotime.Time) (sent bool, _ error) {
+	if c.perspective != protocol.PerspectiveClient || !c.handshakeConfirmed {
+		return false, nil
+	}
+	pm := c.pathManagerOutgoing.Load()
+	if pm == nil {
+		return false, nil
+	}
+	id, connID, frame, tr, remoteAddr, ok := pm.NextPathToProbe()
+	if !ok {
+		return false, nil
+	}
+	if c.multipath != nil {
+		if err := c.sendMultipathProbe(pm, id, frame, tr, remoteAddr, now); err != nil {
+			return false, err
+		}
+		// There's (likely) more data to send. Loop around again.
+		c.scheduleSending()
+		return true, nil
+	}
+	probe, buf, err := c.packer.PackPathProbePacket(connID, []ackhandler.Frame{frame}, c.version)
+	if err != nil {
+		return false, err
+	}
+	c.logger.Debugf("sending path probe packet from %s", c.LocalAddr())
+	c.logShortHeaderPacket(probe.DestConnID, probe.Ack, probe.Frames, probe.StreamFrames, probe.PacketNumber, probe.PacketNumberLen, probe.KeyPhase, protocol.ECNNon, buf.Len(), false)
+	c.registerPackedShortHeaderPacket(probe, protocol.ECNNon, now)
+	if remoteAddr == nil {
+		remoteAddr = c.conn.RemoteAddr()
+	}
+	if tr != nil {
+		tr.WriteTo(buf.Data, remoteAddr)
+	} else {
+		c.sendQueue.SendProbe(buf, remoteAddr)
+	}
+	// There's (likely) more data to send. Loop around again.
+	c.scheduleSending()
+	return true, nil
+}
+
+// Path MTU Discovery
+// Can't use GSO, since we need to send a single packet that's larger than our current maximum size.
+// Performance-wise, this doesn't matter, since we only send a very small (<10) number of
+// MTU probe packets per connection.
+func (c *Conn) maybeSendMTUProbePacket(now monotime.Time) (sent bool, _ error) {
+	if !c.handshakeConfirmed || c.mtuDiscoverer == nil || !c.mtuDiscoverer.ShouldSendProbe(now) {
+		return false, nil
+	}
+	ping, size := c.mtuDiscoverer.GetPing(now)
+	p, buf, err := c.packer.PackMTUProbePacket(ping, size, c.version)
+	if err != nil {
+		return false, err
+	}
+	ecn := c.sentPacketHandler.ECNMode(true)
+	c.logShortHeaderPacket(p.DestConnID, p.Ack, p.Frames, p.StreamFrames, p.PacketNumber, p.PacketNumberLen, p.KeyPhase, ecn, buf.Len(), false)
+	c.registerPackedShortHeaderPacket(p, ecn, now)
+	c.sendQueue.Send(buf, 0, ecn)
+	// There's (likely) more data to send. Loop around again.
+	c.scheduleSending()
+	return true, nil
+}
+
+func (c *Conn) queueConnectionLevelFrames(now monotime.Time) {
+	if offset := c.connFlowController.GetWindowUpdate(now); offset > 0 {
+		c.framer.QueueControlFrame(&wire.MaxDataFrame{MaximumData: offset})
+	}
+	if cf := c.cryptoStreamManager.GetPostHandshakeData(protocol.MaxPostHandshakeCryptoFrameSize); cf != nil {
+		c.queueControlFrame(cf)
+	}
+	if c.ackFrequencyManager != nil && c.handshakeConfirmed {
+		if f := c.ackFrequencyManager.GetFrame(c.sentPacketHandler.CongestionWindow(), c.maxPacketSize(), c.rttStats, now); f != nil {
+			c.queueControlFrame(f)
+		}
+	}
+}
+
 func (c *Conn) sendPacketsWithoutGSO(now monotime.Time) error {
 	for {
 		buf := getPacketBuffer()
 		ecn := c.sentPacketHandler.ECNMode(true)
 		if _, err := c.appendOneShortHeaderPacket(buf, c.maxPacketSize(), ecn, now); err != nil {
 			if err == errNothingToPack {
+				c.sentPacketHandler.SetAppLimited()
 				buf.Release()
 				return nil
 			}
@@ -2568,6 +3081,7 @@ func (c *Conn) sendPacketsWithGSO(now monotime.Time) error {
 			if err != errNothingToPack {
 				return err
 			}
+			c.sentPacketHandler.SetAppLimited()
 			if buf.Len() == 0 {
 				buf.Release()
 				return nil
@@ -2665,6 +3179,7 @@ func (c *Conn) sendProbePacket(sendMode ackhandler.SendMode, now monotime.Time)
 		encLevel = protocol.EncryptionHandshake
 	case ackhandler.SendPTOAppData:
 		encLevel = protocol.Encryption1RTT
+		c.maybeQueueImmediateAck()
 	default:
 		return fmt.Errorf("connection BUG: unexpected send mode: %d", sendMode)
 	}
@@ -2893,6 +3408,46 @@ func (c *Conn) OpenUniStreamSync(ctx context.Context) (*SendStream, error) {
 	return c.streamsMap.OpenUniStreamSync(ctx)
 }
 
+// OpenStreamWithPriority is like OpenStream, but sets the priority of the new stream.
+func (c *Conn) OpenStreamWithPriority(p StreamPriority) (*Stream, error) {
+	str, err := c.streamsMap.OpenStream()
+	if err != nil {
+		return nil, err
+	}
+	str.SetPriority(p)
+	return str, nil
+}
+
+// OpenStreamSyncWithPriority is like OpenStreamSync, but sets the priority of the new stream.
+func (c *Conn) OpenStreamSyncWithPriority(ctx context.Context, p StreamPriority) (*Stream, error) {
+	str, err := c.streamsMap.OpenStreamSync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	str.SetPriority(p)
+	return str, nil
+}
+
+// OpenUniStreamWithPriority is like OpenUniStream, but sets the priority of the new stream.
+func (c *Conn) OpenUniStreamWithPriority(p StreamPriority) (*SendStream, error) {
+	str, err := c.streamsMap.OpenUniStream()
+	if err != nil {
+		return nil, err
+	}
+	str.SetPriority(p)
+	return str, nil
+}
+
+// OpenUniStreamSyncWithPriority is like OpenUniStreamSync, but sets the priority of the new stream.
+func (c *Conn) OpenUniStreamSyncWithPriority(ctx context.Context, p StreamPriority) (*SendStream, error) {
+	str, err := c.streamsMap.OpenUniStreamSync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	str.SetPriority(p)
+	return str, nil
+}
+
 func (c *Conn) newFlowController(id protocol.StreamID) flowcontrol.StreamFlowController {
 	initialSendWindow := c.peerParams.InitialMaxStreamDataUni
 	if id.Type() == protocol.StreamTypeBidi {
@@ -2962,7 +3517,7 @@ func (c *Conn) queueControlFrame(f wire.Frame) {
 func (c *Conn) onHasConnectionData() { c.scheduleSending() }
 
 func (c *Conn) onHasStreamData(id protocol.StreamID, str *SendStream) {
-	c.framer.AddActiveStream(id, str)
+	c.framer.AddActiveStream(id, str, str.Priority())
 	c.scheduleSending()
 }
 
@@ -2985,23 +3540,62 @@ func (c *Conn) onStreamCompleted(id protocol.StreamID) {
 // In addition, a datagram may be dropped before being sent out if the available packet size suddenly decreases.
 // If the payload is too large to be sent at the current time, a DatagramTooLargeError is returned.
 func (c *Conn) SendDatagram(p []byte) error {
+	f, err := c.newDatagramFrame(p)
+	if err != nil {
+		return err
+	}
+	return c.datagramQueue.Add(f)
+}
+
+// SendDatagramWithFeedback sends a message using a QUIC datagram, like SendDatagram.
+// The returned DatagramHandle reports if the datagram was acknowledged by the peer,
+// if it was declared lost, or if it was discarded before being sent.
+// If a deadline is set, the datagram is discarded if it can't be sent before the deadline.
+func (c *Conn) SendDatagramWithFeedback(p []byte, opts SendDatagramOptions) (*DatagramHandle, error) {
+	f, err := c.newDatagramFrame(p)
+	if err != nil {
+		return nil, err
+	}
+	h := newDatagramHandle()
+	if err := c.datagramQueue.AddWithHandle(f, monotime.FromTime(opts.Deadline), h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (c *Conn) newDatagramFrame(p []byte) (*wire.DatagramFrame, error) {
 	if !c.supportsDatagrams() {
-		return errors.New("datagram support disabled")
+		return nil, errors.New("datagram support disabled")
 	}
 
-	f := &wire.DatagramFrame{DataLenPresent: true}
+	maxDataLen := c.maxDatagramPayloadSize()
+	if protocol.ByteCount(len(p)) > maxDataLen {
+		c.datagramQueue.RecordTooLarge()
+		return nil, &DatagramTooLargeError{MaxDatagramPayloadSize: int64(maxDataLen)}
+	}
+	f := &wire.DatagramFrame{DataLenPresent: true, Data: make([]byte, len(p))}
+	copy(f.Data, p)
+	return f, nil
+}
+
+// MaxDatagramPayloadSize returns the maximum payload size of a datagram that can currently be sent.
+// This value depends on the peer's max_datagram_frame_size transport parameter and on the
+// maximum packet size of the path. It increases as Path MTU Discovery finds larger packet sizes.
+// It returns 0 if the peer didn't enable datagram support.
+func (c *Conn) MaxDatagramPayloadSize() int64 {
+	if !c.supportsDatagrams() {
+		return 0
+	}
+	return int64(c.maxDatagramPayloadSize())
+}
+
+func (c *Conn) maxDatagramPayloadSize() protocol.ByteCount {
 	// The payload size estimate is conservative.
 	// Under many circumstances we could send a few more bytes.
-	maxDataLen := min(
-		f.MaxDataLen(c.peerParams.MaxDatagramFrameSize, c.version),
+	return min(
+		(&wire.DatagramFrame{DataLenPresent: true}).MaxDataLen(c.peerParams.MaxDatagramFrameSize, c.version),
 		protocol.ByteCount(c.currentMTUEstimate.Load()),
 	)
-	if protocol.ByteCount(len(p)) > maxDataLen {
-		return &DatagramTooLargeError{MaxDatagramPayloadSize: int64(maxDataLen)}
-	}
-	f.Data = make([]byte, len(p))
-	copy(f.Data, p)
-	return c.datagramQueue.Add(f)
 }
 
 // ReceiveDatagram gets a message received in a QUIC datagram, as specified in RFC 9221.
@@ -3013,10 +3607,18 @@ func (c *Conn) ReceiveDatagram(ctx context.Context) ([]byte, error) {
 }
 
 // LocalAddr returns the local address of the QUIC connection.
-func (c *Conn) LocalAddr() net.Addr { return c.conn.LocalAddr() }
+func (c *Conn) LocalAddr() net.Addr {
+	c.connMx.Lock()
+	defer c.connMx.Unlock()
+	return c.conn.LocalAddr()
+}
 
 // RemoteAddr returns the remote address of the QUIC connection.
-func (c *Conn) RemoteAddr() net.Addr { return c.conn.RemoteAddr() }
+func (c *Conn) RemoteAddr() net.Addr {
+	c.connMx.Lock()
+	defer c.connMx.Unlock()
+	return c.conn.RemoteAddr()
+}
 
 // getPathManager lazily initializes the Conn's pathManagerOutgoing.
 // May create multiple pathManagerOutgoing objects if called concurrently.
@@ -3029,8 +3631,8 @@ func (c *Conn) getPathManager() *pathManagerOutgoing {
 
 	// Initialize the path manager
 	new := newPathManagerOutgoing(
-		c.connIDManager.GetConnIDForPath,
-		c.connIDManager.RetireConnIDForPath,
+		c.getConnIDForPath,
+		c.retireConnIDForPath,
 		c.scheduleSending,
 	)
 	if c.pathManagerOutgoing.CompareAndSwap(old, new) {
@@ -3041,6 +3643,61 @@ func (c *Conn) getPathManager() *pathManagerOutgoing {
 	return c.pathManagerOutgoing.Load()
 }
 
+// addPreferredAddress issues the connection ID for the server's preferred address,
+// and starts accepting packets for this connection on the preferred address' Transport.
+func (c *Conn) addPreferredAddress(pa *PreferredAddress) (*wire.PreferredAddress, error) {
+	if err := validatePreferredAddress(pa, c.srcConnIDLen); err != nil {
+		return nil, err
+	}
+	runner := (*packetHandlerMap)(pa.Transport)
+	handler := &preferredAddressPacketHandler{Conn: c}
+	connID, token, err := c.connIDGenerator.AddPreferredAddressConnID(
+		runner,
+		connRunnerCallbacks{
+			AddConnectionID:    func(connID protocol.ConnectionID) { runner.Add(connID, handler) },
+			RemoveConnectionID: runner.Remove,
+			ReplaceWithClosed:  runner.ReplaceWithClosed,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &wire.PreferredAddress{
+		IPv4:                pa.IPv4,
+		IPv6:                pa.IPv6,
+		ConnectionID:        connID,
+		StatelessResetToken: token,
+	}, nil
+}
+
+// validatePreferredAddress checks that the preferred address can be used by a server
+// that uses connection IDs of length connIDLen.
+func validatePreferredAddress(pa *PreferredAddress, connIDLen int) error {
+	// A server that chooses a zero-length connection ID MUST NOT provide a preferred address,
+	// see section 18.2 of RFC 9000.
+	if connIDLen == 0 {
+		return errors.New("invalid preferred address: using zero-length connection IDs")
+	}
+	tr := pa.Transport
+	if err := tr.init(false); err != nil {
+		return err
+	}
+	if tr.connIDLen != connIDLen {
+		return fmt.Errorf("invalid preferred address: connection ID length mismatch: %d vs. %d", tr.connIDLen, connIDLen)
+	}
+	return nil
+}
+
+// The preferredAddressPacketHandler handles packets received on the Transport of the server's preferred address.
+type preferredAddressPacketHandler struct {
+	*Conn
+}
+
+func (h *preferredAddressPacketHandler) handlePacket(p receivedPacket) {
+	p.toPreferredAddress = true
+	h.Conn.handlePacket(p)
+}
+
 func (c *Conn) AddPath(t *Transport) (*Path, error) {
 	if c.perspective == protocol.PerspectiveServer {
 		return nil, errors.New("server cannot initiate connection migration")
@@ -3053,7 +3710,8 @@ func (c *Conn) AddPath(t *Transport) (*Path, error) {
 	}
 	return c.getPathManager().NewPath(
 		t,
-		200*time.Millisecond, // initial RTT estimate
+		nil,
+		initialPTOForPath(t.PathMetricsCache, c.RemoteAddr(), c.config),
 		func() {
 			runner := (*packetHandlerMap)(t)
 			c.connIDGenerator.AddConnRunner(
@@ -3068,6 +3726,27 @@ func (c *Conn) AddPath(t *Transport) (*Path, error) {
 	), nil
 }
 
+// InitiateKeyUpdate initiates an update of the 1-RTT keys, see section 6 of RFC 9001.
+// The key update is performed when the next packet is sent, once the handshake is confirmed
+// and the peer acknowledged a packet sent with the current keys.
+// Multiple calls before the key update is performed result in a single key update.
+// Completed key updates are counted in ConnectionStats.KeyUpdates.
+func (c *Conn) InitiateKeyUpdate() error {
+	if c.ctx.Err() != nil {
+		return context.Cause(c.ctx)
+	}
+	select {
+	case <-c.handshakeCompleteChan:
+	default:
+		return errors.New("handshake not complete")
+	}
+	c.cryptoStreamHandler.InitiateKeyUpdate()
+	// Make sure that a packet is sent, even if there's no other data to send.
+	// If the key update isn't allowed yet, another PING is sent once it is.
+	c.queueControlFrame(&wire.PingFrame{})
+	return nil
+}
+
 // HandshakeComplete blocks until the handshake completes (or fails).
 // For the client, data sent before completion of the handshake is encrypted with 0-RTT keys.
 // For the server, data sent before completion of the handshake is encrypted with 1-RTT keys,
@@ -42,6 +42,14 @@ func toQlogFrame(frame wire.Frame) qlog.Frame {
 				Length: int64(len(f.Data)),
 			},
 		}
+	case *wire.PathAckFrame:
+		// PATH_ACK frames are pooled as well.
+		return qlog.Frame{
+			Frame: &qlog.PathAckFrame{
+				PathID:   f.PathID,
+				AckFrame: *toQlogAckFrame(&f.AckFrame),
+			},
+		}
 	default:
 		return qlog.Frame{Frame: frame}
 	}
@@ -171,6 +179,22 @@ func (c *Conn) logShortHeaderPacket(
 	}
 }
 
+// logShortHeaderPacketOnPath logs a short header packet sent on a path of a multipath connection.
+// On paths other than the initial path, acknowledgments are sent in PATH_ACK frames.
+func (c *Conn) logShortHeaderPacketOnPath(pathID protocol.PathID, p shortHeaderPacket, ecn protocol.ECN, size protocol.ByteCount) {
+	if pathID == 0 || p.Ack == nil {
+		c.logShortHeaderPacket(p.DestConnID, p.Ack, p.Frames, p.StreamFrames, p.PacketNumber, p.PacketNumberLen, p.KeyPhase, ecn, size, false)
+		return
+	}
+	frames := p.Frames
+	if c.logger.Debug() || c.qlogger != nil {
+		frames = make([]ackhandler.Frame, 0, len(p.Frames)+1)
+		frames = append(frames, ackhandler.Frame{Frame: &wire.PathAckFrame{PathID: pathID, AckFrame: *p.Ack}})
+		frames = append(frames, p.Frames...)
+	}
+	c.logShortHeaderPacket(p.DestConnID, nil, frames, p.StreamFrames, p.PacketNumber, p.PacketNumberLen, p.KeyPhase, ecn, size, false)
+}
+
 func (c *Conn) logCoalescedPacket(packet *coalescedPacket, ecn protocol.ECN) {
 	if c.logger.Debug() {
 		// There's a short period between dropping both Initial and Handshake keys and completion of the handshake,
@@ -225,6 +249,7 @@ func (c *Conn) qlogTransportParameters(tp *wire.TransportParameters, sentBy prot
 		InitialMaxStreamsUni:            int64(tp.MaxUniStreamNum),
 		MaxDatagramFrameSize:            tp.MaxDatagramFrameSize,
 		EnableResetStreamAt:             tp.EnableResetStreamAt,
+		InitialMaxPathID:                tp.InitialMaxPathID,
 	}
 	if sentBy == c.perspective {
 		ev.Initiator = qlog.InitiatorLocal
@@ -0,0 +1,965 @@
+package quic
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net"
+	"slices"
+
+	"github.com/quic-go/quic-go/internal/ackhandler"
+	"github.com/quic-go/quic-go/internal/handshake"
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/internal/utils"
+	"github.com/quic-go/quic-go/internal/wire"
+	"github.com/quic-go/quic-go/qlog"
+)
+
+// APPLICATION_ABANDON_PATH, see section 10.2 of draft-ietf-quic-multipath-14.
+const pathAbandonErrorApplication = 0x004150d3
+
+// maxPathChallenges is the maximum number of outstanding PATH_CHALLENGE frames per path.
+const maxPathChallenges = 4
+
+// A multipathPath is a path of a multipath connection.
+// Every path has its own packet number space, as well as its own RTT estimate and congestion controller.
+type multipathPath struct {
+	id protocol.PathID
+
+	// conn is nil if packets are sent using the connection's sendConn
+	conn       sendConn
+	remoteAddr net.Addr
+
+	destConnID          protocol.ConnectionID
+	destConnIDSeq       uint64
+	statelessResetToken protocol.StatelessResetToken
+
+	rttStats              *utils.RTTStats
+	sentPacketHandler     ackhandler.SentPacketHandler
+	receivedPacketHandler ackhandler.ReceivedPacketHandler
+	packer                packer
+	unpacker              *pathUnpacker
+
+	validated bool
+	// PATH_CHALLENGE frames sent by the server
+	pathChallenges    [][8]byte
+	lastChallengeTime monotime.Time
+
+	localStatus         PathStatus
+	remoteStatus        PathStatus
+	nextStatusSeq       uint64 // the sequence number of the next PATH_STATUS frame we send
+	nextRemoteStatusSeq uint64 // PATH_STATUS frames with smaller sequence numbers are ignored
+
+	abandoned bool // only used for the initial path, all other paths are removed when abandoned
+}
+
+func (p *multipathPath) status() PathStatus {
+	if p.localStatus == PathStatusBackup || p.remoteStatus == PathStatusBackup {
+		return PathStatusBackup
+	}
+	return PathStatusAvailable
+}
+
+// The multipathManager keeps track of the paths of a multipath connection.
+// It is only accessed from the connection's run loop.
+type multipathManager struct {
+	localMaxPathID   protocol.PathID // the maximum path ID that the peer is allowed to use
+	peerMaxPathID    protocol.PathID // the maximum path ID that we're allowed to use
+	sentPathsBlocked bool
+
+	initialPath *multipathPath
+	paths       map[protocol.PathID]*multipathPath // all paths except for the initial path
+	// The path IDs up to localMaxPathID that haven't been abandoned (excluding the initial path).
+	// Abandoned paths are tracked implicitly: all other path IDs up to localMaxPathID are abandoned.
+	// Since localMaxPathID is only increased when a path is abandoned, the size of this set is bounded.
+	unabandonedPaths map[protocol.PathID]struct{}
+
+	// connection IDs issued by the peer that are not in use yet
+	connIDs       map[protocol.PathID][]newConnID
+	retirePriorTo map[protocol.PathID]uint64
+
+	// reused for every packet sent, to avoid allocations
+	allPathsBuf []*multipathPath
+	sendPaths   []*multipathPath
+	pathInfos   []PathInfo
+}
+
+func newMultipathManager(localMaxPathID, peerMaxPathID protocol.PathID, initialPath *multipathPath) *multipathManager {
+	m := &multipathManager{
+		localMaxPathID:   localMaxPathID,
+		peerMaxPathID:    peerMaxPathID,
+		initialPath:      initialPath,
+		paths:            make(map[protocol.PathID]*multipathPath),
+		unabandonedPaths: make(map[protocol.PathID]struct{}, localMaxPathID),
+		connIDs:          make(map[protocol.PathID][]newConnID),
+		retirePriorTo:    make(map[protocol.PathID]uint64),
+	}
+	for id := protocol.PathID(1); id <= localMaxPathID; id++ {
+		m.unabandonedPaths[id] = struct{}{}
+	}
+	return m
+}
+
+// isAbandoned says if the path was abandoned.
+func (m *multipathManager) isAbandoned(id protocol.PathID) bool {
+	if id == 0 {
+		return m.initialPath.abandoned
+	}
+	if id > m.localMaxPathID {
+		return false
+	}
+	_, ok := m.unabandonedPaths[id]
+	return !ok
+}
+
+// increaseLocalMaxPathID allows the peer to open one more path.
+func (m *multipathManager) increaseLocalMaxPathID() {
+	m.localMaxPathID++
+	m.unabandonedPaths[m.localMaxPathID] = struct{}{}
+}
+
+func (m *multipathManager) maxPathID() protocol.PathID {
+	return min(m.localMaxPathID, m.peerMaxPathID)
+}
+
+// path returns the path with the given ID, including the initial path.
+func (m *multipathManager) path(id protocol.PathID) *multipathPath {
+	if id == 0 {
+		return m.initialPath
+	}
+	return m.paths[id]
+}
+
+// allPaths returns all paths, sorted by path ID.
+// The returned slice is only valid until the next call.
+func (m *multipathManager) allPaths() []*multipathPath {
+	m.allPathsBuf = append(m.allPathsBuf[:0], m.initialPath)
+	for _, p := range m.paths {
+		m.allPathsBuf = append(m.allPathsBuf, p)
+	}
+	slices.SortFunc(m.allPathsBuf[1:], func(a, b *multipathPath) int { return int(a.id) - int(b.id) })
+	return m.allPathsBuf
+}
+
+// AddConnectionID handles a PATH_NEW_CONNECTION_ID frame for a path other than the initial path.
+func (m *multipathManager) AddConnectionID(
+	f *wire.PathNewConnectionIDFrame,
+	queueControlFrame func(wire.Frame),
+	addStatelessResetToken, removeStatelessResetToken func(protocol.StatelessResetToken),
+) error {
+	if m.isAbandoned(f.PathID) {
+		return nil
+	}
+	retirePriorTo := m.retirePriorTo[f.PathID]
+	if f.SequenceNumber < retirePriorTo {
+		queueControlFrame(&wire.PathRetireConnectionIDFrame{PathID: f.PathID, SequenceNumber: f.SequenceNumber})
+		return nil
+	}
+	queue := m.connIDs[f.PathID]
+	if f.RetirePriorTo > retirePriorTo {
+		m.retirePriorTo[f.PathID] = f.RetirePriorTo
+		queue = slices.DeleteFunc(queue, func(entry newConnID) bool {
+			if entry.SequenceNumber >= f.RetirePriorTo {
+				return false
+			}
+			queueControlFrame(&wire.PathRetireConnectionIDFrame{PathID: f.PathID, SequenceNumber: entry.SequenceNumber})
+			return true
+		})
+	}
+	path := m.paths[f.PathID]
+	if path != nil && path.destConnIDSeq == f.SequenceNumber {
+		m.connIDs[f.PathID] = queue
+		return nil
+	}
+	idx, found := slices.BinarySearchFunc(queue, f.SequenceNumber, func(entry newConnID, seq uint64) int {
+		return int(entry.SequenceNumber) - int(seq)
+	})
+	if found {
+		if queue[idx].ConnectionID != f.ConnectionID {
+			return fmt.Errorf("received conflicting connection IDs for sequence number %d on path %d", f.SequenceNumber, f.PathID)
+		}
+		if queue[idx].StatelessResetToken != f.StatelessResetToken {
+			return fmt.Errorf("received conflicting stateless reset tokens for sequence number %d on path %d", f.SequenceNumber, f.PathID)
+		}
+	} else {
+		queue = slices.Insert(queue, idx, newConnID{
+			SequenceNumber:      f.SequenceNumber,
+			ConnectionID:        f.ConnectionID,
+			StatelessResetToken: f.StatelessResetToken,
+		})
+	}
+	m.connIDs[f.PathID] = queue
+	// For paths that are open, one connection ID is in use and not contained in the queue.
+	// For paths that haven't been opened yet, all active connection IDs are queued.
+	maxQueued := protocol.MaxActiveConnectionIDs - 1
+	if path == nil {
+		maxQueued = protocol.MaxActiveConnectionIDs
+	}
+	if len(queue) > maxQueued {
+		return &qerr.TransportError{ErrorCode: qerr.ConnectionIDLimitError}
+	}
+
+	// Switch to a new connection ID if the one in use was retired.
+	if path != nil && path.destConnIDSeq < f.RetirePriorTo && len(queue) > 0 {
+		queueControlFrame(&wire.PathRetireConnectionIDFrame{PathID: f.PathID, SequenceNumber: path.destConnIDSeq})
+		removeStatelessResetToken(path.statelessResetToken)
+		front := queue[0]
+		m.connIDs[f.PathID] = queue[1:]
+		path.destConnID = front.ConnectionID
+		path.destConnIDSeq = front.SequenceNumber
+		path.statelessResetToken = front.StatelessResetToken
+		addStatelessResetToken(path.statelessResetToken)
+	}
+	return nil
+}
+
+func (m *multipathManager) hasConnID(id protocol.PathID) bool {
+	return len(m.connIDs[id]) > 0
+}
+
+func (m *multipathManager) popConnID(id protocol.PathID) (newConnID, bool) {
+	queue := m.connIDs[id]
+	if len(queue) == 0 {
+		return newConnID{}, false
+	}
+	m.connIDs[id] = queue[1:]
+	return queue[0], true
+}
+
+// The pathSealingManager provides the 1-RTT sealer of a path of a multipath connection.
+// Only 1-RTT packets are sent on paths other than the initial path.
+type pathSealingManager struct {
+	sealer handshake.ShortHeaderSealer
+}
+
+var _ sealingManager = &pathSealingManager{}
+
+func (m *pathSealingManager) GetInitialSealer() (handshake.LongHeaderSealer, error) {
+	return nil, handshake.ErrKeysDropped
+}
+
+func (m *pathSealingManager) GetHandshakeSealer() (handshake.LongHeaderSealer, error) {
+	return nil, handshake.ErrKeysDropped
+}
+
+func (m *pathSealingManager) Get0RTTSealer() (handshake.LongHeaderSealer, error) {
+	return nil, handshake.ErrKeysDropped
+}
+
+func (m *pathSealingManager) Get1RTTSealer() (handshake.ShortHeaderSealer, error) {
+	return m.sealer, nil
+}
+
+// enableMultipath is called once the use of the multipath extension was negotiated.
+func (c *Conn) enableMultipath(peerMaxPathID protocol.PathID) {
+	c.multipath = newMultipathManager(
+		protocol.MaxMultipathPathID,
+		peerMaxPathID,
+		&multipathPath{
+			id:                    0,
+			rttStats:              c.rttStats,
+			sentPacketHandler:     c.sentPacketHandler,
+			receivedPacketHandler: c.receivedPacketHandler,
+			packer:                c.packer,
+			validated:             true,
+		},
+	)
+	c.multipathNegotiated.Store(true)
+	c.connStateMutex.Lock()
+	c.connState.SupportsMultipath = true
+	c.connStateMutex.Unlock()
+	c.connIDGenerator.SetMaxPathID(c.multipath.maxPathID())
+}
+
+func (c *Conn) errMultipathNotNegotiated(frameName string) error {
+	return &qerr.TransportError{
+		ErrorCode:    qerr.ProtocolViolation,
+		ErrorMessage: fmt.Sprintf("received %s frame, but multipath was not negotiated", frameName),
+	}
+}
+
+// newMultipathPath creates a new path.
+// If unpacker is nil, a new unpacker is created.
+func (c *Conn) newMultipathPath(id protocol.PathID, conn sendConn, remoteAddr net.Addr, unpacker *pathUnpacker) (*multipathPath, error) {
+	connID, ok := c.multipath.popConnID(id)
+	if !ok {
+		return nil, fmt.Errorf("no connection ID available for path %d", id)
+	}
+	sealer, err := c.cryptoStreamHandler.Get1RTTSealerForPath(id)
+	if err != nil {
+		return nil, err
+	}
+	if unpacker == nil {
+		opener, err := c.cryptoStreamHandler.Get1RTTOpenerForPath(id)
+		if err != nil {
+			return nil, err
+		}
+		unpacker = newPathUnpacker(opener, c.srcConnIDLen)
+	}
+	rttStats := utils.NewRTTStats()
+	rttStats.SetMaxAckDelay(c.peerParams.MaxAckDelay)
+	sph, rph := ackhandler.NewPathAckHandler(
+		protocol.ByteCount(c.config.InitialPacketSize),
+		rttStats,
+		&c.connStats,
+		conn != nil && conn.capabilities().ECN,
+		c.config.congestionControl(),
+		c.perspective,
+		c.qlogger,
+		c.logger,
+	)
+	if c.peerAckFrequency != nil {
+		rph.HandleAckFrequencyFrame(c.peerAckFrequency)
+	}
+	path := &multipathPath{
+		id:                    id,
+		conn:                  conn,
+		remoteAddr:            remoteAddr,
+		destConnID:            connID.ConnectionID,
+		destConnIDSeq:         connID.SequenceNumber,
+		statelessResetToken:   connID.StatelessResetToken,
+		rttStats:              rttStats,
+		sentPacketHandler:     sph,
+		receivedPacketHandler: rph,
+		unpacker:              unpacker,
+	}
+	pp := newPacketPacker(
+		protocol.ConnectionID{}, // only short header packets are sent on this path
+		func() protocol.ConnectionID { return path.destConnID },
+		nil,
+		nil,
+		sph,
+		c.retransmissionQueue,
+		&pathSealingManager{sealer: sealer},
+		c.framer,
+		rph,
+		c.datagramQueue,
+		c.perspective,
+	)
+	pp.pathID = id
+	path.packer = pp
+	c.connIDManager.addStatelessResetToken(path.statelessResetToken)
+	c.multipath.paths[id] = path
+	c.logger.Debugf("created path %d (remote address: %s)", id, remoteAddr)
+	return path, nil
+}
+
+// getConnIDForPath returns the connection ID used to probe a new path.
+func (c *Conn) getConnIDForPath(id pathID) (protocol.ConnectionID, bool) {
+	if c.multipath == nil {
+		return c.connIDManager.GetConnIDForPath(id)
+	}
+	pid := protocol.PathID(id)
+	if path, ok := c.multipath.paths[pid]; ok {
+		return path.destConnID, true
+	}
+	if pid > c.multipath.maxPathID() {
+		if pid > c.multipath.peerMaxPathID && !c.multipath.sentPathsBlocked {
+			c.multipath.sentPathsBlocked = true
+			c.queueControlFrame(&wire.PathsBlockedFrame{MaximumPathID: c.multipath.peerMaxPathID})
+		}
+		return protocol.ConnectionID{}, false
+	}
+	queue := c.multipath.connIDs[pid]
+	if len(queue) == 0 {
+		return protocol.ConnectionID{}, false
+	}
+	return queue[0].ConnectionID, true
+}
+
+// retireConnIDForPath is called when the application closes a path.
+// It is called from the application's goroutine.
+func (c *Conn) retireConnIDForPath(id pathID) {
+	// When using multipath, paths are abandoned from the run loop (see handleMultipathPathUpdates).
+	if c.multipathNegotiated.Load() {
+		return
+	}
+	c.connIDManager.RetireConnIDForPath(id)
+}
+
+// handleMultipathPathUpdates applies the changes that the application made to the paths of a multipath connection.
+func (c *Conn) handleMultipathPathUpdates(pm *pathManagerOutgoing, now monotime.Time) {
+	for _, id := range pm.PopAbandonedPaths() {
+		c.abandonPath(protocol.PathID(id), pathAbandonErrorApplication, now)
+	}
+	for id, status := range pm.PopPathStatusUpdates() {
+		if path := c.multipath.path(protocol.PathID(id)); path != nil {
+			c.setLocalPathStatus(path, status)
+		}
+	}
+	// Switching to a path makes this path the only available path.
+	if id, _, _, ok := pm.ShouldSwitchPath(); ok {
+		for _, path := range c.multipath.allPaths() {
+			if path.id == protocol.PathID(id) {
+				c.setLocalPathStatus(path, PathStatusAvailable)
+			} else {
+				c.setLocalPathStatus(path, PathStatusBackup)
+			}
+		}
+	}
+}
+
+func (c *Conn) setLocalPathStatus(path *multipathPath, status PathStatus) {
+	if path.localStatus == status {
+		return
+	}
+	path.localStatus = status
+	c.queueControlFrame(&wire.PathStatusFrame{
+		PathID:         path.id,
+		SequenceNumber: path.nextStatusSeq,
+		Backup:         status == PathStatusBackup,
+	})
+	path.nextStatusSeq++
+}
+
+// abandonPath abandons a path, and sends a PATH_ABANDON frame.
+// All frames sent on this path that are still outstanding are retransmitted on the remaining paths.
+func (c *Conn) abandonPath(id protocol.PathID, errorCode uint64, now monotime.Time) error {
+	m := c.multipath
+	if m.isAbandoned(id) {
+		return nil
+	}
+	c.logger.Debugf("abandoning path %d", id)
+	c.queueControlFrame(&wire.PathAbandonFrame{PathID: id, ErrorCode: errorCode})
+	path := m.path(id)
+	if id != 0 {
+		delete(m.unabandonedPaths, id)
+	}
+	if path != nil {
+		path.sentPacketHandler.MigratedPath(now, protocol.ByteCount(c.config.InitialPacketSize))
+		if id == 0 {
+			path.abandoned = true
+		} else {
+			// The Transport used by this path is owned by the application, so we don't close it here.
+			c.connIDManager.removeStatelessResetToken(path.statelessResetToken)
+			delete(m.paths, id)
+		}
+	}
+	// The connection IDs of an abandoned path are implicitly retired.
+	delete(m.connIDs, id)
+	delete(m.retirePriorTo, id)
+	if id == 0 {
+		return nil
+	}
+	c.connIDGenerator.RemovePath(id, now.Add(3*c.rttStats.PTO(false)))
+	// Allow the peer to open a new path.
+	// This is only done for paths that were actually used, otherwise the peer could
+	// increase the maximum path ID (and the state we keep) by abandoning unused paths.
+	if path == nil {
+		return nil
+	}
+	m.increaseLocalMaxPathID()
+	c.queueControlFrame(&wire.MaxPathIDFrame{MaximumPathID: m.localMaxPathID})
+	return c.connIDGenerator.SetMaxPathID(m.maxPathID())
+}
+
+// handleMultipathPacket handles a packet received on a path other than the initial path.
+func (c *Conn) handleMultipathPacket(p receivedPacket, id protocol.PathID, destConnID protocol.ConnectionID) (wasProcessed, wasQueued bool, _ error) {
+	path, ok := c.multipath.paths[id]
+	var unpacker *pathUnpacker
+	if ok {
+		unpacker = path.unpacker
+	} else {
+		// Only the client opens new paths.
+		// The server needs a connection ID issued by the client to respond on this path.
+		if c.multipath.isAbandoned(id) || c.perspective == protocol.PerspectiveClient || !c.multipath.hasConnID(id) {
+			c.logger.Debugf("dropping packet for unknown path %d", id)
+			if c.qlogger != nil {
+				c.qlogger.RecordEvent(qlog.PacketDropped{
+					Header: qlog.PacketHeader{
+						PacketType:   qlog.PacketType1RTT,
+						PacketNumber: protocol.InvalidPacketNumber,
+					},
+					Raw:     qlog.RawInfo{Length: len(p.data)},
+					Trigger: qlog.PacketDropUnknownConnectionID,
+				})
+			}
+			return false, false, nil
+		}
+		opener, err := c.cryptoStreamHandler.Get1RTTOpenerForPath(id)
+		if err != nil {
+			wasQueued, err := c.handleUnpackError(err, p, qlog.PacketType1RTT)
+			return false, wasQueued, err
+		}
+		unpacker = newPathUnpacker(opener, c.srcConnIDLen)
+	}
+
+	pn, pnLen, keyPhase, data, err := unpacker.UnpackShortHeader(p.rcvTime, p.data)
+	if err != nil {
+		wasQueued, err := c.handleUnpackError(err, p, qlog.PacketType1RTT)
+		return false, wasQueued, err
+	}
+	if path == nil {
+		path, err = c.newMultipathPath(id, nil, p.remoteAddr, unpacker)
+		if err != nil {
+			return false, false, err
+		}
+	}
+
+	if c.logger.Debug() {
+		c.logger.Debugf("<- Reading packet %d (%d bytes) for connection %s, 1-RTT, path %d", pn, p.Size(), destConnID, id)
+		wire.LogShortHeader(c.logger, destConnID, pn, pnLen, keyPhase)
+	}
+	if path.receivedPacketHandler.IsPotentiallyDuplicate(pn, protocol.Encryption1RTT) {
+		c.logDuplicateShortHeaderPacket(p, pn)
+		return false, false, nil
+	}
+	_, pathChallenge, err := c.handleUnpackedShortHeaderPacket(
+		destConnID, pn, data, p.ecn, p.rcvTime,
+		path.receivedPacketHandler,
+		c.shortHeaderPacketLogger(p, destConnID, pn, pnLen, keyPhase),
+	)
+	if err != nil {
+		return false, false, err
+	}
+	// The path might have been abandoned by a frame contained in this packet.
+	if c.multipath.paths[id] != path {
+		return true, false, nil
+	}
+
+	var frames []ackhandler.Frame
+	if c.perspective == protocol.PerspectiveServer && !path.validated &&
+		(path.lastChallengeTime.IsZero() || p.rcvTime.Sub(path.lastChallengeTime) >= path.rttStats.PTO(false)) {
+		var b [8]byte
+		_, _ = rand.Read(b[:])
+		if len(path.pathChallenges) >= maxPathChallenges {
+			path.pathChallenges = path.pathChallenges[1:]
+		}
+		path.pathChallenges = append(path.pathChallenges, b)
+		path.lastChallengeTime = p.rcvTime
+		frames = append(frames, ackhandler.Frame{Frame: &wire.PathChallengeFrame{Data: b}, Handler: emptyHandler{}})
+	}
+	// PATH_RESPONSE frames are sent on the path that the PATH_CHALLENGE was received on,
+	// see section 5.1 of draft-ietf-quic-multipath-14.
+	if pathChallenge != nil {
+		frames = append(frames, ackhandler.Frame{Frame: &wire.PathResponseFrame{Data: pathChallenge.Data}, Handler: emptyHandler{}})
+	}
+	if len(frames) > 0 {
+		if err := c.sendPathProbePacket(path, frames, p.rcvTime); err != nil {
+			return true, false, err
+		}
+	}
+	return true, false, nil
+}
+
+func (c *Conn) handlePathAckFrame(f *wire.PathAckFrame, rcvTime monotime.Time) error {
+	if c.multipath == nil {
+		return c.errMultipathNotNegotiated("PATH_ACK")
+	}
+	if f.PathID == 0 {
+		return c.handleAckFrame(&f.AckFrame, protocol.Encryption1RTT, rcvTime)
+	}
+	path, ok := c.multipath.paths[f.PathID]
+	if !ok {
+		// the path might already have been abandoned
+		return nil
+	}
+	_, err := path.sentPacketHandler.ReceivedAck(&f.AckFrame, protocol.Encryption1RTT, c.lastPacketReceivedTime)
+	return err
+}
+
+func (c *Conn) handlePathAbandonFrame(f *wire.PathAbandonFrame, rcvTime monotime.Time) error {
+	if c.multipath == nil {
+		return c.errMultipathNotNegotiated("PATH_ABANDON")
+	}
+	if f.PathID > c.multipath.localMaxPathID {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			ErrorMessage: fmt.Sprintf("received PATH_ABANDON for path %d (maximum path ID: %d)", f.PathID, c.multipath.localMaxPathID),
+		}
+	}
+	// Respond with a PATH_ABANDON frame (unless we already abandoned this path).
+	return c.abandonPath(f.PathID, f.ErrorCode, rcvTime)
+}
+
+func (c *Conn) handlePathStatusFrame(f *wire.PathStatusFrame) error {
+	if c.multipath == nil {
+		return c.errMultipathNotNegotiated("PATH_STATUS")
+	}
+	path := c.multipath.path(f.PathID)
+	if path == nil || f.SequenceNumber < path.nextRemoteStatusSeq {
+		return nil
+	}
+	path.nextRemoteStatusSeq = f.SequenceNumber + 1
+	if f.Backup {
+		path.remoteStatus = PathStatusBackup
+	} else {
+		path.remoteStatus = PathStatusAvailable
+	}
+	return nil
+}
+
+func (c *Conn) handlePathNewConnectionIDFrame(f *wire.PathNewConnectionIDFrame) error {
+	if c.multipath == nil {
+		return c.errMultipathNotNegotiated("PATH_NEW_CONNECTION_ID")
+	}
+	if f.PathID > c.multipath.localMaxPathID {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			ErrorMessage: fmt.Sprintf("received PATH_NEW_CONNECTION_ID for path %d (maximum path ID: %d)", f.PathID, c.multipath.localMaxPathID),
+		}
+	}
+	if f.PathID == 0 {
+		return c.connIDManager.Add(&wire.NewConnectionIDFrame{
+			SequenceNumber:      f.SequenceNumber,
+			RetirePriorTo:       f.RetirePriorTo,
+			ConnectionID:        f.ConnectionID,
+			StatelessResetToken: f.StatelessResetToken,
+		})
+	}
+	return c.multipath.AddConnectionID(
+		f,
+		c.queueControlFrame,
+		c.connIDManager.addStatelessResetToken,
+		c.connIDManager.removeStatelessResetToken,
+	)
+}
+
+func (c *Conn) handlePathRetireConnectionIDFrame(f *wire.PathRetireConnectionIDFrame, destConnID protocol.ConnectionID, rcvTime monotime.Time) error {
+	if c.multipath == nil {
+		return c.errMultipathNotNegotiated("PATH_RETIRE_CONNECTION_ID")
+	}
+	return c.connIDGenerator.RetirePathConnID(f.PathID, f.SequenceNumber, destConnID, rcvTime.Add(3*c.rttStats.PTO(false)))
+}
+
+func (c *Conn) handleMaxPathIDFrame(f *wire.MaxPathIDFrame) error {
+	if c.multipath == nil {
+		return c.errMultipathNotNegotiated("MAX_PATH_ID")
+	}
+	if f.MaximumPathID <= c.multipath.peerMaxPathID {
+		return nil
+	}
+	c.multipath.peerMaxPathID = f.MaximumPathID
+	c.multipath.sentPathsBlocked = false
+	return c.connIDGenerator.SetMaxPathID(c.multipath.maxPathID())
+}
+
+// handlePathResponseFrameMultipath handles a PATH_RESPONSE frame for one of the PATH_CHALLENGEs
+// sent by the server on a path of a multipath connection.
+func (c *Conn) handlePathResponseFrameMultipath(f *wire.PathResponseFrame) bool {
+	for _, path := range c.multipath.paths {
+		if slices.Contains(path.pathChallenges, f.Data) {
+			c.logger.Debugf("path %d validated", path.id)
+			path.validated = true
+			path.pathChallenges = nil
+			return true
+		}
+	}
+	return false
+}
+
+// sendMultipathProbe sends a PATH_CHALLENGE on a (new) path of a multipath connection.
+func (c *Conn) sendMultipathProbe(pm *pathManagerOutgoing, id pathID, frame ackhandler.Frame, tr *Transport, remoteAddr net.Addr, now monotime.Time) error {
+	path, ok := c.multipath.paths[protocol.PathID(id)]
+	if !ok {
+		if remoteAddr == nil {
+			remoteAddr = c.conn.RemoteAddr()
+		}
+		var conn sendConn
+		if tr != nil {
+			conn = newSendConn(tr.conn, remoteAddr, packetInfo{}, c.logger)
+		}
+		var err error
+		path, err = c.newMultipathPath(protocol.PathID(id), conn, remoteAddr, nil)
+		if err != nil {
+			return err
+		}
+		if status := pm.PathStatus(id); status != PathStatusAvailable {
+			c.setLocalPathStatus(path, status)
+		}
+	}
+	return c.sendPathProbePacket(path, []ackhandler.Frame{frame}, now)
+}
+
+func (c *Conn) sendPathProbePacket(path *multipathPath, frames []ackhandler.Frame, now monotime.Time) error {
+	probe, buf, err := path.packer.PackPathProbePacket(path.destConnID, frames, c.version)
+	if err != nil {
+		return err
+	}
+	c.logger.Debugf("sending path probe packet on path %d", path.id)
+	c.logShortHeaderPacketOnPath(path.id, probe, protocol.ECNNon, buf.Len())
+	c.registerPackedShortHeaderPacketOnPath(path, probe, protocol.ECNNon, now)
+	c.sendOnPath(path, buf, protocol.ECNNon)
+	return nil
+}
+
+// sendOnPath sends a packet on a path of a multipath connection.
+func (c *Conn) sendOnPath(path *multipathPath, buf *packetBuffer, ecn protocol.ECN) {
+	if path.id == 0 {
+		c.sendQueue.Send(buf, 0, ecn)
+		return
+	}
+	var err error
+	if path.conn != nil {
+		err = path.conn.Write(buf.Data, 0, ecn)
+	} else {
+		err = c.conn.WriteTo(buf.Data, path.remoteAddr)
+	}
+	buf.Release()
+	if err != nil {
+		c.logger.Debugf("sending packet on path %d failed: %s", path.id, err)
+	}
+}
+
+func (c *Conn) registerPackedShortHeaderPacketOnPath(path *multipathPath, p shortHeaderPacket, ecn protocol.ECN, now monotime.Time) {
+	if path.id == 0 {
+		c.registerPackedShortHeaderPacket(p, ecn, now)
+		return
+	}
+	if !p.IsPathProbePacket && c.firstAckElicitingPacketAfterIdleSentTime.IsZero() &&
+		(len(p.StreamFrames) > 0 || ackhandler.HasAckElicitingFrames(p.Frames)) {
+		c.firstAckElicitingPacketAfterIdleSentTime = now
+	}
+	largestAcked := protocol.InvalidPacketNumber
+	if p.Ack != nil {
+		largestAcked = p.Ack.LargestAcked()
+	}
+	path.sentPacketHandler.SentPacket(
+		now,
+		p.PacketNumber,
+		largestAcked,
+		p.StreamFrames,
+		p.Frames,
+		protocol.Encryption1RTT,
+		ecn,
+		p.Length,
+		p.IsPathMTUProbePacket,
+		p.IsPathProbePacket,
+	)
+}
+
+func (c *Conn) maxPacketSizeOnPath(path *multipathPath) protocol.ByteCount {
+	if path.id == 0 {
+		return c.maxPacketSize()
+	}
+	return protocol.ByteCount(c.config.InitialPacketSize)
+}
+
+func (c *Conn) updatePacingDeadline(path *multipathPath) {
+	deadline := path.sentPacketHandler.TimeUntilSend()
+	if deadline.IsZero() {
+		deadline = deadlineSendImmediately
+	}
+	if c.pacingDeadline.IsZero() || deadline.Before(c.pacingDeadline) {
+		c.pacingDeadline = deadline
+	}
+}
+
+func (c *Conn) canSendOnPath(path *multipathPath, now monotime.Time) bool {
+	if path.id == 0 && c.sendQueue.WouldBlock() {
+		return false
+	}
+	switch path.sentPacketHandler.SendMode(now) {
+	case ackhandler.SendAny:
+		return true
+	case ackhandler.SendPacingLimited:
+		c.updatePacingDeadline(path)
+	}
+	return false
+}
+
+func (c *Conn) selectPath(paths []*multipathPath, infos []PathInfo) int {
+	scheduler := PathScheduler(minRTTPathScheduler{})
+	if s := c.pathScheduler.Load(); s != nil {
+		scheduler = *s
+	}
+	idx := scheduler.SelectPath(infos)
+	if idx < 0 || idx >= len(paths) || !infos[idx].CanSend {
+		return -1
+	}
+	return idx
+}
+
+// triggerSendingMultipath sends packets on all paths of a multipath connection.
+// The PathScheduler decides which path a packet is sent on.
+func (c *Conn) triggerSendingMultipath(now monotime.Time) error {
+	paths := c.multipath.allPaths()
+	// PTO probe packets are sent on the path that the PTO fired on.
+	for _, path := range paths {
+		for path.sentPacketHandler.SendMode(now) == ackhandler.SendPTOAppData {
+			if err := c.sendProbePacketOnPath(path, now); err != nil {
+				return err
+			}
+			if c.sendQueue.WouldBlock() {
+				c.scheduleSending()
+				return nil
+			}
+		}
+	}
+
+	if sent, err := c.maybeSendPathProbePacket(now); sent || err != nil {
+		return err
+	}
+	c.queueConnectionLevelFrames(now)
+
+	sendPaths := c.multipath.sendPaths[:0]
+	infos := c.multipath.pathInfos[:0]
+	var canSendOnInitialPath, canSendOnAnyPath bool
+	for _, path := range paths {
+		if !path.validated || path.abandoned {
+			continue
+		}
+		canSend := c.canSendOnPath(path, now)
+		if path.id == 0 {
+			canSendOnInitialPath = canSend
+		}
+		canSendOnAnyPath = canSendOnAnyPath || canSend
+		localAddr, remoteAddr := c.conn.LocalAddr(), c.conn.RemoteAddr()
+		if path.id != 0 {
+			remoteAddr = path.remoteAddr
+			if path.conn != nil {
+				localAddr = path.conn.LocalAddr()
+			}
+		}
+		sendPaths = append(sendPaths, path)
+		infos = append(infos, PathInfo{
+			ID:          path.id,
+			Status:      path.status(),
+			LocalAddr:   localAddr,
+			RemoteAddr:  remoteAddr,
+			SmoothedRTT: path.rttStats.SmoothedRTT(),
+			CanSend:     canSend,
+		})
+	}
+	c.multipath.sendPaths = sendPaths
+	c.multipath.pathInfos = infos
+
+	// Path MTU Discovery is only performed on the initial path.
+	if canSendOnInitialPath {
+		if sent, err := c.maybeSendMTUProbePacket(now); sent || err != nil {
+			return err
+		}
+	}
+
+	for {
+		idx := c.selectPath(sendPaths, infos)
+		if idx == -1 {
+			break
+		}
+		path := sendPaths[idx]
+		buf := getPacketBuffer()
+		ecn := path.sentPacketHandler.ECNMode(true)
+		p, err := path.packer.AppendPacket(buf, c.maxPacketSizeOnPath(path), now, c.version)
+		if err != nil {
+			buf.Release()
+			if err != errNothingToPack {
+				return err
+			}
+			for i, info := range infos {
+				if info.CanSend {
+					sendPaths[i].sentPacketHandler.SetAppLimited()
+				}
+			}
+			break
+		}
+		c.logShortHeaderPacketOnPath(path.id, p, ecn, buf.Len())
+		c.registerPackedShortHeaderPacketOnPath(path, p, ecn, now)
+		c.sendOnPath(path, buf, ecn)
+		infos[idx].CanSend = c.canSendOnPath(path, now)
+
+		// Prioritize receiving of packets over sending out more packets.
+		c.receivedPacketMx.Lock()
+		hasPackets := !c.receivedPackets.Empty()
+		c.receivedPacketMx.Unlock()
+		if hasPackets {
+			c.pacingDeadline = deadlineSendImmediately
+			return nil
+		}
+	}
+
+	// Send acknowledgments on all paths.
+	// This is a no-op for paths where acknowledgments were already sent along with other frames.
+	for _, path := range paths {
+		if path.id == 0 && c.sendQueue.WouldBlock() {
+			continue
+		}
+		if path.sentPacketHandler.SendMode(now) == ackhandler.SendNone {
+			continue
+		}
+		if err := c.maybeSendAckOnlyPacketOnPath(path, now); err != nil {
+			return err
+		}
+	}
+	if !canSendOnAnyPath && c.pacingDeadline.IsZero() {
+		c.blocked = blockModeCongestionLimited
+	}
+	return nil
+}
+
+func (c *Conn) maybeSendAckOnlyPacketOnPath(path *multipathPath, now monotime.Time) error {
+	if path.id == 0 {
+		return c.maybeSendAckOnlyPacket(now)
+	}
+	ecn := path.sentPacketHandler.ECNMode(true)
+	p, buf, err := path.packer.PackAckOnlyPacket(c.maxPacketSizeOnPath(path), now, c.version)
+	if err != nil {
+		if err == errNothingToPack {
+			return nil
+		}
+		return err
+	}
+	c.logShortHeaderPacketOnPath(path.id, p, ecn, buf.Len())
+	c.registerPackedShortHeaderPacketOnPath(path, p, ecn, now)
+	c.sendOnPath(path, buf, ecn)
+	return nil
+}
+
+func (c *Conn) sendProbePacketOnPath(path *multipathPath, now monotime.Time) error {
+	if path.id == 0 {
+		return c.sendProbePacket(ackhandler.SendPTOAppData, now)
+	}
+	c.maybeQueueImmediateAck()
+	var packet *coalescedPacket
+	for packet == nil {
+		if wasQueued := path.sentPacketHandler.QueueProbePacket(protocol.Encryption1RTT); !wasQueued {
+			break
+		}
+		var err error
+		packet, err = path.packer.PackPTOProbePacket(protocol.Encryption1RTT, c.maxPacketSizeOnPath(path), false, now, c.version)
+		if err != nil {
+			return err
+		}
+	}
+	if packet == nil {
+		var err error
+		packet, err = path.packer.PackPTOProbePacket(protocol.Encryption1RTT, c.maxPacketSizeOnPath(path), true, now, c.version)
+		if err != nil {
+			return err
+		}
+	}
+	if packet == nil || packet.shortHdrPacket == nil {
+		return fmt.Errorf("connection BUG: couldn't pack probe packet on path %d", path.id)
+	}
+	ecn := path.sentPacketHandler.ECNMode(true)
+	c.logShortHeaderPacketOnPath(path.id, *packet.shortHdrPacket, ecn, packet.buffer.Len())
+	c.registerPackedShortHeaderPacketOnPath(path, *packet.shortHdrPacket, ecn, now)
+	c.sendOnPath(path, packet.buffer, ecn)
+	return nil
+}
+
+// nextMultipathTimeout returns the earliest ACK alarm and loss detection timeout of all paths
+// other than the initial path.
+func (c *Conn) nextMultipathTimeout() monotime.Time {
+	var deadline monotime.Time
+	for _, path := range c.multipath.paths {
+		if t := path.receivedPacketHandler.GetAlarmTimeout(); !t.IsZero() && (deadline.IsZero() || t.Before(deadline)) {
+			deadline = t
+		}
+		if t := path.sentPacketHandler.GetLossDetectionTimeout(); !t.IsZero() && (deadline.IsZero() || t.Before(deadline)) {
+			deadline = t
+		}
+	}
+	return deadline
+}
+
+func (c *Conn) onMultipathLossDetectionTimeouts(now monotime.Time) error {
+	for _, path := range c.multipath.paths {
+		if timeout := path.sentPacketHandler.GetLossDetectionTimeout(); !timeout.IsZero() && !timeout.After(now) {
+			if err := path.sentPacketHandler.OnLossDetectionTimeout(now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
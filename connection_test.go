@@ -107,6 +107,10 @@ func newServerTestConnection(
 	sendConn.EXPECT().capabilities().Return(connCapabilities{GSO: gso}).AnyTimes()
 	sendConn.EXPECT().RemoteAddr().Return(remoteAddr).AnyTimes()
 	sendConn.EXPECT().LocalAddr().Return(localAddr).AnyTimes()
+	if config != nil && config.PreferredAddress != nil {
+		// the connection ID for the preferred address
+		connRunner.EXPECT().Add(gomock.Any(), gomock.Any()).MaxTimes(1)
+	}
 	packer := NewMockPacker(mockCtrl)
 	b := make([]byte, 12)
 	rand.Read(b)
@@ -133,6 +137,7 @@ func newServerTestConnection(
 		handshake.NewTokenGenerator(handshake.TokenProtectorKey{}),
 		false,
 		1337*time.Millisecond,
+		0,
 		nil,
 		utils.DefaultLogger,
 		protocol.Version1,
@@ -190,6 +195,8 @@ func newClientTestConnection(
 		populateConfig(config),
 		&tls.Config{ServerName: "quic-go.net"},
 		0,
+		0,
+		0,
 		enable0RTT,
 		false,
 		nil,
@@ -272,6 +279,45 @@ func TestConnectionServerInvalidFrames(t *testing.T) {
 	}
 }
 
+func TestConnectionHandleAckFrequencyFrames(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	rph := mockackhandler.NewMockReceivedPacketHandler(mockCtrl)
+	tc := newServerTestConnection(t,
+		mockCtrl,
+		&Config{EnableAckFrequency: true},
+		false,
+		connectionOptReceivedPacketHandler(rph),
+	)
+	connID := protocol.ConnectionID{}
+	now := monotime.Now()
+
+	f := &wire.AckFrequencyFrame{
+		SequenceNumber:        1,
+		AckElicitingThreshold: 5,
+		RequestMaxAckDelay:    10 * time.Millisecond,
+		ReorderingThreshold:   3,
+	}
+	rph.EXPECT().HandleAckFrequencyFrame(f)
+	_, err := tc.conn.handleFrame(f, protocol.Encryption1RTT, connID, now)
+	require.NoError(t, err)
+	// reordered frames are ignored
+	_, err = tc.conn.handleFrame(&wire.AckFrequencyFrame{SequenceNumber: 0, RequestMaxAckDelay: time.Millisecond}, protocol.Encryption1RTT, connID, now)
+	require.NoError(t, err)
+
+	rph.EXPECT().HandleImmediateAckFrame()
+	_, err = tc.conn.handleFrame(&wire.ImmediateAckFrame{}, protocol.Encryption1RTT, connID, now)
+	require.NoError(t, err)
+
+	// the requested max ack delay must not be smaller than our min_ack_delay
+	_, err = tc.conn.handleFrame(
+		&wire.AckFrequencyFrame{SequenceNumber: 2, RequestMaxAckDelay: protocol.MinAckDelay - 1},
+		protocol.Encryption1RTT,
+		connID,
+		now,
+	)
+	require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.ProtocolViolation})
+}
+
 func TestConnectionClose(t *testing.T) {
 	t.Run("transport error", func(t *testing.T) {
 		expectedErr := &qerr.TransportError{
@@ -587,6 +633,7 @@ func TestConnectionUnpacking(t *testing.T) {
 	wasProcessed, err := tc.conn.handleOnePacket(packet)
 	require.NoError(t, err)
 	require.True(t, wasProcessed)
+	require.EqualValues(t, 1, tc.conn.ConnectionStats().ECNCEReceived)
 	require.Equal(t,
 		[]qlogwriter.Event{
 			qlog.PacketReceived{
@@ -1069,7 +1116,7 @@ func TestConnectionTransportParameters(t *testing.T) {
 		MaxBidiStreamNum:                1,
 		MaxUniStreamNum:                 1,
 	}
-	require.NoError(t, tc.conn.handleTransportParameters(params))
+	require.NoError(t, tc.conn.handleTransportParameters(params, monotime.Now()))
 	require.Equal(t, protocol.ByteCount(1337), connFC.SendWindowSize())
 	_, err = tc.conn.OpenStream()
 	require.NoError(t, err)
@@ -1100,7 +1147,7 @@ func TestConnectionHandleMaxStreamsFrame(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		connFC := flowcontrol.NewConnectionFlowController(0, 0, nil, utils.NewRTTStats(), utils.DefaultLogger)
 		tc := newServerTestConnection(t, mockCtrl, nil, false, connectionOptConnFlowController(connFC))
-		tc.conn.handleTransportParameters(&wire.TransportParameters{})
+		tc.conn.handleTransportParameters(&wire.TransportParameters{}, monotime.Now())
 
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
@@ -1170,7 +1217,7 @@ func TestConnectionTransportParameterValidationFailureServer(t *testing.T) {
 	tc := newServerTestConnection(t, nil, nil, false)
 	err := tc.conn.handleTransportParameters(&wire.TransportParameters{
 		InitialSourceConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4}),
-	})
+	}, monotime.Now())
 	assert.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.TransportParameterError})
 	assert.ErrorContains(t, err, "expected initial_source_connection_id to equal")
 }
@@ -1180,7 +1227,7 @@ func TestConnectionTransportParameterValidationFailureClient(t *testing.T) {
 		tc := newClientTestConnection(t, nil, nil, false)
 		err := tc.conn.handleTransportParameters(&wire.TransportParameters{
 			InitialSourceConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4}),
-		})
+		}, monotime.Now())
 		assert.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.TransportParameterError})
 		assert.ErrorContains(t, err, "expected initial_source_connection_id to equal")
 	})
@@ -1190,7 +1237,7 @@ func TestConnectionTransportParameterValidationFailureClient(t *testing.T) {
 		err := tc.conn.handleTransportParameters(&wire.TransportParameters{
 			InitialSourceConnectionID:       tc.destConnID,
 			OriginalDestinationConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4}),
-		})
+		}, monotime.Now())
 		assert.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.TransportParameterError})
 		assert.ErrorContains(t, err, "expected original_destination_connection_id to equal")
 	})
@@ -1203,7 +1250,7 @@ func TestConnectionTransportParameterValidationFailureClient(t *testing.T) {
 			OriginalDestinationConnectionID: tc.destConnID,
 			RetrySourceConnectionID:         &rcid,
 		}
-		err := tc.conn.handleTransportParameters(params)
+		err := tc.conn.handleTransportParameters(params, monotime.Now())
 		assert.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.TransportParameterError})
 		assert.ErrorContains(t, err, "received retry_source_connection_id, although no Retry was performed")
 	})
@@ -1219,7 +1266,7 @@ func TestConnectionTransportParameterValidationFailureClient(t *testing.T) {
 			InitialSourceConnectionID:       tc.destConnID,
 			OriginalDestinationConnectionID: tc.destConnID,
 		}
-		err := tc.conn.handleTransportParameters(params)
+		err := tc.conn.handleTransportParameters(params, monotime.Now())
 		assert.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.TransportParameterError})
 		assert.ErrorContains(t, err, "missing retry_source_connection_id")
 	})
@@ -1237,7 +1284,7 @@ func TestConnectionTransportParameterValidationFailureClient(t *testing.T) {
 			OriginalDestinationConnectionID: tc.destConnID,
 			RetrySourceConnectionID:         &wrongCID,
 		}
-		err := tc.conn.handleTransportParameters(params)
+		err := tc.conn.handleTransportParameters(params, monotime.Now())
 		assert.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.TransportParameterError})
 		assert.ErrorContains(t, err, "expected retry_source_connection_id to equal")
 	})
@@ -1988,7 +2035,7 @@ func TestConnectionIdleTimeout(t *testing.T) {
 		const idleTimeout = 500 * time.Millisecond
 		require.NoError(t, tc.conn.handleTransportParameters(&wire.TransportParameters{
 			MaxIdleTimeout: idleTimeout,
-		}))
+		}, monotime.Now()))
 
 		sph.EXPECT().GetLossDetectionTimeout().AnyTimes()
 		sph.EXPECT().SendMode(gomock.Any()).Return(ackhandler.SendAny).AnyTimes()
@@ -2003,6 +2050,7 @@ func TestConnectionIdleTimeout(t *testing.T) {
 			},
 		)
 		tc.packer.EXPECT().AppendPacket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(shortHeaderPacket{}, errNothingToPack)
+		sph.EXPECT().SetAppLimited()
 		tc.sendConn.EXPECT().Write(gomock.Any(), gomock.Any(), gomock.Any())
 		tc.connRunner.EXPECT().Remove(gomock.Any()).AnyTimes()
 
@@ -2054,7 +2102,7 @@ func testConnectionKeepAlive(t *testing.T, enable, expectKeepAlive bool) {
 		const idleTimeout = 50 * time.Millisecond
 		require.NoError(t, tc.conn.handleTransportParameters(&wire.TransportParameters{
 			MaxIdleTimeout: idleTimeout,
-		}))
+		}, monotime.Now()))
 
 		// Receive a packet. This starts the keep-alive timer.
 		buf := getPacketBuffer()
@@ -2139,6 +2187,7 @@ func TestConnectionACKTimer(t *testing.T) {
 		sph.EXPECT().SendMode(gomock.Any()).Return(ackhandler.SendAny).AnyTimes()
 		sph.EXPECT().SentPacket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		sph.EXPECT().ECNMode(gomock.Any()).AnyTimes()
+		sph.EXPECT().SetAppLimited().AnyTimes()
 		rph.EXPECT().GetAlarmTimeout().Return(monotime.Now().Add(time.Hour))
 		tc.sendConn.EXPECT().Write(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
@@ -2216,6 +2265,7 @@ func TestConnectionGSOBatch(t *testing.T) {
 		sph.EXPECT().SentPacket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		sph.EXPECT().GetLossDetectionTimeout().AnyTimes()
 		sph.EXPECT().ECNMode(gomock.Any()).Return(protocol.ECT1).AnyTimes()
+		sph.EXPECT().SetAppLimited().AnyTimes()
 
 		maxPacketSize := tc.conn.maxPacketSize()
 		var expectedData []byte
@@ -2282,6 +2332,7 @@ func TestConnectionGSOBatchPacketSize(t *testing.T) {
 		sph.EXPECT().SentPacket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		sph.EXPECT().GetLossDetectionTimeout().AnyTimes()
 		sph.EXPECT().ECNMode(gomock.Any()).Return(protocol.ECT1).AnyTimes()
+		sph.EXPECT().SetAppLimited().AnyTimes()
 
 		maxPacketSize := tc.conn.maxPacketSize()
 		var expectedData []byte
@@ -2370,6 +2421,7 @@ func TestConnectionGSOBatchECN(t *testing.T) {
 		sph.EXPECT().SentPacket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		sph.EXPECT().GetLossDetectionTimeout().AnyTimes()
 		sph.EXPECT().ECNMode(gomock.Any()).DoAndReturn(func(bool) protocol.ECN { return ecnMode }).AnyTimes()
+		sph.EXPECT().SetAppLimited().AnyTimes()
 
 		// 3. Send a GSO batch, until the ECN marking changes.
 		var expectedData []byte
@@ -2637,6 +2689,7 @@ func testConnectionSendQueue(t *testing.T, enableGSO bool) {
 		sph.EXPECT().SentPacket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
 		sph.EXPECT().SendMode(gomock.Any()).Return(ackhandler.SendAny).AnyTimes()
 		sph.EXPECT().ECNMode(gomock.Any()).AnyTimes()
+		sph.EXPECT().SetAppLimited().AnyTimes()
 		tc.packer.EXPECT().AppendPacket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(
 			shortHeaderPacket{PacketNumber: protocol.PacketNumber(1)}, nil,
 		)
@@ -3226,7 +3279,7 @@ func testConnectionPathValidation(t *testing.T, isNATRebinding bool) {
 			connectionOptHandshakeConfirmed(),
 			connectionOptRTT(time.Second),
 		)
-		require.NoError(t, tc.conn.handleTransportParameters(&wire.TransportParameters{MaxUDPPayloadSize: 1456}))
+		require.NoError(t, tc.conn.handleTransportParameters(&wire.TransportParameters{MaxUDPPayloadSize: 1456}, monotime.Now()))
 
 		newRemoteAddr := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 1), Port: 1234}
 		require.NotEqual(t, tc.remoteAddr, newRemoteAddr)
@@ -3366,6 +3419,236 @@ func testConnectionPathValidation(t *testing.T, isNATRebinding bool) {
 	})
 }
 
+func TestConnectionPreferredAddress(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	unpacker := NewMockUnpacker(mockCtrl)
+	tr := &Transport{Conn: newUDPConnLocalhost(t), ConnectionIDLength: 6}
+	defer tr.Close()
+
+	tc := newServerTestConnection(
+		t,
+		mockCtrl,
+		&Config{
+			DisablePathMTUDiscovery: true,
+			PreferredAddress: &PreferredAddress{
+				IPv4:      tr.Conn.LocalAddr().(*net.UDPAddr).AddrPort(),
+				Transport: tr,
+			},
+		},
+		false,
+		connectionOptUnpacker(unpacker),
+		connectionOptHandshakeConfirmed(),
+	)
+	// the connection ID for the preferred address is issued when the connection is created
+	preferredAddressConnID, ok := tc.conn.connIDGenerator.activeSrcConnIDs[1]
+	require.True(t, ok)
+	// packets for this connection are accepted on the Transport of the preferred address
+	handler, ok := (*packetHandlerMap)(tr).Get(preferredAddressConnID)
+	require.True(t, ok)
+	require.IsType(t, &preferredAddressPacketHandler{}, handler)
+	_, ok = (*packetHandlerMap)(tr).Get(tc.srcConnID)
+	require.True(t, ok)
+	require.NoError(t, tc.conn.handleTransportParameters(&wire.TransportParameters{}, monotime.Now()))
+
+	tc.packer.EXPECT().AppendPacket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		shortHeaderPacket{}, errNothingToPack,
+	).AnyTimes()
+	errChan := make(chan error, 1)
+	go func() { errChan <- tc.conn.run() }()
+
+	// The client probes the preferred address, using the same client address.
+	// The server sends a PATH_CHALLENGE from the preferred address.
+	var pathChallenge *wire.PathChallengeFrame
+	probeSent := make(chan struct{})
+	gomock.InOrder(
+		unpacker.EXPECT().UnpackShortHeader(gomock.Any(), gomock.Any()).Return(
+			protocol.PacketNumber(10), protocol.PacketNumberLen2, protocol.KeyPhaseZero, []byte{1}, nil, // PING frame
+		),
+		tc.packer.EXPECT().PackPathProbePacket(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ protocol.ConnectionID, frames []ackhandler.Frame, _ protocol.Version) (shortHeaderPacket, *packetBuffer, error) {
+				defer close(probeSent)
+				pathChallenge = frames[0].Frame.(*wire.PathChallengeFrame)
+				buf := getPacketBuffer()
+				buf.Data = append(buf.Data, []byte("probe")...)
+				return shortHeaderPacket{IsPathProbePacket: true}, buf, nil
+			},
+		),
+	)
+	handler.handlePacket(receivedPacket{
+		data:       make([]byte, 10),
+		buffer:     getPacketBuffer(),
+		remoteAddr: tc.remoteAddr,
+		rcvTime:    monotime.Now(),
+	})
+	select {
+	case <-probeSent:
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	// The PATH_RESPONSE validates the path, and the server switches to the preferred address.
+	payload, err := (&wire.PathResponseFrame{Data: pathChallenge.Data}).Append([]byte{1}, protocol.Version1)
+	require.NoError(t, err)
+	unpacker.EXPECT().UnpackShortHeader(gomock.Any(), gomock.Any()).Return(
+		protocol.PacketNumber(11), protocol.PacketNumberLen2, protocol.KeyPhaseZero, payload, nil,
+	)
+	handler.handlePacket(receivedPacket{
+		data:       make([]byte, 100),
+		buffer:     getPacketBuffer(),
+		remoteAddr: tc.remoteAddr,
+		rcvTime:    monotime.Now(),
+	})
+	require.Eventually(t, func() bool {
+		return tc.conn.LocalAddr().String() == tr.Conn.LocalAddr().String()
+	}, time.Second, 5*time.Millisecond)
+
+	// teardown
+	tc.connRunner.EXPECT().Remove(gomock.Any()).AnyTimes()
+	tc.conn.destroy(nil)
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestConnectionPreferredAddressConnIDLengthMismatch(t *testing.T) {
+	tr := &Transport{Conn: newUDPConnLocalhost(t), ConnectionIDLength: 4}
+	defer tr.Close()
+	tc := newServerTestConnection(t, nil, &Config{
+		PreferredAddress: &PreferredAddress{
+			IPv4:      tr.Conn.LocalAddr().(*net.UDPAddr).AddrPort(),
+			Transport: tr,
+		},
+	}, false)
+	_, ok := (*packetHandlerMap)(tr).Get(tc.srcConnID)
+	require.False(t, ok)
+	require.Zero(t, tc.conn.connIDGenerator.highestSeq)
+}
+
+func TestConnectionPreferredAddressMigration(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		testConnectionPreferredAddressMigration(t, true)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		testConnectionPreferredAddressMigration(t, false)
+	})
+}
+
+func testConnectionPreferredAddressMigration(t *testing.T, enabled bool) {
+	mockCtrl := gomock.NewController(t)
+	cs := mocks.NewMockCryptoSetup(mockCtrl)
+	unpacker := NewMockUnpacker(mockCtrl)
+	var eventRecorder events.Recorder
+	tc := newClientTestConnection(
+		t,
+		mockCtrl,
+		&Config{DisablePathMTUDiscovery: true, DisablePreferredAddressMigration: !enabled},
+		false,
+		connectionOptCryptoSetup(cs),
+		connectionOptUnpacker(unpacker),
+		connectionOptTracer(&eventRecorder),
+		connectionOptHandshakeConfirmed(),
+	)
+	preferredAddr := netip.MustParseAddrPort("5.6.7.8:443")
+	preferredAddrConnID := protocol.ParseConnectionID([]byte{1, 2, 3, 4})
+	require.NoError(t, tc.conn.handleTransportParameters(&wire.TransportParameters{
+		InitialSourceConnectionID:       tc.destConnID,
+		OriginalDestinationConnectionID: tc.destConnID,
+		PreferredAddress: &wire.PreferredAddress{
+			IPv4:                preferredAddr,
+			IPv6:                netip.MustParseAddrPort("[2001:db8::1]:443"),
+			ConnectionID:        preferredAddrConnID,
+			StatelessResetToken: protocol.StatelessResetToken{1, 2, 3, 4},
+		},
+	}, monotime.Now()))
+	tc.conn.applyTransportParameters(monotime.Now())
+	eventRecorder.Clear()
+
+	// The client starts probing the preferred address once the handshake is confirmed.
+	cs.EXPECT().DiscardInitialKeys()
+	cs.EXPECT().SetHandshakeConfirmed()
+	require.NoError(t, tc.conn.handleHandshakeConfirmed(monotime.Now()))
+	if !enabled {
+		require.Nil(t, tc.conn.pathManagerOutgoing.Load())
+		require.Empty(t, eventRecorder.Events(qlog.MigrationStateUpdated{}))
+		return
+	}
+	require.NotNil(t, tc.conn.pathManagerOutgoing.Load())
+
+	tc.packer.EXPECT().AppendPacket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		shortHeaderPacket{}, errNothingToPack,
+	).AnyTimes()
+	var pathChallenge *wire.PathChallengeFrame
+	probeSent := make(chan struct{})
+	newRemoteAddr := net.UDPAddrFromAddrPort(preferredAddr)
+	tc.connRunner.EXPECT().AddResetToken(protocol.StatelessResetToken{1, 2, 3, 4}, gomock.Any())
+	tc.packer.EXPECT().PackPathProbePacket(preferredAddrConnID, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ protocol.ConnectionID, frames []ackhandler.Frame, _ protocol.Version) (shortHeaderPacket, *packetBuffer, error) {
+			pathChallenge = frames[0].Frame.(*wire.PathChallengeFrame)
+			return shortHeaderPacket{IsPathProbePacket: true}, getPacketBuffer(), nil
+		},
+	)
+	// The probe packet is sent to the preferred address.
+	tc.sendConn.EXPECT().WriteTo(gomock.Any(), newRemoteAddr).DoAndReturn(func([]byte, net.Addr) error {
+		close(probeSent)
+		return nil
+	})
+	cs.EXPECT().StartHandshake(gomock.Any())
+	cs.EXPECT().NextEvent().Return(handshake.Event{Kind: handshake.EventNoEvent}).AnyTimes()
+	errChan := make(chan error, 1)
+	go func() { errChan <- tc.conn.run() }()
+
+	select {
+	case <-probeSent:
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	// The PATH_RESPONSE validates the path, and the client switches to the preferred address.
+	migrated := make(chan struct{})
+	tc.sendConn.EXPECT().ChangeRemoteAddr(newRemoteAddr, gomock.Any()).Do(func(net.Addr, packetInfo) { close(migrated) })
+	payload, err := (&wire.PathResponseFrame{Data: pathChallenge.Data}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	unpacker.EXPECT().UnpackShortHeader(gomock.Any(), gomock.Any()).Return(
+		protocol.PacketNumber(10), protocol.PacketNumberLen2, protocol.KeyPhaseZero, payload, nil,
+	)
+	tc.conn.handlePacket(receivedPacket{
+		data:       make([]byte, 100),
+		buffer:     getPacketBuffer(),
+		remoteAddr: newRemoteAddr,
+		rcvTime:    monotime.Now(),
+	})
+	select {
+	case <-migrated:
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	// teardown
+	tc.connRunner.EXPECT().Remove(gomock.Any()).AnyTimes()
+	tc.connRunner.EXPECT().RemoveResetToken(gomock.Any()).AnyTimes()
+	cs.EXPECT().Close()
+	tc.conn.destroy(nil)
+	select {
+	case <-errChan:
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	remote := qlog.PathEndpointInfo{IPv4: preferredAddr}
+	require.Equal(t,
+		[]qlogwriter.Event{
+			qlog.MigrationStateUpdated{State: qlog.MigrationStateProbingStarted, Remote: remote},
+			qlog.MigrationStateUpdated{State: qlog.MigrationStateProbingSuccessful, Remote: remote},
+			qlog.MigrationStateUpdated{State: qlog.MigrationStateMigrationComplete, Remote: remote},
+		},
+		eventRecorder.Events(qlog.MigrationStateUpdated{}),
+	)
+}
+
 func TestConnectionMigrationServer(t *testing.T) {
 	tc := newServerTestConnection(t, nil, nil, false)
 	_, err := tc.conn.AddPath(&Transport{})
@@ -3389,7 +3672,7 @@ func testConnectionMigration(t *testing.T, enabled bool) {
 		InitialSourceConnectionID:       tc.destConnID,
 		OriginalDestinationConnectionID: tc.destConnID,
 		DisableActiveMigration:          !enabled,
-	}))
+	}, monotime.Now()))
 
 	tr := &Transport{
 		Conn:              newUDPConnLocalhost(t),
@@ -3,25 +3,127 @@ package quic
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/quic-go/quic-go/internal/ackhandler"
+	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/utils"
 	"github.com/quic-go/quic-go/internal/utils/ringbuffer"
 	"github.com/quic-go/quic-go/internal/wire"
 )
 
+// A DatagramDropPolicy determines which datagram is dropped when a datagram is added to a full queue.
+type DatagramDropPolicy uint8
+
+const (
+	// DatagramDropNewest drops the datagram that is added to the queue.
+	DatagramDropNewest DatagramDropPolicy = iota + 1
+	// DatagramDropOldest drops the oldest datagram in the queue, making room for the new datagram.
+	// A datagram that is currently being packed into a packet is not dropped.
+	DatagramDropOldest
+)
+
+// DatagramState is the delivery state of a datagram sent using Conn.SendDatagramWithFeedback.
+type DatagramState uint8
+
 const (
-	maxDatagramSendQueueLen = 32
-	maxDatagramRcvQueueLen  = 128
+	// DatagramStatePending means that the datagram is queued for sending, or that it was sent,
+	// but hasn't been acknowledged or declared lost yet.
+	DatagramStatePending DatagramState = iota
+	// DatagramStateAcknowledged means that the packet containing the datagram was acknowledged by the peer.
+	DatagramStateAcknowledged
+	// DatagramStateLost means that the packet containing the datagram was declared lost,
+	// or that the connection was closed before the packet was acknowledged.
+	// Datagrams are never retransmitted.
+	DatagramStateLost
+	// DatagramStateExpired means that the datagram's deadline passed before it could be sent.
+	DatagramStateExpired
+	// DatagramStateDropped means that the datagram was discarded without being sent,
+	// either because the maximum packet size decreased, or because the connection was closed.
+	DatagramStateDropped
 )
 
+func (s DatagramState) String() string {
+	switch s {
+	case DatagramStatePending:
+		return "pending"
+	case DatagramStateAcknowledged:
+		return "acknowledged"
+	case DatagramStateLost:
+		return "lost"
+	case DatagramStateExpired:
+		return "expired"
+	case DatagramStateDropped:
+		return "dropped"
+	default:
+		return "unknown datagram state"
+	}
+}
+
+// SendDatagramOptions are options for Conn.SendDatagramWithFeedback.
+type SendDatagramOptions struct {
+	// Deadline is the time after which the datagram is discarded if it hasn't been sent yet.
+	// This prevents sending of stale data, for example when sending is blocked by congestion control.
+	// The zero value means no deadline.
+	Deadline time.Time
+}
+
+// A DatagramHandle reports the delivery state of a datagram.
+type DatagramHandle struct {
+	done  chan struct{}
+	state DatagramState
+}
+
+func newDatagramHandle() *DatagramHandle {
+	return &DatagramHandle{done: make(chan struct{})}
+}
+
+// Done returns a channel that is closed once the final state of the datagram is known.
+func (h *DatagramHandle) Done() <-chan struct{} { return h.done }
+
+// State returns the delivery state of the datagram.
+// It returns DatagramStatePending until the channel returned by Done is closed.
+func (h *DatagramHandle) State() DatagramState {
+	select {
+	case <-h.done:
+		return h.state
+	default:
+		return DatagramStatePending
+	}
+}
+
+// must be called at most once
+func (h *DatagramHandle) complete(s DatagramState) {
+	h.state = s
+	close(h.done)
+}
+
+type queuedDatagram struct {
+	frame    *wire.DatagramFrame
+	deadline monotime.Time   // zero if no deadline was set
+	handle   *DatagramHandle // nil if no feedback was requested
+}
+
 type datagramQueue struct {
 	sendMx    sync.Mutex
-	sendQueue ringbuffer.RingBuffer[*wire.DatagramFrame]
-	sent      chan struct{} // used to notify Add that a datagram was dequeued
+	sendQueue ringbuffer.RingBuffer[queuedDatagram]
+	// the DATAGRAM frame returned by Peek, it is not part of the sendQueue anymore,
+	// but still counts towards the queue length
+	peeked         *queuedDatagram
+	sendQueueLen   int
+	sendDropPolicy DatagramDropPolicy // 0 if Add blocks when the queue is full
+	sent           chan struct{}      // used to notify Add that a datagram was dequeued
+	// handles of DATAGRAM frames that were sent, but not yet acknowledged or declared lost
+	inFlight map[*wire.DatagramFrame]*DatagramHandle
+
+	rcvMx         sync.Mutex
+	rcvQueue      [][]byte
+	rcvQueueLen   int
+	rcvDropPolicy DatagramDropPolicy
+	rcvd          chan struct{} // used to notify Receive that a new datagram was received
 
-	rcvMx    sync.Mutex
-	rcvQueue [][]byte
-	rcvd     chan struct{} // used to notify Receive that a new datagram was received
+	numSent, numReceived, numSendDropped, numReceiveDropped, numTooLarge atomic.Uint64
 
 	closeErr error
 	closed   chan struct{}
@@ -31,29 +133,80 @@ type datagramQueue struct {
 	logger utils.Logger
 }
 
-func newDatagramQueue(hasData func(), logger utils.Logger) *datagramQueue {
+// newDatagramQueue creates a new datagramQueue.
+// The config needs to be populated.
+func newDatagramQueue(hasData func(), conf *Config, logger utils.Logger) *datagramQueue {
+	rcvDropPolicy := conf.DatagramReceiveDropPolicy
+	if rcvDropPolicy == 0 {
+		rcvDropPolicy = DatagramDropNewest
+	}
 	return &datagramQueue{
-		hasData: hasData,
-		rcvd:    make(chan struct{}, 1),
-		sent:    make(chan struct{}, 1),
-		closed:  make(chan struct{}),
-		logger:  logger,
+		hasData:        hasData,
+		sendQueueLen:   conf.DatagramSendQueueLen,
+		sendDropPolicy: conf.DatagramSendDropPolicy,
+		rcvQueueLen:    conf.DatagramReceiveQueueLen,
+		rcvDropPolicy:  rcvDropPolicy,
+		rcvd:           make(chan struct{}, 1),
+		sent:           make(chan struct{}, 1),
+		closed:         make(chan struct{}),
+		logger:         logger,
 	}
 }
 
 // Add queues a new DATAGRAM frame for sending.
-// Up to 32 DATAGRAM frames will be queued.
-// Once that limit is reached, Add blocks until the queue size has reduced.
+// If the send queue is full, the send drop policy is applied.
+// If no drop policy is configured, Add blocks until the queue size has reduced.
 func (h *datagramQueue) Add(f *wire.DatagramFrame) error {
+	return h.add(queuedDatagram{frame: f})
+}
+
+// AddWithHandle queues a new DATAGRAM frame for sending, like Add.
+// The handle is completed once the delivery state of the DATAGRAM frame is known.
+func (h *datagramQueue) AddWithHandle(f *wire.DatagramFrame, deadline monotime.Time, handle *DatagramHandle) error {
+	if !deadline.IsZero() && !monotime.Now().Before(deadline) {
+		h.numSendDropped.Add(1)
+		handle.complete(DatagramStateExpired)
+		return nil
+	}
+	return h.add(queuedDatagram{frame: f, deadline: deadline, handle: handle})
+}
+
+func (h *datagramQueue) add(d queuedDatagram) error {
 	h.sendMx.Lock()
 
 	for {
-		if h.sendQueue.Len() < maxDatagramSendQueueLen {
-			h.sendQueue.PushBack(f)
+		select {
+		case <-h.closed:
+			h.sendMx.Unlock()
+			return h.closeErr
+		default:
+		}
+		if h.queueLenLocked() < h.sendQueueLen {
+			h.sendQueue.PushBack(d)
 			h.sendMx.Unlock()
 			h.hasData()
 			return nil
 		}
+		switch h.sendDropPolicy {
+		case DatagramDropNewest:
+			h.sendMx.Unlock()
+			h.dropUnsent(d)
+			return nil
+		case DatagramDropOldest:
+			// The peeked DATAGRAM frame might already be packed into a packet.
+			// It can't be dropped anymore.
+			if h.sendQueue.Empty() {
+				h.sendMx.Unlock()
+				h.dropUnsent(d)
+				return nil
+			}
+			dropped := h.popLocked()
+			h.sendQueue.PushBack(d)
+			h.sendMx.Unlock()
+			h.dropUnsent(dropped)
+			h.hasData()
+			return nil
+		}
 		select {
 		case <-h.sent: // drain the queue so we don't loop immediately
 		default:
@@ -68,44 +221,153 @@ func (h *datagramQueue) Add(f *wire.DatagramFrame) error {
 	}
 }
 
+func (h *datagramQueue) queueLenLocked() int {
+	if h.peeked != nil {
+		return h.sendQueue.Len() + 1
+	}
+	return h.sendQueue.Len()
+}
+
 // Peek gets the next DATAGRAM frame for sending.
 // If actually sent out, Pop needs to be called before the next call to Peek.
+// DATAGRAM frames whose deadline has passed are discarded.
 func (h *datagramQueue) Peek() *wire.DatagramFrame {
 	h.sendMx.Lock()
 	defer h.sendMx.Unlock()
-	if h.sendQueue.Empty() {
-		return nil
+
+	var now monotime.Time
+	for {
+		if h.peeked == nil {
+			if h.sendQueue.Empty() {
+				return nil
+			}
+			d := h.sendQueue.PopFront()
+			h.peeked = &d
+		}
+		d := h.peeked
+		if d.deadline.IsZero() {
+			return d.frame
+		}
+		if now.IsZero() {
+			now = monotime.Now()
+		}
+		if now.Before(d.deadline) {
+			return d.frame
+		}
+		h.popPeekedLocked()
+		h.numSendDropped.Add(1)
+		d.handle.complete(DatagramStateExpired)
+		if h.logger.Debug() {
+			h.logger.Debugf("Discarding expired DATAGRAM frame (%d bytes payload)", len(d.frame.Data))
+		}
 	}
-	return h.sendQueue.PeekFront()
 }
 
-func (h *datagramQueue) Pop() {
+// Pop dequeues the DATAGRAM frame returned by Peek, since it is being sent.
+// It returns the handler that needs to be notified when the frame is acknowledged or lost,
+// or nil if no feedback was requested for this frame.
+func (h *datagramQueue) Pop() ackhandler.FrameHandler {
 	h.sendMx.Lock()
 	defer h.sendMx.Unlock()
-	_ = h.sendQueue.PopFront()
+	d := h.popPeekedLocked()
+	h.numSent.Add(1)
+	if d.handle == nil {
+		return nil
+	}
+	if h.inFlight == nil {
+		h.inFlight = make(map[*wire.DatagramFrame]*DatagramHandle)
+	}
+	h.inFlight[d.frame] = d.handle
+	return h
+}
+
+// Discard dequeues the DATAGRAM frame returned by Peek without sending it.
+func (h *datagramQueue) Discard() {
+	h.sendMx.Lock()
+	d := h.popPeekedLocked()
+	h.sendMx.Unlock()
+	h.dropUnsent(d)
+}
+
+func (h *datagramQueue) dropUnsent(d queuedDatagram) {
+	h.numSendDropped.Add(1)
+	if d.handle != nil {
+		d.handle.complete(DatagramStateDropped)
+	}
+	if h.logger.Debug() {
+		h.logger.Debugf("Discarding DATAGRAM frame (%d bytes payload)", len(d.frame.Data))
+	}
+}
+
+// popPeekedLocked dequeues the DATAGRAM frame returned by Peek.
+// If Peek wasn't called, it dequeues the oldest DATAGRAM frame.
+func (h *datagramQueue) popPeekedLocked() queuedDatagram {
+	if h.peeked == nil {
+		return h.popLocked()
+	}
+	d := *h.peeked
+	h.peeked = nil
+	h.notifySent()
+	return d
+}
+
+// popLocked dequeues the oldest DATAGRAM frame that wasn't returned by Peek.
+func (h *datagramQueue) popLocked() queuedDatagram {
+	d := h.sendQueue.PopFront()
+	h.notifySent()
+	return d
+}
+
+func (h *datagramQueue) notifySent() {
 	select {
 	case h.sent <- struct{}{}:
 	default:
 	}
 }
 
+func (h *datagramQueue) OnAcked(f wire.Frame) { h.completeInFlight(f, DatagramStateAcknowledged) }
+
+func (h *datagramQueue) OnLost(f wire.Frame) { h.completeInFlight(f, DatagramStateLost) }
+
+func (h *datagramQueue) completeInFlight(f wire.Frame, state DatagramState) {
+	df := f.(*wire.DatagramFrame)
+	h.sendMx.Lock()
+	handle, ok := h.inFlight[df]
+	delete(h.inFlight, df)
+	h.sendMx.Unlock()
+	if ok {
+		handle.complete(state)
+	}
+}
+
 // HandleDatagramFrame handles a received DATAGRAM frame.
 func (h *datagramQueue) HandleDatagramFrame(f *wire.DatagramFrame) {
+	h.numReceived.Add(1)
 	data := make([]byte, len(f.Data))
 	copy(data, f.Data)
-	var queued bool
+	var droppedLen int // the payload size of the dropped datagram
 	h.rcvMx.Lock()
-	if len(h.rcvQueue) < maxDatagramRcvQueueLen {
+	full := len(h.rcvQueue) >= h.rcvQueueLen
+	if !full || h.rcvDropPolicy == DatagramDropOldest {
+		if full {
+			droppedLen = len(h.rcvQueue[0])
+			h.rcvQueue[0] = nil
+			h.rcvQueue = h.rcvQueue[1:]
+		}
 		h.rcvQueue = append(h.rcvQueue, data)
-		queued = true
 		select {
 		case h.rcvd <- struct{}{}:
 		default:
 		}
+	} else {
+		droppedLen = len(data)
 	}
 	h.rcvMx.Unlock()
-	if !queued && h.logger.Debug() {
-		h.logger.Debugf("Discarding received DATAGRAM frame (%d bytes payload)", len(f.Data))
+	if full {
+		h.numReceiveDropped.Add(1)
+		if h.logger.Debug() {
+			h.logger.Debugf("Discarding received DATAGRAM frame (%d bytes payload)", droppedLen)
+		}
 	}
 }
 
@@ -131,7 +393,41 @@ func (h *datagramQueue) Receive(ctx context.Context) ([]byte, error) {
 	}
 }
 
+// RecordTooLarge records that a datagram was rejected because it was too large.
+func (h *datagramQueue) RecordTooLarge() { h.numTooLarge.Add(1) }
+
+type datagramStats struct {
+	Sent, Received, SendDropped, ReceiveDropped, TooLarge uint64
+}
+
+func (h *datagramQueue) Stats() datagramStats {
+	return datagramStats{
+		Sent:           h.numSent.Load(),
+		Received:       h.numReceived.Load(),
+		SendDropped:    h.numSendDropped.Load(),
+		ReceiveDropped: h.numReceiveDropped.Load(),
+		TooLarge:       h.numTooLarge.Load(),
+	}
+}
+
 func (h *datagramQueue) CloseWithError(e error) {
+	h.sendMx.Lock()
 	h.closeErr = e
 	close(h.closed)
+	if h.peeked != nil {
+		if h.peeked.handle != nil {
+			h.peeked.handle.complete(DatagramStateDropped)
+		}
+		h.peeked = nil
+	}
+	for !h.sendQueue.Empty() {
+		if d := h.sendQueue.PopFront(); d.handle != nil {
+			d.handle.complete(DatagramStateDropped)
+		}
+	}
+	for _, handle := range h.inFlight {
+		handle.complete(DatagramStateLost)
+	}
+	h.inFlight = nil
+	h.sendMx.Unlock()
 }
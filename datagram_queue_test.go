@@ -3,7 +3,10 @@ package quic
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/synctest"
 	"github.com/quic-go/quic-go/internal/utils"
 	"github.com/quic-go/quic-go/internal/wire"
@@ -14,7 +17,7 @@ import (
 
 func TestDatagramQueuePeekAndPop(t *testing.T) {
 	var queued []struct{}
-	queue := newDatagramQueue(func() { queued = append(queued, struct{}{}) }, utils.DefaultLogger)
+	queue := newDatagramQueue(func() { queued = append(queued, struct{}{}) }, populateConfig(nil), utils.DefaultLogger)
 	require.Nil(t, queue.Peek())
 	require.Empty(t, queued)
 	require.NoError(t, queue.Add(&wire.DatagramFrame{Data: []byte("foo")}))
@@ -28,9 +31,9 @@ func TestDatagramQueuePeekAndPop(t *testing.T) {
 
 func TestDatagramQueueSendQueueLength(t *testing.T) {
 	synctest.Test(t, func(t *testing.T) {
-		queue := newDatagramQueue(func() {}, utils.DefaultLogger)
+		queue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
 
-		for range maxDatagramSendQueueLen {
+		for range protocol.DefaultDatagramSendQueueLen {
 			require.NoError(t, queue.Add(&wire.DatagramFrame{Data: []byte{0}}))
 		}
 		errChan := make(chan error, 1)
@@ -63,7 +66,7 @@ func TestDatagramQueueSendQueueLength(t *testing.T) {
 			t.Fatal("timeout")
 		}
 		// pop all the remaining datagrams
-		for range maxDatagramSendQueueLen - 1 {
+		for range protocol.DefaultDatagramSendQueueLen - 1 {
 			queue.Pop()
 		}
 		f := queue.Peek()
@@ -73,7 +76,7 @@ func TestDatagramQueueSendQueueLength(t *testing.T) {
 }
 
 func TestDatagramQueueReceive(t *testing.T) {
-	queue := newDatagramQueue(func() {}, utils.DefaultLogger)
+	queue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
 
 	// receive frames that were received earlier
 	queue.HandleDatagramFrame(&wire.DatagramFrame{Data: []byte("foo")})
@@ -88,7 +91,7 @@ func TestDatagramQueueReceive(t *testing.T) {
 
 func TestDatagramQueueReceiveBlocking(t *testing.T) {
 	synctest.Test(t, func(t *testing.T) {
-		queue := newDatagramQueue(func() {}, utils.DefaultLogger)
+		queue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
 
 		// block until a new frame is received
 		type result struct {
@@ -147,9 +150,9 @@ func TestDatagramQueueReceiveBlocking(t *testing.T) {
 
 func TestDatagramQueueClose(t *testing.T) {
 	synctest.Test(t, func(t *testing.T) {
-		queue := newDatagramQueue(func() {}, utils.DefaultLogger)
+		queue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
 
-		for range maxDatagramSendQueueLen {
+		for range protocol.DefaultDatagramSendQueueLen {
 			require.NoError(t, queue.Add(&wire.DatagramFrame{Data: []byte{0}}))
 		}
 		errChan1 := make(chan error, 1)
@@ -178,3 +181,231 @@ func TestDatagramQueueClose(t *testing.T) {
 		}
 	})
 }
+
+func TestDatagramQueueFeedback(t *testing.T) {
+	queue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
+
+	// no handler is returned if no feedback was requested
+	require.NoError(t, queue.Add(&wire.DatagramFrame{Data: []byte("foo")}))
+	require.NotNil(t, queue.Peek())
+	require.Nil(t, queue.Pop())
+
+	h1 := newDatagramHandle()
+	h2 := newDatagramHandle()
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("foo")}, 0, h1))
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("bar")}, 0, h2))
+	f1 := queue.Peek()
+	handler1 := queue.Pop()
+	require.NotNil(t, handler1)
+	f2 := queue.Peek()
+	handler2 := queue.Pop()
+	require.NotNil(t, handler2)
+	require.Equal(t, DatagramStatePending, h1.State())
+	require.Equal(t, DatagramStatePending, h2.State())
+
+	handler1.OnAcked(f1)
+	handler2.OnLost(f2)
+	select {
+	case <-h1.Done():
+	default:
+		t.Fatal("expected handle to be completed")
+	}
+	require.Equal(t, DatagramStateAcknowledged, h1.State())
+	require.Equal(t, DatagramStateLost, h2.State())
+}
+
+func TestDatagramQueueDeadline(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		queue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
+
+		// the deadline already passed
+		h := newDatagramHandle()
+		require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("foo")}, monotime.Now().Add(-time.Second), h))
+		require.Equal(t, DatagramStateExpired, h.State())
+		require.Nil(t, queue.Peek())
+
+		h1 := newDatagramHandle()
+		h2 := newDatagramHandle()
+		require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("foo")}, monotime.Now().Add(time.Second), h1))
+		require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("bar")}, monotime.Now().Add(2*time.Second), h2))
+		require.NoError(t, queue.Add(&wire.DatagramFrame{Data: []byte("baz")}))
+
+		time.Sleep(1500 * time.Millisecond)
+		require.Equal(t, &wire.DatagramFrame{Data: []byte("bar")}, queue.Peek())
+		require.Equal(t, DatagramStateExpired, h1.State())
+		require.Equal(t, DatagramStatePending, h2.State())
+
+		time.Sleep(time.Second)
+		require.Equal(t, &wire.DatagramFrame{Data: []byte("baz")}, queue.Peek())
+		require.Equal(t, DatagramStateExpired, h2.State())
+	})
+}
+
+func TestDatagramQueueDiscard(t *testing.T) {
+	queue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
+	h := newDatagramHandle()
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("foo")}, 0, h))
+	require.NotNil(t, queue.Peek())
+	queue.Discard()
+	require.Nil(t, queue.Peek())
+	require.Equal(t, DatagramStateDropped, h.State())
+}
+
+func TestDatagramQueueCloseFeedback(t *testing.T) {
+	queue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
+	hInFlight := newDatagramHandle()
+	hQueued := newDatagramHandle()
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("foo")}, 0, hInFlight))
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("bar")}, 0, hQueued))
+	f := queue.Peek()
+	handler := queue.Pop()
+
+	queue.CloseWithError(assert.AnError)
+	require.Equal(t, DatagramStateLost, hInFlight.State())
+	require.Equal(t, DatagramStateDropped, hQueued.State())
+	// the sent packet handler might still report the frame as acknowledged
+	handler.OnAcked(f)
+	require.Equal(t, DatagramStateLost, hInFlight.State())
+
+	// datagrams can't be queued after the queue was closed
+	require.ErrorIs(t, queue.Add(&wire.DatagramFrame{Data: []byte("foo")}), assert.AnError)
+	require.ErrorIs(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("foo")}, 0, newDatagramHandle()), assert.AnError)
+}
+
+func TestDatagramQueueSendDropPolicy(t *testing.T) {
+	t.Run("drop newest", func(t *testing.T) {
+		testDatagramQueueSendDropPolicy(t, DatagramDropNewest)
+	})
+	t.Run("drop oldest", func(t *testing.T) {
+		testDatagramQueueSendDropPolicy(t, DatagramDropOldest)
+	})
+}
+
+func testDatagramQueueSendDropPolicy(t *testing.T, policy DatagramDropPolicy) {
+	var queued int
+	queue := newDatagramQueue(
+		func() { queued++ },
+		populateConfig(&Config{DatagramSendQueueLen: 2, DatagramSendDropPolicy: policy}),
+		utils.DefaultLogger,
+	)
+	handles := make([]*DatagramHandle, 3)
+	for i := range handles {
+		handles[i] = newDatagramHandle()
+		// doesn't block when the queue is full
+		require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte{byte(i)}}, 0, handles[i]))
+	}
+
+	switch policy {
+	case DatagramDropNewest:
+		require.Equal(t, 2, queued)
+		require.Equal(t, DatagramStateDropped, handles[2].State())
+		require.Equal(t, &wire.DatagramFrame{Data: []byte{0}}, queue.Peek())
+		queue.Pop()
+		require.Equal(t, &wire.DatagramFrame{Data: []byte{1}}, queue.Peek())
+	case DatagramDropOldest:
+		require.Equal(t, 3, queued)
+		require.Equal(t, DatagramStateDropped, handles[0].State())
+		require.Equal(t, &wire.DatagramFrame{Data: []byte{1}}, queue.Peek())
+		queue.Pop()
+		require.Equal(t, &wire.DatagramFrame{Data: []byte{2}}, queue.Peek())
+	}
+	queue.Pop()
+	require.Nil(t, queue.Peek())
+	require.Equal(t, datagramStats{Sent: 2, SendDropped: 1}, queue.Stats())
+}
+
+func TestDatagramQueueSendDropOldestPeeked(t *testing.T) {
+	queue := newDatagramQueue(
+		func() {},
+		populateConfig(&Config{DatagramSendQueueLen: 2, DatagramSendDropPolicy: DatagramDropOldest}),
+		utils.DefaultLogger,
+	)
+	handles := make([]*DatagramHandle, 3)
+	for i := range handles {
+		handles[i] = newDatagramHandle()
+	}
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte{0}}, 0, handles[0]))
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte{1}}, 0, handles[1]))
+
+	// the packer peeks the oldest DATAGRAM frame...
+	peeked := queue.Peek()
+	require.Equal(t, &wire.DatagramFrame{Data: []byte{0}}, peeked)
+	// ... while the application adds a new one to the full queue
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte{2}}, 0, handles[2]))
+	// the peeked frame is not dropped, but the one after it
+	require.Equal(t, DatagramStatePending, handles[0].State())
+	require.Equal(t, DatagramStateDropped, handles[1].State())
+	require.Equal(t, peeked, queue.Peek())
+	fh := queue.Pop()
+	require.NotNil(t, fh)
+	fh.OnAcked(peeked)
+	require.Equal(t, DatagramStateAcknowledged, handles[0].State())
+	require.Equal(t, &wire.DatagramFrame{Data: []byte{2}}, queue.Peek())
+	queue.Pop()
+	require.Nil(t, queue.Peek())
+	require.Equal(t, datagramStats{Sent: 2, SendDropped: 1}, queue.Stats())
+}
+
+func TestDatagramQueueSendDropOldestOnlyPeeked(t *testing.T) {
+	queue := newDatagramQueue(
+		func() {},
+		populateConfig(&Config{DatagramSendQueueLen: 1, DatagramSendDropPolicy: DatagramDropOldest}),
+		utils.DefaultLogger,
+	)
+	h1 := newDatagramHandle()
+	h2 := newDatagramHandle()
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte{1}}, 0, h1))
+	require.Equal(t, &wire.DatagramFrame{Data: []byte{1}}, queue.Peek())
+	// the peeked DATAGRAM frame can't be dropped, so the new DATAGRAM frame is dropped
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte{2}}, 0, h2))
+	require.Equal(t, DatagramStatePending, h1.State())
+	require.Equal(t, DatagramStateDropped, h2.State())
+	require.NotNil(t, queue.Pop())
+	require.Nil(t, queue.Peek())
+}
+
+func TestDatagramQueueReceiveDropPolicy(t *testing.T) {
+	t.Run("drop newest", func(t *testing.T) {
+		queue := newDatagramQueue(func() {}, populateConfig(&Config{DatagramReceiveQueueLen: 2}), utils.DefaultLogger)
+		for i := range 3 {
+			queue.HandleDatagramFrame(&wire.DatagramFrame{Data: []byte{byte(i)}})
+		}
+		for i := range 2 {
+			data, err := queue.Receive(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, []byte{byte(i)}, data)
+		}
+		require.Equal(t, datagramStats{Received: 3, ReceiveDropped: 1}, queue.Stats())
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		queue := newDatagramQueue(
+			func() {},
+			populateConfig(&Config{DatagramReceiveQueueLen: 2, DatagramReceiveDropPolicy: DatagramDropOldest}),
+			utils.DefaultLogger,
+		)
+		for i := range 3 {
+			queue.HandleDatagramFrame(&wire.DatagramFrame{Data: []byte{byte(i)}})
+		}
+		for i := range 2 {
+			data, err := queue.Receive(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, []byte{byte(i + 1)}, data)
+		}
+		require.Equal(t, datagramStats{Received: 3, ReceiveDropped: 1}, queue.Stats())
+	})
+}
+
+func TestDatagramQueueStats(t *testing.T) {
+	queue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
+	require.NoError(t, queue.Add(&wire.DatagramFrame{Data: []byte("foo")}))
+	require.NoError(t, queue.Add(&wire.DatagramFrame{Data: []byte("bar")}))
+	require.NoError(t, queue.AddWithHandle(&wire.DatagramFrame{Data: []byte("baz")}, monotime.Now().Add(-time.Second), newDatagramHandle()))
+	queue.Peek()
+	queue.Pop()
+	queue.Peek()
+	queue.Discard()
+	queue.RecordTooLarge()
+	queue.HandleDatagramFrame(&wire.DatagramFrame{Data: []byte("foobar")})
+	require.Equal(t, datagramStats{Sent: 1, Received: 1, SendDropped: 2, TooLarge: 1}, queue.Stats())
+}
@@ -3,6 +3,7 @@ package quic
 import (
 	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/quic-go/quic-go/internal/ackhandler"
 	"github.com/quic-go/quic-go/internal/flowcontrol"
@@ -23,18 +24,30 @@ const (
 const maxStreamControlFrameSize = 25
 
 type streamFrameGetter interface {
-	popStreamFrame(protocol.ByteCount, protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool)
+	popStreamFrame(protocol.ByteCount, monotime.Time, protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool)
 }
 
 type streamControlFrameGetter interface {
 	getControlFrame(monotime.Time) (_ ackhandler.Frame, ok, hasMore bool)
 }
 
+type activeStream struct {
+	str      streamFrameGetter
+	priority StreamPriority
+}
+
 type framer struct {
 	mutex sync.Mutex
 
-	activeStreams            map[protocol.StreamID]streamFrameGetter
-	streamQueue              ringbuffer.RingBuffer[protocol.StreamID]
+	activeStreams map[protocol.StreamID]activeStream
+	// There's one queue per urgency.
+	// Streams are served in strict priority order: a queue is only served if all queues
+	// of a more urgent (lower) urgency are empty.
+	streamQueues [MaxStreamUrgency + 1]ringbuffer.RingBuffer[protocol.StreamID]
+	// For every stream, a bitmask of the streamQueues that contain an entry for this stream.
+	// This includes stale entries of streams that were removed, or whose urgency changed,
+	// and prevents a stream from being contained in the same queue twice.
+	queuedStreams            map[protocol.StreamID]uint8
 	streamsWithControlFrames map[protocol.StreamID]streamControlFrameGetter
 
 	controlFrameMutex          sync.Mutex
@@ -42,11 +55,15 @@ type framer struct {
 	pathResponses              []*wire.PathResponseFrame
 	connFlowController         flowcontrol.ConnectionFlowController
 	queuedTooManyControlFrames bool
+
+	numStreamDataBlocked atomic.Uint64
+	numDataBlocked       atomic.Uint64
 }
 
 func newFramer(connFlowController flowcontrol.ConnectionFlowController) *framer {
 	return &framer{
-		activeStreams:            make(map[protocol.StreamID]streamFrameGetter),
+		activeStreams:            make(map[protocol.StreamID]activeStream),
+		queuedStreams:            make(map[protocol.StreamID]uint8),
 		streamsWithControlFrames: make(map[protocol.StreamID]streamControlFrameGetter),
 		connFlowController:       connFlowController,
 	}
@@ -54,7 +71,7 @@ func newFramer(connFlowController flowcontrol.ConnectionFlowController) *framer
 
 func (f *framer) HasData() bool {
 	f.mutex.Lock()
-	hasData := !f.streamQueue.Empty()
+	hasData := len(f.activeStreams) > 0
 	f.mutex.Unlock()
 	if hasData {
 		return true
@@ -101,12 +118,15 @@ func (f *framer) Append(
 	var streamFrameLen protocol.ByteCount
 	f.mutex.Lock()
 	// pop STREAM frames, until less than 128 bytes are left in the packet
-	numActiveStreams := f.streamQueue.Len()
+	var numActiveStreams int
+	for i := range f.streamQueues {
+		numActiveStreams += f.streamQueues[i].Len()
+	}
 	for i := 0; i < numActiveStreams; i++ {
 		if protocol.MinStreamFrameSize > maxLen {
 			break
 		}
-		sf, blocked := f.getNextStreamFrame(maxLen, v)
+		sf, blocked := f.getNextStreamFrame(maxLen, now, v)
 		if sf.Frame != nil {
 			streamFrames = append(streamFrames, sf)
 			maxLen -= sf.Frame.Length(v)
@@ -116,6 +136,7 @@ func (f *framer) Append(
 		// If the stream just became blocked on stream flow control, attempt to pack the
 		// STREAM_DATA_BLOCKED into the same packet.
 		if blocked != nil {
+			f.numStreamDataBlocked.Add(1)
 			l := blocked.Length(v)
 			// In case it doesn't fit, queue it for the next packet.
 			if maxLen < l {
@@ -130,6 +151,7 @@ func (f *framer) Append(
 
 	// The only way to become blocked on connection-level flow control is by sending STREAM frames.
 	if isBlocked, offset := f.connFlowController.IsNewlyBlocked(); isBlocked {
+		f.numDataBlocked.Add(1)
 		blocked := &wire.DataBlockedFrame{MaximumData: offset}
 		l := blocked.Length(v)
 		// In case it doesn't fit, queue it for the next packet.
@@ -218,15 +240,44 @@ func (f *framer) QueuedTooManyControlFrames() bool {
 	return f.queuedTooManyControlFrames
 }
 
-func (f *framer) AddActiveStream(id protocol.StreamID, str streamFrameGetter) {
+// NumBlockedFrames returns the number of STREAM_DATA_BLOCKED and DATA_BLOCKED frames queued so far.
+func (f *framer) NumBlockedFrames() (streamDataBlocked, dataBlocked uint64) {
+	return f.numStreamDataBlocked.Load(), f.numDataBlocked.Load()
+}
+
+// AddActiveStream adds a stream that has data to send.
+// If the stream is already active, but its priority changed, the stream is rescheduled.
+func (f *framer) AddActiveStream(id protocol.StreamID, str streamFrameGetter, priority StreamPriority) {
+	priority.Urgency = min(priority.Urgency, MaxStreamUrgency)
 	f.mutex.Lock()
-	if _, ok := f.activeStreams[id]; !ok {
-		f.streamQueue.PushBack(id)
-		f.activeStreams[id] = str
+	if as, ok := f.activeStreams[id]; !ok || as.priority != priority {
+		// If the priority changed, the stream is still contained in the queue for the old urgency.
+		// This entry is skipped when appending STREAM frames.
+		f.activeStreams[id] = activeStream{str: str, priority: priority}
+		f.pushStream(id, priority.Urgency)
 	}
 	f.mutex.Unlock()
 }
 
+// pushStream adds the stream to the queue for the urgency,
+// unless the queue already contains an entry for this stream.
+func (f *framer) pushStream(id protocol.StreamID, urgency uint8) {
+	if f.queuedStreams[id]&(1<<urgency) != 0 {
+		return
+	}
+	f.queuedStreams[id] |= 1 << urgency
+	f.streamQueues[urgency].PushBack(id)
+}
+
+func (f *framer) popStream(urgency uint8) {
+	id := f.streamQueues[urgency].PopFront()
+	if queued := f.queuedStreams[id] &^ (1 << urgency); queued != 0 {
+		f.queuedStreams[id] = queued
+	} else {
+		delete(f.queuedStreams, id)
+	}
+}
+
 func (f *framer) AddStreamWithControlFrames(id protocol.StreamID, str streamControlFrameGetter) {
 	f.controlFrameMutex.Lock()
 	if _, ok := f.streamsWithControlFrames[id]; !ok {
@@ -239,30 +290,49 @@ func (f *framer) AddStreamWithControlFrames(id protocol.StreamID, str streamCont
 func (f *framer) RemoveActiveStream(id protocol.StreamID) {
 	f.mutex.Lock()
 	delete(f.activeStreams, id)
-	// We don't delete the stream from the streamQueue,
+	// We don't delete the stream from the streamQueues,
 	// since we'd have to iterate over the ringbuffer.
 	// Instead, we check if the stream is still in activeStreams when appending STREAM frames.
 	f.mutex.Unlock()
 }
 
-func (f *framer) getNextStreamFrame(maxLen protocol.ByteCount, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame) {
-	id := f.streamQueue.PopFront()
-	// This should never return an error. Better check it anyway.
-	// The stream will only be in the streamQueue, if it enqueued itself there.
-	str, ok := f.activeStreams[id]
-	// The stream might have been removed after being enqueued.
-	if !ok {
+func (f *framer) getNextStreamFrame(maxLen protocol.ByteCount, now monotime.Time, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame) {
+	var queue *ringbuffer.RingBuffer[protocol.StreamID]
+	var urgency uint8
+	for i := range f.streamQueues {
+		if !f.streamQueues[i].Empty() {
+			queue = &f.streamQueues[i]
+			urgency = uint8(i)
+			break
+		}
+	}
+	if queue == nil {
+		return ackhandler.StreamFrame{}, nil
+	}
+	id := queue.PeekFront()
+	// The stream will only be in the streamQueues, if it enqueued itself there.
+	as, ok := f.activeStreams[id]
+	// The stream might have been removed after being enqueued,
+	// or it might have been moved to a different queue when its priority changed.
+	if !ok || as.priority.Urgency != urgency {
+		f.popStream(urgency)
 		return ackhandler.StreamFrame{}, nil
 	}
 	// For the last STREAM frame, we'll remove the DataLen field later.
 	// Therefore, we can pretend to have more bytes available when popping
 	// the STREAM frame (which will always have the DataLen set).
 	maxLen += protocol.ByteCount(quicvarint.Len(uint64(maxLen)))
-	frame, blocked, hasMoreData := str.popStreamFrame(maxLen, v)
-	if hasMoreData { // put the stream back in the queue (at the end)
-		f.streamQueue.PushBack(id)
-	} else { // no more data to send. Stream is not active
+	frame, blocked, hasMoreData := as.str.popStreamFrame(maxLen, now, v)
+	switch {
+	case !hasMoreData: // no more data to send. Stream is not active
+		f.popStream(urgency)
 		delete(f.activeStreams, id)
+	case as.priority.Incremental: // put the stream back in the queue (at the end)
+		f.popStream(urgency)
+		f.pushStream(id, urgency)
+	default:
+		// Non-incremental streams stay at the front of the queue,
+		// until all their data has been sent.
 	}
 	// Note that the frame.Frame can be nil:
 	// * if the stream was canceled after it said it had data
@@ -276,10 +346,13 @@ func (f *framer) Handle0RTTRejection() {
 	f.controlFrameMutex.Lock()
 	defer f.controlFrameMutex.Unlock()
 
-	f.streamQueue.Clear()
+	for i := range f.streamQueues {
+		f.streamQueues[i].Clear()
+	}
 	for id := range f.activeStreams {
 		delete(f.activeStreams, id)
 	}
+	clear(f.queuedStreams)
 	var j int
 	for i, frame := range f.controlFrames {
 		switch frame.(type) {
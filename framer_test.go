@@ -116,9 +116,9 @@ func testFramerStreamDataBlocked(t *testing.T, fits bool) {
 	const streamID = 5
 	str := NewMockStreamFrameGetter(gomock.NewController(t))
 	framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
-	framer.AddActiveStream(streamID, str)
-	str.EXPECT().popStreamFrame(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(size protocol.ByteCount, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
+	framer.AddActiveStream(streamID, str, defaultStreamPriority)
+	str.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(size protocol.ByteCount, _ monotime.Time, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
 			data := []byte("foobar")
 			if !fits {
 				// Leave 3 bytes in the packet.
@@ -150,6 +150,8 @@ func testFramerStreamDataBlocked(t *testing.T, fits bool) {
 		require.Len(t, frames, 1)
 		require.Equal(t, &wire.StreamDataBlockedFrame{StreamID: streamID, MaximumStreamData: dataLen}, frames[0].Frame)
 	}
+	numStreamDataBlocked, _ := framer.NumBlockedFrames()
+	require.EqualValues(t, 1, numStreamDataBlocked)
 }
 
 func TestFramerDataBlocked(t *testing.T) {
@@ -177,10 +179,10 @@ func testFramerDataBlocked(t *testing.T, fits bool) {
 
 	str := NewMockStreamFrameGetter(gomock.NewController(t))
 	framer := newFramer(fc)
-	framer.AddActiveStream(streamID, str)
+	framer.AddActiveStream(streamID, str, defaultStreamPriority)
 
-	str.EXPECT().popStreamFrame(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(size protocol.ByteCount, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
+	str.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(size protocol.ByteCount, _ monotime.Time, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
 			data := []byte("foobar")
 			if !fits {
 				// Leave 2 bytes in the packet.
@@ -207,6 +209,9 @@ func testFramerDataBlocked(t *testing.T, fits bool) {
 		require.Len(t, frames, 1)
 		require.Equal(t, &wire.DataBlockedFrame{MaximumData: offset}, frames[0].Frame)
 	}
+	numStreamDataBlocked, numDataBlocked := framer.NumBlockedFrames()
+	require.Zero(t, numStreamDataBlocked)
+	require.EqualValues(t, 1, numDataBlocked)
 }
 
 func TestFramerDetectsFrameDoS(t *testing.T) {
@@ -293,12 +298,12 @@ func TestFramerAppendStreamFrames(t *testing.T) {
 	// add two streams
 	mockCtrl := gomock.NewController(t)
 	str1 := NewMockStreamFrameGetter(mockCtrl)
-	str1.EXPECT().popStreamFrame(gomock.Any(), protocol.Version1).Return(ackhandler.StreamFrame{Frame: f1}, nil, true)
+	str1.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), protocol.Version1).Return(ackhandler.StreamFrame{Frame: f1}, nil, true)
 	str2 := NewMockStreamFrameGetter(mockCtrl)
-	str2.EXPECT().popStreamFrame(gomock.Any(), protocol.Version1).Return(ackhandler.StreamFrame{Frame: f2}, nil, false)
-	framer.AddActiveStream(str1ID, str1)
-	framer.AddActiveStream(str1ID, str1) // duplicate calls are ok (they're no-ops)
-	framer.AddActiveStream(str2ID, str2)
+	str2.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), protocol.Version1).Return(ackhandler.StreamFrame{Frame: f2}, nil, false)
+	framer.AddActiveStream(str1ID, str1, defaultStreamPriority)
+	framer.AddActiveStream(str1ID, str1, defaultStreamPriority) // duplicate calls are ok (they're no-ops)
+	framer.AddActiveStream(str2ID, str2, defaultStreamPriority)
 	require.True(t, framer.HasData())
 
 	// Even though the first stream claimed to have more data,
@@ -321,7 +326,7 @@ func TestFramerAppendStreamFrames(t *testing.T) {
 	require.True(t, framer.HasData()) // the stream claimed to have more data...
 
 	// ... but it actually doesn't
-	str1.EXPECT().popStreamFrame(gomock.Any(), protocol.Version1).Return(ackhandler.StreamFrame{}, nil, false)
+	str1.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), protocol.Version1).Return(ackhandler.StreamFrame{}, nil, false)
 	_, fs, length = framer.Append(nil, nil, protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Empty(t, fs)
 	require.Zero(t, length)
@@ -332,7 +337,7 @@ func TestFramerRemoveActiveStream(t *testing.T) {
 	const id = protocol.StreamID(42)
 	framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
 	require.False(t, framer.HasData())
-	framer.AddActiveStream(id, NewMockStreamFrameGetter(gomock.NewController(t)))
+	framer.AddActiveStream(id, NewMockStreamFrameGetter(gomock.NewController(t)), defaultStreamPriority)
 	require.True(t, framer.HasData())
 	framer.RemoveActiveStream(id) // no calls will be issued to the mock stream
 	// we can't assert on framer.HasData here, since it's not removed from the ringbuffer
@@ -341,11 +346,100 @@ func TestFramerRemoveActiveStream(t *testing.T) {
 	require.False(t, framer.HasData())
 }
 
+func TestFramerStreamPriorities(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	// newStream returns a stream that has numFrames STREAM frames of 500 bytes each to send
+	newStream := func(id protocol.StreamID, numFrames int) *MockStreamFrameGetter {
+		str := NewMockStreamFrameGetter(mockCtrl)
+		str.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(protocol.ByteCount, monotime.Time, protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
+				numFrames--
+				return ackhandler.StreamFrame{Frame: &wire.StreamFrame{StreamID: id, Data: make([]byte, 500)}}, nil, numFrames > 0
+			},
+		).Times(numFrames)
+		return str
+	}
+	// popAll returns the stream IDs of all STREAM frames, in the order they were sent
+	popAll := func(framer *framer) []protocol.StreamID {
+		var ids []protocol.StreamID
+		for framer.HasData() {
+			_, fs, _ := framer.Append(nil, nil, 1200, monotime.Now(), protocol.Version1)
+			require.NotEmpty(t, fs)
+			for _, f := range fs {
+				ids = append(ids, f.Frame.StreamID)
+			}
+		}
+		return ids
+	}
+
+	t.Run("strict priority", func(t *testing.T) {
+		framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
+		framer.AddActiveStream(4, newStream(4, 2), StreamPriority{Urgency: 5, Incremental: true})
+		framer.AddActiveStream(8, newStream(8, 2), StreamPriority{Urgency: 1, Incremental: true})
+		framer.AddActiveStream(12, newStream(12, 2), StreamPriority{Urgency: 0, Incremental: true})
+		require.Equal(t, []protocol.StreamID{12, 12, 8, 8, 4, 4}, popAll(framer))
+	})
+
+	t.Run("incremental streams", func(t *testing.T) {
+		framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
+		framer.AddActiveStream(4, newStream(4, 3), StreamPriority{Urgency: 3, Incremental: true})
+		framer.AddActiveStream(8, newStream(8, 3), StreamPriority{Urgency: 3, Incremental: true})
+		require.Equal(t, []protocol.StreamID{4, 8, 4, 8, 4, 8}, popAll(framer))
+	})
+
+	t.Run("non-incremental streams", func(t *testing.T) {
+		framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
+		framer.AddActiveStream(4, newStream(4, 3), StreamPriority{Urgency: 3})
+		framer.AddActiveStream(8, newStream(8, 3), StreamPriority{Urgency: 3})
+		require.Equal(t, []protocol.StreamID{4, 4, 4, 8, 8, 8}, popAll(framer))
+	})
+
+	t.Run("urgencies are capped", func(t *testing.T) {
+		framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
+		framer.AddActiveStream(4, newStream(4, 1), StreamPriority{Urgency: 100})
+		framer.AddActiveStream(8, newStream(8, 1), StreamPriority{Urgency: MaxStreamUrgency})
+		require.Equal(t, []protocol.StreamID{4, 8}, popAll(framer))
+	})
+
+	t.Run("changing the priority", func(t *testing.T) {
+		framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
+		str4 := newStream(4, 2)
+		framer.AddActiveStream(4, str4, StreamPriority{Urgency: 5})
+		framer.AddActiveStream(8, newStream(8, 2), StreamPriority{Urgency: 3})
+		// Stream 4 is moved to the front.
+		// The stale entry in the queue for urgency 5 doesn't lead to more STREAM frames being sent.
+		framer.AddActiveStream(4, str4, StreamPriority{Urgency: 1})
+		require.Equal(t, []protocol.StreamID{4, 4, 8, 8}, popAll(framer))
+	})
+
+	t.Run("changing the priority back and forth", func(t *testing.T) {
+		framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
+		str4 := newStream(4, 3)
+		framer.AddActiveStream(4, str4, StreamPriority{Urgency: 3, Incremental: true})
+		framer.AddActiveStream(8, newStream(8, 3), StreamPriority{Urgency: 3, Incremental: true})
+		// Stream 4 is moved to a different queue, and then back to the original queue.
+		// It must not get two turns per round-robin cycle.
+		framer.AddActiveStream(4, str4, StreamPriority{Urgency: 5, Incremental: true})
+		framer.AddActiveStream(4, str4, StreamPriority{Urgency: 3, Incremental: true})
+		require.Equal(t, []protocol.StreamID{4, 8, 4, 8, 4, 8}, popAll(framer))
+	})
+
+	t.Run("removing and re-adding a stream", func(t *testing.T) {
+		framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
+		str4 := newStream(4, 3)
+		framer.AddActiveStream(4, str4, StreamPriority{Urgency: 3, Incremental: true})
+		framer.AddActiveStream(8, newStream(8, 3), StreamPriority{Urgency: 3, Incremental: true})
+		framer.RemoveActiveStream(4)
+		framer.AddActiveStream(4, str4, StreamPriority{Urgency: 3, Incremental: true})
+		require.Equal(t, []protocol.StreamID{4, 8, 4, 8, 4, 8}, popAll(framer))
+	})
+}
+
 func TestFramerMinStreamFrameSize(t *testing.T) {
 	const id = protocol.StreamID(42)
 	framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
 	str := NewMockStreamFrameGetter(gomock.NewController(t))
-	framer.AddActiveStream(id, str)
+	framer.AddActiveStream(id, str, defaultStreamPriority)
 
 	require.True(t, framer.HasData())
 	// don't pop frames smaller than the minimum STREAM frame size
@@ -353,8 +447,8 @@ func TestFramerMinStreamFrameSize(t *testing.T) {
 	require.Empty(t, frames)
 
 	// pop frames of the minimum size
-	str.EXPECT().popStreamFrame(gomock.Any(), protocol.Version1).DoAndReturn(
-		func(size protocol.ByteCount, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
+	str.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), protocol.Version1).DoAndReturn(
+		func(size protocol.ByteCount, _ monotime.Time, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
 			f := &wire.StreamFrame{StreamID: id, DataLenPresent: true}
 			f.Data = make([]byte, f.MaxDataLen(protocol.MinStreamFrameSize, v))
 			return ackhandler.StreamFrame{Frame: f}, nil, false
@@ -370,7 +464,7 @@ func TestFramerMinStreamFrameSizeMultipleStreamFrames(t *testing.T) {
 	const id = protocol.StreamID(42)
 	framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
 	str := NewMockStreamFrameGetter(gomock.NewController(t))
-	framer.AddActiveStream(id, str)
+	framer.AddActiveStream(id, str, defaultStreamPriority)
 
 	// pop a frame such that the remaining size is one byte less than the minimum STREAM frame size
 	f := &wire.StreamFrame{
@@ -378,8 +472,8 @@ func TestFramerMinStreamFrameSizeMultipleStreamFrames(t *testing.T) {
 		Data:           bytes.Repeat([]byte("f"), int(500-protocol.MinStreamFrameSize)),
 		DataLenPresent: true,
 	}
-	str.EXPECT().popStreamFrame(gomock.Any(), protocol.Version1).Return(ackhandler.StreamFrame{Frame: f}, nil, false)
-	framer.AddActiveStream(id, str)
+	str.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), protocol.Version1).Return(ackhandler.StreamFrame{Frame: f}, nil, false)
+	framer.AddActiveStream(id, str, defaultStreamPriority)
 	_, fs, length := framer.Append(nil, nil, 500, monotime.Now(), protocol.Version1)
 	require.Len(t, fs, 1)
 	require.Equal(t, f, fs[0].Frame)
@@ -392,8 +486,8 @@ func TestFramerFillPacketOneStream(t *testing.T) {
 	framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
 
 	for i := protocol.MinStreamFrameSize; i < 2000; i++ {
-		str.EXPECT().popStreamFrame(gomock.Any(), protocol.Version1).DoAndReturn(
-			func(size protocol.ByteCount, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
+		str.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), protocol.Version1).DoAndReturn(
+			func(size protocol.ByteCount, _ monotime.Time, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
 				f := &wire.StreamFrame{
 					StreamID:       id,
 					DataLenPresent: true,
@@ -403,7 +497,7 @@ func TestFramerFillPacketOneStream(t *testing.T) {
 				return ackhandler.StreamFrame{Frame: f}, nil, false
 			},
 		)
-		framer.AddActiveStream(id, str)
+		framer.AddActiveStream(id, str, defaultStreamPriority)
 		_, frames, _ := framer.Append(nil, nil, i, monotime.Now(), protocol.Version1)
 		require.Len(t, frames, 1)
 		require.False(t, frames[0].Frame.DataLenPresent)
@@ -423,23 +517,23 @@ func TestFramerFillPacketMultipleStreams(t *testing.T) {
 	framer := newFramer(flowcontrol.NewConnectionFlowController(0, 0, nil, nil, nil))
 
 	for i := 2 * protocol.MinStreamFrameSize; i < 2000; i++ {
-		stream1.EXPECT().popStreamFrame(gomock.Any(), protocol.Version1).DoAndReturn(
-			func(size protocol.ByteCount, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
+		stream1.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), protocol.Version1).DoAndReturn(
+			func(size protocol.ByteCount, _ monotime.Time, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
 				f := &wire.StreamFrame{StreamID: id1, DataLenPresent: true}
 				f.Data = make([]byte, f.MaxDataLen(protocol.MinStreamFrameSize, v))
 				return ackhandler.StreamFrame{Frame: f}, nil, false
 			},
 		)
-		stream2.EXPECT().popStreamFrame(gomock.Any(), protocol.Version1).DoAndReturn(
-			func(size protocol.ByteCount, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
+		stream2.EXPECT().popStreamFrame(gomock.Any(), gomock.Any(), protocol.Version1).DoAndReturn(
+			func(size protocol.ByteCount, _ monotime.Time, v protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
 				f := &wire.StreamFrame{StreamID: id2, DataLenPresent: true}
 				f.Data = make([]byte, f.MaxDataLen(size, v))
 				require.Equal(t, size, f.Length(protocol.Version1))
 				return ackhandler.StreamFrame{Frame: f}, nil, false
 			},
 		)
-		framer.AddActiveStream(id1, stream1)
-		framer.AddActiveStream(id2, stream2)
+		framer.AddActiveStream(id1, stream1, defaultStreamPriority)
+		framer.AddActiveStream(id2, stream2, defaultStreamPriority)
 		_, frames, _ := framer.Append(nil, nil, i, monotime.Now(), protocol.Version1)
 		require.Len(t, frames, 2)
 		require.True(t, frames[0].Frame.DataLenPresent)
@@ -464,7 +558,7 @@ func TestFramer0RTTRejection(t *testing.T) {
 	framer.QueueControlFrame(&wire.StreamsBlockedFrame{StreamLimit: 13})
 	framer.QueueControlFrame(pc)
 
-	framer.AddActiveStream(10, NewMockStreamFrameGetter(gomock.NewController(t)))
+	framer.AddActiveStream(10, NewMockStreamFrameGetter(gomock.NewController(t)), defaultStreamPriority)
 
 	framer.Handle0RTTRejection()
 	controlFrames, streamFrames, _ := framer.Append(nil, nil, protocol.MaxByteCount, monotime.Now(), protocol.Version1)
@@ -44,6 +44,7 @@ func main() {
 		&wire.TransportParameters{ActiveConnectionIDLimit: 2},
 		config,
 		false,
+		nil,
 		&utils.RTTStats{},
 		nil,
 		utils.DefaultLogger.WithPrefix("server"),
@@ -298,6 +298,7 @@ func runHandshake(runConfig [confLen]byte, messageConfig uint8, clientConf *tls.
 		serverTP,
 		serverConf,
 		enable0RTTServer,
+		nil,
 		&utils.RTTStats{},
 		nil,
 		utils.DefaultLogger.WithPrefix("server"),
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/qlogwriter"
 	"github.com/quic-go/quic-go/qlogwriter/jsontext"
 )
 
@@ -51,6 +52,8 @@ type FrameParsed struct {
 
 func (e FrameParsed) Name() string { return "http3:frame_parsed" }
 
+func (e FrameParsed) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e FrameParsed) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -78,6 +81,8 @@ type FrameCreated struct {
 
 func (e FrameCreated) Name() string { return "http3:frame_created" }
 
+func (e FrameCreated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e FrameCreated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -104,6 +109,8 @@ type DatagramCreated struct {
 
 func (e DatagramCreated) Name() string { return "http3:datagram_created" }
 
+func (e DatagramCreated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e DatagramCreated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -124,6 +131,8 @@ type DatagramParsed struct {
 
 func (e DatagramParsed) Name() string { return "http3:datagram_parsed" }
 
+func (e DatagramParsed) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e DatagramParsed) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
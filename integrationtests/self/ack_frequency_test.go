@@ -0,0 +1,99 @@
+package self_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/testutils/events"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckFrequency(t *testing.T) {
+	for _, serverEnable := range []bool{true, false} {
+		t.Run(fmt.Sprintf("server enabled: %t", serverEnable), func(t *testing.T) {
+			testAckFrequency(t, serverEnable)
+		})
+	}
+}
+
+func testAckFrequency(t *testing.T, serverEnable bool) {
+	server, err := quic.Listen(
+		newUDPConnLocalhost(t),
+		getTLSConfig(),
+		getQuicConfig(&quic.Config{EnableAckFrequency: serverEnable}),
+	)
+	require.NoError(t, err)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var eventRecorder events.Recorder
+	conn, err := quic.Dial(
+		ctx,
+		newUDPConnLocalhost(t),
+		server.Addr(),
+		getTLSClientConfig(),
+		getQuicConfig(&quic.Config{EnableAckFrequency: true, Tracer: newTracer(&eventRecorder)}),
+	)
+	require.NoError(t, err)
+	defer conn.CloseWithError(0, "")
+
+	serverConn, err := server.Accept(ctx)
+	require.NoError(t, err)
+	defer serverConn.CloseWithError(0, "")
+
+	errChan := make(chan error, 1)
+	go func() {
+		str, err := serverConn.AcceptUniStream(ctx)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		data, err := io.ReadAll(str)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		if len(data) != len(PRDataLong) {
+			errChan <- fmt.Errorf("received %d bytes, expected %d", len(data), len(PRDataLong))
+			return
+		}
+		close(errChan)
+	}()
+
+	str, err := conn.OpenUniStream()
+	require.NoError(t, err)
+	_, err = str.Write(PRDataLong)
+	require.NoError(t, err)
+	require.NoError(t, str.Close())
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	case <-ctx.Done():
+		t.Fatal("timeout")
+	}
+
+	var ackFrequencyFrames []*qlog.AckFrequencyFrame
+	for _, ev := range eventRecorder.Events(qlog.PacketSent{}) {
+		for _, f := range ev.(qlog.PacketSent).Frames {
+			if af, ok := f.Frame.(*qlog.AckFrequencyFrame); ok {
+				ackFrequencyFrames = append(ackFrequencyFrames, af)
+			}
+		}
+	}
+	if !serverEnable {
+		require.Empty(t, ackFrequencyFrames)
+		return
+	}
+	require.NotEmpty(t, ackFrequencyFrames)
+	t.Logf("sent %d ACK_FREQUENCY frames", len(ackFrequencyFrames))
+	// the first frame is only sent once the congestion window is large enough
+	require.Zero(t, ackFrequencyFrames[0].SequenceNumber)
+	require.Greater(t, ackFrequencyFrames[0].AckElicitingThreshold, uint64(1))
+}
@@ -0,0 +1,80 @@
+package self_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/testutils/events"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmissionControlMaxConnections(t *testing.T) {
+	t.Run("refusing", func(t *testing.T) {
+		testAdmissionControlMaxConnections(t, false)
+	})
+	t.Run("retry", func(t *testing.T) {
+		testAdmissionControlMaxConnections(t, true)
+	})
+}
+
+func testAdmissionControlMaxConnections(t *testing.T, retryOnLimit bool) {
+	var eventRecorder events.Recorder
+	tr := &quic.Transport{
+		Conn:           newUDPConnLocalhost(t),
+		MaxConnections: 1,
+		RetryOnLimit:   retryOnLimit,
+		Tracer:         &eventRecorder,
+	}
+	defer tr.Close()
+	ln, err := tr.Listen(getTLSConfig(), getQuicConfig(nil))
+	require.NoError(t, err)
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := quic.Dial(ctx, newUDPConnLocalhost(t), ln.Addr(), getTLSClientConfig(), getQuicConfig(nil))
+	require.NoError(t, err)
+	serverConn, err := ln.Accept(ctx)
+	require.NoError(t, err)
+
+	_, err = quic.Dial(ctx, newUDPConnLocalhost(t), ln.Addr(), getTLSClientConfig(), getQuicConfig(nil))
+	require.ErrorIs(t, err, &quic.TransportError{Remote: true, ErrorCode: quic.ConnectionRefused})
+
+	stats := tr.AdmissionStats()
+	require.Equal(t, 1, stats.Connections)
+	require.Zero(t, stats.Handshakes)
+	// The client's first flight might consist of multiple Initial packets,
+	// every one of them is rejected.
+	rejected := eventRecorder.Events(qlog.ConnectionRejected{})
+	require.NotEmpty(t, rejected)
+	require.Len(t, rejected, int(stats.RejectedMaxConnections))
+	for _, ev := range rejected {
+		require.Equal(t, qlog.ConnectionRejectionMaxConnections, ev.(qlog.ConnectionRejected).Reason)
+	}
+	if retryOnLimit {
+		// the client is first sent a Retry, and refused after validating its address
+		require.NotZero(t, stats.Retries)
+		require.Less(t, stats.Retries, stats.RejectedMaxConnections)
+		require.True(t, rejected[0].(qlog.ConnectionRejected).Retry)
+		require.False(t, rejected[len(rejected)-1].(qlog.ConnectionRejected).Retry)
+	} else {
+		require.Zero(t, stats.Retries)
+	}
+
+	// closing the first connection allows a new connection to be established
+	conn.CloseWithError(0, "")
+	select {
+	case <-serverConn.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	require.Eventually(t, func() bool { return tr.AdmissionStats().Connections == 0 }, time.Second, time.Millisecond)
+
+	conn, err = quic.Dial(ctx, newUDPConnLocalhost(t), ln.Addr(), getTLSClientConfig(), getQuicConfig(nil))
+	require.NoError(t, err)
+	conn.CloseWithError(0, "")
+}
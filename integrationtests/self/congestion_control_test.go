@@ -0,0 +1,79 @@
+package self_test
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/congestion"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingSendAlgorithm struct {
+	congestion.SendAlgorithm
+
+	sent, acked *atomic.Int64
+}
+
+func (c *countingSendAlgorithm) OnPacketSent(t time.Time, bytesInFlight congestion.ByteCount, pn congestion.PacketNumber, bytes congestion.ByteCount, isRetransmittable bool) {
+	c.sent.Add(1)
+	c.SendAlgorithm.OnPacketSent(t, bytesInFlight, pn, bytes, isRetransmittable)
+}
+
+func (c *countingSendAlgorithm) OnPacketAcked(pn congestion.PacketNumber, ackedBytes, priorInFlight congestion.ByteCount, t time.Time) {
+	c.acked.Add(1)
+	c.SendAlgorithm.OnPacketAcked(pn, ackedBytes, priorInFlight, t)
+}
+
+func TestCustomCongestionControl(t *testing.T) {
+	t.Run("CUBIC", func(t *testing.T) { testCustomCongestionControl(t, congestion.NewCubic) })
+	t.Run("BBR", func(t *testing.T) { testCustomCongestionControl(t, congestion.NewBBR) })
+}
+
+func testCustomCongestionControl(t *testing.T, newCC func(congestion.Params) congestion.SendAlgorithm) {
+	var numCreated, sent, acked atomic.Int64
+	ln, err := quic.Listen(
+		newUDPConnLocalhost(t),
+		getTLSConfig(),
+		getQuicConfig(&quic.Config{
+			CongestionControl: func(p congestion.Params) congestion.SendAlgorithm {
+				numCreated.Add(1)
+				return &countingSendAlgorithm{
+					SendAlgorithm: newCC(p),
+					sent:          &sent,
+					acked:         &acked,
+				}
+			},
+		}),
+	)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	conn, err := quic.Dial(ctx, newUDPConnLocalhost(t), ln.Addr(), getTLSClientConfig(), getQuicConfig(nil))
+	require.NoError(t, err)
+	defer conn.CloseWithError(0, "")
+
+	serverConn, err := ln.Accept(ctx)
+	require.NoError(t, err)
+	str, err := serverConn.OpenUniStream()
+	require.NoError(t, err)
+	_, err = str.Write(PRData)
+	require.NoError(t, err)
+	require.NoError(t, str.Close())
+
+	rstr, err := conn.AcceptUniStream(ctx)
+	require.NoError(t, err)
+	data, err := io.ReadAll(rstr)
+	require.NoError(t, err)
+	require.Equal(t, PRData, data)
+
+	require.EqualValues(t, 1, numCreated.Load())
+	require.Greater(t, sent.Load(), int64(len(PRData)/1500))
+	require.NotZero(t, acked.Load())
+}
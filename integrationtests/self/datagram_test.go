@@ -67,6 +67,7 @@ func testDatagramNegotiation(t *testing.T, serverEnableDatagram, clientEnableDat
 		require.Equal(t, []byte("foo"), datagram)
 	} else {
 		require.False(t, serverConn.ConnectionState().SupportsDatagrams)
+		require.Zero(t, serverConn.MaxDatagramPayloadSize())
 		require.Error(t, serverConn.SendDatagram([]byte("foo")))
 	}
 
@@ -113,6 +114,7 @@ func TestDatagramSizeLimit(t *testing.T) {
 	var sizeErr *quic.DatagramTooLargeError
 	require.ErrorAs(t, err, &sizeErr)
 	require.InDelta(t, sizeErr.MaxDatagramPayloadSize, maxDatagramSize, 10)
+	require.Equal(t, sizeErr.MaxDatagramPayloadSize, clientConn.MaxDatagramPayloadSize())
 
 	require.NoError(t, clientConn.SendDatagram(bytes.Repeat([]byte("b"), int(sizeErr.MaxDatagramPayloadSize))))
 	require.Error(t, clientConn.SendDatagram(bytes.Repeat([]byte("c"), int(sizeErr.MaxDatagramPayloadSize+1))))
@@ -123,6 +125,11 @@ func TestDatagramSizeLimit(t *testing.T) {
 	datagram, err := serverConn.ReceiveDatagram(ctx)
 	require.NoError(t, err)
 	require.Equal(t, bytes.Repeat([]byte("b"), int(sizeErr.MaxDatagramPayloadSize)), datagram)
+
+	clientStats := clientConn.ConnectionStats()
+	require.EqualValues(t, 1, clientStats.DatagramsSent)
+	require.EqualValues(t, 2, clientStats.DatagramsTooLarge)
+	require.EqualValues(t, 1, serverConn.ConnectionStats().DatagramsReceived)
 }
 
 func TestDatagramLoss(t *testing.T) {
@@ -252,3 +259,103 @@ func TestDatagramLoss(t *testing.T) {
 		assert.EqualValues(t, numDatagrams-numDroppedToClient, clientDatagrams, "datagrams received by the client")
 	})
 }
+
+func TestDatagramFeedback(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		const rtt = 100 * time.Millisecond
+		const numDatagrams = 20
+		const datagramSize = 500
+
+		clientAddr := &net.UDPAddr{IP: net.ParseIP("1.0.0.1"), Port: 9001}
+		serverAddr := &net.UDPAddr{IP: net.ParseIP("1.0.0.2"), Port: 9002}
+		var numSent, numDropped atomic.Int32
+		n := &simnet.Simnet{
+			Router: &directionAwareDroppingRouter{
+				ClientAddr: clientAddr,
+				ServerAddr: serverAddr,
+				Drop: func(d direction, p simnet.Packet) bool {
+					if d != directionToServer || wire.IsLongHeaderPacket(p.Data[0]) || len(p.Data) < datagramSize {
+						return false
+					}
+					// drop every 4th packet containing a DATAGRAM frame, except for the last one
+					if n := numSent.Add(1); n%4 == 0 && n < numDatagrams {
+						numDropped.Add(1)
+						return true
+					}
+					return false
+				},
+			},
+		}
+		settings := simnet.NodeBiDiLinkSettings{
+			Downlink: simnet.LinkSettings{BitsPerSecond: math.MaxInt, Latency: rtt / 4},
+			Uplink:   simnet.LinkSettings{BitsPerSecond: math.MaxInt, Latency: rtt / 4},
+		}
+		clientPacketConn := n.NewEndpoint(clientAddr, settings)
+		defer clientPacketConn.Close()
+		serverPacketConn := n.NewEndpoint(serverAddr, settings)
+		defer serverPacketConn.Close()
+		require.NoError(t, n.Start())
+		defer n.Close()
+
+		server, err := quic.Listen(
+			serverPacketConn,
+			getTLSConfig(),
+			getQuicConfig(&quic.Config{DisablePathMTUDiscovery: true, EnableDatagrams: true}),
+		)
+		require.NoError(t, err)
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		clientConn, err := quic.Dial(
+			ctx,
+			clientPacketConn,
+			serverPacketConn.LocalAddr(),
+			getTLSClientConfig(),
+			getQuicConfig(&quic.Config{DisablePathMTUDiscovery: true, EnableDatagrams: true}),
+		)
+		require.NoError(t, err)
+		defer clientConn.CloseWithError(0, "")
+
+		serverConn, err := server.Accept(ctx)
+		require.NoError(t, err)
+		defer serverConn.CloseWithError(0, "")
+
+		// a datagram with a deadline in the past is never sent
+		h, err := clientConn.SendDatagramWithFeedback([]byte("foobar"), quic.SendDatagramOptions{Deadline: time.Now().Add(-time.Second)})
+		require.NoError(t, err)
+		require.Equal(t, quic.DatagramStateExpired, h.State())
+
+		handles := make([]*quic.DatagramHandle, 0, numDatagrams)
+		for i := range numDatagrams {
+			h, err := clientConn.SendDatagramWithFeedback(
+				bytes.Repeat([]byte{uint8(i)}, datagramSize),
+				quic.SendDatagramOptions{Deadline: time.Now().Add(time.Second)},
+			)
+			require.NoError(t, err)
+			handles = append(handles, h)
+			time.Sleep(time.Second)
+		}
+
+		var numAcked, numLost int
+		for _, h := range handles {
+			select {
+			case <-h.Done():
+			case <-time.After(10 * time.Second):
+				t.Fatal("timeout waiting for datagram feedback")
+			}
+			switch h.State() {
+			case quic.DatagramStateAcknowledged:
+				numAcked++
+			case quic.DatagramStateLost:
+				numLost++
+			default:
+				t.Fatalf("unexpected datagram state: %s", h.State())
+			}
+		}
+		t.Logf("dropped %d packets", numDropped.Load())
+		require.NotZero(t, numDropped.Load())
+		require.EqualValues(t, numDropped.Load(), numLost)
+		require.Equal(t, numDatagrams-numLost, numAcked)
+	})
+}
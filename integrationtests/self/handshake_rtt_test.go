@@ -87,6 +87,10 @@ func testHandshakeMeasureHandshake(t *testing.T, verifySourceAddress func(net.Ad
 		defer conn.CloseWithError(0, "")
 
 		rtts = time.Since(start).Seconds() / rtt.Seconds()
+
+		stats := conn.ConnectionStats()
+		require.False(t, stats.HandshakeStartTime.IsZero())
+		require.InDelta(t, rtts, stats.HandshakeCompleteTime.Sub(stats.HandshakeStartTime).Seconds()/rtt.Seconds(), 0.01)
 	})
 	return rtts
 }
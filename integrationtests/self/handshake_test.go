@@ -95,6 +95,42 @@ func TestHandshake(t *testing.T) {
 	}
 }
 
+func TestHandshakeAdditionalTransportParameters(t *testing.T) {
+	server, err := quic.Listen(
+		newUDPConnLocalhost(t),
+		getTLSConfig(),
+		getQuicConfig(&quic.Config{AdditionalTransportParameters: map[uint64][]byte{0x1337: []byte("server")}}),
+	)
+	require.NoError(t, err)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := quic.Dial(
+		ctx,
+		newUDPConnLocalhost(t),
+		server.Addr(),
+		getTLSClientConfig(),
+		getQuicConfig(&quic.Config{AdditionalTransportParameters: map[uint64][]byte{0x4242: []byte("client"), 0x42: {}}}),
+	)
+	require.NoError(t, err)
+	defer conn.CloseWithError(0, "")
+	require.Equal(t, map[uint64][]byte{0x1337: []byte("server")}, conn.ConnectionState().AdditionalTransportParameters)
+	// modifying the returned map doesn't modify the connection state
+	params := conn.ConnectionState().AdditionalTransportParameters
+	params[0x1337][0] = 'x'
+	delete(params, 0x1337)
+	require.Equal(t, map[uint64][]byte{0x1337: []byte("server")}, conn.ConnectionState().AdditionalTransportParameters)
+
+	serverConn, err := server.Accept(ctx)
+	require.NoError(t, err)
+	defer serverConn.CloseWithError(0, "")
+	require.Equal(t,
+		map[uint64][]byte{0x4242: []byte("client"), 0x42: {}},
+		serverConn.ConnectionState().AdditionalTransportParameters,
+	)
+}
+
 func TestHandshakeServerMismatch(t *testing.T) {
 	server, err := quic.Listen(newUDPConnLocalhost(t), getTLSConfig(), getQuicConfig(nil))
 	require.NoError(t, err)
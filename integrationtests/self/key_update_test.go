@@ -91,3 +91,87 @@ func TestKeyUpdates(t *testing.T) {
 	assert.Greater(t, keyPhasesReceived, 10)
 	assert.InDelta(t, keyPhasesSent, keyPhasesReceived, 2)
 }
+
+func TestKeyUpdateInitiatedByApplication(t *testing.T) {
+	server, err := quic.Listen(newUDPConnLocalhost(t), getTLSConfig(), getQuicConfig(nil))
+	require.NoError(t, err)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := quic.Dial(ctx, newUDPConnLocalhost(t), server.Addr(), getTLSClientConfig(), getQuicConfig(nil))
+	require.NoError(t, err)
+	defer conn.CloseWithError(0, "")
+
+	serverConn, err := server.Accept(ctx)
+	require.NoError(t, err)
+	defer serverConn.CloseWithError(0, "")
+
+	for i := range 3 {
+		require.NoError(t, serverConn.InitiateKeyUpdate())
+		// the key update is completed once both endpoints use the new keys
+		require.Eventually(t, func() bool {
+			return serverConn.ConnectionStats().KeyUpdates == uint64(i+1) &&
+				conn.ConnectionStats().KeyUpdates == uint64(i+1)
+		}, time.Second, 5*time.Millisecond)
+	}
+	require.NoError(t, conn.InitiateKeyUpdate())
+	require.Eventually(t, func() bool {
+		return serverConn.ConnectionStats().KeyUpdates == 4 && conn.ConnectionStats().KeyUpdates == 4
+	}, time.Second, 5*time.Millisecond)
+
+	// the connection is still usable
+	str, err := conn.OpenStream()
+	require.NoError(t, err)
+	_, err = str.Write([]byte("foobar"))
+	require.NoError(t, err)
+	require.NoError(t, str.Close())
+	sstr, err := serverConn.AcceptStream(ctx)
+	require.NoError(t, err)
+	data, err := io.ReadAll(sstr)
+	require.NoError(t, err)
+	require.Equal(t, []byte("foobar"), data)
+}
+
+func TestKeyUpdatePacketInterval(t *testing.T) {
+	server, err := quic.Listen(newUDPConnLocalhost(t), getTLSConfig(), getQuicConfig(&quic.Config{KeyUpdatePacketInterval: 200}))
+	require.NoError(t, err)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := quic.Dial(ctx, newUDPConnLocalhost(t), server.Addr(), getTLSClientConfig(), getQuicConfig(nil))
+	require.NoError(t, err)
+	defer conn.CloseWithError(0, "")
+
+	serverConn, err := server.Accept(ctx)
+	require.NoError(t, err)
+	defer serverConn.CloseWithError(0, "")
+
+	serverErrChan := make(chan error, 1)
+	go func() {
+		str, err := serverConn.OpenUniStream()
+		if err != nil {
+			serverErrChan <- err
+			return
+		}
+		defer str.Close()
+		if _, err := str.Write(PRDataLong); err != nil {
+			serverErrChan <- err
+			return
+		}
+		close(serverErrChan)
+	}()
+
+	str, err := conn.AcceptUniStream(ctx)
+	require.NoError(t, err)
+	data, err := io.ReadAll(str)
+	require.NoError(t, err)
+	require.Equal(t, PRDataLong, data)
+	require.NoError(t, <-serverErrChan)
+
+	// The keys are updated once after 100 packets, and then every 200 packets.
+	// PRDataLong is sent in more than 1000 packets.
+	require.Greater(t, serverConn.ConnectionStats().PacketsSent, uint64(1000))
+	require.Greater(t, serverConn.ConnectionStats().KeyUpdates, uint64(2))
+}
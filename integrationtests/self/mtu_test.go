@@ -128,6 +128,7 @@ func TestPathMTUDiscovery(t *testing.T) {
 	var datagramErr *quic.DatagramTooLargeError
 	require.ErrorAs(t, err, &datagramErr)
 	initialMaxDatagramSize := datagramErr.MaxDatagramPayloadSize
+	require.Equal(t, initialMaxDatagramSize, conn.MaxDatagramPayloadSize())
 
 	str, err := conn.OpenStream()
 	require.NoError(t, err)
@@ -163,6 +164,7 @@ func TestPathMTUDiscovery(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorAs(t, err, &datagramErr)
 	finalMaxDatagramSize := datagramErr.MaxDatagramPayloadSize
+	require.Equal(t, finalMaxDatagramSize, conn.MaxDatagramPayloadSize())
 
 	mx.Lock()
 	defer mx.Unlock()
@@ -174,6 +176,7 @@ func TestPathMTUDiscovery(t *testing.T) {
 	}
 
 	maxPacketSizeClient := mtus[len(mtus)-1]
+	require.EqualValues(t, maxPacketSizeClient, conn.ConnectionStats().MTU)
 	t.Logf("max client packet size: %d, MTU: %d", maxPacketSizeClient, mtu)
 	t.Logf("max datagram size: initial: %d, final: %d", initialMaxDatagramSize, finalMaxDatagramSize)
 	t.Logf("max server packet size: %d, MTU: %d", maxPacketSizeServer, mtu)
@@ -0,0 +1,141 @@
+package self_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	quicproxy "github.com/quic-go/quic-go/integrationtests/tools/proxy"
+
+	"github.com/stretchr/testify/require"
+)
+
+type roundRobinPathScheduler struct {
+	next int
+}
+
+func (s *roundRobinPathScheduler) SelectPath(paths []quic.PathInfo) int {
+	for range paths {
+		idx := s.next % len(paths)
+		s.next++
+		if paths[idx].CanSend {
+			return idx
+		}
+	}
+	return -1
+}
+
+func TestMultipath(t *testing.T) {
+	ln, err := quic.ListenAddr("localhost:0", getTLSConfig(), getQuicConfig(&quic.Config{EnableMultipath: true}))
+	require.NoError(t, err)
+	defer ln.Close()
+
+	tr1 := &quic.Transport{Conn: newUDPConnLocalhost(t)}
+	defer tr1.Close()
+	tr2 := &quic.Transport{Conn: newUDPConnLocalhost(t)}
+	defer tr2.Close()
+
+	var packetsPath1, packetsPath2 atomic.Int64
+
+	const rtt = 5 * time.Millisecond
+	proxy := quicproxy.Proxy{
+		Conn:       newUDPConnLocalhost(t),
+		ServerAddr: ln.Addr().(*net.UDPAddr),
+		DelayPacket: func(dir quicproxy.Direction, from, to net.Addr, _ []byte) time.Duration {
+			var port int
+			switch dir {
+			case quicproxy.DirectionIncoming:
+				port = from.(*net.UDPAddr).Port
+			case quicproxy.DirectionOutgoing:
+				port = to.(*net.UDPAddr).Port
+			}
+			switch port {
+			case tr1.Conn.LocalAddr().(*net.UDPAddr).Port:
+				packetsPath1.Add(1)
+			case tr2.Conn.LocalAddr().(*net.UDPAddr).Port:
+				packetsPath2.Add(1)
+			}
+			return rtt / 2
+		},
+	}
+	require.NoError(t, proxy.Start())
+	defer proxy.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := tr1.Dial(ctx, proxy.LocalAddr(), getTLSClientConfig(), getQuicConfig(&quic.Config{EnableMultipath: true}))
+	require.NoError(t, err)
+	defer conn.CloseWithError(0, "")
+	conn.SetPathScheduler(&roundRobinPathScheduler{})
+
+	sconn, err := ln.Accept(ctx)
+	require.NoError(t, err)
+	defer sconn.CloseWithError(0, "")
+	sconn.SetPathScheduler(&roundRobinPathScheduler{})
+
+	require.True(t, conn.ConnectionState().SupportsMultipath)
+	require.True(t, sconn.ConnectionState().SupportsMultipath)
+
+	sendAndReceiveFile := func(t *testing.T) {
+		t.Helper()
+		str, err := conn.OpenStream()
+		require.NoError(t, err)
+
+		errChan := make(chan error, 1)
+		go func() {
+			defer close(errChan)
+			sstr, err := sconn.AcceptStream(ctx)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			if _, err := io.Copy(sstr, sstr); err != nil {
+				errChan <- err
+				return
+			}
+			errChan <- sstr.Close()
+		}()
+
+		_, err = str.Write(PRData)
+		require.NoError(t, err)
+		require.NoError(t, str.Close())
+		data, err := io.ReadAll(str)
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(data, PRData))
+
+		select {
+		case err := <-errChan:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the stream to be closed")
+		}
+	}
+
+	sendAndReceiveFile(t)
+	require.NotZero(t, packetsPath1.Load())
+	require.Zero(t, packetsPath2.Load())
+
+	path, err := conn.AddPath(tr2)
+	require.NoError(t, err)
+	require.NoError(t, path.Probe(ctx))
+	time.Sleep(3 * rtt) // wait for the server to validate the path
+
+	// both paths are used at the same time
+	c1, c2 := packetsPath1.Load(), packetsPath2.Load()
+	sendAndReceiveFile(t)
+	require.Greater(t, packetsPath1.Load()-c1, int64(20))
+	require.Greater(t, packetsPath2.Load()-c2, int64(10))
+
+	// after abandoning the second path, only the first path is used
+	require.NoError(t, path.Close())
+	time.Sleep(3 * rtt)
+	c2 = packetsPath2.Load()
+	sendAndReceiveFile(t)
+	require.LessOrEqual(t, packetsPath2.Load()-c2, int64(2))
+}
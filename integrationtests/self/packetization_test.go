@@ -219,6 +219,8 @@ func testConnAndStreamDataBlocked(t *testing.T, limitStream, limitConn bool) {
 		require.ErrorIs(t, err, os.ErrDeadlineExceeded)
 		require.Equal(t, n, n2)
 	}
+	clientStats := conn.ConnectionStats()
+	serverStats := serverConn.ConnectionStats()
 
 	conn.CloseWithError(0, "")
 	serverConn.CloseWithError(0, "")
@@ -262,6 +264,9 @@ func testConnAndStreamDataBlocked(t *testing.T, limitStream, limitConn bool) {
 			assert.Equal(t, str.StreamID(), f.StreamID)
 			assert.Equal(t, expectedBlockOffsets[i], f.MaximumStreamData)
 		}
+		assert.EqualValues(t, numBatches, clientStats.StreamDataBlockedSent)
+		assert.EqualValues(t, numBatches, serverStats.StreamDataBlockedReceived)
+		assert.Zero(t, clientStats.DataBlockedSent)
 	}
 	if limitConn {
 		assert.Empty(t, streamDataBlockedFrames)
@@ -269,5 +274,8 @@ func testConnAndStreamDataBlocked(t *testing.T, limitStream, limitConn bool) {
 		for i, f := range dataBlockedFrames {
 			assert.Equal(t, expectedBlockOffsets[i], f.MaximumData)
 		}
+		assert.EqualValues(t, numBatches, clientStats.DataBlockedSent)
+		assert.EqualValues(t, numBatches, serverStats.DataBlockedReceived)
+		assert.Zero(t, clientStats.StreamDataBlockedSent)
 	}
 }
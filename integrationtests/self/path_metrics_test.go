@@ -0,0 +1,101 @@
+package self_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/internal/synctest"
+	"github.com/quic-go/quic-go/testutils/simnet"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitialRTT(t *testing.T) {
+	const rtt = 20 * time.Millisecond
+
+	// The client's first flight of the second connection is lost.
+	// It is retransmitted after the PTO, which, before an RTT sample is taken, is twice the initial RTT.
+	t.Run("default", func(t *testing.T) {
+		d := testInitialRTT(t, rtt, nil, 0)
+		require.GreaterOrEqual(t, d, 200*time.Millisecond)
+	})
+	t.Run("Config.InitialRTT", func(t *testing.T) {
+		d := testInitialRTT(t, rtt, nil, rtt)
+		require.Less(t, d, 4*rtt)
+	})
+	t.Run("Transport.PathMetricsCache", func(t *testing.T) {
+		d := testInitialRTT(t, rtt, quic.NewLRUPathMetricsCache(10, time.Hour), 0)
+		require.Less(t, d, 4*rtt)
+	})
+}
+
+// testInitialRTT dials two connections, and returns the duration of the second handshake.
+func testInitialRTT(t *testing.T, rtt time.Duration, cache quic.PathMetricsCache, initialRTT time.Duration) time.Duration {
+	var handshakeDuration time.Duration
+	synctest.Test(t, func(t *testing.T) {
+		serverAddr := &net.UDPAddr{IP: net.ParseIP("1.0.0.2"), Port: 9002}
+		var dropUntil atomic.Pointer[time.Time]
+		clientPacketConn, serverPacketConn, close := newSimnetLinkWithRouter(t, rtt, &droppingRouter{
+			Drop: func(p simnet.Packet) bool {
+				// only drop Initial packets
+				if p.To.String() != serverAddr.String() || p.Data[0]&0xf0 != 0xc0 {
+					return false
+				}
+				deadline := dropUntil.Load()
+				return deadline != nil && time.Now().Before(*deadline)
+			},
+		})
+		defer close(t)
+
+		var serverCache quic.PathMetricsCache
+		if cache != nil {
+			serverCache = quic.NewLRUPathMetricsCache(10, time.Hour)
+		}
+		serverTr := &quic.Transport{Conn: serverPacketConn, PathMetricsCache: serverCache}
+		defer serverTr.Close()
+		ln, err := serverTr.Listen(getTLSConfig(), getQuicConfig(nil))
+		require.NoError(t, err)
+		defer ln.Close()
+
+		clientTr := &quic.Transport{Conn: clientPacketConn, PathMetricsCache: cache}
+		defer clientTr.Close()
+		clientConf := getQuicConfig(&quic.Config{InitialRTT: initialRTT})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		conn, err := clientTr.Dial(ctx, serverPacketConn.LocalAddr(), getTLSClientConfig(), clientConf)
+		require.NoError(t, err)
+		serverConn, err := ln.Accept(ctx)
+		require.NoError(t, err)
+		require.NoError(t, conn.CloseWithError(0, ""))
+		<-serverConn.Context().Done()
+		synctest.Wait()
+
+		if cache != nil {
+			// the metrics were saved on both sides
+			m, ok := cache.Get(serverPacketConn.LocalAddr())
+			require.True(t, ok)
+			require.Equal(t, rtt, m.SmoothedRTT)
+			require.Equal(t, rtt, m.MinRTT)
+			require.NotZero(t, m.BandwidthEstimate)
+			m, ok = serverCache.Get(clientPacketConn.LocalAddr())
+			require.True(t, ok)
+			require.Equal(t, rtt, m.SmoothedRTT)
+		}
+
+		// Drop the client's first flight.
+		// Packets pass the router after the uplink latency of rtt/4.
+		start := time.Now()
+		deadline := start.Add(rtt / 2)
+		dropUntil.Store(&deadline)
+		conn, err = clientTr.Dial(ctx, serverPacketConn.LocalAddr(), getTLSClientConfig(), clientConf)
+		require.NoError(t, err)
+		defer conn.CloseWithError(0, "")
+		handshakeDuration = time.Since(start)
+	})
+	return handshakeDuration
+}
@@ -0,0 +1,122 @@
+package self_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/pcapng"
+
+	"github.com/stretchr/testify/require"
+)
+
+type lockedBuffer struct {
+	mx  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+// parseCapture returns the UDP payloads and the TLS key log lines contained in a pcapng capture
+func parseCapture(t *testing.T, data []byte) (packets [][]byte, keyLog []string) {
+	t.Helper()
+	for len(data) > 0 {
+		blockType := binary.LittleEndian.Uint32(data)
+		blockLen := binary.LittleEndian.Uint32(data[4:])
+		body := data[8 : blockLen-4]
+		switch blockType {
+		case 0x6: // Enhanced Packet Block
+			ip := body[20 : 20+binary.LittleEndian.Uint32(body[12:])]
+			ipHeaderLen := 40
+			if ip[0]>>4 == 4 {
+				ipHeaderLen = 20
+			}
+			packets = append(packets, ip[ipHeaderLen+8:])
+		case 0xa: // Decryption Secrets Block
+			require.Equal(t, uint32(0x544c534b), binary.LittleEndian.Uint32(body))
+			l := binary.LittleEndian.Uint32(body[4:])
+			keyLog = append(keyLog, strings.Split(strings.TrimSpace(string(body[8:8+l])), "\n")...)
+		}
+		data = data[blockLen:]
+	}
+	return packets, keyLog
+}
+
+func TestPacketCapture(t *testing.T) {
+	var serverBuf, clientBuf lockedBuffer
+	serverCapture, err := pcapng.NewWriter(&serverBuf)
+	require.NoError(t, err)
+	clientCapture, err := pcapng.NewWriter(&clientBuf)
+	require.NoError(t, err)
+
+	serverTr := &quic.Transport{Conn: newUDPConnLocalhost(t), PacketCapture: serverCapture}
+	defer serverTr.Close()
+	ln, err := serverTr.Listen(getTLSConfig(), getQuicConfig(nil))
+	require.NoError(t, err)
+	defer ln.Close()
+
+	clientTr := &quic.Transport{Conn: newUDPConnLocalhost(t), PacketCapture: clientCapture}
+	defer clientTr.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := clientTr.Dial(ctx, ln.Addr(), getTLSClientConfig(), getQuicConfig(nil))
+	require.NoError(t, err)
+	serverConn, err := ln.Accept(ctx)
+	require.NoError(t, err)
+
+	str, err := conn.OpenUniStream()
+	require.NoError(t, err)
+	_, err = str.Write([]byte("foobar"))
+	require.NoError(t, err)
+	require.NoError(t, str.Close())
+	sstr, err := serverConn.AcceptUniStream(ctx)
+	require.NoError(t, err)
+	data, err := io.ReadAll(sstr)
+	require.NoError(t, err)
+	require.Equal(t, []byte("foobar"), data)
+
+	conn.CloseWithError(0, "")
+	select {
+	case <-serverConn.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	serverPackets, serverKeyLog := parseCapture(t, serverBuf.Bytes())
+	clientPackets, clientKeyLog := parseCapture(t, clientBuf.Bytes())
+	require.NotEmpty(t, clientPackets)
+	require.NotEmpty(t, serverPackets)
+	// the first packet is the client's Initial
+	require.Equal(t, byte(0xc0), clientPackets[0][0]&0xf0)
+	require.Equal(t, clientPackets[0], serverPackets[0])
+
+	// both sides derive the same secrets
+	require.ElementsMatch(t, clientKeyLog, serverKeyLog)
+	var labels []string
+	for _, line := range clientKeyLog {
+		labels = append(labels, strings.Fields(line)[0])
+	}
+	require.ElementsMatch(t, []string{
+		"CLIENT_HANDSHAKE_TRAFFIC_SECRET",
+		"SERVER_HANDSHAKE_TRAFFIC_SECRET",
+		"CLIENT_TRAFFIC_SECRET_0",
+		"SERVER_TRAFFIC_SECRET_0",
+	}, labels)
+}
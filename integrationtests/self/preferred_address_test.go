@@ -0,0 +1,247 @@
+package self_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	quicproxy "github.com/quic-go/quic-go/integrationtests/tools/proxy"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+	"github.com/quic-go/quic-go/testutils/events"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerPreferredAddress(t *testing.T) {
+	preferredTr := &quic.Transport{
+		Conn:              newUDPConnLocalhost(t),
+		StatelessResetKey: &quic.StatelessResetKey{},
+	}
+	addTracer(preferredTr)
+	defer preferredTr.Close()
+	preferredAddr := preferredTr.Conn.LocalAddr().(*net.UDPAddr)
+
+	tr := &quic.Transport{
+		Conn:              newUDPConnLocalhost(t),
+		StatelessResetKey: &quic.StatelessResetKey{},
+	}
+	addTracer(tr)
+	defer tr.Close()
+	ln, err := tr.Listen(
+		getTLSConfig(),
+		getQuicConfig(&quic.Config{
+			PreferredAddress: &quic.PreferredAddress{
+				IPv4:      preferredAddr.AddrPort(),
+				Transport: preferredTr,
+			},
+		}),
+	)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	// The proxy allows us to redirect the client's packets to the preferred address,
+	// without the client noticing.
+	proxy := quicproxy.Proxy{
+		ServerAddr: ln.Addr().(*net.UDPAddr),
+		Conn:       newUDPConnLocalhost(t),
+	}
+	require.NoError(t, proxy.Start())
+	defer proxy.Close()
+
+	var clientEvents events.Recorder
+	clientUDPConn := newUDPConnLocalhost(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := quic.Dial(
+		ctx,
+		clientUDPConn,
+		proxy.LocalAddr(),
+		getTLSClientConfig(),
+		getQuicConfig(&quic.Config{
+			Tracer: func(context.Context, bool, quic.ConnectionID) qlogwriter.Trace {
+				return &events.Trace{Recorder: &clientEvents}
+			},
+			// The migration is simulated by the proxy.
+			DisablePreferredAddressMigration: true,
+		}),
+	)
+	require.NoError(t, err)
+	defer conn.CloseWithError(0, "")
+
+	// the client received the preferred address in the server's transport parameters
+	var params *qlog.ParametersSet
+	for _, ev := range clientEvents.Events(qlog.ParametersSet{}) {
+		if p := ev.(qlog.ParametersSet); p.Initiator == qlog.InitiatorRemote {
+			params = &p
+		}
+	}
+	require.NotNil(t, params)
+	require.NotNil(t, params.PreferredAddress)
+	require.Equal(t, preferredAddr.AddrPort(), params.PreferredAddress.IPv4)
+	require.False(t, params.PreferredAddress.IPv6.IsValid())
+	require.Equal(t, 4, params.PreferredAddress.ConnectionID.Len())
+
+	serverConn, err := ln.Accept(ctx)
+	require.NoError(t, err)
+	defer serverConn.CloseWithError(0, "")
+	require.Equal(t, ln.Addr().String(), serverConn.LocalAddr().String())
+
+	transfer := func() {
+		t.Helper()
+		str, err := serverConn.OpenUniStream()
+		require.NoError(t, err)
+		_, err = str.Write(PRData)
+		require.NoError(t, err)
+		require.NoError(t, str.Close())
+		rstr, err := conn.AcceptUniStream(ctx)
+		require.NoError(t, err)
+		rstr.SetReadDeadline(time.Now().Add(5 * time.Second))
+		data, err := io.ReadAll(rstr)
+		require.NoError(t, err)
+		require.Equal(t, PRData, data)
+	}
+
+	// clientTransfer sends data from the client, i.e. in non-probing packets
+	clientTransfer := func() {
+		t.Helper()
+		str, err := conn.OpenUniStream()
+		require.NoError(t, err)
+		_, err = str.Write([]byte("foobar"))
+		require.NoError(t, err)
+		require.NoError(t, str.Close())
+		rstr, err := serverConn.AcceptUniStream(ctx)
+		require.NoError(t, err)
+		rstr.SetReadDeadline(time.Now().Add(5 * time.Second))
+		data, err := io.ReadAll(rstr)
+		require.NoError(t, err)
+		require.Equal(t, []byte("foobar"), data)
+	}
+
+	transfer()
+
+	require.NoError(t, proxy.SwitchServerAddr(clientUDPConn.LocalAddr().(*net.UDPAddr), preferredAddr))
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// The server only switches to the preferred address when it receives a non-probing packet
+	// on the path after validating it. Keep the client sending until that happens.
+	for serverConn.LocalAddr().String() != preferredAddr.String() {
+		require.NoError(t, ctx.Err(), "server didn't switch to the preferred address")
+		clientTransfer()
+		time.Sleep(10 * time.Millisecond)
+	}
+	transfer()
+}
+
+func TestClientMigrationToPreferredAddress(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		testClientMigrationToPreferredAddress(t, true)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		testClientMigrationToPreferredAddress(t, false)
+	})
+}
+
+func testClientMigrationToPreferredAddress(t *testing.T, enabled bool) {
+	preferredTr := &quic.Transport{
+		Conn:              newUDPConnLocalhost(t),
+		StatelessResetKey: &quic.StatelessResetKey{},
+	}
+	addTracer(preferredTr)
+	defer preferredTr.Close()
+	preferredAddr := preferredTr.Conn.LocalAddr().(*net.UDPAddr)
+
+	tr := &quic.Transport{
+		Conn:              newUDPConnLocalhost(t),
+		StatelessResetKey: &quic.StatelessResetKey{},
+	}
+	addTracer(tr)
+	defer tr.Close()
+	ln, err := tr.Listen(
+		getTLSConfig(),
+		getQuicConfig(&quic.Config{
+			PreferredAddress: &quic.PreferredAddress{
+				IPv4:      preferredAddr.AddrPort(),
+				Transport: preferredTr,
+			},
+		}),
+	)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	var clientEvents events.Recorder
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := quic.Dial(
+		ctx,
+		newUDPConnLocalhost(t),
+		ln.Addr(),
+		getTLSClientConfig(),
+		getQuicConfig(&quic.Config{
+			Tracer: func(context.Context, bool, quic.ConnectionID) qlogwriter.Trace {
+				return &events.Trace{Recorder: &clientEvents}
+			},
+			DisablePreferredAddressMigration: !enabled,
+		}),
+	)
+	require.NoError(t, err)
+	defer conn.CloseWithError(0, "")
+
+	serverConn, err := ln.Accept(ctx)
+	require.NoError(t, err)
+	defer serverConn.CloseWithError(0, "")
+
+	transfer := func() {
+		t.Helper()
+		str, err := serverConn.OpenUniStream()
+		require.NoError(t, err)
+		_, err = str.Write(PRData)
+		require.NoError(t, err)
+		require.NoError(t, str.Close())
+		rstr, err := conn.AcceptUniStream(ctx)
+		require.NoError(t, err)
+		data, err := io.ReadAll(rstr)
+		require.NoError(t, err)
+		require.Equal(t, PRData, data)
+	}
+
+	transfer()
+
+	if !enabled {
+		// give the client some time to (not) probe the preferred address
+		time.Sleep(scaleDuration(50 * time.Millisecond))
+		require.Equal(t, ln.Addr().String(), conn.RemoteAddr().String())
+		require.Equal(t, ln.Addr().String(), serverConn.LocalAddr().String())
+		require.Empty(t, clientEvents.Events(qlog.MigrationStateUpdated{}))
+		return
+	}
+
+	require.Eventually(t, func() bool {
+		return conn.RemoteAddr().String() == preferredAddr.String()
+	}, 2*time.Second, 10*time.Millisecond)
+	// the server switches once it receives non-probing packets from the client on the new path
+	transfer()
+	require.Eventually(t, func() bool {
+		return serverConn.LocalAddr().String() == preferredAddr.String()
+	}, 2*time.Second, 10*time.Millisecond)
+	transfer()
+
+	var states []qlog.MigrationState
+	for _, ev := range clientEvents.Events(qlog.MigrationStateUpdated{}) {
+		e := ev.(qlog.MigrationStateUpdated)
+		require.Equal(t, preferredAddr.AddrPort(), e.Remote.IPv4)
+		states = append(states, e.State)
+	}
+	require.Equal(t,
+		[]qlog.MigrationState{
+			qlog.MigrationStateProbingStarted,
+			qlog.MigrationStateProbingSuccessful,
+			qlog.MigrationStateMigrationComplete,
+		},
+		states,
+	)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/quic-go/quic-go"
 	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogreader"
 
 	"github.com/stretchr/testify/require"
 )
@@ -72,4 +73,31 @@ func TestQlogDirEnvironmentVariable(t *testing.T) {
 	require.Equal(t, odcids[0], odcids[1])
 	require.Contains(t, vantagePoints, "client")
 	require.Contains(t, vantagePoints, "server")
+
+	// the qlog files can be parsed again
+	for i, child := range childs {
+		f, err := os.Open(path.Join(qlogDir, child.Name()))
+		require.NoError(t, err)
+		defer f.Close()
+		r, err := qlogreader.NewReader(f)
+		require.NoError(t, err)
+		require.Equal(t, vantagePoints[i], r.Header().VantagePoint)
+		require.Equal(t, odcids[i], r.Header().GroupID)
+		events, err := r.ReadAll()
+		require.NoError(t, err)
+
+		var numParametersSet, numPacketsSent int
+		for _, ev := range events {
+			switch ev.Event.(type) {
+			case qlog.ParametersSet:
+				numParametersSet++
+			case qlog.PacketSent:
+				numPacketsSent++
+			case qlogreader.RawEvent:
+				t.Fatalf("failed to decode event %s", ev.Event.Name())
+			}
+		}
+		require.Equal(t, 2, numParametersSet) // one for each direction
+		require.NotZero(t, numPacketsSent)
+	}
 }
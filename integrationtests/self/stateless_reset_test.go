@@ -1,6 +1,7 @@
 package self_test
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"sync/atomic"
@@ -16,14 +17,17 @@ import (
 
 func TestStatelessResets(t *testing.T) {
 	t.Run("zero-length connection IDs", func(t *testing.T) {
-		testStatelessReset(t, 0)
+		testStatelessReset(t, 0, false)
 	})
 	t.Run("10 byte connection IDs", func(t *testing.T) {
-		testStatelessReset(t, 10)
+		testStatelessReset(t, 10, false)
+	})
+	t.Run("key rotation", func(t *testing.T) {
+		testStatelessReset(t, 10, true)
 	})
 }
 
-func testStatelessReset(t *testing.T, connIDLen int) {
+func testStatelessReset(t *testing.T, connIDLen int, rotateKeys bool) {
 	synctest.Test(t, func(t *testing.T) {
 		var drop atomic.Bool
 		clientPacketConn, serverPacketConn, closeFn := newSimnetLinkWithRouter(t,
@@ -34,10 +38,13 @@ func testStatelessReset(t *testing.T, connIDLen int) {
 
 		var statelessResetKey quic.StatelessResetKey
 		rand.Read(statelessResetKey[:])
+		keyRing := quic.NewKeyRing(statelessResetKey)
 
-		tr := &quic.Transport{
-			Conn:              serverPacketConn,
-			StatelessResetKey: &statelessResetKey,
+		tr := &quic.Transport{Conn: serverPacketConn}
+		if rotateKeys {
+			tr.StatelessResetKeys = keyRing
+		} else {
+			tr.StatelessResetKey = &statelessResetKey
 		}
 		defer tr.Close()
 
@@ -105,9 +112,18 @@ func testStatelessReset(t *testing.T, connIDLen int) {
 
 		// We need to create a new Transport here, since the old one is still sending out
 		// CONNECTION_CLOSE packets for (recently) closed connections).
-		tr2 := &quic.Transport{
-			Conn:              serverPacketConn,
-			StatelessResetKey: &statelessResetKey,
+		tr2 := &quic.Transport{Conn: serverPacketConn}
+		msg := []byte("Lorem ipsum dolor sit amet.")
+		if rotateKeys {
+			// The token for the connection ID was derived from the old key.
+			var newKey quic.StatelessResetKey
+			rand.Read(newKey[:])
+			keyRing.SetKeys(newKey, statelessResetKey)
+			tr2.StatelessResetKeys = keyRing
+			// The packet needs to be large enough to trigger one stateless reset per key.
+			msg = bytes.Repeat(msg, 4)
+		} else {
+			tr2.StatelessResetKey = &statelessResetKey
 		}
 		defer tr2.Close()
 		ln2, err := tr2.Listen(getTLSConfig(), getQuicConfig(nil))
@@ -116,7 +132,7 @@ func testStatelessReset(t *testing.T, connIDLen int) {
 
 		// Trigger something (not too small) to be sent, so that we receive the stateless reset.
 		// If the client already sent another packet, it might already have received a packet.
-		_, serr := str.Write([]byte("Lorem ipsum dolor sit amet."))
+		_, serr := str.Write(msg)
 		if serr == nil {
 			_, serr = str.Read([]byte{0})
 		}
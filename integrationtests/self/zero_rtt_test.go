@@ -7,6 +7,7 @@ import (
 	"io"
 	"net"
 	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -774,6 +775,171 @@ func Test0RTTRejectedWhenDisabled(t *testing.T) {
 	require.Empty(t, counter.getRcvd0RTTPacketNumbers())
 }
 
+// reusingClientSessionCache doesn't store new session tickets,
+// so that the same session ticket is used for all connections.
+type reusingClientSessionCache struct {
+	tls.ClientSessionCache
+}
+
+func (reusingClientSessionCache) Put(string, *tls.ClientSessionState) {}
+
+func Test0RTTRejectedOnReplay(t *testing.T) {
+	const rtt = 5 * time.Millisecond
+	tlsConf := getTLSConfig()
+	clientConf := dialAndReceiveTicket(t, rtt, tlsConf, getQuicConfig(&quic.Config{Allow0RTT: true}), nil)
+	clientConf.ClientSessionCache = reusingClientSessionCache{clientConf.ClientSessionCache}
+
+	counter, tracer := newPacketTracer()
+	ln, err := quic.ListenEarly(
+		newUDPConnLocalhost(t),
+		tlsConf,
+		getQuicConfig(&quic.Config{
+			Allow0RTT:  true,
+			AntiReplay: quic.NewInMemoryAntiReplayStore(time.Minute),
+			Tracer:     func(context.Context, bool, quic.ConnectionID) qlogwriter.Trace { return tracer },
+		}),
+	)
+	require.NoError(t, err)
+	defer ln.Close()
+	proxy, _ := runCountingProxyAndCount0RTTPackets(t, ln.Addr().(*net.UDPAddr).Port, rtt)
+
+	// the first connection using the session ticket uses 0-RTT
+	transfer0RTTData(t, ln, proxy.LocalAddr(), clientConf, getQuicConfig(nil), []byte("foobar"))
+	require.NotEmpty(t, counter.getRcvd0RTTPacketNumbers())
+
+	// the session ticket is reused, and the connection falls back to 1-RTT
+	counter, tracer = newPacketTracer()
+	conn, serverConn := check0RTTRejected(t, ln, proxy.LocalAddr(), clientConf, true)
+	defer conn.CloseWithError(0, "")
+	serverConn.CloseWithError(0, "")
+	require.True(t, conn.ConnectionState().TLS.DidResume)
+	require.Empty(t, counter.getRcvd0RTTPacketNumbers())
+}
+
+func Test0RTTAfterClientRestart(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "tokens.json")
+	sessionFile := filepath.Join(dir, "sessions.json")
+
+	addrVerifiedChan := make(chan bool, 2)
+	serverConf := getQuicConfig(&quic.Config{Allow0RTT: true})
+	serverConf.GetConfigForClient = func(info *quic.ClientInfo) (*quic.Config, error) {
+		addrVerifiedChan <- info.AddrVerified
+		return serverConf, nil
+	}
+	ln, err := quic.ListenEarly(newUDPConnLocalhost(t), getTLSConfig(), serverConf)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	// newClientConfigs simulates a client (re)start, loading the state from disk
+	newClientConfigs := func() (*tls.Config, *quic.Config) {
+		tokenStore, err := quic.NewFileTokenStore(tokenFile, 10, 4, time.Hour)
+		require.NoError(t, err)
+		sessionCache, err := quic.NewFileClientSessionCache(sessionFile, 10, time.Hour)
+		require.NoError(t, err)
+		tlsConf := getTLSClientConfig()
+		tlsConf.ClientSessionCache = sessionCache
+		return tlsConf, getQuicConfig(&quic.Config{TokenStore: tokenStore})
+	}
+
+	clientTLSConf, clientConf := newClientConfigs()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := quic.Dial(ctx, newUDPConnLocalhost(t), ln.Addr(), clientTLSConf, clientConf)
+	require.NoError(t, err)
+	serverConn, err := ln.Accept(ctx)
+	require.NoError(t, err)
+	require.False(t, <-addrVerifiedChan)
+	// wait for the session ticket and the token to be written to disk
+	require.Eventually(t, func() bool {
+		_, err1 := os.Stat(tokenFile)
+		_, err2 := os.Stat(sessionFile)
+		return err1 == nil && err2 == nil
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, conn.CloseWithError(0, ""))
+	select {
+	case <-serverConn.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for connection to close")
+	}
+
+	clientTLSConf, clientConf = newClientConfigs()
+	transfer0RTTData(t, ln, ln.Addr(), clientTLSConf, clientConf, PRData)
+	// the address was validated using the token
+	require.True(t, <-addrVerifiedChan)
+}
+
+func Test0RTTAdditionalTransportParameters(t *testing.T) {
+	const rtt = 5 * time.Millisecond
+	tlsConf := getTLSConfig()
+	serverConf := getQuicConfig(&quic.Config{
+		Allow0RTT:                     true,
+		AdditionalTransportParameters: map[uint64][]byte{0x1337: []byte("foo")},
+	})
+	clientConf := dialAndReceiveTicket(t, rtt, tlsConf, serverConf, nil)
+
+	ln, err := quic.ListenEarly(newUDPConnLocalhost(t), tlsConf, serverConf)
+	require.NoError(t, err)
+	defer ln.Close()
+	proxy, _ := runCountingProxyAndCount0RTTPackets(t, ln.Addr().(*net.UDPAddr).Port, rtt)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := quic.DialEarly(ctx, newUDPConnLocalhost(t), proxy.LocalAddr(), clientConf, getQuicConfig(nil))
+	require.NoError(t, err)
+	defer conn.CloseWithError(0, "")
+	// the transport parameters are restored from the session ticket
+	require.False(t, conn.ConnectionState().TLS.HandshakeComplete)
+	require.Equal(t, map[uint64][]byte{0x1337: []byte("foo")}, conn.ConnectionState().AdditionalTransportParameters)
+
+	select {
+	case <-conn.HandshakeComplete():
+	case <-time.After(time.Second):
+		t.Fatal("handshake did not complete in time")
+	}
+	require.True(t, conn.ConnectionState().Used0RTT)
+	require.Equal(t, map[uint64][]byte{0x1337: []byte("foo")}, conn.ConnectionState().AdditionalTransportParameters)
+}
+
+func Test0RTTRejectedOnAdditionalTransportParametersChanged(t *testing.T) {
+	const rtt = 5 * time.Millisecond
+	tlsConf := getTLSConfig()
+	clientConf := dialAndReceiveTicket(t,
+		rtt,
+		tlsConf,
+		getQuicConfig(&quic.Config{
+			Allow0RTT:                     true,
+			AdditionalTransportParameters: map[uint64][]byte{0x1337: []byte("foo")},
+		}),
+		nil,
+	)
+
+	counter, tracer := newPacketTracer()
+	ln, err := quic.ListenEarly(
+		newUDPConnLocalhost(t),
+		tlsConf,
+		getQuicConfig(&quic.Config{
+			Allow0RTT:                     true,
+			AdditionalTransportParameters: map[uint64][]byte{0x1337: []byte("bar")},
+			Tracer:                        func(context.Context, bool, quic.ConnectionID) qlogwriter.Trace { return tracer },
+		}),
+	)
+	require.NoError(t, err)
+	defer ln.Close()
+	proxy, num0RTTPackets := runCountingProxyAndCount0RTTPackets(t, ln.Addr().(*net.UDPAddr).Port, rtt)
+
+	conn, serverConn := check0RTTRejected(t, ln, proxy.LocalAddr(), clientConf, true)
+	defer conn.CloseWithError(0, "")
+	require.Equal(t, map[uint64][]byte{0x1337: []byte("bar")}, conn.ConnectionState().AdditionalTransportParameters)
+
+	serverConn.CloseWithError(0, "")
+	// The client should send 0-RTT packets, but the server doesn't process them.
+	num0RTT := num0RTTPackets.Load()
+	t.Logf("Sent %d 0-RTT packets.", num0RTT)
+	require.NotZero(t, num0RTT)
+	require.Empty(t, counter.getRcvd0RTTPacketNumbers())
+}
+
 func Test0RTTRejectedOnDatagramsDisabled(t *testing.T) {
 	const rtt = 5 * time.Millisecond
 	tlsConf := getTLSConfig()
@@ -17,9 +17,9 @@ import (
 // Connection is a UDP connection
 type connection struct {
 	ClientAddr *net.UDPAddr // Address of the client
-	ServerAddr *net.UDPAddr // Address of the server
 
 	mx         sync.Mutex
+	ServerAddr *net.UDPAddr // Address of the server
 	ServerConn *net.UDPConn // UDP connection to server
 
 	incomingPackets chan packetEntry
@@ -41,6 +41,20 @@ func (c *connection) SwitchConn(conn *net.UDPConn) {
 	c.ServerConn = conn
 }
 
+func (c *connection) SwitchServerAddr(addr *net.UDPAddr) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.ServerAddr = addr
+}
+
+func (c *connection) GetServerAddr() *net.UDPAddr {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.ServerAddr
+}
+
 func (c *connection) GetServerConn() *net.UDPConn {
 	c.mx.Lock()
 	defer c.mx.Unlock()
@@ -203,6 +217,19 @@ func (p *Proxy) SwitchConn(clientAddr *net.UDPAddr, conn *net.UDPConn) error {
 	return nil
 }
 
+// SwitchServerAddr switches the server address that packets from a client are forwarded to,
+// identified the address that the client is sending from.
+func (p *Proxy) SwitchServerAddr(clientAddr, serverAddr *net.UDPAddr) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	c, ok := p.clientDict[clientAddr.String()]
+	if !ok {
+		return fmt.Errorf("client %s not found", clientAddr)
+	}
+	c.SwitchServerAddr(serverAddr)
+	return nil
+}
+
 // Close stops the UDP Proxy
 func (p *Proxy) Close() error {
 	p.mutex.Lock()
@@ -268,7 +295,7 @@ func (p *Proxy) runProxy() error {
 		}
 		p.mutex.Unlock()
 
-		if p.DropPacket != nil && p.DropPacket(DirectionIncoming, cliaddr, conn.ServerAddr, raw) {
+		if p.DropPacket != nil && p.DropPacket(DirectionIncoming, cliaddr, conn.GetServerAddr(), raw) {
 			if p.logger.Debug() {
 				p.logger.Debugf("dropping incoming packet(%d bytes)", n)
 			}
@@ -277,19 +304,19 @@ func (p *Proxy) runProxy() error {
 
 		var delay time.Duration
 		if p.DelayPacket != nil {
-			delay = p.DelayPacket(DirectionIncoming, cliaddr, conn.ServerAddr, raw)
+			delay = p.DelayPacket(DirectionIncoming, cliaddr, conn.GetServerAddr(), raw)
 		}
 		if delay == 0 {
 			if p.logger.Debug() {
-				p.logger.Debugf("forwarding incoming packet (%d bytes) to %s", len(raw), conn.ServerAddr)
+				p.logger.Debugf("forwarding incoming packet (%d bytes) to %s", len(raw), conn.GetServerAddr())
 			}
-			if _, err := conn.GetServerConn().WriteTo(raw, conn.ServerAddr); err != nil {
+			if _, err := conn.GetServerConn().WriteTo(raw, conn.GetServerAddr()); err != nil {
 				return err
 			}
 		} else {
 			now := monotime.Now()
 			if p.logger.Debug() {
-				p.logger.Debugf("delaying incoming packet (%d bytes) to %s by %s", len(raw), conn.ServerAddr, delay)
+				p.logger.Debugf("delaying incoming packet (%d bytes) to %s by %s", len(raw), conn.GetServerAddr(), delay)
 			}
 			conn.queuePacket(now.Add(delay), raw)
 		}
@@ -364,7 +391,7 @@ func (p *Proxy) runIncomingConnection(conn *connection) error {
 			// Send the packet to the server
 			conn.Incoming.Add(e)
 		case <-conn.Incoming.Timer():
-			if _, err := conn.GetServerConn().WriteTo(conn.Incoming.Get(), conn.ServerAddr); err != nil {
+			if _, err := conn.GetServerConn().WriteTo(conn.Incoming.Get(), conn.GetServerAddr()); err != nil {
 				return err
 			}
 		}
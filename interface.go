@@ -5,9 +5,11 @@ import (
 	"crypto/tls"
 	"errors"
 	"net"
+	"net/netip"
 	"slices"
 	"time"
 
+	"github.com/quic-go/quic-go/congestion"
 	"github.com/quic-go/quic-go/internal/handshake"
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/qlogwriter"
@@ -16,6 +18,30 @@ import (
 // The StreamID is the ID of a QUIC stream.
 type StreamID = protocol.StreamID
 
+const (
+	// MaxStreamUrgency is the lowest urgency (i.e. the largest value) that a stream can have.
+	MaxStreamUrgency = 7
+	// DefaultStreamUrgency is the urgency of a newly opened stream.
+	DefaultStreamUrgency = 3
+)
+
+// StreamPriority is the priority of a stream.
+// It uses the same semantics as the Extensible Prioritization Scheme for HTTP (RFC 9218).
+// The priority only affects the order in which data is sent; it is not communicated to the peer.
+// Newly opened streams use DefaultStreamUrgency and are incremental,
+// such that streams share the available bandwidth unless priorities are set.
+// Note that this differs from RFC 9218, which defaults to non-incremental.
+type StreamPriority struct {
+	// Urgency is the urgency of the stream, between 0 and MaxStreamUrgency.
+	// Data on streams with a lower value is sent before data on streams with a higher value.
+	// Values larger than MaxStreamUrgency are treated as MaxStreamUrgency.
+	Urgency uint8
+	// Incremental says if the stream can make use of data that's delivered incrementally.
+	// Incremental streams of the same urgency share the available bandwidth (round-robin),
+	// while non-incremental streams of the same urgency are sent one after the other.
+	Incremental bool
+}
+
 // A Version is a QUIC version number.
 type Version = protocol.Version
 
@@ -50,6 +76,34 @@ type TokenStore interface {
 	Put(key string, token *ClientToken)
 }
 
+// PathMetrics are the metrics of a network path to a remote address.
+type PathMetrics struct {
+	SmoothedRTT time.Duration
+	MinRTT      time.Duration
+	// BandwidthEstimate is the congestion controller's bandwidth estimate, in bits per second.
+	// It is 0 if the congestion controller doesn't provide an estimate.
+	BandwidthEstimate uint64
+}
+
+// A PathMetricsCache caches the metrics of network paths, see Transport.PathMetricsCache.
+type PathMetricsCache interface {
+	// Get returns the metrics for the given remote address.
+	Get(addr net.Addr) (PathMetrics, bool)
+	// Put saves the metrics for the given remote address.
+	// It is called when a connection is closed.
+	Put(addr net.Addr, metrics PathMetrics)
+}
+
+// A ZeroRTTAttempt describes a client's attempt to send 0-RTT data.
+type ZeroRTTAttempt = handshake.ZeroRTTAttempt
+
+// An AntiReplayStore is consulted by the server before accepting 0-RTT.
+// crypto/tls doesn't protect against replays of 0-RTT data, see section 8 of RFC 8446.
+// To detect replays across multiple server instances, the store needs to be shared between them.
+// Implementations can, for example, ensure that every session ticket is only used once (using the TicketID),
+// or record ClientHellos received within a time window (using the ClientHelloHash).
+type AntiReplayStore = handshake.AntiReplayStore
+
 // Err0RTTRejected is the returned from:
 //   - Open{Uni}Stream{Sync}
 //   - Accept{Uni}Stream
@@ -82,6 +136,21 @@ type StatelessResetKey [32]byte
 // TokenGeneratorKey is a key used to encrypt session resumption tokens.
 type TokenGeneratorKey = handshake.TokenProtectorKey
 
+// A StatelessResetKeyRing provides the keys used to derive stateless reset tokens.
+// Stateless reset tokens for new connection IDs are derived from the current key.
+// Since it's not known which key was used to derive the token for a given connection ID,
+// stateless resets are sent for tokens derived from the current and all previous keys.
+// Keys may be changed at any time, so Keys must be safe for concurrent use.
+type StatelessResetKeyRing interface {
+	Keys() (current StatelessResetKey, previous []StatelessResetKey)
+}
+
+// A TokenGeneratorKeyRing provides the keys used to encrypt Retry and session resumption tokens.
+// New tokens are encrypted using the current key.
+// When decoding a token, the current key is tried first, followed by the previous keys.
+// Keys may be changed at any time, so Keys must be safe for concurrent use.
+type TokenGeneratorKeyRing = handshake.TokenProtectorKeyRing
+
 // A ConnectionID is a QUIC Connection ID, as defined in RFC 9000.
 // It is not able to handle QUIC Connection IDs longer than 20 bytes,
 // as they are allowed by RFC 8999.
@@ -167,10 +236,26 @@ type Config struct {
 	// If set to a negative value, it doesn't allow any unidirectional streams.
 	// Values larger than 2^60 will be clipped to that value.
 	MaxIncomingUniStreams int64
+	// KeyUpdatePacketInterval is the maximum number of packets sent or received with the same 1-RTT keys
+	// before a key update is initiated, see section 6 of RFC 9001.
+	// If zero, it defaults to 100,000 packets. Larger values will be clipped to that value.
+	KeyUpdatePacketInterval uint64
+	// KeyUpdateTimeInterval is the maximum duration that the same 1-RTT keys are used
+	// before a key update is initiated.
+	// The key update is performed when the next packet is sent after the interval elapsed,
+	// and only once the peer acknowledged a packet sent with the current keys.
+	// If zero, keys are not updated based on their age.
+	KeyUpdateTimeInterval time.Duration
 	// KeepAlivePeriod defines whether this peer will periodically send a packet to keep the connection alive.
 	// If set to 0, then no keep alive is sent. Otherwise, the keep alive is sent on that period (or at most
 	// every half of MaxIdleTimeout, whichever is smaller).
 	KeepAlivePeriod time.Duration
+	// InitialRTT is the RTT estimate used before the first RTT sample is taken.
+	// It determines the probe timeout and the pacing rate at the beginning of a connection.
+	// An RTT restored from an address validation token, or from the Transport's PathMetricsCache,
+	// takes precedence over this value.
+	// If zero, an initial RTT of 100ms is used.
+	InitialRTT time.Duration
 	// InitialPacketSize is the initial size (and the lower limit) for packets sent.
 	// Under most circumstances, it is not necessary to manually set this value,
 	// since path MTU discovery quickly finds the path's MTU.
@@ -181,18 +266,84 @@ type Config struct {
 	// This allows the sending of QUIC packets that fully utilize the available MTU of the path.
 	// Path MTU discovery is only available on systems that allow setting of the Don't Fragment (DF) bit.
 	DisablePathMTUDiscovery bool
+	// CongestionControl creates the congestion controller for a connection.
+	// It is called when the connection is established, and every time the connection migrates to a new path.
+	// The returned SendAlgorithm must not be shared between connections.
+	// If nil, NewReno (see congestion.NewReno) is used.
+	CongestionControl func(congestion.Params) congestion.SendAlgorithm
+	// PreferredAddress is the preferred address advertised to the client in the preferred_address
+	// transport parameter (see section 9.6 of RFC 9000).
+	// Clients may migrate to the preferred address after completion of the handshake.
+	// Only valid for the server.
+	PreferredAddress *PreferredAddress
+	// DisablePreferredAddressMigration disables migration to the server's preferred address.
+	// By default, if the server sends a preferred address, the client probes the path to that
+	// address after confirmation of the handshake, and migrates the connection once the path is validated.
+	// Only valid for the client.
+	DisablePreferredAddressMigration bool
 	// Allow0RTT allows the application to decide if a 0-RTT connection attempt should be accepted.
 	// Only valid for the server.
 	Allow0RTT bool
+	// AntiReplay is consulted before a 0-RTT connection attempt is accepted.
+	// If it rejects the attempt, the handshake falls back to 1-RTT.
+	// If not set, there's no protection against replays beyond what crypto/tls provides.
+	// Only valid for the server, and only used if Allow0RTT is set.
+	AntiReplay AntiReplayStore
 	// Enable QUIC datagram support (RFC 9221).
 	EnableDatagrams bool
+	// DatagramSendQueueLen is the maximum number of datagrams queued for sending.
+	// If zero, the default value of 32 is used.
+	DatagramSendQueueLen int
+	// DatagramSendDropPolicy determines what happens when a datagram is sent while the send queue is full.
+	// If unset, SendDatagram blocks until a queued datagram has been sent.
+	DatagramSendDropPolicy DatagramDropPolicy
+	// DatagramReceiveQueueLen is the maximum number of received datagrams queued until they are read by the application.
+	// If zero, the default value of 128 is used.
+	DatagramReceiveQueueLen int
+	// DatagramReceiveDropPolicy determines which datagram is dropped when a datagram is received
+	// while the receive queue is full.
+	// If unset, the newly received datagram is dropped.
+	DatagramReceiveDropPolicy DatagramDropPolicy
 	// Enable QUIC Stream Resets with Partial Delivery.
 	// See https://datatracker.ietf.org/doc/html/draft-ietf-quic-reliable-stream-reset-07.
 	EnableStreamResetPartialDelivery bool
+	// Enable the QUIC multipath extension.
+	// See https://datatracker.ietf.org/doc/html/draft-ietf-quic-multipath-14.
+	// Multipath is only used if both endpoints enable it, and if both use non-zero-length connection IDs.
+	// It then allows the client to use multiple paths (see Conn.AddPath) at the same time.
+	EnableMultipath bool
+	// Enable the QUIC ACK frequency extension.
+	// See https://datatracker.ietf.org/doc/html/draft-ietf-quic-ack-frequency-11.
+	// If both endpoints enable it, the sender asks the peer to send fewer ACKs
+	// when the congestion window is large, reducing the ACK processing overhead for high-throughput transfers.
+	EnableAckFrequency bool
+
+	// AdditionalTransportParameters are sent to the peer in addition to the transport parameters
+	// used by quic-go, allowing applications to negotiate their own protocol extensions.
+	// The map is keyed by the transport parameter ID.
+	// IDs of transport parameters implemented by quic-go, as well as IDs reserved for greasing, can't be used.
+	// The transport parameters sent by the peer are available in the ConnectionState.
+	// When 0-RTT is used, the server rejects 0-RTT if its additional transport parameters changed
+	// since the session ticket was issued.
+	AdditionalTransportParameters map[uint64][]byte
 
 	Tracer func(ctx context.Context, isClient bool, connID ConnectionID) qlogwriter.Trace
 }
 
+// A PreferredAddress is an address that the server prefers clients to use.
+type PreferredAddress struct {
+	// IPv4 is the IPv4 preferred address.
+	IPv4 netip.AddrPort
+	// IPv6 is the IPv6 preferred address.
+	IPv6 netip.AddrPort
+	// Transport is the Transport that receives the packets sent to the preferred address.
+	// It must be bound to the preferred address, and use the same connection ID length
+	// (or ConnectionIDGenerator) as the Transport that accepted the connection.
+	// It should be configured with the same StatelessResetKey (or StatelessResetKeys).
+	// It doesn't need to be listening for new connections.
+	Transport *Transport
+}
+
 // ClientHelloInfo contains information about an incoming connection attempt.
 //
 // Deprecated: Use ClientInfo instead.
@@ -205,7 +356,8 @@ type ClientInfo struct {
 	RemoteAddr net.Addr
 	// AddrVerified says if the remote address was verified using QUIC's Retry mechanism.
 	// Note that the Retry mechanism costs one network roundtrip,
-	// and is not performed unless Transport.MaxUnvalidatedHandshakes is surpassed.
+	// and is not performed unless Transport.MaxUnvalidatedHandshakes is reached,
+	// or Transport.VerifySourceAddress requests it.
 	AddrVerified bool
 }
 
@@ -220,6 +372,12 @@ type ConnectionState struct {
 	SupportsDatagrams bool
 	// SupportsStreamResetPartialDelivery indicates whether the peer advertised support for QUIC Stream Resets with Partial Delivery.
 	SupportsStreamResetPartialDelivery bool
+	// SupportsMultipath indicates whether the use of the QUIC multipath extension was negotiated.
+	SupportsMultipath bool
+	// AdditionalTransportParameters are the transport parameters sent by the peer that are not implemented by quic-go,
+	// keyed by the transport parameter ID. Greased transport parameters are not included.
+	// When using 0-RTT, the client uses the values remembered from the previous connection until the handshake completes.
+	AdditionalTransportParameters map[uint64][]byte
 	// Used0RTT says if 0-RTT resumption was used.
 	Used0RTT bool
 	// Version is the QUIC version of the QUIC connection.
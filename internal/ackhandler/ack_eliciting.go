@@ -6,7 +6,7 @@ import "github.com/quic-go/quic-go/internal/wire"
 func IsFrameTypeAckEliciting(t wire.FrameType) bool {
 	//nolint:exhaustive // The default case catches the rest.
 	switch t {
-	case wire.FrameTypeAck, wire.FrameTypeAckECN:
+	case wire.FrameTypeAck, wire.FrameTypeAckECN, wire.FrameTypePathAck, wire.FrameTypePathAckECN:
 		return false
 	case wire.FrameTypeConnectionClose, wire.FrameTypeApplicationClose:
 		return false
@@ -18,8 +18,9 @@ func IsFrameTypeAckEliciting(t wire.FrameType) bool {
 // IsFrameAckEliciting returns true if the frame is ack-eliciting.
 func IsFrameAckEliciting(f wire.Frame) bool {
 	_, isAck := f.(*wire.AckFrame)
+	_, isPathAck := f.(*wire.PathAckFrame)
 	_, isConnectionClose := f.(*wire.ConnectionCloseFrame)
-	return !isAck && !isConnectionClose
+	return !isAck && !isPathAck && !isConnectionClose
 }
 
 // HasAckElicitingFrames returns true if at least one frame is ack-eliciting.
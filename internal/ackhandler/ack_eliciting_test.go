@@ -44,6 +44,11 @@ func TestIsFrameTypeAckEliciting(t *testing.T) {
 		wire.FrameTypeDatagramWithLength: true,
 		wire.FrameTypeAckFrequency:       true,
 		wire.FrameTypeImmediateAck:       true,
+		wire.FrameTypePathAck:            false,
+		wire.FrameTypePathAckECN:         false,
+		wire.FrameTypePathAbandon:        true,
+		wire.FrameTypePathStatusBackup:   true,
+		wire.FrameTypeMaxPathID:          true,
 	}
 
 	for ft, expected := range testCases {
@@ -65,6 +70,8 @@ func TestAckElicitingFrames(t *testing.T) {
 		&wire.StopSendingFrame{}:     true,
 		&wire.AckFrequencyFrame{}:    true,
 		&wire.ImmediateAckFrame{}:    true,
+		&wire.PathAckFrame{}:         false,
+		&wire.PathAbandonFrame{}:     true,
 	}
 
 	for f, expected := range testCases {
@@ -1,6 +1,8 @@
 package ackhandler
 
 import (
+	"github.com/quic-go/quic-go/internal/congestion"
+	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/utils"
 	"github.com/quic-go/quic-go/qlogwriter"
@@ -9,17 +11,50 @@ import (
 // NewAckHandler creates a new SentPacketHandler and a new ReceivedPacketHandler.
 // clientAddressValidated indicates whether the address was validated beforehand by an address validation token.
 // clientAddressValidated has no effect for a client.
+// congestionControl creates the congestion controller. If nil, NewReno is used.
+// initialBandwidthEstimate is passed to the congestion controller. It is 0 if the bandwidth of the path is unknown.
 func NewAckHandler(
 	initialPacketNumber protocol.PacketNumber,
 	initialMaxDatagramSize protocol.ByteCount,
+	initialBandwidthEstimate congestion.Bandwidth,
 	rttStats *utils.RTTStats,
 	connStats *utils.ConnectionStats,
 	clientAddressValidated bool,
 	enableECN bool,
+	congestionControl func(congestion.Params) congestion.SendAlgorithm,
 	pers protocol.Perspective,
 	qlogger qlogwriter.Recorder,
 	logger utils.Logger,
 ) (SentPacketHandler, ReceivedPacketHandler) {
-	sph := newSentPacketHandler(initialPacketNumber, initialMaxDatagramSize, rttStats, connStats, clientAddressValidated, enableECN, pers, qlogger, logger)
+	sph := newSentPacketHandler(initialPacketNumber, initialMaxDatagramSize, initialBandwidthEstimate, rttStats, connStats, true, clientAddressValidated, enableECN, congestionControl, pers, qlogger, logger)
 	return sph, newReceivedPacketHandler(sph, logger)
 }
+
+// NewPathAckHandler creates a new SentPacketHandler and a new ReceivedPacketHandler
+// for a path of a multipath connection, other than the path used during the handshake.
+// Every path uses its own application data packet number space, starting at 0,
+// as well as its own RTT estimate and congestion controller.
+// Packets are only sent on such a path after the handshake has been confirmed,
+// so the Initial and the Handshake packet number spaces are dropped right away.
+// The counters of connStats are shared with all other paths, but the path's state
+// (congestion window, bytes in flight, etc.) is not published to connStats.
+func NewPathAckHandler(
+	initialMaxDatagramSize protocol.ByteCount,
+	rttStats *utils.RTTStats,
+	connStats *utils.ConnectionStats,
+	enableECN bool,
+	congestionControl func(congestion.Params) congestion.SendAlgorithm,
+	pers protocol.Perspective,
+	qlogger qlogwriter.Recorder,
+	logger utils.Logger,
+) (SentPacketHandler, ReceivedPacketHandler) {
+	sph := newSentPacketHandler(0, initialMaxDatagramSize, 0, rttStats, connStats, false, true, enableECN, congestionControl, pers, qlogger, logger)
+	now := monotime.Now()
+	sph.DropPackets(protocol.EncryptionInitial, now)
+	sph.DropPackets(protocol.EncryptionHandshake, now)
+	sph.peerCompletedAddressValidation = true
+	rph := newReceivedPacketHandler(sph, logger)
+	rph.DropPackets(protocol.EncryptionInitial)
+	rph.DropPackets(protocol.EncryptionHandshake)
+	return sph, rph
+}
@@ -0,0 +1,83 @@
+package ackhandler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/utils"
+	"github.com/quic-go/quic-go/internal/wire"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathAckHandler(t *testing.T) {
+	rttStats := utils.NewRTTStats()
+	sph, rph := NewPathAckHandler(1200, rttStats, &utils.ConnectionStats{}, false, nil, protocol.PerspectiveClient, nil, utils.DefaultLogger)
+
+	// the handshake is already confirmed, so there's no amplification limit, and no Initial / Handshake packets
+	now := monotime.Now()
+	require.Equal(t, SendAny, sph.SendMode(now))
+	require.Zero(t, sph.GetLossDetectionTimeout())
+
+	// packet numbers start at 0
+	pn, _ := sph.PeekPacketNumber(protocol.Encryption1RTT)
+	require.Equal(t, protocol.PacketNumber(0), pn)
+	var packets packetTracker
+	pn = sph.PopPacketNumber(protocol.Encryption1RTT)
+	sph.SentPacket(now, pn, protocol.InvalidPacketNumber, nil, []Frame{packets.NewPingFrame(pn)}, protocol.Encryption1RTT, protocol.ECNNon, 1200, false, false)
+	require.NotZero(t, sph.GetLossDetectionTimeout())
+
+	// the path uses its own RTT estimate
+	_, err := sph.ReceivedAck(
+		&wire.AckFrame{AckRanges: []wire.AckRange{{Smallest: pn, Largest: pn}}},
+		protocol.Encryption1RTT,
+		now.Add(42*time.Millisecond),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []protocol.PacketNumber{pn}, packets.Acked)
+	require.Equal(t, 42*time.Millisecond, rttStats.LatestRTT())
+
+	require.NoError(t, rph.ReceivedPacket(0, protocol.ECNNon, protocol.Encryption1RTT, now, true))
+	require.NoError(t, rph.ReceivedPacket(1, protocol.ECNNon, protocol.Encryption1RTT, now, true))
+	ack := rph.GetAckFrame(protocol.Encryption1RTT, now, true)
+	require.NotNil(t, ack)
+	require.Equal(t, []wire.AckRange{{Smallest: 0, Largest: 1}}, ack.AckRanges)
+	require.Nil(t, rph.GetAckFrame(protocol.EncryptionInitial, now, false))
+	require.Nil(t, rph.GetAckFrame(protocol.EncryptionHandshake, now, false))
+}
+
+func TestPathAckHandlerConnectionStats(t *testing.T) {
+	var connStats utils.ConnectionStats
+	sph0, _ := NewAckHandler(0, 1200, 0, utils.NewRTTStats(), &connStats, true, false, nil, protocol.PerspectiveServer, nil, utils.DefaultLogger)
+	sph0.DropPackets(protocol.EncryptionInitial, monotime.Now())
+	sph0.DropPackets(protocol.EncryptionHandshake, monotime.Now())
+	sph0.SetMaxDatagramSize(1400)
+	require.EqualValues(t, 1400, connStats.MaxPacketSize.Load())
+
+	var packets packetTracker
+	now := monotime.Now()
+	pn := sph0.PopPacketNumber(protocol.Encryption1RTT)
+	sph0.SentPacket(now, pn, protocol.InvalidPacketNumber, nil, []Frame{packets.NewPingFrame(pn)}, protocol.Encryption1RTT, protocol.ECNNon, 1000, false, false)
+	require.EqualValues(t, 1000, connStats.BytesInFlight.Load())
+	cwnd := connStats.CongestionWindow.Load()
+	require.NotZero(t, cwnd)
+
+	// adding a path doesn't change the stats of path 0
+	sph1, _ := NewPathAckHandler(1200, utils.NewRTTStats(), &connStats, false, nil, protocol.PerspectiveServer, nil, utils.DefaultLogger)
+	require.EqualValues(t, 1400, connStats.MaxPacketSize.Load())
+	require.EqualValues(t, 1000, connStats.BytesInFlight.Load())
+
+	// sending on path 1 updates the counters, but not the state of path 0
+	for range 3 {
+		pn := sph1.PopPacketNumber(protocol.Encryption1RTT)
+		sph1.SentPacket(now, pn, protocol.InvalidPacketNumber, nil, []Frame{packets.NewPingFrame(pn)}, protocol.Encryption1RTT, protocol.ECNNon, 500, false, false)
+	}
+	sph1.SetMaxDatagramSize(1300)
+	require.EqualValues(t, 4, connStats.PacketsSent.Load())
+	require.EqualValues(t, 2500, connStats.BytesSent.Load())
+	require.EqualValues(t, 1000, connStats.BytesInFlight.Load())
+	require.Equal(t, cwnd, connStats.CongestionWindow.Load())
+	require.EqualValues(t, 1400, connStats.MaxPacketSize.Load())
+}
@@ -41,6 +41,7 @@ const numECNTestingPackets = 10
 type ecnHandler interface {
 	SentPacket(protocol.PacketNumber, protocol.ECN)
 	Mode() protocol.ECN
+	State() protocol.ECNState
 	HandleNewlyAcked(packets []packetWithPacketNumber, ect0, ect1, ecnce int64) (congested bool)
 	LostPacket(protocol.PacketNumber)
 }
@@ -302,6 +303,22 @@ func (e *ecnTracker) HandleNewlyAcked(packets []packetWithPacketNumber, ect0, ec
 	return e.state == ecnStateCapable && newECNCE > 0
 }
 
+// State returns the current state of ECN validation.
+func (e *ecnTracker) State() protocol.ECNState {
+	switch e.state {
+	case ecnStateInitial, ecnStateTesting:
+		return protocol.ECNStateTesting
+	case ecnStateUnknown:
+		return protocol.ECNStateUnknown
+	case ecnStateCapable:
+		return protocol.ECNStateCapable
+	case ecnStateFailed:
+		return protocol.ECNStateFailed
+	default:
+		panic(fmt.Sprintf("unknown ECN state: %d", e.state))
+	}
+}
+
 // failIfMangled fails ECN validation if all testing packets are lost or CE-marked.
 func (e *ecnTracker) failIfMangled() {
 	numAckedECNCE := e.numAckedECNCE + int64(e.numLostTesting)
@@ -25,6 +25,7 @@ func getAckedPackets(pns ...protocol.PacketNumber) []packetWithPacketNumber {
 func sendECNTestingPackets(t *testing.T, ecnTracker *ecnTracker, recorder *events.Recorder) {
 	t.Helper()
 
+	require.Equal(t, protocol.ECNStateTesting, ecnTracker.State())
 	for i := range protocol.PacketNumber(9) {
 		require.Equal(t, protocol.ECT0, ecnTracker.Mode())
 		// do this twice to make sure only sent packets are counted
@@ -43,6 +44,7 @@ func sendECNTestingPackets(t *testing.T, ecnTracker *ecnTracker, recorder *event
 		recorder.Events(),
 	)
 	recorder.Clear()
+	require.Equal(t, protocol.ECNStateUnknown, ecnTracker.State())
 	// in unknown state, packets shouldn't be ECN-marked
 	require.Equal(t, protocol.ECNNon, ecnTracker.Mode())
 }
@@ -81,6 +83,7 @@ func TestECNTestingPacketsLoss(t *testing.T) {
 		},
 		eventRecorder.Events(),
 	)
+	require.Equal(t, protocol.ECNStateFailed, ecnTracker.State())
 }
 
 // ECN support is validated once an acknowledgment for any testing packet is received.
@@ -134,6 +137,7 @@ func TestECNValidationInUnknownState(t *testing.T) {
 		[]qlogwriter.Event{qlog.ECNStateUpdated{State: qlog.ECNStateCapable}},
 		eventRecorder.Events(),
 	)
+	require.Equal(t, protocol.ECNStateCapable, ecnTracker.State())
 }
 
 func TestECNValidationFailures(t *testing.T) {
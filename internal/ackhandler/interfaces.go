@@ -23,6 +23,11 @@ type SentPacketHandler interface {
 	// It is used for pacing packets.
 	TimeUntilSend() monotime.Time
 	SetMaxDatagramSize(count protocol.ByteCount)
+	// CongestionWindow returns the current congestion window.
+	CongestionWindow() protocol.ByteCount
+	// SetAppLimited is called when there's no more data to send,
+	// although the congestion controller and the pacer would allow sending more.
+	SetAppLimited()
 
 	// only to be called once the handshake is complete
 	QueueProbePacket(protocol.EncryptionLevel) bool /* was a packet queued */
@@ -48,6 +53,11 @@ type ReceivedPacketHandler interface {
 	ReceivedPacket(pn protocol.PacketNumber, ecn protocol.ECN, encLevel protocol.EncryptionLevel, rcvTime monotime.Time, ackEliciting bool) error
 	DropPackets(protocol.EncryptionLevel)
 
+	// HandleAckFrequencyFrame and HandleImmediateAckFrame implement the receiver side
+	// of the ACK frequency extension (draft-ietf-quic-ack-frequency-11).
+	HandleAckFrequencyFrame(*wire.AckFrequencyFrame)
+	HandleImmediateAckFrame()
+
 	GetAlarmTimeout() monotime.Time
 	GetAckFrame(_ protocol.EncryptionLevel, now monotime.Time, onlyIfQueued bool) *wire.AckFrame
 }
@@ -187,3 +187,41 @@ func (c *MockECNHandlerSentPacketCall) DoAndReturn(f func(protocol.PacketNumber,
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
+
+// State mocks base method.
+func (m *MockECNHandler) State() protocol.ECNState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "State")
+	ret0, _ := ret[0].(protocol.ECNState)
+	return ret0
+}
+
+// State indicates an expected call of State.
+func (mr *MockECNHandlerMockRecorder) State() *MockECNHandlerStateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockECNHandler)(nil).State))
+	return &MockECNHandlerStateCall{Call: call}
+}
+
+// MockECNHandlerStateCall wrap *gomock.Call
+type MockECNHandlerStateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockECNHandlerStateCall) Return(arg0 protocol.ECNState) *MockECNHandlerStateCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockECNHandlerStateCall) Do(f func() protocol.ECNState) *MockECNHandlerStateCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockECNHandlerStateCall) DoAndReturn(f func() protocol.ECNState) *MockECNHandlerStateCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -26,6 +26,14 @@ type packet struct {
 	includedInBytesInFlight bool
 	declaredLost            bool
 	isPathProbePacket       bool
+
+	// state used for delivery rate estimation
+	delivered     protocol.ByteCount
+	deliveredTime monotime.Time
+	firstSentTime monotime.Time
+	lost          protocol.ByteCount
+	txInFlight    protocol.ByteCount
+	isAppLimited  bool
 }
 
 func (p *packet) outstanding() bool {
@@ -46,6 +54,12 @@ func getPacket() *packet {
 	p.includedInBytesInFlight = false
 	p.declaredLost = false
 	p.isPathProbePacket = false
+	p.delivered = 0
+	p.deliveredTime = 0
+	p.firstSentTime = 0
+	p.lost = 0
+	p.txInFlight = 0
+	p.isAppLimited = false
 	return p
 }
 
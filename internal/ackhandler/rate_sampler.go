@@ -0,0 +1,106 @@
+package ackhandler
+
+import (
+	"time"
+
+	"github.com/quic-go/quic-go/internal/congestion"
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// The rateSampler generates delivery rate samples, as described in
+// https://datatracker.ietf.org/doc/html/draft-cheng-iccrg-delivery-rate-estimation.
+// The state needed for sampling is stored in every packet of the sent packet history.
+type rateSampler struct {
+	delivered     protocol.ByteCount
+	deliveredTime monotime.Time
+	firstSentTime monotime.Time
+	lost          protocol.ByteCount
+	// The value of delivered at the end of the application-limited phase.
+	// 0 if the sender is not application-limited.
+	appLimited protocol.ByteCount
+
+	// state of the current sample
+	hasSample        bool
+	sample           congestion.RateSample
+	lastSentTime     monotime.Time
+	lastPacketNumber protocol.PacketNumber
+	sendElapsed      time.Duration
+	ackElapsed       time.Duration
+}
+
+// SetAppLimited is called when the sender runs out of data to send,
+// while the congestion controller and the pacer would allow sending more.
+func (s *rateSampler) SetAppLimited(bytesInFlight protocol.ByteCount) {
+	s.appLimited = max(s.delivered+bytesInFlight, 1)
+}
+
+// OnPacketSent is called for every packet that is counted towards bytes in flight.
+// priorInFlight is the number of bytes in flight before this packet was sent.
+func (s *rateSampler) OnPacketSent(p *packet, priorInFlight protocol.ByteCount) {
+	if priorInFlight == 0 {
+		s.firstSentTime = p.SendTime
+		s.deliveredTime = p.SendTime
+	}
+	p.delivered = s.delivered
+	p.deliveredTime = s.deliveredTime
+	p.firstSentTime = s.firstSentTime
+	p.lost = s.lost
+	p.txInFlight = priorInFlight + p.Length
+	p.isAppLimited = s.appLimited != 0
+}
+
+// OnPacketLost is called when a packet is declared lost.
+func (s *rateSampler) OnPacketLost(p *packet) {
+	s.lost += p.Length
+	s.sample.NewlyLost += p.Length
+}
+
+// OnPacketAcked is called for every packet that is acknowledged (and not declared lost before).
+func (s *rateSampler) OnPacketAcked(pn protocol.PacketNumber, p *packet, now monotime.Time) {
+	s.delivered += p.Length
+	s.deliveredTime = now
+	s.sample.NewlyAcked += p.Length
+
+	// use the most recently sent packet to generate the sample
+	if s.hasSample && (p.SendTime < s.lastSentTime || (p.SendTime == s.lastSentTime && pn < s.lastPacketNumber)) {
+		return
+	}
+	s.hasSample = true
+	s.lastSentTime = p.SendTime
+	s.lastPacketNumber = pn
+	s.sendElapsed = p.SendTime.Sub(p.firstSentTime)
+	s.ackElapsed = now.Sub(p.deliveredTime)
+	s.sample.PriorDelivered = p.delivered
+	s.sample.IsAppLimited = p.isAppLimited
+	s.sample.TxInFlight = p.txInFlight
+	s.sample.Lost = s.lost - p.lost
+	s.sample.RTT = now.Sub(p.SendTime)
+	// Note that this doesn't change the value that sendElapsed is calculated from for this sample,
+	// since it uses the packet's firstSentTime.
+	s.firstSentTime = p.SendTime
+}
+
+// GenerateSample generates a rate sample from all packets acknowledged since the last call.
+// It returns false if no packets were acknowledged.
+// Samples with an interval shorter than minRTT are not considered valid,
+// and their delivery rate is set to 0.
+func (s *rateSampler) GenerateSample(minRTT time.Duration) (congestion.RateSample, bool) {
+	if s.appLimited != 0 && s.delivered > s.appLimited {
+		s.appLimited = 0
+	}
+	if !s.hasSample {
+		s.sample.NewlyLost = 0
+		return congestion.RateSample{}, false
+	}
+	rs := s.sample
+	rs.TotalDelivered = s.delivered
+	rs.Delivered = s.delivered - rs.PriorDelivered
+	rs.Interval = max(s.sendElapsed, s.ackElapsed)
+	if rs.Interval >= minRTT && rs.Interval > 0 {
+		rs.DeliveryRate = congestion.Bandwidth(rs.Delivered) * congestion.Bandwidth(time.Second) / congestion.Bandwidth(rs.Interval) * congestion.BytesPerSecond
+	}
+	s.hasSample = false
+	s.sample = congestion.RateSample{}
+	return rs, true
+}
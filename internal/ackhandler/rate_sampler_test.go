@@ -0,0 +1,105 @@
+package ackhandler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/congestion"
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateSamplerDeliveryRate(t *testing.T) {
+	var s rateSampler
+	now := monotime.Now()
+
+	// send 10 packets, 1 ms apart
+	var packets []*packet
+	var bytesInFlight protocol.ByteCount
+	for i := range 10 {
+		p := &packet{SendTime: now.Add(time.Duration(i) * time.Millisecond), Length: 1000}
+		s.OnPacketSent(p, bytesInFlight)
+		bytesInFlight += p.Length
+		packets = append(packets, p)
+	}
+	require.Equal(t, protocol.ByteCount(1000), packets[0].txInFlight)
+	require.Equal(t, protocol.ByteCount(10000), packets[9].txInFlight)
+
+	_, ok := s.GenerateSample(10 * time.Millisecond)
+	require.False(t, ok)
+
+	// the first 5 packets are acknowledged by a single ACK
+	ackTime := now.Add(20 * time.Millisecond)
+	for i, p := range packets[:5] {
+		s.OnPacketAcked(protocol.PacketNumber(i), p, ackTime)
+	}
+	rs, ok := s.GenerateSample(10 * time.Millisecond)
+	require.True(t, ok)
+	require.Equal(t, protocol.ByteCount(5000), rs.Delivered)
+	require.Equal(t, protocol.ByteCount(5000), rs.TotalDelivered)
+	require.Equal(t, protocol.ByteCount(5000), rs.NewlyAcked)
+	require.Zero(t, rs.PriorDelivered)
+	require.Equal(t, 20*time.Millisecond, rs.Interval)
+	require.Equal(t, 16*time.Millisecond, rs.RTT)
+	require.Equal(t, protocol.ByteCount(5000), rs.TxInFlight)
+	require.Equal(t, congestion.Bandwidth(250_000)*congestion.BytesPerSecond, rs.DeliveryRate)
+
+	// packet 5 is lost, packets 6-9 are acknowledged
+	s.OnPacketLost(packets[5])
+	ackTime = ackTime.Add(5 * time.Millisecond)
+	for i, p := range packets[6:] {
+		s.OnPacketAcked(protocol.PacketNumber(6+i), p, ackTime)
+	}
+	rs, ok = s.GenerateSample(10 * time.Millisecond)
+	require.True(t, ok)
+	require.Equal(t, protocol.ByteCount(9000), rs.TotalDelivered)
+	require.Equal(t, protocol.ByteCount(4000), rs.NewlyAcked)
+	require.Equal(t, protocol.ByteCount(1000), rs.NewlyLost)
+	require.Equal(t, protocol.ByteCount(1000), rs.Lost)
+	require.Equal(t, protocol.ByteCount(9000), rs.Delivered)
+	require.Equal(t, 25*time.Millisecond, rs.Interval)
+}
+
+func TestRateSamplerShortInterval(t *testing.T) {
+	var s rateSampler
+	now := monotime.Now()
+	p := &packet{SendTime: now, Length: 1000}
+	s.OnPacketSent(p, 0)
+	s.OnPacketAcked(0, p, now.Add(5*time.Millisecond))
+	rs, ok := s.GenerateSample(10 * time.Millisecond)
+	require.True(t, ok)
+	require.Equal(t, protocol.ByteCount(1000), rs.Delivered)
+	require.Equal(t, 5*time.Millisecond, rs.Interval)
+	require.Zero(t, rs.DeliveryRate)
+}
+
+func TestRateSamplerAppLimited(t *testing.T) {
+	var s rateSampler
+	now := monotime.Now()
+
+	p1 := &packet{SendTime: now, Length: 1000}
+	s.OnPacketSent(p1, 0)
+	// the application has no more data to send
+	s.SetAppLimited(1000)
+	p2 := &packet{SendTime: now.Add(time.Millisecond), Length: 1000}
+	s.OnPacketSent(p2, 1000)
+	require.False(t, p1.isAppLimited)
+	require.True(t, p2.isAppLimited)
+
+	s.OnPacketAcked(0, p1, now.Add(10*time.Millisecond))
+	rs, ok := s.GenerateSample(0)
+	require.True(t, ok)
+	require.False(t, rs.IsAppLimited)
+
+	s.OnPacketAcked(1, p2, now.Add(11*time.Millisecond))
+	rs, ok = s.GenerateSample(0)
+	require.True(t, ok)
+	require.True(t, rs.IsAppLimited)
+
+	// all data sent during the app-limited phase has been delivered
+	p3 := &packet{SendTime: now.Add(12 * time.Millisecond), Length: 1000}
+	s.OnPacketSent(p3, 0)
+	require.False(t, p3.isAppLimited)
+}
@@ -83,6 +83,14 @@ func (h *receivedPacketHandler) DropPackets(encLevel protocol.EncryptionLevel) {
 	}
 }
 
+func (h *receivedPacketHandler) HandleAckFrequencyFrame(f *wire.AckFrequencyFrame) {
+	h.appDataPackets.HandleAckFrequencyFrame(f)
+}
+
+func (h *receivedPacketHandler) HandleImmediateAckFrame() {
+	h.appDataPackets.HandleImmediateAckFrame()
+}
+
 func (h *receivedPacketHandler) GetAlarmTimeout() monotime.Time {
 	return h.appDataPackets.GetAlarmTimeout()
 }
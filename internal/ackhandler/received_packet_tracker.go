@@ -10,7 +10,9 @@ import (
 	"github.com/quic-go/quic-go/internal/wire"
 )
 
-const reorderingThreshold = 1
+// The default reordering threshold, see section 13.2.1 of RFC 9000.
+// It can be changed by the peer using an ACK_FREQUENCY frame.
+const defaultReorderingThreshold = 1
 
 // The receivedPacketTracker tracks packets for the Initial and Handshake packet number space.
 // Every received packet is acknowledged immediately.
@@ -75,11 +77,14 @@ func (h *receivedPacketTracker) IsPotentiallyDuplicate(pn protocol.PacketNumber)
 	return h.packetHistory.IsPotentiallyDuplicate(pn)
 }
 
-// number of ack-eliciting packets received before sending an ACK
-const packetsBeforeAck = 2
+// Number of ack-eliciting packets received without sending an ACK.
+// By default, an ACK is sent for every 2 ack-eliciting packets.
+// It can be changed by the peer using an ACK_FREQUENCY frame.
+const defaultAckElicitingThreshold = 1
 
 // The appDataReceivedPacketTracker tracks packets received in the Application Data packet number space.
 // It waits until at least 2 packets were received before queueing an ACK, or until the max_ack_delay was reached.
+// The peer can change these values using the ACK_FREQUENCY frame (draft-ietf-quic-ack-frequency-11).
 type appDataReceivedPacketTracker struct {
 	receivedPacketTracker
 
@@ -88,8 +93,14 @@ type appDataReceivedPacketTracker struct {
 	largestObserved protocol.PacketNumber
 	ignoreBelow     protocol.PacketNumber
 
-	maxAckDelay time.Duration
-	ackQueued   bool // true if we need send a new ACK
+	maxAckDelay           time.Duration
+	ackElicitingThreshold uint64
+	reorderingThreshold   protocol.PacketNumber
+	// the sequence number of the last ACK_FREQUENCY frame that was processed
+	ackFrequencySeq           uint64
+	receivedAckFrequencyFrame bool
+
+	ackQueued bool // true if we need send a new ACK
 
 	ackElicitingPacketsReceivedSinceLastAck int
 	ackAlarm                                monotime.Time
@@ -101,6 +112,8 @@ func newAppDataReceivedPacketTracker(logger utils.Logger) *appDataReceivedPacket
 	h := &appDataReceivedPacketTracker{
 		receivedPacketTracker: *newReceivedPacketTracker(),
 		maxAckDelay:           protocol.MaxAckDelay,
+		ackElicitingThreshold: defaultAckElicitingThreshold,
+		reorderingThreshold:   defaultReorderingThreshold,
 		logger:                logger,
 	}
 	return h
@@ -133,6 +146,29 @@ func (h *appDataReceivedPacketTracker) ReceivedPacket(pn protocol.PacketNumber,
 	return nil
 }
 
+// HandleAckFrequencyFrame applies the values requested by the peer in an ACK_FREQUENCY frame.
+// Frames that are older than the last processed frame are ignored.
+func (h *appDataReceivedPacketTracker) HandleAckFrequencyFrame(f *wire.AckFrequencyFrame) {
+	if h.receivedAckFrequencyFrame && f.SequenceNumber <= h.ackFrequencySeq {
+		return
+	}
+	h.receivedAckFrequencyFrame = true
+	h.ackFrequencySeq = f.SequenceNumber
+	h.ackElicitingThreshold = f.AckElicitingThreshold
+	h.maxAckDelay = f.RequestMaxAckDelay
+	h.reorderingThreshold = f.ReorderingThreshold
+	if h.logger.Debug() {
+		h.logger.Debugf("\tUpdating ACK frequency: ack-eliciting threshold %d, max ack delay %s, reordering threshold %d", f.AckElicitingThreshold, f.RequestMaxAckDelay, f.ReorderingThreshold)
+	}
+}
+
+// HandleImmediateAckFrame is called when an IMMEDIATE_ACK frame is received.
+// An ACK is sent right away.
+func (h *appDataReceivedPacketTracker) HandleImmediateAckFrame() {
+	h.ackQueued = true
+	h.ackAlarm = 0
+}
+
 // IgnoreBelow sets a lower limit for acknowledging packets.
 // Packets with packet numbers smaller than p will not be acked.
 func (h *appDataReceivedPacketTracker) IgnoreBelow(pn protocol.PacketNumber) {
@@ -155,10 +191,11 @@ func (h *appDataReceivedPacketTracker) isMissing(p protocol.PacketNumber) bool {
 }
 
 func (h *appDataReceivedPacketTracker) hasNewMissingPackets() bool {
-	if h.largestObserved < reorderingThreshold {
+	// a reordering threshold of 0 means that reordering doesn't trigger an immediate ACK
+	if h.reorderingThreshold == 0 || h.largestObserved < h.reorderingThreshold {
 		return false
 	}
-	highestMissing := h.packetHistory.HighestMissingUpTo(h.largestObserved - reorderingThreshold)
+	highestMissing := h.packetHistory.HighestMissingUpTo(h.largestObserved - h.reorderingThreshold)
 	if highestMissing == protocol.InvalidPacketNumber {
 		return false
 	}
@@ -166,7 +203,7 @@ func (h *appDataReceivedPacketTracker) hasNewMissingPackets() bool {
 		// the packet was already reported missing in the last ACK
 		return false
 	}
-	return highestMissing > h.lastAck.LargestAcked()-reorderingThreshold
+	return highestMissing > h.lastAck.LargestAcked()-h.reorderingThreshold
 }
 
 func (h *appDataReceivedPacketTracker) shouldQueueACK(pn protocol.PacketNumber, ecn protocol.ECN, wasMissing bool) bool {
@@ -179,17 +216,18 @@ func (h *appDataReceivedPacketTracker) shouldQueueACK(pn protocol.PacketNumber,
 	// Send an ACK if this packet was reported missing in an ACK sent before.
 	// Ack decimation with reordering relies on the timer to send an ACK, but if
 	// missing packets we reported in the previous ACK, send an ACK immediately.
-	if wasMissing {
+	// This doesn't apply if the peer asked us to not send ACKs when packets are reordered.
+	if wasMissing && h.reorderingThreshold > 0 {
 		if h.logger.Debug() {
 			h.logger.Debugf("\tQueueing ACK because packet %d was missing before.", pn)
 		}
 		return true
 	}
 
-	// send an ACK every 2 ack-eliciting packets
-	if h.ackElicitingPacketsReceivedSinceLastAck >= packetsBeforeAck {
+	// send an ACK once the ack-eliciting threshold is exceeded (by default: every 2 ack-eliciting packets)
+	if uint64(h.ackElicitingPacketsReceivedSinceLastAck) > h.ackElicitingThreshold {
 		if h.logger.Debug() {
-			h.logger.Debugf("\tQueueing ACK because packet %d packets were received after the last ACK (using initial threshold: %d).", h.ackElicitingPacketsReceivedSinceLastAck, packetsBeforeAck)
+			h.logger.Debugf("\tQueueing ACK because %d packets were received after the last ACK (using threshold: %d).", h.ackElicitingPacketsReceivedSinceLastAck, h.ackElicitingThreshold)
 		}
 		return true
 	}
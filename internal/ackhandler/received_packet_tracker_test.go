@@ -150,6 +150,50 @@ func TestAppDataReceivedPacketTrackerMissingPackets(t *testing.T) {
 	require.NotNil(t, tr.GetAckFrame(now, true))
 }
 
+func TestAppDataReceivedPacketTrackerAckFrequency(t *testing.T) {
+	tr := newAppDataReceivedPacketTracker(utils.DefaultLogger)
+
+	now := monotime.Now()
+	// the first packet is always acknowledged
+	require.NoError(t, tr.ReceivedPacket(0, protocol.ECNNon, now, true))
+	require.NotNil(t, tr.GetAckFrame(now, true))
+
+	tr.HandleAckFrequencyFrame(&wire.AckFrequencyFrame{
+		SequenceNumber:        1,
+		AckElicitingThreshold: 4,
+		RequestMaxAckDelay:    50 * time.Millisecond,
+		ReorderingThreshold:   0,
+	})
+	for p := protocol.PacketNumber(1); p <= 4; p++ {
+		require.NoError(t, tr.ReceivedPacket(p, protocol.ECNNon, now, true))
+		require.Nil(t, tr.GetAckFrame(now, true))
+	}
+	require.Equal(t, now.Add(50*time.Millisecond), tr.GetAlarmTimeout())
+	require.NoError(t, tr.ReceivedPacket(5, protocol.ECNNon, now, true))
+	require.NotNil(t, tr.GetAckFrame(now, true))
+
+	// with a reordering threshold of 0, missing packets don't trigger an ACK
+	require.NoError(t, tr.ReceivedPacket(10, protocol.ECNNon, now, true))
+	require.Nil(t, tr.GetAckFrame(now, true))
+
+	// frames with an old sequence number are ignored
+	tr.HandleAckFrequencyFrame(&wire.AckFrequencyFrame{
+		SequenceNumber:        1,
+		AckElicitingThreshold: 0,
+		RequestMaxAckDelay:    time.Millisecond,
+		ReorderingThreshold:   1,
+	})
+	require.NoError(t, tr.ReceivedPacket(11, protocol.ECNNon, now, true))
+	require.Nil(t, tr.GetAckFrame(now, true))
+
+	// an IMMEDIATE_ACK frame causes an ACK to be sent right away
+	tr.HandleImmediateAckFrame()
+	require.Zero(t, tr.GetAlarmTimeout())
+	ack := tr.GetAckFrame(now, true)
+	require.NotNil(t, ack)
+	require.Equal(t, protocol.PacketNumber(11), ack.LargestAcked())
+}
+
 func TestAppDataReceivedPacketTrackerDelayTime(t *testing.T) {
 	tr := newAppDataReceivedPacketTracker(utils.DefaultLogger)
 
@@ -91,9 +91,23 @@ type sentPacketHandler struct {
 
 	bytesInFlight protocol.ByteCount
 
-	congestion congestion.SendAlgorithmWithDebugInfos
-	rttStats   *utils.RTTStats
-	connStats  *utils.ConnectionStats
+	congestion        congestion.SendAlgorithm
+	congestionControl func(congestion.Params) congestion.SendAlgorithm
+	// The optional interfaces implemented by the congestion controller.
+	// They are determined once when the congestion controller is created,
+	// since type assertions on every sent packet and every ACK are expensive.
+	slowStartThresholdReporter congestion.SlowStartThresholdReporter
+	bandwidthEstimator         congestion.BandwidthEstimator
+	rateSampleConsumer         congestion.RateSampleConsumer
+
+	rateSampler rateSampler
+	rttStats    *utils.RTTStats
+	connStats   *utils.ConnectionStats
+	// Counters (bytes and packets sent, lost, etc.) are added to the connection stats by all paths.
+	// Values describing the state of the path (congestion window, bytes in flight, etc.)
+	// are only published by the handler of the path used during the handshake.
+	// Otherwise, the paths of a multipath connection would overwrite each other's values.
+	publishPathState bool
 
 	// The number of times a PTO has been sent without receiving an ack.
 	ptoCount uint32
@@ -122,26 +136,25 @@ var (
 
 // clientAddressValidated indicates whether the address was validated beforehand by an address validation token.
 // If the address was validated, the amplification limit doesn't apply. It has no effect for a client.
+// If congestionControl is nil, NewReno is used.
+// publishPathState says if the state of the path is published to connStats, see the publishPathState field.
 func newSentPacketHandler(
 	initialPN protocol.PacketNumber,
 	initialMaxDatagramSize protocol.ByteCount,
+	initialBandwidthEstimate congestion.Bandwidth,
 	rttStats *utils.RTTStats,
 	connStats *utils.ConnectionStats,
+	publishPathState bool,
 	clientAddressValidated bool,
 	enableECN bool,
+	congestionControl func(congestion.Params) congestion.SendAlgorithm,
 	pers protocol.Perspective,
 	qlogger qlogwriter.Recorder,
 	logger utils.Logger,
 ) *sentPacketHandler {
-	congestion := congestion.NewCubicSender(
-		congestion.DefaultClock{},
-		rttStats,
-		connStats,
-		initialMaxDatagramSize,
-		true, // use Reno
-		qlogger,
-	)
-
+	if congestionControl == nil {
+		congestionControl = newReno
+	}
 	h := &sentPacketHandler{
 		peerCompletedAddressValidation: pers == protocol.PerspectiveServer,
 		peerAddressValidated:           pers == protocol.PerspectiveClient || clientAddressValidated,
@@ -151,18 +164,42 @@ func newSentPacketHandler(
 		lostPackets:                    *newLostPacketTracker(64),
 		rttStats:                       rttStats,
 		connStats:                      connStats,
-		congestion:                     congestion,
+		publishPathState:               publishPathState,
+		congestionControl:              congestionControl,
 		perspective:                    pers,
 		qlogger:                        qlogger,
 		logger:                         logger,
 	}
+	h.setCongestionController(h.newCongestionController(initialMaxDatagramSize, initialBandwidthEstimate))
 	if enableECN {
 		h.enableECN = true
 		h.ecnTracker = newECNTracker(logger, qlogger)
 	}
+	h.updateMaxPacketSize(initialMaxDatagramSize)
+	h.updateConnStats()
 	return h
 }
 
+func newReno(p congestion.Params) congestion.SendAlgorithm {
+	return congestion.NewCubicSender(congestion.DefaultClock{}, p.RTTStats, p.InitialMaxDatagramSize, p.InitialBandwidthEstimate, true, p.Recorder)
+}
+
+func (h *sentPacketHandler) newCongestionController(initialMaxDatagramSize protocol.ByteCount, initialBandwidthEstimate congestion.Bandwidth) congestion.SendAlgorithm {
+	return h.congestionControl(congestion.Params{
+		RTTStats:                 h.rttStats,
+		InitialMaxDatagramSize:   initialMaxDatagramSize,
+		InitialBandwidthEstimate: initialBandwidthEstimate,
+		Recorder:                 h.qlogger,
+	})
+}
+
+func (h *sentPacketHandler) setCongestionController(cc congestion.SendAlgorithm) {
+	h.congestion = cc
+	h.slowStartThresholdReporter, _ = cc.(congestion.SlowStartThresholdReporter)
+	h.bandwidthEstimator, _ = cc.(congestion.BandwidthEstimator)
+	h.rateSampleConsumer, _ = cc.(congestion.RateSampleConsumer)
+}
+
 func (h *sentPacketHandler) removeFromBytesInFlight(p *packet) {
 	if p.includedInBytesInFlight {
 		if p.Length > h.bytesInFlight {
@@ -221,6 +258,7 @@ func (h *sentPacketHandler) DropPackets(encLevel protocol.EncryptionLevel, now m
 	h.ptoCount = 0
 	h.numProbesToSend = 0
 	h.ptoMode = SendNone
+	h.updateConnStats()
 	h.setLossDetectionTimer(now)
 }
 
@@ -285,6 +323,7 @@ func (h *sentPacketHandler) SentPacket(
 		p.Frames = frames
 		p.isPathProbePacket = true
 		pnSpace.history.SentPathProbePacket(pn, p)
+		h.updateConnStats()
 		h.setLossDetectionTimer(t)
 		return
 	}
@@ -318,14 +357,43 @@ func (h *sentPacketHandler) SentPacket(
 	p.Frames = frames
 	p.IsPathMTUProbePacket = isPathMTUProbePacket
 	p.includedInBytesInFlight = true
+	h.rateSampler.OnPacketSent(p, h.bytesInFlight-size)
 
 	pnSpace.history.SentAckElicitingPacket(pn, p)
 	if h.qlogger != nil {
 		h.qlogMetricsUpdated()
 	}
+	h.updateConnStats()
 	h.setLossDetectionTimer(t)
 }
 
+// updateConnStats updates the congestion and recovery state exposed in the connection stats.
+func (h *sentPacketHandler) updateConnStats() {
+	if !h.publishPathState {
+		return
+	}
+	h.connStats.CongestionWindow.Store(uint64(h.congestion.GetCongestionWindow()))
+	h.connStats.BytesInFlight.Store(uint64(h.bytesInFlight))
+	h.connStats.PacingRate.Store(uint64(h.congestion.PacingRate()))
+	var ssthresh uint64
+	if h.slowStartThresholdReporter != nil {
+		if s := h.slowStartThresholdReporter.SlowStartThreshold(); s < protocol.MaxByteCount {
+			ssthresh = uint64(s)
+		}
+	}
+	h.connStats.SlowStartThreshold.Store(ssthresh)
+	var bw uint64
+	if h.bandwidthEstimator != nil {
+		bw = uint64(h.bandwidthEstimator.BandwidthEstimate())
+	}
+	h.connStats.BandwidthEstimate.Store(bw)
+	ecnState := protocol.ECNStateDisabled
+	if h.ecnTracker != nil {
+		ecnState = h.ecnTracker.State()
+	}
+	h.connStats.ECNState.Store(uint32(ecnState))
+}
+
 func (h *sentPacketHandler) qlogMetricsUpdated() {
 	var metricsUpdatedEvent qlog.MetricsUpdated
 	var updated bool
@@ -366,6 +434,18 @@ func (h *sentPacketHandler) qlogMetricsUpdated() {
 		h.lastMetrics.PacketsInFlight = metricsUpdatedEvent.PacketsInFlight
 		updated = true
 	}
+	if pacingRate := uint64(h.congestion.PacingRate()); h.lastMetrics.PacingRate != pacingRate {
+		metricsUpdatedEvent.PacingRate = pacingRate
+		h.lastMetrics.PacingRate = pacingRate
+		updated = true
+	}
+	if h.bandwidthEstimator != nil {
+		if bw := uint64(h.bandwidthEstimator.BandwidthEstimate()); h.lastMetrics.BandwidthEstimate != bw {
+			metricsUpdatedEvent.BandwidthEstimate = bw
+			h.lastMetrics.BandwidthEstimate = bw
+			updated = true
+		}
+	}
 	if updated {
 		h.qlogger.RecordEvent(metricsUpdatedEvent)
 	}
@@ -427,6 +507,10 @@ func (h *sentPacketHandler) ReceivedAck(ack *wire.AckFrame, encLevel protocol.En
 
 	// Only inform the ECN tracker about new 1-RTT ACKs if the ACK increases the largest acked.
 	if encLevel == protocol.Encryption1RTT && h.ecnTracker != nil && largestAcked > pnSpace.largestAcked {
+		// Only the connection's run loop writes this value, so there's no race between Load and Store.
+		if ack.ECNCE > h.connStats.ECNCEReported.Load() {
+			h.connStats.ECNCEReported.Store(ack.ECNCE)
+		}
 		congested := h.ecnTracker.HandleNewlyAcked(ackedPackets, int64(ack.ECT0), int64(ack.ECT1), int64(ack.ECNCE))
 		if congested {
 			h.congestion.OnCongestionEvent(largestAcked, 0, priorInFlight)
@@ -442,6 +526,7 @@ func (h *sentPacketHandler) ReceivedAck(ack *wire.AckFrame, encLevel protocol.En
 	var acked1RTTPacket bool
 	for _, p := range ackedPackets {
 		if p.includedInBytesInFlight && !p.declaredLost {
+			h.rateSampler.OnPacketAcked(p.PacketNumber, p.packet, rcvTime)
 			h.congestion.OnPacketAcked(p.PacketNumber, p.Length, priorInFlight, rcvTime)
 		}
 		if p.EncryptionLevel == protocol.Encryption1RTT {
@@ -453,6 +538,12 @@ func (h *sentPacketHandler) ReceivedAck(ack *wire.AckFrame, encLevel protocol.En
 		}
 	}
 
+	if rs, ok := h.rateSampler.GenerateSample(h.rttStats.MinRTT()); ok {
+		if h.rateSampleConsumer != nil {
+			h.rateSampleConsumer.OnRateSample(rcvTime, h.bytesInFlight, rs)
+		}
+	}
+
 	// detect spurious losses for application data packets, if the ACK was not reordered
 	if encLevel == protocol.Encryption1RTT && largestAcked == pnSpace.largestAcked {
 		h.detectSpuriousLosses(
@@ -481,6 +572,7 @@ func (h *sentPacketHandler) ReceivedAck(ack *wire.AckFrame, encLevel protocol.En
 	if h.qlogger != nil {
 		h.qlogMetricsUpdated()
 	}
+	h.updateConnStats()
 
 	h.setLossDetectionTimer(rcvTime)
 	return acked1RTTPacket, nil
@@ -856,6 +948,9 @@ func (h *sentPacketHandler) detectLostPackets(now monotime.Time, encLevel protoc
 				h.removeFromBytesInFlight(p)
 				h.queueFramesForRetransmission(p)
 				if !p.IsPathMTUProbePacket {
+					h.connStats.PacketsLost.Add(1)
+					h.connStats.BytesLost.Add(uint64(p.Length))
+					h.rateSampler.OnPacketLost(p)
 					h.congestion.OnCongestionEvent(pn, p.Length, priorInFlight)
 				}
 				if encLevel == protocol.Encryption1RTT && h.ecnTracker != nil {
@@ -868,6 +963,7 @@ func (h *sentPacketHandler) detectLostPackets(now monotime.Time, encLevel protoc
 
 func (h *sentPacketHandler) OnLossDetectionTimeout(now monotime.Time) error {
 	defer h.setLossDetectionTimer(now)
+	defer h.updateConnStats()
 
 	if h.handshakeConfirmed {
 		h.detectLostPathProbes(now)
@@ -897,6 +993,7 @@ func (h *sentPacketHandler) OnLossDetectionTimeout(now monotime.Time) error {
 	// actually packets outstanding.
 	if h.bytesInFlight == 0 && !h.peerCompletedAddressValidation {
 		h.ptoCount++
+		h.connStats.PTOCount.Add(1)
 		h.numProbesToSend++
 		if h.initialPackets != nil {
 			h.ptoMode = SendPTOInitial
@@ -917,6 +1014,7 @@ func (h *sentPacketHandler) OnLossDetectionTimeout(now monotime.Time) error {
 		return nil
 	}
 	h.ptoCount++
+	h.connStats.PTOCount.Add(1)
 	if h.logger.Debug() {
 		h.logger.Debugf("Loss detection alarm for %s fired in PTO mode. PTO count: %d", encLevel, h.ptoCount)
 	}
@@ -1029,8 +1127,24 @@ func (h *sentPacketHandler) TimeUntilSend() monotime.Time {
 	return h.congestion.TimeUntilSend(h.bytesInFlight)
 }
 
+func (h *sentPacketHandler) SetAppLimited() {
+	h.rateSampler.SetAppLimited(h.bytesInFlight)
+}
+
 func (h *sentPacketHandler) SetMaxDatagramSize(s protocol.ByteCount) {
 	h.congestion.SetMaxDatagramSize(s)
+	h.updateMaxPacketSize(s)
+	h.updateConnStats()
+}
+
+func (h *sentPacketHandler) updateMaxPacketSize(s protocol.ByteCount) {
+	if h.publishPathState {
+		h.connStats.MaxPacketSize.Store(uint64(s))
+	}
+}
+
+func (h *sentPacketHandler) CongestionWindow() protocol.ByteCount {
+	return h.congestion.GetCongestionWindow()
 }
 
 func (h *sentPacketHandler) isAmplificationLimited() bool {
@@ -1116,6 +1230,7 @@ func (h *sentPacketHandler) ResetForRetry(now monotime.Time) {
 		}
 	}
 	h.ptoCount = 0
+	h.updateConnStats()
 }
 
 func (h *sentPacketHandler) MigratedPath(now monotime.Time, initialMaxDatagramSize protocol.ByteCount) {
@@ -1130,13 +1245,10 @@ func (h *sentPacketHandler) MigratedPath(now monotime.Time, initialMaxDatagramSi
 	for pn := range h.appDataPackets.history.PathProbes() {
 		h.appDataPackets.history.RemovePathProbe(pn)
 	}
-	h.congestion = congestion.NewCubicSender(
-		congestion.DefaultClock{},
-		h.rttStats,
-		h.connStats,
-		initialMaxDatagramSize,
-		true, // use Reno
-		h.qlogger,
-	)
+	// the bandwidth of the new path is unknown
+	h.setCongestionController(h.newCongestionController(initialMaxDatagramSize, 0))
+	h.rateSampler = rateSampler{}
+	h.updateMaxPacketSize(initialMaxDatagramSize)
+	h.updateConnStats()
 	h.setLossDetectionTimer(now)
 }
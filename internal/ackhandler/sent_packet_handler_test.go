@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/quic-go/quic-go/internal/congestion"
 	"github.com/quic-go/quic-go/internal/mocks"
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
@@ -111,10 +112,13 @@ func testSentPacketHandlerSendAndAcknowledge(t *testing.T, encLevel protocol.Enc
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		utils.NewRTTStats(),
 		&utils.ConnectionStats{},
+		true,
 		false,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -165,10 +169,13 @@ func TestSentPacketHandlerAcknowledgeSkippedPacket(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		utils.NewRTTStats(),
 		&utils.ConnectionStats{},
+		true,
 		false,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -220,10 +227,13 @@ func testSentPacketHandlerRTTs(t *testing.T, encLevel protocol.EncryptionLevel,
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
+		true,
 		false,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -310,10 +320,13 @@ func testSentPacketHandlerAmplificationLimitServer(t *testing.T, addressValidate
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		utils.NewRTTStats(),
 		&utils.ConnectionStats{},
+		true,
 		addressValidated,
 		false,
+		nil,
 		protocol.PerspectiveServer,
 		nil,
 		utils.DefaultLogger,
@@ -380,10 +393,13 @@ func testSentPacketHandlerAmplificationLimitClient(t *testing.T, dropHandshake b
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		utils.NewRTTStats(),
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -435,10 +451,13 @@ func TestSentPacketHandlerDelayBasedLossDetection(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveServer,
 		nil,
 		utils.DefaultLogger,
@@ -489,10 +508,13 @@ func TestSentPacketHandlerPacketBasedLossDetection(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveServer,
 		nil,
 		utils.DefaultLogger,
@@ -547,13 +569,17 @@ func testSentPacketHandlerPTO(t *testing.T, encLevel protocol.EncryptionLevel, p
 	rttStats.UpdateRTT(500*time.Millisecond, 0)
 	rttStats.UpdateRTT(1000*time.Millisecond, 0)
 	rttStats.UpdateRTT(1500*time.Millisecond, 0)
+	var connStats utils.ConnectionStats
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
-		&utils.ConnectionStats{},
+		&connStats,
+		true,
 		true,
 		false,
+		nil,
 		protocol.PerspectiveServer,
 		&eventRecorder,
 		utils.DefaultLogger,
@@ -738,6 +764,8 @@ func testSentPacketHandlerPTO(t *testing.T, encLevel protocol.EncryptionLevel, p
 	// the PTO timer is now set for the last remaining packet (8),
 	// with no exponential backoff
 	require.Equal(t, sendTimes[8].Add(rttStats.PTO(encLevel == protocol.Encryption1RTT)), sph.GetLossDetectionTimeout())
+	// the PTO count in the connection stats is not reset
+	require.EqualValues(t, 2, connStats.PTOCount.Load())
 }
 
 func TestSentPacketHandlerPacketNumberSpacesPTO(t *testing.T) {
@@ -747,10 +775,13 @@ func TestSentPacketHandlerPacketNumberSpacesPTO(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveServer,
 		nil,
 		utils.DefaultLogger,
@@ -839,10 +870,13 @@ func TestSentPacketHandler0RTT(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		utils.NewRTTStats(),
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -885,20 +919,26 @@ func TestSentPacketHandler0RTT(t *testing.T) {
 
 func TestSentPacketHandlerCongestion(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
-	cong := mocks.NewMockSendAlgorithmWithDebugInfos(mockCtrl)
+	cong := mocks.NewMockSendAlgorithm(mockCtrl)
 	rttStats := utils.NewRTTStats()
+	var connStats utils.ConnectionStats
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
-		&utils.ConnectionStats{},
+		&connStats,
+		true,
 		true,
 		false,
+		nil,
 		protocol.PerspectiveServer,
 		nil,
 		utils.DefaultLogger,
 	)
-	sph.congestion = cong
+	sph.setCongestionController(cong)
+	cong.EXPECT().GetCongestionWindow().Return(protocol.ByteCount(12345)).AnyTimes()
+	cong.EXPECT().PacingRate().Return(congestion.Bandwidth(1e6)).AnyTimes()
 
 	var packets packetTracker
 	// Send the first 5 packets: not congestion-limited, not pacing-limited.
@@ -921,6 +961,10 @@ func TestSentPacketHandlerCongestion(t *testing.T) {
 		sendTimes = append(sendTimes, now)
 		now = now.Add(100 * time.Millisecond)
 	}
+	require.EqualValues(t, 5000, connStats.BytesInFlight.Load())
+	require.EqualValues(t, 12345, connStats.CongestionWindow.Load())
+	require.EqualValues(t, 1e6, connStats.PacingRate.Load())
+	require.Equal(t, protocol.ECNStateDisabled, protocol.ECNState(connStats.ECNState.Load()))
 
 	// try to send another packet: not congestion-limited, but pacing-limited
 	now = now.Add(100 * time.Millisecond)
@@ -953,6 +997,10 @@ func TestSentPacketHandlerCongestion(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, []protocol.PacketNumber{pns[2], pns[3]}, packets.Acked)
 	require.Equal(t, []protocol.PacketNumber{pns[0], pns[1]}, packets.Lost)
+	// the Path MTU probe packet is not counted as lost
+	require.EqualValues(t, 1, connStats.PacketsLost.Load())
+	require.EqualValues(t, 1000, connStats.BytesLost.Load())
+	require.EqualValues(t, 1000, connStats.BytesInFlight.Load())
 
 	// Now receive a (delayed) ACK for the 1st packet.
 	// Since this packet was already lost, we don't expect any calls to the congestion controller.
@@ -972,6 +1020,129 @@ func TestSentPacketHandlerCongestion(t *testing.T) {
 	sph.SentPacket(now, pn, protocol.InvalidPacketNumber, nil, []Frame{packets.NewPingFrame(pn)}, protocol.EncryptionInitial, protocol.ECNNon, 1000, false, false)
 }
 
+func TestSentPacketHandlerCongestionControl(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	rttStats := utils.NewRTTStats()
+	var params []congestion.Params
+	var congs []*mocks.MockSendAlgorithm
+	var connStats utils.ConnectionStats
+	sph := newSentPacketHandler(
+		0,
+		1200,
+		42*congestion.BytesPerSecond,
+		rttStats,
+		&connStats,
+		true,
+		true,
+		false,
+		func(p congestion.Params) congestion.SendAlgorithm {
+			params = append(params, p)
+			cong := mocks.NewMockSendAlgorithm(mockCtrl)
+			cong.EXPECT().GetCongestionWindow().AnyTimes()
+			cong.EXPECT().PacingRate().AnyTimes()
+			congs = append(congs, cong)
+			return cong
+		},
+		protocol.PerspectiveServer,
+		nil,
+		utils.DefaultLogger,
+	)
+	require.Len(t, params, 1)
+	require.Equal(t, rttStats, params[0].RTTStats)
+	require.Equal(t, protocol.ByteCount(1200), params[0].InitialMaxDatagramSize)
+	require.Equal(t, 42*congestion.BytesPerSecond, params[0].InitialBandwidthEstimate)
+	require.Nil(t, params[0].Recorder)
+
+	now := monotime.Now()
+	congs[0].EXPECT().CanSend(protocol.ByteCount(0)).Return(false)
+	require.Equal(t, SendAck, sph.SendMode(now))
+
+	congs[0].EXPECT().SetMaxDatagramSize(protocol.ByteCount(1300))
+	require.EqualValues(t, 1200, connStats.MaxPacketSize.Load())
+	sph.SetMaxDatagramSize(1300)
+	require.EqualValues(t, 1300, connStats.MaxPacketSize.Load())
+
+	// a new congestion controller is created when the path is migrated
+	sph.MigratedPath(now, 1400)
+	require.EqualValues(t, 1400, connStats.MaxPacketSize.Load())
+	require.Len(t, params, 2)
+	require.Equal(t, protocol.ByteCount(1400), params[1].InitialMaxDatagramSize)
+	require.Zero(t, params[1].InitialBandwidthEstimate) // the bandwidth of the new path is unknown
+	gomock.InOrder(
+		congs[1].EXPECT().CanSend(protocol.ByteCount(0)).Return(true),
+		congs[1].EXPECT().HasPacingBudget(now).Return(true),
+	)
+	require.Equal(t, SendAny, sph.SendMode(now))
+}
+
+type rateSampleRecordingSendAlgorithm struct {
+	*mocks.MockSendAlgorithm
+	samples []congestion.RateSample
+}
+
+func (c *rateSampleRecordingSendAlgorithm) OnRateSample(_ monotime.Time, _ protocol.ByteCount, rs congestion.RateSample) {
+	c.samples = append(c.samples, rs)
+}
+
+func TestSentPacketHandlerRateSamples(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	cong := &rateSampleRecordingSendAlgorithm{MockSendAlgorithm: mocks.NewMockSendAlgorithm(mockCtrl)}
+	cong.EXPECT().OnPacketSent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	cong.EXPECT().OnPacketAcked(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	cong.EXPECT().OnCongestionEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	cong.EXPECT().MaybeExitSlowStart().AnyTimes()
+	cong.EXPECT().GetCongestionWindow().AnyTimes()
+	cong.EXPECT().PacingRate().AnyTimes()
+	sph := newSentPacketHandler(
+		0,
+		1200,
+		0,
+		utils.NewRTTStats(),
+		&utils.ConnectionStats{},
+		true,
+		true,
+		false,
+		func(congestion.Params) congestion.SendAlgorithm { return cong },
+		protocol.PerspectiveClient,
+		nil,
+		utils.DefaultLogger,
+	)
+
+	var packets packetTracker
+	now := monotime.Now()
+	var pns []protocol.PacketNumber
+	for i := range 5 {
+		pn := sph.PopPacketNumber(protocol.Encryption1RTT)
+		sph.SentPacket(now.Add(time.Duration(i)*time.Millisecond), pn, protocol.InvalidPacketNumber, nil, []Frame{packets.NewPingFrame(pn)}, protocol.Encryption1RTT, protocol.ECNNon, 1000, false, false)
+		pns = append(pns, pn)
+	}
+	// the application runs out of data to send
+	sph.SetAppLimited()
+
+	_, err := sph.ReceivedAck(&wire.AckFrame{AckRanges: ackRanges(pns[0], pns[1])}, protocol.Encryption1RTT, now.Add(50*time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, cong.samples, 1)
+	rs := cong.samples[0]
+	require.Equal(t, protocol.ByteCount(2000), rs.NewlyAcked)
+	require.Equal(t, protocol.ByteCount(2000), rs.Delivered)
+	require.Equal(t, protocol.ByteCount(2000), rs.TxInFlight)
+	require.Equal(t, 49*time.Millisecond, rs.RTT)
+	require.Equal(t, 50*time.Millisecond, rs.Interval)
+	require.NotZero(t, rs.DeliveryRate)
+	require.False(t, rs.IsAppLimited)
+
+	// packets sent while app-limited are marked as such
+	pn := sph.PopPacketNumber(protocol.Encryption1RTT)
+	sph.SentPacket(now.Add(60*time.Millisecond), pn, protocol.InvalidPacketNumber, nil, []Frame{packets.NewPingFrame(pn)}, protocol.Encryption1RTT, protocol.ECNNon, 1000, false, false)
+	_, err = sph.ReceivedAck(&wire.AckFrame{AckRanges: ackRanges(pns[0], pns[1], pns[2], pns[3], pns[4], pn)}, protocol.Encryption1RTT, now.Add(110*time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, cong.samples, 2)
+	rs = cong.samples[1]
+	require.Equal(t, protocol.ByteCount(4000), rs.NewlyAcked)
+	require.Equal(t, protocol.ByteCount(6000), rs.TotalDelivered)
+	require.True(t, rs.IsAppLimited)
+}
+
 func TestSentPacketHandlerRetry(t *testing.T) {
 	t.Run("long RTT measurement", func(t *testing.T) {
 		testSentPacketHandlerRetry(t, time.Second, time.Second)
@@ -990,10 +1161,13 @@ func testSentPacketHandlerRetry(t *testing.T, rtt, expectedRTT time.Duration) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -1041,10 +1215,13 @@ func TestSentPacketHandlerRetryAfterPTO(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -1077,24 +1254,31 @@ func TestSentPacketHandlerRetryAfterPTO(t *testing.T) {
 
 func TestSentPacketHandlerECN(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
-	cong := mocks.NewMockSendAlgorithmWithDebugInfos(mockCtrl)
+	cong := mocks.NewMockSendAlgorithm(mockCtrl)
 	cong.EXPECT().OnPacketSent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	cong.EXPECT().OnPacketAcked(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	cong.EXPECT().MaybeExitSlowStart().AnyTimes()
+	cong.EXPECT().GetCongestionWindow().AnyTimes()
+	cong.EXPECT().PacingRate().AnyTimes()
 	ecnHandler := NewMockECNHandler(mockCtrl)
+	ecnHandler.EXPECT().State().Return(protocol.ECNStateCapable).AnyTimes()
+	var connStats utils.ConnectionStats
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		utils.NewRTTStats(),
-		&utils.ConnectionStats{},
+		&connStats,
+		true,
 		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
 	)
 	sph.ecnTracker = ecnHandler
-	sph.congestion = cong
+	sph.setCongestionController(cong)
 
 	// ECN marks on non-1-RTT packets are ignored
 	sph.SentPacket(monotime.Now(), sph.PopPacketNumber(protocol.EncryptionInitial), protocol.InvalidPacketNumber, nil, nil, protocol.EncryptionInitial, protocol.ECT1, 1200, false, false)
@@ -1139,6 +1323,8 @@ func TestSentPacketHandlerECN(t *testing.T) {
 	)
 	require.NoError(t, err)
 	require.Equal(t, []protocol.PacketNumber{pns[0]}, packets.Lost)
+	require.EqualValues(t, 12, connStats.ECNCEReported.Load())
+	require.Equal(t, protocol.ECNStateCapable, protocol.ECNState(connStats.ECNState.Load()))
 
 	// The second packet is still outstanding.
 	// Receive a (delayed) ACK for it.
@@ -1146,6 +1332,7 @@ func TestSentPacketHandlerECN(t *testing.T) {
 	now = now.Add(100 * time.Millisecond)
 	_, err = sph.ReceivedAck(&wire.AckFrame{AckRanges: ackRanges(pns[1])}, protocol.Encryption1RTT, now)
 	require.NoError(t, err)
+	require.EqualValues(t, 12, connStats.ECNCEReported.Load())
 
 	// Send two more packets, and receive an ACK for the second one.
 	pns = pns[:2]
@@ -1189,10 +1376,13 @@ func TestSentPacketHandlerPathProbe(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -1269,10 +1459,13 @@ func TestSentPacketHandlerPathProbeAckAndLoss(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -1345,10 +1538,13 @@ func testSentPacketHandlerRandomized(t *testing.T, seed uint64) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
@@ -1414,10 +1610,13 @@ func TestSentPacketHandlerSpuriousLoss(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		utils.NewRTTStats(),
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		&eventRecorder,
 		utils.DefaultLogger,
@@ -1549,10 +1748,13 @@ func benchmarkSendAndAcknowledge(b *testing.B, ackEvery, inFlight int) {
 	sph := newSentPacketHandler(
 		0,
 		1200,
+		0,
 		rttStats,
 		&utils.ConnectionStats{},
 		true,
+		true,
 		false,
+		nil,
 		protocol.PerspectiveClient,
 		nil,
 		utils.DefaultLogger,
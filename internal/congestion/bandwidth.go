@@ -16,6 +16,20 @@ const (
 	BytesPerSecond = 8 * BitsPerSecond
 )
 
+// initialCongestionWindowFor returns the initial congestion window.
+// If the bandwidth of the path is known from a previous connection, the congestion window is
+// initialized to half of the bandwidth-delay product, similar to Careful Resume
+// (https://datatracker.ietf.org/doc/draft-ietf-tsvwg-careful-resume/).
+// It is never smaller than the default initial congestion window,
+// and never larger than the maximum congestion window.
+func initialCongestionWindowFor(maxDatagramSize protocol.ByteCount, bw Bandwidth, rtt time.Duration) protocol.ByteCount {
+	cwnd := initialCongestionWindow * maxDatagramSize
+	if bw == 0 || rtt <= 0 {
+		return cwnd
+	}
+	return min(max(cwnd, bytesFromBandwidth(bw, rtt)/2), protocol.MaxCongestionWindowPackets*maxDatagramSize)
+}
+
 // BandwidthFromDelta calculates the bandwidth from a number of bytes and a time delta
 func BandwidthFromDelta(bytes protocol.ByteCount, delta time.Duration) Bandwidth {
 	return Bandwidth(bytes) * Bandwidth(time.Second) / Bandwidth(delta) * BytesPerSecond
@@ -4,9 +4,31 @@ import (
 	"testing"
 	"time"
 
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/utils"
+
 	"github.com/stretchr/testify/require"
 )
 
 func TestBandwidthFromDelta(t *testing.T) {
 	require.Equal(t, 1000*BytesPerSecond, BandwidthFromDelta(1, time.Millisecond))
 }
+
+func TestInitialCongestionWindow(t *testing.T) {
+	const maxDatagramSize = 1200
+	// no bandwidth estimate
+	require.Equal(t, protocol.ByteCount(initialCongestionWindow*maxDatagramSize), initialCongestionWindowFor(maxDatagramSize, 0, 100*time.Millisecond))
+	// small bandwidth-delay product
+	require.Equal(t, protocol.ByteCount(initialCongestionWindow*maxDatagramSize), initialCongestionWindowFor(maxDatagramSize, 100*BytesPerSecond, 100*time.Millisecond))
+	// 10 MB/s * 100ms = 1 MB, half of that is used
+	require.Equal(t, protocol.ByteCount(500_000), initialCongestionWindowFor(maxDatagramSize, 10_000_000*BytesPerSecond, 100*time.Millisecond))
+	// huge bandwidth-delay product
+	require.Equal(t, protocol.ByteCount(protocol.MaxCongestionWindowPackets*maxDatagramSize), initialCongestionWindowFor(maxDatagramSize, 1e12*BytesPerSecond, time.Second))
+}
+
+func TestInitialCongestionWindowFromBandwidthEstimate(t *testing.T) {
+	rttStats := utils.NewRTTStats()
+	rttStats.SetInitialRTT(100 * time.Millisecond)
+	require.Equal(t, protocol.ByteCount(500_000), NewCubicSender(DefaultClock{}, rttStats, 1200, 10_000_000*BytesPerSecond, true, nil).GetCongestionWindow())
+	require.Equal(t, protocol.ByteCount(500_000), NewBBRSender(DefaultClock{}, rttStats, 1200, 10_000_000*BytesPerSecond, nil).GetCongestionWindow())
+}
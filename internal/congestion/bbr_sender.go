@@ -0,0 +1,907 @@
+package congestion
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+)
+
+// This file implements BBR (version 3), as specified in
+// https://datatracker.ietf.org/doc/html/draft-ietf-ccwg-bbr-02.
+
+const (
+	bbrStartupPacingGain  = 2.77 // 4 * ln(2)
+	bbrStartupCwndGain    = 2.0
+	bbrDrainPacingGain    = 0.35
+	bbrDefaultCwndGain    = 2.0
+	bbrProbeBWDownGain    = 0.9
+	bbrProbeBWUpGain      = 1.25
+	bbrProbeBWUpCwndGain  = 2.25
+	bbrProbeRTTCwndGain   = 0.5
+	bbrPacingMarginFactor = 0.99
+	// The maximum tolerated per-round-trip packet loss rate when probing for bandwidth.
+	bbrLossThreshold = 0.02
+	// The multiplicative decrease applied to the bandwidth and inflight model on packet loss.
+	bbrBeta = 0.7
+	// The multiplicative factor used to leave headroom for other flows when cruising.
+	bbrHeadroom = 0.15
+	// Bandwidth growth required to consider the bandwidth still growing in Startup.
+	bbrFullBWThreshold = 1.25
+	bbrFullBWCount     = 3
+	// The number of loss events per round required to exit Startup due to high loss.
+	bbrStartupFullLossCount = 6
+	// The minimum congestion window, in packets.
+	bbrMinPipeCwndPackets = 4
+	// The number of round trips over which the extra acked filter is applied.
+	// The filter is implemented using two windows of half this length.
+	bbrExtraAckedFilterLen = 10
+	bbrMinRTTFilterLen     = 10 * time.Second
+	bbrProbeRTTInterval    = 5 * time.Second
+	bbrProbeRTTDuration    = 200 * time.Millisecond
+	bbrMaxProbeUpRounds    = 30
+	bbrMaxRenoRounds       = 63
+)
+
+const bbrInfiniteBandwidth = Bandwidth(math.MaxUint64)
+
+type bbrState uint8
+
+const (
+	bbrStateStartup bbrState = iota
+	bbrStateDrain
+	bbrStateProbeBWDown
+	bbrStateProbeBWCruise
+	bbrStateProbeBWRefill
+	bbrStateProbeBWUp
+	bbrStateProbeRTT
+)
+
+func (s bbrState) qlogState() qlog.CongestionState {
+	switch s {
+	case bbrStateStartup:
+		return qlog.CongestionStateStartup
+	case bbrStateDrain:
+		return qlog.CongestionStateDrain
+	case bbrStateProbeBWDown:
+		return qlog.CongestionStateProbeBWDown
+	case bbrStateProbeBWCruise:
+		return qlog.CongestionStateProbeBWCruise
+	case bbrStateProbeBWRefill:
+		return qlog.CongestionStateProbeBWRefill
+	case bbrStateProbeBWUp:
+		return qlog.CongestionStateProbeBWUp
+	case bbrStateProbeRTT:
+		return qlog.CongestionStateProbeRTT
+	default:
+		panic(fmt.Sprintf("unknown BBR state: %d", s))
+	}
+}
+
+type bbrAckPhase uint8
+
+const (
+	bbrAcksInit bbrAckPhase = iota
+	bbrAcksProbeStarting
+	bbrAcksProbeFeedback
+	bbrAcksProbeStopping
+	bbrAcksRefilling
+)
+
+type bbrSender struct {
+	rttStats RTTStats
+	pacer    *Pacer
+
+	state      bbrState
+	pacingGain float64
+	cwndGain   float64
+
+	cwnd            protocol.ByteCount
+	priorCwnd       protocol.ByteCount
+	pacingRate      Bandwidth
+	bytesInFlight   protocol.ByteCount
+	maxDatagramSize protocol.ByteCount
+	isCwndLimited   bool
+	isAppLimited    bool
+	idleRestart     bool
+
+	// round counting
+	delivered          protocol.ByteCount
+	nextRoundDelivered protocol.ByteCount
+	roundCount         uint64
+	roundStart         bool
+
+	// the bandwidth model
+	maxBW       Bandwidth
+	maxBWFilter [2]Bandwidth
+	bwLo        Bandwidth
+	bw          Bandwidth
+	minRTT      time.Duration
+	minRTTStamp monotime.Time
+
+	// the inflight model
+	inflightHi     protocol.ByteCount
+	inflightLo     protocol.ByteCount
+	bwLatest       Bandwidth
+	inflightLatest protocol.ByteCount
+
+	// congestion signals
+	lossRoundStart     bool
+	lossRoundDelivered protocol.ByteCount
+	lossInRound        bool
+	lossEventsInRound  int
+
+	// Startup
+	fullBW        Bandwidth
+	fullBWCount   int
+	fullBWNow     bool
+	fullBWReached bool
+
+	// ProbeBW
+	ackPhase           bbrAckPhase
+	cycleStamp         monotime.Time
+	roundsSinceBWProbe uint64
+	bwProbeWait        time.Duration
+	bwProbeSamples     bool
+	bwProbeUpRounds    int
+	bwProbeUpAcks      protocol.ByteCount
+	probeUpCount       protocol.ByteCount
+
+	// ProbeRTT
+	probeRTTMinDelay  time.Duration
+	probeRTTMinStamp  monotime.Time
+	probeRTTExpired   bool
+	probeRTTDoneStamp monotime.Time
+	probeRTTRoundDone bool
+
+	// ACK aggregation
+	extraAcked       protocol.ByteCount
+	extraAckedFilter [2]protocol.ByteCount
+	extraAckedIdx    int
+	extraAckedRounds int
+	ackEpochStart    monotime.Time
+	ackEpochAcked    protocol.ByteCount
+
+	initialCwnd protocol.ByteCount
+
+	lastQlogState qlog.CongestionState
+	qlogger       qlogwriter.Recorder
+}
+
+var (
+	_ SendAlgorithm               = &bbrSender{}
+	_ SendAlgorithmWithDebugInfos = &bbrSender{}
+)
+
+// NewBBRSender makes a new BBR sender.
+// initialBandwidthEstimate is the bandwidth of the path saved from a previous connection, or 0 if unknown.
+func NewBBRSender(
+	clock Clock,
+	rttStats RTTStats,
+	initialMaxDatagramSize protocol.ByteCount,
+	initialBandwidthEstimate Bandwidth,
+	qlogger qlogwriter.Recorder,
+) *bbrSender {
+	return newBBRSender(
+		clock,
+		rttStats,
+		initialMaxDatagramSize,
+		initialCongestionWindowFor(initialMaxDatagramSize, initialBandwidthEstimate, rttStats.SmoothedRTT()),
+		qlogger,
+	)
+}
+
+func newBBRSender(
+	clock Clock,
+	rttStats RTTStats,
+	initialMaxDatagramSize,
+	initialCwnd protocol.ByteCount,
+	qlogger qlogwriter.Recorder,
+) *bbrSender {
+	b := &bbrSender{
+		rttStats:         rttStats,
+		maxDatagramSize:  initialMaxDatagramSize,
+		cwnd:             initialCwnd,
+		initialCwnd:      initialCwnd,
+		bwLo:             bbrInfiniteBandwidth,
+		inflightHi:       protocol.MaxByteCount,
+		inflightLo:       protocol.MaxByteCount,
+		minRTT:           time.Duration(math.MaxInt64),
+		probeRTTMinDelay: time.Duration(math.MaxInt64),
+		probeUpCount:     protocol.MaxByteCount,
+		qlogger:          qlogger,
+	}
+	now := clock.Now()
+	b.minRTTStamp = now
+	b.probeRTTMinStamp = now
+	b.cycleStamp = now
+	b.ackEpochStart = now
+	b.pacer = NewPacer(b.PacingRate)
+	b.pacer.SetMaxDatagramSize(initialMaxDatagramSize)
+	b.initPacingRate()
+	b.enterStartup()
+	return b
+}
+
+// TimeUntilSend returns when the next packet should be sent.
+func (b *bbrSender) TimeUntilSend(_ protocol.ByteCount) monotime.Time {
+	return b.pacer.TimeUntilSend()
+}
+
+func (b *bbrSender) HasPacingBudget(now monotime.Time) bool {
+	return b.pacer.Budget(now) >= b.maxDatagramSize
+}
+
+func (b *bbrSender) OnPacketSent(
+	sentTime monotime.Time,
+	bytesInFlight protocol.ByteCount,
+	_ protocol.PacketNumber,
+	bytes protocol.ByteCount,
+	isRetransmittable bool,
+) {
+	b.pacer.SentPacket(sentTime, bytes)
+	if !isRetransmittable {
+		return
+	}
+	if bytesInFlight == bytes && b.isAppLimited {
+		b.handleRestartFromIdle(sentTime)
+	}
+	b.bytesInFlight = bytesInFlight
+	if bytesInFlight >= b.cwnd {
+		b.isCwndLimited = true
+	}
+}
+
+func (b *bbrSender) handleRestartFromIdle(now monotime.Time) {
+	b.idleRestart = true
+	b.ackEpochStart = now
+	if b.isInProbeBWState() {
+		b.setPacingRateWithGain(1)
+	} else if b.state == bbrStateProbeRTT {
+		b.checkProbeRTTDone(now)
+	}
+}
+
+func (b *bbrSender) CanSend(bytesInFlight protocol.ByteCount) bool {
+	return bytesInFlight < b.cwnd
+}
+
+func (b *bbrSender) InRecovery() bool { return false }
+
+func (b *bbrSender) InSlowStart() bool { return b.state == bbrStateStartup }
+
+func (b *bbrSender) GetCongestionWindow() protocol.ByteCount { return b.cwnd }
+
+// PacingRate returns the rate at which packets are paced out
+func (b *bbrSender) PacingRate() Bandwidth { return b.pacingRate }
+
+// BandwidthEstimate returns the current bandwidth estimate
+func (b *bbrSender) BandwidthEstimate() Bandwidth { return b.bw }
+
+// MaybeExitSlowStart is a no-op: BBR uses the delivery rate to decide when to exit Startup.
+func (b *bbrSender) MaybeExitSlowStart() {}
+
+// OnPacketAcked is a no-op: BBR updates its model using rate samples.
+func (b *bbrSender) OnPacketAcked(protocol.PacketNumber, protocol.ByteCount, protocol.ByteCount, monotime.Time) {
+}
+
+func (b *bbrSender) OnCongestionEvent(_ protocol.PacketNumber, lostBytes, _ protocol.ByteCount) {
+	if lostBytes > 0 {
+		b.lossEventsInRound++
+	}
+}
+
+// OnRetransmissionTimeout is called on an retransmission timeout
+func (b *bbrSender) OnRetransmissionTimeout(packetsRetransmitted bool) {
+	if !packetsRetransmitted {
+		return
+	}
+	b.saveCwnd()
+	b.cwnd = max(b.bytesInFlight+b.maxDatagramSize, b.minPipeCwnd())
+}
+
+func (b *bbrSender) SetMaxDatagramSize(s protocol.ByteCount) {
+	if s < b.maxDatagramSize {
+		panic(fmt.Sprintf("congestion BUG: decreased max datagram size from %d to %d", b.maxDatagramSize, s))
+	}
+	cwndIsMinCwnd := b.cwnd == b.minPipeCwnd()
+	b.maxDatagramSize = s
+	if cwndIsMinCwnd {
+		b.cwnd = b.minPipeCwnd()
+	}
+	b.pacer.SetMaxDatagramSize(s)
+}
+
+// OnRateSample is called once per ACK frame, after all packets acknowledged and lost
+// were passed to OnPacketAcked and OnCongestionEvent.
+func (b *bbrSender) OnRateSample(now monotime.Time, bytesInFlight protocol.ByteCount, rs RateSample) {
+	b.bytesInFlight = bytesInFlight
+	b.delivered = rs.TotalDelivered
+	b.isAppLimited = rs.IsAppLimited
+	b.updateModelAndState(now, rs)
+	b.updateControlParameters(rs)
+	b.maybeQlogStateChange()
+}
+
+func (b *bbrSender) updateModelAndState(now monotime.Time, rs RateSample) {
+	b.updateLatestDeliverySignals(rs)
+	b.updateCongestionSignals(rs)
+	b.updateACKAggregation(now, rs)
+	b.checkFullBWReached(rs)
+	b.checkStartupDone(rs)
+	b.checkDrainDone(now)
+	b.updateProbeBWCyclePhase(now, rs)
+	b.updateMinRTT(now, rs)
+	b.checkProbeRTT(now, rs)
+	b.advanceLatestDeliverySignals(rs)
+	b.boundBWForModel()
+}
+
+func (b *bbrSender) updateControlParameters(rs RateSample) {
+	b.setPacingRate()
+	b.setCwnd(rs)
+}
+
+func (b *bbrSender) maybeQlogStateChange() {
+	if b.qlogger == nil {
+		return
+	}
+	if s := b.state.qlogState(); s != b.lastQlogState {
+		b.qlogger.RecordEvent(qlog.CongestionStateUpdated{State: s})
+		b.lastQlogState = s
+	}
+}
+
+func (b *bbrSender) enterStartup() {
+	b.state = bbrStateStartup
+	b.pacingGain = bbrStartupPacingGain
+	b.cwndGain = bbrStartupCwndGain
+	b.maybeQlogStateChange()
+}
+
+func (b *bbrSender) checkStartupDone(rs RateSample) {
+	b.checkStartupHighLoss(rs)
+	if b.state == bbrStateStartup && b.fullBWReached {
+		b.enterDrain()
+	}
+}
+
+// checkStartupHighLoss exits Startup if the loss rate is too high.
+func (b *bbrSender) checkStartupHighLoss(rs RateSample) {
+	if b.fullBWReached || b.state != bbrStateStartup {
+		return
+	}
+	if b.lossEventsInRound >= bbrStartupFullLossCount && isInflightTooHigh(rs) {
+		b.fullBWReached = true
+		b.inflightHi = max(b.bdpMultiple(b.bw, 1), b.inflightLatest)
+	}
+}
+
+func (b *bbrSender) enterDrain() {
+	b.state = bbrStateDrain
+	b.pacingGain = bbrDrainPacingGain
+	b.cwndGain = bbrStartupCwndGain
+}
+
+func (b *bbrSender) checkDrainDone(now monotime.Time) {
+	if b.state == bbrStateDrain && b.bytesInFlight <= b.inflight(b.bw, 1) {
+		b.enterProbeBW(now)
+	}
+}
+
+func (b *bbrSender) checkFullBWReached(rs RateSample) {
+	if b.fullBWNow || !b.roundStart || rs.IsAppLimited {
+		return
+	}
+	if float64(rs.DeliveryRate) >= float64(b.fullBW)*bbrFullBWThreshold {
+		b.resetFullBW()
+		b.fullBW = rs.DeliveryRate
+		return
+	}
+	b.fullBWCount++
+	b.fullBWNow = b.fullBWCount >= bbrFullBWCount
+	if b.fullBWNow {
+		b.fullBWReached = true
+	}
+}
+
+func (b *bbrSender) resetFullBW() {
+	b.fullBW = 0
+	b.fullBWCount = 0
+	b.fullBWNow = false
+}
+
+func (b *bbrSender) startRound() {
+	b.nextRoundDelivered = b.delivered
+}
+
+func (b *bbrSender) updateRound(rs RateSample) {
+	if rs.PriorDelivered >= b.nextRoundDelivered {
+		b.startRound()
+		b.roundCount++
+		b.roundsSinceBWProbe++
+		b.roundStart = true
+	} else {
+		b.roundStart = false
+	}
+}
+
+func (b *bbrSender) updateLatestDeliverySignals(rs RateSample) {
+	b.lossRoundStart = false
+	b.bwLatest = max(b.bwLatest, rs.DeliveryRate)
+	b.inflightLatest = max(b.inflightLatest, rs.Delivered)
+	if rs.PriorDelivered >= b.lossRoundDelivered {
+		b.lossRoundDelivered = rs.TotalDelivered
+		b.lossRoundStart = true
+	}
+}
+
+func (b *bbrSender) advanceLatestDeliverySignals(rs RateSample) {
+	if b.lossRoundStart {
+		b.bwLatest = rs.DeliveryRate
+		b.inflightLatest = rs.Delivered
+	}
+}
+
+func (b *bbrSender) updateCongestionSignals(rs RateSample) {
+	b.updateMaxBW(rs)
+	if rs.NewlyLost > 0 {
+		b.lossInRound = true
+	}
+	if !b.lossRoundStart {
+		return
+	}
+	b.adaptLowerBoundsFromCongestion()
+	b.lossInRound = false
+	b.lossEventsInRound = 0
+}
+
+func (b *bbrSender) updateMaxBW(rs RateSample) {
+	b.updateRound(rs)
+	if rs.DeliveryRate > 0 && (rs.DeliveryRate >= b.maxBW || !rs.IsAppLimited) {
+		b.maxBWFilter[1] = max(b.maxBWFilter[1], rs.DeliveryRate)
+		b.maxBW = max(b.maxBWFilter[0], b.maxBWFilter[1])
+	}
+}
+
+func (b *bbrSender) advanceMaxBWFilter() {
+	b.maxBWFilter[0] = b.maxBWFilter[1]
+	b.maxBWFilter[1] = 0
+}
+
+func (b *bbrSender) adaptLowerBoundsFromCongestion() {
+	if b.isProbingBW() {
+		return
+	}
+	if b.lossInRound {
+		if b.bwLo == bbrInfiniteBandwidth {
+			b.bwLo = b.maxBW
+		}
+		if b.inflightLo == protocol.MaxByteCount {
+			b.inflightLo = b.cwnd
+		}
+		b.bwLo = max(b.bwLatest, Bandwidth(bbrBeta*float64(b.bwLo)))
+		b.inflightLo = max(b.inflightLatest, protocol.ByteCount(bbrBeta*float64(b.inflightLo)))
+	}
+}
+
+func (b *bbrSender) resetLowerBounds() {
+	b.bwLo = bbrInfiniteBandwidth
+	b.inflightLo = protocol.MaxByteCount
+}
+
+func (b *bbrSender) resetCongestionSignals() {
+	b.lossInRound = false
+	b.lossEventsInRound = 0
+	b.bwLatest = 0
+	b.inflightLatest = 0
+}
+
+func (b *bbrSender) boundBWForModel() {
+	b.bw = min(b.maxBW, b.bwLo)
+}
+
+func (b *bbrSender) updateACKAggregation(now monotime.Time, rs RateSample) {
+	// Use two windows of half the filter length, and advance the window when a new round starts.
+	if b.roundStart {
+		b.extraAckedRounds++
+		if b.extraAckedRounds >= bbrExtraAckedFilterLen/2 {
+			b.extraAckedRounds = 0
+			b.extraAckedIdx = 1 - b.extraAckedIdx
+			b.extraAckedFilter[b.extraAckedIdx] = 0
+		}
+	}
+	interval := now.Sub(b.ackEpochStart)
+	expectedDelivered := bytesFromBandwidth(b.bw, interval)
+	// reset the ACK aggregation epoch if the ACK rate is below the expected bandwidth
+	if b.ackEpochAcked <= expectedDelivered {
+		b.ackEpochAcked = 0
+		b.ackEpochStart = now
+		expectedDelivered = 0
+	}
+	b.ackEpochAcked += rs.NewlyAcked
+	extra := min(b.ackEpochAcked-expectedDelivered, b.cwnd)
+	b.extraAckedFilter[b.extraAckedIdx] = max(b.extraAckedFilter[b.extraAckedIdx], extra)
+	b.extraAcked = max(b.extraAckedFilter[0], b.extraAckedFilter[1])
+}
+
+func (b *bbrSender) isInProbeBWState() bool {
+	switch b.state {
+	case bbrStateProbeBWDown, bbrStateProbeBWCruise, bbrStateProbeBWRefill, bbrStateProbeBWUp:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *bbrSender) isProbingBW() bool {
+	switch b.state {
+	case bbrStateStartup, bbrStateProbeBWRefill, bbrStateProbeBWUp:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *bbrSender) enterProbeBW(now monotime.Time) {
+	b.cwndGain = bbrDefaultCwndGain
+	b.startProbeBWDown(now)
+}
+
+func (b *bbrSender) startProbeBWDown(now monotime.Time) {
+	b.resetCongestionSignals()
+	b.probeUpCount = protocol.MaxByteCount
+	b.pickProbeWait()
+	b.cycleStamp = now
+	b.ackPhase = bbrAcksProbeStopping
+	b.startRound()
+	b.state = bbrStateProbeBWDown
+	b.pacingGain = bbrProbeBWDownGain
+	b.cwndGain = bbrDefaultCwndGain
+}
+
+func (b *bbrSender) startProbeBWCruise() {
+	b.state = bbrStateProbeBWCruise
+	b.pacingGain = 1
+	b.cwndGain = bbrDefaultCwndGain
+}
+
+func (b *bbrSender) startProbeBWRefill() {
+	b.resetLowerBounds()
+	b.bwProbeUpRounds = 0
+	b.bwProbeUpAcks = 0
+	b.ackPhase = bbrAcksRefilling
+	b.startRound()
+	b.state = bbrStateProbeBWRefill
+	b.pacingGain = 1
+	b.cwndGain = bbrDefaultCwndGain
+}
+
+func (b *bbrSender) startProbeBWUp(now monotime.Time, rs RateSample) {
+	b.ackPhase = bbrAcksProbeStarting
+	b.startRound()
+	b.resetFullBW()
+	b.fullBW = rs.DeliveryRate
+	b.cycleStamp = now
+	b.state = bbrStateProbeBWUp
+	b.pacingGain = bbrProbeBWUpGain
+	b.cwndGain = bbrProbeBWUpCwndGain
+	b.raiseInflightHiSlope()
+}
+
+func (b *bbrSender) updateProbeBWCyclePhase(now monotime.Time, rs RateSample) {
+	if !b.fullBWReached {
+		return
+	}
+	b.adaptUpperBounds(now, rs)
+	if !b.isInProbeBWState() {
+		return
+	}
+	switch b.state {
+	case bbrStateProbeBWDown:
+		if b.isTimeToProbeBW(now) {
+			return
+		}
+		if b.isTimeToCruise() {
+			b.startProbeBWCruise()
+		}
+	case bbrStateProbeBWCruise:
+		b.isTimeToProbeBW(now)
+	case bbrStateProbeBWRefill:
+		// After one round of REFILL, start UP.
+		if b.roundStart {
+			b.bwProbeSamples = true
+			b.startProbeBWUp(now, rs)
+		}
+	case bbrStateProbeBWUp:
+		if b.isTimeToGoDown(rs) {
+			b.startProbeBWDown(now)
+		}
+	}
+}
+
+func (b *bbrSender) isTimeToProbeBW(now monotime.Time) bool {
+	if now.Sub(b.cycleStamp) > b.bwProbeWait || b.isRenoCoexistenceProbeTime() {
+		b.startProbeBWRefill()
+		return true
+	}
+	return false
+}
+
+func (b *bbrSender) pickProbeWait() {
+	b.roundsSinceBWProbe = uint64(rand.IntN(2))
+	b.bwProbeWait = 2*time.Second + rand.N(time.Second)
+}
+
+func (b *bbrSender) isRenoCoexistenceProbeTime() bool {
+	renoRounds := uint64(b.targetInflight() / b.maxDatagramSize)
+	return b.roundsSinceBWProbe >= min(renoRounds, bbrMaxRenoRounds)
+}
+
+func (b *bbrSender) targetInflight() protocol.ByteCount {
+	return min(b.bdpMultiple(b.bw, 1), b.cwnd)
+}
+
+func (b *bbrSender) isTimeToCruise() bool {
+	if b.bytesInFlight > b.inflightWithHeadroom() {
+		return false
+	}
+	return b.bytesInFlight <= b.inflight(b.maxBW, 1)
+}
+
+func (b *bbrSender) isTimeToGoDown(rs RateSample) bool {
+	if b.isCwndLimited && b.cwnd >= b.inflightHi {
+		b.resetFullBW()
+		b.fullBW = rs.DeliveryRate
+	} else if b.fullBWNow {
+		return true
+	}
+	return false
+}
+
+func isInflightTooHigh(rs RateSample) bool {
+	return float64(rs.Lost) > float64(rs.TxInFlight)*bbrLossThreshold
+}
+
+func (b *bbrSender) adaptUpperBounds(now monotime.Time, rs RateSample) {
+	if b.ackPhase == bbrAcksProbeStarting && b.roundStart {
+		// starting to get bandwidth probing samples
+		b.ackPhase = bbrAcksProbeFeedback
+	}
+	if b.ackPhase == bbrAcksProbeStopping && b.roundStart {
+		// end of samples from bandwidth probing phase
+		if b.isInProbeBWState() && !rs.IsAppLimited {
+			b.advanceMaxBWFilter()
+		}
+	}
+	if isInflightTooHigh(rs) {
+		if b.bwProbeSamples {
+			b.handleInflightTooHigh(now, rs)
+		}
+		return
+	}
+	if b.inflightHi == protocol.MaxByteCount {
+		return
+	}
+	if rs.TxInFlight > b.inflightHi {
+		b.inflightHi = rs.TxInFlight
+	}
+	if b.state == bbrStateProbeBWUp {
+		b.probeInflightHiUpward(rs)
+	}
+}
+
+func (b *bbrSender) handleInflightTooHigh(now monotime.Time, rs RateSample) {
+	b.bwProbeSamples = false
+	if !rs.IsAppLimited {
+		b.inflightHi = max(rs.TxInFlight, protocol.ByteCount(float64(b.targetInflight())*bbrBeta))
+	}
+	if b.state == bbrStateProbeBWUp {
+		b.startProbeBWDown(now)
+	}
+}
+
+func (b *bbrSender) raiseInflightHiSlope() {
+	growthThisRound := b.maxDatagramSize << b.bwProbeUpRounds
+	b.bwProbeUpRounds = min(b.bwProbeUpRounds+1, bbrMaxProbeUpRounds)
+	b.probeUpCount = max(b.cwnd/growthThisRound, 1)
+}
+
+func (b *bbrSender) probeInflightHiUpward(rs RateSample) {
+	if !b.isCwndLimited || b.cwnd < b.inflightHi {
+		return // not fully using inflight_hi, so don't grow it
+	}
+	b.bwProbeUpAcks += rs.NewlyAcked
+	if b.bwProbeUpAcks >= b.probeUpCount {
+		delta := b.bwProbeUpAcks / b.probeUpCount
+		b.bwProbeUpAcks -= delta * b.probeUpCount
+		b.inflightHi += delta
+	}
+	if b.roundStart {
+		b.raiseInflightHiSlope()
+	}
+}
+
+func (b *bbrSender) updateMinRTT(now monotime.Time, rs RateSample) {
+	b.probeRTTExpired = now.Sub(b.probeRTTMinStamp) > bbrProbeRTTInterval
+	if rs.RTT > 0 && (rs.RTT < b.probeRTTMinDelay || b.probeRTTExpired) {
+		b.probeRTTMinDelay = rs.RTT
+		b.probeRTTMinStamp = now
+	}
+	minRTTExpired := now.Sub(b.minRTTStamp) > bbrMinRTTFilterLen
+	if b.probeRTTMinDelay < b.minRTT || minRTTExpired {
+		b.minRTT = b.probeRTTMinDelay
+		b.minRTTStamp = b.probeRTTMinStamp
+	}
+}
+
+func (b *bbrSender) checkProbeRTT(now monotime.Time, rs RateSample) {
+	if b.state != bbrStateProbeRTT && b.probeRTTExpired && !b.idleRestart {
+		b.enterProbeRTT()
+		b.saveCwnd()
+		b.probeRTTDoneStamp = 0
+		b.ackPhase = bbrAcksProbeStopping
+		b.startRound()
+	}
+	if b.state == bbrStateProbeRTT {
+		b.handleProbeRTT(now)
+	}
+	if rs.Delivered > 0 {
+		b.idleRestart = false
+	}
+}
+
+func (b *bbrSender) enterProbeRTT() {
+	b.state = bbrStateProbeRTT
+	b.pacingGain = 1
+	b.cwndGain = bbrProbeRTTCwndGain
+}
+
+func (b *bbrSender) handleProbeRTT(now monotime.Time) {
+	if b.probeRTTDoneStamp.IsZero() && b.bytesInFlight <= b.probeRTTCwnd() {
+		// wait for at least ProbeRTTDuration and at least one round to elapse
+		b.probeRTTDoneStamp = now.Add(bbrProbeRTTDuration)
+		b.probeRTTRoundDone = false
+		b.startRound()
+	} else if !b.probeRTTDoneStamp.IsZero() {
+		if b.roundStart {
+			b.probeRTTRoundDone = true
+		}
+		if b.probeRTTRoundDone {
+			b.checkProbeRTTDone(now)
+		}
+	}
+}
+
+func (b *bbrSender) checkProbeRTTDone(now monotime.Time) {
+	if !b.probeRTTDoneStamp.IsZero() && now.After(b.probeRTTDoneStamp) {
+		// schedule the next ProbeRTT
+		b.probeRTTMinStamp = now
+		b.restoreCwnd()
+		b.exitProbeRTT(now)
+	}
+}
+
+func (b *bbrSender) exitProbeRTT(now monotime.Time) {
+	b.resetLowerBounds()
+	if b.fullBWReached {
+		b.startProbeBWDown(now)
+		b.startProbeBWCruise()
+	} else {
+		b.enterStartup()
+	}
+}
+
+func (b *bbrSender) probeRTTCwnd() protocol.ByteCount {
+	return max(b.bdpMultiple(b.bw, bbrProbeRTTCwndGain), b.minPipeCwnd())
+}
+
+func (b *bbrSender) saveCwnd() {
+	if b.state != bbrStateProbeRTT {
+		b.priorCwnd = b.cwnd
+	} else {
+		b.priorCwnd = max(b.priorCwnd, b.cwnd)
+	}
+}
+
+func (b *bbrSender) restoreCwnd() {
+	b.cwnd = max(b.cwnd, b.priorCwnd)
+}
+
+func (b *bbrSender) initPacingRate() {
+	srtt := b.rttStats.SmoothedRTT()
+	if srtt == 0 {
+		srtt = time.Millisecond
+	}
+	b.pacingRate = Bandwidth(bbrStartupPacingGain * float64(BandwidthFromDelta(b.cwnd, srtt)))
+}
+
+func (b *bbrSender) setPacingRate() {
+	b.setPacingRateWithGain(b.pacingGain)
+}
+
+func (b *bbrSender) setPacingRateWithGain(gain float64) {
+	if b.bw == 0 {
+		return
+	}
+	rate := Bandwidth(gain * float64(b.bw) * bbrPacingMarginFactor)
+	if b.fullBWReached || rate > b.pacingRate {
+		b.pacingRate = rate
+	}
+}
+
+func (b *bbrSender) minPipeCwnd() protocol.ByteCount {
+	return bbrMinPipeCwndPackets * b.maxDatagramSize
+}
+
+func (b *bbrSender) bdpMultiple(bw Bandwidth, gain float64) protocol.ByteCount {
+	if b.minRTT == time.Duration(math.MaxInt64) {
+		return b.initialCwnd // no valid RTT samples yet
+	}
+	return protocol.ByteCount(gain * float64(bytesFromBandwidth(bw, b.minRTT)))
+}
+
+func (b *bbrSender) quantizationBudget(inflight protocol.ByteCount) protocol.ByteCount {
+	inflight = max(inflight, b.minPipeCwnd())
+	if b.state == bbrStateProbeBWUp {
+		inflight += 2 * b.maxDatagramSize
+	}
+	return inflight
+}
+
+func (b *bbrSender) inflight(bw Bandwidth, gain float64) protocol.ByteCount {
+	return b.quantizationBudget(b.bdpMultiple(bw, gain))
+}
+
+func (b *bbrSender) maxInflight() protocol.ByteCount {
+	return b.quantizationBudget(b.bdpMultiple(b.bw, b.cwndGain) + b.extraAcked)
+}
+
+func (b *bbrSender) inflightWithHeadroom() protocol.ByteCount {
+	if b.inflightHi == protocol.MaxByteCount {
+		return protocol.MaxByteCount
+	}
+	headroom := max(b.maxDatagramSize, protocol.ByteCount(bbrHeadroom*float64(b.inflightHi)))
+	return max(b.inflightHi-headroom, b.minPipeCwnd())
+}
+
+func (b *bbrSender) setCwnd(rs RateSample) {
+	maxInflight := b.maxInflight()
+	if rs.NewlyLost > 0 {
+		b.cwnd = max(b.cwnd-rs.NewlyLost, b.minPipeCwnd())
+	}
+	if b.fullBWReached {
+		b.cwnd = min(b.cwnd+rs.NewlyAcked, maxInflight)
+	} else if b.cwnd < maxInflight || b.delivered < b.initialCwnd {
+		b.cwnd += rs.NewlyAcked
+	}
+	b.cwnd = max(b.cwnd, b.minPipeCwnd())
+	if b.state == bbrStateProbeRTT {
+		b.cwnd = min(b.cwnd, b.probeRTTCwnd())
+	}
+	b.boundCwndForModel()
+	if b.roundStart {
+		b.isCwndLimited = false
+	}
+}
+
+func (b *bbrSender) boundCwndForModel() {
+	capacity := protocol.MaxByteCount
+	if b.isInProbeBWState() && b.state != bbrStateProbeBWCruise {
+		capacity = b.inflightHi
+	} else if b.state == bbrStateProbeRTT || b.state == bbrStateProbeBWCruise {
+		capacity = b.inflightWithHeadroom()
+	}
+	capacity = min(capacity, b.inflightLo)
+	capacity = max(capacity, b.minPipeCwnd())
+	b.cwnd = min(b.cwnd, capacity)
+}
+
+func bytesFromBandwidth(bw Bandwidth, d time.Duration) protocol.ByteCount {
+	return protocol.ByteCount(float64(bw/BytesPerSecond) * d.Seconds())
+}
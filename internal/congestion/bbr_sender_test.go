@@ -0,0 +1,236 @@
+package congestion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/utils"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+	"github.com/quic-go/quic-go/testutils/events"
+
+	"github.com/stretchr/testify/require"
+)
+
+type bbrTestPacket struct {
+	pn            protocol.PacketNumber
+	sendTime      monotime.Time
+	ackTime       monotime.Time
+	lost          bool
+	delivered     protocol.ByteCount
+	deliveredTime monotime.Time
+	firstSentTime monotime.Time
+	priorLost     protocol.ByteCount
+	txInFlight    protocol.ByteCount
+}
+
+// bbrTestLink simulates a path with a bottleneck link of a fixed bandwidth and a fixed RTT.
+// It implements a simplified version of the delivery rate estimation.
+type bbrTestLink struct {
+	bandwidth Bandwidth
+	rtt       time.Duration
+	// shouldDrop is called for every packet sent
+	shouldDrop func(protocol.PacketNumber) bool
+
+	clock         mockClock
+	sender        *bbrSender
+	rttStats      *utils.RTTStats
+	inFlight      []*bbrTestPacket
+	bytesInFlight protocol.ByteCount
+	nextPN        protocol.PacketNumber
+	lastDeparture monotime.Time
+	delivered     protocol.ByteCount
+	deliveredTime monotime.Time
+	firstSentTime monotime.Time
+	lost          protocol.ByteCount
+	states        []bbrState
+}
+
+func newBBRTestLink(bandwidth Bandwidth, rtt time.Duration, recorder qlogwriter.Recorder) *bbrTestLink {
+	l := &bbrTestLink{
+		bandwidth: bandwidth,
+		rtt:       rtt,
+		rttStats:  utils.NewRTTStats(),
+		clock:     mockClock(monotime.Now()),
+	}
+	l.sender = newBBRSender(&l.clock, l.rttStats, maxDatagramSize, initialCongestionWindow*maxDatagramSize, recorder)
+	return l
+}
+
+func (l *bbrTestLink) send(now monotime.Time) {
+	if l.bytesInFlight == 0 {
+		l.firstSentTime = now
+		l.deliveredTime = now
+	}
+	p := &bbrTestPacket{
+		pn:            l.nextPN,
+		sendTime:      now,
+		delivered:     l.delivered,
+		deliveredTime: l.deliveredTime,
+		firstSentTime: l.firstSentTime,
+		priorLost:     l.lost,
+		txInFlight:    l.bytesInFlight + maxDatagramSize,
+	}
+	l.nextPN++
+	departure := max(now, l.lastDeparture).Add(time.Duration(float64(Bandwidth(maxDatagramSize)*BytesPerSecond) / float64(l.bandwidth) * float64(time.Second)))
+	l.lastDeparture = departure
+	p.ackTime = departure.Add(l.rtt)
+	if l.shouldDrop != nil && l.shouldDrop(p.pn) {
+		p.lost = true
+	}
+	l.bytesInFlight += maxDatagramSize
+	l.sender.OnPacketSent(now, l.bytesInFlight, p.pn, maxDatagramSize, true)
+	l.inFlight = append(l.inFlight, p)
+}
+
+// processACK acknowledges all packets that arrived before now.
+// Lost packets are declared lost once a later packet is acknowledged.
+func (l *bbrTestLink) processACK(now monotime.Time) {
+	var rs RateSample
+	var newest *bbrTestPacket
+	var largestAcked protocol.PacketNumber = -1
+	remaining := l.inFlight[:0]
+	for _, p := range l.inFlight {
+		if p.lost || p.ackTime.After(now) {
+			remaining = append(remaining, p)
+			continue
+		}
+		l.delivered += maxDatagramSize
+		l.deliveredTime = now
+		l.bytesInFlight -= maxDatagramSize
+		rs.NewlyAcked += maxDatagramSize
+		newest = p
+		largestAcked = p.pn
+	}
+	if newest == nil {
+		l.inFlight = remaining
+		return
+	}
+	l.inFlight = l.inFlight[:0]
+	for _, p := range remaining {
+		if p.lost && p.pn < largestAcked {
+			l.lost += maxDatagramSize
+			l.bytesInFlight -= maxDatagramSize
+			rs.NewlyLost += maxDatagramSize
+			l.sender.OnCongestionEvent(p.pn, maxDatagramSize, l.bytesInFlight)
+			continue
+		}
+		l.inFlight = append(l.inFlight, p)
+	}
+	l.rttStats.UpdateRTT(now.Sub(newest.sendTime), 0)
+	rs.PriorDelivered = newest.delivered
+	rs.TotalDelivered = l.delivered
+	rs.Delivered = l.delivered - newest.delivered
+	rs.TxInFlight = newest.txInFlight
+	rs.Lost = l.lost - newest.priorLost
+	rs.RTT = now.Sub(newest.sendTime)
+	rs.Interval = max(newest.sendTime.Sub(newest.firstSentTime), now.Sub(newest.deliveredTime))
+	l.firstSentTime = newest.sendTime
+	if rs.Interval >= l.rttStats.MinRTT() {
+		rs.DeliveryRate = BandwidthFromDelta(rs.Delivered, rs.Interval)
+	}
+	l.sender.OnRateSample(now, l.bytesInFlight, rs)
+}
+
+func (l *bbrTestLink) run(d time.Duration) {
+	end := monotime.Time(l.clock).Add(d)
+	for now := monotime.Time(l.clock); now.Before(end); now = now.Add(time.Millisecond) {
+		l.clock = mockClock(now)
+		l.processACK(now)
+		for l.sender.CanSend(l.bytesInFlight) && l.sender.HasPacingBudget(now) {
+			l.send(now)
+		}
+		if len(l.states) == 0 || l.states[len(l.states)-1] != l.sender.state {
+			l.states = append(l.states, l.sender.state)
+		}
+	}
+}
+
+func TestBBRStartupAndProbeBW(t *testing.T) {
+	var recorder events.Recorder
+	const bandwidth = 10_000_000 * BitsPerSecond // 10 Mbit/s
+	const rtt = 40 * time.Millisecond
+	l := newBBRTestLink(bandwidth, rtt, &recorder)
+	require.Equal(t, bbrStateStartup, l.sender.state)
+	require.True(t, l.sender.InSlowStart())
+	require.Equal(t, initialCongestionWindow*maxDatagramSize, l.sender.GetCongestionWindow())
+	require.NotZero(t, l.sender.PacingRate())
+
+	l.run(3 * time.Second)
+
+	require.NotEqual(t, bbrStateStartup, l.sender.state)
+	require.False(t, l.sender.InSlowStart())
+	require.True(t, l.sender.isInProbeBWState())
+	require.InEpsilon(t, float64(bandwidth), float64(l.sender.BandwidthEstimate()), 0.1)
+	require.InEpsilon(t, rtt, l.sender.minRTT, 0.1)
+	// the congestion window is roughly 2 BDP (plus some allowance for ACK aggregation)
+	bdp := bytesFromBandwidth(bandwidth, rtt)
+	require.Greater(t, l.sender.GetCongestionWindow(), bdp)
+	require.Less(t, l.sender.GetCongestionWindow(), 4*bdp)
+	require.Equal(t, []bbrState{bbrStateStartup, bbrStateDrain}, l.states[:2])
+
+	evs := recorder.Events(qlog.CongestionStateUpdated{})
+	require.GreaterOrEqual(t, len(evs), 3)
+	require.Equal(t, qlog.CongestionStateUpdated{State: qlog.CongestionStateStartup}, evs[0])
+	require.Equal(t, qlog.CongestionStateUpdated{State: qlog.CongestionStateDrain}, evs[1])
+	require.Contains(t,
+		[]qlog.CongestionState{qlog.CongestionStateProbeBWDown, qlog.CongestionStateProbeBWCruise},
+		evs[2].(qlog.CongestionStateUpdated).State,
+	)
+}
+
+func TestBBRProbeRTT(t *testing.T) {
+	l := newBBRTestLink(10_000_000*BitsPerSecond, 20*time.Millisecond, nil)
+	l.run(12 * time.Second)
+	require.Contains(t, l.states, bbrStateProbeRTT)
+	// ProbeRTT is left after ProbeRTTDuration
+	require.NotEqual(t, bbrStateProbeRTT, l.states[len(l.states)-1])
+	require.True(t, l.sender.fullBWReached)
+}
+
+func TestBBRLoss(t *testing.T) {
+	const bandwidth = 10_000_000 * BitsPerSecond // 10 Mbit/s
+	const rtt = 40 * time.Millisecond
+	l := newBBRTestLink(bandwidth, rtt, nil)
+	l.run(3 * time.Second)
+	require.True(t, l.sender.isInProbeBWState())
+	require.Equal(t, protocol.MaxByteCount, l.sender.inflightLo)
+
+	// drop every 10th packet
+	l.shouldDrop = func(pn protocol.PacketNumber) bool { return pn%10 == 0 }
+	l.run(time.Second)
+	// the loss rate is too high, and the inflight model is reduced
+	require.Less(t, l.sender.inflightHi, protocol.MaxByteCount)
+	bdp := bytesFromBandwidth(bandwidth, rtt)
+	require.Less(t, l.sender.GetCongestionWindow(), 2*bdp)
+}
+
+func TestBBRStartupHighLoss(t *testing.T) {
+	l := newBBRTestLink(100_000_000*BitsPerSecond, 40*time.Millisecond, nil)
+	l.shouldDrop = func(pn protocol.PacketNumber) bool { return pn%5 == 0 && pn > 10 }
+	l.run(500 * time.Millisecond)
+	require.True(t, l.sender.fullBWReached)
+	require.NotEqual(t, bbrStateStartup, l.sender.state)
+	require.Less(t, l.sender.inflightHi, protocol.MaxByteCount)
+}
+
+func TestBBRRetransmissionTimeout(t *testing.T) {
+	var clock mockClock
+	b := newBBRSender(&clock, utils.NewRTTStats(), maxDatagramSize, initialCongestionWindow*maxDatagramSize, nil)
+	b.OnPacketSent(monotime.Time(clock), 3*maxDatagramSize, 0, maxDatagramSize, true)
+	b.OnRetransmissionTimeout(false)
+	require.Equal(t, initialCongestionWindow*maxDatagramSize, b.GetCongestionWindow())
+	b.OnRetransmissionTimeout(true)
+	require.Equal(t, 4*maxDatagramSize, b.GetCongestionWindow())
+	require.Equal(t, initialCongestionWindow*maxDatagramSize, b.priorCwnd)
+}
+
+func TestBBRSetMaxDatagramSize(t *testing.T) {
+	var clock mockClock
+	b := newBBRSender(&clock, utils.NewRTTStats(), maxDatagramSize, 4*maxDatagramSize, nil)
+	b.SetMaxDatagramSize(maxDatagramSize + 100)
+	require.Equal(t, 4*(maxDatagramSize+100), b.GetCongestionWindow())
+	require.Panics(t, func() { b.SetMaxDatagramSize(maxDatagramSize) })
+}
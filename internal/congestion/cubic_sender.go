@@ -5,7 +5,6 @@ import (
 
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
-	"github.com/quic-go/quic-go/internal/utils"
 	"github.com/quic-go/quic-go/qlog"
 	"github.com/quic-go/quic-go/qlogwriter"
 )
@@ -22,10 +21,9 @@ const (
 
 type cubicSender struct {
 	hybridSlowStart HybridSlowStart
-	rttStats        *utils.RTTStats
-	connStats       *utils.ConnectionStats
+	rttStats        RTTStats
 	cubic           *Cubic
-	pacer           *pacer
+	pacer           *Pacer
 	clock           Clock
 
 	reno bool
@@ -66,22 +64,22 @@ var (
 	_ SendAlgorithmWithDebugInfos = &cubicSender{}
 )
 
-// NewCubicSender makes a new cubic sender
+// NewCubicSender makes a new cubic sender.
+// initialBandwidthEstimate is the bandwidth of the path saved from a previous connection, or 0 if unknown.
 func NewCubicSender(
 	clock Clock,
-	rttStats *utils.RTTStats,
-	connStats *utils.ConnectionStats,
+	rttStats RTTStats,
 	initialMaxDatagramSize protocol.ByteCount,
+	initialBandwidthEstimate Bandwidth,
 	reno bool,
 	qlogger qlogwriter.Recorder,
 ) *cubicSender {
 	return newCubicSender(
 		clock,
 		rttStats,
-		connStats,
 		reno,
 		initialMaxDatagramSize,
-		initialCongestionWindow*initialMaxDatagramSize,
+		initialCongestionWindowFor(initialMaxDatagramSize, initialBandwidthEstimate, rttStats.SmoothedRTT()),
 		protocol.MaxCongestionWindowPackets*initialMaxDatagramSize,
 		qlogger,
 	)
@@ -89,8 +87,7 @@ func NewCubicSender(
 
 func newCubicSender(
 	clock Clock,
-	rttStats *utils.RTTStats,
-	connStats *utils.ConnectionStats,
+	rttStats RTTStats,
 	reno bool,
 	initialMaxDatagramSize,
 	initialCongestionWindow,
@@ -99,7 +96,6 @@ func newCubicSender(
 ) *cubicSender {
 	c := &cubicSender{
 		rttStats:                   rttStats,
-		connStats:                  connStats,
 		largestSentPacketNumber:    protocol.InvalidPacketNumber,
 		largestAckedPacketNumber:   protocol.InvalidPacketNumber,
 		largestSentAtLastCutback:   protocol.InvalidPacketNumber,
@@ -113,7 +109,7 @@ func newCubicSender(
 		qlogger:                    qlogger,
 		maxDatagramSize:            initialMaxDatagramSize,
 	}
-	c.pacer = newPacer(c.BandwidthEstimate)
+	c.pacer = NewPacer(c.PacingRate)
 	if c.qlogger != nil {
 		c.lastState = qlog.CongestionStateSlowStart
 		c.qlogger.RecordEvent(qlog.CongestionStateUpdated{
@@ -167,6 +163,10 @@ func (c *cubicSender) InSlowStart() bool {
 	return c.GetCongestionWindow() < c.slowStartThreshold
 }
 
+func (c *cubicSender) SlowStartThreshold() protocol.ByteCount {
+	return c.slowStartThreshold
+}
+
 func (c *cubicSender) GetCongestionWindow() protocol.ByteCount {
 	return c.congestionWindow
 }
@@ -197,9 +197,6 @@ func (c *cubicSender) OnPacketAcked(
 }
 
 func (c *cubicSender) OnCongestionEvent(packetNumber protocol.PacketNumber, lostBytes, priorInFlight protocol.ByteCount) {
-	c.connStats.PacketsLost.Add(1)
-	c.connStats.BytesLost.Add(uint64(lostBytes))
-
 	// TCP NewReno (RFC6582) says that once a loss occurs, any losses in packets
 	// already sent should be treated as a single loss event, since it's expected.
 	if packetNumber <= c.largestSentAtLastCutback {
@@ -284,6 +281,15 @@ func (c *cubicSender) BandwidthEstimate() Bandwidth {
 	return BandwidthFromDelta(c.GetCongestionWindow(), srtt)
 }
 
+// PacingRate returns the rate at which packets are paced out
+func (c *cubicSender) PacingRate() Bandwidth {
+	// Use a slightly higher value than the actual measured bandwidth.
+	// RTT variations then won't result in under-utilization of the congestion window.
+	// Ultimately, this will result in sending packets as acknowledgments are received rather than when timers fire,
+	// provided the congestion window is fully utilized and acknowledgments arrive at regular intervals.
+	return c.BandwidthEstimate() * 5 / 4
+}
+
 // OnRetransmissionTimeout is called on an retransmission timeout
 func (c *cubicSender) OnRetransmissionTimeout(packetsRetransmitted bool) {
 	c.largestSentAtLastCutback = protocol.InvalidPacketNumber
@@ -48,7 +48,6 @@ func newTestCubicSender(cubic bool) *testCubicSender {
 		sender: newCubicSender(
 			&clock,
 			&rttStats,
-			&utils.ConnectionStats{},
 			!cubic,
 			protocol.InitialPacketSize,
 			initialCongestionWindowPackets*maxDatagramSize,
@@ -177,6 +176,7 @@ func TestCubicSenderExponentialSlowStart(t *testing.T) {
 
 func TestCubicSenderSlowStartPacketLoss(t *testing.T) {
 	sender := newTestCubicSender(false)
+	require.Equal(t, protocol.MaxByteCount, sender.sender.SlowStartThreshold())
 
 	const numberOfAcks = 10
 	for range numberOfAcks {
@@ -195,6 +195,7 @@ func TestCubicSenderSlowStartPacketLoss(t *testing.T) {
 	// We should now have fallen out of slow start with a reduced window.
 	expectedSendWindow = protocol.ByteCount(float32(expectedSendWindow) * renoBeta)
 	require.Equal(t, expectedSendWindow, sender.sender.GetCongestionWindow())
+	require.Equal(t, expectedSendWindow, sender.sender.SlowStartThreshold())
 
 	// Recovery phase. We need to ack every packet in the recovery window before
 	// we exit recovery.
@@ -495,7 +496,6 @@ func TestCubicSenderSlowStartsUpToMaximumCongestionWindow(t *testing.T) {
 	sender := newCubicSender(
 		&clock,
 		&rttStats,
-		&utils.ConnectionStats{},
 		true,
 		protocol.InitialPacketSize,
 		initialCongestionWindowPackets*maxDatagramSize,
@@ -522,7 +522,6 @@ func TestCubicSenderSlowStartsPacketSizeIncrease(t *testing.T) {
 	sender := newCubicSender(
 		&clock,
 		&rttStats,
-		&utils.ConnectionStats{},
 		true,
 		protocol.InitialPacketSize,
 		initialCongestionWindowPackets*maxDatagramSize,
@@ -546,7 +545,6 @@ func TestCubicSenderLimitCwndIncreaseInCongestionAvoidance(t *testing.T) {
 	sender := newCubicSender(
 		&clock,
 		&rttStats,
-		&utils.ConnectionStats{},
 		false,
 		protocol.InitialPacketSize,
 		initialCongestionWindowPackets*maxDatagramSize,
@@ -1,10 +1,60 @@
 package congestion
 
 import (
+	"time"
+
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/qlogwriter"
 )
 
+// Params are the parameters used to create a congestion controller.
+type Params struct {
+	RTTStats                 RTTStats
+	InitialMaxDatagramSize   protocol.ByteCount
+	InitialBandwidthEstimate Bandwidth // 0 if unknown
+	Recorder                 qlogwriter.Recorder
+}
+
+// RTTStats provides the RTT estimates of a connection.
+// It is implemented by utils.RTTStats.
+type RTTStats interface {
+	MinRTT() time.Duration
+	LatestRTT() time.Duration
+	SmoothedRTT() time.Duration
+	MeanDeviation() time.Duration
+}
+
+// A RateSample is a delivery rate sample,
+// see https://datatracker.ietf.org/doc/html/draft-cheng-iccrg-delivery-rate-estimation.
+// A RateSample is generated for every ACK frame that newly acknowledges packets.
+// Its values are derived from the most recently sent packet acknowledged by that ACK frame.
+type RateSample struct {
+	// DeliveryRate is the estimated delivery rate.
+	// It is 0 if the sample interval was too short to obtain a valid estimate.
+	DeliveryRate Bandwidth
+	// Delivered is the number of bytes delivered during the sample interval.
+	Delivered protocol.ByteCount
+	// Interval is the length of the sample interval.
+	Interval time.Duration
+	// RTT is the RTT measured for the packet.
+	RTT time.Duration
+	// IsAppLimited says if the packet was sent while the sender was application-limited.
+	IsAppLimited bool
+	// PriorDelivered is the total number of bytes delivered when the packet was sent.
+	PriorDelivered protocol.ByteCount
+	// TotalDelivered is the total number of bytes delivered.
+	TotalDelivered protocol.ByteCount
+	// TxInFlight is the number of bytes in flight when the packet was sent, including the packet itself.
+	TxInFlight protocol.ByteCount
+	// Lost is the number of bytes declared lost since the packet was sent.
+	Lost protocol.ByteCount
+	// NewlyAcked is the number of bytes newly acknowledged by the ACK frame.
+	NewlyAcked protocol.ByteCount
+	// NewlyLost is the number of bytes declared lost since the last rate sample.
+	NewlyLost protocol.ByteCount
+}
+
 // A SendAlgorithm performs congestion control
 type SendAlgorithm interface {
 	TimeUntilSend(bytesInFlight protocol.ByteCount) monotime.Time
@@ -16,6 +66,23 @@ type SendAlgorithm interface {
 	OnCongestionEvent(number protocol.PacketNumber, lostBytes protocol.ByteCount, priorInFlight protocol.ByteCount)
 	OnRetransmissionTimeout(packetsRetransmitted bool)
 	SetMaxDatagramSize(protocol.ByteCount)
+	GetCongestionWindow() protocol.ByteCount
+	PacingRate() Bandwidth
+}
+
+// A RateSampleConsumer is a SendAlgorithm that uses delivery rate samples.
+type RateSampleConsumer interface {
+	OnRateSample(eventTime monotime.Time, bytesInFlight protocol.ByteCount, rs RateSample)
+}
+
+// A BandwidthEstimator is a SendAlgorithm that estimates the bandwidth of the path.
+type BandwidthEstimator interface {
+	BandwidthEstimate() Bandwidth
+}
+
+// A SlowStartThresholdReporter is a SendAlgorithm that uses a slow start threshold.
+type SlowStartThresholdReporter interface {
+	SlowStartThreshold() protocol.ByteCount
 }
 
 // A SendAlgorithmWithDebugInfos is a SendAlgorithm that exposes some debug infos
@@ -23,5 +90,4 @@ type SendAlgorithmWithDebugInfos interface {
 	SendAlgorithm
 	InSlowStart() bool
 	InRecovery() bool
-	GetCongestionWindow() protocol.ByteCount
 }
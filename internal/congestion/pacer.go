@@ -10,32 +10,30 @@ import (
 
 const maxBurstSizePackets = 10
 
-// The pacer implements a token bucket pacing algorithm.
-type pacer struct {
+// The Pacer implements a token bucket pacing algorithm.
+type Pacer struct {
 	budgetAtLastSent  protocol.ByteCount
 	maxDatagramSize   protocol.ByteCount
 	lastSentTime      monotime.Time
 	adjustedBandwidth func() uint64 // in bytes/s
 }
 
-func newPacer(getBandwidth func() Bandwidth) *pacer {
-	p := &pacer{
+// NewPacer creates a new Pacer.
+// getPacingRate is called every time the pacer needs to know the current pacing rate.
+func NewPacer(getPacingRate func() Bandwidth) *Pacer {
+	p := &Pacer{
 		maxDatagramSize: initialMaxDatagramSize,
 		adjustedBandwidth: func() uint64 {
 			// Bandwidth is in bits/s. We need the value in bytes/s.
-			bw := uint64(getBandwidth() / BytesPerSecond)
-			// Use a slightly higher value than the actual measured bandwidth.
-			// RTT variations then won't result in under-utilization of the congestion window.
-			// Ultimately, this will result in sending packets as acknowledgments are received rather than when timers fire,
-			// provided the congestion window is fully utilized and acknowledgments arrive at regular intervals.
-			return bw * 5 / 4
+			return uint64(getPacingRate() / BytesPerSecond)
 		},
 	}
 	p.budgetAtLastSent = p.maxBurstSize()
 	return p
 }
 
-func (p *pacer) SentPacket(sendTime monotime.Time, size protocol.ByteCount) {
+// SentPacket is called when a packet is sent.
+func (p *Pacer) SentPacket(sendTime monotime.Time, size protocol.ByteCount) {
 	budget := p.Budget(sendTime)
 	if size >= budget {
 		p.budgetAtLastSent = 0
@@ -45,7 +43,8 @@ func (p *pacer) SentPacket(sendTime monotime.Time, size protocol.ByteCount) {
 	p.lastSentTime = sendTime
 }
 
-func (p *pacer) Budget(now monotime.Time) protocol.ByteCount {
+// Budget returns the number of bytes that can be sent at the given time.
+func (p *Pacer) Budget(now monotime.Time) protocol.ByteCount {
 	if p.lastSentTime.IsZero() {
 		return p.maxBurstSize()
 	}
@@ -61,7 +60,7 @@ func (p *pacer) Budget(now monotime.Time) protocol.ByteCount {
 	return min(p.maxBurstSize(), budget)
 }
 
-func (p *pacer) maxBurstSize() protocol.ByteCount {
+func (p *Pacer) maxBurstSize() protocol.ByteCount {
 	return max(
 		p.timeScaledBandwidth(uint64((protocol.MinPacingDelay + protocol.TimerGranularity).Nanoseconds())),
 		maxBurstSizePackets*p.maxDatagramSize,
@@ -71,7 +70,7 @@ func (p *pacer) maxBurstSize() protocol.ByteCount {
 // timeScaledBandwidth calculates the number of bytes that may be sent within
 // a given time interval (ns nanoseconds), based on the current bandwidth estimate.
 // It caps the scaled value to the maximum allowed burst and handles overflows.
-func (p *pacer) timeScaledBandwidth(ns uint64) protocol.ByteCount {
+func (p *Pacer) timeScaledBandwidth(ns uint64) protocol.ByteCount {
 	bw := p.adjustedBandwidth()
 	if bw == 0 {
 		return 0
@@ -89,7 +88,7 @@ func (p *pacer) timeScaledBandwidth(ns uint64) protocol.ByteCount {
 
 // TimeUntilSend returns when the next packet should be sent.
 // It returns zero if a packet can be sent immediately.
-func (p *pacer) TimeUntilSend() monotime.Time {
+func (p *Pacer) TimeUntilSend() monotime.Time {
 	if p.budgetAtLastSent >= p.maxDatagramSize {
 		return 0
 	}
@@ -105,6 +104,7 @@ func (p *pacer) TimeUntilSend() monotime.Time {
 	return p.lastSentTime.Add(max(protocol.MinPacingDelay, time.Duration(d)*time.Nanosecond))
 }
 
-func (p *pacer) SetMaxDatagramSize(s protocol.ByteCount) {
+// SetMaxDatagramSize sets the maximum datagram size.
+func (p *Pacer) SetMaxDatagramSize(s protocol.ByteCount) {
 	p.maxDatagramSize = s
 }
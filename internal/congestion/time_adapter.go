@@ -0,0 +1,253 @@
+package congestion
+
+import (
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A TimeSendAlgorithm is a SendAlgorithm that uses time.Time instead of monotime.Time.
+// It has the same methods as the SendAlgorithm of the public congestion package.
+type TimeSendAlgorithm interface {
+	TimeUntilSend(bytesInFlight protocol.ByteCount) time.Time
+	HasPacingBudget(now time.Time) bool
+	OnPacketSent(sentTime time.Time, bytesInFlight protocol.ByteCount, packetNumber protocol.PacketNumber, bytes protocol.ByteCount, isRetransmittable bool)
+	CanSend(bytesInFlight protocol.ByteCount) bool
+	MaybeExitSlowStart()
+	OnPacketAcked(number protocol.PacketNumber, ackedBytes protocol.ByteCount, priorInFlight protocol.ByteCount, eventTime time.Time)
+	OnCongestionEvent(number protocol.PacketNumber, lostBytes protocol.ByteCount, priorInFlight protocol.ByteCount)
+	OnRetransmissionTimeout(packetsRetransmitted bool)
+	SetMaxDatagramSize(protocol.ByteCount)
+	GetCongestionWindow() protocol.ByteCount
+	PacingRate() Bandwidth
+}
+
+// A TimeRateSampleConsumer is a TimeSendAlgorithm that uses delivery rate samples.
+type TimeRateSampleConsumer interface {
+	OnRateSample(eventTime time.Time, bytesInFlight protocol.ByteCount, rs RateSample)
+}
+
+// ToTimeSendAlgorithm converts a SendAlgorithm to a TimeSendAlgorithm.
+// The returned TimeSendAlgorithm implements the optional TimeRateSampleConsumer,
+// BandwidthEstimator and SlowStartThresholdReporter interfaces if and only if s
+// implements the corresponding interface.
+func ToTimeSendAlgorithm(s SendAlgorithm) TimeSendAlgorithm {
+	a := &timeSendAlgorithm{s: s}
+	rsc, isRSC := s.(RateSampleConsumer)
+	bwe, isBWE := s.(BandwidthEstimator)
+	sst, isSST := s.(SlowStartThresholdReporter)
+	r := timeRateSampleConsumer{c: rsc}
+	switch {
+	case isRSC && isBWE && isSST:
+		return struct {
+			*timeSendAlgorithm
+			timeRateSampleConsumer
+			BandwidthEstimator
+			SlowStartThresholdReporter
+		}{a, r, bwe, sst}
+	case isRSC && isBWE:
+		return struct {
+			*timeSendAlgorithm
+			timeRateSampleConsumer
+			BandwidthEstimator
+		}{a, r, bwe}
+	case isRSC && isSST:
+		return struct {
+			*timeSendAlgorithm
+			timeRateSampleConsumer
+			SlowStartThresholdReporter
+		}{a, r, sst}
+	case isBWE && isSST:
+		return struct {
+			*timeSendAlgorithm
+			BandwidthEstimator
+			SlowStartThresholdReporter
+		}{a, bwe, sst}
+	case isRSC:
+		return struct {
+			*timeSendAlgorithm
+			timeRateSampleConsumer
+		}{a, r}
+	case isBWE:
+		return struct {
+			*timeSendAlgorithm
+			BandwidthEstimator
+		}{a, bwe}
+	case isSST:
+		return struct {
+			*timeSendAlgorithm
+			SlowStartThresholdReporter
+		}{a, sst}
+	default:
+		return a
+	}
+}
+
+// FromTimeSendAlgorithm converts a TimeSendAlgorithm to a SendAlgorithm.
+// If s was returned by ToTimeSendAlgorithm, the original SendAlgorithm is returned.
+// Otherwise, the returned SendAlgorithm implements the optional RateSampleConsumer,
+// BandwidthEstimator and SlowStartThresholdReporter interfaces if and only if s
+// implements the corresponding interface.
+func FromTimeSendAlgorithm(s TimeSendAlgorithm) SendAlgorithm {
+	if ts, ok := s.(interface{ sendAlgorithm() SendAlgorithm }); ok {
+		return ts.sendAlgorithm()
+	}
+	a := &monotimeSendAlgorithm{s: s}
+	rsc, isRSC := s.(TimeRateSampleConsumer)
+	bwe, isBWE := s.(BandwidthEstimator)
+	sst, isSST := s.(SlowStartThresholdReporter)
+	r := monotimeRateSampleConsumer{c: rsc}
+	switch {
+	case isRSC && isBWE && isSST:
+		return struct {
+			*monotimeSendAlgorithm
+			monotimeRateSampleConsumer
+			BandwidthEstimator
+			SlowStartThresholdReporter
+		}{a, r, bwe, sst}
+	case isRSC && isBWE:
+		return struct {
+			*monotimeSendAlgorithm
+			monotimeRateSampleConsumer
+			BandwidthEstimator
+		}{a, r, bwe}
+	case isRSC && isSST:
+		return struct {
+			*monotimeSendAlgorithm
+			monotimeRateSampleConsumer
+			SlowStartThresholdReporter
+		}{a, r, sst}
+	case isBWE && isSST:
+		return struct {
+			*monotimeSendAlgorithm
+			BandwidthEstimator
+			SlowStartThresholdReporter
+		}{a, bwe, sst}
+	case isRSC:
+		return struct {
+			*monotimeSendAlgorithm
+			monotimeRateSampleConsumer
+		}{a, r}
+	case isBWE:
+		return struct {
+			*monotimeSendAlgorithm
+			BandwidthEstimator
+		}{a, bwe}
+	case isSST:
+		return struct {
+			*monotimeSendAlgorithm
+			SlowStartThresholdReporter
+		}{a, sst}
+	default:
+		return a
+	}
+}
+
+type timeRateSampleConsumer struct{ c RateSampleConsumer }
+
+var _ TimeRateSampleConsumer = timeRateSampleConsumer{}
+
+func (c timeRateSampleConsumer) OnRateSample(eventTime time.Time, bytesInFlight protocol.ByteCount, rs RateSample) {
+	c.c.OnRateSample(monotime.FromTime(eventTime), bytesInFlight, rs)
+}
+
+type monotimeRateSampleConsumer struct{ c TimeRateSampleConsumer }
+
+var _ RateSampleConsumer = monotimeRateSampleConsumer{}
+
+func (c monotimeRateSampleConsumer) OnRateSample(eventTime monotime.Time, bytesInFlight protocol.ByteCount, rs RateSample) {
+	c.c.OnRateSample(eventTime.ToTime(), bytesInFlight, rs)
+}
+
+type timeSendAlgorithm struct {
+	s SendAlgorithm
+}
+
+var _ TimeSendAlgorithm = &timeSendAlgorithm{}
+
+// sendAlgorithm returns the wrapped SendAlgorithm.
+// It is used by FromTimeSendAlgorithm to undo the conversion.
+func (a *timeSendAlgorithm) sendAlgorithm() SendAlgorithm { return a.s }
+
+func (a *timeSendAlgorithm) TimeUntilSend(bytesInFlight protocol.ByteCount) time.Time {
+	return a.s.TimeUntilSend(bytesInFlight).ToTime()
+}
+
+func (a *timeSendAlgorithm) HasPacingBudget(now time.Time) bool {
+	return a.s.HasPacingBudget(monotime.FromTime(now))
+}
+
+func (a *timeSendAlgorithm) OnPacketSent(sentTime time.Time, bytesInFlight protocol.ByteCount, packetNumber protocol.PacketNumber, bytes protocol.ByteCount, isRetransmittable bool) {
+	a.s.OnPacketSent(monotime.FromTime(sentTime), bytesInFlight, packetNumber, bytes, isRetransmittable)
+}
+
+func (a *timeSendAlgorithm) CanSend(bytesInFlight protocol.ByteCount) bool {
+	return a.s.CanSend(bytesInFlight)
+}
+
+func (a *timeSendAlgorithm) MaybeExitSlowStart() { a.s.MaybeExitSlowStart() }
+
+func (a *timeSendAlgorithm) OnPacketAcked(number protocol.PacketNumber, ackedBytes, priorInFlight protocol.ByteCount, eventTime time.Time) {
+	a.s.OnPacketAcked(number, ackedBytes, priorInFlight, monotime.FromTime(eventTime))
+}
+
+func (a *timeSendAlgorithm) OnCongestionEvent(number protocol.PacketNumber, lostBytes, priorInFlight protocol.ByteCount) {
+	a.s.OnCongestionEvent(number, lostBytes, priorInFlight)
+}
+
+func (a *timeSendAlgorithm) OnRetransmissionTimeout(packetsRetransmitted bool) {
+	a.s.OnRetransmissionTimeout(packetsRetransmitted)
+}
+
+func (a *timeSendAlgorithm) SetMaxDatagramSize(s protocol.ByteCount) { a.s.SetMaxDatagramSize(s) }
+
+func (a *timeSendAlgorithm) GetCongestionWindow() protocol.ByteCount {
+	return a.s.GetCongestionWindow()
+}
+
+func (a *timeSendAlgorithm) PacingRate() Bandwidth { return a.s.PacingRate() }
+
+type monotimeSendAlgorithm struct {
+	s TimeSendAlgorithm
+}
+
+var _ SendAlgorithm = &monotimeSendAlgorithm{}
+
+func (a *monotimeSendAlgorithm) TimeUntilSend(bytesInFlight protocol.ByteCount) monotime.Time {
+	return monotime.FromTime(a.s.TimeUntilSend(bytesInFlight))
+}
+
+func (a *monotimeSendAlgorithm) HasPacingBudget(now monotime.Time) bool {
+	return a.s.HasPacingBudget(now.ToTime())
+}
+
+func (a *monotimeSendAlgorithm) OnPacketSent(sentTime monotime.Time, bytesInFlight protocol.ByteCount, packetNumber protocol.PacketNumber, bytes protocol.ByteCount, isRetransmittable bool) {
+	a.s.OnPacketSent(sentTime.ToTime(), bytesInFlight, packetNumber, bytes, isRetransmittable)
+}
+
+func (a *monotimeSendAlgorithm) CanSend(bytesInFlight protocol.ByteCount) bool {
+	return a.s.CanSend(bytesInFlight)
+}
+
+func (a *monotimeSendAlgorithm) MaybeExitSlowStart() { a.s.MaybeExitSlowStart() }
+
+func (a *monotimeSendAlgorithm) OnPacketAcked(number protocol.PacketNumber, ackedBytes, priorInFlight protocol.ByteCount, eventTime monotime.Time) {
+	a.s.OnPacketAcked(number, ackedBytes, priorInFlight, eventTime.ToTime())
+}
+
+func (a *monotimeSendAlgorithm) OnCongestionEvent(number protocol.PacketNumber, lostBytes, priorInFlight protocol.ByteCount) {
+	a.s.OnCongestionEvent(number, lostBytes, priorInFlight)
+}
+
+func (a *monotimeSendAlgorithm) OnRetransmissionTimeout(packetsRetransmitted bool) {
+	a.s.OnRetransmissionTimeout(packetsRetransmitted)
+}
+
+func (a *monotimeSendAlgorithm) SetMaxDatagramSize(s protocol.ByteCount) { a.s.SetMaxDatagramSize(s) }
+
+func (a *monotimeSendAlgorithm) GetCongestionWindow() protocol.ByteCount {
+	return a.s.GetCongestionWindow()
+}
+
+func (a *monotimeSendAlgorithm) PacingRate() Bandwidth { return a.s.PacingRate() }
@@ -0,0 +1,117 @@
+package congestion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTimeSendAlgorithm records the timestamps passed to a TimeSendAlgorithm.
+type recordingTimeSendAlgorithm struct {
+	TimeSendAlgorithm
+
+	sentTimes  []time.Time
+	ackedTimes []time.Time
+}
+
+func (a *recordingTimeSendAlgorithm) OnPacketSent(sentTime time.Time, bytesInFlight protocol.ByteCount, pn protocol.PacketNumber, bytes protocol.ByteCount, isRetransmittable bool) {
+	a.sentTimes = append(a.sentTimes, sentTime)
+	a.TimeSendAlgorithm.OnPacketSent(sentTime, bytesInFlight, pn, bytes, isRetransmittable)
+}
+
+func (a *recordingTimeSendAlgorithm) OnPacketAcked(pn protocol.PacketNumber, ackedBytes, priorInFlight protocol.ByteCount, eventTime time.Time) {
+	a.ackedTimes = append(a.ackedTimes, eventTime)
+	a.TimeSendAlgorithm.OnPacketAcked(pn, ackedBytes, priorInFlight, eventTime)
+}
+
+func TestTimeSendAlgorithmUnwrapping(t *testing.T) {
+	sender := NewCubicSender(DefaultClock{}, utils.NewRTTStats(), protocol.InitialPacketSize, 0, true, nil)
+	require.Same(t, sender, FromTimeSendAlgorithm(ToTimeSendAlgorithm(sender)))
+}
+
+func TestTimeSendAlgorithmConversion(t *testing.T) {
+	rttStats := utils.NewRTTStats()
+	rttStats.UpdateRTT(100*time.Millisecond, 0)
+	cc := &recordingTimeSendAlgorithm{
+		TimeSendAlgorithm: ToTimeSendAlgorithm(NewCubicSender(DefaultClock{}, rttStats, 1200, 0, true, nil)),
+	}
+	s := FromTimeSendAlgorithm(cc)
+	require.IsType(t, &monotimeSendAlgorithm{}, s)
+
+	now := monotime.Now()
+	require.True(t, s.HasPacingBudget(now))
+	s.OnPacketSent(now, 1200, 0, 1200, true)
+	s.OnPacketAcked(0, 1200, 1200, now.Add(100*time.Millisecond))
+	require.Len(t, cc.sentTimes, 1)
+	require.Equal(t, now, monotime.FromTime(cc.sentTimes[0]))
+	require.Len(t, cc.ackedTimes, 1)
+	require.Equal(t, now.Add(100*time.Millisecond), monotime.FromTime(cc.ackedTimes[0]))
+
+	// the zero value is preserved
+	require.Zero(t, s.TimeUntilSend(0))
+
+	// the recording wrapper doesn't implement any of the optional interfaces
+	_, ok := s.(RateSampleConsumer)
+	require.False(t, ok)
+	_, ok = s.(BandwidthEstimator)
+	require.False(t, ok)
+	_, ok = s.(SlowStartThresholdReporter)
+	require.False(t, ok)
+}
+
+func TestTimeSendAlgorithmOptionalInterfaces(t *testing.T) {
+	t.Run("NewReno", func(t *testing.T) {
+		cubic := NewCubicSender(DefaultClock{}, utils.NewRTTStats(), 1200, 0, true, nil)
+		s := ToTimeSendAlgorithm(cubic)
+		_, ok := s.(TimeRateSampleConsumer)
+		require.False(t, ok)
+		require.Equal(t, cubic.BandwidthEstimate(), s.(BandwidthEstimator).BandwidthEstimate())
+		require.Equal(t, cubic.SlowStartThreshold(), s.(SlowStartThresholdReporter).SlowStartThreshold())
+	})
+
+	t.Run("BBR", func(t *testing.T) {
+		bbr := NewBBRSender(DefaultClock{}, utils.NewRTTStats(), 1200, 0, nil)
+		s := ToTimeSendAlgorithm(bbr)
+		_, ok := s.(TimeRateSampleConsumer)
+		require.True(t, ok)
+		_, ok = s.(BandwidthEstimator)
+		require.True(t, ok)
+		_, ok = s.(SlowStartThresholdReporter)
+		require.False(t, ok)
+	})
+}
+
+// rateSampleRecordingTimeSendAlgorithm is a TimeSendAlgorithm that consumes rate samples.
+type rateSampleRecordingTimeSendAlgorithm struct {
+	TimeSendAlgorithm
+
+	eventTimes []time.Time
+	samples    []RateSample
+}
+
+func (a *rateSampleRecordingTimeSendAlgorithm) OnRateSample(eventTime time.Time, _ protocol.ByteCount, rs RateSample) {
+	a.eventTimes = append(a.eventTimes, eventTime)
+	a.samples = append(a.samples, rs)
+}
+
+func TestTimeSendAlgorithmRateSamples(t *testing.T) {
+	cc := &rateSampleRecordingTimeSendAlgorithm{
+		TimeSendAlgorithm: ToTimeSendAlgorithm(NewCubicSender(DefaultClock{}, utils.NewRTTStats(), 1200, 0, true, nil)),
+	}
+	s := FromTimeSendAlgorithm(cc)
+	_, ok := s.(BandwidthEstimator)
+	require.False(t, ok)
+	_, ok = s.(SlowStartThresholdReporter)
+	require.False(t, ok)
+
+	now := monotime.Now()
+	s.(RateSampleConsumer).OnRateSample(now, 1000, RateSample{Delivered: 1234})
+	require.Len(t, cc.eventTimes, 1)
+	require.Equal(t, now, monotime.FromTime(cc.eventTimes[0]))
+	require.Equal(t, []RateSample{{Delivered: 1234}}, cc.samples)
+}
@@ -1,6 +1,8 @@
 package flowcontrol
 
 import (
+	"time"
+
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
 )
@@ -8,7 +10,6 @@ import (
 type flowController interface {
 	// for sending
 	SendWindowSize() protocol.ByteCount
-	UpdateSendWindow(protocol.ByteCount) (updated bool)
 	AddBytesSent(protocol.ByteCount)
 	// for receiving
 	GetWindowUpdate(monotime.Time) protocol.ByteCount // returns 0 if no update is necessary
@@ -25,13 +26,18 @@ type StreamFlowController interface {
 	// Abandon is called when reading from the stream is aborted early,
 	// and there won't be any further calls to AddBytesRead.
 	Abandon()
-	IsNewlyBlocked() bool
+	UpdateSendWindow(offset protocol.ByteCount, now monotime.Time) (updated bool)
+	IsNewlyBlocked(now monotime.Time) bool
+	// BlockedTime returns the total time the stream was blocked by stream-level flow control,
+	// including the time it has been blocked for so far, if it is currently blocked.
+	BlockedTime(now monotime.Time) time.Duration
 }
 
 // The ConnectionFlowController is the flow controller for the connection.
 type ConnectionFlowController interface {
 	flowController
 	AddBytesRead(protocol.ByteCount) (hasWindowUpdate bool)
+	UpdateSendWindow(protocol.ByteCount) (updated bool)
 	Reset() error
 	IsNewlyBlocked() (bool, protocol.ByteCount)
 }
@@ -2,6 +2,7 @@ package flowcontrol
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
@@ -17,6 +18,11 @@ type streamFlowController struct {
 	connection connectionFlowControllerI
 
 	receivedFinalOffset bool
+
+	// Time spent blocked by stream-level flow control.
+	// Protected by the mutex, since these values are accessed by BlockedTime.
+	blockedSince monotime.Time
+	blockedTime  time.Duration
 }
 
 var _ StreamFlowController = &streamFlowController{}
@@ -126,11 +132,39 @@ func (c *streamFlowController) SendWindowSize() protocol.ByteCount {
 	return min(c.baseFlowController.SendWindowSize(), c.connection.SendWindowSize())
 }
 
-func (c *streamFlowController) IsNewlyBlocked() bool {
+func (c *streamFlowController) IsNewlyBlocked(now monotime.Time) bool {
 	blocked, _ := c.baseFlowController.IsNewlyBlocked()
+	if blocked {
+		c.mutex.Lock()
+		c.blockedSince = now
+		c.mutex.Unlock()
+	}
 	return blocked
 }
 
+func (c *streamFlowController) UpdateSendWindow(offset protocol.ByteCount, now monotime.Time) (updated bool) {
+	updated = c.baseFlowController.UpdateSendWindow(offset)
+	if updated {
+		c.mutex.Lock()
+		if !c.blockedSince.IsZero() {
+			c.blockedTime += now.Sub(c.blockedSince)
+			c.blockedSince = 0
+		}
+		c.mutex.Unlock()
+	}
+	return updated
+}
+
+func (c *streamFlowController) BlockedTime(now monotime.Time) time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.blockedSince.IsZero() {
+		return c.blockedTime
+	}
+	return c.blockedTime + now.Sub(c.blockedSince)
+}
+
 func (c *streamFlowController) shouldQueueWindowUpdate() bool {
 	return !c.receivedFinalOffset && c.hasWindowUpdate()
 }
@@ -153,27 +153,35 @@ func TestStreamSendWindow(t *testing.T) {
 		utils.NewRTTStats(),
 		utils.DefaultLogger,
 	)
+	now := monotime.Now()
 	// first, we're limited by the stream flow controller
 	require.Equal(t, protocol.ByteCount(100), fc.SendWindowSize())
 	fc.AddBytesSent(50)
-	require.False(t, fc.IsNewlyBlocked())
+	require.False(t, fc.IsNewlyBlocked(now))
 	require.Equal(t, protocol.ByteCount(50), fc.SendWindowSize())
 	fc.AddBytesSent(50)
-	require.True(t, fc.IsNewlyBlocked())
+	require.Zero(t, fc.BlockedTime(now))
+	require.True(t, fc.IsNewlyBlocked(now))
 	require.Zero(t, fc.SendWindowSize())
-	require.False(t, fc.IsNewlyBlocked()) // we're still blocked, but it's not new
+	require.False(t, fc.IsNewlyBlocked(now.Add(time.Second))) // we're still blocked, but it's not new
+	// the blocked time includes the time the stream is currently blocked for
+	require.Equal(t, time.Hour, fc.BlockedTime(now.Add(time.Hour)))
 
 	// Update the stream flow control limit, but don't update the connection flow control limit.
 	// We're now limited by the connection flow controller.
-	require.True(t, fc.UpdateSendWindow(1000))
+	require.True(t, fc.UpdateSendWindow(1000, now.Add(2*time.Second)))
+	require.Equal(t, 2*time.Second, fc.BlockedTime(now.Add(2*time.Second)))
+	// the stream is not blocked anymore, so the blocked time doesn't increase
+	require.Equal(t, 2*time.Second, fc.BlockedTime(now.Add(time.Hour)))
 	// reordered updates are ignored
-	require.False(t, fc.UpdateSendWindow(999))
+	require.False(t, fc.UpdateSendWindow(999, now.Add(3*time.Second)))
 
-	require.False(t, fc.IsNewlyBlocked()) // we're not blocked anymore
+	require.False(t, fc.IsNewlyBlocked(now.Add(3*time.Second))) // we're not blocked anymore
 	require.Equal(t, protocol.ByteCount(200), fc.SendWindowSize())
 	fc.AddBytesSent(200)
 	require.Zero(t, fc.SendWindowSize())
-	require.False(t, fc.IsNewlyBlocked()) // we're blocked, but not on stream flow control
+	require.False(t, fc.IsNewlyBlocked(now.Add(3*time.Second))) // we're blocked, but not on stream flow control
+	require.Equal(t, 2*time.Second, fc.BlockedTime(now.Add(time.Hour)))
 }
 
 func TestStreamWindowUpdate(t *testing.T) {
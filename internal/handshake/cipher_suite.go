@@ -79,25 +79,33 @@ func (f *xorNonceAEAD) NonceSize() int        { return 8 } // 64-bit sequence nu
 func (f *xorNonceAEAD) Overhead() int         { return f.aead.Overhead() }
 func (f *xorNonceAEAD) explicitNonceLen() int { return 0 }
 
+// Seal seals the plaintext.
+// The nonce is usually the 64-bit packet number. For multipath QUIC, it can also be
+// the full-length nonce, with the path ID in the first 4 bytes.
+// Shorter nonces are right-aligned before being XORed with the nonce mask.
 func (f *xorNonceAEAD) Seal(out, nonce, plaintext, additionalData []byte) []byte {
+	offset := aeadNonceLength - len(nonce)
 	for i, b := range nonce {
-		f.nonceMask[4+i] ^= b
+		f.nonceMask[offset+i] ^= b
 	}
 	result := f.aead.Seal(out, f.nonceMask[:], plaintext, additionalData)
 	for i, b := range nonce {
-		f.nonceMask[4+i] ^= b
+		f.nonceMask[offset+i] ^= b
 	}
 
 	return result
 }
 
+// Open opens the ciphertext.
+// The same rules as for Seal apply to the nonce.
 func (f *xorNonceAEAD) Open(out, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	offset := aeadNonceLength - len(nonce)
 	for i, b := range nonce {
-		f.nonceMask[4+i] ^= b
+		f.nonceMask[offset+i] ^= b
 	}
 	result, err := f.aead.Open(out, f.nonceMask[:], ciphertext, additionalData)
 	for i, b := range nonce {
-		f.nonceMask[4+i] ^= b
+		f.nonceMask[offset+i] ^= b
 	}
 
 	return result, err
@@ -2,9 +2,11 @@ package handshake
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"hash"
 	"net"
 	"strings"
 	"sync/atomic"
@@ -38,6 +40,8 @@ type cryptoSetup struct {
 
 	zeroRTTParameters *wire.TransportParameters
 	allow0RTT         bool
+	antiReplay        AntiReplayStore // only set for the server
+	clientHelloHash   hash.Hash       // only set for the server, if an AntiReplayStore is used
 
 	rttStats *utils.RTTStats
 
@@ -108,6 +112,7 @@ func NewCryptoSetupServer(
 	tp *wire.TransportParameters,
 	tlsConf *tls.Config,
 	allow0RTT bool,
+	antiReplay AntiReplayStore,
 	rttStats *utils.RTTStats,
 	qlogger qlogwriter.Recorder,
 	logger utils.Logger,
@@ -123,6 +128,10 @@ func NewCryptoSetupServer(
 		version,
 	)
 	cs.allow0RTT = allow0RTT
+	if allow0RTT && antiReplay != nil {
+		cs.antiReplay = antiReplay
+		cs.clientHelloHash = sha256.New()
+	}
 
 	tlsConf = setupConfigForServer(tlsConf, localAddr, remoteAddr)
 
@@ -188,6 +197,22 @@ func (h *cryptoSetup) SetLargest1RTTAcked(pn protocol.PacketNumber) error {
 	return h.aead.SetLargestAcked(pn)
 }
 
+func (h *cryptoSetup) SetKeyUpdateIntervals(packets uint64, d time.Duration) {
+	h.aead.SetKeyUpdateIntervals(packets, d)
+}
+
+func (h *cryptoSetup) InitiateKeyUpdate() {
+	h.aead.InitiateKeyUpdate()
+}
+
+func (h *cryptoSetup) KeyUpdatePending() bool {
+	return h.aead.KeyUpdatePending()
+}
+
+func (h *cryptoSetup) NumKeyUpdates() uint64 {
+	return h.aead.NumKeyUpdates()
+}
+
 func (h *cryptoSetup) StartHandshake(ctx context.Context) error {
 	err := h.conn.Start(context.WithValue(ctx, QUICVersionContextKey, h.version))
 	if err != nil {
@@ -229,6 +254,9 @@ func (h *cryptoSetup) HandleMessage(data []byte, encLevel protocol.EncryptionLev
 }
 
 func (h *cryptoSetup) handleMessage(data []byte, encLevel protocol.EncryptionLevel) error {
+	if h.clientHelloHash != nil && encLevel == protocol.EncryptionInitial {
+		h.clientHelloHash.Write(data)
+	}
 	if err := h.conn.HandleData(encLevel.ToTLSEncryptionLevel(), data); err != nil {
 		return err
 	}
@@ -288,6 +316,7 @@ func (h *cryptoSetup) handleEvent(ev tls.QUICEvent) (err error) {
 		case protocol.PerspectiveServer:
 			// for servers, this event occurs when receiving the client's session ticket
 			allowEarlyData = h.handleSessionTicket(
+				ev.SessionState,
 				findSessionStateExtraData(ev.SessionState.Extra),
 				ev.SessionState.EarlyData,
 			)
@@ -371,6 +400,7 @@ func decodeDataFromSessionState(b []byte, earlyData bool) (*wire.TransportParame
 
 func (h *cryptoSetup) getDataForSessionTicket() []byte {
 	return (&sessionTicket{
+		Issued:     time.Now(),
 		Parameters: h.ourParams,
 	}).Marshal()
 }
@@ -408,7 +438,7 @@ func (h *cryptoSetup) GetSessionTicket() ([]byte, error) {
 // It reads parameters from the session ticket and checks whether to accept 0-RTT if the session ticket enabled 0-RTT.
 // Note that the fact that the session ticket allows 0-RTT doesn't mean that the actual TLS handshake enables 0-RTT:
 // A client may use a 0-RTT enabled session to resume a TLS session without using 0-RTT.
-func (h *cryptoSetup) handleSessionTicket(data []byte, using0RTT bool) (allowEarlyData bool) {
+func (h *cryptoSetup) handleSessionTicket(state *tls.SessionState, data []byte, using0RTT bool) (allowEarlyData bool) {
 	var t sessionTicket
 	if err := t.Unmarshal(data); err != nil {
 		h.logger.Debugf("Unmarshalling session ticket failed: %s", err.Error())
@@ -426,6 +456,22 @@ func (h *cryptoSetup) handleSessionTicket(data []byte, using0RTT bool) (allowEar
 		h.logger.Debugf("0-RTT not allowed. Rejecting 0-RTT.")
 		return false
 	}
+	if h.antiReplay != nil {
+		stateBytes, err := state.Bytes()
+		if err != nil {
+			h.logger.Debugf("Serializing session state failed: %s. Rejecting 0-RTT.", err)
+			return false
+		}
+		attempt := ZeroRTTAttempt{
+			TicketID:     sha256.Sum256(stateBytes),
+			TicketIssued: t.Issued,
+		}
+		h.clientHelloHash.Sum(attempt.ClientHelloHash[:0])
+		if !h.antiReplay.Accept0RTT(attempt) {
+			h.logger.Debugf("0-RTT attempt rejected by the anti-replay store. Rejecting 0-RTT.")
+			return false
+		}
+	}
 	return true
 }
 
@@ -666,6 +712,30 @@ func (h *cryptoSetup) Get1RTTOpener() (ShortHeaderOpener, error) {
 	return h.aead, nil
 }
 
+// Get1RTTSealerForPath returns a sealer for a path of a multipath connection.
+// Every call returns a new sealer, so it should be called once per path.
+func (h *cryptoSetup) Get1RTTSealerForPath(id protocol.PathID) (ShortHeaderSealer, error) {
+	if !h.has1RTTSealer {
+		return nil, ErrKeysNotYetAvailable
+	}
+	if id == 0 {
+		return h.aead, nil
+	}
+	return &pathSealer{aead: h.aead, pathID: id}, nil
+}
+
+// Get1RTTOpenerForPath returns an opener for a path of a multipath connection.
+// Every call returns a new opener, so it should be called once per path.
+func (h *cryptoSetup) Get1RTTOpenerForPath(id protocol.PathID) (ShortHeaderOpener, error) {
+	if !h.has1RTTOpener {
+		return nil, ErrKeysNotYetAvailable
+	}
+	if id == 0 {
+		return h.aead, nil
+	}
+	return newPathOpener(h.aead, id), nil
+}
+
 func (h *cryptoSetup) ConnectionState() ConnectionState {
 	return ConnectionState{
 		ConnectionState: h.conn.ConnectionState(),
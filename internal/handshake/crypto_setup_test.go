@@ -92,6 +92,7 @@ func TestMessageReceivedAtWrongEncryptionLevel(t *testing.T) {
 		&wire.TransportParameters{StatelessResetToken: &token},
 		testdata.GetTLSConfig(),
 		false,
+		nil,
 		utils.NewRTTStats(),
 		nil,
 		utils.DefaultLogger.WithPrefix("server"),
@@ -207,6 +208,7 @@ func handshakeWithTLSConf(
 		serverTransportParameters,
 		serverConf,
 		enable0RTT,
+		nil,
 		serverRTTStats,
 		nil,
 		utils.DefaultLogger.WithPrefix("server"),
@@ -302,6 +304,7 @@ func TestTransportParameters(t *testing.T) {
 		sTransportParameters,
 		serverConf,
 		false,
+		nil,
 		utils.NewRTTStats(),
 		nil,
 		utils.DefaultLogger.WithPrefix("server"),
@@ -566,3 +569,70 @@ func Test0RTTRejectionOnTransportParametersChanged(t *testing.T) {
 	require.False(t, server.ConnectionState().Used0RTT)
 	require.False(t, client.ConnectionState().Used0RTT)
 }
+
+type antiReplayStoreFunc func(ZeroRTTAttempt) bool
+
+func (f antiReplayStoreFunc) Accept0RTT(a ZeroRTTAttempt) bool { return f(a) }
+
+func Test0RTTRejectionByAntiReplayStore(t *testing.T) {
+	clientConf, serverConf := getTLSConfigs()
+	csc := newMockClientSessionCache()
+	clientConf.ClientSessionCache = csc
+	_, _, clientErr, _, _, serverErr := handshakeWithTLSConf(
+		t,
+		clientConf, serverConf,
+		utils.NewRTTStats(), utils.NewRTTStats(),
+		&wire.TransportParameters{ActiveConnectionIDLimit: 2},
+		&wire.TransportParameters{ActiveConnectionIDLimit: 2},
+		true,
+	)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	select {
+	case <-csc.puts:
+	case <-time.After(time.Second):
+		t.Fatal("didn't receive a session ticket")
+	}
+
+	client := NewCryptoSetupClient(
+		protocol.ConnectionID{},
+		&wire.TransportParameters{ActiveConnectionIDLimit: 2},
+		clientConf,
+		true,
+		utils.NewRTTStats(),
+		nil,
+		utils.DefaultLogger.WithPrefix("client"),
+		protocol.Version1,
+	)
+	var token protocol.StatelessResetToken
+	var attempts []ZeroRTTAttempt
+	server := NewCryptoSetupServer(
+		protocol.ConnectionID{},
+		&net.UDPAddr{IP: net.IPv6loopback, Port: 1234},
+		&net.UDPAddr{IP: net.IPv6loopback, Port: 4321},
+		&wire.TransportParameters{ActiveConnectionIDLimit: 2, StatelessResetToken: &token},
+		serverConf,
+		true,
+		antiReplayStoreFunc(func(a ZeroRTTAttempt) bool {
+			attempts = append(attempts, a)
+			return false
+		}),
+		utils.NewRTTStats(),
+		nil,
+		utils.DefaultLogger.WithPrefix("server"),
+		protocol.Version1,
+	)
+	_, clientErr, _, serverErr = handshake(t, client, server)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+
+	require.Len(t, attempts, 1)
+	require.NotZero(t, attempts[0].TicketID)
+	require.NotZero(t, attempts[0].ClientHelloHash)
+	require.WithinDuration(t, time.Now(), attempts[0].TicketIssued, time.Second)
+	// the handshake falls back to 1-RTT
+	require.True(t, server.ConnectionState().DidResume)
+	require.True(t, client.ConnectionState().DidResume)
+	require.False(t, server.ConnectionState().Used0RTT)
+	require.False(t, client.ConnectionState().Used0RTT)
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"io"
+	"time"
 
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
@@ -54,6 +55,26 @@ type ShortHeaderSealer interface {
 	KeyPhase() protocol.KeyPhaseBit
 }
 
+// A ZeroRTTAttempt describes a client's attempt to send 0-RTT data.
+type ZeroRTTAttempt struct {
+	// TicketID identifies the session ticket used for the attempt.
+	// It is the same for all connection attempts using the same session ticket.
+	TicketID [32]byte
+	// TicketIssued is the time when the session ticket was issued.
+	TicketIssued time.Time
+	// ClientHelloHash is the SHA-256 hash of the ClientHello.
+	// A replayed ClientHello has the same hash.
+	ClientHelloHash [32]byte
+}
+
+// An AntiReplayStore is consulted before 0-RTT is accepted.
+type AntiReplayStore interface {
+	// Accept0RTT is called when a client attempts to use 0-RTT.
+	// If it returns false, 0-RTT is rejected, and the handshake falls back to 1-RTT.
+	// It is called during the handshake and must be safe for concurrent use.
+	Accept0RTT(ZeroRTTAttempt) bool
+}
+
 type ConnectionState struct {
 	tls.ConnectionState
 	Used0RTT bool
@@ -124,6 +145,11 @@ type CryptoSetup interface {
 	NextEvent() Event
 
 	SetLargest1RTTAcked(protocol.PacketNumber) error
+	SetKeyUpdateIntervals(packets uint64, d time.Duration)
+	KeyUpdatePending() bool
+	// InitiateKeyUpdate and NumKeyUpdates can be called from a different go routine.
+	InitiateKeyUpdate()
+	NumKeyUpdates() uint64
 	DiscardInitialKeys()
 	SetHandshakeConfirmed()
 	ConnectionState() ConnectionState
@@ -137,4 +163,8 @@ type CryptoSetup interface {
 	GetHandshakeSealer() (LongHeaderSealer, error)
 	Get0RTTSealer() (LongHeaderSealer, error)
 	Get1RTTSealer() (ShortHeaderSealer, error)
+
+	// for multipath connections
+	Get1RTTOpenerForPath(protocol.PathID) (ShortHeaderOpener, error)
+	Get1RTTSealerForPath(protocol.PathID) (ShortHeaderSealer, error)
 }
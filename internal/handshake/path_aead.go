@@ -0,0 +1,140 @@
+package handshake
+
+import (
+	"encoding/binary"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+)
+
+// The pathSealer seals 1-RTT packets sent on a path of a multipath connection.
+// All paths share the keys (and therefore the key phase) of the updatableAEAD,
+// but every path has its own packet number space.
+// The path ID is included in the nonce, see section 2.4 of draft-ietf-quic-multipath-14.
+type pathSealer struct {
+	aead   *updatableAEAD
+	pathID protocol.PathID
+}
+
+var _ ShortHeaderSealer = &pathSealer{}
+
+func (s *pathSealer) Seal(dst, src []byte, pn protocol.PacketNumber, ad []byte) []byte {
+	a := s.aead
+	a.sentWithCurrentKeyOnOtherPath = true
+	a.numSentWithCurrentKey++
+	a.setPathNonce(s.pathID, pn)
+	return a.sendAEAD.Seal(dst, a.pathNonceBuf[:], src, ad)
+}
+
+func (s *pathSealer) EncryptHeader(sample []byte, firstByte *byte, hdrBytes []byte) {
+	s.aead.EncryptHeader(sample, firstByte, hdrBytes)
+}
+
+func (s *pathSealer) Overhead() int                  { return s.aead.Overhead() }
+func (s *pathSealer) KeyPhase() protocol.KeyPhaseBit { return s.aead.KeyPhase() }
+
+// The pathOpener opens 1-RTT packets received on a path of a multipath connection.
+type pathOpener struct {
+	aead   *updatableAEAD
+	pathID protocol.PathID
+
+	highestRcvdPN protocol.PacketNumber
+	// the first packet number received on this path with the key phase firstRcvdKeyPhase
+	firstRcvdWithCurrentKey protocol.PacketNumber
+	firstRcvdKeyPhase       protocol.KeyPhase
+}
+
+var _ ShortHeaderOpener = &pathOpener{}
+
+func newPathOpener(aead *updatableAEAD, id protocol.PathID) *pathOpener {
+	return &pathOpener{
+		aead:                    aead,
+		pathID:                  id,
+		firstRcvdWithCurrentKey: protocol.InvalidPacketNumber,
+	}
+}
+
+func (o *pathOpener) DecodePacketNumber(wirePN protocol.PacketNumber, wirePNLen protocol.PacketNumberLen) protocol.PacketNumber {
+	return protocol.DecodePacketNumber(wirePNLen, o.highestRcvdPN, wirePN)
+}
+
+func (o *pathOpener) DecryptHeader(sample []byte, firstByte *byte, hdrBytes []byte) {
+	o.aead.DecryptHeader(sample, firstByte, hdrBytes)
+}
+
+func (o *pathOpener) Open(dst, src []byte, rcvTime monotime.Time, pn protocol.PacketNumber, kp protocol.KeyPhaseBit, ad []byte) ([]byte, error) {
+	a := o.aead
+	dec, err := o.open(dst, src, rcvTime, pn, kp, ad)
+	if err == ErrDecryptionFailed {
+		a.invalidPacketCount++
+		if a.invalidPacketCount >= a.invalidPacketLimit {
+			return nil, &qerr.TransportError{ErrorCode: qerr.AEADLimitReached}
+		}
+	}
+	if err == nil {
+		o.highestRcvdPN = max(o.highestRcvdPN, pn)
+	}
+	return dec, err
+}
+
+func (o *pathOpener) open(dst, src []byte, rcvTime monotime.Time, pn protocol.PacketNumber, kp protocol.KeyPhaseBit, ad []byte) ([]byte, error) {
+	a := o.aead
+	a.maybeDropPrevRcvAEAD(rcvTime)
+	a.setPathNonce(o.pathID, pn)
+	firstRcvd := o.firstRcvdWithCurrentKey
+	if o.firstRcvdKeyPhase != a.keyPhase {
+		firstRcvd = protocol.InvalidPacketNumber
+	}
+	if kp != a.keyPhase.Bit() {
+		// Decryption happens in place, so we need to decide which key to use before opening the packet.
+		// The packet was protected with the previous key if
+		// 1. we initiated a key update, but didn't receive any packet with the new key yet, or
+		// 2. it was sent before the first packet with the new key received on this path, or
+		// 3. it's not clear whether the peer is already using the next key phase, but we still have the previous key.
+		usePrev := (a.keyPhase > 0 && a.firstRcvdWithCurrentKey == protocol.InvalidPacketNumber && !a.rcvdWithCurrentKeyOnOtherPath) ||
+			(firstRcvd != protocol.InvalidPacketNumber && pn < firstRcvd) ||
+			(firstRcvd == protocol.InvalidPacketNumber && a.prevRcvAEAD != nil)
+		if usePrev {
+			if a.prevRcvAEAD == nil {
+				return nil, ErrKeysDropped
+			}
+			dec, err := a.prevRcvAEAD.Open(dst, a.pathNonceBuf[:], src, ad)
+			if err != nil {
+				err = ErrDecryptionFailed
+			}
+			return dec, err
+		}
+		dec, err := a.openWithNextKeyPhase(dst, a.pathNonceBuf[:], src, rcvTime, ad)
+		if err != nil {
+			return nil, err
+		}
+		a.rcvdWithCurrentKeyOnOtherPath = true
+		o.firstRcvdWithCurrentKey = pn
+		o.firstRcvdKeyPhase = a.keyPhase
+		return dec, nil
+	}
+	dec, err := a.rcvAEAD.Open(dst, a.pathNonceBuf[:], src, ad)
+	if err != nil {
+		return dec, ErrDecryptionFailed
+	}
+	a.numRcvdWithCurrentKey++
+	if a.keyPhase > 0 && a.firstRcvdWithCurrentKey == protocol.InvalidPacketNumber && !a.rcvdWithCurrentKeyOnOtherPath {
+		// We initiated the key update, and this is the first packet protected with the new key phase.
+		a.logger.Debugf("Peer confirmed key update to phase %d", a.keyPhase)
+		a.startKeyDropTimer(rcvTime)
+	}
+	a.rcvdWithCurrentKeyOnOtherPath = true
+	if firstRcvd == protocol.InvalidPacketNumber {
+		o.firstRcvdWithCurrentKey = pn
+		o.firstRcvdKeyPhase = a.keyPhase
+	}
+	return dec, nil
+}
+
+// setPathNonce writes the nonce for a packet sent on a path of a multipath connection:
+// The 32 bits of the path ID, followed by the 64 bits of the packet number.
+func (a *updatableAEAD) setPathNonce(id protocol.PathID, pn protocol.PacketNumber) {
+	binary.BigEndian.PutUint32(a.pathNonceBuf[:4], uint32(id))
+	binary.BigEndian.PutUint64(a.pathNonceBuf[4:], uint64(pn))
+}
@@ -0,0 +1,105 @@
+package handshake
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/utils"
+	"github.com/quic-go/quic-go/qlog"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathAEADNonce(t *testing.T) {
+	client, server, _ := setupEndpoints(t, utils.NewRTTStats())
+
+	sealer := &pathSealer{aead: server, pathID: 0xdeadbeef}
+	encrypted := sealer.Seal(nil, []byte(msg), 0x1337, []byte(ad))
+
+	// the nonce consists of the path ID and the packet number
+	var nonce [aeadNonceLength]byte
+	binary.BigEndian.PutUint32(nonce[:4], 0xdeadbeef)
+	binary.BigEndian.PutUint64(nonce[4:], 0x1337)
+	decrypted, err := client.rcvAEAD.Open(nil, nonce[:], encrypted, []byte(ad))
+	require.NoError(t, err)
+	require.Equal(t, msg, string(decrypted))
+
+	// packets can't be opened on a different path
+	_, err = newPathOpener(client, 1).Open(nil, encrypted, monotime.Now(), 0x1337, protocol.KeyPhaseZero, []byte(ad))
+	require.Equal(t, ErrDecryptionFailed, err)
+	_, err = client.Open(nil, encrypted, monotime.Now(), 0x1337, protocol.KeyPhaseZero, []byte(ad))
+	require.Equal(t, ErrDecryptionFailed, err)
+
+	// the path ID 0 results in the same nonce as the single-path case
+	sealer0 := &pathSealer{aead: server, pathID: 0}
+	encrypted0 := sealer0.Seal(nil, []byte(msg), 42, []byte(ad))
+	decrypted, err = client.Open(nil, encrypted0, monotime.Now(), 42, protocol.KeyPhaseZero, []byte(ad))
+	require.NoError(t, err)
+	require.Equal(t, msg, string(decrypted))
+}
+
+func TestPathAEADPacketNumbers(t *testing.T) {
+	client, server, _ := setupEndpoints(t, utils.NewRTTStats())
+
+	opener := newPathOpener(client, 2)
+	sealer := &pathSealer{aead: server, pathID: 2}
+	encrypted := sealer.Seal(nil, []byte(msg), 0x1337, []byte(ad))
+	_, err := opener.Open(nil, encrypted, monotime.Now(), 0x1337, protocol.KeyPhaseZero, []byte(ad))
+	require.NoError(t, err)
+	// every path keeps track of its own highest packet number
+	require.Equal(t, protocol.PacketNumber(0x1338), opener.DecodePacketNumber(0x38, protocol.PacketNumberLen1))
+	require.Equal(t, protocol.PacketNumber(0x38), client.DecodePacketNumber(0x38, protocol.PacketNumberLen1))
+	require.Equal(t, protocol.PacketNumber(0x38), newPathOpener(client, 3).DecodePacketNumber(0x38, protocol.PacketNumberLen1))
+}
+
+func TestPathAEADKeyUpdateByPeer(t *testing.T) {
+	client, server, eventRecorder := setupEndpoints(t, utils.NewRTTStats())
+
+	clientSealer := &pathSealer{aead: client, pathID: 1}
+	serverOpener := newPathOpener(server, 1)
+	now := monotime.Now()
+
+	encrypted01 := clientSealer.Seal(nil, []byte(msg), 1, []byte(ad))
+	encrypted02 := clientSealer.Seal(nil, []byte(msg), 2, []byte(ad))
+	_, err := serverOpener.Open(nil, encrypted01, now, 1, protocol.KeyPhaseZero, []byte(ad))
+	require.NoError(t, err)
+
+	// the server only sends on path 1
+	_ = (&pathSealer{aead: server, pathID: 1}).Seal(nil, []byte(msg), 1, []byte(ad))
+
+	client.rollKeys()
+	encrypted1 := clientSealer.Seal(nil, []byte(msg), 3, []byte(ad))
+	_, err = serverOpener.Open(nil, encrypted1, now, 3, protocol.KeyPhaseOne, []byte(ad))
+	require.NoError(t, err)
+	require.Equal(t, protocol.KeyPhaseOne, server.KeyPhase())
+	require.Equal(t,
+		bothSides(qlog.KeyUpdated{Trigger: qlog.KeyUpdateRemote, KeyPhase: 1}),
+		eventRecorder.Events(),
+	)
+
+	// reordered packets with the old key phase can still be opened, both on path 1 and on path 0
+	decrypted, err := serverOpener.Open(nil, encrypted02, now, 2, protocol.KeyPhaseZero, []byte(ad))
+	require.NoError(t, err)
+	require.Equal(t, msg, string(decrypted))
+}
+
+func TestPathAEADKeyUpdateTooQuickly(t *testing.T) {
+	client, server, _ := setupEndpoints(t, utils.NewRTTStats())
+	server.SetHandshakeConfirmed()
+
+	clientSealer := &pathSealer{aead: client, pathID: 1}
+	serverOpener := newPathOpener(server, 1)
+	now := monotime.Now()
+
+	// the first key update is always allowed
+	client.rollKeys()
+	_, err := serverOpener.Open(nil, clientSealer.Seal(nil, []byte(msg), 1, []byte(ad)), now, 1, protocol.KeyPhaseOne, []byte(ad))
+	require.NoError(t, err)
+
+	// the server didn't send any packet with the new key yet
+	client.rollKeys()
+	_, err = serverOpener.Open(nil, clientSealer.Seal(nil, []byte(msg), 2, []byte(ad)), now, 2, protocol.KeyPhaseZero, []byte(ad))
+	require.ErrorContains(t, err, "keys updated too quickly")
+}
@@ -4,20 +4,23 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/quic-go/quic-go/internal/wire"
 	"github.com/quic-go/quic-go/quicvarint"
 )
 
-const sessionTicketRevision = 5
+const sessionTicketRevision = 6
 
 type sessionTicket struct {
+	Issued     time.Time
 	Parameters *wire.TransportParameters
 }
 
 func (t *sessionTicket) Marshal() []byte {
 	b := make([]byte, 0, 256)
 	b = quicvarint.Append(b, sessionTicketRevision)
+	b = quicvarint.Append(b, uint64(t.Issued.UnixMilli()))
 	return t.Parameters.MarshalForSessionTicket(b)
 }
 
@@ -30,6 +33,12 @@ func (t *sessionTicket) Unmarshal(b []byte) error {
 	if rev != sessionTicketRevision {
 		return fmt.Errorf("unknown session ticket revision: %d", rev)
 	}
+	issued, l, err := quicvarint.Parse(b)
+	if err != nil {
+		return errors.New("failed to read session ticket issue time")
+	}
+	b = b[l:]
+	t.Issued = time.UnixMilli(int64(issued))
 	var tp wire.TransportParameters
 	if err := tp.UnmarshalFromSessionTicket(b); err != nil {
 		return fmt.Errorf("unmarshaling transport parameters from session ticket failed: %s", err.Error())
@@ -2,6 +2,7 @@ package handshake
 
 import (
 	"testing"
+	"time"
 
 	"github.com/quic-go/quic-go/internal/wire"
 	"github.com/quic-go/quic-go/quicvarint"
@@ -10,7 +11,9 @@ import (
 )
 
 func TestMarshalUnmarshalSessionTicket(t *testing.T) {
+	issued := time.Now().Truncate(time.Millisecond)
 	ticket := &sessionTicket{
+		Issued: issued,
 		Parameters: &wire.TransportParameters{
 			InitialMaxStreamDataBidiLocal:  1,
 			InitialMaxStreamDataBidiRemote: 2,
@@ -20,6 +23,7 @@ func TestMarshalUnmarshalSessionTicket(t *testing.T) {
 	}
 	var t2 sessionTicket
 	require.NoError(t, t2.Unmarshal(ticket.Marshal()))
+	require.True(t, t2.Issued.Equal(issued))
 	require.EqualValues(t, 1, t2.Parameters.InitialMaxStreamDataBidiLocal)
 	require.EqualValues(t, 2, t2.Parameters.InitialMaxStreamDataBidiRemote)
 	require.EqualValues(t, 10, t2.Parameters.ActiveConnectionIDLimit)
@@ -37,8 +41,15 @@ func TestUnmarshalRefusesUnknownRevision(t *testing.T) {
 	require.EqualError(t, err, "unknown session ticket revision: 1337")
 }
 
+func TestUnmarshalRefusesMissingIssueTime(t *testing.T) {
+	b := quicvarint.Append(nil, sessionTicketRevision)
+	err := (&sessionTicket{}).Unmarshal(b)
+	require.EqualError(t, err, "failed to read session ticket issue time")
+}
+
 func TestUnmarshal0RTTRefusesInvalidTransportParameters(t *testing.T) {
 	b := quicvarint.Append(nil, sessionTicketRevision)
+	b = quicvarint.Append(b, uint64(time.Now().UnixMilli()))
 	b = append(b, []byte("foobar")...)
 	err := (&sessionTicket{}).Unmarshal(b)
 	require.Error(t, err)
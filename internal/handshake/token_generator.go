@@ -52,6 +52,11 @@ func NewTokenGenerator(key TokenProtectorKey) *TokenGenerator {
 	return &TokenGenerator{tokenProtector: *newTokenProtector(key)}
 }
 
+// NewTokenGeneratorWithKeyRing initializes a new TokenGenerator that uses the keys provided by the key ring.
+func NewTokenGeneratorWithKeyRing(keys TokenProtectorKeyRing) *TokenGenerator {
+	return &TokenGenerator{tokenProtector: *newTokenProtectorWithKeyRing(keys)}
+}
+
 // NewRetryToken generates a new token for a Retry for a given source address
 func (g *TokenGenerator) NewRetryToken(
 	raddr net.Addr,
@@ -14,16 +14,34 @@ import (
 // TokenProtectorKey is the key used to encrypt both Retry and session resumption tokens.
 type TokenProtectorKey [32]byte
 
+// A TokenProtectorKeyRing provides the keys used to encrypt tokens.
+// New tokens are encrypted using the current key.
+// When decoding a token, the current key is tried first, followed by the previous keys.
+// Keys may be changed at any time, so Keys must be safe for concurrent use.
+type TokenProtectorKeyRing interface {
+	Keys() (current TokenProtectorKey, previous []TokenProtectorKey)
+}
+
+type staticTokenProtectorKey TokenProtectorKey
+
+func (k staticTokenProtectorKey) Keys() (TokenProtectorKey, []TokenProtectorKey) {
+	return TokenProtectorKey(k), nil
+}
+
 const tokenNonceSize = 32
 
 // tokenProtector is used to create and verify a token
 type tokenProtector struct {
-	key TokenProtectorKey
+	keys TokenProtectorKeyRing
 }
 
 // newTokenProtector creates a source for source address tokens
 func newTokenProtector(key TokenProtectorKey) *tokenProtector {
-	return &tokenProtector{key: key}
+	return newTokenProtectorWithKeyRing(staticTokenProtectorKey(key))
+}
+
+func newTokenProtectorWithKeyRing(keys TokenProtectorKeyRing) *tokenProtector {
+	return &tokenProtector{keys: keys}
 }
 
 // NewToken encodes data into a new token.
@@ -32,7 +50,8 @@ func (s *tokenProtector) NewToken(data []byte) ([]byte, error) {
 	if _, err := rand.Read(nonce[:]); err != nil {
 		return nil, err
 	}
-	aead, aeadNonce, err := s.createAEAD(nonce[:])
+	key, _ := s.keys.Keys()
+	aead, aeadNonce, err := s.createAEAD(key, nonce[:])
 	if err != nil {
 		return nil, err
 	}
@@ -45,15 +64,30 @@ func (s *tokenProtector) DecodeToken(p []byte) ([]byte, error) {
 		return nil, fmt.Errorf("token too short: %d", len(p))
 	}
 	nonce := p[:tokenNonceSize]
-	aead, aeadNonce, err := s.createAEAD(nonce)
+	current, previous := s.keys.Keys()
+	data, err := s.decodeToken(current, nonce, p[tokenNonceSize:])
+	if err == nil {
+		return data, nil
+	}
+	for _, key := range previous {
+		if data, err := s.decodeToken(key, nonce, p[tokenNonceSize:]); err == nil {
+			return data, nil
+		}
+	}
+	// return the error we got when using the current key
+	return nil, err
+}
+
+func (s *tokenProtector) decodeToken(key TokenProtectorKey, nonce, ciphertext []byte) ([]byte, error) {
+	aead, aeadNonce, err := s.createAEAD(key, nonce)
 	if err != nil {
 		return nil, err
 	}
-	return aead.Open(nil, aeadNonce, p[tokenNonceSize:], nil)
+	return aead.Open(nil, aeadNonce, ciphertext, nil)
 }
 
-func (s *tokenProtector) createAEAD(nonce []byte) (cipher.AEAD, []byte, error) {
-	h := hkdf.New(sha256.New, s.key[:], nonce, []byte("quic-go token source"))
+func (s *tokenProtector) createAEAD(tokenKey TokenProtectorKey, nonce []byte) (cipher.AEAD, []byte, error) {
+	h := hkdf.New(sha256.New, tokenKey[:], nonce, []byte("quic-go token source"))
 	key := make([]byte, 32) // use a 32 byte key, in order to select AES-256
 	if _, err := io.ReadFull(h, key); err != nil {
 		return nil, nil, err
@@ -45,6 +45,52 @@ func TestTokenProtectorDifferentKeys(t *testing.T) {
 	require.Error(t, err)
 }
 
+type tokenProtectorKeyRing struct {
+	current  TokenProtectorKey
+	previous []TokenProtectorKey
+}
+
+func (r *tokenProtectorKeyRing) Keys() (TokenProtectorKey, []TokenProtectorKey) {
+	return r.current, r.previous
+}
+
+func TestTokenProtectorKeyRotation(t *testing.T) {
+	var key1, key2, key3 TokenProtectorKey
+	rand.Read(key1[:])
+	rand.Read(key2[:])
+	rand.Read(key3[:])
+	keys := &tokenProtectorKeyRing{current: key1}
+	tp := newTokenProtectorWithKeyRing(keys)
+
+	t1, err := tp.NewToken([]byte("foo"))
+	require.NoError(t, err)
+
+	// tokens encrypted with a previous key are still accepted
+	keys.current = key2
+	keys.previous = []TokenProtectorKey{key1}
+	t2, err := tp.NewToken([]byte("bar"))
+	require.NoError(t, err)
+	decoded, err := tp.DecodeToken(t1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("foo"), decoded)
+	decoded, err = tp.DecodeToken(t2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), decoded)
+	// new tokens are encrypted with the current key
+	_, err = newTokenProtector(key1).DecodeToken(t2)
+	require.Error(t, err)
+	_, err = newTokenProtector(key2).DecodeToken(t2)
+	require.NoError(t, err)
+
+	// once the key is removed from the key ring, tokens are rejected
+	keys.current = key3
+	keys.previous = []TokenProtectorKey{key2}
+	_, err = tp.DecodeToken(t1)
+	require.Error(t, err)
+	_, err = tp.DecodeToken(t2)
+	require.NoError(t, err)
+}
+
 func TestTokenProtectorInvalidTokens(t *testing.T) {
 	var key TokenProtectorKey
 	rand.Read(key[:])
@@ -7,6 +7,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"sync/atomic"
+	"time"
 
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
@@ -53,9 +54,21 @@ type updatableAEAD struct {
 	numSentWithCurrentKey   uint64
 	rcvAEAD                 cipher.AEAD
 	sendAEAD                cipher.AEAD
+	// For multipath connections: was a packet sent / received with the current key on a path other than path 0?
+	sentWithCurrentKeyOnOtherPath bool
+	rcvdWithCurrentKeyOnOtherPath bool
 	// caches cipher.AEAD.Overhead(). This speeds up calls to Overhead().
 	aeadOverhead int
 
+	// the maximum number of packets sent or received with a key, and the maximum time a key is used
+	keyUpdateInterval     uint64
+	keyUpdateTimeInterval time.Duration
+	// the time when the current key phase started
+	keyPhaseStart monotime.Time
+	// set by InitiateKeyUpdate, can be set from a different go routine
+	keyUpdateRequested atomic.Bool
+	numKeyUpdates      atomic.Uint64
+
 	nextRcvAEAD           cipher.AEAD
 	nextSendAEAD          cipher.AEAD
 	nextRcvTrafficSecret  []byte
@@ -72,6 +85,8 @@ type updatableAEAD struct {
 
 	// use a single slice to avoid allocations
 	nonceBuf []byte
+	// the nonce for multipath packets includes the path ID
+	pathNonceBuf [aeadNonceLength]byte
 }
 
 var (
@@ -109,10 +124,14 @@ func (a *updatableAEAD) rollKeys() {
 	}
 
 	a.keyPhase++
+	a.keyPhaseStart = monotime.Now()
+	a.keyUpdateRequested.Store(false)
 	a.firstRcvdWithCurrentKey = protocol.InvalidPacketNumber
 	a.firstSentWithCurrentKey = protocol.InvalidPacketNumber
 	a.numRcvdWithCurrentKey = 0
 	a.numSentWithCurrentKey = 0
+	a.sentWithCurrentKeyOnOtherPath = false
+	a.rcvdWithCurrentKeyOnOtherPath = false
 	a.prevRcvAEAD = a.rcvAEAD
 	a.rcvAEAD = a.nextRcvAEAD
 	a.sendAEAD = a.nextSendAEAD
@@ -123,7 +142,9 @@ func (a *updatableAEAD) rollKeys() {
 	a.nextSendAEAD = createAEAD(a.suite, a.nextSendTrafficSecret, a.version)
 }
 
+// startKeyDropTimer is called once both endpoints updated their keys, completing the key update.
 func (a *updatableAEAD) startKeyDropTimer(now monotime.Time) {
+	a.numKeyUpdates.Add(1)
 	d := 3 * a.rttStats.PTO(true)
 	a.logger.Debugf("Starting key drop timer to drop key phase %d (in %s)", a.keyPhase-1, d)
 	a.prevRcvAEADExpiry = now.Add(d)
@@ -152,6 +173,7 @@ func (a *updatableAEAD) SetReadKey(suite *cipherSuite, trafficSecret []byte) {
 // For the server, this function is called before SetReadKey.
 func (a *updatableAEAD) SetWriteKey(suite *cipherSuite, trafficSecret []byte) {
 	a.sendAEAD = createAEAD(suite, trafficSecret, a.version)
+	a.keyPhaseStart = monotime.Now()
 	a.headerEncrypter = newHeaderProtector(suite, trafficSecret, false, a.version)
 	if a.suite == nil {
 		a.setAEADParameters(a.sendAEAD, suite)
@@ -194,21 +216,7 @@ func (a *updatableAEAD) Open(dst, src []byte, rcvTime monotime.Time, pn protocol
 }
 
 func (a *updatableAEAD) open(dst, src []byte, rcvTime monotime.Time, pn protocol.PacketNumber, kp protocol.KeyPhaseBit, ad []byte) ([]byte, error) {
-	if a.prevRcvAEAD != nil && !a.prevRcvAEADExpiry.IsZero() && rcvTime.After(a.prevRcvAEADExpiry) {
-		a.prevRcvAEAD = nil
-		a.logger.Debugf("Dropping key phase %d", a.keyPhase-1)
-		a.prevRcvAEADExpiry = 0
-		if a.qlogger != nil {
-			a.qlogger.RecordEvent(qlog.KeyDiscarded{
-				KeyType:  qlog.KeyTypeClient1RTT,
-				KeyPhase: a.keyPhase - 1,
-			})
-			a.qlogger.RecordEvent(qlog.KeyDiscarded{
-				KeyType:  qlog.KeyTypeServer1RTT,
-				KeyPhase: a.keyPhase - 1,
-			})
-		}
-	}
+	a.maybeDropPrevRcvAEAD(rcvTime)
 	binary.BigEndian.PutUint64(a.nonceBuf[len(a.nonceBuf)-8:], uint64(pn))
 	if kp != a.keyPhase.Bit() {
 		if a.keyPhase > 0 && a.firstRcvdWithCurrentKey == protocol.InvalidPacketNumber || pn < a.firstRcvdWithCurrentKey {
@@ -223,33 +231,9 @@ func (a *updatableAEAD) open(dst, src []byte, rcvTime monotime.Time, pn protocol
 			return dec, err
 		}
 		// try opening the packet with the next key phase
-		dec, err := a.nextRcvAEAD.Open(dst, a.nonceBuf, src, ad)
+		dec, err := a.openWithNextKeyPhase(dst, a.nonceBuf, src, rcvTime, ad)
 		if err != nil {
-			return nil, ErrDecryptionFailed
-		}
-		// Opening succeeded. Check if the peer was allowed to update.
-		if a.keyPhase > 0 && a.firstSentWithCurrentKey == protocol.InvalidPacketNumber {
-			return nil, &qerr.TransportError{
-				ErrorCode:    qerr.KeyUpdateError,
-				ErrorMessage: "keys updated too quickly",
-			}
-		}
-		a.rollKeys()
-		a.logger.Debugf("Peer updated keys to %d", a.keyPhase)
-		// The peer initiated this key update. It's safe to drop the keys for the previous generation now.
-		// Start a timer to drop the previous key generation.
-		a.startKeyDropTimer(rcvTime)
-		if a.qlogger != nil {
-			a.qlogger.RecordEvent(qlog.KeyUpdated{
-				Trigger:  qlog.KeyUpdateRemote,
-				KeyType:  qlog.KeyTypeClient1RTT,
-				KeyPhase: a.keyPhase,
-			})
-			a.qlogger.RecordEvent(qlog.KeyUpdated{
-				Trigger:  qlog.KeyUpdateRemote,
-				KeyType:  qlog.KeyTypeServer1RTT,
-				KeyPhase: a.keyPhase,
-			})
+			return nil, err
 		}
 		a.firstRcvdWithCurrentKey = pn
 		return dec, err
@@ -264,7 +248,7 @@ func (a *updatableAEAD) open(dst, src []byte, rcvTime monotime.Time, pn protocol
 	if a.firstRcvdWithCurrentKey == protocol.InvalidPacketNumber {
 		// We initiated the key updated, and now we received the first packet protected with the new key phase.
 		// Therefore, we are certain that the peer rolled its keys as well. Start a timer to drop the old keys.
-		if a.keyPhase > 0 {
+		if a.keyPhase > 0 && !a.rcvdWithCurrentKeyOnOtherPath {
 			a.logger.Debugf("Peer confirmed key update to phase %d", a.keyPhase)
 			a.startKeyDropTimer(rcvTime)
 		}
@@ -273,6 +257,58 @@ func (a *updatableAEAD) open(dst, src []byte, rcvTime monotime.Time, pn protocol
 	return dec, err
 }
 
+// openWithNextKeyPhase opens a packet that was protected with the next key phase.
+// If successful, it rolls the keys.
+func (a *updatableAEAD) openWithNextKeyPhase(dst, nonce, src []byte, rcvTime monotime.Time, ad []byte) ([]byte, error) {
+	dec, err := a.nextRcvAEAD.Open(dst, nonce, src, ad)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	// Opening succeeded. Check if the peer was allowed to update.
+	if a.keyPhase > 0 && a.firstSentWithCurrentKey == protocol.InvalidPacketNumber && !a.sentWithCurrentKeyOnOtherPath {
+		return nil, &qerr.TransportError{
+			ErrorCode:    qerr.KeyUpdateError,
+			ErrorMessage: "keys updated too quickly",
+		}
+	}
+	a.rollKeys()
+	a.logger.Debugf("Peer updated keys to %d", a.keyPhase)
+	// The peer initiated this key update. It's safe to drop the keys for the previous generation now.
+	// Start a timer to drop the previous key generation.
+	a.startKeyDropTimer(rcvTime)
+	if a.qlogger != nil {
+		a.qlogger.RecordEvent(qlog.KeyUpdated{
+			Trigger:  qlog.KeyUpdateRemote,
+			KeyType:  qlog.KeyTypeClient1RTT,
+			KeyPhase: a.keyPhase,
+		})
+		a.qlogger.RecordEvent(qlog.KeyUpdated{
+			Trigger:  qlog.KeyUpdateRemote,
+			KeyType:  qlog.KeyTypeServer1RTT,
+			KeyPhase: a.keyPhase,
+		})
+	}
+	return dec, nil
+}
+
+func (a *updatableAEAD) maybeDropPrevRcvAEAD(rcvTime monotime.Time) {
+	if a.prevRcvAEAD != nil && !a.prevRcvAEADExpiry.IsZero() && rcvTime.After(a.prevRcvAEADExpiry) {
+		a.prevRcvAEAD = nil
+		a.logger.Debugf("Dropping key phase %d", a.keyPhase-1)
+		a.prevRcvAEADExpiry = 0
+		if a.qlogger != nil {
+			a.qlogger.RecordEvent(qlog.KeyDiscarded{
+				KeyType:  qlog.KeyTypeClient1RTT,
+				KeyPhase: a.keyPhase - 1,
+			})
+			a.qlogger.RecordEvent(qlog.KeyDiscarded{
+				KeyType:  qlog.KeyTypeServer1RTT,
+				KeyPhase: a.keyPhase - 1,
+			})
+		}
+	}
+}
+
 func (a *updatableAEAD) Seal(dst, src []byte, pn protocol.PacketNumber, ad []byte) []byte {
 	if a.firstSentWithCurrentKey == protocol.InvalidPacketNumber {
 		a.firstSentWithCurrentKey = pn
@@ -299,6 +335,33 @@ func (a *updatableAEAD) SetLargestAcked(pn protocol.PacketNumber) error {
 	return nil
 }
 
+// SetKeyUpdateIntervals sets the maximum number of packets sent or received with a key,
+// and the maximum duration a key is used, before a key update is initiated.
+// If the number of packets is 0, or larger than the default, the default is used.
+// If the duration is 0, keys are not updated based on their age.
+func (a *updatableAEAD) SetKeyUpdateIntervals(packets uint64, d time.Duration) {
+	a.keyUpdateInterval = packets
+	a.keyUpdateTimeInterval = d
+}
+
+// InitiateKeyUpdate requests a key update.
+// The keys are updated when the next packet is sent, as soon as a key update is allowed.
+// It is safe to call this function from a different go routine.
+func (a *updatableAEAD) InitiateKeyUpdate() {
+	a.keyUpdateRequested.Store(true)
+}
+
+// KeyUpdatePending says if a key update was requested, and is now allowed to be performed.
+func (a *updatableAEAD) KeyUpdatePending() bool {
+	return a.keyUpdateRequested.Load() && a.updateAllowed()
+}
+
+// NumKeyUpdates returns the number of completed key updates.
+// It is safe to call this function from a different go routine.
+func (a *updatableAEAD) NumKeyUpdates() uint64 {
+	return a.numKeyUpdates.Load()
+}
+
 func (a *updatableAEAD) SetHandshakeConfirmed() {
 	a.handshakeConfirmed = true
 }
@@ -325,14 +388,26 @@ func (a *updatableAEAD) shouldInitiateKeyUpdate() bool {
 			return true
 		}
 	}
-	if a.numRcvdWithCurrentKey >= keyUpdateInterval.Load() {
+	if a.keyUpdateRequested.Load() {
+		a.logger.Debugf("Key update requested by the application. Initiating key update to the next key phase: %d", a.keyPhase+1)
+		return true
+	}
+	interval := keyUpdateInterval.Load()
+	if a.keyUpdateInterval > 0 {
+		interval = min(interval, a.keyUpdateInterval)
+	}
+	if a.numRcvdWithCurrentKey >= interval {
 		a.logger.Debugf("Received %d packets with current key phase. Initiating key update to the next key phase: %d", a.numRcvdWithCurrentKey, a.keyPhase+1)
 		return true
 	}
-	if a.numSentWithCurrentKey >= keyUpdateInterval.Load() {
+	if a.numSentWithCurrentKey >= interval {
 		a.logger.Debugf("Sent %d packets with current key phase. Initiating key update to the next key phase: %d", a.numSentWithCurrentKey, a.keyPhase+1)
 		return true
 	}
+	if a.keyUpdateTimeInterval > 0 && monotime.Since(a.keyPhaseStart) >= a.keyUpdateTimeInterval {
+		a.logger.Debugf("Used current key phase for %s. Initiating key update to the next key phase: %d", monotime.Since(a.keyPhaseStart), a.keyPhase+1)
+		return true
+	}
 	return false
 }
 
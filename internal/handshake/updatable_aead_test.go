@@ -11,6 +11,7 @@ import (
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/internal/synctest"
 	"github.com/quic-go/quic-go/internal/utils"
 	"github.com/quic-go/quic-go/qlog"
 	"github.com/quic-go/quic-go/qlogwriter"
@@ -433,6 +434,95 @@ func TestInitiateKeyUpdateAfterSendingMaxPackets(t *testing.T) {
 	)
 }
 
+func TestKeyUpdateIntervalPerConnection(t *testing.T) {
+	const firstKeyUpdateInterval = 5
+	const keyUpdateInterval = 20
+	setKeyUpdateIntervals(t, firstKeyUpdateInterval, protocol.KeyUpdateInterval)
+
+	client, server, eventRecorder := setupEndpoints(t, utils.NewRTTStats())
+	server.SetKeyUpdateIntervals(keyUpdateInterval, 0)
+	server.SetHandshakeConfirmed()
+
+	var pn protocol.PacketNumber
+	for range firstKeyUpdateInterval {
+		server.Seal(nil, []byte(msg), pn, []byte(ad))
+		pn++
+	}
+	require.Equal(t, protocol.KeyPhaseOne, server.KeyPhase())
+	require.Zero(t, server.NumKeyUpdates())
+	// receive a packet sent with the new key phase, completing the key update
+	client.rollKeys()
+	b := client.Seal(nil, []byte("foobar"), 1, []byte("ad"))
+	_, err := server.Open(nil, b, monotime.Now(), 1, protocol.KeyPhaseOne, []byte("ad"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), server.NumKeyUpdates())
+	require.NoError(t, server.SetLargestAcked(firstKeyUpdateInterval))
+	eventRecorder.Clear()
+
+	for range keyUpdateInterval {
+		require.Equal(t, protocol.KeyPhaseOne, server.KeyPhase())
+		server.Seal(nil, []byte(msg), pn, []byte(ad))
+		pn++
+	}
+	require.Equal(t, protocol.KeyPhaseZero, server.KeyPhase())
+	require.Equal(t,
+		append(
+			bothSides(qlog.KeyDiscarded{KeyPhase: 0}),
+			bothSides(qlog.KeyUpdated{KeyPhase: 2, Trigger: qlog.KeyUpdateLocal})...,
+		),
+		eventRecorder.Events(),
+	)
+}
+
+func TestKeyUpdateTimeInterval(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		_, server, eventRecorder := setupEndpoints(t, utils.NewRTTStats())
+		server.SetKeyUpdateIntervals(0, time.Hour)
+		server.SetHandshakeConfirmed()
+
+		server.Seal(nil, []byte(msg), 0, []byte(ad))
+		time.Sleep(time.Hour - time.Nanosecond)
+		require.Equal(t, protocol.KeyPhaseZero, server.KeyPhase())
+		time.Sleep(time.Nanosecond)
+		require.Equal(t, protocol.KeyPhaseOne, server.KeyPhase())
+		require.Equal(t,
+			bothSides(qlog.KeyUpdated{KeyPhase: 1, Trigger: qlog.KeyUpdateLocal}),
+			eventRecorder.Events(),
+		)
+	})
+}
+
+func TestKeyUpdateRequestedByApplication(t *testing.T) {
+	client, server, eventRecorder := setupEndpoints(t, utils.NewRTTStats())
+
+	// key updates are only possible after the handshake has been confirmed
+	server.InitiateKeyUpdate()
+	require.Equal(t, protocol.KeyPhaseZero, server.KeyPhase())
+	server.SetHandshakeConfirmed()
+	require.Equal(t, protocol.KeyPhaseOne, server.KeyPhase())
+	require.Equal(t,
+		bothSides(qlog.KeyUpdated{KeyPhase: 1, Trigger: qlog.KeyUpdateLocal}),
+		eventRecorder.Events(),
+	)
+	eventRecorder.Clear()
+
+	// the next key update can only be initiated after a packet sent with the current keys has been acknowledged
+	server.InitiateKeyUpdate()
+	server.Seal(nil, []byte(msg), 0x42, []byte(ad))
+	require.Equal(t, protocol.KeyPhaseOne, server.KeyPhase())
+	require.False(t, server.KeyUpdatePending())
+	client.rollKeys()
+	b := client.Seal(nil, []byte("foobar"), 1, []byte("ad"))
+	_, err := server.Open(nil, b, monotime.Now(), 1, protocol.KeyPhaseOne, []byte("ad"))
+	require.NoError(t, err)
+	require.NoError(t, server.SetLargestAcked(0x42))
+	require.True(t, server.KeyUpdatePending())
+	require.Equal(t, protocol.KeyPhaseZero, server.KeyPhase())
+	// the request was consumed by the key update
+	require.False(t, server.KeyUpdatePending())
+	require.Equal(t, uint64(1), server.NumKeyUpdates())
+}
+
 func TestKeyUpdateEnforceACKKeyPhase(t *testing.T) {
 	const firstKeyUpdateInterval = 5
 	setKeyUpdateIntervals(t, firstKeyUpdateInterval, protocol.KeyUpdateInterval)
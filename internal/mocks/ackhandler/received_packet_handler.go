@@ -154,6 +154,78 @@ func (c *MockReceivedPacketHandlerGetAlarmTimeoutCall) DoAndReturn(f func() mono
 	return c
 }
 
+// HandleAckFrequencyFrame mocks base method.
+func (m *MockReceivedPacketHandler) HandleAckFrequencyFrame(arg0 *wire.AckFrequencyFrame) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleAckFrequencyFrame", arg0)
+}
+
+// HandleAckFrequencyFrame indicates an expected call of HandleAckFrequencyFrame.
+func (mr *MockReceivedPacketHandlerMockRecorder) HandleAckFrequencyFrame(arg0 any) *MockReceivedPacketHandlerHandleAckFrequencyFrameCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleAckFrequencyFrame", reflect.TypeOf((*MockReceivedPacketHandler)(nil).HandleAckFrequencyFrame), arg0)
+	return &MockReceivedPacketHandlerHandleAckFrequencyFrameCall{Call: call}
+}
+
+// MockReceivedPacketHandlerHandleAckFrequencyFrameCall wrap *gomock.Call
+type MockReceivedPacketHandlerHandleAckFrequencyFrameCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockReceivedPacketHandlerHandleAckFrequencyFrameCall) Return() *MockReceivedPacketHandlerHandleAckFrequencyFrameCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockReceivedPacketHandlerHandleAckFrequencyFrameCall) Do(f func(*wire.AckFrequencyFrame)) *MockReceivedPacketHandlerHandleAckFrequencyFrameCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockReceivedPacketHandlerHandleAckFrequencyFrameCall) DoAndReturn(f func(*wire.AckFrequencyFrame)) *MockReceivedPacketHandlerHandleAckFrequencyFrameCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// HandleImmediateAckFrame mocks base method.
+func (m *MockReceivedPacketHandler) HandleImmediateAckFrame() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleImmediateAckFrame")
+}
+
+// HandleImmediateAckFrame indicates an expected call of HandleImmediateAckFrame.
+func (mr *MockReceivedPacketHandlerMockRecorder) HandleImmediateAckFrame() *MockReceivedPacketHandlerHandleImmediateAckFrameCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleImmediateAckFrame", reflect.TypeOf((*MockReceivedPacketHandler)(nil).HandleImmediateAckFrame))
+	return &MockReceivedPacketHandlerHandleImmediateAckFrameCall{Call: call}
+}
+
+// MockReceivedPacketHandlerHandleImmediateAckFrameCall wrap *gomock.Call
+type MockReceivedPacketHandlerHandleImmediateAckFrameCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockReceivedPacketHandlerHandleImmediateAckFrameCall) Return() *MockReceivedPacketHandlerHandleImmediateAckFrameCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockReceivedPacketHandlerHandleImmediateAckFrameCall) Do(f func()) *MockReceivedPacketHandlerHandleImmediateAckFrameCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockReceivedPacketHandlerHandleImmediateAckFrameCall) DoAndReturn(f func()) *MockReceivedPacketHandlerHandleImmediateAckFrameCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // IsPotentiallyDuplicate mocks base method.
 func (m *MockReceivedPacketHandler) IsPotentiallyDuplicate(arg0 protocol.PacketNumber, arg1 protocol.EncryptionLevel) bool {
 	m.ctrl.T.Helper()
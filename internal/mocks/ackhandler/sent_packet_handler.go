@@ -43,6 +43,44 @@ func (m *MockSentPacketHandler) EXPECT() *MockSentPacketHandlerMockRecorder {
 	return m.recorder
 }
 
+// CongestionWindow mocks base method.
+func (m *MockSentPacketHandler) CongestionWindow() protocol.ByteCount {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CongestionWindow")
+	ret0, _ := ret[0].(protocol.ByteCount)
+	return ret0
+}
+
+// CongestionWindow indicates an expected call of CongestionWindow.
+func (mr *MockSentPacketHandlerMockRecorder) CongestionWindow() *MockSentPacketHandlerCongestionWindowCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CongestionWindow", reflect.TypeOf((*MockSentPacketHandler)(nil).CongestionWindow))
+	return &MockSentPacketHandlerCongestionWindowCall{Call: call}
+}
+
+// MockSentPacketHandlerCongestionWindowCall wrap *gomock.Call
+type MockSentPacketHandlerCongestionWindowCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSentPacketHandlerCongestionWindowCall) Return(arg0 protocol.ByteCount) *MockSentPacketHandlerCongestionWindowCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSentPacketHandlerCongestionWindowCall) Do(f func() protocol.ByteCount) *MockSentPacketHandlerCongestionWindowCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSentPacketHandlerCongestionWindowCall) DoAndReturn(f func() protocol.ByteCount) *MockSentPacketHandlerCongestionWindowCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // DropPackets mocks base method.
 func (m *MockSentPacketHandler) DropPackets(arg0 protocol.EncryptionLevel, rcvTime monotime.Time) {
 	m.ctrl.T.Helper()
@@ -529,6 +567,42 @@ func (c *MockSentPacketHandlerSentPacketCall) DoAndReturn(f func(monotime.Time,
 	return c
 }
 
+// SetAppLimited mocks base method.
+func (m *MockSentPacketHandler) SetAppLimited() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAppLimited")
+}
+
+// SetAppLimited indicates an expected call of SetAppLimited.
+func (mr *MockSentPacketHandlerMockRecorder) SetAppLimited() *MockSentPacketHandlerSetAppLimitedCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppLimited", reflect.TypeOf((*MockSentPacketHandler)(nil).SetAppLimited))
+	return &MockSentPacketHandlerSetAppLimitedCall{Call: call}
+}
+
+// MockSentPacketHandlerSetAppLimitedCall wrap *gomock.Call
+type MockSentPacketHandlerSetAppLimitedCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSentPacketHandlerSetAppLimitedCall) Return() *MockSentPacketHandlerSetAppLimitedCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSentPacketHandlerSetAppLimitedCall) Do(f func()) *MockSentPacketHandlerSetAppLimitedCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSentPacketHandlerSetAppLimitedCall) DoAndReturn(f func()) *MockSentPacketHandlerSetAppLimitedCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetMaxDatagramSize mocks base method.
 func (m *MockSentPacketHandler) SetMaxDatagramSize(count protocol.ByteCount) {
 	m.ctrl.T.Helper()
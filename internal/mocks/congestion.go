@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/quic-go/quic-go/internal/congestion (interfaces: SendAlgorithmWithDebugInfos)
+// Source: github.com/quic-go/quic-go/internal/congestion (interfaces: SendAlgorithm)
 //
 // Generated by this command:
 //
-//	mockgen -typed -build_flags=-tags=gomock -package mocks -destination congestion.go github.com/quic-go/quic-go/internal/congestion SendAlgorithmWithDebugInfos
+//	mockgen -typed -build_flags=-tags=gomock -package mocks -destination congestion.go github.com/quic-go/quic-go/internal/congestion SendAlgorithm
 //
 
 // Package mocks is a generated GoMock package.
@@ -12,37 +12,38 @@ package mocks
 import (
 	reflect "reflect"
 
+	congestion "github.com/quic-go/quic-go/internal/congestion"
 	monotime "github.com/quic-go/quic-go/internal/monotime"
 	protocol "github.com/quic-go/quic-go/internal/protocol"
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockSendAlgorithmWithDebugInfos is a mock of SendAlgorithmWithDebugInfos interface.
-type MockSendAlgorithmWithDebugInfos struct {
+// MockSendAlgorithm is a mock of SendAlgorithm interface.
+type MockSendAlgorithm struct {
 	ctrl     *gomock.Controller
-	recorder *MockSendAlgorithmWithDebugInfosMockRecorder
+	recorder *MockSendAlgorithmMockRecorder
 	isgomock struct{}
 }
 
-// MockSendAlgorithmWithDebugInfosMockRecorder is the mock recorder for MockSendAlgorithmWithDebugInfos.
-type MockSendAlgorithmWithDebugInfosMockRecorder struct {
-	mock *MockSendAlgorithmWithDebugInfos
+// MockSendAlgorithmMockRecorder is the mock recorder for MockSendAlgorithm.
+type MockSendAlgorithmMockRecorder struct {
+	mock *MockSendAlgorithm
 }
 
-// NewMockSendAlgorithmWithDebugInfos creates a new mock instance.
-func NewMockSendAlgorithmWithDebugInfos(ctrl *gomock.Controller) *MockSendAlgorithmWithDebugInfos {
-	mock := &MockSendAlgorithmWithDebugInfos{ctrl: ctrl}
-	mock.recorder = &MockSendAlgorithmWithDebugInfosMockRecorder{mock}
+// NewMockSendAlgorithm creates a new mock instance.
+func NewMockSendAlgorithm(ctrl *gomock.Controller) *MockSendAlgorithm {
+	mock := &MockSendAlgorithm{ctrl: ctrl}
+	mock.recorder = &MockSendAlgorithmMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockSendAlgorithmWithDebugInfos) EXPECT() *MockSendAlgorithmWithDebugInfosMockRecorder {
+func (m *MockSendAlgorithm) EXPECT() *MockSendAlgorithmMockRecorder {
 	return m.recorder
 }
 
 // CanSend mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) CanSend(bytesInFlight protocol.ByteCount) bool {
+func (m *MockSendAlgorithm) CanSend(bytesInFlight protocol.ByteCount) bool {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CanSend", bytesInFlight)
 	ret0, _ := ret[0].(bool)
@@ -50,37 +51,37 @@ func (m *MockSendAlgorithmWithDebugInfos) CanSend(bytesInFlight protocol.ByteCou
 }
 
 // CanSend indicates an expected call of CanSend.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) CanSend(bytesInFlight any) *MockSendAlgorithmWithDebugInfosCanSendCall {
+func (mr *MockSendAlgorithmMockRecorder) CanSend(bytesInFlight any) *MockSendAlgorithmCanSendCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSend", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).CanSend), bytesInFlight)
-	return &MockSendAlgorithmWithDebugInfosCanSendCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSend", reflect.TypeOf((*MockSendAlgorithm)(nil).CanSend), bytesInFlight)
+	return &MockSendAlgorithmCanSendCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosCanSendCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosCanSendCall struct {
+// MockSendAlgorithmCanSendCall wrap *gomock.Call
+type MockSendAlgorithmCanSendCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosCanSendCall) Return(arg0 bool) *MockSendAlgorithmWithDebugInfosCanSendCall {
+func (c *MockSendAlgorithmCanSendCall) Return(arg0 bool) *MockSendAlgorithmCanSendCall {
 	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosCanSendCall) Do(f func(protocol.ByteCount) bool) *MockSendAlgorithmWithDebugInfosCanSendCall {
+func (c *MockSendAlgorithmCanSendCall) Do(f func(protocol.ByteCount) bool) *MockSendAlgorithmCanSendCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosCanSendCall) DoAndReturn(f func(protocol.ByteCount) bool) *MockSendAlgorithmWithDebugInfosCanSendCall {
+func (c *MockSendAlgorithmCanSendCall) DoAndReturn(f func(protocol.ByteCount) bool) *MockSendAlgorithmCanSendCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
 // GetCongestionWindow mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) GetCongestionWindow() protocol.ByteCount {
+func (m *MockSendAlgorithm) GetCongestionWindow() protocol.ByteCount {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetCongestionWindow")
 	ret0, _ := ret[0].(protocol.ByteCount)
@@ -88,37 +89,37 @@ func (m *MockSendAlgorithmWithDebugInfos) GetCongestionWindow() protocol.ByteCou
 }
 
 // GetCongestionWindow indicates an expected call of GetCongestionWindow.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) GetCongestionWindow() *MockSendAlgorithmWithDebugInfosGetCongestionWindowCall {
+func (mr *MockSendAlgorithmMockRecorder) GetCongestionWindow() *MockSendAlgorithmGetCongestionWindowCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCongestionWindow", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).GetCongestionWindow))
-	return &MockSendAlgorithmWithDebugInfosGetCongestionWindowCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCongestionWindow", reflect.TypeOf((*MockSendAlgorithm)(nil).GetCongestionWindow))
+	return &MockSendAlgorithmGetCongestionWindowCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosGetCongestionWindowCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosGetCongestionWindowCall struct {
+// MockSendAlgorithmGetCongestionWindowCall wrap *gomock.Call
+type MockSendAlgorithmGetCongestionWindowCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosGetCongestionWindowCall) Return(arg0 protocol.ByteCount) *MockSendAlgorithmWithDebugInfosGetCongestionWindowCall {
+func (c *MockSendAlgorithmGetCongestionWindowCall) Return(arg0 protocol.ByteCount) *MockSendAlgorithmGetCongestionWindowCall {
 	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosGetCongestionWindowCall) Do(f func() protocol.ByteCount) *MockSendAlgorithmWithDebugInfosGetCongestionWindowCall {
+func (c *MockSendAlgorithmGetCongestionWindowCall) Do(f func() protocol.ByteCount) *MockSendAlgorithmGetCongestionWindowCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosGetCongestionWindowCall) DoAndReturn(f func() protocol.ByteCount) *MockSendAlgorithmWithDebugInfosGetCongestionWindowCall {
+func (c *MockSendAlgorithmGetCongestionWindowCall) DoAndReturn(f func() protocol.ByteCount) *MockSendAlgorithmGetCongestionWindowCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
 // HasPacingBudget mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) HasPacingBudget(now monotime.Time) bool {
+func (m *MockSendAlgorithm) HasPacingBudget(now monotime.Time) bool {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "HasPacingBudget", now)
 	ret0, _ := ret[0].(bool)
@@ -126,329 +127,291 @@ func (m *MockSendAlgorithmWithDebugInfos) HasPacingBudget(now monotime.Time) boo
 }
 
 // HasPacingBudget indicates an expected call of HasPacingBudget.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) HasPacingBudget(now any) *MockSendAlgorithmWithDebugInfosHasPacingBudgetCall {
+func (mr *MockSendAlgorithmMockRecorder) HasPacingBudget(now any) *MockSendAlgorithmHasPacingBudgetCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPacingBudget", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).HasPacingBudget), now)
-	return &MockSendAlgorithmWithDebugInfosHasPacingBudgetCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPacingBudget", reflect.TypeOf((*MockSendAlgorithm)(nil).HasPacingBudget), now)
+	return &MockSendAlgorithmHasPacingBudgetCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosHasPacingBudgetCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosHasPacingBudgetCall struct {
+// MockSendAlgorithmHasPacingBudgetCall wrap *gomock.Call
+type MockSendAlgorithmHasPacingBudgetCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosHasPacingBudgetCall) Return(arg0 bool) *MockSendAlgorithmWithDebugInfosHasPacingBudgetCall {
+func (c *MockSendAlgorithmHasPacingBudgetCall) Return(arg0 bool) *MockSendAlgorithmHasPacingBudgetCall {
 	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosHasPacingBudgetCall) Do(f func(monotime.Time) bool) *MockSendAlgorithmWithDebugInfosHasPacingBudgetCall {
+func (c *MockSendAlgorithmHasPacingBudgetCall) Do(f func(monotime.Time) bool) *MockSendAlgorithmHasPacingBudgetCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosHasPacingBudgetCall) DoAndReturn(f func(monotime.Time) bool) *MockSendAlgorithmWithDebugInfosHasPacingBudgetCall {
+func (c *MockSendAlgorithmHasPacingBudgetCall) DoAndReturn(f func(monotime.Time) bool) *MockSendAlgorithmHasPacingBudgetCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
-// InRecovery mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) InRecovery() bool {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InRecovery")
-	ret0, _ := ret[0].(bool)
-	return ret0
-}
-
-// InRecovery indicates an expected call of InRecovery.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) InRecovery() *MockSendAlgorithmWithDebugInfosInRecoveryCall {
-	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InRecovery", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).InRecovery))
-	return &MockSendAlgorithmWithDebugInfosInRecoveryCall{Call: call}
-}
-
-// MockSendAlgorithmWithDebugInfosInRecoveryCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosInRecoveryCall struct {
-	*gomock.Call
-}
-
-// Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosInRecoveryCall) Return(arg0 bool) *MockSendAlgorithmWithDebugInfosInRecoveryCall {
-	c.Call = c.Call.Return(arg0)
-	return c
-}
-
-// Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosInRecoveryCall) Do(f func() bool) *MockSendAlgorithmWithDebugInfosInRecoveryCall {
-	c.Call = c.Call.Do(f)
-	return c
-}
-
-// DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosInRecoveryCall) DoAndReturn(f func() bool) *MockSendAlgorithmWithDebugInfosInRecoveryCall {
-	c.Call = c.Call.DoAndReturn(f)
-	return c
-}
-
-// InSlowStart mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) InSlowStart() bool {
+// MaybeExitSlowStart mocks base method.
+func (m *MockSendAlgorithm) MaybeExitSlowStart() {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InSlowStart")
-	ret0, _ := ret[0].(bool)
-	return ret0
+	m.ctrl.Call(m, "MaybeExitSlowStart")
 }
 
-// InSlowStart indicates an expected call of InSlowStart.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) InSlowStart() *MockSendAlgorithmWithDebugInfosInSlowStartCall {
+// MaybeExitSlowStart indicates an expected call of MaybeExitSlowStart.
+func (mr *MockSendAlgorithmMockRecorder) MaybeExitSlowStart() *MockSendAlgorithmMaybeExitSlowStartCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InSlowStart", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).InSlowStart))
-	return &MockSendAlgorithmWithDebugInfosInSlowStartCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaybeExitSlowStart", reflect.TypeOf((*MockSendAlgorithm)(nil).MaybeExitSlowStart))
+	return &MockSendAlgorithmMaybeExitSlowStartCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosInSlowStartCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosInSlowStartCall struct {
+// MockSendAlgorithmMaybeExitSlowStartCall wrap *gomock.Call
+type MockSendAlgorithmMaybeExitSlowStartCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosInSlowStartCall) Return(arg0 bool) *MockSendAlgorithmWithDebugInfosInSlowStartCall {
-	c.Call = c.Call.Return(arg0)
+func (c *MockSendAlgorithmMaybeExitSlowStartCall) Return() *MockSendAlgorithmMaybeExitSlowStartCall {
+	c.Call = c.Call.Return()
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosInSlowStartCall) Do(f func() bool) *MockSendAlgorithmWithDebugInfosInSlowStartCall {
+func (c *MockSendAlgorithmMaybeExitSlowStartCall) Do(f func()) *MockSendAlgorithmMaybeExitSlowStartCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosInSlowStartCall) DoAndReturn(f func() bool) *MockSendAlgorithmWithDebugInfosInSlowStartCall {
+func (c *MockSendAlgorithmMaybeExitSlowStartCall) DoAndReturn(f func()) *MockSendAlgorithmMaybeExitSlowStartCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
-// MaybeExitSlowStart mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) MaybeExitSlowStart() {
+// OnCongestionEvent mocks base method.
+func (m *MockSendAlgorithm) OnCongestionEvent(number protocol.PacketNumber, lostBytes, priorInFlight protocol.ByteCount) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "MaybeExitSlowStart")
+	m.ctrl.Call(m, "OnCongestionEvent", number, lostBytes, priorInFlight)
 }
 
-// MaybeExitSlowStart indicates an expected call of MaybeExitSlowStart.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) MaybeExitSlowStart() *MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall {
+// OnCongestionEvent indicates an expected call of OnCongestionEvent.
+func (mr *MockSendAlgorithmMockRecorder) OnCongestionEvent(number, lostBytes, priorInFlight any) *MockSendAlgorithmOnCongestionEventCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaybeExitSlowStart", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).MaybeExitSlowStart))
-	return &MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnCongestionEvent", reflect.TypeOf((*MockSendAlgorithm)(nil).OnCongestionEvent), number, lostBytes, priorInFlight)
+	return &MockSendAlgorithmOnCongestionEventCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall struct {
+// MockSendAlgorithmOnCongestionEventCall wrap *gomock.Call
+type MockSendAlgorithmOnCongestionEventCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall) Return() *MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall {
+func (c *MockSendAlgorithmOnCongestionEventCall) Return() *MockSendAlgorithmOnCongestionEventCall {
 	c.Call = c.Call.Return()
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall) Do(f func()) *MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall {
+func (c *MockSendAlgorithmOnCongestionEventCall) Do(f func(protocol.PacketNumber, protocol.ByteCount, protocol.ByteCount)) *MockSendAlgorithmOnCongestionEventCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall) DoAndReturn(f func()) *MockSendAlgorithmWithDebugInfosMaybeExitSlowStartCall {
+func (c *MockSendAlgorithmOnCongestionEventCall) DoAndReturn(f func(protocol.PacketNumber, protocol.ByteCount, protocol.ByteCount)) *MockSendAlgorithmOnCongestionEventCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
-// OnCongestionEvent mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) OnCongestionEvent(number protocol.PacketNumber, lostBytes, priorInFlight protocol.ByteCount) {
+// OnPacketAcked mocks base method.
+func (m *MockSendAlgorithm) OnPacketAcked(number protocol.PacketNumber, ackedBytes, priorInFlight protocol.ByteCount, eventTime monotime.Time) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "OnCongestionEvent", number, lostBytes, priorInFlight)
+	m.ctrl.Call(m, "OnPacketAcked", number, ackedBytes, priorInFlight, eventTime)
 }
 
-// OnCongestionEvent indicates an expected call of OnCongestionEvent.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) OnCongestionEvent(number, lostBytes, priorInFlight any) *MockSendAlgorithmWithDebugInfosOnCongestionEventCall {
+// OnPacketAcked indicates an expected call of OnPacketAcked.
+func (mr *MockSendAlgorithmMockRecorder) OnPacketAcked(number, ackedBytes, priorInFlight, eventTime any) *MockSendAlgorithmOnPacketAckedCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnCongestionEvent", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).OnCongestionEvent), number, lostBytes, priorInFlight)
-	return &MockSendAlgorithmWithDebugInfosOnCongestionEventCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnPacketAcked", reflect.TypeOf((*MockSendAlgorithm)(nil).OnPacketAcked), number, ackedBytes, priorInFlight, eventTime)
+	return &MockSendAlgorithmOnPacketAckedCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosOnCongestionEventCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosOnCongestionEventCall struct {
+// MockSendAlgorithmOnPacketAckedCall wrap *gomock.Call
+type MockSendAlgorithmOnPacketAckedCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosOnCongestionEventCall) Return() *MockSendAlgorithmWithDebugInfosOnCongestionEventCall {
+func (c *MockSendAlgorithmOnPacketAckedCall) Return() *MockSendAlgorithmOnPacketAckedCall {
 	c.Call = c.Call.Return()
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosOnCongestionEventCall) Do(f func(protocol.PacketNumber, protocol.ByteCount, protocol.ByteCount)) *MockSendAlgorithmWithDebugInfosOnCongestionEventCall {
+func (c *MockSendAlgorithmOnPacketAckedCall) Do(f func(protocol.PacketNumber, protocol.ByteCount, protocol.ByteCount, monotime.Time)) *MockSendAlgorithmOnPacketAckedCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosOnCongestionEventCall) DoAndReturn(f func(protocol.PacketNumber, protocol.ByteCount, protocol.ByteCount)) *MockSendAlgorithmWithDebugInfosOnCongestionEventCall {
+func (c *MockSendAlgorithmOnPacketAckedCall) DoAndReturn(f func(protocol.PacketNumber, protocol.ByteCount, protocol.ByteCount, monotime.Time)) *MockSendAlgorithmOnPacketAckedCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
-// OnPacketAcked mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) OnPacketAcked(number protocol.PacketNumber, ackedBytes, priorInFlight protocol.ByteCount, eventTime monotime.Time) {
+// OnPacketSent mocks base method.
+func (m *MockSendAlgorithm) OnPacketSent(sentTime monotime.Time, bytesInFlight protocol.ByteCount, packetNumber protocol.PacketNumber, bytes protocol.ByteCount, isRetransmittable bool) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "OnPacketAcked", number, ackedBytes, priorInFlight, eventTime)
+	m.ctrl.Call(m, "OnPacketSent", sentTime, bytesInFlight, packetNumber, bytes, isRetransmittable)
 }
 
-// OnPacketAcked indicates an expected call of OnPacketAcked.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) OnPacketAcked(number, ackedBytes, priorInFlight, eventTime any) *MockSendAlgorithmWithDebugInfosOnPacketAckedCall {
+// OnPacketSent indicates an expected call of OnPacketSent.
+func (mr *MockSendAlgorithmMockRecorder) OnPacketSent(sentTime, bytesInFlight, packetNumber, bytes, isRetransmittable any) *MockSendAlgorithmOnPacketSentCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnPacketAcked", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).OnPacketAcked), number, ackedBytes, priorInFlight, eventTime)
-	return &MockSendAlgorithmWithDebugInfosOnPacketAckedCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnPacketSent", reflect.TypeOf((*MockSendAlgorithm)(nil).OnPacketSent), sentTime, bytesInFlight, packetNumber, bytes, isRetransmittable)
+	return &MockSendAlgorithmOnPacketSentCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosOnPacketAckedCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosOnPacketAckedCall struct {
+// MockSendAlgorithmOnPacketSentCall wrap *gomock.Call
+type MockSendAlgorithmOnPacketSentCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosOnPacketAckedCall) Return() *MockSendAlgorithmWithDebugInfosOnPacketAckedCall {
+func (c *MockSendAlgorithmOnPacketSentCall) Return() *MockSendAlgorithmOnPacketSentCall {
 	c.Call = c.Call.Return()
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosOnPacketAckedCall) Do(f func(protocol.PacketNumber, protocol.ByteCount, protocol.ByteCount, monotime.Time)) *MockSendAlgorithmWithDebugInfosOnPacketAckedCall {
+func (c *MockSendAlgorithmOnPacketSentCall) Do(f func(monotime.Time, protocol.ByteCount, protocol.PacketNumber, protocol.ByteCount, bool)) *MockSendAlgorithmOnPacketSentCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosOnPacketAckedCall) DoAndReturn(f func(protocol.PacketNumber, protocol.ByteCount, protocol.ByteCount, monotime.Time)) *MockSendAlgorithmWithDebugInfosOnPacketAckedCall {
+func (c *MockSendAlgorithmOnPacketSentCall) DoAndReturn(f func(monotime.Time, protocol.ByteCount, protocol.PacketNumber, protocol.ByteCount, bool)) *MockSendAlgorithmOnPacketSentCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
-// OnPacketSent mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) OnPacketSent(sentTime monotime.Time, bytesInFlight protocol.ByteCount, packetNumber protocol.PacketNumber, bytes protocol.ByteCount, isRetransmittable bool) {
+// OnRetransmissionTimeout mocks base method.
+func (m *MockSendAlgorithm) OnRetransmissionTimeout(packetsRetransmitted bool) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "OnPacketSent", sentTime, bytesInFlight, packetNumber, bytes, isRetransmittable)
+	m.ctrl.Call(m, "OnRetransmissionTimeout", packetsRetransmitted)
 }
 
-// OnPacketSent indicates an expected call of OnPacketSent.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) OnPacketSent(sentTime, bytesInFlight, packetNumber, bytes, isRetransmittable any) *MockSendAlgorithmWithDebugInfosOnPacketSentCall {
+// OnRetransmissionTimeout indicates an expected call of OnRetransmissionTimeout.
+func (mr *MockSendAlgorithmMockRecorder) OnRetransmissionTimeout(packetsRetransmitted any) *MockSendAlgorithmOnRetransmissionTimeoutCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnPacketSent", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).OnPacketSent), sentTime, bytesInFlight, packetNumber, bytes, isRetransmittable)
-	return &MockSendAlgorithmWithDebugInfosOnPacketSentCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnRetransmissionTimeout", reflect.TypeOf((*MockSendAlgorithm)(nil).OnRetransmissionTimeout), packetsRetransmitted)
+	return &MockSendAlgorithmOnRetransmissionTimeoutCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosOnPacketSentCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosOnPacketSentCall struct {
+// MockSendAlgorithmOnRetransmissionTimeoutCall wrap *gomock.Call
+type MockSendAlgorithmOnRetransmissionTimeoutCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosOnPacketSentCall) Return() *MockSendAlgorithmWithDebugInfosOnPacketSentCall {
+func (c *MockSendAlgorithmOnRetransmissionTimeoutCall) Return() *MockSendAlgorithmOnRetransmissionTimeoutCall {
 	c.Call = c.Call.Return()
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosOnPacketSentCall) Do(f func(monotime.Time, protocol.ByteCount, protocol.PacketNumber, protocol.ByteCount, bool)) *MockSendAlgorithmWithDebugInfosOnPacketSentCall {
+func (c *MockSendAlgorithmOnRetransmissionTimeoutCall) Do(f func(bool)) *MockSendAlgorithmOnRetransmissionTimeoutCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosOnPacketSentCall) DoAndReturn(f func(monotime.Time, protocol.ByteCount, protocol.PacketNumber, protocol.ByteCount, bool)) *MockSendAlgorithmWithDebugInfosOnPacketSentCall {
+func (c *MockSendAlgorithmOnRetransmissionTimeoutCall) DoAndReturn(f func(bool)) *MockSendAlgorithmOnRetransmissionTimeoutCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
-// OnRetransmissionTimeout mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) OnRetransmissionTimeout(packetsRetransmitted bool) {
+// PacingRate mocks base method.
+func (m *MockSendAlgorithm) PacingRate() congestion.Bandwidth {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "OnRetransmissionTimeout", packetsRetransmitted)
+	ret := m.ctrl.Call(m, "PacingRate")
+	ret0, _ := ret[0].(congestion.Bandwidth)
+	return ret0
 }
 
-// OnRetransmissionTimeout indicates an expected call of OnRetransmissionTimeout.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) OnRetransmissionTimeout(packetsRetransmitted any) *MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall {
+// PacingRate indicates an expected call of PacingRate.
+func (mr *MockSendAlgorithmMockRecorder) PacingRate() *MockSendAlgorithmPacingRateCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnRetransmissionTimeout", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).OnRetransmissionTimeout), packetsRetransmitted)
-	return &MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PacingRate", reflect.TypeOf((*MockSendAlgorithm)(nil).PacingRate))
+	return &MockSendAlgorithmPacingRateCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall struct {
+// MockSendAlgorithmPacingRateCall wrap *gomock.Call
+type MockSendAlgorithmPacingRateCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall) Return() *MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall {
-	c.Call = c.Call.Return()
+func (c *MockSendAlgorithmPacingRateCall) Return(arg0 congestion.Bandwidth) *MockSendAlgorithmPacingRateCall {
+	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall) Do(f func(bool)) *MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall {
+func (c *MockSendAlgorithmPacingRateCall) Do(f func() congestion.Bandwidth) *MockSendAlgorithmPacingRateCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall) DoAndReturn(f func(bool)) *MockSendAlgorithmWithDebugInfosOnRetransmissionTimeoutCall {
+func (c *MockSendAlgorithmPacingRateCall) DoAndReturn(f func() congestion.Bandwidth) *MockSendAlgorithmPacingRateCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
 // SetMaxDatagramSize mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) SetMaxDatagramSize(arg0 protocol.ByteCount) {
+func (m *MockSendAlgorithm) SetMaxDatagramSize(arg0 protocol.ByteCount) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "SetMaxDatagramSize", arg0)
 }
 
 // SetMaxDatagramSize indicates an expected call of SetMaxDatagramSize.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) SetMaxDatagramSize(arg0 any) *MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall {
+func (mr *MockSendAlgorithmMockRecorder) SetMaxDatagramSize(arg0 any) *MockSendAlgorithmSetMaxDatagramSizeCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaxDatagramSize", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).SetMaxDatagramSize), arg0)
-	return &MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaxDatagramSize", reflect.TypeOf((*MockSendAlgorithm)(nil).SetMaxDatagramSize), arg0)
+	return &MockSendAlgorithmSetMaxDatagramSizeCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall struct {
+// MockSendAlgorithmSetMaxDatagramSizeCall wrap *gomock.Call
+type MockSendAlgorithmSetMaxDatagramSizeCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall) Return() *MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall {
+func (c *MockSendAlgorithmSetMaxDatagramSizeCall) Return() *MockSendAlgorithmSetMaxDatagramSizeCall {
 	c.Call = c.Call.Return()
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall) Do(f func(protocol.ByteCount)) *MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall {
+func (c *MockSendAlgorithmSetMaxDatagramSizeCall) Do(f func(protocol.ByteCount)) *MockSendAlgorithmSetMaxDatagramSizeCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall) DoAndReturn(f func(protocol.ByteCount)) *MockSendAlgorithmWithDebugInfosSetMaxDatagramSizeCall {
+func (c *MockSendAlgorithmSetMaxDatagramSizeCall) DoAndReturn(f func(protocol.ByteCount)) *MockSendAlgorithmSetMaxDatagramSizeCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
 // TimeUntilSend mocks base method.
-func (m *MockSendAlgorithmWithDebugInfos) TimeUntilSend(bytesInFlight protocol.ByteCount) monotime.Time {
+func (m *MockSendAlgorithm) TimeUntilSend(bytesInFlight protocol.ByteCount) monotime.Time {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "TimeUntilSend", bytesInFlight)
 	ret0, _ := ret[0].(monotime.Time)
@@ -456,31 +419,31 @@ func (m *MockSendAlgorithmWithDebugInfos) TimeUntilSend(bytesInFlight protocol.B
 }
 
 // TimeUntilSend indicates an expected call of TimeUntilSend.
-func (mr *MockSendAlgorithmWithDebugInfosMockRecorder) TimeUntilSend(bytesInFlight any) *MockSendAlgorithmWithDebugInfosTimeUntilSendCall {
+func (mr *MockSendAlgorithmMockRecorder) TimeUntilSend(bytesInFlight any) *MockSendAlgorithmTimeUntilSendCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TimeUntilSend", reflect.TypeOf((*MockSendAlgorithmWithDebugInfos)(nil).TimeUntilSend), bytesInFlight)
-	return &MockSendAlgorithmWithDebugInfosTimeUntilSendCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TimeUntilSend", reflect.TypeOf((*MockSendAlgorithm)(nil).TimeUntilSend), bytesInFlight)
+	return &MockSendAlgorithmTimeUntilSendCall{Call: call}
 }
 
-// MockSendAlgorithmWithDebugInfosTimeUntilSendCall wrap *gomock.Call
-type MockSendAlgorithmWithDebugInfosTimeUntilSendCall struct {
+// MockSendAlgorithmTimeUntilSendCall wrap *gomock.Call
+type MockSendAlgorithmTimeUntilSendCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockSendAlgorithmWithDebugInfosTimeUntilSendCall) Return(arg0 monotime.Time) *MockSendAlgorithmWithDebugInfosTimeUntilSendCall {
+func (c *MockSendAlgorithmTimeUntilSendCall) Return(arg0 monotime.Time) *MockSendAlgorithmTimeUntilSendCall {
 	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockSendAlgorithmWithDebugInfosTimeUntilSendCall) Do(f func(protocol.ByteCount) monotime.Time) *MockSendAlgorithmWithDebugInfosTimeUntilSendCall {
+func (c *MockSendAlgorithmTimeUntilSendCall) Do(f func(protocol.ByteCount) monotime.Time) *MockSendAlgorithmTimeUntilSendCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockSendAlgorithmWithDebugInfosTimeUntilSendCall) DoAndReturn(f func(protocol.ByteCount) monotime.Time) *MockSendAlgorithmWithDebugInfosTimeUntilSendCall {
+func (c *MockSendAlgorithmTimeUntilSendCall) DoAndReturn(f func(protocol.ByteCount) monotime.Time) *MockSendAlgorithmTimeUntilSendCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
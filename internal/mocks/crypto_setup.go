@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	handshake "github.com/quic-go/quic-go/internal/handshake"
 	protocol "github.com/quic-go/quic-go/internal/protocol"
@@ -307,6 +308,45 @@ func (c *MockCryptoSetupGet1RTTOpenerCall) DoAndReturn(f func() (handshake.Short
 	return c
 }
 
+// Get1RTTOpenerForPath mocks base method.
+func (m *MockCryptoSetup) Get1RTTOpenerForPath(arg0 protocol.PathID) (handshake.ShortHeaderOpener, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get1RTTOpenerForPath", arg0)
+	ret0, _ := ret[0].(handshake.ShortHeaderOpener)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get1RTTOpenerForPath indicates an expected call of Get1RTTOpenerForPath.
+func (mr *MockCryptoSetupMockRecorder) Get1RTTOpenerForPath(arg0 any) *MockCryptoSetupGet1RTTOpenerForPathCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get1RTTOpenerForPath", reflect.TypeOf((*MockCryptoSetup)(nil).Get1RTTOpenerForPath), arg0)
+	return &MockCryptoSetupGet1RTTOpenerForPathCall{Call: call}
+}
+
+// MockCryptoSetupGet1RTTOpenerForPathCall wrap *gomock.Call
+type MockCryptoSetupGet1RTTOpenerForPathCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCryptoSetupGet1RTTOpenerForPathCall) Return(arg0 handshake.ShortHeaderOpener, arg1 error) *MockCryptoSetupGet1RTTOpenerForPathCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCryptoSetupGet1RTTOpenerForPathCall) Do(f func(protocol.PathID) (handshake.ShortHeaderOpener, error)) *MockCryptoSetupGet1RTTOpenerForPathCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCryptoSetupGet1RTTOpenerForPathCall) DoAndReturn(f func(protocol.PathID) (handshake.ShortHeaderOpener, error)) *MockCryptoSetupGet1RTTOpenerForPathCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Get1RTTSealer mocks base method.
 func (m *MockCryptoSetup) Get1RTTSealer() (handshake.ShortHeaderSealer, error) {
 	m.ctrl.T.Helper()
@@ -346,6 +386,45 @@ func (c *MockCryptoSetupGet1RTTSealerCall) DoAndReturn(f func() (handshake.Short
 	return c
 }
 
+// Get1RTTSealerForPath mocks base method.
+func (m *MockCryptoSetup) Get1RTTSealerForPath(arg0 protocol.PathID) (handshake.ShortHeaderSealer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get1RTTSealerForPath", arg0)
+	ret0, _ := ret[0].(handshake.ShortHeaderSealer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get1RTTSealerForPath indicates an expected call of Get1RTTSealerForPath.
+func (mr *MockCryptoSetupMockRecorder) Get1RTTSealerForPath(arg0 any) *MockCryptoSetupGet1RTTSealerForPathCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get1RTTSealerForPath", reflect.TypeOf((*MockCryptoSetup)(nil).Get1RTTSealerForPath), arg0)
+	return &MockCryptoSetupGet1RTTSealerForPathCall{Call: call}
+}
+
+// MockCryptoSetupGet1RTTSealerForPathCall wrap *gomock.Call
+type MockCryptoSetupGet1RTTSealerForPathCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCryptoSetupGet1RTTSealerForPathCall) Return(arg0 handshake.ShortHeaderSealer, arg1 error) *MockCryptoSetupGet1RTTSealerForPathCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCryptoSetupGet1RTTSealerForPathCall) Do(f func(protocol.PathID) (handshake.ShortHeaderSealer, error)) *MockCryptoSetupGet1RTTSealerForPathCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCryptoSetupGet1RTTSealerForPathCall) DoAndReturn(f func(protocol.PathID) (handshake.ShortHeaderSealer, error)) *MockCryptoSetupGet1RTTSealerForPathCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetHandshakeOpener mocks base method.
 func (m *MockCryptoSetup) GetHandshakeOpener() (handshake.LongHeaderOpener, error) {
 	m.ctrl.T.Helper()
@@ -579,6 +658,80 @@ func (c *MockCryptoSetupHandleMessageCall) DoAndReturn(f func([]byte, protocol.E
 	return c
 }
 
+// InitiateKeyUpdate mocks base method.
+func (m *MockCryptoSetup) InitiateKeyUpdate() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InitiateKeyUpdate")
+}
+
+// InitiateKeyUpdate indicates an expected call of InitiateKeyUpdate.
+func (mr *MockCryptoSetupMockRecorder) InitiateKeyUpdate() *MockCryptoSetupInitiateKeyUpdateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitiateKeyUpdate", reflect.TypeOf((*MockCryptoSetup)(nil).InitiateKeyUpdate))
+	return &MockCryptoSetupInitiateKeyUpdateCall{Call: call}
+}
+
+// MockCryptoSetupInitiateKeyUpdateCall wrap *gomock.Call
+type MockCryptoSetupInitiateKeyUpdateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCryptoSetupInitiateKeyUpdateCall) Return() *MockCryptoSetupInitiateKeyUpdateCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCryptoSetupInitiateKeyUpdateCall) Do(f func()) *MockCryptoSetupInitiateKeyUpdateCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCryptoSetupInitiateKeyUpdateCall) DoAndReturn(f func()) *MockCryptoSetupInitiateKeyUpdateCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// KeyUpdatePending mocks base method.
+func (m *MockCryptoSetup) KeyUpdatePending() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyUpdatePending")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// KeyUpdatePending indicates an expected call of KeyUpdatePending.
+func (mr *MockCryptoSetupMockRecorder) KeyUpdatePending() *MockCryptoSetupKeyUpdatePendingCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyUpdatePending", reflect.TypeOf((*MockCryptoSetup)(nil).KeyUpdatePending))
+	return &MockCryptoSetupKeyUpdatePendingCall{Call: call}
+}
+
+// MockCryptoSetupKeyUpdatePendingCall wrap *gomock.Call
+type MockCryptoSetupKeyUpdatePendingCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCryptoSetupKeyUpdatePendingCall) Return(arg0 bool) *MockCryptoSetupKeyUpdatePendingCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCryptoSetupKeyUpdatePendingCall) Do(f func() bool) *MockCryptoSetupKeyUpdatePendingCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCryptoSetupKeyUpdatePendingCall) DoAndReturn(f func() bool) *MockCryptoSetupKeyUpdatePendingCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // NextEvent mocks base method.
 func (m *MockCryptoSetup) NextEvent() handshake.Event {
 	m.ctrl.T.Helper()
@@ -617,6 +770,44 @@ func (c *MockCryptoSetupNextEventCall) DoAndReturn(f func() handshake.Event) *Mo
 	return c
 }
 
+// NumKeyUpdates mocks base method.
+func (m *MockCryptoSetup) NumKeyUpdates() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumKeyUpdates")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// NumKeyUpdates indicates an expected call of NumKeyUpdates.
+func (mr *MockCryptoSetupMockRecorder) NumKeyUpdates() *MockCryptoSetupNumKeyUpdatesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumKeyUpdates", reflect.TypeOf((*MockCryptoSetup)(nil).NumKeyUpdates))
+	return &MockCryptoSetupNumKeyUpdatesCall{Call: call}
+}
+
+// MockCryptoSetupNumKeyUpdatesCall wrap *gomock.Call
+type MockCryptoSetupNumKeyUpdatesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCryptoSetupNumKeyUpdatesCall) Return(arg0 uint64) *MockCryptoSetupNumKeyUpdatesCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCryptoSetupNumKeyUpdatesCall) Do(f func() uint64) *MockCryptoSetupNumKeyUpdatesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCryptoSetupNumKeyUpdatesCall) DoAndReturn(f func() uint64) *MockCryptoSetupNumKeyUpdatesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetHandshakeConfirmed mocks base method.
 func (m *MockCryptoSetup) SetHandshakeConfirmed() {
 	m.ctrl.T.Helper()
@@ -653,6 +844,42 @@ func (c *MockCryptoSetupSetHandshakeConfirmedCall) DoAndReturn(f func()) *MockCr
 	return c
 }
 
+// SetKeyUpdateIntervals mocks base method.
+func (m *MockCryptoSetup) SetKeyUpdateIntervals(packets uint64, d time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetKeyUpdateIntervals", packets, d)
+}
+
+// SetKeyUpdateIntervals indicates an expected call of SetKeyUpdateIntervals.
+func (mr *MockCryptoSetupMockRecorder) SetKeyUpdateIntervals(packets, d any) *MockCryptoSetupSetKeyUpdateIntervalsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyUpdateIntervals", reflect.TypeOf((*MockCryptoSetup)(nil).SetKeyUpdateIntervals), packets, d)
+	return &MockCryptoSetupSetKeyUpdateIntervalsCall{Call: call}
+}
+
+// MockCryptoSetupSetKeyUpdateIntervalsCall wrap *gomock.Call
+type MockCryptoSetupSetKeyUpdateIntervalsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCryptoSetupSetKeyUpdateIntervalsCall) Return() *MockCryptoSetupSetKeyUpdateIntervalsCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCryptoSetupSetKeyUpdateIntervalsCall) Do(f func(uint64, time.Duration)) *MockCryptoSetupSetKeyUpdateIntervalsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCryptoSetupSetKeyUpdateIntervalsCall) DoAndReturn(f func(uint64, time.Duration)) *MockCryptoSetupSetKeyUpdateIntervalsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SetLargest1RTTAcked mocks base method.
 func (m *MockCryptoSetup) SetLargest1RTTAcked(arg0 protocol.PacketNumber) error {
 	m.ctrl.T.Helper()
@@ -11,6 +11,7 @@ package mocks
 
 import (
 	reflect "reflect"
+	time "time"
 
 	monotime "github.com/quic-go/quic-go/internal/monotime"
 	protocol "github.com/quic-go/quic-go/internal/protocol"
@@ -152,6 +153,44 @@ func (c *MockStreamFlowControllerAddBytesSentCall) DoAndReturn(f func(protocol.B
 	return c
 }
 
+// BlockedTime mocks base method.
+func (m *MockStreamFlowController) BlockedTime(now monotime.Time) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockedTime", now)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// BlockedTime indicates an expected call of BlockedTime.
+func (mr *MockStreamFlowControllerMockRecorder) BlockedTime(now any) *MockStreamFlowControllerBlockedTimeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockedTime", reflect.TypeOf((*MockStreamFlowController)(nil).BlockedTime), now)
+	return &MockStreamFlowControllerBlockedTimeCall{Call: call}
+}
+
+// MockStreamFlowControllerBlockedTimeCall wrap *gomock.Call
+type MockStreamFlowControllerBlockedTimeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStreamFlowControllerBlockedTimeCall) Return(arg0 time.Duration) *MockStreamFlowControllerBlockedTimeCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStreamFlowControllerBlockedTimeCall) Do(f func(monotime.Time) time.Duration) *MockStreamFlowControllerBlockedTimeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStreamFlowControllerBlockedTimeCall) DoAndReturn(f func(monotime.Time) time.Duration) *MockStreamFlowControllerBlockedTimeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetWindowUpdate mocks base method.
 func (m *MockStreamFlowController) GetWindowUpdate(arg0 monotime.Time) protocol.ByteCount {
 	m.ctrl.T.Helper()
@@ -191,17 +230,17 @@ func (c *MockStreamFlowControllerGetWindowUpdateCall) DoAndReturn(f func(monotim
 }
 
 // IsNewlyBlocked mocks base method.
-func (m *MockStreamFlowController) IsNewlyBlocked() bool {
+func (m *MockStreamFlowController) IsNewlyBlocked(now monotime.Time) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsNewlyBlocked")
+	ret := m.ctrl.Call(m, "IsNewlyBlocked", now)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // IsNewlyBlocked indicates an expected call of IsNewlyBlocked.
-func (mr *MockStreamFlowControllerMockRecorder) IsNewlyBlocked() *MockStreamFlowControllerIsNewlyBlockedCall {
+func (mr *MockStreamFlowControllerMockRecorder) IsNewlyBlocked(now any) *MockStreamFlowControllerIsNewlyBlockedCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNewlyBlocked", reflect.TypeOf((*MockStreamFlowController)(nil).IsNewlyBlocked))
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNewlyBlocked", reflect.TypeOf((*MockStreamFlowController)(nil).IsNewlyBlocked), now)
 	return &MockStreamFlowControllerIsNewlyBlockedCall{Call: call}
 }
 
@@ -217,13 +256,13 @@ func (c *MockStreamFlowControllerIsNewlyBlockedCall) Return(arg0 bool) *MockStre
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockStreamFlowControllerIsNewlyBlockedCall) Do(f func() bool) *MockStreamFlowControllerIsNewlyBlockedCall {
+func (c *MockStreamFlowControllerIsNewlyBlockedCall) Do(f func(monotime.Time) bool) *MockStreamFlowControllerIsNewlyBlockedCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockStreamFlowControllerIsNewlyBlockedCall) DoAndReturn(f func() bool) *MockStreamFlowControllerIsNewlyBlockedCall {
+func (c *MockStreamFlowControllerIsNewlyBlockedCall) DoAndReturn(f func(monotime.Time) bool) *MockStreamFlowControllerIsNewlyBlockedCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -305,17 +344,17 @@ func (c *MockStreamFlowControllerUpdateHighestReceivedCall) DoAndReturn(f func(p
 }
 
 // UpdateSendWindow mocks base method.
-func (m *MockStreamFlowController) UpdateSendWindow(arg0 protocol.ByteCount) bool {
+func (m *MockStreamFlowController) UpdateSendWindow(offset protocol.ByteCount, now monotime.Time) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSendWindow", arg0)
+	ret := m.ctrl.Call(m, "UpdateSendWindow", offset, now)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // UpdateSendWindow indicates an expected call of UpdateSendWindow.
-func (mr *MockStreamFlowControllerMockRecorder) UpdateSendWindow(arg0 any) *MockStreamFlowControllerUpdateSendWindowCall {
+func (mr *MockStreamFlowControllerMockRecorder) UpdateSendWindow(offset, now any) *MockStreamFlowControllerUpdateSendWindowCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSendWindow", reflect.TypeOf((*MockStreamFlowController)(nil).UpdateSendWindow), arg0)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSendWindow", reflect.TypeOf((*MockStreamFlowController)(nil).UpdateSendWindow), offset, now)
 	return &MockStreamFlowControllerUpdateSendWindowCall{Call: call}
 }
 
@@ -331,13 +370,13 @@ func (c *MockStreamFlowControllerUpdateSendWindowCall) Return(updated bool) *Moc
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockStreamFlowControllerUpdateSendWindowCall) Do(f func(protocol.ByteCount) bool) *MockStreamFlowControllerUpdateSendWindowCall {
+func (c *MockStreamFlowControllerUpdateSendWindowCall) Do(f func(protocol.ByteCount, monotime.Time) bool) *MockStreamFlowControllerUpdateSendWindowCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockStreamFlowControllerUpdateSendWindowCall) DoAndReturn(f func(protocol.ByteCount) bool) *MockStreamFlowControllerUpdateSendWindowCall {
+func (c *MockStreamFlowControllerUpdateSendWindowCall) DoAndReturn(f func(protocol.ByteCount, monotime.Time) bool) *MockStreamFlowControllerUpdateSendWindowCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
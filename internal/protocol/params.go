@@ -42,6 +42,12 @@ const DefaultMaxIncomingStreams = 100
 // DefaultMaxIncomingUniStreams is the maximum number of unidirectional streams that a peer may open
 const DefaultMaxIncomingUniStreams = 100
 
+// DefaultDatagramSendQueueLen is the maximum number of DATAGRAM frames queued for sending
+const DefaultDatagramSendQueueLen = 32
+
+// DefaultDatagramReceiveQueueLen is the maximum number of received DATAGRAM frames queued until they are read by the application
+const DefaultDatagramReceiveQueueLen = 128
+
 // MaxServerUnprocessedPackets is the max number of packets stored in the server that are not yet processed.
 const MaxServerUnprocessedPackets = 1024
 
@@ -138,6 +144,13 @@ const MaxActiveConnectionIDs = 4
 // MaxIssuedConnectionIDs is the maximum number of connection IDs that we're issuing at the same time.
 const MaxIssuedConnectionIDs = 6
 
+// MaxMultipathPathID is the maximum path ID we allow the peer to use when multipath is enabled.
+// It limits the number of paths that can be open at the same time.
+const MaxMultipathPathID = 3
+
+// MaxIssuedPathConnectionIDs is the number of connection IDs that we issue for every path ID other than 0.
+const MaxIssuedPathConnectionIDs = 2
+
 // PacketsPerConnectionID is the number of packets we send using one connection ID.
 // If the peer provices us with enough new connection IDs, we switch to a new connection ID.
 const PacketsPerConnectionID = 10000
@@ -156,6 +169,10 @@ const MaxAckDelay = 25 * time.Millisecond
 // This is the value that should be advertised to the peer.
 const MaxAckDelayInclGranularity = MaxAckDelay + TimerGranularity
 
+// MinAckDelay is the min_ack_delay we advertise when the ACK frequency extension is enabled.
+// We can't delay ACKs by less than the timer granularity.
+const MinAckDelay = TimerGranularity
+
 // KeyUpdateInterval is the maximum number of packets we send or receive before initiating a key update.
 const KeyUpdateInterval = 100 * 1000
 
@@ -93,6 +93,42 @@ func (e ECN) String() string {
 	}
 }
 
+// ECNState is the state of ECN validation of a path, see section 13.4.2 of RFC 9000.
+type ECNState uint8
+
+const (
+	// ECNStateDisabled means that ECN is not used,
+	// either because it was disabled in the config, or because it is not supported on this platform.
+	ECNStateDisabled ECNState = iota
+	// ECNStateTesting means that ECN-marked testing packets are being sent.
+	// This is also the state before the first 1-RTT packet was sent.
+	ECNStateTesting
+	// ECNStateUnknown means that all testing packets have been sent,
+	// but ECN capability hasn't been confirmed yet.
+	ECNStateUnknown
+	// ECNStateCapable means that the path was validated to support ECN.
+	ECNStateCapable
+	// ECNStateFailed means that ECN validation failed.
+	ECNStateFailed
+)
+
+func (s ECNState) String() string {
+	switch s {
+	case ECNStateDisabled:
+		return "disabled"
+	case ECNStateTesting:
+		return "testing"
+	case ECNStateUnknown:
+		return "unknown"
+	case ECNStateCapable:
+		return "capable"
+	case ECNStateFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("invalid ECN state: %d", s)
+	}
+}
+
 // A ByteCount in QUIC
 type ByteCount int64
 
@@ -107,6 +143,13 @@ const InvalidByteCount ByteCount = -1
 // A StatelessResetToken is a stateless reset token.
 type StatelessResetToken [16]byte
 
+// A PathID identifies a path of a multipath connection.
+// The path used during the handshake has the path ID 0.
+type PathID uint32
+
+// MaxPathID is the maximum value of a path ID
+const MaxPathID = PathID(1<<32 - 1)
+
 // MaxPacketBufferSize maximum packet size of any QUIC packet, based on
 // ethernet's max size, minus the IP and UDP headers. IPv6 has a 40 byte header,
 // UDP adds an additional 8 bytes.  This is a total overhead of 48 bytes.
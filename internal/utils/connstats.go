@@ -11,4 +11,23 @@ type ConnectionStats struct {
 	PacketsReceived atomic.Uint64
 	BytesLost       atomic.Uint64
 	PacketsLost     atomic.Uint64
+
+	CongestionWindow   atomic.Uint64
+	BytesInFlight      atomic.Uint64
+	SlowStartThreshold atomic.Uint64 // 0 if not set
+	PacingRate         atomic.Uint64 // in bits per second
+	BandwidthEstimate  atomic.Uint64 // in bits per second, 0 if not available
+	PTOCount           atomic.Uint64
+	MaxPacketSize      atomic.Uint64
+
+	ECNState      atomic.Uint32 // a protocol.ECNState
+	ECNCEReported atomic.Uint64
+	ECNCEReceived atomic.Uint64
+
+	StreamDataBlockedReceived atomic.Uint64
+	DataBlockedReceived       atomic.Uint64
+
+	HandshakeStartTime     atomic.Int64 // a monotime.Time
+	HandshakeCompleteTime  atomic.Int64 // a monotime.Time
+	HandshakeConfirmedTime atomic.Int64 // a monotime.Time
 }
@@ -20,6 +20,8 @@ const DefaultInitialRTT = 100 * time.Millisecond
 // RTTStats provides round-trip statistics
 type RTTStats struct {
 	hasMeasurement bool
+	// the RTT estimate used to calculate the PTO before an RTT sample is taken
+	initialRTT time.Duration
 
 	minRTT        atomic.Int64 // nanoseconds
 	latestRTT     atomic.Int64 // nanoseconds
@@ -31,6 +33,7 @@ type RTTStats struct {
 
 func NewRTTStats() *RTTStats {
 	var rttStats RTTStats
+	rttStats.initialRTT = DefaultInitialRTT
 	rttStats.minRTT.Store(DefaultInitialRTT.Nanoseconds())
 	rttStats.latestRTT.Store(DefaultInitialRTT.Nanoseconds())
 	rttStats.smoothedRTT.Store(DefaultInitialRTT.Nanoseconds())
@@ -68,7 +71,7 @@ func (r *RTTStats) MaxAckDelay() time.Duration {
 // PTO gets the probe timeout duration.
 func (r *RTTStats) PTO(includeMaxAckDelay bool) time.Duration {
 	if !r.hasMeasurement {
-		return 2 * DefaultInitialRTT
+		return 2 * r.initialRTT
 	}
 	pto := r.SmoothedRTT() + max(4*r.MeanDeviation(), protocol.TimerGranularity)
 	if includeMaxAckDelay {
@@ -125,21 +128,25 @@ func (r *RTTStats) SetMaxAckDelay(mad time.Duration) {
 }
 
 // SetInitialRTT sets the initial RTT.
-// It is used during handshake when restoring the RTT stats from the token.
+// It is used during handshake when restoring the RTT stats from the token,
+// or when the RTT to the peer is known from a previous connection.
+// A non-positive value is ignored.
 func (r *RTTStats) SetInitialRTT(t time.Duration) {
 	// On the server side, by the time we get to process the session ticket,
 	// we might already have obtained an RTT measurement.
 	// This can happen if we received the ClientHello in multiple pieces, and one of those pieces was lost.
 	// Discard the restored value. A fresh measurement is always better.
-	if r.hasMeasurement {
+	if r.hasMeasurement || t <= 0 {
 		return
 	}
+	r.initialRTT = t
 	r.smoothedRTT.Store(int64(t))
 	r.latestRTT.Store(int64(t))
 }
 
 func (r *RTTStats) ResetForPathMigration() {
 	r.hasMeasurement = false
+	r.initialRTT = DefaultInitialRTT
 	r.minRTT.Store(DefaultInitialRTT.Nanoseconds())
 	r.latestRTT.Store(DefaultInitialRTT.Nanoseconds())
 	r.smoothedRTT.Store(DefaultInitialRTT.Nanoseconds())
@@ -150,6 +157,7 @@ func (r *RTTStats) ResetForPathMigration() {
 func (r *RTTStats) Clone() *RTTStats {
 	out := &RTTStats{}
 	out.hasMeasurement = r.hasMeasurement
+	out.initialRTT = r.initialRTT
 	out.minRTT.Store(r.minRTT.Load())
 	out.latestRTT.Store(r.latestRTT.Load())
 	out.smoothedRTT.Store(r.smoothedRTT.Load())
@@ -98,10 +98,16 @@ func TestRTTStatsUpdateWithBadSendDeltas(t *testing.T) {
 
 func TestRTTStatsRestore(t *testing.T) {
 	rttStats := NewRTTStats()
+	require.Equal(t, 2*DefaultInitialRTT, rttStats.PTO(false))
 	rttStats.SetInitialRTT(10 * time.Second)
 	require.Equal(t, 10*time.Second, rttStats.LatestRTT())
 	require.Equal(t, 10*time.Second, rttStats.SmoothedRTT())
 	require.Zero(t, rttStats.MeanDeviation())
+	require.Equal(t, 20*time.Second, rttStats.PTO(false))
+	// non-positive values are ignored
+	rttStats.SetInitialRTT(0)
+	require.Equal(t, 10*time.Second, rttStats.SmoothedRTT())
+	require.Equal(t, 20*time.Second, rttStats.PTO(false))
 	// update the RTT and make sure that the initial value is immediately forgotten
 	rttStats.UpdateRTT(200*time.Millisecond, 0)
 	require.Equal(t, 200*time.Millisecond, rttStats.LatestRTT())
@@ -123,6 +129,7 @@ func TestRTTMeasurementAfterRestore(t *testing.T) {
 func TestRTTStatsResetForPathMigration(t *testing.T) {
 	rttStats := NewRTTStats()
 	rttStats.SetMaxAckDelay(42 * time.Millisecond)
+	rttStats.SetInitialRTT(time.Second)
 	rttStats.UpdateRTT(time.Second, 0)
 	rttStats.UpdateRTT(10*time.Second, 0)
 	require.True(t, rttStats.HasMeasurement())
@@ -18,19 +18,23 @@ type FrameParser struct {
 	supportsDatagrams     bool
 	supportsResetStreamAt bool
 	supportsAckFrequency  bool
+	supportsMultipath     bool
 
 	// To avoid allocating when parsing, keep a single ACK frame struct.
 	// It is used over and over again.
-	ackFrame *AckFrame
+	ackFrame     *AckFrame
+	pathAckFrame *PathAckFrame
 }
 
 // NewFrameParser creates a new frame parser.
-func NewFrameParser(supportsDatagrams, supportsResetStreamAt, supportsAckFrequency bool) *FrameParser {
+func NewFrameParser(supportsDatagrams, supportsResetStreamAt, supportsAckFrequency, supportsMultipath bool) *FrameParser {
 	return &FrameParser{
 		supportsDatagrams:     supportsDatagrams,
 		supportsResetStreamAt: supportsResetStreamAt,
 		supportsAckFrequency:  supportsAckFrequency,
+		supportsMultipath:     supportsMultipath,
 		ackFrame:              &AckFrame{},
+		pathAckFrame:          &PathAckFrame{},
 	}
 }
 
@@ -55,7 +59,8 @@ func (p *FrameParser) ParseType(b []byte, encLevel protocol.EncryptionLevel) (Fr
 		valid := ft.isValidRFC9000() ||
 			(p.supportsDatagrams && ft.IsDatagramFrameType()) ||
 			(p.supportsResetStreamAt && ft == FrameTypeResetStreamAt) ||
-			(p.supportsAckFrequency && (ft == FrameTypeAckFrequency || ft == FrameTypeImmediateAck))
+			(p.supportsAckFrequency && (ft == FrameTypeAckFrequency || ft == FrameTypeImmediateAck)) ||
+			(p.supportsMultipath && ft.isMultipathFrameType())
 		if !valid {
 			return 0, parsed, &qerr.TransportError{
 				ErrorCode:    qerr.FrameEncodingError,
@@ -105,6 +110,21 @@ func (p *FrameParser) ParseAckFrame(frameType FrameType, data []byte, encLevel p
 	return p.ackFrame, l, nil
 }
 
+// ParsePathAckFrame parses a PATH_ACK frame.
+// PATH_ACK frames are only sent in 1-RTT packets.
+func (p *FrameParser) ParsePathAckFrame(frameType FrameType, data []byte, v protocol.Version) (*PathAckFrame, int, error) {
+	p.pathAckFrame.Reset()
+	l, err := parsePathAckFrame(p.pathAckFrame, data, frameType, p.ackDelayExponent, v)
+	if err != nil {
+		return nil, l, &qerr.TransportError{
+			ErrorCode:    qerr.FrameEncodingError,
+			FrameType:    uint64(frameType),
+			ErrorMessage: err.Error(),
+		}
+	}
+	return p.pathAckFrame, l, nil
+}
+
 func (p *FrameParser) ParseDatagramFrame(frameType FrameType, data []byte, v protocol.Version) (*DatagramFrame, int, error) {
 	f, l, err := parseDatagramFrame(data, frameType, v)
 	if err != nil {
@@ -165,6 +185,20 @@ func (p *FrameParser) ParseLessCommonFrame(frameType FrameType, data []byte, v p
 		frame, l, err = parseAckFrequencyFrame(data, v)
 	case FrameTypeImmediateAck:
 		frame = &ImmediateAckFrame{}
+	case FrameTypePathAbandon:
+		frame, l, err = parsePathAbandonFrame(data, v)
+	case FrameTypePathStatusBackup, FrameTypePathStatusAvailable:
+		frame, l, err = parsePathStatusFrame(data, frameType, v)
+	case FrameTypePathNewConnectionID:
+		frame, l, err = parsePathNewConnectionIDFrame(data, v)
+	case FrameTypePathRetireConnectionID:
+		frame, l, err = parsePathRetireConnectionIDFrame(data, v)
+	case FrameTypeMaxPathID:
+		frame, l, err = parseMaxPathIDFrame(data, v)
+	case FrameTypePathsBlocked:
+		frame, l, err = parsePathsBlockedFrame(data, v)
+	case FrameTypePathConnectionIDsBlocked:
+		frame, l, err = parsePathConnectionIDsBlockedFrame(data, v)
 	default:
 		err = errUnknownFrameType
 	}
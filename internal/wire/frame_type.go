@@ -35,6 +35,17 @@ const (
 	// https://datatracker.ietf.org/doc/draft-ietf-quic-ack-frequency/11/
 	FrameTypeAckFrequency FrameType = 0xaf
 	FrameTypeImmediateAck FrameType = 0x1f
+	// https://datatracker.ietf.org/doc/draft-ietf-quic-multipath/14/
+	FrameTypePathAck                  FrameType = 0x15228c00
+	FrameTypePathAckECN               FrameType = 0x15228c01
+	FrameTypePathAbandon              FrameType = 0x15228c05
+	FrameTypePathStatusBackup         FrameType = 0x15228c07
+	FrameTypePathStatusAvailable      FrameType = 0x15228c08
+	FrameTypePathNewConnectionID      FrameType = 0x15228c09
+	FrameTypePathRetireConnectionID   FrameType = 0x15228c0a
+	FrameTypeMaxPathID                FrameType = 0x15228c0c
+	FrameTypePathsBlocked             FrameType = 0x15228c0d
+	FrameTypePathConnectionIDsBlocked FrameType = 0x15228c0e
 
 	FrameTypeDatagramNoLength   FrameType = 0x30
 	FrameTypeDatagramWithLength FrameType = 0x31
@@ -52,6 +63,22 @@ func (t FrameType) IsAckFrameType() bool {
 	return t == FrameTypeAck || t == FrameTypeAckECN
 }
 
+func (t FrameType) IsPathAckFrameType() bool {
+	return t == FrameTypePathAck || t == FrameTypePathAckECN
+}
+
+func (t FrameType) isMultipathFrameType() bool {
+	switch t {
+	case FrameTypePathAck, FrameTypePathAckECN, FrameTypePathAbandon,
+		FrameTypePathStatusBackup, FrameTypePathStatusAvailable,
+		FrameTypePathNewConnectionID, FrameTypePathRetireConnectionID,
+		FrameTypeMaxPathID, FrameTypePathsBlocked, FrameTypePathConnectionIDsBlocked:
+		return true
+	default:
+		return false
+	}
+}
+
 func (t FrameType) IsDatagramFrameType() bool {
 	return t == FrameTypeDatagramNoLength || t == FrameTypeDatagramWithLength
 }
@@ -71,7 +98,8 @@ func (t FrameType) isAllowedAtEncLevel(encLevel protocol.EncryptionLevel) bool {
 		case FrameTypeCrypto, FrameTypeAck, FrameTypeAckECN, FrameTypeConnectionClose, FrameTypeNewToken, FrameTypePathResponse, FrameTypeRetireConnectionID:
 			return false
 		default:
-			return true
+			// multipath is only negotiated once the handshake completes
+			return !t.isMultipathFrameType()
 		}
 	case protocol.Encryption1RTT:
 		return true
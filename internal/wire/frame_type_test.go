@@ -27,3 +27,10 @@ func TestIsDatagramFrameType(t *testing.T) {
 	require.False(t, FrameTypePing.IsDatagramFrameType(), "PingFrameType should not be recognized as DATAGRAM")
 	require.False(t, FrameType(0x1e).IsDatagramFrameType(), "HandshakeDoneFrameType should not be recognized as DATAGRAM")
 }
+
+func TestIsPathAckFrameType(t *testing.T) {
+	require.True(t, FrameTypePathAck.IsPathAckFrameType())
+	require.True(t, FrameTypePathAckECN.IsPathAckFrameType())
+	require.False(t, FrameTypeAck.IsPathAckFrameType())
+	require.False(t, FrameTypePathAbandon.IsPathAckFrameType())
+}
@@ -26,20 +26,9 @@ func LogFrame(logger utils.Logger, frame Frame, sent bool) {
 	case *ResetStreamFrame:
 		logger.Debugf("\t%s &wire.ResetStreamFrame{StreamID: %d, ErrorCode: %#x, FinalSize: %d}", dir, f.StreamID, f.ErrorCode, f.FinalSize)
 	case *AckFrame:
-		hasECN := f.ECT0 > 0 || f.ECT1 > 0 || f.ECNCE > 0
-		var ecn string
-		if hasECN {
-			ecn = fmt.Sprintf(", ECT0: %d, ECT1: %d, CE: %d", f.ECT0, f.ECT1, f.ECNCE)
-		}
-		if len(f.AckRanges) > 1 {
-			ackRanges := make([]string, len(f.AckRanges))
-			for i, r := range f.AckRanges {
-				ackRanges[i] = fmt.Sprintf("{Largest: %d, Smallest: %d}", r.Largest, r.Smallest)
-			}
-			logger.Debugf("\t%s &wire.AckFrame{LargestAcked: %d, LowestAcked: %d, AckRanges: {%s}, DelayTime: %s%s}", dir, f.LargestAcked(), f.LowestAcked(), strings.Join(ackRanges, ", "), f.DelayTime.String(), ecn)
-		} else {
-			logger.Debugf("\t%s &wire.AckFrame{LargestAcked: %d, LowestAcked: %d, DelayTime: %s%s}", dir, f.LargestAcked(), f.LowestAcked(), f.DelayTime.String(), ecn)
-		}
+		logger.Debugf("\t%s &wire.AckFrame{%s}", dir, ackFrameLogString(f))
+	case *PathAckFrame:
+		logger.Debugf("\t%s &wire.PathAckFrame{PathID: %d, %s}", dir, f.PathID, ackFrameLogString(&f.AckFrame))
 	case *MaxDataFrame:
 		logger.Debugf("\t%s &wire.MaxDataFrame{MaximumData: %d}", dir, f.MaximumData)
 	case *MaxStreamDataFrame:
@@ -72,3 +61,18 @@ func LogFrame(logger utils.Logger, frame Frame, sent bool) {
 		logger.Debugf("\t%s %#v", dir, frame)
 	}
 }
+
+func ackFrameLogString(f *AckFrame) string {
+	var ecn string
+	if f.hasECN() {
+		ecn = fmt.Sprintf(", ECT0: %d, ECT1: %d, CE: %d", f.ECT0, f.ECT1, f.ECNCE)
+	}
+	if len(f.AckRanges) > 1 {
+		ackRanges := make([]string, len(f.AckRanges))
+		for i, r := range f.AckRanges {
+			ackRanges[i] = fmt.Sprintf("{Largest: %d, Smallest: %d}", r.Largest, r.Smallest)
+		}
+		return fmt.Sprintf("LargestAcked: %d, LowestAcked: %d, AckRanges: {%s}, DelayTime: %s%s", f.LargestAcked(), f.LowestAcked(), strings.Join(ackRanges, ", "), f.DelayTime.String(), ecn)
+	}
+	return fmt.Sprintf("LargestAcked: %d, LowestAcked: %d, DelayTime: %s%s", f.LargestAcked(), f.LowestAcked(), f.DelayTime.String(), ecn)
+}
@@ -81,6 +81,20 @@ func TestLogAckFrameWithECN(t *testing.T) {
 	require.Contains(t, buf.String(), "\t<- &wire.AckFrame{LargestAcked: 1337, LowestAcked: 42, DelayTime: 1ms, ECT0: 5, ECT1: 66, CE: 777}\n")
 }
 
+func TestLogPathAckFrame(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLogTest(t, buf)
+	frame := &PathAckFrame{
+		PathID: 2,
+		AckFrame: AckFrame{
+			AckRanges: []AckRange{{Smallest: 42, Largest: 1337}},
+			DelayTime: 1 * time.Millisecond,
+		},
+	}
+	LogFrame(logger, frame, true)
+	require.Contains(t, buf.String(), "\t-> &wire.PathAckFrame{PathID: 2, LargestAcked: 1337, LowestAcked: 42, DelayTime: 1ms}\n")
+}
+
 func TestLogAckFrameWithMissingPackets(t *testing.T) {
 	buf := &bytes.Buffer{}
 	logger := setupLogTest(t, buf)
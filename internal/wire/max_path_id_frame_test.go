@@ -0,0 +1,45 @@
+package wire
+
+import (
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaxPathIDFrame(t *testing.T) {
+	data := encodeVarInt(0x1337) // maximum path ID
+	frame, l, err := parseMaxPathIDFrame(data, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, protocol.PathID(0x1337), frame.MaximumPathID)
+	require.Equal(t, len(data), l)
+}
+
+func TestParseMaxPathIDFrameInvalidPathID(t *testing.T) {
+	_, _, err := parseMaxPathIDFrame(encodeVarInt(1<<32), protocol.Version1)
+	require.EqualError(t, err, "invalid path ID: 4294967296")
+}
+
+func TestParseMaxPathIDFrameErrorsOnEOFs(t *testing.T) {
+	data := encodeVarInt(0xdeadbeef) // maximum path ID
+	_, l, err := parseMaxPathIDFrame(data, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(data), l)
+	for i := range data {
+		_, _, err := parseMaxPathIDFrame(data[:i], protocol.Version1)
+		require.Equal(t, io.EOF, err)
+	}
+}
+
+func TestWriteMaxPathIDFrame(t *testing.T) {
+	frame := &MaxPathIDFrame{MaximumPathID: 42}
+	b, err := frame.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	expected := quicvarint.Append(nil, uint64(FrameTypeMaxPathID))
+	expected = append(expected, encodeVarInt(42)...)
+	require.Equal(t, expected, b)
+	require.Len(t, b, int(frame.Length(protocol.Version1)))
+}
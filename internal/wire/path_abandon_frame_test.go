@@ -0,0 +1,44 @@
+package wire
+
+import (
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathAbandonFrame(t *testing.T) {
+	data := encodeVarInt(3)                      // path ID
+	data = append(data, encodeVarInt(0x1337)...) // error code
+	frame, l, err := parsePathAbandonFrame(data, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, protocol.PathID(3), frame.PathID)
+	require.Equal(t, uint64(0x1337), frame.ErrorCode)
+	require.Equal(t, len(data), l)
+}
+
+func TestParsePathAbandonFrameErrorsOnEOFs(t *testing.T) {
+	data := encodeVarInt(3)                          // path ID
+	data = append(data, encodeVarInt(0xdeadbeef)...) // error code
+	_, l, err := parsePathAbandonFrame(data, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(data), l)
+	for i := range data {
+		_, _, err := parsePathAbandonFrame(data[:i], protocol.Version1)
+		require.Equal(t, io.EOF, err)
+	}
+}
+
+func TestWritePathAbandonFrame(t *testing.T) {
+	frame := &PathAbandonFrame{PathID: 2, ErrorCode: 0x42}
+	b, err := frame.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	expected := quicvarint.Append(nil, uint64(FrameTypePathAbandon))
+	expected = append(expected, encodeVarInt(2)...)
+	expected = append(expected, encodeVarInt(0x42)...)
+	require.Equal(t, expected, b)
+	require.Len(t, b, int(frame.Length(protocol.Version1)))
+}
@@ -0,0 +1,64 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// A PathAckFrame is a PATH_ACK frame.
+// It acknowledges packets received on the path with the given path ID.
+type PathAckFrame struct {
+	PathID protocol.PathID
+	AckFrame
+}
+
+// parsePathAckFrame reads a PATH_ACK frame
+func parsePathAckFrame(frame *PathAckFrame, b []byte, typ FrameType, ackDelayExponent uint8, v protocol.Version) (int, error) {
+	startLen := len(b)
+	id, l, err := parsePathID(b)
+	if err != nil {
+		return 0, err
+	}
+	b = b[l:]
+	frame.PathID = id
+	l, err = parseAckFrame(&frame.AckFrame, b, typ, ackDelayExponent, v)
+	if err != nil {
+		return 0, err
+	}
+	return startLen - len(b) + l, nil
+}
+
+// Append appends a PATH_ACK frame.
+func (f *PathAckFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
+	if f.hasECN() {
+		b = quicvarint.Append(b, uint64(FrameTypePathAckECN))
+	} else {
+		b = quicvarint.Append(b, uint64(FrameTypePathAck))
+	}
+	b = quicvarint.Append(b, uint64(f.PathID))
+	return f.appendBody(b), nil
+}
+
+// Length of a written frame
+func (f *PathAckFrame) Length(_ protocol.Version) protocol.ByteCount {
+	return protocol.ByteCount(quicvarint.Len(uint64(FrameTypePathAck))+quicvarint.Len(uint64(f.PathID))) + f.bodyLength()
+}
+
+// Reset resets the PATH_ACK frame.
+func (f *PathAckFrame) Reset() {
+	f.PathID = 0
+	f.AckFrame.Reset()
+}
+
+func parsePathID(b []byte) (protocol.PathID, int, error) {
+	id, l, err := quicvarint.Parse(b)
+	if err != nil {
+		return 0, 0, replaceUnexpectedEOF(err)
+	}
+	if id > uint64(protocol.MaxPathID) {
+		return 0, 0, fmt.Errorf("invalid path ID: %d", id)
+	}
+	return protocol.PathID(id), l, nil
+}
@@ -0,0 +1,135 @@
+package wire
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathAckFrame(t *testing.T) {
+	data := encodeVarInt(3)                   // path ID
+	data = append(data, encodeVarInt(100)...) // largest acked
+	data = append(data, encodeVarInt(0)...)   // delay
+	data = append(data, encodeVarInt(0)...)   // num blocks
+	data = append(data, encodeVarInt(10)...)  // first ack block
+	var frame PathAckFrame
+	n, err := parsePathAckFrame(&frame, data, FrameTypePathAck, protocol.AckDelayExponent, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	require.Equal(t, protocol.PathID(3), frame.PathID)
+	require.Equal(t, protocol.PacketNumber(100), frame.LargestAcked())
+	require.Equal(t, protocol.PacketNumber(90), frame.LowestAcked())
+	require.False(t, frame.HasMissingRanges())
+}
+
+func TestParsePathAckFrameWithECN(t *testing.T) {
+	data := encodeVarInt(1)                       // path ID
+	data = append(data, encodeVarInt(100)...)     // largest acked
+	data = append(data, encodeVarInt(0)...)       // delay
+	data = append(data, encodeVarInt(0)...)       // num blocks
+	data = append(data, encodeVarInt(10)...)      // first ack block
+	data = append(data, encodeVarInt(0x42)...)    // ECT(0)
+	data = append(data, encodeVarInt(0x12345)...) // ECT(1)
+	data = append(data, encodeVarInt(0x12)...)    // ECN-CE
+	var frame PathAckFrame
+	n, err := parsePathAckFrame(&frame, data, FrameTypePathAckECN, protocol.AckDelayExponent, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	require.Equal(t, uint64(0x42), frame.ECT0)
+	require.Equal(t, uint64(0x12345), frame.ECT1)
+	require.Equal(t, uint64(0x12), frame.ECNCE)
+}
+
+func TestParsePathAckFrameInvalidPathID(t *testing.T) {
+	data := encodeVarInt(1 << 32)             // path ID
+	data = append(data, encodeVarInt(100)...) // largest acked
+	data = append(data, encodeVarInt(0)...)   // delay
+	data = append(data, encodeVarInt(0)...)   // num blocks
+	data = append(data, encodeVarInt(10)...)  // first ack block
+	var frame PathAckFrame
+	_, err := parsePathAckFrame(&frame, data, FrameTypePathAck, protocol.AckDelayExponent, protocol.Version1)
+	require.EqualError(t, err, "invalid path ID: 4294967296")
+}
+
+func TestParsePathAckFrameErrorsOnEOFs(t *testing.T) {
+	data := encodeVarInt(2)                    // path ID
+	data = append(data, encodeVarInt(1000)...) // largest acked
+	data = append(data, encodeVarInt(0)...)    // delay
+	data = append(data, encodeVarInt(1)...)    // num blocks
+	data = append(data, encodeVarInt(100)...)  // first ack block
+	data = append(data, encodeVarInt(98)...)   // gap
+	data = append(data, encodeVarInt(50)...)   // ack block
+	var frame PathAckFrame
+	n, err := parsePathAckFrame(&frame, data, FrameTypePathAck, protocol.AckDelayExponent, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	for i := range data {
+		var frame PathAckFrame
+		_, err := parsePathAckFrame(&frame, data[:i], FrameTypePathAck, protocol.AckDelayExponent, protocol.Version1)
+		require.Equal(t, io.EOF, err)
+	}
+}
+
+func TestWritePathAckFrame(t *testing.T) {
+	f := &PathAckFrame{
+		PathID: 0x1337,
+		AckFrame: AckFrame{
+			AckRanges: []AckRange{{Smallest: 100, Largest: 1000}, {Smallest: 10, Largest: 50}},
+			DelayTime: 18 * time.Millisecond,
+		},
+	}
+	b, err := f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Len(t, b, int(f.Length(protocol.Version1)))
+	typ, l, err := quicvarint.Parse(b)
+	require.NoError(t, err)
+	require.Equal(t, FrameTypePathAck, FrameType(typ))
+	var frame PathAckFrame
+	n, err := parsePathAckFrame(&frame, b[l:], FrameTypePathAck, protocol.AckDelayExponent, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(b)-l, n)
+	require.Equal(t, f, &frame)
+}
+
+func TestWritePathAckFrameWithECN(t *testing.T) {
+	f := &PathAckFrame{
+		PathID: 1,
+		AckFrame: AckFrame{
+			AckRanges: []AckRange{{Smallest: 10, Largest: 2000}},
+			ECT0:      13,
+			ECT1:      37,
+			ECNCE:     12345,
+		},
+	}
+	b, err := f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Len(t, b, int(f.Length(protocol.Version1)))
+	typ, l, err := quicvarint.Parse(b)
+	require.NoError(t, err)
+	require.Equal(t, FrameTypePathAckECN, FrameType(typ))
+	var frame PathAckFrame
+	_, err = parsePathAckFrame(&frame, b[l:], FrameTypePathAckECN, protocol.AckDelayExponent, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, f, &frame)
+}
+
+func TestPathAckFrameReset(t *testing.T) {
+	f := &PathAckFrame{
+		PathID: 5,
+		AckFrame: AckFrame{
+			AckRanges: []AckRange{{Smallest: 10, Largest: 20}},
+			DelayTime: time.Second,
+			ECT0:      1,
+		},
+	}
+	f.Reset()
+	require.Zero(t, f.PathID)
+	require.Empty(t, f.AckRanges)
+	require.Zero(t, f.DelayTime)
+	require.Zero(t, f.ECT0)
+}
@@ -0,0 +1,39 @@
+package wire
+
+import (
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// A PathConnectionIDsBlockedFrame is a PATH_CIDS_BLOCKED frame
+type PathConnectionIDsBlockedFrame struct {
+	PathID             protocol.PathID
+	NextSequenceNumber uint64
+}
+
+func parsePathConnectionIDsBlockedFrame(b []byte, _ protocol.Version) (*PathConnectionIDsBlockedFrame, int, error) {
+	startLen := len(b)
+	id, l, err := parsePathID(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	b = b[l:]
+	seq, l, err := quicvarint.Parse(b)
+	if err != nil {
+		return nil, 0, replaceUnexpectedEOF(err)
+	}
+	b = b[l:]
+	return &PathConnectionIDsBlockedFrame{PathID: id, NextSequenceNumber: seq}, startLen - len(b), nil
+}
+
+func (f *PathConnectionIDsBlockedFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
+	b = quicvarint.Append(b, uint64(FrameTypePathConnectionIDsBlocked))
+	b = quicvarint.Append(b, uint64(f.PathID))
+	b = quicvarint.Append(b, f.NextSequenceNumber)
+	return b, nil
+}
+
+// Length of a written frame
+func (f *PathConnectionIDsBlockedFrame) Length(protocol.Version) protocol.ByteCount {
+	return protocol.ByteCount(quicvarint.Len(uint64(FrameTypePathConnectionIDsBlocked)) + quicvarint.Len(uint64(f.PathID)) + quicvarint.Len(f.NextSequenceNumber))
+}
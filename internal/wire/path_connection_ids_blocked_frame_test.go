@@ -0,0 +1,44 @@
+package wire
+
+import (
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathConnectionIDsBlockedFrame(t *testing.T) {
+	data := encodeVarInt(3)                      // path ID
+	data = append(data, encodeVarInt(0x1337)...) // next sequence number
+	frame, l, err := parsePathConnectionIDsBlockedFrame(data, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, protocol.PathID(3), frame.PathID)
+	require.Equal(t, uint64(0x1337), frame.NextSequenceNumber)
+	require.Equal(t, len(data), l)
+}
+
+func TestParsePathConnectionIDsBlockedFrameErrorsOnEOFs(t *testing.T) {
+	data := encodeVarInt(3)                          // path ID
+	data = append(data, encodeVarInt(0xdeadbeef)...) // next sequence number
+	_, l, err := parsePathConnectionIDsBlockedFrame(data, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(data), l)
+	for i := range data {
+		_, _, err := parsePathConnectionIDsBlockedFrame(data[:i], protocol.Version1)
+		require.Equal(t, io.EOF, err)
+	}
+}
+
+func TestWritePathConnectionIDsBlockedFrame(t *testing.T) {
+	frame := &PathConnectionIDsBlockedFrame{PathID: 1, NextSequenceNumber: 7}
+	b, err := frame.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	expected := quicvarint.Append(nil, uint64(FrameTypePathConnectionIDsBlocked))
+	expected = append(expected, encodeVarInt(1)...)
+	expected = append(expected, encodeVarInt(7)...)
+	require.Equal(t, expected, b)
+	require.Len(t, b, int(frame.Length(protocol.Version1)))
+}
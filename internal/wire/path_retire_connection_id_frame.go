@@ -0,0 +1,39 @@
+package wire
+
+import (
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// A PathRetireConnectionIDFrame is a PATH_RETIRE_CONNECTION_ID frame
+type PathRetireConnectionIDFrame struct {
+	PathID         protocol.PathID
+	SequenceNumber uint64
+}
+
+func parsePathRetireConnectionIDFrame(b []byte, _ protocol.Version) (*PathRetireConnectionIDFrame, int, error) {
+	startLen := len(b)
+	id, l, err := parsePathID(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	b = b[l:]
+	seq, l, err := quicvarint.Parse(b)
+	if err != nil {
+		return nil, 0, replaceUnexpectedEOF(err)
+	}
+	b = b[l:]
+	return &PathRetireConnectionIDFrame{PathID: id, SequenceNumber: seq}, startLen - len(b), nil
+}
+
+func (f *PathRetireConnectionIDFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
+	b = quicvarint.Append(b, uint64(FrameTypePathRetireConnectionID))
+	b = quicvarint.Append(b, uint64(f.PathID))
+	b = quicvarint.Append(b, f.SequenceNumber)
+	return b, nil
+}
+
+// Length of a written frame
+func (f *PathRetireConnectionIDFrame) Length(protocol.Version) protocol.ByteCount {
+	return protocol.ByteCount(quicvarint.Len(uint64(FrameTypePathRetireConnectionID)) + quicvarint.Len(uint64(f.PathID)) + quicvarint.Len(f.SequenceNumber))
+}
@@ -0,0 +1,54 @@
+package wire
+
+import (
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathStatusFrame(t *testing.T) {
+	data := encodeVarInt(1)                    // path ID
+	data = append(data, encodeVarInt(1337)...) // sequence number
+	frame, l, err := parsePathStatusFrame(data, FrameTypePathStatusBackup, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &PathStatusFrame{PathID: 1, SequenceNumber: 1337, Backup: true}, frame)
+	require.Equal(t, len(data), l)
+
+	frame, l, err = parsePathStatusFrame(data, FrameTypePathStatusAvailable, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &PathStatusFrame{PathID: 1, SequenceNumber: 1337, Backup: false}, frame)
+	require.Equal(t, len(data), l)
+}
+
+func TestParsePathStatusFrameErrorsOnEOFs(t *testing.T) {
+	data := encodeVarInt(1)                    // path ID
+	data = append(data, encodeVarInt(1337)...) // sequence number
+	_, l, err := parsePathStatusFrame(data, FrameTypePathStatusBackup, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(data), l)
+	for i := range data {
+		_, _, err := parsePathStatusFrame(data[:i], FrameTypePathStatusBackup, protocol.Version1)
+		require.Equal(t, io.EOF, err)
+	}
+}
+
+func TestWritePathStatusFrame(t *testing.T) {
+	for _, backup := range []bool{true, false} {
+		frame := &PathStatusFrame{PathID: 2, SequenceNumber: 0x1337, Backup: backup}
+		b, err := frame.Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		typ := FrameTypePathStatusAvailable
+		if backup {
+			typ = FrameTypePathStatusBackup
+		}
+		expected := quicvarint.Append(nil, uint64(typ))
+		expected = append(expected, encodeVarInt(2)...)
+		expected = append(expected, encodeVarInt(0x1337)...)
+		require.Equal(t, expected, b)
+		require.Len(t, b, int(frame.Length(protocol.Version1)))
+	}
+}
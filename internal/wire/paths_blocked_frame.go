@@ -0,0 +1,30 @@
+package wire
+
+import (
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// A PathsBlockedFrame is a PATHS_BLOCKED frame
+type PathsBlockedFrame struct {
+	MaximumPathID protocol.PathID
+}
+
+func parsePathsBlockedFrame(b []byte, _ protocol.Version) (*PathsBlockedFrame, int, error) {
+	id, l, err := parsePathID(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &PathsBlockedFrame{MaximumPathID: id}, l, nil
+}
+
+func (f *PathsBlockedFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
+	b = quicvarint.Append(b, uint64(FrameTypePathsBlocked))
+	b = quicvarint.Append(b, uint64(f.MaximumPathID))
+	return b, nil
+}
+
+// Length of a written frame
+func (f *PathsBlockedFrame) Length(protocol.Version) protocol.ByteCount {
+	return protocol.ByteCount(quicvarint.Len(uint64(FrameTypePathsBlocked)) + quicvarint.Len(uint64(f.MaximumPathID)))
+}
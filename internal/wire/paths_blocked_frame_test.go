@@ -0,0 +1,40 @@
+package wire
+
+import (
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathsBlockedFrame(t *testing.T) {
+	data := encodeVarInt(0x1337) // maximum path ID
+	frame, l, err := parsePathsBlockedFrame(data, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, protocol.PathID(0x1337), frame.MaximumPathID)
+	require.Equal(t, len(data), l)
+}
+
+func TestParsePathsBlockedFrameErrorsOnEOFs(t *testing.T) {
+	data := encodeVarInt(0xdeadbeef) // maximum path ID
+	_, l, err := parsePathsBlockedFrame(data, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(data), l)
+	for i := range data {
+		_, _, err := parsePathsBlockedFrame(data[:i], protocol.Version1)
+		require.Equal(t, io.EOF, err)
+	}
+}
+
+func TestWritePathsBlockedFrame(t *testing.T) {
+	frame := &PathsBlockedFrame{MaximumPathID: 42}
+	b, err := frame.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	expected := quicvarint.Append(nil, uint64(FrameTypePathsBlocked))
+	expected = append(expected, encodeVarInt(42)...)
+	require.Equal(t, expected, b)
+	require.Len(t, b, int(frame.Length(protocol.Version1)))
+}
@@ -33,6 +33,7 @@ func appendInitialSourceConnectionID(b []byte) []byte {
 func TestTransportParametersStringRepresentation(t *testing.T) {
 	rcid := protocol.ParseConnectionID([]byte{0xde, 0xad, 0xc0, 0xde})
 	minAckDelay := 42 * time.Millisecond
+	maxPathID := protocol.PathID(7)
 	p := &TransportParameters{
 		InitialMaxStreamDataBidiLocal:   1234,
 		InitialMaxStreamDataBidiRemote:  2345,
@@ -51,8 +52,9 @@ func TestTransportParametersStringRepresentation(t *testing.T) {
 		MaxDatagramFrameSize:            876,
 		EnableResetStreamAt:             true,
 		MinAckDelay:                     &minAckDelay,
+		InitialMaxPathID:                &maxPathID,
 	}
-	expected := "&wire.TransportParameters{OriginalDestinationConnectionID: deadbeef, InitialSourceConnectionID: decafbad, RetrySourceConnectionID: deadc0de, InitialMaxStreamDataBidiLocal: 1234, InitialMaxStreamDataBidiRemote: 2345, InitialMaxStreamDataUni: 3456, InitialMaxData: 4567, MaxBidiStreamNum: 1337, MaxUniStreamNum: 7331, MaxIdleTimeout: 42s, AckDelayExponent: 14, MaxAckDelay: 37ms, ActiveConnectionIDLimit: 123, StatelessResetToken: 0x112233445566778899aabbccddeeff00, MaxDatagramFrameSize: 876, EnableResetStreamAt: true, MinAckDelay: 42ms}"
+	expected := "&wire.TransportParameters{OriginalDestinationConnectionID: deadbeef, InitialSourceConnectionID: decafbad, RetrySourceConnectionID: deadc0de, InitialMaxStreamDataBidiLocal: 1234, InitialMaxStreamDataBidiRemote: 2345, InitialMaxStreamDataUni: 3456, InitialMaxData: 4567, MaxBidiStreamNum: 1337, MaxUniStreamNum: 7331, MaxIdleTimeout: 42s, AckDelayExponent: 14, MaxAckDelay: 37ms, ActiveConnectionIDLimit: 123, StatelessResetToken: 0x112233445566778899aabbccddeeff00, MaxDatagramFrameSize: 876, EnableResetStreamAt: true, MinAckDelay: 42ms, InitialMaxPathID: 7}"
 	require.Equal(t, expected, p.String())
 }
 
@@ -81,6 +83,7 @@ func TestMarshalAndUnmarshalTransportParameters(t *testing.T) {
 	rand.Read(token[:])
 	rcid := protocol.ParseConnectionID([]byte{0xde, 0xad, 0xc0, 0xde})
 	minAckDelay := 42 * time.Millisecond
+	maxPathID := protocol.PathID(getRandomValueUpTo(uint64(protocol.MaxPathID)))
 	params := &TransportParameters{
 		InitialMaxStreamDataBidiLocal:   protocol.ByteCount(getRandomValue()),
 		InitialMaxStreamDataBidiRemote:  protocol.ByteCount(getRandomValue()),
@@ -101,6 +104,8 @@ func TestMarshalAndUnmarshalTransportParameters(t *testing.T) {
 		MaxDatagramFrameSize:            protocol.ByteCount(getRandomValue()),
 		EnableResetStreamAt:             getRandomValue()%2 == 0,
 		MinAckDelay:                     &minAckDelay,
+		InitialMaxPathID:                &maxPathID,
+		AdditionalParameters:            map[uint64][]byte{0x1337: []byte("foobar"), 0x42: {}},
 	}
 	data := params.Marshal(protocol.PerspectiveServer)
 
@@ -126,6 +131,9 @@ func TestMarshalAndUnmarshalTransportParameters(t *testing.T) {
 	require.Equal(t, params.EnableResetStreamAt, p.EnableResetStreamAt)
 	require.NotNil(t, p.MinAckDelay)
 	require.Equal(t, minAckDelay, *p.MinAckDelay)
+	require.NotNil(t, p.InitialMaxPathID)
+	require.Equal(t, maxPathID, *p.InitialMaxPathID)
+	require.Equal(t, params.AdditionalParameters, p.AdditionalParameters)
 }
 
 func TestMarshalAdditionalTransportParameters(t *testing.T) {
@@ -419,6 +427,29 @@ func TestTransportParameterErrors(t *testing.T) {
 			perspective:    protocol.PerspectiveClient,
 			expectedErrMsg: "min_ack_delay (2562047h47m16.854775807s) is greater than max_ack_delay (42ms)",
 		},
+		{
+			name: "initial max path ID too large",
+			data: func() []byte {
+				b := quicvarint.Append(nil, uint64(initialMaxPathIDParameterID))
+				b = quicvarint.Append(b, uint64(quicvarint.Len(1<<32)))
+				b = quicvarint.Append(b, 1<<32)
+				return appendInitialSourceConnectionID(b)
+			}(),
+			perspective:    protocol.PerspectiveClient,
+			expectedErrMsg: "invalid value for initial_max_path_id: 4294967296 (maximum 4294967295)",
+		},
+		{
+			name: "initial max path ID with zero-length connection ID",
+			data: func() []byte {
+				b := quicvarint.Append(nil, uint64(initialMaxPathIDParameterID))
+				b = quicvarint.Append(b, uint64(quicvarint.Len(3)))
+				b = quicvarint.Append(b, 3)
+				b = quicvarint.Append(b, uint64(initialSourceConnectionIDParameterID))
+				return quicvarint.Append(b, 0)
+			}(),
+			perspective:    protocol.PerspectiveClient,
+			expectedErrMsg: "initial_max_path_id sent with a zero-length connection ID",
+		},
 	}
 
 	for _, tt := range tests {
@@ -448,6 +479,10 @@ func TestTransportParameterUnknownParameters(t *testing.T) {
 	b = quicvarint.Append(b, 0x42)
 	b = quicvarint.Append(b, 6)
 	b = append(b, []byte("foobar")...)
+	// write a greased parameter
+	b = quicvarint.Append(b, 31*1337+27)
+	b = quicvarint.Append(b, 3)
+	b = append(b, []byte("foo")...)
 	// write a known parameter
 	b = quicvarint.Append(b, uint64(initialMaxStreamDataBidiRemoteParameterID))
 	b = quicvarint.Append(b, uint64(quicvarint.Len(0x42)))
@@ -458,6 +493,16 @@ func TestTransportParameterUnknownParameters(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, protocol.ByteCount(0x1337), p.InitialMaxStreamDataBidiLocal)
 	require.Equal(t, protocol.ByteCount(0x42), p.InitialMaxStreamDataBidiRemote)
+	// unknown parameters are exposed, greased parameters are dropped
+	require.Equal(t, map[uint64][]byte{0x42: []byte("foobar")}, p.AdditionalParameters)
+}
+
+func TestTransportParameterReservedIDs(t *testing.T) {
+	require.True(t, IsReservedTransportParameterID(uint64(maxIdleTimeoutParameterID)))
+	require.True(t, IsReservedTransportParameterID(uint64(initialMaxPathIDParameterID)))
+	require.True(t, IsReservedTransportParameterID(27))
+	require.True(t, IsReservedTransportParameterID(31*1337+27))
+	require.False(t, IsReservedTransportParameterID(0x1337))
 }
 
 func TestTransportParameterRejectsDuplicateParameters(t *testing.T) {
@@ -601,6 +646,7 @@ func TestTransportParametersFromSessionTicket(t *testing.T) {
 		ActiveConnectionIDLimit:        2 + getRandomValueUpTo(quicvarint.Max-2),
 		MaxDatagramFrameSize:           protocol.ByteCount(getRandomValueUpTo(uint64(MaxDatagramSize))),
 		EnableResetStreamAt:            getRandomValue()%2 == 0,
+		AdditionalParameters:           map[uint64][]byte{0x1337: []byte("foobar")},
 	}
 	require.True(t, params.ValidFor0RTT(params))
 	b := params.MarshalForSessionTicket(nil)
@@ -615,6 +661,7 @@ func TestTransportParametersFromSessionTicket(t *testing.T) {
 	require.Equal(t, params.ActiveConnectionIDLimit, tp.ActiveConnectionIDLimit)
 	require.Equal(t, params.MaxDatagramFrameSize, tp.MaxDatagramFrameSize)
 	require.Equal(t, params.EnableResetStreamAt, tp.EnableResetStreamAt)
+	require.Equal(t, params.AdditionalParameters, tp.AdditionalParameters)
 }
 
 func TestSessionTicketInvalidTransportParameters(t *testing.T) {
@@ -641,6 +688,7 @@ func TestTransportParametersValidFor0RTT(t *testing.T) {
 		MaxUniStreamNum:                6,
 		ActiveConnectionIDLimit:        7,
 		MaxDatagramFrameSize:           1000,
+		AdditionalParameters:           map[uint64][]byte{0x1337: []byte("foo")},
 	}
 
 	tests := []struct {
@@ -653,6 +701,18 @@ func TestTransportParametersValidFor0RTT(t *testing.T) {
 			modify: func(p *TransportParameters) {},
 			valid:  true,
 		},
+		{
+			name:   "AdditionalParameters changed",
+			modify: func(p *TransportParameters) { p.AdditionalParameters = map[uint64][]byte{0x1337: []byte("bar")} },
+			valid:  false,
+		},
+		{
+			name: "AdditionalParameters added",
+			modify: func(p *TransportParameters) {
+				p.AdditionalParameters = map[uint64][]byte{0x1337: []byte("foo"), 0x42: nil}
+			},
+			valid: false,
+		},
 		{
 			name: "InitialMaxStreamDataBidiLocal reduced",
 			modify: func(p *TransportParameters) {
@@ -1,11 +1,13 @@
 package wire
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math"
 	"net/netip"
 	"slices"
@@ -50,8 +52,50 @@ const (
 	resetStreamAtParameterID transportParameterID = 0x17f7586d2cb571
 	// https://datatracker.ietf.org/doc/draft-ietf-quic-ack-frequency/11/
 	minAckDelayParameterID transportParameterID = 0xff04de1b
+	// https://datatracker.ietf.org/doc/draft-ietf-quic-multipath/14/
+	initialMaxPathIDParameterID transportParameterID = 0x0f739bbc1b666d0c
 )
 
+// IsReservedTransportParameterID says if the transport parameter ID is reserved,
+// either because it is a transport parameter implemented by quic-go,
+// or because it is reserved for greasing (see section 18.1 of RFC 9000).
+// Reserved IDs can't be used for additional transport parameters.
+func IsReservedTransportParameterID(id uint64) bool {
+	if isGreasedTransportParameterID(id) {
+		return true
+	}
+	switch transportParameterID(id) {
+	case originalDestinationConnectionIDParameterID,
+		maxIdleTimeoutParameterID,
+		statelessResetTokenParameterID,
+		maxUDPPayloadSizeParameterID,
+		initialMaxDataParameterID,
+		initialMaxStreamDataBidiLocalParameterID,
+		initialMaxStreamDataBidiRemoteParameterID,
+		initialMaxStreamDataUniParameterID,
+		initialMaxStreamsBidiParameterID,
+		initialMaxStreamsUniParameterID,
+		ackDelayExponentParameterID,
+		maxAckDelayParameterID,
+		disableActiveMigrationParameterID,
+		preferredAddressParameterID,
+		activeConnectionIDLimitParameterID,
+		initialSourceConnectionIDParameterID,
+		retrySourceConnectionIDParameterID,
+		maxDatagramFrameSizeParameterID,
+		resetStreamAtParameterID,
+		minAckDelayParameterID,
+		initialMaxPathIDParameterID:
+		return true
+	default:
+		return false
+	}
+}
+
+func isGreasedTransportParameterID(id uint64) bool {
+	return id%31 == 27
+}
+
 // PreferredAddress is the value encoding in the preferred_address transport parameter
 type PreferredAddress struct {
 	IPv4, IPv6          netip.AddrPort
@@ -90,6 +134,11 @@ type TransportParameters struct {
 	MaxDatagramFrameSize protocol.ByteCount // RFC 9221
 	EnableResetStreamAt  bool               // https://datatracker.ietf.org/doc/draft-ietf-quic-reliable-stream-reset/06/
 	MinAckDelay          *time.Duration
+	InitialMaxPathID     *protocol.PathID // https://datatracker.ietf.org/doc/draft-ietf-quic-multipath/14/
+
+	// AdditionalParameters are transport parameters that are not implemented by quic-go.
+	// When unmarshaling, all unknown transport parameters (except for greased ones) are stored here.
+	AdditionalParameters map[uint64][]byte
 }
 
 // Unmarshal the transport parameters
@@ -146,7 +195,8 @@ func (p *TransportParameters) unmarshal(b []byte, sentBy protocol.Perspective, f
 			maxDatagramFrameSizeParameterID,
 			ackDelayExponentParameterID,
 			activeConnectionIDLimitParameterID,
-			minAckDelayParameterID:
+			minAckDelayParameterID,
+			initialMaxPathIDParameterID:
 			if err := p.readNumericTransportParameter(b, paramID, int(paramLen)); err != nil {
 				return err
 			}
@@ -211,6 +261,12 @@ func (p *TransportParameters) unmarshal(b []byte, sentBy protocol.Perspective, f
 			}
 			p.EnableResetStreamAt = true
 		default:
+			if !isGreasedTransportParameterID(uint64(paramID)) {
+				if p.AdditionalParameters == nil {
+					p.AdditionalParameters = make(map[uint64][]byte)
+				}
+				p.AdditionalParameters[uint64(paramID)] = slices.Clone(b[:paramLen])
+			}
 			b = b[paramLen:]
 		}
 	}
@@ -229,6 +285,10 @@ func (p *TransportParameters) unmarshal(b []byte, sentBy protocol.Perspective, f
 		if !readInitialSourceConnectionID {
 			return errors.New("missing initial_source_connection_id")
 		}
+		// multipath requires non-zero-length connection IDs
+		if p.InitialMaxPathID != nil && p.InitialSourceConnectionID.Len() == 0 {
+			return errors.New("initial_max_path_id sent with a zero-length connection ID")
+		}
 	}
 
 	// check that every transport parameter was sent at most once
@@ -344,6 +404,12 @@ func (p *TransportParameters) readNumericTransportParameter(b []byte, paramID tr
 			mad = math.MaxInt64
 		}
 		p.MinAckDelay = &mad
+	case initialMaxPathIDParameterID:
+		if val > uint64(protocol.MaxPathID) {
+			return fmt.Errorf("invalid value for initial_max_path_id: %d (maximum %d)", val, protocol.MaxPathID)
+		}
+		id := protocol.PathID(val)
+		p.InitialMaxPathID = &id
 	default:
 		return fmt.Errorf("TransportParameter BUG: transport parameter %d not found", paramID)
 	}
@@ -458,6 +524,11 @@ func (p *TransportParameters) Marshal(pers protocol.Perspective) []byte {
 	if p.MinAckDelay != nil {
 		b = p.marshalVarintParam(b, minAckDelayParameterID, uint64(*p.MinAckDelay/time.Microsecond))
 	}
+	// Multipath Extension for QUIC
+	if p.InitialMaxPathID != nil {
+		b = p.marshalVarintParam(b, initialMaxPathIDParameterID, uint64(*p.InitialMaxPathID))
+	}
+	b = p.marshalAdditionalParameters(b)
 
 	if pers == protocol.PerspectiveClient && len(AdditionalTransportParametersClient) > 0 {
 		for k, v := range AdditionalTransportParametersClient {
@@ -470,6 +541,15 @@ func (p *TransportParameters) Marshal(pers protocol.Perspective) []byte {
 	return b
 }
 
+func (p *TransportParameters) marshalAdditionalParameters(b []byte) []byte {
+	for _, id := range slices.Sorted(maps.Keys(p.AdditionalParameters)) {
+		b = quicvarint.Append(b, id)
+		b = quicvarint.Append(b, uint64(len(p.AdditionalParameters[id])))
+		b = append(b, p.AdditionalParameters[id]...)
+	}
+	return b
+}
+
 func (p *TransportParameters) marshalVarintParam(b []byte, id transportParameterID, val uint64) []byte {
 	b = quicvarint.Append(b, uint64(id))
 	b = quicvarint.Append(b, uint64(quicvarint.Len(val)))
@@ -510,7 +590,8 @@ func (p *TransportParameters) MarshalForSessionTicket(b []byte) []byte {
 		b = quicvarint.Append(b, uint64(resetStreamAtParameterID))
 		b = quicvarint.Append(b, 0)
 	}
-	return b
+	// additional transport parameters
+	return p.marshalAdditionalParameters(b)
 }
 
 // UnmarshalFromSessionTicket unmarshals transport parameters from a session ticket.
@@ -530,6 +611,11 @@ func (p *TransportParameters) ValidFor0RTT(saved *TransportParameters) bool {
 	if saved.MaxDatagramFrameSize != protocol.InvalidByteCount && (p.MaxDatagramFrameSize == protocol.InvalidByteCount || p.MaxDatagramFrameSize < saved.MaxDatagramFrameSize) {
 		return false
 	}
+	// The semantics of additional transport parameters are unknown to quic-go.
+	// The client might rely on them when sending 0-RTT data, so they must not change.
+	if !maps.EqualFunc(p.AdditionalParameters, saved.AdditionalParameters, bytes.Equal) {
+		return false
+	}
 	return p.InitialMaxStreamDataBidiLocal >= saved.InitialMaxStreamDataBidiLocal &&
 		p.InitialMaxStreamDataBidiRemote >= saved.InitialMaxStreamDataBidiRemote &&
 		p.InitialMaxStreamDataUni >= saved.InitialMaxStreamDataUni &&
@@ -578,6 +664,14 @@ func (p *TransportParameters) String() string {
 		logString += ", MinAckDelay: %s"
 		logParams = append(logParams, *p.MinAckDelay)
 	}
+	if p.InitialMaxPathID != nil {
+		logString += ", InitialMaxPathID: %d"
+		logParams = append(logParams, *p.InitialMaxPathID)
+	}
+	if len(p.AdditionalParameters) > 0 {
+		logString += ", AdditionalParameters: %d"
+		logParams = append(logParams, slices.Sorted(maps.Keys(p.AdditionalParameters)))
+	}
 	logString += "}"
 	return fmt.Sprintf(logString, logParams...)
 }
@@ -0,0 +1,43 @@
+package quic
+
+import "sync"
+
+// A KeyRing holds a current key and a list of previous keys.
+// The current key is used to issue new tokens, and all keys are used to validate tokens.
+// Keys can be replaced at any time using SetKeys, allowing keys to be rotated on a live Transport.
+//
+// A *KeyRing[StatelessResetKey] implements the StatelessResetKeyRing,
+// and a *KeyRing[TokenGeneratorKey] implements the TokenGeneratorKeyRing.
+type KeyRing[K StatelessResetKey | TokenGeneratorKey] struct {
+	mx       sync.RWMutex
+	current  K
+	previous []K
+}
+
+// NewKeyRing creates a new key ring.
+func NewKeyRing[K StatelessResetKey | TokenGeneratorKey](current K, previous ...K) *KeyRing[K] {
+	r := &KeyRing[K]{}
+	r.SetKeys(current, previous...)
+	return r
+}
+
+// SetKeys replaces the keys.
+// To rotate keys without invalidating outstanding tokens, the current key
+// should be kept as one of the previous keys for some time.
+func (r *KeyRing[K]) SetKeys(current K, previous ...K) {
+	// copy the slice, so that the caller can't modify it
+	previous = append([]K(nil), previous...)
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.current = current
+	r.previous = previous
+}
+
+// Keys returns the current and the previous keys.
+// The returned slice must not be modified.
+func (r *KeyRing[K]) Keys() (current K, previous []K) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+	return r.current, r.previous
+}
@@ -0,0 +1,22 @@
+package quic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRing(t *testing.T) {
+	r := NewKeyRing(StatelessResetKey{1})
+	current, previous := r.Keys()
+	require.Equal(t, StatelessResetKey{1}, current)
+	require.Empty(t, previous)
+
+	keys := []StatelessResetKey{{2}, {3}}
+	r.SetKeys(StatelessResetKey{4}, keys...)
+	// modifying the slice doesn't change the key ring
+	keys[0] = StatelessResetKey{5}
+	current, previous = r.Keys()
+	require.Equal(t, StatelessResetKey{4}, current)
+	require.Equal(t, []StatelessResetKey{{2}, {3}}, previous)
+}
@@ -0,0 +1,237 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type connectionCollectors struct {
+	connsStarted      *prometheus.CounterVec
+	connsClosed       *prometheus.CounterVec
+	connDuration      *prometheus.HistogramVec
+	handshakeDuration *prometheus.HistogramVec
+	rtt               *prometheus.HistogramVec
+	packetsSent       *prometheus.CounterVec
+	packetsReceived   *prometheus.CounterVec
+	packetsLost       *prometheus.CounterVec
+	packetsDropped    *prometheus.CounterVec
+}
+
+func newConnectionCollectors(registerer prometheus.Registerer) *connectionCollectors {
+	return &connectionCollectors{
+		connsStarted: registerOrGet(registerer, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricNamespace,
+				Name:      "connections_started_total",
+				Help:      "Connections Started",
+			},
+			[]string{"dir"},
+		)),
+		connsClosed: registerOrGet(registerer, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricNamespace,
+				Name:      "connections_closed_total",
+				Help:      "Connections Closed",
+			},
+			[]string{"dir", "reason"},
+		)),
+		connDuration: registerOrGet(registerer, prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: metricNamespace,
+				Name:      "connection_duration_seconds",
+				Help:      "Duration of a Connection",
+				Buckets:   prometheus.ExponentialBuckets(1.0/16, 2, 25), // up to 24 days
+			},
+			[]string{"dir"},
+		)),
+		handshakeDuration: registerOrGet(registerer, prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: metricNamespace,
+				Name:      "handshake_duration_seconds",
+				Help:      "Duration of the QUIC Handshake",
+				Buckets:   prometheus.ExponentialBuckets(0.001, 1.3, 35),
+			},
+			[]string{"dir"},
+		)),
+		rtt: registerOrGet(registerer, prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: metricNamespace,
+				Name:      "connection_rtt_seconds",
+				Help:      "RTT of a Connection, recorded when the connection is closed",
+				Buckets:   prometheus.ExponentialBuckets(0.001, 1.3, 35),
+			},
+			[]string{"dir", "type"},
+		)),
+		packetsSent: registerOrGet(registerer, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricNamespace,
+				Name:      "packets_sent_total",
+				Help:      "Packets Sent",
+			},
+			[]string{"type"},
+		)),
+		packetsReceived: registerOrGet(registerer, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricNamespace,
+				Name:      "packets_received_total",
+				Help:      "Packets Received",
+			},
+			[]string{"type"},
+		)),
+		packetsLost: registerOrGet(registerer, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricNamespace,
+				Name:      "packets_lost_total",
+				Help:      "Packets Lost",
+			},
+			[]string{"type", "reason"},
+		)),
+		packetsDropped: registerOrGet(registerer, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricNamespace,
+				Name:      "packets_dropped_total",
+				Help:      "Packets dropped by a Connection",
+			},
+			[]string{"reason"},
+		)),
+	}
+}
+
+// NewConnectionTracer returns a function that can be used as the quic.Config.Tracer.
+// It registers the metrics with the default Prometheus registerer.
+func NewConnectionTracer() func(context.Context, bool, quic.ConnectionID) qlogwriter.Trace {
+	return NewConnectionTracerWithRegisterer(prometheus.DefaultRegisterer)
+}
+
+// NewConnectionTracerWithRegisterer returns a function that can be used as the quic.Config.Tracer,
+// registering the metrics with the given registerer.
+// Multiple tracers can share the same registerer.
+func NewConnectionTracerWithRegisterer(registerer prometheus.Registerer) func(context.Context, bool, quic.ConnectionID) qlogwriter.Trace {
+	collectors := newConnectionCollectors(registerer)
+	return func(_ context.Context, isClient bool, _ quic.ConnectionID) qlogwriter.Trace {
+		return &connectionTrace{
+			collectors: collectors,
+			dir:        getDirection(isClient),
+		}
+	}
+}
+
+// The connectionTrace collects metrics for a single connection.
+// Events can be recorded by multiple producers (e.g. the QUIC and the HTTP/3 layer).
+// Only events defined by the QUIC event schema are used.
+type connectionTrace struct {
+	collectors *connectionCollectors
+	dir        string
+
+	mx            sync.Mutex
+	numProducers  int
+	startTime     time.Time
+	handshakeDone bool
+	closed        bool
+	minRTT        time.Duration
+	smoothedRTT   time.Duration
+}
+
+var _ qlogwriter.Trace = &connectionTrace{}
+
+func (t *connectionTrace) SupportsSchemas(schema string) bool {
+	return schema == qlog.EventSchema
+}
+
+func (t *connectionTrace) AddProducer() qlogwriter.Recorder {
+	t.mx.Lock()
+	t.numProducers++
+	t.mx.Unlock()
+	return &connectionRecorder{trace: t}
+}
+
+func (t *connectionTrace) recordEvent(ev qlogwriter.Event) {
+	switch ev := ev.(type) {
+	case qlog.PacketSent:
+		t.collectors.packetsSent.WithLabelValues(string(ev.Header.PacketType)).Inc()
+	case qlog.PacketReceived:
+		t.collectors.packetsReceived.WithLabelValues(string(ev.Header.PacketType)).Inc()
+	case qlog.PacketLost:
+		t.collectors.packetsLost.WithLabelValues(string(ev.Header.PacketType), string(ev.Trigger)).Inc()
+	case qlog.PacketDropped:
+		t.collectors.packetsDropped.WithLabelValues(string(ev.Trigger)).Inc()
+	case qlog.StartedConnection:
+		t.mx.Lock()
+		defer t.mx.Unlock()
+		// When a connection is recreated (e.g. after a Retry), the trace is reused.
+		if !t.startTime.IsZero() {
+			return
+		}
+		t.startTime = time.Now()
+		t.collectors.connsStarted.WithLabelValues(t.dir).Inc()
+	case qlog.ALPNInformation:
+		// The ALPN is logged when the handshake completes.
+		t.mx.Lock()
+		defer t.mx.Unlock()
+		if t.handshakeDone || t.startTime.IsZero() {
+			return
+		}
+		t.handshakeDone = true
+		t.collectors.handshakeDuration.WithLabelValues(t.dir).Observe(time.Since(t.startTime).Seconds())
+	case qlog.MetricsUpdated:
+		t.mx.Lock()
+		defer t.mx.Unlock()
+		if ev.MinRTT != 0 {
+			t.minRTT = ev.MinRTT
+		}
+		if ev.SmoothedRTT != 0 {
+			t.smoothedRTT = ev.SmoothedRTT
+		}
+	case qlog.ConnectionClosed:
+		t.mx.Lock()
+		defer t.mx.Unlock()
+		t.close(closeReason(ev))
+	}
+}
+
+func (t *connectionTrace) removeProducer() {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	t.numProducers--
+	// If the connection didn't log a connection_closed event, we still need to record that it was closed.
+	if t.numProducers == 0 {
+		t.close("unknown")
+	}
+}
+
+// close must be called with the mutex held.
+func (t *connectionTrace) close(reason string) {
+	if t.closed || t.startTime.IsZero() {
+		return
+	}
+	t.closed = true
+	t.collectors.connsClosed.WithLabelValues(t.dir, reason).Inc()
+	t.collectors.connDuration.WithLabelValues(t.dir).Observe(time.Since(t.startTime).Seconds())
+	if t.minRTT > 0 {
+		t.collectors.rtt.WithLabelValues(t.dir, "min").Observe(t.minRTT.Seconds())
+	}
+	if t.smoothedRTT > 0 {
+		t.collectors.rtt.WithLabelValues(t.dir, "smoothed").Observe(t.smoothedRTT.Seconds())
+	}
+}
+
+type connectionRecorder struct {
+	trace *connectionTrace
+	once  sync.Once
+}
+
+var _ qlogwriter.Recorder = &connectionRecorder{}
+
+func (r *connectionRecorder) RecordEvent(ev qlogwriter.Event) { r.trace.recordEvent(ev) }
+
+func (r *connectionRecorder) Close() error {
+	r.once.Do(r.trace.removeProducer)
+	return nil
+}
@@ -0,0 +1,74 @@
+// Package metrics exports Prometheus metrics for QUIC connections and transports.
+//
+// The metrics are collected from qlog events: NewTracer returns a qlogwriter.Recorder
+// to be used as the quic.Transport.Tracer, and NewConnectionTracer returns a function
+// to be used as the quic.Config.Tracer.
+//
+// The metrics are designed to be used with the Grafana dashboard in the dashboards directory.
+package metrics
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/quic-go/quic-go/qlog"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricNamespace = "quicgo"
+
+const (
+	dirIncoming = "incoming"
+	dirOutgoing = "outgoing"
+)
+
+func getDirection(isClient bool) string {
+	if isClient {
+		return dirOutgoing
+	}
+	return dirIncoming
+}
+
+// registerOrGet registers the collector with the registerer.
+// If an identical collector was already registered, the existing collector is returned,
+// allowing multiple tracers to be created with the same registerer.
+func registerOrGet[T prometheus.Collector](registerer prometheus.Registerer, c T) T {
+	if err := registerer.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
+
+// closeReason returns a low-cardinality label for the reason a connection was closed.
+func closeReason(e qlog.ConnectionClosed) string {
+	if e.Trigger != "" {
+		return string(e.Trigger)
+	}
+	switch {
+	case e.ApplicationError != nil:
+		return "application_error"
+	case e.ConnectionError != nil:
+		if e.ConnectionError.IsCryptoError() {
+			return "crypto_error"
+		}
+		return transportErrorLabel(*e.ConnectionError)
+	default:
+		return "unknown"
+	}
+}
+
+func transportErrorLabel(code qlog.TransportErrorCode) string {
+	s := code.String()
+	// Unknown error codes are aggregated to limit the cardinality of the label.
+	if strings.HasPrefix(s, "unknown") {
+		return "unknown_transport_error"
+	}
+	return strings.ToLower(s)
+}
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/internal/testdata"
+	"github.com/quic-go/quic-go/qlog"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracerDroppedPackets(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	tr := NewTracerWithRegisterer(registry)
+	tr.RecordEvent(qlog.PacketDropped{Trigger: qlog.PacketDropUnknownConnectionID})
+	tr.RecordEvent(qlog.PacketDropped{Trigger: qlog.PacketDropUnknownConnectionID})
+	tr.RecordEvent(qlog.PacketDropped{Trigger: qlog.PacketDropDOSPrevention})
+
+	c := tr.(*tracer).packetsDropped
+	require.Equal(t, 2.0, testutil.ToFloat64(c.WithLabelValues("unknown_connection_id")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.WithLabelValues("dos_prevention")))
+}
+
+func TestTracerRejectedConnections(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	tr := NewTracerWithRegisterer(registry)
+	tr.RecordEvent(qlog.PacketSent{
+		Header: qlog.PacketHeader{PacketType: qlog.PacketTypeInitial},
+		Frames: []qlog.Frame{{Frame: &qlog.ConnectionCloseFrame{ErrorCode: uint64(quic.ConnectionRefused)}}},
+	})
+	tr.RecordEvent(qlog.PacketSent{
+		Header: qlog.PacketHeader{PacketType: qlog.PacketTypeInitial},
+		Frames: []qlog.Frame{{Frame: &qlog.ConnectionCloseFrame{ErrorCode: uint64(quic.InvalidToken)}}},
+	})
+	// Retry packets are not rejections
+	tr.RecordEvent(qlog.PacketSent{Header: qlog.PacketHeader{PacketType: qlog.PacketTypeRetry}})
+
+	c := tr.(*tracer).connsRejected
+	require.Equal(t, 2, testutil.CollectAndCount(c))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.WithLabelValues("connection_refused")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.WithLabelValues("invalid_token")))
+}
+
+func TestTracerAdmissionRejectedConnections(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	tr := NewTracerWithRegisterer(registry)
+	tr.RecordEvent(qlog.ConnectionRejected{Reason: qlog.ConnectionRejectionMaxHandshakes, Retry: true})
+	tr.RecordEvent(qlog.ConnectionRejected{Reason: qlog.ConnectionRejectionMaxHandshakes})
+	tr.RecordEvent(qlog.ConnectionRejected{Reason: qlog.ConnectionRejectionRateLimit})
+	tr.RecordEvent(qlog.ConnectionRejected{Reason: qlog.ConnectionRejectionRateLimit})
+
+	c := tr.(*tracer).connsAdmissionRejected
+	require.Equal(t, 3, testutil.CollectAndCount(c))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.WithLabelValues("max_handshakes", "retry")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.WithLabelValues("max_handshakes", "refuse")))
+	require.Equal(t, 2.0, testutil.ToFloat64(c.WithLabelValues("rate_limit", "refuse")))
+}
+
+func TestTracerSharedRegisterer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	tr1 := NewTracerWithRegisterer(registry)
+	tr2 := NewTracerWithRegisterer(registry)
+	tr1.RecordEvent(qlog.PacketDropped{Trigger: qlog.PacketDropHeaderParseError})
+	tr2.RecordEvent(qlog.PacketDropped{Trigger: qlog.PacketDropHeaderParseError})
+	require.Equal(t, 2.0, testutil.ToFloat64(tr1.(*tracer).packetsDropped.WithLabelValues("header_parse_error")))
+
+	NewConnectionTracerWithRegisterer(registry)
+	NewConnectionTracerWithRegisterer(registry)
+}
+
+func TestConnectionTracer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	trace := NewConnectionTracerWithRegisterer(registry)(context.Background(), false, quic.ConnectionID{})
+	collectors := trace.(*connectionTrace).collectors
+	r := trace.AddProducer()
+
+	r.RecordEvent(qlog.StartedConnection{})
+	require.Equal(t, 1.0, testutil.ToFloat64(collectors.connsStarted.WithLabelValues(dirIncoming)))
+	r.RecordEvent(qlog.PacketReceived{Header: qlog.PacketHeader{PacketType: qlog.PacketTypeInitial}})
+	r.RecordEvent(qlog.PacketSent{Header: qlog.PacketHeader{PacketType: qlog.PacketTypeHandshake}})
+	r.RecordEvent(qlog.PacketSent{Header: qlog.PacketHeader{PacketType: qlog.PacketTypeHandshake}})
+	r.RecordEvent(qlog.ALPNInformation{ChosenALPN: "h3"})
+	r.RecordEvent(qlog.MetricsUpdated{MinRTT: 10 * time.Millisecond, SmoothedRTT: 15 * time.Millisecond})
+	r.RecordEvent(qlog.PacketLost{Header: qlog.PacketHeader{PacketType: qlog.PacketType1RTT}, Trigger: qlog.PacketLossTimeThreshold})
+	r.RecordEvent(qlog.PacketDropped{Trigger: qlog.PacketDropDuplicate})
+	require.Equal(t, 1.0, testutil.ToFloat64(collectors.packetsReceived.WithLabelValues("initial")))
+	require.Equal(t, 2.0, testutil.ToFloat64(collectors.packetsSent.WithLabelValues("handshake")))
+	require.Equal(t, 1.0, testutil.ToFloat64(collectors.packetsLost.WithLabelValues("1RTT", "time_threshold")))
+	require.Equal(t, 1.0, testutil.ToFloat64(collectors.packetsDropped.WithLabelValues("duplicate")))
+	require.Equal(t, 1, testutil.CollectAndCount(collectors.handshakeDuration))
+	require.Zero(t, testutil.CollectAndCount(collectors.connDuration))
+
+	code := quic.ApplicationErrorCode(42)
+	r.RecordEvent(qlog.ConnectionClosed{Initiator: qlog.InitiatorRemote, ApplicationError: &code})
+	require.NoError(t, r.Close())
+	require.Equal(t, 1.0, testutil.ToFloat64(collectors.connsClosed.WithLabelValues(dirIncoming, "application_error")))
+	require.Equal(t, 1, testutil.CollectAndCount(collectors.connDuration))
+	require.Equal(t, 2, testutil.CollectAndCount(collectors.rtt))
+	// the connection is only counted as closed once
+	require.Equal(t, 1, testutil.CollectAndCount(collectors.connsClosed))
+}
+
+func TestConnectionTracerCloseReasons(t *testing.T) {
+	protocolViolation := quic.ProtocolViolation
+	cryptoError := quic.TransportErrorCode(0x100 + 42)
+	unknownError := quic.TransportErrorCode(0x1337)
+
+	for _, tc := range []struct {
+		name   string
+		event  *qlog.ConnectionClosed
+		reason string
+	}{
+		{name: "idle timeout", event: &qlog.ConnectionClosed{Trigger: qlog.ConnectionCloseTriggerIdleTimeout}, reason: "idle_timeout"},
+		{name: "stateless reset", event: &qlog.ConnectionClosed{Trigger: qlog.ConnectionCloseTriggerStatelessReset}, reason: "stateless_reset"},
+		{name: "transport error", event: &qlog.ConnectionClosed{ConnectionError: &protocolViolation}, reason: "protocol_violation"},
+		{name: "crypto error", event: &qlog.ConnectionClosed{ConnectionError: &cryptoError}, reason: "crypto_error"},
+		{name: "unknown transport error", event: &qlog.ConnectionClosed{ConnectionError: &unknownError}, reason: "unknown_transport_error"},
+		{name: "no connection_closed event", reason: "unknown"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			trace := NewConnectionTracerWithRegisterer(registry)(context.Background(), true, quic.ConnectionID{})
+			collectors := trace.(*connectionTrace).collectors
+			r := trace.AddProducer()
+			r.RecordEvent(qlog.StartedConnection{})
+			if tc.event != nil {
+				r.RecordEvent(*tc.event)
+			}
+			require.NoError(t, r.Close())
+			require.Equal(t, 1, testutil.CollectAndCount(collectors.connsClosed))
+			require.Equal(t, 1.0, testutil.ToFloat64(collectors.connsClosed.WithLabelValues(dirOutgoing, tc.reason)))
+		})
+	}
+}
+
+func TestConnectionTracerHandshake(t *testing.T) {
+	serverTLSConf := testdata.GetTLSConfig()
+	serverTLSConf.NextProtos = []string{"metrics"}
+	clientTLSConf := &tls.Config{RootCAs: testdata.GetRootCA(), ServerName: "localhost", NextProtos: []string{"metrics"}}
+
+	registry := prometheus.NewRegistry()
+	connTracer := NewConnectionTracerWithRegisterer(registry)
+
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0})
+	require.NoError(t, err)
+	tr := &quic.Transport{Conn: udpConn, Tracer: NewTracerWithRegisterer(registry)}
+	defer tr.Close()
+	ln, err := tr.Listen(serverTLSConf, &quic.Config{Tracer: connTracer})
+	require.NoError(t, err)
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := quic.DialAddr(ctx, ln.Addr().String(), clientTLSConf, &quic.Config{Tracer: connTracer})
+	require.NoError(t, err)
+	serverConn, err := ln.Accept(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, conn.CloseWithError(0, ""))
+	select {
+	case <-serverConn.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	count := func(name string) int {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, f := range families {
+			if f.GetName() == name {
+				var n int
+				for _, m := range f.GetMetric() {
+					switch {
+					case m.GetCounter() != nil:
+						n += int(m.GetCounter().GetValue())
+					case m.GetHistogram() != nil:
+						n += int(m.GetHistogram().GetSampleCount())
+					}
+				}
+				return n
+			}
+		}
+		return 0
+	}
+	require.Equal(t, 2, count("quicgo_connections_started_total"))
+	require.Eventually(t, func() bool { return count("quicgo_connections_closed_total") == 2 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, 2, count("quicgo_handshake_duration_seconds"))
+	require.Equal(t, 2, count("quicgo_connection_duration_seconds"))
+	require.NotZero(t, count("quicgo_packets_sent_total"))
+	require.NotZero(t, count("quicgo_packets_received_total"))
+}
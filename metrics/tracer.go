@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type tracer struct {
+	connsRejected          *prometheus.CounterVec
+	connsAdmissionRejected *prometheus.CounterVec
+	packetsDropped         *prometheus.CounterVec
+}
+
+var _ qlogwriter.Recorder = &tracer{}
+
+// NewTracer returns a qlogwriter.Recorder that can be used as the quic.Transport.Tracer.
+// It registers the metrics with the default Prometheus registerer.
+func NewTracer() qlogwriter.Recorder {
+	return NewTracerWithRegisterer(prometheus.DefaultRegisterer)
+}
+
+// NewTracerWithRegisterer returns a qlogwriter.Recorder that can be used as the quic.Transport.Tracer,
+// registering the metrics with the given registerer.
+// Multiple tracers can share the same registerer.
+func NewTracerWithRegisterer(registerer prometheus.Registerer) qlogwriter.Recorder {
+	return &tracer{
+		connsRejected: registerOrGet(registerer, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricNamespace,
+				Name:      "server_connections_rejected_total",
+				Help:      "Connections Rejected",
+			},
+			[]string{"reason"},
+		)),
+		connsAdmissionRejected: registerOrGet(registerer, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricNamespace,
+				Name:      "server_connections_admission_rejected_total",
+				Help:      "Connections rejected by admission control",
+			},
+			[]string{"reason", "action"},
+		)),
+		packetsDropped: registerOrGet(registerer, prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricNamespace,
+				Name:      "server_received_packets_dropped_total",
+				Help:      "packets dropped",
+			},
+			[]string{"reason"},
+		)),
+	}
+}
+
+func (t *tracer) RecordEvent(ev qlogwriter.Event) {
+	switch ev := ev.(type) {
+	case qlog.PacketDropped:
+		t.packetsDropped.WithLabelValues(string(ev.Trigger)).Inc()
+	case qlog.ConnectionRejected:
+		action := "refuse"
+		if ev.Retry {
+			action = "retry"
+		}
+		t.connsAdmissionRejected.WithLabelValues(string(ev.Reason), action).Inc()
+	case qlog.PacketSent:
+		// The server rejects connections by sending a CONNECTION_CLOSE frame in an Initial packet,
+		// before a connection is created.
+		if ev.Header.PacketType != qlog.PacketTypeInitial {
+			return
+		}
+		for _, f := range ev.Frames {
+			if ccf, ok := f.Frame.(*qlog.ConnectionCloseFrame); ok && !ccf.IsApplicationError {
+				t.connsRejected.WithLabelValues(transportErrorLabel(qlog.TransportErrorCode(ccf.ErrorCode))).Inc()
+			}
+		}
+	}
+}
+
+func (t *tracer) Close() error { return nil }
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	ackhandler "github.com/quic-go/quic-go/internal/ackhandler"
+	monotime "github.com/quic-go/quic-go/internal/monotime"
 	protocol "github.com/quic-go/quic-go/internal/protocol"
 	wire "github.com/quic-go/quic-go/internal/wire"
 	gomock "go.uber.org/mock/gomock"
@@ -43,9 +44,9 @@ func (m *MockStreamFrameGetter) EXPECT() *MockStreamFrameGetterMockRecorder {
 }
 
 // popStreamFrame mocks base method.
-func (m *MockStreamFrameGetter) popStreamFrame(arg0 protocol.ByteCount, arg1 protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
+func (m *MockStreamFrameGetter) popStreamFrame(arg0 protocol.ByteCount, arg1 monotime.Time, arg2 protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "popStreamFrame", arg0, arg1)
+	ret := m.ctrl.Call(m, "popStreamFrame", arg0, arg1, arg2)
 	ret0, _ := ret[0].(ackhandler.StreamFrame)
 	ret1, _ := ret[1].(*wire.StreamDataBlockedFrame)
 	ret2, _ := ret[2].(bool)
@@ -53,9 +54,9 @@ func (m *MockStreamFrameGetter) popStreamFrame(arg0 protocol.ByteCount, arg1 pro
 }
 
 // popStreamFrame indicates an expected call of popStreamFrame.
-func (mr *MockStreamFrameGetterMockRecorder) popStreamFrame(arg0, arg1 any) *MockStreamFrameGetterpopStreamFrameCall {
+func (mr *MockStreamFrameGetterMockRecorder) popStreamFrame(arg0, arg1, arg2 any) *MockStreamFrameGetterpopStreamFrameCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "popStreamFrame", reflect.TypeOf((*MockStreamFrameGetter)(nil).popStreamFrame), arg0, arg1)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "popStreamFrame", reflect.TypeOf((*MockStreamFrameGetter)(nil).popStreamFrame), arg0, arg1, arg2)
 	return &MockStreamFrameGetterpopStreamFrameCall{Call: call}
 }
 
@@ -71,13 +72,13 @@ func (c *MockStreamFrameGetterpopStreamFrameCall) Return(arg0 ackhandler.StreamF
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockStreamFrameGetterpopStreamFrameCall) Do(f func(protocol.ByteCount, protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool)) *MockStreamFrameGetterpopStreamFrameCall {
+func (c *MockStreamFrameGetterpopStreamFrameCall) Do(f func(protocol.ByteCount, monotime.Time, protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool)) *MockStreamFrameGetterpopStreamFrameCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockStreamFrameGetterpopStreamFrameCall) DoAndReturn(f func(protocol.ByteCount, protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool)) *MockStreamFrameGetterpopStreamFrameCall {
+func (c *MockStreamFrameGetterpopStreamFrameCall) DoAndReturn(f func(protocol.ByteCount, monotime.Time, protocol.Version) (ackhandler.StreamFrame, *wire.StreamDataBlockedFrame, bool)) *MockStreamFrameGetterpopStreamFrameCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -0,0 +1,113 @@
+package quic
+
+import (
+	"net"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A PathID identifies a path of a multipath connection.
+// The path used during the handshake has the path ID 0.
+type PathID = protocol.PathID
+
+// PathStatus is the status of a path of a multipath connection.
+type PathStatus uint8
+
+const (
+	// PathStatusAvailable is the status of a path that can be used to send packets.
+	PathStatusAvailable PathStatus = iota
+	// PathStatusBackup is the status of a path that is only used if no available path exists.
+	PathStatusBackup
+)
+
+func (s PathStatus) String() string {
+	switch s {
+	case PathStatusAvailable:
+		return "available"
+	case PathStatusBackup:
+		return "backup"
+	default:
+		return "unknown path status"
+	}
+}
+
+// PathInfo contains information about a path of a multipath connection.
+type PathInfo struct {
+	ID PathID
+	// Status is the status of the path.
+	// A path is a backup path if either endpoint marked it as a backup path.
+	Status     PathStatus
+	LocalAddr  net.Addr
+	RemoteAddr net.Addr
+	// SmoothedRTT is the smoothed RTT of the path.
+	SmoothedRTT time.Duration
+	// CanSend says if congestion control and pacing allow sending a packet on this path.
+	CanSend bool
+}
+
+// A PathScheduler decides which path a packet is sent on.
+// It is only used if the multipath extension was negotiated (see Config.EnableMultipath).
+//
+// SelectPath is called from the connection's run loop every time a packet is about to be sent.
+// It must not block, and it must not call any methods on the connection.
+type PathScheduler interface {
+	// SelectPath returns the index of the path that the next packet is sent on.
+	// Only paths that completed path validation are passed to the scheduler.
+	// If it returns -1, or the index of a path that can't send (see PathInfo.CanSend),
+	// no packet is sent until the connection's state changes (e.g. when an ACK is received).
+	SelectPath(paths []PathInfo) int
+}
+
+// The minRTTPathScheduler is the default PathScheduler.
+// It sends packets on the path with the lowest RTT that congestion control allows sending on.
+// Backup paths are only used if no path is available.
+type minRTTPathScheduler struct{}
+
+var _ PathScheduler = minRTTPathScheduler{}
+
+func (minRTTPathScheduler) SelectPath(paths []PathInfo) int {
+	var hasAvailablePath bool
+	for _, p := range paths {
+		if p.Status == PathStatusAvailable {
+			hasAvailablePath = true
+			break
+		}
+	}
+	best := -1
+	for i, p := range paths {
+		if !p.CanSend || (hasAvailablePath && p.Status != PathStatusAvailable) {
+			continue
+		}
+		if best == -1 || p.SmoothedRTT < paths[best].SmoothedRTT {
+			best = i
+		}
+	}
+	return best
+}
+
+// SetPathScheduler sets the PathScheduler used to decide which path a packet is sent on.
+// It only has an effect if the multipath extension was negotiated (see Config.EnableMultipath).
+// If no scheduler is set, packets are sent on the path with the lowest RTT,
+// and backup paths are only used if no other path is available.
+func (c *Conn) SetPathScheduler(s PathScheduler) {
+	if s == nil {
+		c.pathScheduler.Store(nil)
+		return
+	}
+	c.pathScheduler.Store(&s)
+}
+
+// SetStatus sets the status of the path.
+// It only has an effect if the multipath extension was negotiated (see Config.EnableMultipath).
+// The peer is informed about the status change, and also stops sending on backup paths
+// as long as other paths are available.
+func (p *Path) SetStatus(s PathStatus) error {
+	select {
+	case <-p.abandon:
+		return ErrPathClosed
+	default:
+	}
+	p.pathManager.setPathStatus(p.id, s)
+	return nil
+}
@@ -0,0 +1,157 @@
+package quic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/monotime"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/internal/wire"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMinRTTPathScheduler(t *testing.T) {
+	var s minRTTPathScheduler
+
+	require.Equal(t, -1, s.SelectPath(nil))
+	require.Equal(t, 1, s.SelectPath([]PathInfo{
+		{ID: 0, SmoothedRTT: 20 * time.Millisecond, CanSend: true},
+		{ID: 1, SmoothedRTT: 10 * time.Millisecond, CanSend: true},
+		{ID: 2, SmoothedRTT: 30 * time.Millisecond, CanSend: true},
+	}))
+	// paths that can't send are skipped
+	require.Equal(t, 0, s.SelectPath([]PathInfo{
+		{ID: 0, SmoothedRTT: 20 * time.Millisecond, CanSend: true},
+		{ID: 1, SmoothedRTT: 10 * time.Millisecond, CanSend: false},
+	}))
+	require.Equal(t, -1, s.SelectPath([]PathInfo{
+		{ID: 0, SmoothedRTT: 20 * time.Millisecond, CanSend: false},
+		{ID: 1, SmoothedRTT: 10 * time.Millisecond, CanSend: false},
+	}))
+	// backup paths are only used if there's no available path
+	require.Equal(t, 0, s.SelectPath([]PathInfo{
+		{ID: 0, SmoothedRTT: 20 * time.Millisecond, CanSend: true},
+		{ID: 1, SmoothedRTT: 10 * time.Millisecond, CanSend: true, Status: PathStatusBackup},
+	}))
+	// ... even if the available path is congestion limited
+	require.Equal(t, -1, s.SelectPath([]PathInfo{
+		{ID: 0, SmoothedRTT: 20 * time.Millisecond, CanSend: false},
+		{ID: 1, SmoothedRTT: 10 * time.Millisecond, CanSend: true, Status: PathStatusBackup},
+	}))
+	require.Equal(t, 1, s.SelectPath([]PathInfo{
+		{ID: 0, SmoothedRTT: 30 * time.Millisecond, CanSend: true, Status: PathStatusBackup},
+		{ID: 1, SmoothedRTT: 20 * time.Millisecond, CanSend: true, Status: PathStatusBackup},
+		{ID: 2, SmoothedRTT: 10 * time.Millisecond, CanSend: false, Status: PathStatusBackup},
+	}))
+}
+
+func TestMultipathManagerConnectionIDs(t *testing.T) {
+	m := newMultipathManager(3, 3, &multipathPath{})
+	var queued []wire.Frame
+	queueControlFrame := func(f wire.Frame) { queued = append(queued, f) }
+	var addedTokens, removedTokens []protocol.StatelessResetToken
+	addToken := func(t protocol.StatelessResetToken) { addedTokens = append(addedTokens, t) }
+	removeToken := func(t protocol.StatelessResetToken) { removedTokens = append(removedTokens, t) }
+
+	newFrame := func(seq, retirePriorTo uint64) *wire.PathNewConnectionIDFrame {
+		return &wire.PathNewConnectionIDFrame{
+			PathID:              1,
+			SequenceNumber:      seq,
+			RetirePriorTo:       retirePriorTo,
+			ConnectionID:        protocol.ParseConnectionID([]byte{byte(seq), 1, 2, 3}),
+			StatelessResetToken: protocol.StatelessResetToken{byte(seq)},
+		}
+	}
+
+	_, ok := m.popConnID(1)
+	require.False(t, ok)
+	require.NoError(t, m.AddConnectionID(newFrame(1, 0), queueControlFrame, addToken, removeToken))
+	require.NoError(t, m.AddConnectionID(newFrame(0, 0), queueControlFrame, addToken, removeToken))
+	// duplicates are ignored
+	require.NoError(t, m.AddConnectionID(newFrame(0, 0), queueControlFrame, addToken, removeToken))
+	// conflicting connection IDs are rejected
+	f := newFrame(0, 0)
+	f.ConnectionID = protocol.ParseConnectionID([]byte{4, 3, 2, 1})
+	require.Error(t, m.AddConnectionID(f, queueControlFrame, addToken, removeToken))
+	require.True(t, m.hasConnID(1))
+	require.False(t, m.hasConnID(2))
+
+	// connection IDs are used in order
+	m.paths[1] = &multipathPath{id: 1, destConnIDSeq: 0, statelessResetToken: protocol.StatelessResetToken{0}}
+	entry, ok := m.popConnID(1)
+	require.True(t, ok)
+	require.Equal(t, uint64(0), entry.SequenceNumber)
+	require.Empty(t, queued)
+
+	// retiring the connection ID in use switches to a new connection ID
+	require.NoError(t, m.AddConnectionID(newFrame(2, 2), queueControlFrame, addToken, removeToken))
+	require.Equal(t, []wire.Frame{
+		&wire.PathRetireConnectionIDFrame{PathID: 1, SequenceNumber: 1},
+		&wire.PathRetireConnectionIDFrame{PathID: 1, SequenceNumber: 0},
+	}, queued)
+	require.Equal(t, uint64(2), m.paths[1].destConnIDSeq)
+	require.Equal(t, protocol.ParseConnectionID([]byte{2, 1, 2, 3}), m.paths[1].destConnID)
+	require.Equal(t, []protocol.StatelessResetToken{{0}}, removedTokens)
+	require.Equal(t, []protocol.StatelessResetToken{{2}}, addedTokens)
+
+	// connection IDs below retire_prior_to are retired immediately
+	queued = queued[:0]
+	require.NoError(t, m.AddConnectionID(newFrame(1, 0), queueControlFrame, addToken, removeToken))
+	require.Equal(t, []wire.Frame{&wire.PathRetireConnectionIDFrame{PathID: 1, SequenceNumber: 1}}, queued)
+
+	// too many connection IDs
+	var err error
+	for i := uint64(3); i < 3+protocol.MaxActiveConnectionIDs; i++ {
+		if err = m.AddConnectionID(newFrame(i, 0), queueControlFrame, addToken, removeToken); err != nil {
+			break
+		}
+	}
+	require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.ConnectionIDLimitError})
+
+	// for paths that haven't been opened yet, all active connection IDs are queued
+	for i := uint64(0); i < protocol.MaxActiveConnectionIDs; i++ {
+		f := newFrame(i, 0)
+		f.PathID = 3
+		require.NoError(t, m.AddConnectionID(f, queueControlFrame, addToken, removeToken))
+	}
+	f = newFrame(protocol.MaxActiveConnectionIDs, 0)
+	f.PathID = 3
+	require.ErrorIs(t,
+		m.AddConnectionID(f, queueControlFrame, addToken, removeToken),
+		&qerr.TransportError{ErrorCode: qerr.ConnectionIDLimitError},
+	)
+
+	// connection IDs for closed paths are ignored
+	delete(m.unabandonedPaths, 2)
+	f = newFrame(0, 0)
+	f.PathID = 2
+	require.NoError(t, m.AddConnectionID(f, queueControlFrame, addToken, removeToken))
+	require.False(t, m.hasConnID(2))
+}
+
+func TestMultipathAbandonUnusedPaths(t *testing.T) {
+	tc := newServerTestConnection(t, nil, nil, false)
+	tc.connRunner.EXPECT().Add(gomock.Any(), gomock.Any()).AnyTimes()
+	tc.conn.enableMultipath(protocol.MaxMultipathPathID)
+	m := tc.conn.multipath
+	maxPathID := m.localMaxPathID
+
+	// Abandoning paths that were never opened doesn't allow the peer to open new paths.
+	// Otherwise, the peer could make us keep an unbounded amount of state.
+	for range 10 {
+		for id := protocol.PathID(1); id <= maxPathID; id++ {
+			require.NoError(t, tc.conn.handlePathAbandonFrame(&wire.PathAbandonFrame{PathID: id}, monotime.Now()))
+			require.True(t, m.isAbandoned(id))
+		}
+	}
+	require.Equal(t, maxPathID, m.localMaxPathID)
+	require.Empty(t, m.unabandonedPaths)
+
+	require.ErrorIs(t,
+		tc.conn.handlePathAbandonFrame(&wire.PathAbandonFrame{PathID: maxPathID + 1}, monotime.Now()),
+		&qerr.TransportError{ErrorCode: qerr.ProtocolViolation},
+	)
+}
@@ -134,6 +134,11 @@ type packetPacker struct {
 	retransmissionQueue *retransmissionQueue
 	rand                rand.Rand
 
+	// The ID of the path that this packer is used for.
+	// On paths other than the initial path of a multipath connection,
+	// acknowledgments are sent in PATH_ACK frames.
+	pathID protocol.PathID
+
 	numNonAckElicitingAcks int
 }
 
@@ -635,7 +640,7 @@ func (p *packetPacker) composeNextPacket(
 ) payload {
 	if onlyAck {
 		if ack := p.acks.GetAckFrame(protocol.Encryption1RTT, now, true); ack != nil {
-			return payload{ack: ack, length: ack.Length(v)}
+			return payload{ack: ack, length: p.ackFrameLength(ack, v)}
 		}
 		return payload{}
 	}
@@ -648,7 +653,7 @@ func (p *packetPacker) composeNextPacket(
 	if ackAllowed {
 		if ack := p.acks.GetAckFrame(protocol.Encryption1RTT, now, !hasRetransmission && !hasData); ack != nil {
 			pl.ack = ack
-			pl.length += ack.Length(v)
+			pl.length += p.ackFrameLength(ack, v)
 			hasAck = true
 		}
 	}
@@ -657,14 +662,13 @@ func (p *packetPacker) composeNextPacket(
 		if f := p.datagramQueue.Peek(); f != nil {
 			size := f.Length(v)
 			if size <= maxPayloadSize-pl.length { // DATAGRAM frame fits
-				pl.frames = append(pl.frames, ackhandler.Frame{Frame: f})
+				pl.frames = append(pl.frames, ackhandler.Frame{Frame: f, Handler: p.datagramQueue.Pop()})
 				pl.length += size
-				p.datagramQueue.Pop()
 			} else if !hasAck {
 				// The DATAGRAM frame doesn't fit, and the packet doesn't contain an ACK.
 				// Discard this frame. There's no point in retrying this in the next packet,
 				// as it's unlikely that the available packet size will increase.
-				p.datagramQueue.Pop()
+				p.datagramQueue.Discard()
 			}
 			// If the DATAGRAM frame was too large and the packet contained an ACK, we'll try to send it out later.
 		}
@@ -703,6 +707,9 @@ func (p *packetPacker) composeNextPacket(
 			case *wire.PathChallengeFrame, *wire.PathResponseFrame:
 				// Path probing is currently not supported, therefore we don't need to set the OnAcked callback yet.
 				// PATH_CHALLENGE and PATH_RESPONSE are never retransmitted.
+			case *wire.ImmediateAckFrame:
+				// IMMEDIATE_ACK is only useful when it arrives in time, and is never retransmitted.
+				pl.frames[i].Handler = emptyHandler{}
 			default:
 				// we might be packing a 0-RTT packet, but we need to use the 1-RTT ack handler anyway
 				pl.frames[i].Handler = p.retransmissionQueue.AckHandler(protocol.Encryption1RTT)
@@ -952,13 +959,24 @@ func (p *packetPacker) appendShortHeaderPacket(
 	}, nil
 }
 
+func (p *packetPacker) ackFrameLength(ack *wire.AckFrame, v protocol.Version) protocol.ByteCount {
+	if p.pathID != 0 {
+		return (&wire.PathAckFrame{PathID: p.pathID, AckFrame: *ack}).Length(v)
+	}
+	return ack.Length(v)
+}
+
 // appendPacketPayload serializes the payload of a packet into the raw byte slice.
 // It modifies the order of payload.frames.
 func (p *packetPacker) appendPacketPayload(raw []byte, pl payload, paddingLen protocol.ByteCount, v protocol.Version) ([]byte, error) {
 	payloadOffset := len(raw)
 	if pl.ack != nil {
 		var err error
-		raw, err = pl.ack.Append(raw, v)
+		if p.pathID != 0 {
+			raw, err = (&wire.PathAckFrame{PathID: p.pathID, AckFrame: *pl.ack}).Append(raw, v)
+		} else {
+			raw, err = pl.ack.Append(raw, v)
+		}
 		if err != nil {
 			return nil, err
 		}
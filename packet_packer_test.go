@@ -43,7 +43,7 @@ func newTestPacketPacker(t *testing.T, mockCtrl *gomock.Controller, pers protoco
 	framer := NewMockFrameSource(mockCtrl)
 	ackFramer := NewMockAckFrameSource(mockCtrl)
 	sealingManager := NewMockSealingManager(mockCtrl)
-	datagramQueue := newDatagramQueue(func() {}, utils.DefaultLogger)
+	datagramQueue := newDatagramQueue(func() {}, populateConfig(nil), utils.DefaultLogger)
 	retransmissionQueue := newRetransmissionQueue()
 	return &testPacketPacker{
 		pnManager:           pnManager,
@@ -524,6 +524,34 @@ func TestPack1RTTPacketWithACK(t *testing.T) {
 	require.Equal(t, ack, p.Ack)
 }
 
+func TestPack1RTTPacketWithPathACK(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	tp := newTestPacketPacker(t, mockCtrl, protocol.PerspectiveServer)
+	tp.packer.pathID = 3
+	tp.pnManager.EXPECT().PeekPacketNumber(protocol.Encryption1RTT).Return(protocol.PacketNumber(0x42), protocol.PacketNumberLen2)
+	tp.pnManager.EXPECT().PopPacketNumber(protocol.Encryption1RTT).Return(protocol.PacketNumber(0x42))
+	sealer := newMockShortHeaderSealer(mockCtrl)
+	tp.sealingManager.EXPECT().Get1RTTSealer().Return(sealer, nil)
+	ack := &wire.AckFrame{AckRanges: []wire.AckRange{{Largest: 42, Smallest: 1}}}
+	tp.ackFramer.EXPECT().GetAckFrame(protocol.Encryption1RTT, gomock.Any(), true).Return(ack)
+	p, buffer, err := tp.packer.PackAckOnlyPacket(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, ack, p.Ack)
+
+	// the acknowledgment is serialized as a PATH_ACK frame
+	l, _, _, _, err := wire.ParseShortHeader(buffer.Data, testPackerConnIDLen)
+	require.NoError(t, err)
+	payload := buffer.Data[l : len(buffer.Data)-sealer.Overhead()]
+	parser := wire.NewFrameParser(false, false, false, true)
+	frameType, n, err := parser.ParseType(payload, protocol.Encryption1RTT)
+	require.NoError(t, err)
+	require.True(t, frameType.IsPathAckFrameType())
+	pathAck, _, err := parser.ParsePathAckFrame(frameType, payload[n:], protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, protocol.PathID(3), pathAck.PathID)
+	require.Equal(t, ack.AckRanges, pathAck.AckRanges)
+}
+
 func TestPackPathChallengeAndPathResponse(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	tp := newTestPacketPacker(t, mockCtrl, protocol.PerspectiveServer)
@@ -581,9 +609,30 @@ func TestPackDatagramFrames(t *testing.T) {
 	require.Len(t, p.Frames, 1)
 	require.IsType(t, &wire.DatagramFrame{}, p.Frames[0].Frame)
 	require.Equal(t, []byte("foobar"), p.Frames[0].Frame.(*wire.DatagramFrame).Data)
+	require.Nil(t, p.Frames[0].Handler)
 	require.NotEmpty(t, buffer.Data)
 }
 
+func TestPackDatagramFramesWithFeedback(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	tp := newTestPacketPacker(t, mockCtrl, protocol.PerspectiveServer)
+
+	tp.ackFramer.EXPECT().GetAckFrame(protocol.Encryption1RTT, gomock.Any(), true)
+	tp.pnManager.EXPECT().PeekPacketNumber(protocol.Encryption1RTT).Return(protocol.PacketNumber(0x42), protocol.PacketNumberLen2)
+	tp.pnManager.EXPECT().PopPacketNumber(protocol.Encryption1RTT).Return(protocol.PacketNumber(0x42))
+	tp.sealingManager.EXPECT().Get1RTTSealer().Return(newMockShortHeaderSealer(mockCtrl), nil)
+	h := newDatagramHandle()
+	require.NoError(t, tp.datagramQueue.AddWithHandle(&wire.DatagramFrame{DataLenPresent: true, Data: []byte("foobar")}, 0, h))
+	tp.framer.EXPECT().HasData()
+	p, err := tp.packer.AppendPacket(getPacketBuffer(), protocol.MaxByteCount, monotime.Now(), protocol.Version1)
+	require.NoError(t, err)
+	require.Len(t, p.Frames, 1)
+	require.NotNil(t, p.Frames[0].Handler)
+	require.Equal(t, DatagramStatePending, h.State())
+	p.Frames[0].Handler.OnAcked(p.Frames[0].Frame)
+	require.Equal(t, DatagramStateAcknowledged, h.State())
+}
+
 func TestPackLargeDatagramFrame(t *testing.T) {
 	// If a packet contains an ACK, and doesn't have enough space for the DATAGRAM frame,
 	// it should be skipped. It will be packed in the next packet.
@@ -595,7 +644,8 @@ func TestPackLargeDatagramFrame(t *testing.T) {
 	tp.pnManager.EXPECT().PopPacketNumber(protocol.Encryption1RTT).Return(protocol.PacketNumber(0x42))
 	tp.sealingManager.EXPECT().Get1RTTSealer().Return(newMockShortHeaderSealer(mockCtrl), nil)
 	f := &wire.DatagramFrame{DataLenPresent: true, Data: make([]byte, maxPacketSize-10)}
-	tp.datagramQueue.Add(f)
+	h := newDatagramHandle()
+	require.NoError(t, tp.datagramQueue.AddWithHandle(f, 0, h))
 	tp.framer.EXPECT().HasData()
 	buffer := getPacketBuffer()
 	p, err := tp.packer.AppendPacket(buffer, maxPacketSize, monotime.Now(), protocol.Version1)
@@ -616,6 +666,7 @@ func TestPackLargeDatagramFrame(t *testing.T) {
 	p, err = tp.packer.AppendPacket(buffer, newMaxPacketSize, monotime.Now(), protocol.Version1)
 	require.ErrorIs(t, err, errNothingToPack)
 	require.Nil(t, tp.datagramQueue.Peek()) // make sure the frame is gone
+	require.Equal(t, DatagramStateDropped, h.State())
 }
 
 func TestPackRetransmissions(t *testing.T) {
@@ -735,7 +786,7 @@ func TestPackLongHeaderPadToAtLeast4Bytes(t *testing.T) {
 	// first bytes should be 2 PADDING frames...
 	require.Equal(t, []byte{0, 0}, data[:2])
 	// ...followed by the PING frame
-	frameParser := wire.NewFrameParser(false, false, false)
+	frameParser := wire.NewFrameParser(false, false, false, false)
 
 	frameType, lt, err := frameParser.ParseType(data[2:], protocol.EncryptionHandshake)
 	require.NoError(t, err)
@@ -778,7 +829,7 @@ func TestPackShortHeaderPadToAtLeast4Bytes(t *testing.T) {
 	require.Equal(t, byte(0), payload[0])
 
 	// ... followed by the STREAM frame
-	frameParser := wire.NewFrameParser(false, false, false)
+	frameParser := wire.NewFrameParser(false, false, false, false)
 	frameType, l, err := frameParser.ParseType(payload[1:], protocol.Encryption1RTT)
 	require.NoError(t, err)
 	require.Equal(t, 1, l)
@@ -111,6 +111,10 @@ func (u *packetUnpacker) UnpackShortHeader(rcvTime monotime.Time, data []byte) (
 	if err != nil {
 		return 0, 0, 0, nil, err
 	}
+	return u.unpackShortHeaderWithOpener(opener, rcvTime, data)
+}
+
+func (u *packetUnpacker) unpackShortHeaderWithOpener(opener handshake.ShortHeaderOpener, rcvTime monotime.Time, data []byte) (protocol.PacketNumber, protocol.PacketNumberLen, protocol.KeyPhaseBit, []byte, error) {
 	pn, pnLen, kp, decrypted, err := u.unpackShortHeaderPacket(opener, rcvTime, data)
 	if err != nil {
 		return 0, 0, 0, nil, err
@@ -220,3 +224,22 @@ func unpackLongHeader(hd headerDecryptor, hdr *wire.Header, data []byte) (*wire.
 	}
 	return extHdr, parseErr
 }
+
+// The pathUnpacker unpacks short header packets received on a path of a multipath connection.
+// Every path uses its own packet protection keys.
+type pathUnpacker struct {
+	packetUnpacker
+
+	opener handshake.ShortHeaderOpener
+}
+
+func newPathUnpacker(opener handshake.ShortHeaderOpener, shortHdrConnIDLen int) *pathUnpacker {
+	return &pathUnpacker{
+		packetUnpacker: packetUnpacker{shortHdrConnIDLen: shortHdrConnIDLen},
+		opener:         opener,
+	}
+}
+
+func (u *pathUnpacker) UnpackShortHeader(rcvTime monotime.Time, data []byte) (protocol.PacketNumber, protocol.PacketNumberLen, protocol.KeyPhaseBit, []byte, error) {
+	return u.unpackShortHeaderWithOpener(u.opener, rcvTime, data)
+}
@@ -29,12 +29,14 @@ const maxPaths = 3
 const pathTimeout = 5 * time.Second
 
 type path struct {
-	id             pathID
-	addr           net.Addr
-	lastPacketTime monotime.Time
-	pathChallenge  [8]byte
-	validated      bool
-	rcvdNonProbing bool
+	id   pathID
+	addr net.Addr
+	// toPreferredAddress is set for paths to the server's preferred address
+	toPreferredAddress bool
+	lastPacketTime     monotime.Time
+	pathChallenge      [8]byte
+	validated          bool
+	rcvdNonProbing     bool
 }
 
 type pathManager struct {
@@ -65,13 +67,14 @@ func newPathManager(
 // May return nil.
 func (pm *pathManager) HandlePacket(
 	remoteAddr net.Addr,
+	toPreferredAddress bool, // was the packet received on the server's preferred address
 	t monotime.Time,
 	pathChallenge *wire.PathChallengeFrame, // may be nil if the packet didn't contain a PATH_CHALLENGE
 	isNonProbing bool,
 ) (_ protocol.ConnectionID, _ []ackhandler.Frame, shouldSwitch bool) {
 	var p *path
 	for i, path := range pm.paths {
-		if addrsEqual(path.addr, remoteAddr) {
+		if addrsEqual(path.addr, remoteAddr) && path.toPreferredAddress == toPreferredAddress {
 			p = path
 			p.lastPacketTime = t
 			// already sent a PATH_CHALLENGE for this path
@@ -124,11 +127,12 @@ func (pm *pathManager) HandlePacket(
 		var pathChallengeData [8]byte
 		rand.Read(pathChallengeData[:])
 		p = &path{
-			id:             pm.nextPathID,
-			addr:           remoteAddr,
-			lastPacketTime: t,
-			rcvdNonProbing: isNonProbing,
-			pathChallenge:  pathChallengeData,
+			id:                 pm.nextPathID,
+			addr:               remoteAddr,
+			toPreferredAddress: toPreferredAddress,
+			lastPacketTime:     t,
+			rcvdNonProbing:     isNonProbing,
+			pathChallenge:      pathChallengeData,
 		}
 		pm.nextPathID++
 		pm.paths = append(pm.paths, p)
@@ -159,10 +163,10 @@ func (pm *pathManager) HandlePathResponseFrame(f *wire.PathResponseFrame) {
 }
 
 // SwitchToPath is called when the connection switches to a new path
-func (pm *pathManager) SwitchToPath(addr net.Addr) {
+func (pm *pathManager) SwitchToPath(addr net.Addr, toPreferredAddress bool) {
 	// retire all other paths
 	for _, path := range pm.paths {
-		if addrsEqual(path.addr, addr) {
+		if addrsEqual(path.addr, addr) && path.toPreferredAddress == toPreferredAddress {
 			pm.logger.Debugf("switching to path %d (%s)", path.id, addr)
 			continue
 		}
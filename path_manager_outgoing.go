@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"errors"
+	"net"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -28,7 +29,8 @@ type Path struct {
 	id          pathID
 	pathManager *pathManagerOutgoing
 	tr          *Transport
-	initialRTT  time.Duration
+	remoteAddr  net.Addr      // nil if the path uses the connection's current remote address
+	initialPTO  time.Duration // timeout for the first probe, doubled for every retransmission
 
 	enablePath func()
 	validated  atomic.Bool
@@ -39,7 +41,7 @@ func (p *Path) Probe(ctx context.Context) error {
 	path := p.pathManager.addPath(p, p.enablePath)
 
 	p.pathManager.enqueueProbe(p)
-	nextProbeDur := p.initialRTT
+	nextProbeDur := p.initialPTO
 	var timer *time.Timer
 	var timerChan <-chan time.Time
 	for {
@@ -106,6 +108,7 @@ func (p *Path) Close() error {
 type pathOutgoing struct {
 	pathChallenges [][8]byte // length is implicitly limited by exponential backoff
 	tr             *Transport
+	remoteAddr     net.Addr
 	isValidated    bool
 	probeSent      chan struct{} // receives when a PATH_CHALLENGE is sent
 	validated      chan struct{} // closed when the path the corresponding PATH_RESPONSE is received
@@ -126,6 +129,11 @@ type pathManagerOutgoing struct {
 	paths          map[pathID]*pathOutgoing
 	nextPathID     pathID
 	pathToSwitchTo *pathOutgoing
+
+	// only used if multipath is used
+	abandonedPaths []pathID
+	pathStatuses   map[pathID]PathStatus // initialized lazily
+	statusUpdates  []pathID
 }
 
 // newPathManagerOutgoing creates a new pathManagerOutgoing object. This
@@ -158,6 +166,7 @@ func (pm *pathManagerOutgoing) addPath(p *Path, enablePath func()) *pathOutgoing
 
 	path := &pathOutgoing{
 		tr:         p.tr,
+		remoteAddr: p.remoteAddr,
 		probeSent:  make(chan struct{}, 1),
 		validated:  make(chan struct{}),
 		enablePath: enablePath,
@@ -196,9 +205,58 @@ func (pm *pathManagerOutgoing) removePathImpl(id pathID) error {
 		pm.retireConnID(id)
 	}
 	delete(pm.paths, id)
+	pm.abandonedPaths = append(pm.abandonedPaths, id)
 	return nil
 }
 
+func (pm *pathManagerOutgoing) setPathStatus(id pathID, s PathStatus) {
+	pm.mx.Lock()
+	if pm.pathStatuses == nil {
+		pm.pathStatuses = make(map[pathID]PathStatus)
+	}
+	pm.pathStatuses[id] = s
+	pm.statusUpdates = append(pm.statusUpdates, id)
+	pm.mx.Unlock()
+	pm.scheduleSending()
+}
+
+// PathStatus returns the status of a path, as set by the application.
+func (pm *pathManagerOutgoing) PathStatus(id pathID) PathStatus {
+	pm.mx.Lock()
+	defer pm.mx.Unlock()
+
+	return pm.pathStatuses[id]
+}
+
+// PopPathStatusUpdates returns the paths that the application changed the status of.
+func (pm *pathManagerOutgoing) PopPathStatusUpdates() map[pathID]PathStatus {
+	pm.mx.Lock()
+	defer pm.mx.Unlock()
+
+	if len(pm.statusUpdates) == 0 {
+		return nil
+	}
+	updates := make(map[pathID]PathStatus, len(pm.statusUpdates))
+	for _, id := range pm.statusUpdates {
+		updates[id] = pm.pathStatuses[id]
+	}
+	pm.statusUpdates = pm.statusUpdates[:0]
+	return updates
+}
+
+// PopAbandonedPaths returns the paths that were closed by the application.
+func (pm *pathManagerOutgoing) PopAbandonedPaths() []pathID {
+	pm.mx.Lock()
+	defer pm.mx.Unlock()
+
+	if len(pm.abandonedPaths) == 0 {
+		return nil
+	}
+	ids := pm.abandonedPaths
+	pm.abandonedPaths = nil
+	return ids
+}
+
 func (pm *pathManagerOutgoing) switchToPath(id pathID) error {
 	pm.mx.Lock()
 	defer pm.mx.Unlock()
@@ -215,7 +273,11 @@ func (pm *pathManagerOutgoing) switchToPath(id pathID) error {
 	return nil
 }
 
-func (pm *pathManagerOutgoing) NewPath(t *Transport, initialRTT time.Duration, enablePath func()) *Path {
+// NewPath creates a new path.
+// If t is nil, the path uses the connection's current Transport.
+// If remoteAddr is nil, the path uses the connection's current remote address.
+// initialPTO is the time after which the first probe is retransmitted.
+func (pm *pathManagerOutgoing) NewPath(t *Transport, remoteAddr net.Addr, initialPTO time.Duration, enablePath func()) *Path {
 	pm.mx.Lock()
 	defer pm.mx.Unlock()
 
@@ -225,13 +287,14 @@ func (pm *pathManagerOutgoing) NewPath(t *Transport, initialRTT time.Duration, e
 		pathManager: pm,
 		id:          id,
 		tr:          t,
+		remoteAddr:  remoteAddr,
 		enablePath:  enablePath,
-		initialRTT:  initialRTT,
+		initialPTO:  initialPTO,
 		abandon:     make(chan struct{}),
 	}
 }
 
-func (pm *pathManagerOutgoing) NextPathToProbe() (_ protocol.ConnectionID, _ ackhandler.Frame, _ *Transport, hasPath bool) {
+func (pm *pathManagerOutgoing) NextPathToProbe() (_ pathID, _ protocol.ConnectionID, _ ackhandler.Frame, _ *Transport, remoteAddr net.Addr, hasPath bool) {
 	pm.mx.Lock()
 	defer pm.mx.Unlock()
 
@@ -248,12 +311,12 @@ func (pm *pathManagerOutgoing) NextPathToProbe() (_ protocol.ConnectionID, _ ack
 		pm.pathsToProbe = pm.pathsToProbe[1:]
 	}
 	if id == invalidPathID {
-		return protocol.ConnectionID{}, ackhandler.Frame{}, nil, false
+		return 0, protocol.ConnectionID{}, ackhandler.Frame{}, nil, nil, false
 	}
 
 	connID, ok := pm.getConnID(id)
 	if !ok {
-		return protocol.ConnectionID{}, ackhandler.Frame{}, nil, false
+		return 0, protocol.ConnectionID{}, ackhandler.Frame{}, nil, nil, false
 	}
 
 	var b [8]byte
@@ -270,14 +333,16 @@ func (pm *pathManagerOutgoing) NextPathToProbe() (_ protocol.ConnectionID, _ ack
 		Frame:   &wire.PathChallengeFrame{Data: b},
 		Handler: (*pathManagerOutgoingAckHandler)(pm),
 	}
-	return connID, frame, p.tr, true
+	return id, connID, frame, p.tr, p.remoteAddr, true
 }
 
-func (pm *pathManagerOutgoing) HandlePathResponseFrame(f *wire.PathResponseFrame) {
+// HandlePathResponseFrame handles a PATH_RESPONSE frame.
+// It returns the ID of the path that was validated, if any.
+func (pm *pathManagerOutgoing) HandlePathResponseFrame(f *wire.PathResponseFrame) (_ pathID, validated bool) {
 	pm.mx.Lock()
 	defer pm.mx.Unlock()
 
-	for _, p := range pm.paths {
+	for id, p := range pm.paths {
 		if slices.Contains(p.pathChallenges, f.Data) {
 			// path validated
 			if !p.isValidated {
@@ -285,22 +350,24 @@ func (pm *pathManagerOutgoing) HandlePathResponseFrame(f *wire.PathResponseFrame
 				p.isValidated = true
 				p.pathChallenges = nil
 				close(p.validated)
+				return id, true
 			}
 			break
 		}
 	}
+	return 0, false
 }
 
-func (pm *pathManagerOutgoing) ShouldSwitchPath() (*Transport, bool) {
+func (pm *pathManagerOutgoing) ShouldSwitchPath() (_ pathID, _ *Transport, remoteAddr net.Addr, ok bool) {
 	pm.mx.Lock()
 	defer pm.mx.Unlock()
 
 	if pm.pathToSwitchTo == nil {
-		return nil, false
+		return 0, nil, nil, false
 	}
 	p := pm.pathToSwitchTo
 	pm.pathToSwitchTo = nil
-	return p.tr, true
+	return pm.activePath, p.tr, p.remoteAddr, true
 }
 
 type pathManagerOutgoingAckHandler pathManagerOutgoing
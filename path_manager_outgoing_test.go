@@ -2,6 +2,7 @@ package quic
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
 
@@ -27,12 +28,12 @@ func TestPathManagerOutgoingPathProbing(t *testing.T) {
 			func() {},
 		)
 
-		_, _, _, ok := pm.NextPathToProbe()
+		_, _, _, _, _, ok := pm.NextPathToProbe()
 		require.False(t, ok)
 
 		tr1 := &Transport{}
 		var enabled bool
-		p := pm.NewPath(tr1, time.Second, func() { enabled = true })
+		p := pm.NewPath(tr1, nil, time.Second, func() { enabled = true })
 		require.ErrorIs(t, p.Switch(), ErrPathNotValidated)
 
 		errChan := make(chan error, 1)
@@ -42,15 +43,17 @@ func TestPathManagerOutgoingPathProbing(t *testing.T) {
 		synctest.Wait()
 
 		require.False(t, enabled)
-		connID, f, tr, ok := pm.NextPathToProbe()
+		id, connID, f, tr, remoteAddr, ok := pm.NextPathToProbe()
 		require.True(t, ok)
+		require.Equal(t, p.id, id)
 		require.Equal(t, tr1, tr)
+		require.Nil(t, remoteAddr)
 		require.Equal(t, protocol.ParseConnectionID([]byte{1, 2, 3, 4, 5, 6, 7, 8}), connID)
 		require.IsType(t, &wire.PathChallengeFrame{}, f.Frame)
 		pc := f.Frame.(*wire.PathChallengeFrame)
 		require.True(t, enabled)
 
-		_, _, _, ok = pm.NextPathToProbe()
+		_, _, _, _, _, ok = pm.NextPathToProbe()
 		require.False(t, ok)
 
 		select {
@@ -68,7 +71,7 @@ func TestPathManagerOutgoingPathProbing(t *testing.T) {
 		}
 
 		require.ErrorIs(t, p.Switch(), ErrPathNotValidated)
-		_, ok = pm.ShouldSwitchPath()
+		_, _, _, ok = pm.ShouldSwitchPath()
 		require.False(t, ok)
 
 		// ... neither does receiving a random PATH_RESPONSE...
@@ -82,7 +85,9 @@ func TestPathManagerOutgoingPathProbing(t *testing.T) {
 		}
 
 		// ... only receiving the corresponding PATH_RESPONSE does
-		pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: pc.Data})
+		validatedID, validated := pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: pc.Data})
+		require.True(t, validated)
+		require.Equal(t, p.id, validatedID)
 
 		synctest.Wait()
 
@@ -94,17 +99,20 @@ func TestPathManagerOutgoingPathProbing(t *testing.T) {
 		}
 
 		// receiving it multiple times is ok
-		pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: pc.Data})
+		_, validated = pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: pc.Data})
+		require.False(t, validated)
 
 		// now switch to the other path
-		_, ok = pm.ShouldSwitchPath()
+		_, _, _, ok = pm.ShouldSwitchPath()
 		require.False(t, ok)
 		require.NoError(t, p.Switch())
 		// the active path can't be closed
 		require.EqualError(t, p.Close(), "cannot close active path")
-		switchToTransport, ok := pm.ShouldSwitchPath()
+		switchToID, switchToTransport, switchToAddr, ok := pm.ShouldSwitchPath()
 		require.True(t, ok)
+		require.Equal(t, p.id, switchToID)
 		require.Equal(t, tr1, switchToTransport)
+		require.Nil(t, switchToAddr)
 	})
 }
 
@@ -122,12 +130,12 @@ func TestPathManagerOutgoingRetransmissions(t *testing.T) {
 			func() { scheduledSending <- struct{}{} },
 		)
 
-		_, _, _, ok := pm.NextPathToProbe()
+		_, _, _, _, _, ok := pm.NextPathToProbe()
 		require.False(t, ok)
 
 		tr1 := &Transport{}
-		const initialRTT = 5 * time.Millisecond
-		p := pm.NewPath(tr1, initialRTT, func() {})
+		const initialPTO = 5 * time.Millisecond
+		p := pm.NewPath(tr1, nil, initialPTO, func() {})
 
 		pathChallengeChan := make(chan [8]byte)
 		done := make(chan struct{})
@@ -139,7 +147,7 @@ func TestPathManagerOutgoingRetransmissions(t *testing.T) {
 				case <-done:
 					return
 				}
-				_, f, _, ok := pm.NextPathToProbe()
+				_, _, f, _, _, ok := pm.NextPathToProbe()
 				if !ok {
 					// should never happen
 					pathChallengeChan <- [8]byte{}
@@ -174,7 +182,7 @@ func TestPathManagerOutgoingRetransmissions(t *testing.T) {
 			if i > 0 {
 				took := r1.took - results[i-1].took
 				t.Log("took", took)
-				require.Equal(t, took, initialRTT<<(i-1))
+				require.Equal(t, took, initialPTO<<(i-1))
 			}
 			for j, r2 := range results {
 				if i == j {
@@ -242,7 +250,7 @@ func TestPathManagerOutgoingAbandonPath(t *testing.T) {
 		)
 
 		// path abandoned before the PATH_CHALLENGE is sent out
-		p1 := pm.NewPath(&Transport{}, time.Second, func() {})
+		p1 := pm.NewPath(&Transport{}, nil, time.Second, func() {})
 		errChan := make(chan error, 1)
 		go func() { errChan <- p1.Probe(context.Background()) }()
 
@@ -253,7 +261,7 @@ func TestPathManagerOutgoingAbandonPath(t *testing.T) {
 		// closing the path multiple times is ok
 		require.NoError(t, p1.Close())
 		require.NoError(t, p1.Close())
-		_, _, _, ok := pm.NextPathToProbe()
+		_, _, _, _, _, ok := pm.NextPathToProbe()
 		require.False(t, ok)
 
 		synctest.Wait()
@@ -266,22 +274,56 @@ func TestPathManagerOutgoingAbandonPath(t *testing.T) {
 		}
 		require.Empty(t, retiredPaths)
 
-		p2 := pm.NewPath(&Transport{}, time.Second, func() {})
+		p2 := pm.NewPath(&Transport{}, nil, time.Second, func() {})
 		go func() { errChan <- p2.Probe(context.Background()) }()
 
 		// wait for the path to be queued for probing
 		synctest.Wait()
 
-		connID, f, _, ok := pm.NextPathToProbe()
+		_, connID, f, _, _, ok := pm.NextPathToProbe()
 		require.True(t, ok)
 		require.Equal(t, protocol.ParseConnectionID([]byte{1, 2, 3, 4, 5, 6, 7, 8}), connID)
 
 		require.NoError(t, p2.Close())
 		require.Equal(t, []pathID{p2.id}, retiredPaths)
 		pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: f.Frame.(*wire.PathChallengeFrame).Data})
-		_, _, _, ok = pm.NextPathToProbe()
+		_, _, _, _, _, ok = pm.NextPathToProbe()
 		require.False(t, ok)
 		// it's not possible to switch to an abandoned path
 		require.ErrorIs(t, p2.Switch(), ErrPathClosed)
 	})
 }
+
+func TestPathManagerOutgoingRemoteAddress(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		pm := newPathManagerOutgoing(
+			func(id pathID) (protocol.ConnectionID, bool) {
+				return protocol.ParseConnectionID([]byte{1, 2, 3, 4}), true
+			},
+			func(id pathID) { t.Fatal("didn't expect any connection ID to be retired") },
+			func() {},
+		)
+
+		remoteAddr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 443}
+		p := pm.NewPath(nil, remoteAddr, time.Second, func() {})
+		errChan := make(chan error, 1)
+		go func() { errChan <- p.Probe(context.Background()) }()
+
+		synctest.Wait()
+
+		_, _, f, tr, addr, ok := pm.NextPathToProbe()
+		require.True(t, ok)
+		require.Nil(t, tr)
+		require.Equal(t, remoteAddr, addr)
+
+		pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: f.Frame.(*wire.PathChallengeFrame).Data})
+		synctest.Wait()
+		require.NoError(t, <-errChan)
+
+		require.NoError(t, p.Switch())
+		_, tr, addr, ok = pm.ShouldSwitchPath()
+		require.True(t, ok)
+		require.Nil(t, tr)
+		require.Equal(t, remoteAddr, addr)
+	})
+}
@@ -33,6 +33,7 @@ func TestPathManagerIntentionalMigration(t *testing.T) {
 	now := monotime.Now()
 	connID, frames, shouldSwitch := pm.HandlePacket(
 		&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000},
+		false,
 		now,
 		&wire.PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
 		false,
@@ -50,6 +51,7 @@ func TestPathManagerIntentionalMigration(t *testing.T) {
 	// receiving another packet for the same path doesn't trigger another PATH_CHALLENGE
 	connID, frames, shouldSwitch = pm.HandlePacket(
 		&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000},
+		false,
 		now,
 		nil,
 		false,
@@ -60,7 +62,7 @@ func TestPathManagerIntentionalMigration(t *testing.T) {
 
 	// receiving a packet for a different path triggers another PATH_CHALLENGE
 	addr2 := &net.UDPAddr{IP: net.IPv4(5, 6, 7, 8), Port: 1000}
-	connID, frames, shouldSwitch = pm.HandlePacket(addr2, now, nil, false)
+	connID, frames, shouldSwitch = pm.HandlePacket(addr2, false, now, nil, false)
 	require.Equal(t, connIDs[1], connID)
 	require.Len(t, frames, 1)
 	require.IsType(t, &wire.PathChallengeFrame{}, frames[0].Frame)
@@ -74,6 +76,7 @@ func TestPathManagerIntentionalMigration(t *testing.T) {
 	}
 	connID, frames, shouldSwitch = pm.HandlePacket(
 		&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000},
+		false,
 		now,
 		nil,
 		false,
@@ -84,7 +87,7 @@ func TestPathManagerIntentionalMigration(t *testing.T) {
 
 	// receiving a PATH_RESPONSE for the second path confirms the path
 	pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: pc2.Data})
-	connID, frames, shouldSwitch = pm.HandlePacket(addr2, now, nil, false)
+	connID, frames, shouldSwitch = pm.HandlePacket(addr2, false, now, nil, false)
 	require.Zero(t, connID)
 	require.Empty(t, frames)
 	require.False(t, shouldSwitch) // no non-probing packet received yet
@@ -93,6 +96,7 @@ func TestPathManagerIntentionalMigration(t *testing.T) {
 	// confirming the path doesn't remove other paths
 	connID, frames, shouldSwitch = pm.HandlePacket(
 		&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000},
+		false,
 		now,
 		nil,
 		false,
@@ -104,6 +108,7 @@ func TestPathManagerIntentionalMigration(t *testing.T) {
 	// now receive a non-probing packet for the new path
 	connID, frames, shouldSwitch = pm.HandlePacket(
 		&net.UDPAddr{IP: net.IPv4(5, 6, 7, 8), Port: 1000},
+		false,
 		now,
 		nil,
 		true,
@@ -113,10 +118,10 @@ func TestPathManagerIntentionalMigration(t *testing.T) {
 	require.True(t, shouldSwitch)
 
 	// now switch to the new path
-	pm.SwitchToPath(&net.UDPAddr{IP: net.IPv4(5, 6, 7, 8), Port: 1000})
+	pm.SwitchToPath(&net.UDPAddr{IP: net.IPv4(5, 6, 7, 8), Port: 1000}, false)
 
 	// switching to the path removes other paths
-	connID, frames, shouldSwitch = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000}, now, nil, false)
+	connID, frames, shouldSwitch = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000}, false, now, nil, false)
 	require.Equal(t, connIDs[2], connID)
 	require.NotEmpty(t, frames)
 	require.NotEqual(t, frames[0].Frame.(*wire.PathChallengeFrame).Data, pc1.Data)
@@ -137,6 +142,7 @@ func TestPathManagerMultipleProbes(t *testing.T) {
 	// first receive a packet without a PATH_CHALLENGE
 	connID, frames, shouldSwitch := pm.HandlePacket(
 		&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000},
+		false,
 		now,
 		nil,
 		false,
@@ -149,6 +155,7 @@ func TestPathManagerMultipleProbes(t *testing.T) {
 	// now receive a packet on the same path with a PATH_CHALLENGE
 	connID, frames, shouldSwitch = pm.HandlePacket(
 		&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000},
+		false,
 		now,
 		&wire.PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
 		false,
@@ -161,6 +168,7 @@ func TestPathManagerMultipleProbes(t *testing.T) {
 	// now receive another packet on the same path with a PATH_RESPONSE
 	connID, frames, shouldSwitch = pm.HandlePacket(
 		&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000},
+		false,
 		now,
 		&wire.PathChallengeFrame{Data: [8]byte{8, 7, 6, 5, 4, 3, 2, 1}},
 		false,
@@ -189,7 +197,7 @@ func TestPathManagerNATRebinding(t *testing.T) {
 	)
 
 	now := monotime.Now()
-	connID, frames, shouldSwitch := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000}, now, nil, true)
+	connID, frames, shouldSwitch := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000}, false, now, nil, true)
 	require.Equal(t, connIDs[0], connID)
 	require.Len(t, frames, 1)
 	require.IsType(t, &wire.PathChallengeFrame{}, frames[0].Frame)
@@ -200,12 +208,43 @@ func TestPathManagerNATRebinding(t *testing.T) {
 	// receiving a PATH_RESPONSE for the second path confirms the path
 	pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: pc1.Data})
 	// we now switch to the new path, as soon as the next packet on that path is received
-	connID, frames, shouldSwitch = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000}, now, nil, false)
+	connID, frames, shouldSwitch = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000}, false, now, nil, false)
 	require.Zero(t, connID)
 	require.Empty(t, frames)
 	require.True(t, shouldSwitch)
 }
 
+func TestPathManagerPreferredAddress(t *testing.T) {
+	connIDs := []protocol.ConnectionID{
+		protocol.ParseConnectionID([]byte{1, 2, 3, 4, 5, 6, 7, 8}),
+		protocol.ParseConnectionID([]byte{2, 3, 4, 5, 6, 7, 8, 9}),
+	}
+	var retiredConnIDs []protocol.ConnectionID
+	pm := newPathManager(
+		func(id pathID) (protocol.ConnectionID, bool) { return connIDs[id], true },
+		func(id pathID) { retiredConnIDs = append(retiredConnIDs, connIDs[id]) },
+		utils.DefaultLogger,
+	)
+	now := monotime.Now()
+	addr := &net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000}
+	connID, frames, shouldSwitch := pm.HandlePacket(addr, false, now, nil, true)
+	require.Equal(t, connIDs[0], connID)
+	require.Len(t, frames, 1)
+	require.False(t, shouldSwitch)
+
+	// a packet sent from the same address to the preferred address is a different path
+	connID, frames, shouldSwitch = pm.HandlePacket(addr, true, now, nil, true)
+	require.Equal(t, connIDs[1], connID)
+	require.Len(t, frames, 1)
+	require.False(t, shouldSwitch)
+	pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: frames[0].Frame.(*wire.PathChallengeFrame).Data})
+	_, _, shouldSwitch = pm.HandlePacket(addr, true, now, nil, true)
+	require.True(t, shouldSwitch)
+
+	pm.SwitchToPath(addr, true)
+	require.Equal(t, []protocol.ConnectionID{connIDs[0]}, retiredConnIDs)
+}
+
 func TestPathManagerLimits(t *testing.T) {
 	var connIDs []protocol.ConnectionID
 	for range 2*maxPaths + 2 {
@@ -225,7 +264,7 @@ func TestPathManagerLimits(t *testing.T) {
 	var firstPathConnID protocol.ConnectionID
 	require.Greater(t, pathTimeout, maxPaths*time.Second)
 	for i := range maxPaths {
-		connID, frames, _ := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000 + i}, now, nil, true)
+		connID, frames, _ := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000 + i}, false, now, nil, true)
 		require.NotEmpty(t, frames)
 		require.Equal(t, connIDs[i], connID)
 		if i == 0 {
@@ -235,14 +274,14 @@ func TestPathManagerLimits(t *testing.T) {
 	}
 	// the maximum number of paths is already being probed
 	now = firstPathTime.Add(pathTimeout).Add(-time.Nanosecond)
-	connID, frames, _ := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 2000}, now, nil, true)
+	connID, frames, _ := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 2000}, false, now, nil, true)
 	require.Zero(t, connID)
 	require.Empty(t, frames)
 
 	// receiving another packet after the pathTimeout of the first path evicts the first path
 	now = firstPathTime.Add(pathTimeout)
 	connIDIndex := maxPaths
-	connID, frames, _ = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000 + maxPaths}, now, nil, true)
+	connID, frames, _ = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000 + maxPaths}, false, now, nil, true)
 	require.NotEmpty(t, frames)
 	require.Equal(t, connIDs[connIDIndex], connID)
 	require.Equal(t, []protocol.ConnectionID{firstPathConnID}, retiredConnIDs)
@@ -250,9 +289,9 @@ func TestPathManagerLimits(t *testing.T) {
 
 	// switching to a new path frees is up all paths
 	var f1 []ackhandler.Frame
-	pm.SwitchToPath(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000})
+	pm.SwitchToPath(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000}, false)
 	for i := range maxPaths {
-		connID, frames, _ := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 3000 + i}, now, nil, true)
+		connID, frames, _ := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 3000 + i}, false, now, nil, true)
 		if i == 0 {
 			f1 = frames
 		}
@@ -261,7 +300,7 @@ func TestPathManagerLimits(t *testing.T) {
 		connIDIndex++
 	}
 	// again, the maximum number of paths is already being probed
-	connID, frames, _ = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 2000}, now, nil, true)
+	connID, frames, _ = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 2000}, false, now, nil, true)
 	require.Zero(t, connID)
 	require.Empty(t, frames)
 
@@ -269,10 +308,10 @@ func TestPathManagerLimits(t *testing.T) {
 	f1[0].Handler.OnLost(f1[0].Frame)
 
 	// we can open exactly one more path
-	connID, frames, _ = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 4000}, now, nil, true)
+	connID, frames, _ = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 4000}, false, now, nil, true)
 	require.NotEmpty(t, frames)
 	require.Equal(t, connIDs[connIDIndex], connID)
-	connID, frames, _ = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 4001}, now, nil, true)
+	connID, frames, _ = pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 4001}, false, now, nil, true)
 	require.Zero(t, connID)
 	require.Empty(t, frames)
 }
@@ -0,0 +1,132 @@
+package quic
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	internalcongestion "github.com/quic-go/quic-go/internal/congestion"
+	"github.com/quic-go/quic-go/internal/utils"
+	list "github.com/quic-go/quic-go/internal/utils/linkedlist"
+)
+
+type lruPathMetricsCacheEntry struct {
+	key     string
+	metrics PathMetrics
+	stored  time.Time
+}
+
+type lruPathMetricsCache struct {
+	maxAge time.Duration
+
+	mutex    sync.Mutex
+	m        map[string]*list.Element[*lruPathMetricsCacheEntry]
+	q        *list.List[*lruPathMetricsCacheEntry]
+	capacity int
+}
+
+var _ PathMetricsCache = &lruPathMetricsCache{}
+
+// NewLRUPathMetricsCache creates a new LRU cache for path metrics.
+// Similar to the TCP metrics cache of the Linux kernel, metrics are saved per remote IP address,
+// i.e. all connections to the same host share the cache entry, independent of the port.
+// capacity specifies how many remote IP addresses this cache is saving metrics for.
+// If capacity is not positive, no metrics are saved.
+// Metrics saved more than maxAge ago are not used. If maxAge is 0, metrics don't expire.
+func NewLRUPathMetricsCache(capacity int, maxAge time.Duration) PathMetricsCache {
+	return &lruPathMetricsCache{
+		maxAge:   maxAge,
+		m:        make(map[string]*list.Element[*lruPathMetricsCacheEntry]),
+		q:        list.New[*lruPathMetricsCacheEntry](),
+		capacity: capacity,
+	}
+}
+
+func (c *lruPathMetricsCache) Get(addr net.Addr) (PathMetrics, bool) {
+	key := pathMetricsCacheKey(addr)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	el, ok := c.m[key]
+	if !ok {
+		return PathMetrics{}, false
+	}
+	if c.maxAge > 0 && time.Since(el.Value.stored) > c.maxAge {
+		c.q.Remove(el)
+		delete(c.m, key)
+		return PathMetrics{}, false
+	}
+	c.q.MoveToFront(el)
+	return el.Value.metrics, true
+}
+
+func (c *lruPathMetricsCache) Put(addr net.Addr, metrics PathMetrics) {
+	key := pathMetricsCacheKey(addr)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if el, ok := c.m[key]; ok {
+		el.Value.metrics = metrics
+		el.Value.stored = time.Now()
+		c.q.MoveToFront(el)
+		return
+	}
+
+	if c.capacity <= 0 {
+		return
+	}
+	if c.q.Len() < c.capacity {
+		c.m[key] = c.q.PushFront(&lruPathMetricsCacheEntry{key: key, metrics: metrics, stored: time.Now()})
+		return
+	}
+
+	elem := c.q.Back()
+	entry := elem.Value
+	delete(c.m, entry.key)
+	entry.key = key
+	entry.metrics = metrics
+	entry.stored = time.Now()
+	c.q.MoveToFront(elem)
+	c.m[key] = elem
+}
+
+func pathMetricsCacheKey(addr net.Addr) string {
+	if udpAddr, ok := addr.(*net.UDPAddr); ok {
+		return udpAddr.AddrPort().Addr().Unmap().String()
+	}
+	return addr.String()
+}
+
+// initialRTTForPath returns the initial RTT estimate for a new path to the remote address.
+// The RTT saved in the cache (if any) takes precedence over the configured initial RTT.
+// It returns 0 if neither is available.
+func initialRTTForPath(cache PathMetricsCache, remoteAddr net.Addr, conf *Config) time.Duration {
+	if cache != nil {
+		if m, ok := cache.Get(remoteAddr); ok && m.SmoothedRTT > 0 {
+			return m.SmoothedRTT
+		}
+	}
+	return conf.InitialRTT
+}
+
+// initialPTOForPath returns the PTO of a new path to the remote address, before an RTT sample is taken on that path.
+// It is twice the initial RTT returned by initialRTTForPath, or twice the default initial RTT.
+func initialPTOForPath(cache PathMetricsCache, remoteAddr net.Addr, conf *Config) time.Duration {
+	if rtt := initialRTTForPath(cache, remoteAddr, conf); rtt > 0 {
+		return 2 * rtt
+	}
+	return 2 * utils.DefaultInitialRTT
+}
+
+// initialBandwidthForPath returns the bandwidth estimate saved in the cache for a new connection to the remote address.
+// It returns 0 if no estimate is available.
+func initialBandwidthForPath(cache PathMetricsCache, remoteAddr net.Addr) internalcongestion.Bandwidth {
+	if cache != nil {
+		if m, ok := cache.Get(remoteAddr); ok {
+			return internalcongestion.Bandwidth(m.BandwidthEstimate)
+		}
+	}
+	return 0
+}
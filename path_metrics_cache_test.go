@@ -0,0 +1,91 @@
+package quic
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/synctest"
+	"github.com/quic-go/quic-go/internal/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUPathMetricsCache(t *testing.T) {
+	c := NewLRUPathMetricsCache(2, 0)
+	addr1 := &net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 443}
+	addr2 := &net.UDPAddr{IP: net.IPv4(5, 6, 7, 8), Port: 443}
+	addr3 := &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 443}
+
+	_, ok := c.Get(addr1)
+	require.False(t, ok)
+	c.Put(addr1, PathMetrics{SmoothedRTT: time.Second})
+	c.Put(addr2, PathMetrics{SmoothedRTT: 2 * time.Second})
+	// metrics are saved per IP address, independent of the port
+	m, ok := c.Get(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1234})
+	require.True(t, ok)
+	require.Equal(t, PathMetrics{SmoothedRTT: time.Second}, m)
+	// IPv4-mapped IPv6 addresses are treated as IPv4 addresses
+	_, ok = c.Get(&net.UDPAddr{IP: net.ParseIP("::ffff:1.2.3.4"), Port: 443})
+	require.True(t, ok)
+
+	// addr2 is the least recently used entry, and is evicted
+	c.Put(addr3, PathMetrics{SmoothedRTT: 3 * time.Second})
+	_, ok = c.Get(addr2)
+	require.False(t, ok)
+	m, ok = c.Get(addr3)
+	require.True(t, ok)
+	require.Equal(t, PathMetrics{SmoothedRTT: 3 * time.Second}, m)
+
+	// updating an entry replaces the metrics
+	c.Put(addr1, PathMetrics{SmoothedRTT: 4 * time.Second, MinRTT: time.Second, BandwidthEstimate: 1e6})
+	m, ok = c.Get(addr1)
+	require.True(t, ok)
+	require.Equal(t, PathMetrics{SmoothedRTT: 4 * time.Second, MinRTT: time.Second, BandwidthEstimate: 1e6}, m)
+}
+
+func TestLRUPathMetricsCacheZeroCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		c := NewLRUPathMetricsCache(capacity, 0)
+		addr := &net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 443}
+		c.Put(addr, PathMetrics{SmoothedRTT: time.Second})
+		_, ok := c.Get(addr)
+		require.False(t, ok)
+	}
+}
+
+func TestLRUPathMetricsCacheExpiry(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := NewLRUPathMetricsCache(10, time.Hour)
+		addr := &net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 443}
+		c.Put(addr, PathMetrics{SmoothedRTT: time.Second})
+		time.Sleep(time.Hour)
+		_, ok := c.Get(addr)
+		require.True(t, ok)
+		time.Sleep(time.Nanosecond)
+		_, ok = c.Get(addr)
+		require.False(t, ok)
+	})
+}
+
+func TestInitialRTTForPath(t *testing.T) {
+	addr := &net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 443}
+	require.Zero(t, initialRTTForPath(nil, addr, &Config{}))
+	require.Equal(t, time.Second, initialRTTForPath(nil, addr, &Config{InitialRTT: time.Second}))
+
+	c := NewLRUPathMetricsCache(10, 0)
+	require.Equal(t, time.Second, initialRTTForPath(c, addr, &Config{InitialRTT: time.Second}))
+	// the cached RTT takes precedence over the configured initial RTT
+	c.Put(addr, PathMetrics{SmoothedRTT: 42 * time.Millisecond})
+	require.Equal(t, 42*time.Millisecond, initialRTTForPath(c, addr, &Config{InitialRTT: time.Second}))
+}
+
+func TestInitialPTOForPath(t *testing.T) {
+	addr := &net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 443}
+	require.Equal(t, 2*utils.DefaultInitialRTT, initialPTOForPath(nil, addr, &Config{}))
+	require.Equal(t, 2*time.Second, initialPTOForPath(nil, addr, &Config{InitialRTT: time.Second}))
+
+	c := NewLRUPathMetricsCache(10, 0)
+	c.Put(addr, PathMetrics{SmoothedRTT: 42 * time.Millisecond})
+	require.Equal(t, 84*time.Millisecond, initialPTOForPath(c, addr, &Config{InitialRTT: time.Second}))
+}
@@ -0,0 +1,240 @@
+// Package pcapng writes packet captures in the pcapng format,
+// https://datatracker.ietf.org/doc/draft-ietf-opsawg-pcapng/.
+//
+// UDP datagrams are written with synthetic IP and UDP headers.
+// TLS secrets can be embedded in the capture in Decryption Secrets Blocks,
+// which allows Wireshark to decrypt the QUIC packets without a separate key log file.
+package pcapng
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+const (
+	blockTypeSectionHeader        = 0x0a0d0d0a
+	blockTypeInterfaceDescription = 0x00000001
+	blockTypeEnhancedPacket       = 0x00000006
+	blockTypeDecryptionSecrets    = 0x0000000a
+
+	byteOrderMagic = 0x1a2b3c4d
+
+	// LINKTYPE_RAW: the packet begins with an IPv4 or IPv6 header
+	linkTypeRaw = 101
+
+	optionEndOfOpt = 0
+	// if_tsresol: the resolution of the timestamps
+	optionTimestampResolution = 9
+	// timestamps are in nanoseconds
+	timestampResolutionNanoseconds = 9
+
+	// TLS Key Log, https://firefox-source-docs.mozilla.org/security/nss/legacy/key_log_format/index.html
+	secretsTypeTLSKeyLog = 0x544c534b
+
+	ipProtocolUDP = 17
+	ipv4HeaderLen = 20
+	ipv6HeaderLen = 40
+	udpHeaderLen  = 8
+	defaultTTL    = 64
+)
+
+// A Writer writes packets and TLS secrets to a pcapng capture.
+// It is safe for concurrent use by multiple goroutines.
+type Writer struct {
+	mx  sync.Mutex
+	w   io.Writer
+	err error
+	buf []byte
+}
+
+// NewWriter creates a new Writer.
+// It writes the Section Header Block and the Interface Description Block to w.
+// Since a Write call is made for every block, it is recommended to use a buffered writer.
+func NewWriter(w io.Writer) (*Writer, error) {
+	wr := &Writer{w: w}
+
+	// Section Header Block
+	var shb []byte
+	shb = binary.LittleEndian.AppendUint32(shb, byteOrderMagic)
+	shb = binary.LittleEndian.AppendUint16(shb, 1) // major version
+	shb = binary.LittleEndian.AppendUint16(shb, 0) // minor version
+	shb = binary.LittleEndian.AppendUint64(shb, 0xffffffffffffffff)
+	if err := wr.writeBlock(blockTypeSectionHeader, shb); err != nil {
+		return nil, err
+	}
+
+	// Interface Description Block
+	var idb []byte
+	idb = binary.LittleEndian.AppendUint16(idb, linkTypeRaw)
+	idb = binary.LittleEndian.AppendUint16(idb, 0) // reserved
+	idb = binary.LittleEndian.AppendUint32(idb, 0) // no snap length
+	idb = binary.LittleEndian.AppendUint16(idb, optionTimestampResolution)
+	idb = binary.LittleEndian.AppendUint16(idb, 1)
+	idb = append(idb, timestampResolutionNanoseconds, 0, 0, 0)
+	idb = binary.LittleEndian.AppendUint16(idb, optionEndOfOpt)
+	idb = binary.LittleEndian.AppendUint16(idb, 0)
+	if err := wr.writeBlock(blockTypeInterfaceDescription, idb); err != nil {
+		return nil, err
+	}
+	return wr, nil
+}
+
+// WritePacket writes a UDP datagram sent from src to dst.
+// The ECN bits are written to the IP header.
+// Both addresses need to be of the same IP version.
+// IPv4-mapped IPv6 addresses are written as IPv4 addresses.
+func (w *Writer) WritePacket(t time.Time, src, dst netip.AddrPort, ecn uint8, payload []byte) error {
+	srcIP, dstIP := src.Addr().Unmap(), dst.Addr().Unmap()
+	if srcIP.Is4() != dstIP.Is4() {
+		return errors.New("pcapng: source and destination address are of different IP versions")
+	}
+	ipHeaderLen := ipv6HeaderLen
+	if srcIP.Is4() {
+		ipHeaderLen = ipv4HeaderLen
+	}
+	if len(payload) > 0xffff-ipHeaderLen-udpHeaderLen {
+		return errors.New("pcapng: packet too large")
+	}
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	// Enhanced Packet Block
+	ts := uint64(t.UnixNano())
+	packetLen := ipHeaderLen + udpHeaderLen + len(payload)
+	b := w.buf[:0]
+	b = binary.LittleEndian.AppendUint32(b, 0) // interface ID
+	b = binary.LittleEndian.AppendUint32(b, uint32(ts>>32))
+	b = binary.LittleEndian.AppendUint32(b, uint32(ts))
+	b = binary.LittleEndian.AppendUint32(b, uint32(packetLen)) // captured length
+	b = binary.LittleEndian.AppendUint32(b, uint32(packetLen)) // original length
+	if srcIP.Is4() {
+		b = appendIPv4Header(b, srcIP, dstIP, ecn, packetLen)
+	} else {
+		b = appendIPv6Header(b, srcIP, dstIP, ecn, udpHeaderLen+len(payload))
+	}
+	b = appendUDPHeader(b, srcIP, dstIP, src.Port(), dst.Port(), payload)
+	b = append(b, payload...)
+	w.buf = b
+	return w.writeBlock(blockTypeEnhancedPacket, b)
+}
+
+// WriteSecrets writes TLS secrets, in the NSS Key Log format, to a Decryption Secrets Block.
+func (w *Writer) WriteSecrets(keyLog []byte) error {
+	b := make([]byte, 0, 8+len(keyLog))
+	b = binary.LittleEndian.AppendUint32(b, secretsTypeTLSKeyLog)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(keyLog)))
+	b = append(b, keyLog...)
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+	return w.writeBlock(blockTypeDecryptionSecrets, b)
+}
+
+// KeyLogWriter returns an io.Writer that writes TLS secrets to Decryption Secrets Blocks.
+// It can be used as the tls.Config.KeyLogWriter.
+func (w *Writer) KeyLogWriter() io.Writer {
+	return keyLogWriter{w: w}
+}
+
+type keyLogWriter struct{ w *Writer }
+
+func (w keyLogWriter) Write(p []byte) (int, error) {
+	if err := w.w.WriteSecrets(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeBlock writes a block with the given body.
+// The body is padded to 32 bits.
+// Once writing fails, all subsequent calls return the same error.
+func (w *Writer) writeBlock(blockType uint32, body []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	padding := (4 - len(body)%4) % 4
+	totalLen := uint32(12 + len(body) + padding)
+	var hdr, trailer [8]byte
+	binary.LittleEndian.PutUint32(hdr[:4], blockType)
+	binary.LittleEndian.PutUint32(hdr[4:], totalLen)
+	binary.LittleEndian.PutUint32(trailer[padding:], totalLen)
+	if _, w.err = w.w.Write(hdr[:]); w.err != nil {
+		return w.err
+	}
+	if _, w.err = w.w.Write(body); w.err != nil {
+		return w.err
+	}
+	_, w.err = w.w.Write(trailer[:padding+4])
+	return w.err
+}
+
+func appendIPv4Header(b []byte, src, dst netip.Addr, ecn uint8, totalLen int) []byte {
+	start := len(b)
+	b = append(b, 0x45, ecn&0b11) // version 4, header length 5 words, DSCP 0
+	b = binary.BigEndian.AppendUint16(b, uint16(totalLen))
+	b = binary.BigEndian.AppendUint16(b, 0)      // identification
+	b = binary.BigEndian.AppendUint16(b, 0x4000) // Don't Fragment
+	b = append(b, defaultTTL, ipProtocolUDP)
+	b = binary.BigEndian.AppendUint16(b, 0) // checksum, set below
+	b = append(b, src.AsSlice()...)
+	b = append(b, dst.AsSlice()...)
+	binary.BigEndian.PutUint16(b[start+10:], ^foldChecksum(checksum(0, b[start:])))
+	return b
+}
+
+func appendIPv6Header(b []byte, src, dst netip.Addr, ecn uint8, payloadLen int) []byte {
+	// version 6, the ECN bits are the lowest bits of the traffic class
+	b = binary.BigEndian.AppendUint32(b, 6<<28|uint32(ecn&0b11)<<20)
+	b = binary.BigEndian.AppendUint16(b, uint16(payloadLen))
+	b = append(b, ipProtocolUDP, defaultTTL)
+	b = append(b, src.AsSlice()...)
+	b = append(b, dst.AsSlice()...)
+	return b
+}
+
+func appendUDPHeader(b []byte, src, dst netip.Addr, srcPort, dstPort uint16, payload []byte) []byte {
+	udpLen := udpHeaderLen + len(payload)
+	start := len(b)
+	b = binary.BigEndian.AppendUint16(b, srcPort)
+	b = binary.BigEndian.AppendUint16(b, dstPort)
+	b = binary.BigEndian.AppendUint16(b, uint16(udpLen))
+	b = binary.BigEndian.AppendUint16(b, 0) // checksum, set below
+
+	// pseudo header
+	sum := checksum(0, src.AsSlice())
+	sum = checksum(sum, dst.AsSlice())
+	sum += ipProtocolUDP + uint32(udpLen)
+	sum = checksum(sum, b[start:])
+	sum = checksum(sum, payload)
+	cs := ^foldChecksum(sum)
+	if cs == 0 { // a checksum of 0 means that no checksum was calculated
+		cs = 0xffff
+	}
+	binary.BigEndian.PutUint16(b[start+6:], cs)
+	return b
+}
+
+// checksum adds data to the one's complement sum used for IP and UDP checksums.
+// data must have an even length, unless it's the last data added to the sum.
+func checksum(sum uint32, data []byte) uint32 {
+	for len(data) >= 2 {
+		sum += uint32(data[0])<<8 | uint32(data[1])
+		data = data[2:]
+	}
+	if len(data) == 1 {
+		sum += uint32(data[0]) << 8
+	}
+	return uint32(foldChecksum(sum))
+}
+
+func foldChecksum(sum uint32) uint16 {
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+	return uint16(sum)
+}
@@ -0,0 +1,208 @@
+package pcapng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type block struct {
+	typ  uint32
+	body []byte
+}
+
+func parseBlocks(t *testing.T, data []byte) []block {
+	t.Helper()
+	var blocks []block
+	for len(data) > 0 {
+		require.GreaterOrEqual(t, len(data), 12)
+		typ := binary.LittleEndian.Uint32(data)
+		l := binary.LittleEndian.Uint32(data[4:])
+		require.Zero(t, l%4)
+		require.GreaterOrEqual(t, len(data), int(l))
+		require.Equal(t, l, binary.LittleEndian.Uint32(data[l-4:]))
+		blocks = append(blocks, block{typ: typ, body: data[8 : l-4]})
+		data = data[l:]
+	}
+	return blocks
+}
+
+type packet struct {
+	time    time.Time
+	ipData  []byte
+	payload []byte
+}
+
+func parseEnhancedPacketBlock(t *testing.T, b block) packet {
+	t.Helper()
+	require.Equal(t, uint32(blockTypeEnhancedPacket), b.typ)
+	require.Zero(t, binary.LittleEndian.Uint32(b.body)) // interface ID
+	ts := uint64(binary.LittleEndian.Uint32(b.body[4:]))<<32 | uint64(binary.LittleEndian.Uint32(b.body[8:]))
+	capLen := binary.LittleEndian.Uint32(b.body[12:])
+	require.Equal(t, capLen, binary.LittleEndian.Uint32(b.body[16:]))
+	data := b.body[20 : 20+capLen]
+	// the rest is padding
+	require.Less(t, len(b.body)-20-int(capLen), 4)
+	return packet{time: time.Unix(0, int64(ts)), ipData: data}
+}
+
+// verifyChecksum verifies that the one's complement sum over the data (including the checksum) is 0xffff
+func verifyChecksum(t *testing.T, sum uint32, data []byte) {
+	t.Helper()
+	require.Equal(t, uint16(0xffff), foldChecksum(checksum(sum, data)))
+}
+
+func TestWriterHeader(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := NewWriter(&buf)
+	require.NoError(t, err)
+
+	blocks := parseBlocks(t, buf.Bytes())
+	require.Len(t, blocks, 2)
+	require.Equal(t, uint32(blockTypeSectionHeader), blocks[0].typ)
+	require.Equal(t, uint32(byteOrderMagic), binary.LittleEndian.Uint32(blocks[0].body))
+	require.Equal(t, uint16(1), binary.LittleEndian.Uint16(blocks[0].body[4:]))
+	require.Equal(t, uint16(0), binary.LittleEndian.Uint16(blocks[0].body[6:]))
+
+	require.Equal(t, uint32(blockTypeInterfaceDescription), blocks[1].typ)
+	require.Equal(t, uint16(linkTypeRaw), binary.LittleEndian.Uint16(blocks[1].body))
+	// if_tsresol option
+	require.Equal(t, uint16(optionTimestampResolution), binary.LittleEndian.Uint16(blocks[1].body[8:]))
+	require.Equal(t, uint16(1), binary.LittleEndian.Uint16(blocks[1].body[10:]))
+	require.Equal(t, uint8(timestampResolutionNanoseconds), blocks[1].body[12])
+}
+
+func TestWriterIPv4Packet(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	now := time.Now()
+	src := netip.MustParseAddrPort("192.0.2.1:1234")
+	dst := netip.MustParseAddrPort("[::ffff:198.51.100.2]:443") // IPv4-mapped
+	payload := []byte("foobar!")                                // odd length
+	require.NoError(t, w.WritePacket(now, src, dst, 0b10, payload))
+
+	blocks := parseBlocks(t, buf.Bytes())
+	require.Len(t, blocks, 3)
+	p := parseEnhancedPacketBlock(t, blocks[2])
+	require.True(t, p.time.Equal(now))
+	require.Len(t, p.ipData, 20+8+len(payload))
+
+	ip := p.ipData[:20]
+	require.Equal(t, uint8(0x45), ip[0])
+	require.Equal(t, uint8(0b10), ip[1]) // ECN
+	require.Equal(t, uint16(len(p.ipData)), binary.BigEndian.Uint16(ip[2:]))
+	require.Equal(t, uint8(ipProtocolUDP), ip[9])
+	require.Equal(t, []byte{192, 0, 2, 1}, ip[12:16])
+	require.Equal(t, []byte{198, 51, 100, 2}, ip[16:20])
+	verifyChecksum(t, 0, ip)
+
+	udp := p.ipData[20:]
+	require.Equal(t, uint16(1234), binary.BigEndian.Uint16(udp))
+	require.Equal(t, uint16(443), binary.BigEndian.Uint16(udp[2:]))
+	require.Equal(t, uint16(8+len(payload)), binary.BigEndian.Uint16(udp[4:]))
+	require.Equal(t, payload, udp[8:])
+	pseudoHeader := checksum(checksum(0, ip[12:20]), []byte{0, ipProtocolUDP, 0, byte(len(udp))})
+	verifyChecksum(t, pseudoHeader, udp)
+}
+
+func TestWriterIPv6Packet(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	src := netip.MustParseAddrPort("[2001:db8::1]:443")
+	dst := netip.MustParseAddrPort("[2001:db8::2]:1234")
+	payload := bytes.Repeat([]byte{0xaa}, 1200)
+	require.NoError(t, w.WritePacket(time.Now(), src, dst, 0b11, payload))
+
+	blocks := parseBlocks(t, buf.Bytes())
+	require.Len(t, blocks, 3)
+	p := parseEnhancedPacketBlock(t, blocks[2])
+	require.Len(t, p.ipData, 40+8+len(payload))
+
+	ip := p.ipData[:40]
+	require.Equal(t, uint8(6), ip[0]>>4)
+	require.Equal(t, uint32(0b11), binary.BigEndian.Uint32(ip)>>20&0xff) // ECN
+	require.Equal(t, uint16(8+len(payload)), binary.BigEndian.Uint16(ip[4:]))
+	require.Equal(t, uint8(ipProtocolUDP), ip[6])
+	require.Equal(t, src.Addr().AsSlice(), ip[8:24])
+	require.Equal(t, dst.Addr().AsSlice(), ip[24:40])
+
+	udp := p.ipData[40:]
+	require.Equal(t, uint16(443), binary.BigEndian.Uint16(udp))
+	require.Equal(t, uint16(1234), binary.BigEndian.Uint16(udp[2:]))
+	require.Equal(t, payload, udp[8:])
+	var udpLen [4]byte
+	binary.BigEndian.PutUint32(udpLen[:], uint32(len(udp)))
+	pseudoHeader := checksum(checksum(checksum(0, ip[8:40]), udpLen[:]), []byte{0, 0, 0, ipProtocolUDP})
+	verifyChecksum(t, pseudoHeader, udp)
+}
+
+func TestWriterInvalidPackets(t *testing.T) {
+	w, err := NewWriter(&bytes.Buffer{})
+	require.NoError(t, err)
+	require.ErrorContains(t,
+		w.WritePacket(time.Now(), netip.MustParseAddrPort("192.0.2.1:1234"), netip.MustParseAddrPort("[2001:db8::1]:443"), 0, nil),
+		"different IP versions",
+	)
+	require.ErrorContains(t,
+		w.WritePacket(time.Now(), netip.MustParseAddrPort("192.0.2.1:1234"), netip.MustParseAddrPort("192.0.2.2:443"), 0, make([]byte, 1<<16)),
+		"packet too large",
+	)
+}
+
+func TestWriterSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	line1 := []byte("CLIENT_HANDSHAKE_TRAFFIC_SECRET 0102 0304\n")
+	line2 := []byte("SERVER_TRAFFIC_SECRET_0 0102 050607\n")
+	require.NoError(t, w.WriteSecrets(line1))
+	n, err := w.KeyLogWriter().Write(line2)
+	require.NoError(t, err)
+	require.Equal(t, len(line2), n)
+
+	blocks := parseBlocks(t, buf.Bytes())
+	require.Len(t, blocks, 4)
+	for i, line := range [][]byte{line1, line2} {
+		b := blocks[2+i]
+		require.Equal(t, uint32(blockTypeDecryptionSecrets), b.typ)
+		require.Equal(t, uint32(secretsTypeTLSKeyLog), binary.LittleEndian.Uint32(b.body))
+		l := binary.LittleEndian.Uint32(b.body[4:])
+		require.Equal(t, line, b.body[8:8+l])
+	}
+}
+
+type errWriter struct {
+	n int // number of successful writes
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("write failed")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestWriterErrors(t *testing.T) {
+	_, err := NewWriter(&errWriter{})
+	require.EqualError(t, err, "write failed")
+
+	ew := &errWriter{n: 6} // enough for the section header and the interface description block
+	w, err := NewWriter(ew)
+	require.NoError(t, err)
+	src := netip.MustParseAddrPort("192.0.2.1:1234")
+	dst := netip.MustParseAddrPort("192.0.2.2:443")
+	require.EqualError(t, w.WritePacket(time.Now(), src, dst, 0, []byte("foo")), "write failed")
+	// errors are sticky
+	ew.n = 100
+	require.EqualError(t, w.WritePacket(time.Now(), src, dst, 0, []byte("foo")), "write failed")
+	_, err = w.KeyLogWriter().Write([]byte("foo"))
+	require.EqualError(t, err, "write failed")
+}
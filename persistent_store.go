@@ -0,0 +1,268 @@
+package quic
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// loadPersistentStore reads the JSON-encoded content of the file at path into v.
+// It is not an error if the file doesn't exist.
+func loadPersistentStore(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// savePersistentStore writes the JSON encoding of v to the file at path.
+// The file is replaced atomically, so readers never observe a partially written file.
+func savePersistentStore(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// The temporary file is created with permissions 0600,
+	// which is what we want, since it contains secret key material.
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+type persistedToken struct {
+	Data     []byte        `json:"data"`
+	RTT      time.Duration `json:"rtt"`
+	Received time.Time     `json:"received"`
+}
+
+type persistedTokenOrigin struct {
+	Key    string           `json:"key"`
+	Tokens []persistedToken `json:"tokens"` // the most recently received token is the last element
+}
+
+type fileTokenStore struct {
+	path            string
+	maxOrigins      int
+	tokensPerOrigin int
+	maxAge          time.Duration
+
+	mutex   sync.Mutex
+	origins []persistedTokenOrigin // the most recently used origin is the last element
+}
+
+var _ TokenStore = &fileTokenStore{}
+
+// NewFileTokenStore creates a TokenStore that persists tokens to the file at path,
+// allowing a client to skip address validation after a restart.
+// maxOrigins specifies how many origins this cache is saving tokens for.
+// tokensPerOrigin specifies the maximum number of tokens per origin.
+// Tokens received more than maxAge ago are not used. If maxAge is 0, tokens don't expire.
+// The file is rewritten every time a token is added or removed.
+// Errors writing the file are ignored, in that case the store continues to work in memory.
+// Multiple processes should not use the same file concurrently, as they would overwrite each other's updates.
+func NewFileTokenStore(path string, maxOrigins, tokensPerOrigin int, maxAge time.Duration) (TokenStore, error) {
+	s := &fileTokenStore{
+		path:            path,
+		maxOrigins:      maxOrigins,
+		tokensPerOrigin: tokensPerOrigin,
+		maxAge:          maxAge,
+	}
+	if err := loadPersistentStore(path, &s.origins); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileTokenStore) Put(key string, token *ClientToken) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	origin := persistedTokenOrigin{Key: key}
+	if i := s.index(key); i >= 0 {
+		origin = s.origins[i]
+		s.origins = slices.Delete(s.origins, i, i+1)
+	}
+	origin.Tokens = append(origin.Tokens, persistedToken{Data: token.data, RTT: token.rtt, Received: time.Now()})
+	if len(origin.Tokens) > s.tokensPerOrigin {
+		origin.Tokens = origin.Tokens[len(origin.Tokens)-s.tokensPerOrigin:]
+	}
+	s.origins = append(s.origins, origin)
+	if len(s.origins) > s.maxOrigins {
+		s.origins = s.origins[len(s.origins)-s.maxOrigins:]
+	}
+	s.save()
+}
+
+func (s *fileTokenStore) Pop(key string) *ClientToken {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	i := s.index(key)
+	if i < 0 {
+		return nil
+	}
+	origin := s.origins[i]
+	s.origins = slices.Delete(s.origins, i, i+1)
+	// remove expired tokens
+	if s.maxAge > 0 {
+		origin.Tokens = slices.DeleteFunc(origin.Tokens, func(t persistedToken) bool {
+			return time.Since(t.Received) > s.maxAge
+		})
+	}
+	var token *ClientToken
+	if n := len(origin.Tokens); n > 0 {
+		t := origin.Tokens[n-1]
+		token = &ClientToken{data: t.Data, rtt: t.RTT}
+		origin.Tokens = origin.Tokens[:n-1]
+	}
+	if len(origin.Tokens) > 0 {
+		s.origins = append(s.origins, origin)
+	}
+	s.save()
+	return token
+}
+
+func (s *fileTokenStore) index(key string) int {
+	return slices.IndexFunc(s.origins, func(o persistedTokenOrigin) bool { return o.Key == key })
+}
+
+func (s *fileTokenStore) save() {
+	_ = savePersistentStore(s.path, s.origins)
+}
+
+type persistedSession struct {
+	Key string `json:"key"`
+	// Ticket is the session ticket sent by the server.
+	Ticket []byte `json:"ticket"`
+	// State is the serialized tls.SessionState.
+	// Among other things, it contains the transport parameters needed for 0-RTT.
+	State  []byte    `json:"state"`
+	Stored time.Time `json:"stored"`
+}
+
+type fileClientSessionCache struct {
+	path     string
+	capacity int
+	maxAge   time.Duration
+
+	mutex    sync.Mutex
+	sessions []persistedSession // the most recently used session is the last element
+}
+
+var _ tls.ClientSessionCache = &fileClientSessionCache{}
+
+// NewFileClientSessionCache creates a tls.ClientSessionCache that persists session tickets to the file at path.
+// It also persists the server's transport parameters that quic-go saves with the session ticket,
+// so that a restarted client can resume a session and use 0-RTT.
+// capacity is the maximum number of sessions that are stored.
+// Sessions stored more than maxAge ago are not used. If maxAge is 0, sessions only expire when
+// the session ticket's lifetime, as determined by the server, ends.
+// The file is rewritten every time a session is added or removed.
+// Errors writing the file are ignored, in that case the cache continues to work in memory.
+// Multiple processes should not use the same file concurrently, as they would overwrite each other's updates.
+func NewFileClientSessionCache(path string, capacity int, maxAge time.Duration) (tls.ClientSessionCache, error) {
+	c := &fileClientSessionCache{
+		path:     path,
+		capacity: capacity,
+		maxAge:   maxAge,
+	}
+	if err := loadPersistentStore(path, &c.sessions); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *fileClientSessionCache) Get(key string) (*tls.ClientSessionState, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	i := c.index(key)
+	if i < 0 {
+		return nil, false
+	}
+	session := c.sessions[i]
+	c.sessions = slices.Delete(c.sessions, i, i+1)
+	if c.maxAge > 0 && time.Since(session.Stored) > c.maxAge {
+		c.save()
+		return nil, false
+	}
+	state, err := tls.ParseSessionState(session.State)
+	if err != nil {
+		c.save()
+		return nil, false
+	}
+	cs, err := tls.NewResumptionState(session.Ticket, state)
+	if err != nil {
+		c.save()
+		return nil, false
+	}
+	c.sessions = append(c.sessions, session)
+	return cs, true
+}
+
+func (c *fileClientSessionCache) Put(key string, cs *tls.ClientSessionState) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if i := c.index(key); i >= 0 {
+		c.sessions = slices.Delete(c.sessions, i, i+1)
+	}
+	// crypto/tls removes a session from the cache by putting a nil session
+	if cs == nil {
+		c.save()
+		return
+	}
+	ticket, state, err := cs.ResumptionState()
+	if err != nil || state == nil {
+		c.save()
+		return
+	}
+	stateBytes, err := state.Bytes()
+	if err != nil {
+		c.save()
+		return
+	}
+	c.sessions = append(c.sessions, persistedSession{
+		Key:    key,
+		Ticket: ticket,
+		State:  stateBytes,
+		Stored: time.Now(),
+	})
+	if len(c.sessions) > c.capacity {
+		c.sessions = c.sessions[len(c.sessions)-c.capacity:]
+	}
+	c.save()
+}
+
+func (c *fileClientSessionCache) index(key string) int {
+	return slices.IndexFunc(c.sessions, func(s persistedSession) bool { return s.Key == key })
+}
+
+func (c *fileClientSessionCache) save() {
+	_ = savePersistentStore(c.path, c.sessions)
+}
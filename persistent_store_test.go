@@ -0,0 +1,188 @@
+package quic
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/synctest"
+	"github.com/quic-go/quic-go/internal/testdata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+
+	s, err := NewFileTokenStore(path, 2, 2, 0)
+	require.NoError(t, err)
+	s.Put("origin1", mockToken(1))
+	s.Put("origin1", mockToken(2))
+	s.Put("origin1", mockToken(3))
+	s.Put("origin2", mockToken(4))
+
+	// the tokens are restored from the file
+	s, err = NewFileTokenStore(path, 2, 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, mockToken(3), s.Pop("origin1"))
+	require.Equal(t, mockToken(2), s.Pop("origin1"))
+	require.Nil(t, s.Pop("origin1"))
+
+	// popped tokens are removed from the file
+	s, err = NewFileTokenStore(path, 2, 2, 0)
+	require.NoError(t, err)
+	require.Nil(t, s.Pop("origin1"))
+	// the least recently used origin is evicted
+	s.Put("origin3", mockToken(5))
+	s.Put("origin1", mockToken(6))
+	require.Nil(t, s.Pop("origin2"))
+	require.Equal(t, mockToken(5), s.Pop("origin3"))
+	require.Equal(t, mockToken(6), s.Pop("origin1"))
+}
+
+func TestFileTokenStoreExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+
+	synctest.Test(t, func(t *testing.T) {
+		s, err := NewFileTokenStore(path, 1, 3, time.Hour)
+		require.NoError(t, err)
+		s.Put("origin", mockToken(1))
+		time.Sleep(30 * time.Minute)
+		s.Put("origin", mockToken(2))
+		time.Sleep(30 * time.Minute)
+		s.Put("origin", mockToken(3))
+		time.Sleep(time.Minute)
+
+		require.Equal(t, mockToken(3), s.Pop("origin"))
+		require.Equal(t, mockToken(2), s.Pop("origin"))
+		require.Nil(t, s.Pop("origin"))
+	})
+}
+
+func TestFileTokenStoreCorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	require.NoError(t, os.WriteFile(path, []byte("foobar"), 0o600))
+	_, err := NewFileTokenStore(path, 1, 1, 0)
+	require.Error(t, err)
+	_, err = NewFileClientSessionCache(path, 1, 0)
+	require.Error(t, err)
+}
+
+type recordingClientSessionCache struct {
+	sessions chan *tls.ClientSessionState
+}
+
+func (c *recordingClientSessionCache) Get(string) (*tls.ClientSessionState, bool) { return nil, false }
+func (c *recordingClientSessionCache) Put(_ string, cs *tls.ClientSessionState) {
+	if cs != nil {
+		c.sessions <- cs
+	}
+}
+
+// getClientSessionState performs a TLS handshake and returns the session received from the server.
+func getClientSessionState(t *testing.T) *tls.ClientSessionState {
+	t.Helper()
+
+	cache := &recordingClientSessionCache{sessions: make(chan *tls.ClientSessionState, 1)}
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	go func() {
+		server := tls.Server(c2, testdata.GetTLSConfig())
+		if err := server.Handshake(); err != nil {
+			return
+		}
+		server.Write([]byte("foobar"))
+	}()
+	client := tls.Client(c1, &tls.Config{
+		ServerName:         "localhost",
+		RootCAs:            testdata.GetRootCA(),
+		ClientSessionCache: cache,
+	})
+	require.NoError(t, client.Handshake())
+	// reading application data processes the NewSessionTicket message
+	_, err := client.Read(make([]byte, 6))
+	require.NoError(t, err)
+
+	select {
+	case cs := <-cache.sessions:
+		return cs
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	return nil
+}
+
+func requireSameSession(t *testing.T, expected, actual *tls.ClientSessionState) {
+	t.Helper()
+
+	ticket1, state1, err := expected.ResumptionState()
+	require.NoError(t, err)
+	ticket2, state2, err := actual.ResumptionState()
+	require.NoError(t, err)
+	require.Equal(t, ticket1, ticket2)
+	b1, err := state1.Bytes()
+	require.NoError(t, err)
+	b2, err := state2.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, b1, b2)
+}
+
+func TestFileClientSessionCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	cs1 := getClientSessionState(t)
+	cs2 := getClientSessionState(t)
+	cs3 := getClientSessionState(t)
+
+	c, err := NewFileClientSessionCache(path, 2, 0)
+	require.NoError(t, err)
+	c.Put("key1", cs1)
+	c.Put("key2", cs2)
+	_, ok := c.Get("key1") // key1 is now the most recently used session
+	require.True(t, ok)
+	c.Put("key3", cs3)
+
+	// the sessions are restored from the file
+	c, err = NewFileClientSessionCache(path, 2, 0)
+	require.NoError(t, err)
+	_, ok = c.Get("key2")
+	require.False(t, ok)
+	cs, ok := c.Get("key1")
+	require.True(t, ok)
+	requireSameSession(t, cs1, cs)
+	cs, ok = c.Get("key3")
+	require.True(t, ok)
+	requireSameSession(t, cs3, cs)
+
+	// putting a nil session deletes the session
+	c.Put("key1", nil)
+	c, err = NewFileClientSessionCache(path, 2, 0)
+	require.NoError(t, err)
+	_, ok = c.Get("key1")
+	require.False(t, ok)
+	_, ok = c.Get("key3")
+	require.True(t, ok)
+}
+
+func TestFileClientSessionCacheExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	cs := getClientSessionState(t)
+
+	synctest.Test(t, func(t *testing.T) {
+		c, err := NewFileClientSessionCache(path, 2, time.Hour)
+		require.NoError(t, err)
+		c.Put("key1", cs)
+		time.Sleep(59 * time.Minute)
+		c.Put("key2", cs)
+		time.Sleep(2 * time.Minute)
+
+		_, ok := c.Get("key1")
+		require.False(t, ok)
+		_, ok = c.Get("key2")
+		require.True(t, ok)
+	})
+}
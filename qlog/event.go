@@ -7,6 +7,7 @@ import (
 
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/qlogwriter"
 	"github.com/quic-go/quic-go/qlogwriter/jsontext"
 )
 
@@ -85,6 +86,8 @@ type StartedConnection struct {
 
 func (e StartedConnection) Name() string { return "transport:connection_started" }
 
+func (e StartedConnection) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e StartedConnection) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -107,6 +110,8 @@ type VersionInformation struct {
 
 func (e VersionInformation) Name() string { return "transport:version_information" }
 
+func (e VersionInformation) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e VersionInformation) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -141,6 +146,8 @@ type ConnectionClosed struct {
 
 func (e ConnectionClosed) Name() string { return "transport:connection_closed" }
 
+func (e ConnectionClosed) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e ConnectionClosed) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -223,6 +230,8 @@ type PacketSent struct {
 
 func (e PacketSent) Name() string { return "transport:packet_sent" }
 
+func (e PacketSent) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e PacketSent) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -267,6 +276,8 @@ type PacketReceived struct {
 
 func (e PacketReceived) Name() string { return "transport:packet_received" }
 
+func (e PacketReceived) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e PacketReceived) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -307,6 +318,10 @@ type VersionNegotiationReceived struct {
 
 func (e VersionNegotiationReceived) Name() string { return "transport:packet_received" }
 
+func (e VersionNegotiationReceived) Importance() qlogwriter.Importance {
+	return qlogwriter.ImportanceCore
+}
+
 func (e VersionNegotiationReceived) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -329,6 +344,8 @@ type VersionNegotiationSent struct {
 
 func (e VersionNegotiationSent) Name() string { return "transport:packet_sent" }
 
+func (e VersionNegotiationSent) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e VersionNegotiationSent) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -351,6 +368,8 @@ type PacketBuffered struct {
 
 func (e PacketBuffered) Name() string { return "transport:packet_buffered" }
 
+func (e PacketBuffered) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e PacketBuffered) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -377,6 +396,8 @@ type PacketDropped struct {
 
 func (e PacketDropped) Name() string { return "transport:packet_dropped" }
 
+func (e PacketDropped) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e PacketDropped) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -401,6 +422,8 @@ type MTUUpdated struct {
 
 func (e MTUUpdated) Name() string { return "recovery:mtu_updated" }
 
+func (e MTUUpdated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceExtra }
+
 func (e MTUUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -423,10 +446,16 @@ type MetricsUpdated struct {
 	CongestionWindow int
 	BytesInFlight    int
 	PacketsInFlight  int
+	// PacingRate is the pacing rate, in bits per second.
+	PacingRate uint64
+	// BandwidthEstimate is the congestion controller's bandwidth estimate, in bits per second.
+	BandwidthEstimate uint64
 }
 
 func (e MetricsUpdated) Name() string { return "recovery:metrics_updated" }
 
+func (e MetricsUpdated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e MetricsUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -458,6 +487,14 @@ func (e MetricsUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 		h.WriteToken(jsontext.String("packets_in_flight"))
 		h.WriteToken(jsontext.Uint(uint64(e.PacketsInFlight)))
 	}
+	if e.PacingRate != 0 {
+		h.WriteToken(jsontext.String("pacing_rate"))
+		h.WriteToken(jsontext.Uint(e.PacingRate))
+	}
+	if e.BandwidthEstimate != 0 {
+		h.WriteToken(jsontext.String("bandwidth_estimate"))
+		h.WriteToken(jsontext.Uint(e.BandwidthEstimate))
+	}
 	h.WriteToken(jsontext.EndObject)
 	return h.err
 }
@@ -470,6 +507,8 @@ type PTOCountUpdated struct {
 
 func (e PTOCountUpdated) Name() string { return "recovery:metrics_updated" }
 
+func (e PTOCountUpdated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e PTOCountUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -486,6 +525,8 @@ type PacketLost struct {
 
 func (e PacketLost) Name() string { return "recovery:packet_lost" }
 
+func (e PacketLost) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e PacketLost) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -508,6 +549,8 @@ type SpuriousLoss struct {
 
 func (e SpuriousLoss) Name() string { return "recovery:spurious_loss" }
 
+func (e SpuriousLoss) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e SpuriousLoss) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -532,6 +575,8 @@ type KeyUpdated struct {
 
 func (e KeyUpdated) Name() string { return "security:key_updated" }
 
+func (e KeyUpdated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e KeyUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -554,6 +599,8 @@ type KeyDiscarded struct {
 
 func (e KeyDiscarded) Name() string { return "security:key_discarded" }
 
+func (e KeyDiscarded) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e KeyDiscarded) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -594,6 +641,7 @@ type ParametersSet struct {
 	PreferredAddress                *PreferredAddress
 	MaxDatagramFrameSize            protocol.ByteCount
 	EnableResetStreamAt             bool
+	InitialMaxPathID                *protocol.PathID
 }
 
 func (e ParametersSet) Name() string {
@@ -603,6 +651,13 @@ func (e ParametersSet) Name() string {
 	return "transport:parameters_set"
 }
 
+func (e ParametersSet) Importance() qlogwriter.Importance {
+	if e.Restore {
+		return qlogwriter.ImportanceBase
+	}
+	return qlogwriter.ImportanceCore
+}
+
 func (e ParametersSet) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -684,6 +739,10 @@ func (e ParametersSet) Encode(enc *jsontext.Encoder, _ time.Time) error {
 		h.WriteToken(jsontext.String("reset_stream_at"))
 		h.WriteToken(jsontext.True)
 	}
+	if e.InitialMaxPathID != nil {
+		h.WriteToken(jsontext.String("initial_max_path_id"))
+		h.WriteToken(jsontext.Uint(uint64(*e.InitialMaxPathID)))
+	}
 	h.WriteToken(jsontext.EndObject)
 	return h.err
 }
@@ -726,6 +785,8 @@ type LossTimerUpdated struct {
 
 func (e LossTimerUpdated) Name() string { return "recovery:loss_timer_updated" }
 
+func (e LossTimerUpdated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceExtra }
+
 func (e LossTimerUpdated) Encode(enc *jsontext.Encoder, t time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -747,6 +808,8 @@ type eventLossTimerCanceled struct{}
 
 func (e eventLossTimerCanceled) Name() string { return "recovery:loss_timer_updated" }
 
+func (e eventLossTimerCanceled) Importance() qlogwriter.Importance { return qlogwriter.ImportanceExtra }
+
 func (e eventLossTimerCanceled) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -762,6 +825,8 @@ type CongestionStateUpdated struct {
 
 func (e CongestionStateUpdated) Name() string { return "recovery:congestion_state_updated" }
 
+func (e CongestionStateUpdated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
 func (e CongestionStateUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -778,6 +843,8 @@ type ECNStateUpdated struct {
 
 func (e ECNStateUpdated) Name() string { return "recovery:ecn_state_updated" }
 
+func (e ECNStateUpdated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceExtra }
+
 func (e ECNStateUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
@@ -791,12 +858,92 @@ func (e ECNStateUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	return h.err
 }
 
+type MigrationStateUpdated struct {
+	State MigrationState
+	// Remote is the remote endpoint of the new path
+	Remote PathEndpointInfo
+}
+
+func (e MigrationStateUpdated) Name() string { return "transport:migration_state_updated" }
+
+func (e MigrationStateUpdated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceExtra }
+
+func (e MigrationStateUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
+	h := encoderHelper{enc: enc}
+	h.WriteToken(jsontext.BeginObject)
+	h.WriteToken(jsontext.String("new"))
+	h.WriteToken(jsontext.String(string(e.State)))
+	if e.Remote.IPv4.IsValid() || e.Remote.IPv6.IsValid() {
+		h.WriteToken(jsontext.String("path_remote"))
+		if err := e.Remote.encode(enc); err != nil {
+			return err
+		}
+	}
+	h.WriteToken(jsontext.EndObject)
+	return h.err
+}
+
+// ConnectionRejected is emitted when the server rejects a new connection
+// because one of the Transport's admission control limits was reached.
+// The connection is either refused with a CONNECTION_REFUSED error,
+// or the client is asked to validate its address using a Retry.
+type ConnectionRejected struct {
+	Remote PathEndpointInfo
+	Reason ConnectionRejectionReason
+	Retry  bool
+}
+
+func (e ConnectionRejected) Name() string { return "transport:connection_rejected" }
+
+func (e ConnectionRejected) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
+func (e ConnectionRejected) Encode(enc *jsontext.Encoder, _ time.Time) error {
+	h := encoderHelper{enc: enc}
+	h.WriteToken(jsontext.BeginObject)
+	h.WriteToken(jsontext.String("reason"))
+	h.WriteToken(jsontext.String(string(e.Reason)))
+	h.WriteToken(jsontext.String("retry"))
+	h.WriteToken(jsontext.Bool(e.Retry))
+	if e.Remote.IPv4.IsValid() || e.Remote.IPv6.IsValid() {
+		h.WriteToken(jsontext.String("path_remote"))
+		if err := e.Remote.encode(enc); err != nil {
+			return err
+		}
+	}
+	h.WriteToken(jsontext.EndObject)
+	return h.err
+}
+
+// RetryStateUpdated is emitted when the server starts or stops sending Retry packets
+// because of the number of handshakes with clients whose address hasn't been validated.
+type RetryStateUpdated struct {
+	Enabled               bool
+	UnvalidatedHandshakes int
+}
+
+func (e RetryStateUpdated) Name() string { return "transport:retry_state_updated" }
+
+func (e RetryStateUpdated) Importance() qlogwriter.Importance { return qlogwriter.ImportanceBase }
+
+func (e RetryStateUpdated) Encode(enc *jsontext.Encoder, _ time.Time) error {
+	h := encoderHelper{enc: enc}
+	h.WriteToken(jsontext.BeginObject)
+	h.WriteToken(jsontext.String("enabled"))
+	h.WriteToken(jsontext.Bool(e.Enabled))
+	h.WriteToken(jsontext.String("unvalidated_handshakes"))
+	h.WriteToken(jsontext.Int(int64(e.UnvalidatedHandshakes)))
+	h.WriteToken(jsontext.EndObject)
+	return h.err
+}
+
 type ALPNInformation struct {
 	ChosenALPN string
 }
 
 func (e ALPNInformation) Name() string { return "transport:alpn_information" }
 
+func (e ALPNInformation) Importance() qlogwriter.Importance { return qlogwriter.ImportanceCore }
+
 func (e ALPNInformation) Encode(enc *jsontext.Encoder, _ time.Time) error {
 	h := encoderHelper{enc: enc}
 	h.WriteToken(jsontext.BeginObject)
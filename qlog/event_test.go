@@ -213,6 +213,7 @@ func TestTransportCryptoError(t *testing.T) {
 
 func TestSentTransportParameters(t *testing.T) {
 	rcid := protocol.ParseConnectionID([]byte{0xde, 0xca, 0xfb, 0xad})
+	maxPathID := protocol.PathID(3)
 	name, ev := testEventEncoding(t, &ParametersSet{
 		Initiator:                       InitiatorLocal,
 		SentBy:                          protocol.PerspectiveServer,
@@ -234,6 +235,7 @@ func TestSentTransportParameters(t *testing.T) {
 		InitialMaxStreamsUni:            20,
 		MaxDatagramFrameSize:            protocol.InvalidByteCount,
 		EnableResetStreamAt:             true,
+		InitialMaxPathID:                &maxPathID,
 	})
 
 	require.Equal(t, "transport:parameters_set", name)
@@ -253,6 +255,7 @@ func TestSentTransportParameters(t *testing.T) {
 	require.Equal(t, float64(10), ev["initial_max_streams_bidi"])
 	require.Equal(t, float64(20), ev["initial_max_streams_uni"])
 	require.True(t, ev["reset_stream_at"].(bool))
+	require.Equal(t, float64(3), ev["initial_max_path_id"])
 	require.NotContains(t, ev, "preferred_address")
 	require.NotContains(t, ev, "max_datagram_frame_size")
 }
@@ -607,13 +610,15 @@ func TestMetricsUpdated(t *testing.T) {
 	rttStats.UpdateRTT(20*time.Millisecond, 0)
 	rttStats.UpdateRTT(25*time.Millisecond, 0)
 	name, ev := testEventEncoding(t, &MetricsUpdated{
-		MinRTT:           rttStats.MinRTT(),
-		SmoothedRTT:      rttStats.SmoothedRTT(),
-		LatestRTT:        rttStats.LatestRTT(),
-		RTTVariance:      rttStats.MeanDeviation(),
-		CongestionWindow: 4321,
-		BytesInFlight:    1234,
-		PacketsInFlight:  42,
+		MinRTT:            rttStats.MinRTT(),
+		SmoothedRTT:       rttStats.SmoothedRTT(),
+		LatestRTT:         rttStats.LatestRTT(),
+		RTTVariance:       rttStats.MeanDeviation(),
+		CongestionWindow:  4321,
+		BytesInFlight:     1234,
+		PacketsInFlight:   42,
+		PacingRate:        1e6,
+		BandwidthEstimate: 8e5,
 	})
 
 	require.Equal(t, "recovery:metrics_updated", name)
@@ -626,6 +631,8 @@ func TestMetricsUpdated(t *testing.T) {
 	require.Equal(t, float64(4321), ev["congestion_window"])
 	require.Equal(t, float64(1234), ev["bytes_in_flight"])
 	require.Equal(t, float64(42), ev["packets_in_flight"])
+	require.Equal(t, float64(1e6), ev["pacing_rate"])
+	require.Equal(t, float64(8e5), ev["bandwidth_estimate"])
 }
 
 func TestPacketLost(t *testing.T) {
@@ -831,6 +838,49 @@ func TestECNStateUpdatedWithTrigger(t *testing.T) {
 	require.Equal(t, "ACK doesn't contain ECN marks", ev["trigger"])
 }
 
+func TestMigrationStateUpdated(t *testing.T) {
+	name, ev := testEventEncoding(t, &MigrationStateUpdated{
+		State:  MigrationStateProbingStarted,
+		Remote: PathEndpointInfo{IPv4: netip.MustParseAddrPort("192.0.2.1:443")},
+	})
+
+	require.Equal(t, "transport:migration_state_updated", name)
+	require.Len(t, ev, 2)
+	require.Equal(t, "probing_started", ev["new"])
+	require.Equal(t, map[string]any{"ip_v4": "192.0.2.1", "port_v4": float64(443)}, ev["path_remote"])
+}
+
+func TestMigrationStateUpdatedNoRemote(t *testing.T) {
+	name, ev := testEventEncoding(t, &MigrationStateUpdated{State: MigrationStateMigrationComplete})
+
+	require.Equal(t, "transport:migration_state_updated", name)
+	require.Len(t, ev, 1)
+	require.Equal(t, "migration_complete", ev["new"])
+}
+
+func TestConnectionRejected(t *testing.T) {
+	name, ev := testEventEncoding(t, &ConnectionRejected{
+		Remote: PathEndpointInfo{IPv6: netip.MustParseAddrPort("[2001:db8::1]:443")},
+		Reason: ConnectionRejectionRateLimit,
+		Retry:  true,
+	})
+
+	require.Equal(t, "transport:connection_rejected", name)
+	require.Len(t, ev, 3)
+	require.Equal(t, "rate_limit", ev["reason"])
+	require.Equal(t, true, ev["retry"])
+	require.Equal(t, map[string]any{"ip_v6": "2001:db8::1", "port_v6": float64(443)}, ev["path_remote"])
+}
+
+func TestRetryStateUpdated(t *testing.T) {
+	name, ev := testEventEncoding(t, &RetryStateUpdated{Enabled: true, UnvalidatedHandshakes: 42})
+
+	require.Equal(t, "transport:retry_state_updated", name)
+	require.Len(t, ev, 2)
+	require.Equal(t, true, ev["enabled"])
+	require.Equal(t, float64(42), ev["unvalidated_handshakes"])
+}
+
 func TestALPNInformation(t *testing.T) {
 	name, ev := testEventEncoding(t, &ALPNInformation{
 		ChosenALPN: "h3",
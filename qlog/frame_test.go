@@ -419,3 +419,118 @@ func TestDatagramFrame(t *testing.T) {
 		},
 	)
 }
+
+func TestPathAckFrame(t *testing.T) {
+	check(t,
+		&PathAckFrame{
+			PathID: 2,
+			AckFrame: AckFrame{
+				DelayTime: 86 * time.Millisecond,
+				AckRanges: []AckRange{{Smallest: 100, Largest: 120}},
+				ECT0:      10,
+			},
+		},
+		map[string]any{
+			"frame_type":   "path_ack",
+			"path_id":      2,
+			"ack_delay":    86,
+			"acked_ranges": [][]float64{{100, 120}},
+			"ect0":         10,
+			"ect1":         0,
+			"ce":           0,
+		},
+	)
+}
+
+func TestPathAbandonFrame(t *testing.T) {
+	check(t,
+		&PathAbandonFrame{PathID: 3, ErrorCode: 0x1337},
+		map[string]any{
+			"frame_type": "path_abandon",
+			"path_id":    3,
+			"error_code": 0x1337,
+		},
+	)
+}
+
+func TestPathStatusFrame(t *testing.T) {
+	check(t,
+		&PathStatusFrame{PathID: 1, SequenceNumber: 42, Backup: true},
+		map[string]any{
+			"frame_type":                  "path_status_backup",
+			"path_id":                     1,
+			"path_status_sequence_number": 42,
+		},
+	)
+	check(t,
+		&PathStatusFrame{PathID: 1, SequenceNumber: 43},
+		map[string]any{
+			"frame_type":                  "path_status_available",
+			"path_id":                     1,
+			"path_status_sequence_number": 43,
+		},
+	)
+}
+
+func TestPathNewConnectionIDFrame(t *testing.T) {
+	check(t,
+		&PathNewConnectionIDFrame{
+			PathID:              2,
+			SequenceNumber:      42,
+			RetirePriorTo:       24,
+			ConnectionID:        protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef}),
+			StatelessResetToken: protocol.StatelessResetToken{0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8, 0x9, 0xa, 0xb, 0xc, 0xd, 0xe, 0xf, 0x0},
+		},
+		map[string]any{
+			"frame_type":            "path_new_connection_id",
+			"path_id":               2,
+			"sequence_number":       42,
+			"retire_prior_to":       24,
+			"length":                4,
+			"connection_id":         "deadbeef",
+			"stateless_reset_token": "0102030405060708090a0b0c0d0e0f00",
+		},
+	)
+}
+
+func TestPathRetireConnectionIDFrame(t *testing.T) {
+	check(t,
+		&PathRetireConnectionIDFrame{PathID: 2, SequenceNumber: 1337},
+		map[string]any{
+			"frame_type":      "path_retire_connection_id",
+			"path_id":         2,
+			"sequence_number": 1337,
+		},
+	)
+}
+
+func TestMaxPathIDFrame(t *testing.T) {
+	check(t,
+		&MaxPathIDFrame{MaximumPathID: 7},
+		map[string]any{
+			"frame_type":      "max_path_id",
+			"maximum_path_id": 7,
+		},
+	)
+}
+
+func TestPathsBlockedFrame(t *testing.T) {
+	check(t,
+		&PathsBlockedFrame{MaximumPathID: 7},
+		map[string]any{
+			"frame_type":      "paths_blocked",
+			"maximum_path_id": 7,
+		},
+	)
+}
+
+func TestPathConnectionIDsBlockedFrame(t *testing.T) {
+	check(t,
+		&PathConnectionIDsBlockedFrame{PathID: 3, NextSequenceNumber: 5},
+		map[string]any{
+			"frame_type":           "path_cids_blocked",
+			"path_id":              3,
+			"next_sequence_number": 5,
+		},
+	)
+}
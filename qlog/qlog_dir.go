@@ -4,11 +4,14 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"slices"
 	"strings"
+	"sync/atomic"
 
+	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/internal/utils"
 	"github.com/quic-go/quic-go/qlogwriter"
 )
@@ -20,18 +23,61 @@ const EventSchema = "urn:ietf:params:qlog:events:quic-12"
 // File names are <odcid>_<perspective>.sqlog.
 // Returns nil if QLOGDIR is not set.
 func DefaultConnectionTracer(_ context.Context, isClient bool, connID ConnectionID) qlogwriter.Trace {
-	return defaultConnectionTracerWithSchemas(isClient, connID, []string{EventSchema})
+	return newConnectionTrace(&ConnectionTracerOptions{}, []string{EventSchema}, isClient, connID)
 }
 
 func DefaultConnectionTracerWithSchemas(_ context.Context, isClient bool, connID ConnectionID, eventSchemas []string) qlogwriter.Trace {
 	if !slices.Contains(eventSchemas, EventSchema) {
 		eventSchemas = append([]string{EventSchema}, eventSchemas...)
 	}
-	return defaultConnectionTracerWithSchemas(isClient, connID, eventSchemas)
+	return newConnectionTrace(&ConnectionTracerOptions{}, eventSchemas, isClient, connID)
 }
 
-func defaultConnectionTracerWithSchemas(isClient bool, connID ConnectionID, eventSchemas []string) qlogwriter.Trace {
-	qlogDir := os.Getenv("QLOGDIR")
+// ConnectionTracerOptions configures the connection tracer created by NewConnectionTracer.
+type ConnectionTracerOptions struct {
+	// Dir is the directory that the qlog files are written to.
+	// If empty, the directory specified by the QLOGDIR environment variable is used.
+	// If that is not set either, no connections are traced.
+	Dir string
+	// EventSchemas are the event schemas that are supported, in addition to the QUIC event schema.
+	EventSchemas []string
+	// Filter selects the events that are written to the qlog files.
+	Filter qlogwriter.Filter
+	// SampleRate is the rate at which connections are traced: one in SampleRate connections is traced.
+	// If 0 or 1, all connections are traced.
+	SampleRate uint
+	// ErrorsOnly only keeps the qlog files of connections that were closed with an error.
+	// The qlog file is written while the connection is active, and removed when the connection is closed without an error.
+	// Idle timeouts, stateless resets, transport errors other than NO_ERROR and application errors other than 0 count as errors.
+	ErrorsOnly bool
+	// Compression compresses the qlog files.
+	// If nil, the qlog files are not compressed.
+	Compression qlogwriter.Compression
+}
+
+// NewConnectionTracer creates a connection tracer that can be used as the quic.Config's Tracer.
+// File names are <odcid>_<perspective>.sqlog, followed by the file extension of the compression, if any.
+func NewConnectionTracer(opts ConnectionTracerOptions) func(context.Context, bool, ConnectionID) qlogwriter.Trace {
+	eventSchemas := []string{EventSchema}
+	for _, schema := range opts.EventSchemas {
+		if !slices.Contains(eventSchemas, schema) {
+			eventSchemas = append(eventSchemas, schema)
+		}
+	}
+	var numConns atomic.Uint64
+	return func(_ context.Context, isClient bool, connID ConnectionID) qlogwriter.Trace {
+		if opts.SampleRate > 1 && (numConns.Add(1)-1)%uint64(opts.SampleRate) != 0 {
+			return nil
+		}
+		return newConnectionTrace(&opts, eventSchemas, isClient, connID)
+	}
+}
+
+func newConnectionTrace(opts *ConnectionTracerOptions, eventSchemas []string, isClient bool, connID ConnectionID) qlogwriter.Trace {
+	qlogDir := opts.Dir
+	if qlogDir == "" {
+		qlogDir = os.Getenv("QLOGDIR")
+	}
 	if qlogDir == "" {
 		return nil
 	}
@@ -45,17 +91,96 @@ func defaultConnectionTracerWithSchemas(isClient bool, connID ConnectionID, even
 		label = "client"
 	}
 	path := fmt.Sprintf("%s/%s_%s.sqlog", strings.TrimRight(qlogDir, "/"), connID, label)
+	if opts.Compression != nil {
+		path += opts.Compression.FileExtension()
+	}
 	f, err := os.Create(path)
 	if err != nil {
 		log.Printf("Failed to create qlog file %s: %s", path, err.Error())
 		return nil
 	}
-	fileSeq := qlogwriter.NewConnectionFileSeq(
-		utils.NewBufferedWriteCloser(bufio.NewWriter(f), f),
-		isClient,
-		connID,
-		eventSchemas,
-	)
+	w := utils.NewBufferedWriteCloser(bufio.NewWriter(f), f)
+	if opts.Compression != nil {
+		cw, err := qlogwriter.NewCompressedWriter(w, opts.Compression)
+		if err != nil {
+			log.Printf("Failed to compress qlog file %s: %s", path, err.Error())
+			f.Close()
+			os.Remove(path)
+			return nil
+		}
+		w = cw
+	}
+	var errorsOnly *errorsOnlyTrace
+	if opts.ErrorsOnly {
+		errorsOnly = &errorsOnlyTrace{}
+		w = &removingWriteCloser{WriteCloser: w, path: path, trace: errorsOnly}
+	}
+	fileSeq := qlogwriter.NewConnectionFileSeq(w, isClient, connID, eventSchemas)
 	go fileSeq.Run()
-	return fileSeq
+
+	var trace qlogwriter.Trace = fileSeq
+	if f := opts.Filter; len(f.Events) > 0 || len(f.ExcludeEvents) > 0 || len(f.Schemas) > 0 || f.Importance != 0 {
+		trace = qlogwriter.NewFilteredTrace(trace, f)
+	}
+	if errorsOnly != nil {
+		// The connection_closed event needs to be observed, even if it is filtered out.
+		errorsOnly.Trace = trace
+		trace = errorsOnly
+	}
+	return trace
+}
+
+// errorsOnlyTrace records whether the connection was closed with an error.
+type errorsOnlyTrace struct {
+	qlogwriter.Trace
+	closedWithError atomic.Bool
+}
+
+func (t *errorsOnlyTrace) AddProducer() qlogwriter.Recorder {
+	r := t.Trace.AddProducer()
+	if r == nil {
+		return nil
+	}
+	return &errorsOnlyRecorder{Recorder: r, trace: t}
+}
+
+type errorsOnlyRecorder struct {
+	qlogwriter.Recorder
+	trace *errorsOnlyTrace
+}
+
+func (r *errorsOnlyRecorder) RecordEvent(ev qlogwriter.Event) {
+	if e, ok := ev.(ConnectionClosed); ok && isErrorClose(&e) {
+		r.trace.closedWithError.Store(true)
+	}
+	r.Recorder.RecordEvent(ev)
+}
+
+func isErrorClose(e *ConnectionClosed) bool {
+	switch {
+	case e.ConnectionError != nil:
+		return *e.ConnectionError != qerr.NoError
+	case e.ApplicationError != nil:
+		return *e.ApplicationError != 0
+	default:
+		return e.Trigger != "" && e.Trigger != ConnectionCloseTriggerApplication
+	}
+}
+
+// removingWriteCloser removes the qlog file when it is closed,
+// unless the connection was closed with an error.
+type removingWriteCloser struct {
+	io.WriteCloser
+	path  string
+	trace *errorsOnlyTrace
+}
+
+func (w *removingWriteCloser) Close() error {
+	err := w.WriteCloser.Close()
+	if !w.trace.closedWithError.Load() {
+		if rerr := os.Remove(w.path); err == nil {
+			err = rerr
+		}
+	}
+	return err
 }
@@ -1,14 +1,19 @@
 package qlog
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/qlogwriter"
 	"github.com/stretchr/testify/require"
 )
@@ -68,3 +73,145 @@ func TestQLOGDIRNotSet(t *testing.T) {
 	tracer := DefaultConnectionTracer(context.Background(), true, connID)
 	require.Nil(t, tracer)
 }
+
+func readQlogFile(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	if strings.HasSuffix(path, ".gz") {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		data, err = io.ReadAll(r)
+		require.NoError(t, err)
+	}
+	var records []map[string]any
+	for _, record := range bytes.Split(data, []byte{qlogwriter.RecordSeparator})[1:] {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(record, &m))
+		records = append(records, m)
+	}
+	return records
+}
+
+func TestConnectionTracerDir(t *testing.T) {
+	t.Setenv("QLOGDIR", "")
+	qlogDir := filepath.Join(t.TempDir(), "qlogs")
+	tracer := NewConnectionTracer(ConnectionTracerOptions{
+		Dir:          qlogDir,
+		EventSchemas: []string{"urn:ietf:params:qlog:events:foobar", EventSchema},
+	})
+	connID := protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef})
+	trace := tracer(context.Background(), false, connID)
+	require.NotNil(t, trace)
+	require.True(t, trace.SupportsSchemas(EventSchema))
+	require.True(t, trace.SupportsSchemas("urn:ietf:params:qlog:events:foobar"))
+	testQLOGDIRSet(t, qlogDir, trace, []string{EventSchema, "urn:ietf:params:qlog:events:foobar"})
+	_, err := os.Stat(filepath.Join(qlogDir, "deadbeef_server.sqlog"))
+	require.NoError(t, err)
+
+	// QLOGDIR is used if no directory is set
+	require.Nil(t, NewConnectionTracer(ConnectionTracerOptions{})(context.Background(), false, connID))
+}
+
+func TestConnectionTracerSampling(t *testing.T) {
+	qlogDir := t.TempDir()
+	tracer := NewConnectionTracer(ConnectionTracerOptions{Dir: qlogDir, SampleRate: 3})
+
+	var traced []int
+	for i := range 7 {
+		connID := protocol.ParseConnectionID([]byte{0, 0, 0, byte(i)})
+		if trace := tracer(context.Background(), true, connID); trace != nil {
+			traced = append(traced, i)
+			trace.AddProducer().Close()
+		}
+	}
+	require.Equal(t, []int{0, 3, 6}, traced)
+	entries, err := os.ReadDir(qlogDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+}
+
+func TestConnectionTracerFilter(t *testing.T) {
+	qlogDir := t.TempDir()
+	tracer := NewConnectionTracer(ConnectionTracerOptions{
+		Dir:    qlogDir,
+		Filter: qlogwriter.Filter{Importance: qlogwriter.ImportanceCore, ExcludeEvents: []string{"recovery:*"}},
+	})
+	trace := tracer(context.Background(), true, protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef}))
+	require.NotNil(t, trace)
+	producer := trace.AddProducer()
+	producer.RecordEvent(PacketSent{Header: PacketHeader{PacketType: PacketType1RTT, PacketNumber: 1}})
+	producer.RecordEvent(MTUUpdated{Value: 1400})                  // extra
+	producer.RecordEvent(MetricsUpdated{MinRTT: time.Millisecond}) // core, but excluded
+	producer.RecordEvent(ALPNInformation{ChosenALPN: "h3"})
+	producer.Close()
+
+	records := readQlogFile(t, filepath.Join(qlogDir, "deadbeef_client.sqlog"))
+	require.Len(t, records, 3)
+	require.Equal(t, "transport:packet_sent", records[1]["name"])
+	require.Equal(t, "transport:alpn_information", records[2]["name"])
+}
+
+func TestConnectionTracerCompression(t *testing.T) {
+	qlogDir := t.TempDir()
+	tracer := NewConnectionTracer(ConnectionTracerOptions{Dir: qlogDir, Compression: qlogwriter.Gzip{}})
+	trace := tracer(context.Background(), true, protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef}))
+	require.NotNil(t, trace)
+	producer := trace.AddProducer()
+	producer.RecordEvent(ALPNInformation{ChosenALPN: "h3"})
+	producer.Close()
+
+	records := readQlogFile(t, filepath.Join(qlogDir, "deadbeef_client.sqlog.gz"))
+	require.Len(t, records, 2)
+	require.Equal(t, "transport:alpn_information", records[1]["name"])
+}
+
+func TestConnectionTracerErrorsOnly(t *testing.T) {
+	noError := qerr.NoError
+	internalError := qerr.InternalError
+	var appNoError ApplicationErrorCode
+	appError := ApplicationErrorCode(42)
+
+	for _, tc := range []struct {
+		name   string
+		closed *ConnectionClosed
+		kept   bool
+	}{
+		{name: "not closed", closed: nil, kept: false},
+		{name: "NO_ERROR", closed: &ConnectionClosed{ConnectionError: &noError}, kept: false},
+		{name: "application error 0", closed: &ConnectionClosed{ApplicationError: &appNoError}, kept: false},
+		{name: "transport error", closed: &ConnectionClosed{ConnectionError: &internalError}, kept: true},
+		{name: "application error", closed: &ConnectionClosed{ApplicationError: &appError}, kept: true},
+		{name: "idle timeout", closed: &ConnectionClosed{Trigger: ConnectionCloseTriggerIdleTimeout}, kept: true},
+		{name: "stateless reset", closed: &ConnectionClosed{Trigger: ConnectionCloseTriggerStatelessReset}, kept: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			qlogDir := t.TempDir()
+			tracer := NewConnectionTracer(ConnectionTracerOptions{
+				Dir:        qlogDir,
+				ErrorsOnly: true,
+				// the connection_closed event is taken into account, even if it is not written
+				Filter: qlogwriter.Filter{ExcludeEvents: []string{"transport:connection_closed"}},
+			})
+			trace := tracer(context.Background(), true, protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef}))
+			require.NotNil(t, trace)
+			producer := trace.AddProducer()
+			producer.RecordEvent(ALPNInformation{ChosenALPN: "h3"})
+			if tc.closed != nil {
+				producer.RecordEvent(*tc.closed)
+			}
+			producer.Close()
+
+			entries, err := os.ReadDir(qlogDir)
+			require.NoError(t, err)
+			if !tc.kept {
+				require.Empty(t, entries)
+				return
+			}
+			require.Len(t, entries, 1)
+			records := readQlogFile(t, filepath.Join(qlogDir, entries[0].Name()))
+			require.Len(t, records, 2)
+			require.Equal(t, "transport:alpn_information", records[1]["name"])
+		})
+	}
+}
@@ -231,6 +231,18 @@ const (
 	PacketDropDuplicate PacketDropReason = "duplicate"
 )
 
+// ConnectionRejectionReason is the reason why a new connection was rejected by the server
+type ConnectionRejectionReason string
+
+const (
+	// ConnectionRejectionMaxConnections is used when the maximum number of connections was reached
+	ConnectionRejectionMaxConnections ConnectionRejectionReason = "max_connections"
+	// ConnectionRejectionMaxHandshakes is used when the maximum number of handshakes was reached
+	ConnectionRejectionMaxHandshakes ConnectionRejectionReason = "max_handshakes"
+	// ConnectionRejectionRateLimit is used when the connection rate limit of the source address prefix was exceeded
+	ConnectionRejectionRateLimit ConnectionRejectionReason = "rate_limit"
+)
+
 type LossTimerUpdateType string
 
 const (
@@ -261,6 +273,20 @@ const (
 	CongestionStateRecovery CongestionState = "recovery"
 	// CongestionStateApplicationLimited means that the congestion controller is application limited
 	CongestionStateApplicationLimited CongestionState = "application_limited"
+	// CongestionStateStartup is the Startup phase of BBR
+	CongestionStateStartup CongestionState = "startup"
+	// CongestionStateDrain is the Drain phase of BBR
+	CongestionStateDrain CongestionState = "drain"
+	// CongestionStateProbeBWDown is the ProbeBW_DOWN phase of BBR
+	CongestionStateProbeBWDown CongestionState = "probe_bw_down"
+	// CongestionStateProbeBWCruise is the ProbeBW_CRUISE phase of BBR
+	CongestionStateProbeBWCruise CongestionState = "probe_bw_cruise"
+	// CongestionStateProbeBWRefill is the ProbeBW_REFILL phase of BBR
+	CongestionStateProbeBWRefill CongestionState = "probe_bw_refill"
+	// CongestionStateProbeBWUp is the ProbeBW_UP phase of BBR
+	CongestionStateProbeBWUp CongestionState = "probe_bw_up"
+	// CongestionStateProbeRTT is the ProbeRTT phase of BBR
+	CongestionStateProbeRTT CongestionState = "probe_rtt"
 )
 
 func (s CongestionState) String() string {
@@ -281,6 +307,20 @@ const (
 	ECNStateCapable ECNState = "capable"
 )
 
+// MigrationState is the state of a connection migration
+type MigrationState string
+
+const (
+	// MigrationStateProbingStarted is used when probing of a new path started
+	MigrationStateProbingStarted MigrationState = "probing_started"
+	// MigrationStateProbingAbandoned is used when probing of a new path was abandoned
+	MigrationStateProbingAbandoned MigrationState = "probing_abandoned"
+	// MigrationStateProbingSuccessful is used when a new path was successfully validated
+	MigrationStateProbingSuccessful MigrationState = "probing_successful"
+	// MigrationStateMigrationComplete is used when the connection switched to the new path
+	MigrationStateMigrationComplete MigrationState = "migration_complete"
+)
+
 type ConnectionCloseTrigger string
 
 const (
@@ -0,0 +1,286 @@
+package qlogreader
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/qlog"
+)
+
+// frameFields contains the fields of all QUIC frame types.
+// Fields that are encoded differently depending on the frame type are decoded in parseFrame.
+type frameFields struct {
+	FrameType           string          `json:"frame_type"`
+	StreamID            int64           `json:"stream_id"`
+	Offset              int64           `json:"offset"`
+	Length              int64           `json:"length"`
+	Fin                 bool            `json:"fin"`
+	FinalSize           int64           `json:"final_size"`
+	ReliableSize        int64           `json:"reliable_size"`
+	ErrorCode           json.RawMessage `json:"error_code"`
+	RawErrorCode        uint64          `json:"raw_error_code"`
+	ErrorSpace          string          `json:"error_space"`
+	Reason              string          `json:"reason"`
+	Maximum             int64           `json:"maximum"`
+	Limit               int64           `json:"limit"`
+	StreamType          string          `json:"stream_type"`
+	SequenceNumber      uint64          `json:"sequence_number"`
+	RetirePriorTo       uint64          `json:"retire_prior_to"`
+	ConnectionID        string          `json:"connection_id"`
+	StatelessResetToken string          `json:"stateless_reset_token"`
+	Data                string          `json:"data"`
+	Token               struct {
+		Data string `json:"data"`
+	} `json:"token"`
+	AckDelay              float64   `json:"ack_delay"`
+	AckedRanges           [][]int64 `json:"acked_ranges"`
+	ECT0                  uint64    `json:"ect0"`
+	ECT1                  uint64    `json:"ect1"`
+	CE                    uint64    `json:"ce"`
+	AckElicitingThreshold uint64    `json:"ack_eliciting_threshold"`
+	RequestMaxAckDelay    float64   `json:"request_max_ack_delay"`
+	ReorderingThreshold   int64     `json:"reordering_threshold"`
+	PathID                uint32    `json:"path_id"`
+	PathStatusSeqNum      uint64    `json:"path_status_sequence_number"`
+	MaximumPathID         uint32    `json:"maximum_path_id"`
+	NextSequenceNumber    uint64    `json:"next_sequence_number"`
+}
+
+func parseFrames(raw []json.RawMessage) ([]qlog.Frame, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	frames := make([]qlog.Frame, 0, len(raw))
+	for _, r := range raw {
+		f, err := parseFrame(r)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, qlog.Frame{Frame: f})
+	}
+	return frames, nil
+}
+
+func parseFrame(data []byte) (any, error) {
+	var f frameFields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	switch f.FrameType {
+	case "ping":
+		return &qlog.PingFrame{}, nil
+	case "ack":
+		ack, err := f.ackFrame()
+		if err != nil {
+			return nil, err
+		}
+		return &ack, nil
+	case "reset_stream", "reset_stream_at":
+		var errorCode uint64
+		if err := json.Unmarshal(f.ErrorCode, &errorCode); err != nil {
+			return nil, err
+		}
+		return &qlog.ResetStreamFrame{
+			StreamID:     protocol.StreamID(f.StreamID),
+			ErrorCode:    qerr.StreamErrorCode(errorCode),
+			FinalSize:    protocol.ByteCount(f.FinalSize),
+			ReliableSize: protocol.ByteCount(f.ReliableSize),
+		}, nil
+	case "stop_sending":
+		var errorCode uint64
+		if err := json.Unmarshal(f.ErrorCode, &errorCode); err != nil {
+			return nil, err
+		}
+		return &qlog.StopSendingFrame{
+			StreamID:  protocol.StreamID(f.StreamID),
+			ErrorCode: qerr.StreamErrorCode(errorCode),
+		}, nil
+	case "crypto":
+		return &qlog.CryptoFrame{Offset: f.Offset, Length: f.Length}, nil
+	case "new_token":
+		token, err := hex.DecodeString(f.Token.Data)
+		if err != nil {
+			return nil, err
+		}
+		return &qlog.NewTokenFrame{Token: token}, nil
+	case "stream":
+		return &qlog.StreamFrame{
+			StreamID: protocol.StreamID(f.StreamID),
+			Offset:   f.Offset,
+			Length:   f.Length,
+			Fin:      f.Fin,
+		}, nil
+	case "max_data":
+		return &qlog.MaxDataFrame{MaximumData: protocol.ByteCount(f.Maximum)}, nil
+	case "max_stream_data":
+		return &qlog.MaxStreamDataFrame{
+			StreamID:          protocol.StreamID(f.StreamID),
+			MaximumStreamData: protocol.ByteCount(f.Maximum),
+		}, nil
+	case "max_streams":
+		streamType, err := parseStreamType(f.StreamType)
+		if err != nil {
+			return nil, err
+		}
+		return &qlog.MaxStreamsFrame{Type: streamType, MaxStreamNum: protocol.StreamNum(f.Maximum)}, nil
+	case "data_blocked":
+		return &qlog.DataBlockedFrame{MaximumData: protocol.ByteCount(f.Limit)}, nil
+	case "stream_data_blocked":
+		return &qlog.StreamDataBlockedFrame{
+			StreamID:          protocol.StreamID(f.StreamID),
+			MaximumStreamData: protocol.ByteCount(f.Limit),
+		}, nil
+	case "streams_blocked":
+		streamType, err := parseStreamType(f.StreamType)
+		if err != nil {
+			return nil, err
+		}
+		return &qlog.StreamsBlockedFrame{Type: streamType, StreamLimit: protocol.StreamNum(f.Limit)}, nil
+	case "new_connection_id":
+		connID, err := parseConnectionID(f.ConnectionID)
+		if err != nil {
+			return nil, err
+		}
+		token, err := parseStatelessResetToken(f.StatelessResetToken)
+		if err != nil {
+			return nil, err
+		}
+		return &qlog.NewConnectionIDFrame{
+			SequenceNumber:      f.SequenceNumber,
+			RetirePriorTo:       f.RetirePriorTo,
+			ConnectionID:        connID,
+			StatelessResetToken: token,
+		}, nil
+	case "retire_connection_id":
+		return &qlog.RetireConnectionIDFrame{SequenceNumber: f.SequenceNumber}, nil
+	case "path_challenge":
+		data, err := parsePathData(f.Data)
+		if err != nil {
+			return nil, err
+		}
+		return &qlog.PathChallengeFrame{Data: data}, nil
+	case "path_response":
+		data, err := parsePathData(f.Data)
+		if err != nil {
+			return nil, err
+		}
+		return &qlog.PathResponseFrame{Data: data}, nil
+	case "connection_close":
+		return &qlog.ConnectionCloseFrame{
+			IsApplicationError: f.ErrorSpace == "application",
+			ErrorCode:          f.RawErrorCode,
+			ReasonPhrase:       f.Reason,
+		}, nil
+	case "handshake_done":
+		return &qlog.HandshakeDoneFrame{}, nil
+	case "datagram":
+		return &qlog.DatagramFrame{Length: f.Length}, nil
+	case "ack_frequency":
+		return &qlog.AckFrequencyFrame{
+			SequenceNumber:        f.SequenceNumber,
+			AckElicitingThreshold: f.AckElicitingThreshold,
+			RequestMaxAckDelay:    milliseconds(f.RequestMaxAckDelay),
+			ReorderingThreshold:   protocol.PacketNumber(f.ReorderingThreshold),
+		}, nil
+	case "immediate_ack":
+		return &qlog.ImmediateAckFrame{}, nil
+	case "path_ack":
+		ack, err := f.ackFrame()
+		if err != nil {
+			return nil, err
+		}
+		return &qlog.PathAckFrame{PathID: protocol.PathID(f.PathID), AckFrame: ack}, nil
+	case "path_abandon":
+		var errorCode uint64
+		if err := json.Unmarshal(f.ErrorCode, &errorCode); err != nil {
+			return nil, err
+		}
+		return &qlog.PathAbandonFrame{PathID: protocol.PathID(f.PathID), ErrorCode: errorCode}, nil
+	case "path_status_backup", "path_status_available":
+		return &qlog.PathStatusFrame{
+			PathID:         protocol.PathID(f.PathID),
+			SequenceNumber: f.PathStatusSeqNum,
+			Backup:         f.FrameType == "path_status_backup",
+		}, nil
+	case "path_new_connection_id":
+		connID, err := parseConnectionID(f.ConnectionID)
+		if err != nil {
+			return nil, err
+		}
+		token, err := parseStatelessResetToken(f.StatelessResetToken)
+		if err != nil {
+			return nil, err
+		}
+		return &qlog.PathNewConnectionIDFrame{
+			PathID:              protocol.PathID(f.PathID),
+			SequenceNumber:      f.SequenceNumber,
+			RetirePriorTo:       f.RetirePriorTo,
+			ConnectionID:        connID,
+			StatelessResetToken: token,
+		}, nil
+	case "path_retire_connection_id":
+		return &qlog.PathRetireConnectionIDFrame{
+			PathID:         protocol.PathID(f.PathID),
+			SequenceNumber: f.SequenceNumber,
+		}, nil
+	case "max_path_id":
+		return &qlog.MaxPathIDFrame{MaximumPathID: protocol.PathID(f.MaximumPathID)}, nil
+	case "paths_blocked":
+		return &qlog.PathsBlockedFrame{MaximumPathID: protocol.PathID(f.MaximumPathID)}, nil
+	case "path_cids_blocked":
+		return &qlog.PathConnectionIDsBlockedFrame{
+			PathID:             protocol.PathID(f.PathID),
+			NextSequenceNumber: f.NextSequenceNumber,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown frame type: %s", f.FrameType)
+	}
+}
+
+func (f *frameFields) ackFrame() (qlog.AckFrame, error) {
+	ack := qlog.AckFrame{
+		DelayTime: milliseconds(f.AckDelay),
+		ECT0:      f.ECT0,
+		ECT1:      f.ECT1,
+		ECNCE:     f.CE,
+	}
+	ack.AckRanges = make([]qlog.AckRange, 0, len(f.AckedRanges))
+	for _, r := range f.AckedRanges {
+		switch len(r) {
+		case 1:
+			ack.AckRanges = append(ack.AckRanges, qlog.AckRange{Smallest: protocol.PacketNumber(r[0]), Largest: protocol.PacketNumber(r[0])})
+		case 2:
+			ack.AckRanges = append(ack.AckRanges, qlog.AckRange{Smallest: protocol.PacketNumber(r[0]), Largest: protocol.PacketNumber(r[1])})
+		default:
+			return qlog.AckFrame{}, fmt.Errorf("invalid ACK range: %v", r)
+		}
+	}
+	return ack, nil
+}
+
+func parseStreamType(s string) (protocol.StreamType, error) {
+	switch s {
+	case "unidirectional":
+		return protocol.StreamTypeUni, nil
+	case "bidirectional":
+		return protocol.StreamTypeBidi, nil
+	default:
+		return 0, fmt.Errorf("unknown stream type: %s", s)
+	}
+}
+
+func parsePathData(s string) ([8]byte, error) {
+	var data [8]byte
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return data, err
+	}
+	if len(b) != len(data) {
+		return data, fmt.Errorf("invalid path challenge data length: %d", len(b))
+	}
+	copy(data[:], b)
+	return data, nil
+}
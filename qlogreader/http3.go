@@ -0,0 +1,150 @@
+package qlogreader
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	h3qlog "github.com/quic-go/quic-go/http3/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+)
+
+var http3EventDecoders = map[string]eventDecoder{
+	"http3:frame_created":    decodeHTTP3FrameCreated,
+	"http3:frame_parsed":     decodeHTTP3FrameParsed,
+	"http3:datagram_created": decodeHTTP3DatagramCreated,
+	"http3:datagram_parsed":  decodeHTTP3DatagramParsed,
+}
+
+type http3FrameEvent struct {
+	StreamID uint64          `json:"stream_id"`
+	Raw      rawInfo         `json:"raw"`
+	Frame    json.RawMessage `json:"frame"`
+}
+
+func (e *http3FrameEvent) parse(data []byte) (h3qlog.RawInfo, h3qlog.Frame, error) {
+	if err := json.Unmarshal(data, e); err != nil {
+		return h3qlog.RawInfo{}, h3qlog.Frame{}, err
+	}
+	frame, err := parseHTTP3Frame(e.Frame)
+	if err != nil {
+		return h3qlog.RawInfo{}, h3qlog.Frame{}, err
+	}
+	return h3qlog.RawInfo{Length: e.Raw.Length, PayloadLength: e.Raw.PayloadLength}, frame, nil
+}
+
+func decodeHTTP3FrameCreated(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var e http3FrameEvent
+	raw, frame, err := e.parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return h3qlog.FrameCreated{StreamID: quic.StreamID(e.StreamID), Raw: raw, Frame: frame}, nil
+}
+
+func decodeHTTP3FrameParsed(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var e http3FrameEvent
+	raw, frame, err := e.parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return h3qlog.FrameParsed{StreamID: quic.StreamID(e.StreamID), Raw: raw, Frame: frame}, nil
+}
+
+type http3DatagramEvent struct {
+	QuarterStreamID uint64  `json:"quater_stream_id"`
+	Raw             rawInfo `json:"raw"`
+}
+
+func decodeHTTP3DatagramCreated(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var e http3DatagramEvent
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return h3qlog.DatagramCreated{
+		QuaterStreamID: e.QuarterStreamID,
+		Raw:            h3qlog.RawInfo{Length: e.Raw.Length, PayloadLength: e.Raw.PayloadLength},
+	}, nil
+}
+
+func decodeHTTP3DatagramParsed(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var e http3DatagramEvent
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return h3qlog.DatagramParsed{
+		QuaterStreamID: e.QuarterStreamID,
+		Raw:            h3qlog.RawInfo{Length: e.Raw.Length, PayloadLength: e.Raw.PayloadLength},
+	}, nil
+}
+
+func parseHTTP3Frame(data []byte) (h3qlog.Frame, error) {
+	var f struct {
+		FrameType    string `json:"frame_type"`
+		ID           uint64 `json:"id"`
+		FrameTypeRaw uint64 `json:"frame_type_bytes"`
+		HeaderFields []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"header_fields"`
+		Settings []struct {
+			Name      string          `json:"name"`
+			NameBytes uint64          `json:"name_bytes"`
+			Value     json.RawMessage `json:"value"`
+		} `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return h3qlog.Frame{}, err
+	}
+	switch f.FrameType {
+	case "data":
+		return h3qlog.Frame{Frame: h3qlog.DataFrame{}}, nil
+	case "headers":
+		var frame h3qlog.HeadersFrame
+		for _, hf := range f.HeaderFields {
+			frame.HeaderFields = append(frame.HeaderFields, h3qlog.HeaderField{Name: hf.Name, Value: hf.Value})
+		}
+		return h3qlog.Frame{Frame: frame}, nil
+	case "goaway":
+		return h3qlog.Frame{Frame: h3qlog.GoAwayFrame{StreamID: quic.StreamID(f.ID)}}, nil
+	case "settings":
+		var frame h3qlog.SettingsFrame
+		for _, s := range f.Settings {
+			switch s.Name {
+			case "settings_h3_datagram", "settings_enable_connect_protocol":
+				var v bool
+				if err := json.Unmarshal(s.Value, &v); err != nil {
+					return h3qlog.Frame{}, err
+				}
+				if s.Name == "settings_h3_datagram" {
+					frame.Datagram = &v
+				} else {
+					frame.ExtendedConnect = &v
+				}
+			default:
+				var v uint64
+				if err := json.Unmarshal(s.Value, &v); err != nil {
+					return h3qlog.Frame{}, err
+				}
+				if frame.Other == nil {
+					frame.Other = make(map[uint64]uint64)
+				}
+				frame.Other[s.NameBytes] = v
+			}
+		}
+		return h3qlog.Frame{Frame: frame}, nil
+	case "push_promise":
+		return h3qlog.Frame{Frame: h3qlog.PushPromiseFrame{}}, nil
+	case "cancel_push":
+		return h3qlog.Frame{Frame: h3qlog.CancelPushFrame{}}, nil
+	case "max_push_id":
+		return h3qlog.Frame{Frame: h3qlog.MaxPushIDFrame{}}, nil
+	case "reserved":
+		return h3qlog.Frame{Frame: h3qlog.ReservedFrame{Type: f.FrameTypeRaw}}, nil
+	case "unknown":
+		return h3qlog.Frame{Frame: h3qlog.UnknownFrame{Type: f.FrameTypeRaw}}, nil
+	default:
+		return h3qlog.Frame{}, fmt.Errorf("unknown HTTP/3 frame type: %s", f.FrameType)
+	}
+}
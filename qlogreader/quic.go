@@ -0,0 +1,759 @@
+package qlogreader
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+)
+
+var quicEventDecoders = map[string]eventDecoder{
+	"transport:connection_started":      decodeStartedConnection,
+	"transport:version_information":     decodeVersionInformation,
+	"transport:connection_closed":       decodeConnectionClosed,
+	"transport:packet_sent":             decodePacketSent,
+	"transport:packet_received":         decodePacketReceived,
+	"transport:packet_buffered":         decodePacketBuffered,
+	"transport:packet_dropped":          decodePacketDropped,
+	"transport:parameters_set":          decodeParametersSet,
+	"transport:parameters_restored":     decodeParametersRestored,
+	"transport:migration_state_updated": decodeMigrationStateUpdated,
+	"transport:alpn_information":        decodeALPNInformation,
+	"transport:connection_rejected":     decodeConnectionRejected,
+	"transport:retry_state_updated":     decodeRetryStateUpdated,
+	"recovery:mtu_updated":              decodeMTUUpdated,
+	"recovery:metrics_updated":          decodeMetricsUpdated,
+	"recovery:packet_lost":              decodePacketLost,
+	"recovery:spurious_loss":            decodeSpuriousLoss,
+	"recovery:loss_timer_updated":       decodeLossTimerUpdated,
+	"recovery:congestion_state_updated": decodeCongestionStateUpdated,
+	"recovery:ecn_state_updated":        decodeECNStateUpdated,
+	"security:key_updated":              decodeKeyUpdated,
+	"security:key_discarded":            decodeKeyDiscarded,
+}
+
+var transportErrorCodes = make(map[string]qlog.TransportErrorCode)
+
+func init() {
+	for _, code := range []qerr.TransportErrorCode{
+		qerr.NoError,
+		qerr.InternalError,
+		qerr.ConnectionRefused,
+		qerr.FlowControlError,
+		qerr.StreamLimitError,
+		qerr.StreamStateError,
+		qerr.FinalSizeError,
+		qerr.FrameEncodingError,
+		qerr.TransportParameterError,
+		qerr.ConnectionIDLimitError,
+		qerr.ProtocolViolation,
+		qerr.InvalidToken,
+		qerr.ApplicationErrorErrorCode,
+		qerr.CryptoBufferExceeded,
+		qerr.KeyUpdateError,
+		qerr.AEADLimitReached,
+		qerr.NoViablePathError,
+	} {
+		transportErrorCodes[strings.ToLower(code.String())] = code
+	}
+}
+
+func parseConnectionIDBytes(s string) ([]byte, error) {
+	if s == "" || s == "(empty)" {
+		return nil, nil
+	}
+	return hex.DecodeString(s)
+}
+
+func parseConnectionID(s string) (qlog.ConnectionID, error) {
+	b, err := parseConnectionIDBytes(s)
+	if err != nil {
+		return qlog.ConnectionID{}, err
+	}
+	if len(b) > 20 {
+		return qlog.ConnectionID{}, fmt.Errorf("connection ID too long: %d bytes", len(b))
+	}
+	return protocol.ParseConnectionID(b), nil
+}
+
+func parseStatelessResetToken(s string) (protocol.StatelessResetToken, error) {
+	var token protocol.StatelessResetToken
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return token, err
+	}
+	if len(b) != len(token) {
+		return token, fmt.Errorf("invalid stateless reset token length: %d", len(b))
+	}
+	copy(token[:], b)
+	return token, nil
+}
+
+func parseVersion(s string) (qlog.Version, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, err
+	}
+	return qlog.Version(v), nil
+}
+
+func parseVersions(vs []string) ([]qlog.Version, error) {
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	versions := make([]qlog.Version, 0, len(vs))
+	for _, s := range vs {
+		v, err := parseVersion(s)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+func parsePacketNumberSpace(s string) protocol.EncryptionLevel {
+	switch s {
+	case "initial":
+		return protocol.EncryptionInitial
+	case "handshake":
+		return protocol.EncryptionHandshake
+	case "application_data":
+		return protocol.Encryption1RTT
+	default:
+		return 0
+	}
+}
+
+func parseAddrPort(ip string, port uint16) (netip.AddrPort, error) {
+	if ip == "" {
+		return netip.AddrPort{}, nil
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	return netip.AddrPortFrom(addr, port), nil
+}
+
+type rawInfo struct {
+	Length        int `json:"length"`
+	PayloadLength int `json:"payload_length"`
+}
+
+func (i rawInfo) toQlog() qlog.RawInfo {
+	return qlog.RawInfo{Length: i.Length, PayloadLength: i.PayloadLength}
+}
+
+type pathEndpointInfo struct {
+	IPv4   string `json:"ip_v4"`
+	PortV4 uint16 `json:"port_v4"`
+	IPv6   string `json:"ip_v6"`
+	PortV6 uint16 `json:"port_v6"`
+}
+
+func (p pathEndpointInfo) toQlog() (qlog.PathEndpointInfo, error) {
+	ipv4, err := parseAddrPort(p.IPv4, p.PortV4)
+	if err != nil {
+		return qlog.PathEndpointInfo{}, err
+	}
+	ipv6, err := parseAddrPort(p.IPv6, p.PortV6)
+	if err != nil {
+		return qlog.PathEndpointInfo{}, err
+	}
+	return qlog.PathEndpointInfo{IPv4: ipv4, IPv6: ipv6}, nil
+}
+
+type packetHeader struct {
+	PacketType   string `json:"packet_type"`
+	PacketNumber *int64 `json:"packet_number"`
+	Version      string `json:"version"`
+	SrcConnID    string `json:"scid"`
+	DestConnID   string `json:"dcid"`
+	KeyPhaseBit  string `json:"key_phase_bit"`
+	Token        *struct {
+		Data string `json:"data"`
+	} `json:"token"`
+}
+
+func (h packetHeader) isVersionNegotiation() bool {
+	return qlog.PacketType(h.PacketType) == qlog.PacketTypeVersionNegotiation
+}
+
+func (h packetHeader) toQlog() (qlog.PacketHeader, error) {
+	hdr := qlog.PacketHeader{
+		PacketType:   qlog.PacketType(h.PacketType),
+		PacketNumber: protocol.InvalidPacketNumber,
+	}
+	if h.PacketNumber != nil {
+		hdr.PacketNumber = qlog.PacketNumber(*h.PacketNumber)
+	}
+	if h.Version != "" {
+		v, err := parseVersion(h.Version)
+		if err != nil {
+			return qlog.PacketHeader{}, err
+		}
+		hdr.Version = v
+	}
+	var err error
+	if hdr.SrcConnectionID, err = parseConnectionID(h.SrcConnID); err != nil {
+		return qlog.PacketHeader{}, err
+	}
+	if hdr.DestConnectionID, err = parseConnectionID(h.DestConnID); err != nil {
+		return qlog.PacketHeader{}, err
+	}
+	switch h.KeyPhaseBit {
+	case "0":
+		hdr.KeyPhaseBit = qlog.KeyPhaseZero
+	case "1":
+		hdr.KeyPhaseBit = qlog.KeyPhaseOne
+	}
+	if h.Token != nil {
+		b, err := hex.DecodeString(h.Token.Data)
+		if err != nil {
+			return qlog.PacketHeader{}, err
+		}
+		hdr.Token = &qlog.Token{Raw: b}
+	}
+	return hdr, nil
+}
+
+func (h packetHeader) toQlogVersionNegotiation() (qlog.PacketHeaderVersionNegotiation, error) {
+	src, err := parseConnectionIDBytes(h.SrcConnID)
+	if err != nil {
+		return qlog.PacketHeaderVersionNegotiation{}, err
+	}
+	dest, err := parseConnectionIDBytes(h.DestConnID)
+	if err != nil {
+		return qlog.PacketHeaderVersionNegotiation{}, err
+	}
+	return qlog.PacketHeaderVersionNegotiation{
+		SrcConnectionID:  qlog.ArbitraryLenConnectionID(src),
+		DestConnectionID: qlog.ArbitraryLenConnectionID(dest),
+	}, nil
+}
+
+func decodeStartedConnection(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		Local  pathEndpointInfo `json:"local"`
+		Remote pathEndpointInfo `json:"remote"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	local, err := d.Local.toQlog()
+	if err != nil {
+		return nil, err
+	}
+	remote, err := d.Remote.toQlog()
+	if err != nil {
+		return nil, err
+	}
+	return qlog.StartedConnection{Local: local, Remote: remote}, nil
+}
+
+func decodeVersionInformation(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		ClientVersions []string `json:"client_versions"`
+		ServerVersions []string `json:"server_versions"`
+		ChosenVersion  string   `json:"chosen_version"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	var ev qlog.VersionInformation
+	var err error
+	if ev.ClientVersions, err = parseVersions(d.ClientVersions); err != nil {
+		return nil, err
+	}
+	if ev.ServerVersions, err = parseVersions(d.ServerVersions); err != nil {
+		return nil, err
+	}
+	if ev.ChosenVersion, err = parseVersion(d.ChosenVersion); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+func decodeConnectionClosed(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		Initiator        string  `json:"initiator"`
+		ConnectionError  *string `json:"connection_error"`
+		ApplicationError *string `json:"application_error"`
+		ErrorCode        *uint64 `json:"error_code"`
+		Reason           string  `json:"reason"`
+		Trigger          string  `json:"trigger"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	ev := qlog.ConnectionClosed{
+		Initiator: qlog.Initiator(d.Initiator),
+		Reason:    d.Reason,
+		Trigger:   qlog.ConnectionCloseTrigger(d.Trigger),
+	}
+	if d.ConnectionError != nil {
+		var code qlog.TransportErrorCode
+		switch s := *d.ConnectionError; {
+		case s == "unknown":
+			if d.ErrorCode == nil {
+				return nil, errors.New("missing error code")
+			}
+			code = qlog.TransportErrorCode(*d.ErrorCode)
+		case strings.HasPrefix(s, "crypto_error_"):
+			c, err := strconv.ParseUint(strings.TrimPrefix(s, "crypto_error_"), 0, 16)
+			if err != nil {
+				return nil, err
+			}
+			code = qlog.TransportErrorCode(c)
+		default:
+			var ok bool
+			code, ok = transportErrorCodes[s]
+			if !ok {
+				return nil, fmt.Errorf("unknown transport error: %s", s)
+			}
+		}
+		ev.ConnectionError = &code
+	}
+	if d.ApplicationError != nil {
+		if d.ErrorCode == nil {
+			return nil, errors.New("missing error code")
+		}
+		code := qlog.ApplicationErrorCode(*d.ErrorCode)
+		ev.ApplicationError = &code
+	}
+	return ev, nil
+}
+
+type packetEvent struct {
+	Header            packetHeader      `json:"header"`
+	Raw               rawInfo           `json:"raw"`
+	Frames            []json.RawMessage `json:"frames"`
+	IsCoalesced       bool              `json:"is_coalesced"`
+	ECN               string            `json:"ecn"`
+	Trigger           string            `json:"trigger"`
+	SupportedVersions []string          `json:"supported_versions"`
+}
+
+func decodePacketSent(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d packetEvent
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	if d.Header.isVersionNegotiation() {
+		hdr, err := d.Header.toQlogVersionNegotiation()
+		if err != nil {
+			return nil, err
+		}
+		versions, err := parseVersions(d.SupportedVersions)
+		if err != nil {
+			return nil, err
+		}
+		return qlog.VersionNegotiationSent{Header: hdr, SupportedVersions: versions}, nil
+	}
+	hdr, err := d.Header.toQlog()
+	if err != nil {
+		return nil, err
+	}
+	frames, err := parseFrames(d.Frames)
+	if err != nil {
+		return nil, err
+	}
+	return qlog.PacketSent{
+		Header:      hdr,
+		Raw:         d.Raw.toQlog(),
+		Frames:      frames,
+		ECN:         qlog.ECN(d.ECN),
+		IsCoalesced: d.IsCoalesced,
+		Trigger:     d.Trigger,
+	}, nil
+}
+
+func decodePacketReceived(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d packetEvent
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	if d.Header.isVersionNegotiation() {
+		hdr, err := d.Header.toQlogVersionNegotiation()
+		if err != nil {
+			return nil, err
+		}
+		versions, err := parseVersions(d.SupportedVersions)
+		if err != nil {
+			return nil, err
+		}
+		return qlog.VersionNegotiationReceived{Header: hdr, SupportedVersions: versions}, nil
+	}
+	hdr, err := d.Header.toQlog()
+	if err != nil {
+		return nil, err
+	}
+	frames, err := parseFrames(d.Frames)
+	if err != nil {
+		return nil, err
+	}
+	return qlog.PacketReceived{
+		Header:      hdr,
+		Raw:         d.Raw.toQlog(),
+		Frames:      frames,
+		ECN:         qlog.ECN(d.ECN),
+		IsCoalesced: d.IsCoalesced,
+		Trigger:     d.Trigger,
+	}, nil
+}
+
+func decodePacketBuffered(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d packetEvent
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	hdr, err := d.Header.toQlog()
+	if err != nil {
+		return nil, err
+	}
+	return qlog.PacketBuffered{Header: hdr, Raw: d.Raw.toQlog()}, nil
+}
+
+func decodePacketDropped(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d packetEvent
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	hdr, err := d.Header.toQlog()
+	if err != nil {
+		return nil, err
+	}
+	return qlog.PacketDropped{
+		Header:  hdr,
+		Raw:     d.Raw.toQlog(),
+		Trigger: qlog.PacketDropReason(d.Trigger),
+	}, nil
+}
+
+func decodeParametersSet(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	return decodeParameters(data, false)
+}
+
+func decodeParametersRestored(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	return decodeParameters(data, true)
+}
+
+func decodeParameters(data []byte, restore bool) (qlogwriter.Event, error) {
+	var d struct {
+		Initiator                       string  `json:"initiator"`
+		OriginalDestinationConnectionID *string `json:"original_destination_connection_id"`
+		StatelessResetToken             *string `json:"stateless_reset_token"`
+		RetrySourceConnectionID         *string `json:"retry_source_connection_id"`
+		InitialSourceConnectionID       *string `json:"initial_source_connection_id"`
+		DisableActiveMigration          bool    `json:"disable_active_migration"`
+		MaxIdleTimeout                  float64 `json:"max_idle_timeout"`
+		MaxUDPPayloadSize               int64   `json:"max_udp_payload_size"`
+		AckDelayExponent                uint8   `json:"ack_delay_exponent"`
+		MaxAckDelay                     float64 `json:"max_ack_delay"`
+		ActiveConnectionIDLimit         uint64  `json:"active_connection_id_limit"`
+		InitialMaxData                  int64   `json:"initial_max_data"`
+		InitialMaxStreamDataBidiLocal   int64   `json:"initial_max_stream_data_bidi_local"`
+		InitialMaxStreamDataBidiRemote  int64   `json:"initial_max_stream_data_bidi_remote"`
+		InitialMaxStreamDataUni         int64   `json:"initial_max_stream_data_uni"`
+		InitialMaxStreamsBidi           int64   `json:"initial_max_streams_bidi"`
+		InitialMaxStreamsUni            int64   `json:"initial_max_streams_uni"`
+		MaxDatagramFrameSize            *int64  `json:"max_datagram_frame_size"`
+		EnableResetStreamAt             bool    `json:"reset_stream_at"`
+		InitialMaxPathID                *uint32 `json:"initial_max_path_id"`
+		PreferredAddress                *struct {
+			pathEndpointInfo
+			ConnectionID        string `json:"connection_id"`
+			StatelessResetToken string `json:"stateless_reset_token"`
+		} `json:"preferred_address"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	ev := qlog.ParametersSet{
+		Restore:                        restore,
+		Initiator:                      qlog.Initiator(d.Initiator),
+		SentBy:                         protocol.PerspectiveClient,
+		DisableActiveMigration:         d.DisableActiveMigration,
+		MaxIdleTimeout:                 milliseconds(d.MaxIdleTimeout),
+		MaxUDPPayloadSize:              protocol.ByteCount(d.MaxUDPPayloadSize),
+		AckDelayExponent:               d.AckDelayExponent,
+		MaxAckDelay:                    milliseconds(d.MaxAckDelay),
+		ActiveConnectionIDLimit:        d.ActiveConnectionIDLimit,
+		InitialMaxData:                 protocol.ByteCount(d.InitialMaxData),
+		InitialMaxStreamDataBidiLocal:  protocol.ByteCount(d.InitialMaxStreamDataBidiLocal),
+		InitialMaxStreamDataBidiRemote: protocol.ByteCount(d.InitialMaxStreamDataBidiRemote),
+		InitialMaxStreamDataUni:        protocol.ByteCount(d.InitialMaxStreamDataUni),
+		InitialMaxStreamsBidi:          d.InitialMaxStreamsBidi,
+		InitialMaxStreamsUni:           d.InitialMaxStreamsUni,
+		MaxDatagramFrameSize:           protocol.InvalidByteCount,
+		EnableResetStreamAt:            d.EnableResetStreamAt,
+	}
+	var err error
+	if d.InitialSourceConnectionID != nil {
+		if ev.InitialSourceConnectionID, err = parseConnectionID(*d.InitialSourceConnectionID); err != nil {
+			return nil, err
+		}
+	}
+	// Only the server sends the original_destination_connection_id.
+	if d.OriginalDestinationConnectionID != nil {
+		ev.SentBy = protocol.PerspectiveServer
+		if ev.OriginalDestinationConnectionID, err = parseConnectionID(*d.OriginalDestinationConnectionID); err != nil {
+			return nil, err
+		}
+	}
+	if d.StatelessResetToken != nil {
+		token, err := parseStatelessResetToken(*d.StatelessResetToken)
+		if err != nil {
+			return nil, err
+		}
+		ev.StatelessResetToken = &token
+	}
+	if d.RetrySourceConnectionID != nil {
+		connID, err := parseConnectionID(*d.RetrySourceConnectionID)
+		if err != nil {
+			return nil, err
+		}
+		ev.RetrySourceConnectionID = &connID
+	}
+	if d.MaxDatagramFrameSize != nil {
+		ev.MaxDatagramFrameSize = protocol.ByteCount(*d.MaxDatagramFrameSize)
+	}
+	if d.InitialMaxPathID != nil {
+		pathID := protocol.PathID(*d.InitialMaxPathID)
+		ev.InitialMaxPathID = &pathID
+	}
+	if pa := d.PreferredAddress; pa != nil {
+		addrs, err := pa.toQlog()
+		if err != nil {
+			return nil, err
+		}
+		connID, err := parseConnectionID(pa.ConnectionID)
+		if err != nil {
+			return nil, err
+		}
+		token, err := parseStatelessResetToken(pa.StatelessResetToken)
+		if err != nil {
+			return nil, err
+		}
+		ev.PreferredAddress = &qlog.PreferredAddress{
+			IPv4:                addrs.IPv4,
+			IPv6:                addrs.IPv6,
+			ConnectionID:        connID,
+			StatelessResetToken: token,
+		}
+	}
+	return ev, nil
+}
+
+func decodeMigrationStateUpdated(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		New        string           `json:"new"`
+		PathRemote pathEndpointInfo `json:"path_remote"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	remote, err := d.PathRemote.toQlog()
+	if err != nil {
+		return nil, err
+	}
+	return qlog.MigrationStateUpdated{State: qlog.MigrationState(d.New), Remote: remote}, nil
+}
+
+func decodeALPNInformation(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		ChosenALPN string `json:"chosen_alpn"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return qlog.ALPNInformation{ChosenALPN: d.ChosenALPN}, nil
+}
+
+func decodeConnectionRejected(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		Reason     string           `json:"reason"`
+		Retry      bool             `json:"retry"`
+		PathRemote pathEndpointInfo `json:"path_remote"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	remote, err := d.PathRemote.toQlog()
+	if err != nil {
+		return nil, err
+	}
+	return qlog.ConnectionRejected{
+		Remote: remote,
+		Reason: qlog.ConnectionRejectionReason(d.Reason),
+		Retry:  d.Retry,
+	}, nil
+}
+
+func decodeRetryStateUpdated(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		Enabled               bool `json:"enabled"`
+		UnvalidatedHandshakes int  `json:"unvalidated_handshakes"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return qlog.RetryStateUpdated{Enabled: d.Enabled, UnvalidatedHandshakes: d.UnvalidatedHandshakes}, nil
+}
+
+func decodeMTUUpdated(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		MTU  int  `json:"mtu"`
+		Done bool `json:"done"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return qlog.MTUUpdated{Value: d.MTU, Done: d.Done}, nil
+}
+
+func decodeMetricsUpdated(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		MinRTT            float64 `json:"min_rtt"`
+		SmoothedRTT       float64 `json:"smoothed_rtt"`
+		LatestRTT         float64 `json:"latest_rtt"`
+		RTTVariance       float64 `json:"rtt_variance"`
+		CongestionWindow  int     `json:"congestion_window"`
+		BytesInFlight     int     `json:"bytes_in_flight"`
+		PacketsInFlight   int     `json:"packets_in_flight"`
+		PacingRate        uint64  `json:"pacing_rate"`
+		BandwidthEstimate uint64  `json:"bandwidth_estimate"`
+		PTOCount          *uint32 `json:"pto_count"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	ev := qlog.MetricsUpdated{
+		MinRTT:            milliseconds(d.MinRTT),
+		SmoothedRTT:       milliseconds(d.SmoothedRTT),
+		LatestRTT:         milliseconds(d.LatestRTT),
+		RTTVariance:       milliseconds(d.RTTVariance),
+		CongestionWindow:  d.CongestionWindow,
+		BytesInFlight:     d.BytesInFlight,
+		PacketsInFlight:   d.PacketsInFlight,
+		PacingRate:        d.PacingRate,
+		BandwidthEstimate: d.BandwidthEstimate,
+	}
+	// quic-go logs the PTO count in a separate recovery:metrics_updated event
+	if d.PTOCount != nil && ev == (qlog.MetricsUpdated{}) {
+		return qlog.PTOCountUpdated{PTOCount: *d.PTOCount}, nil
+	}
+	return ev, nil
+}
+
+func decodePacketLost(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		Header  packetHeader `json:"header"`
+		Trigger string       `json:"trigger"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	hdr, err := d.Header.toQlog()
+	if err != nil {
+		return nil, err
+	}
+	return qlog.PacketLost{Header: hdr, Trigger: qlog.PacketLossReason(d.Trigger)}, nil
+}
+
+func decodeSpuriousLoss(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		PacketNumberSpace string  `json:"packet_number_space"`
+		PacketNumber      int64   `json:"packet_number"`
+		ReorderingPackets uint64  `json:"reordering_packets"`
+		ReorderingTime    float64 `json:"reordering_time"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return qlog.SpuriousLoss{
+		EncryptionLevel:  parsePacketNumberSpace(d.PacketNumberSpace),
+		PacketNumber:     protocol.PacketNumber(d.PacketNumber),
+		PacketReordering: d.ReorderingPackets,
+		TimeReordering:   milliseconds(d.ReorderingTime),
+	}, nil
+}
+
+func decodeLossTimerUpdated(data []byte, eventTime time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		EventType         string   `json:"event_type"`
+		TimerType         string   `json:"timer_type"`
+		PacketNumberSpace string   `json:"packet_number_space"`
+		Delta             *float64 `json:"delta"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	ev := qlog.LossTimerUpdated{
+		Type:      qlog.LossTimerUpdateType(d.EventType),
+		TimerType: qlog.TimerType(d.TimerType),
+		EncLevel:  parsePacketNumberSpace(d.PacketNumberSpace),
+	}
+	if d.Delta != nil {
+		ev.Time = eventTime.Add(milliseconds(*d.Delta))
+	}
+	return ev, nil
+}
+
+func decodeCongestionStateUpdated(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		New string `json:"new"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return qlog.CongestionStateUpdated{State: qlog.CongestionState(d.New)}, nil
+}
+
+func decodeECNStateUpdated(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		New     string `json:"new"`
+		Trigger string `json:"trigger"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return qlog.ECNStateUpdated{State: qlog.ECNState(d.New), Trigger: d.Trigger}, nil
+}
+
+func decodeKeyUpdated(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		Trigger  string `json:"trigger"`
+		KeyType  string `json:"key_type"`
+		KeyPhase uint64 `json:"key_phase"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return qlog.KeyUpdated{
+		Trigger:  qlog.KeyUpdateTrigger(d.Trigger),
+		KeyType:  qlog.KeyType(d.KeyType),
+		KeyPhase: qlog.KeyPhase(d.KeyPhase),
+	}, nil
+}
+
+func decodeKeyDiscarded(data []byte, _ time.Time) (qlogwriter.Event, error) {
+	var d struct {
+		KeyType  string `json:"key_type"`
+		KeyPhase uint64 `json:"key_phase"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return qlog.KeyDiscarded{KeyType: qlog.KeyType(d.KeyType), KeyPhase: qlog.KeyPhase(d.KeyPhase)}, nil
+}
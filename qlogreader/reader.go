@@ -0,0 +1,266 @@
+// Package qlogreader parses qlog traces in the JSON-SEQ format,
+// as written by qlogwriter.FileSeq, back into the typed events
+// defined in the qlog and the http3/qlog packages.
+package qlogreader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/quic-go/quic-go/qlogwriter"
+	"github.com/quic-go/quic-go/qlogwriter/jsontext"
+)
+
+// Header is the header of a qlog trace.
+// It is the first record of a JSON-SEQ qlog file.
+type Header struct {
+	FileSchema          string
+	SerializationFormat string
+	Title               string
+	CodeVersion         string
+	// VantagePoint is the vantage point type of the trace,
+	// i.e. "client", "server" or "transport".
+	VantagePoint string
+	// GroupID is the group ID of the trace.
+	// For connection traces, this is the original destination connection ID.
+	GroupID       string
+	ReferenceTime time.Time
+	EventSchemas  []string
+}
+
+// Event is an event read from a qlog trace.
+type Event struct {
+	// Time is the time at which the event occurred.
+	Time time.Time
+	// RelativeTime is the time of the event, relative to the reference time of the trace.
+	RelativeTime time.Duration
+	// Event is the typed event, e.g. a qlog.PacketSent or an http3/qlog.FrameParsed.
+	// Events that are unknown, or that can't be decoded into the corresponding typed event,
+	// are returned as a RawEvent.
+	Event qlogwriter.Event
+}
+
+// A RawEvent is an event that couldn't be decoded into a typed event.
+// It retains the event data as raw JSON.
+type RawEvent struct {
+	EventName string
+	Data      json.RawMessage
+}
+
+var _ qlogwriter.Event = RawEvent{}
+
+func (e RawEvent) Name() string { return e.EventName }
+
+func (e RawEvent) Encode(enc *jsontext.Encoder, _ time.Time) error {
+	if len(e.Data) == 0 {
+		return enc.WriteToken(jsontext.Null)
+	}
+	dec := json.NewDecoder(bytes.NewReader(e.Data))
+	dec.UseNumber()
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var tok jsontext.Token
+		switch v := t.(type) {
+		case json.Delim:
+			switch v {
+			case '{':
+				tok = jsontext.BeginObject
+			case '}':
+				tok = jsontext.EndObject
+			case '[':
+				tok = jsontext.BeginArray
+			case ']':
+				tok = jsontext.EndArray
+			}
+		case string:
+			tok = jsontext.String(v)
+		case bool:
+			tok = jsontext.Bool(v)
+		case nil:
+			tok = jsontext.Null
+		case json.Number:
+			if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+				tok = jsontext.Int(i)
+			} else if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+				tok = jsontext.Uint(u)
+			} else {
+				f, err := v.Float64()
+				if err != nil {
+					return err
+				}
+				tok = jsontext.Float(f)
+			}
+		}
+		if err := enc.WriteToken(tok); err != nil {
+			return err
+		}
+	}
+}
+
+// A Reader reads events from a JSON-SEQ qlog trace.
+type Reader struct {
+	r      *bufio.Reader
+	header Header
+}
+
+// NewReader creates a new Reader.
+// It reads and parses the header of the trace.
+func NewReader(r io.Reader) (*Reader, error) {
+	qr := &Reader{r: bufio.NewReader(r)}
+	rec, _, err := qr.readRecord()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("qlogreader: missing header: %w", io.ErrUnexpectedEOF)
+		}
+		return nil, err
+	}
+	h, err := parseHeader(rec)
+	if err != nil {
+		return nil, fmt.Errorf("qlogreader: failed to parse header: %w", err)
+	}
+	qr.header = h
+	return qr, nil
+}
+
+// Header returns the header of the trace.
+func (r *Reader) Header() Header { return r.header }
+
+// ReadEvent reads the next event.
+// It returns io.EOF when the end of the trace is reached.
+// If the trace ends with a truncated record, for example because the trace file
+// was not properly closed, it returns an error wrapping io.ErrUnexpectedEOF.
+func (r *Reader) ReadEvent() (Event, error) {
+	rec, last, err := r.readRecord()
+	if err != nil {
+		return Event{}, err
+	}
+	var e struct {
+		Time float64         `json:"time"`
+		Name string          `json:"name"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(rec, &e); err != nil {
+		if last {
+			return Event{}, fmt.Errorf("qlogreader: truncated record: %w", io.ErrUnexpectedEOF)
+		}
+		return Event{}, fmt.Errorf("qlogreader: failed to parse event: %w", err)
+	}
+	relTime := milliseconds(e.Time)
+	eventTime := r.header.ReferenceTime.Add(relTime)
+	return Event{
+		Time:         eventTime,
+		RelativeTime: relTime,
+		Event:        decodeEvent(e.Name, e.Data, eventTime),
+	}, nil
+}
+
+// ReadAll reads all remaining events.
+// Reaching the end of the trace is not treated as an error.
+func (r *Reader) ReadAll() ([]Event, error) {
+	var events []Event
+	for {
+		ev, err := r.ReadEvent()
+		if err != nil {
+			if err == io.EOF {
+				return events, nil
+			}
+			return events, err
+		}
+		events = append(events, ev)
+	}
+}
+
+// readRecord reads the next non-empty JSON-SEQ record.
+// last is true if the record is the last record in the stream.
+func (r *Reader) readRecord() (rec []byte, last bool, _ error) {
+	for {
+		b, err := r.r.ReadBytes(qlogwriter.RecordSeparator)
+		if err != nil && err != io.EOF {
+			return nil, false, err
+		}
+		b = bytes.TrimSpace(bytes.TrimSuffix(b, []byte{qlogwriter.RecordSeparator}))
+		if len(b) > 0 {
+			return b, err == io.EOF, nil
+		}
+		if err == io.EOF {
+			return nil, false, io.EOF
+		}
+	}
+}
+
+func parseHeader(b []byte) (Header, error) {
+	var h struct {
+		FileSchema          string `json:"file_schema"`
+		SerializationFormat string `json:"serialization_format"`
+		Title               string `json:"title"`
+		CodeVersion         string `json:"code_version"`
+		Trace               *struct {
+			EventSchemas []string `json:"event_schemas"`
+			VantagePoint struct {
+				Type string `json:"type"`
+			} `json:"vantage_point"`
+			CommonFields struct {
+				GroupID       string `json:"group_id"`
+				ReferenceTime struct {
+					WallClockTime string `json:"wall_clock_time"`
+				} `json:"reference_time"`
+			} `json:"common_fields"`
+		} `json:"trace"`
+	}
+	if err := json.Unmarshal(b, &h); err != nil {
+		return Header{}, err
+	}
+	if h.Trace == nil {
+		return Header{}, errors.New("missing trace")
+	}
+	var refTime time.Time
+	if t := h.Trace.CommonFields.ReferenceTime.WallClockTime; t != "" {
+		var err error
+		refTime, err = time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return Header{}, err
+		}
+	}
+	return Header{
+		FileSchema:          h.FileSchema,
+		SerializationFormat: h.SerializationFormat,
+		Title:               h.Title,
+		CodeVersion:         h.CodeVersion,
+		VantagePoint:        h.Trace.VantagePoint.Type,
+		GroupID:             h.Trace.CommonFields.GroupID,
+		ReferenceTime:       refTime,
+		EventSchemas:        h.Trace.EventSchemas,
+	}, nil
+}
+
+func milliseconds(ms float64) time.Duration {
+	return time.Duration(math.Round(ms * 1e6))
+}
+
+type eventDecoder func(data []byte, eventTime time.Time) (qlogwriter.Event, error)
+
+func decodeEvent(name string, data []byte, eventTime time.Time) qlogwriter.Event {
+	if dec, ok := quicEventDecoders[name]; ok {
+		if ev, err := dec(data, eventTime); err == nil {
+			return ev
+		}
+	} else if dec, ok := http3EventDecoders[name]; ok {
+		if ev, err := dec(data, eventTime); err == nil {
+			return ev
+		}
+	}
+	return RawEvent{EventName: name, Data: json.RawMessage(data)}
+}
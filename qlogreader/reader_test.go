@@ -0,0 +1,462 @@
+package qlogreader
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/netip"
+	"testing"
+	"time"
+
+	h3qlog "github.com/quic-go/quic-go/http3/qlog"
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/internal/synctest"
+	"github.com/quic-go/quic-go/qlog"
+	"github.com/quic-go/quic-go/qlogwriter"
+	"github.com/quic-go/quic-go/qlogwriter/jsontext"
+
+	"github.com/stretchr/testify/require"
+)
+
+type nopWriteCloserImpl struct{ io.Writer }
+
+func (nopWriteCloserImpl) Close() error { return nil }
+
+// writeTrace writes a connection trace containing the events, one event per second.
+func writeTrace(t *testing.T, events ...qlogwriter.Event) (start time.Time, _ []byte) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	synctest.Test(t, func(t *testing.T) {
+		start = time.Now()
+		tr := qlogwriter.NewConnectionFileSeq(
+			&nopWriteCloserImpl{Writer: &buf},
+			true,
+			protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef}),
+			[]string{qlog.EventSchema, h3qlog.EventSchema},
+		)
+		go tr.Run()
+		producer := tr.AddProducer()
+		for _, ev := range events {
+			synctest.Wait()
+			time.Sleep(time.Second)
+			producer.RecordEvent(ev)
+		}
+		producer.Close()
+	})
+	return start, buf.Bytes()
+}
+
+func readTrace(t *testing.T, data []byte) (Header, []Event) {
+	t.Helper()
+	r, err := NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	events, err := r.ReadAll()
+	require.NoError(t, err)
+	return r.Header(), events
+}
+
+func TestReaderHeader(t *testing.T) {
+	start, data := writeTrace(t)
+	hdr, events := readTrace(t, data)
+	require.Empty(t, events)
+	require.Equal(t, "urn:ietf:params:qlog:file:sequential", hdr.FileSchema)
+	require.Equal(t, "application/qlog+json-seq", hdr.SerializationFormat)
+	require.Equal(t, "client", hdr.VantagePoint)
+	require.Equal(t, "deadbeef", hdr.GroupID)
+	require.Equal(t, []string{qlog.EventSchema, h3qlog.EventSchema}, hdr.EventSchemas)
+	require.True(t, hdr.ReferenceTime.Equal(start))
+}
+
+func TestReaderEventTimes(t *testing.T) {
+	start, data := writeTrace(t,
+		qlog.ALPNInformation{ChosenALPN: "h3"},
+		qlog.MTUUpdated{Value: 1337, Done: true},
+	)
+	_, events := readTrace(t, data)
+	require.Len(t, events, 2)
+	require.Equal(t, time.Second, events[0].RelativeTime)
+	require.True(t, events[0].Time.Equal(start.Add(time.Second)))
+	require.Equal(t, 2*time.Second, events[1].RelativeTime)
+	require.True(t, events[1].Time.Equal(start.Add(2*time.Second)))
+}
+
+func TestReaderQUICEvents(t *testing.T) {
+	connID := protocol.ParseConnectionID([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	token := protocol.StatelessResetToken{0xf, 0xe, 0xd, 0xc, 0xb, 0xa, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0}
+	transportErr := qerr.FlowControlError
+	cryptoErr := qerr.TransportErrorCode(0x12a)
+	unknownErr := qerr.TransportErrorCode(0x1337)
+	appErr := qlog.ApplicationErrorCode(42)
+	pathID := protocol.PathID(7)
+
+	for _, ev := range []qlogwriter.Event{
+		qlog.StartedConnection{
+			Local:  qlog.PathEndpointInfo{IPv4: netip.MustParseAddrPort("192.168.1.1:443")},
+			Remote: qlog.PathEndpointInfo{IPv6: netip.MustParseAddrPort("[2001:db8::1]:1234")},
+		},
+		qlog.VersionInformation{
+			ClientVersions: []qlog.Version{protocol.Version1, protocol.Version2},
+			ServerVersions: []qlog.Version{protocol.Version2},
+			ChosenVersion:  protocol.Version2,
+		},
+		qlog.ConnectionClosed{Initiator: qlog.InitiatorLocal, ConnectionError: &transportErr, Reason: "foobar"},
+		qlog.ConnectionClosed{Initiator: qlog.InitiatorRemote, ConnectionError: &cryptoErr, Reason: "tls"},
+		qlog.ConnectionClosed{Initiator: qlog.InitiatorRemote, ConnectionError: &unknownErr, Reason: "?"},
+		qlog.ConnectionClosed{Initiator: qlog.InitiatorLocal, ApplicationError: &appErr, Reason: "app"},
+		qlog.ConnectionClosed{Initiator: qlog.InitiatorLocal, Trigger: qlog.ConnectionCloseTriggerIdleTimeout},
+		qlog.PacketSent{
+			Header: qlog.PacketHeader{
+				PacketType:       qlog.PacketTypeInitial,
+				PacketNumber:     42,
+				Version:          protocol.Version1,
+				SrcConnectionID:  connID,
+				DestConnectionID: protocol.ParseConnectionID([]byte{0xca, 0xfe}),
+				Token:            &qlog.Token{Raw: []byte("token")},
+			},
+			Raw: qlog.RawInfo{Length: 1200, PayloadLength: 1100},
+			Frames: []qlog.Frame{
+				{Frame: &qlog.CryptoFrame{Offset: 10, Length: 100}},
+				{Frame: &qlog.AckFrame{
+					AckRanges: []qlog.AckRange{{Smallest: 10, Largest: 12}, {Smallest: 5, Largest: 5}},
+					DelayTime: 3 * time.Millisecond,
+					ECT0:      1, ECT1: 2, ECNCE: 3,
+				}},
+				{Frame: &qlog.PingFrame{}},
+			},
+			ECN:         qlog.ECT0,
+			IsCoalesced: true,
+			Trigger:     "pto_probe",
+		},
+		qlog.PacketReceived{
+			Header: qlog.PacketHeader{
+				PacketType:       qlog.PacketType1RTT,
+				PacketNumber:     1337,
+				KeyPhaseBit:      qlog.KeyPhaseOne,
+				DestConnectionID: connID,
+			},
+			Raw: qlog.RawInfo{Length: 100},
+			Frames: []qlog.Frame{
+				{Frame: &qlog.StreamFrame{StreamID: 4, Offset: 100, Length: 50, Fin: true}},
+				{Frame: &qlog.DatagramFrame{Length: 20}},
+			},
+		},
+		qlog.PacketSent{
+			Header: qlog.PacketHeader{
+				PacketType:       qlog.PacketTypeRetry,
+				PacketNumber:     protocol.InvalidPacketNumber,
+				Version:          protocol.Version1,
+				SrcConnectionID:  connID,
+				DestConnectionID: connID,
+			},
+		},
+		qlog.VersionNegotiationReceived{
+			Header: qlog.PacketHeaderVersionNegotiation{
+				SrcConnectionID:  qlog.ArbitraryLenConnectionID{1, 2, 3},
+				DestConnectionID: qlog.ArbitraryLenConnectionID{4, 5, 6, 7},
+			},
+			SupportedVersions: []qlog.Version{protocol.Version1, 0xdeadbeef},
+		},
+		qlog.VersionNegotiationSent{
+			Header: qlog.PacketHeaderVersionNegotiation{
+				SrcConnectionID:  qlog.ArbitraryLenConnectionID{1, 2, 3},
+				DestConnectionID: qlog.ArbitraryLenConnectionID{4, 5, 6, 7},
+			},
+			SupportedVersions: []qlog.Version{protocol.Version2},
+		},
+		qlog.PacketBuffered{
+			Header: qlog.PacketHeader{PacketType: qlog.PacketTypeHandshake, PacketNumber: 3, Version: protocol.Version1, DestConnectionID: connID},
+			Raw:    qlog.RawInfo{Length: 500},
+		},
+		qlog.PacketDropped{
+			Header:  qlog.PacketHeader{PacketType: qlog.PacketType1RTT, PacketNumber: protocol.InvalidPacketNumber, DestConnectionID: connID},
+			Raw:     qlog.RawInfo{Length: 50},
+			Trigger: qlog.PacketDropPayloadDecryptError,
+		},
+		qlog.ParametersSet{
+			Initiator:                       qlog.InitiatorRemote,
+			SentBy:                          protocol.PerspectiveServer,
+			OriginalDestinationConnectionID: connID,
+			InitialSourceConnectionID:       protocol.ParseConnectionID([]byte{0xde, 0xca, 0xfb, 0xad}),
+			RetrySourceConnectionID:         &connID,
+			StatelessResetToken:             &token,
+			DisableActiveMigration:          true,
+			MaxIdleTimeout:                  30 * time.Second,
+			MaxUDPPayloadSize:               1452,
+			AckDelayExponent:                3,
+			MaxAckDelay:                     25 * time.Millisecond,
+			ActiveConnectionIDLimit:         4,
+			InitialMaxData:                  1 << 20,
+			InitialMaxStreamDataBidiLocal:   1 << 16,
+			InitialMaxStreamDataBidiRemote:  1 << 17,
+			InitialMaxStreamDataUni:         1 << 18,
+			InitialMaxStreamsBidi:           100,
+			InitialMaxStreamsUni:            10,
+			PreferredAddress: &qlog.PreferredAddress{
+				IPv4:                netip.MustParseAddrPort("10.0.0.1:443"),
+				IPv6:                netip.MustParseAddrPort("[::1]:443"),
+				ConnectionID:        connID,
+				StatelessResetToken: token,
+			},
+			MaxDatagramFrameSize: 1200,
+			EnableResetStreamAt:  true,
+			InitialMaxPathID:     &pathID,
+		},
+		qlog.ParametersSet{
+			Initiator:                 qlog.InitiatorLocal,
+			SentBy:                    protocol.PerspectiveClient,
+			InitialSourceConnectionID: protocol.ConnectionID{},
+			MaxDatagramFrameSize:      protocol.InvalidByteCount,
+		},
+		qlog.ParametersSet{
+			Restore:              true,
+			SentBy:               protocol.PerspectiveClient,
+			InitialMaxData:       1000,
+			MaxDatagramFrameSize: protocol.InvalidByteCount,
+		},
+		qlog.MigrationStateUpdated{
+			State:  qlog.MigrationStateProbingStarted,
+			Remote: qlog.PathEndpointInfo{IPv4: netip.MustParseAddrPort("1.2.3.4:5678")},
+		},
+		qlog.ALPNInformation{ChosenALPN: "h3"},
+		qlog.ConnectionRejected{
+			Remote: qlog.PathEndpointInfo{IPv4: netip.MustParseAddrPort("1.2.3.4:5678")},
+			Reason: qlog.ConnectionRejectionMaxHandshakes,
+			Retry:  true,
+		},
+		qlog.RetryStateUpdated{Enabled: true, UnvalidatedHandshakes: 10},
+		qlog.MTUUpdated{Value: 1400, Done: true},
+		qlog.MetricsUpdated{
+			MinRTT:            10 * time.Millisecond,
+			SmoothedRTT:       12345 * time.Microsecond,
+			LatestRTT:         15 * time.Millisecond,
+			RTTVariance:       1234567 * time.Nanosecond,
+			CongestionWindow:  12000,
+			BytesInFlight:     6000,
+			PacketsInFlight:   5,
+			PacingRate:        1 << 25,
+			BandwidthEstimate: 1 << 24,
+		},
+		qlog.PTOCountUpdated{PTOCount: 3},
+		qlog.PacketLost{
+			Header:  qlog.PacketHeader{PacketType: qlog.PacketType1RTT, PacketNumber: 99},
+			Trigger: qlog.PacketLossTimeThreshold,
+		},
+		qlog.SpuriousLoss{
+			EncryptionLevel:  protocol.EncryptionHandshake,
+			PacketNumber:     7,
+			PacketReordering: 3,
+			TimeReordering:   5 * time.Millisecond,
+		},
+		qlog.LossTimerUpdated{
+			Type:      qlog.LossTimerUpdateTypeExpired,
+			TimerType: qlog.TimerTypePTO,
+			EncLevel:  protocol.Encryption1RTT,
+		},
+		qlog.LossTimerUpdated{Type: qlog.LossTimerUpdateTypeCancelled},
+		qlog.CongestionStateUpdated{State: qlog.CongestionStateRecovery},
+		qlog.ECNStateUpdated{State: qlog.ECNStateCapable, Trigger: "ACKed"},
+		qlog.KeyUpdated{Trigger: qlog.KeyUpdateLocal, KeyType: qlog.KeyTypeClient1RTT, KeyPhase: 2},
+		qlog.KeyUpdated{Trigger: qlog.KeyUpdateTLS, KeyType: qlog.KeyTypeServerHandshake},
+		qlog.KeyDiscarded{KeyType: qlog.KeyTypeServerInitial},
+		qlog.KeyDiscarded{KeyType: qlog.KeyTypeServer1RTT, KeyPhase: 4},
+	} {
+		t.Run(ev.Name(), func(t *testing.T) {
+			_, data := writeTrace(t, ev)
+			_, events := readTrace(t, data)
+			require.Len(t, events, 1)
+			require.Equal(t, ev, events[0].Event)
+		})
+	}
+}
+
+func TestReaderLossTimerSet(t *testing.T) {
+	var start time.Time
+	var buf bytes.Buffer
+	synctest.Test(t, func(t *testing.T) {
+		start = time.Now()
+		tr := qlogwriter.NewConnectionFileSeq(&nopWriteCloserImpl{Writer: &buf}, true, protocol.ConnectionID{}, nil)
+		go tr.Run()
+		producer := tr.AddProducer()
+		synctest.Wait()
+		time.Sleep(time.Second)
+		producer.RecordEvent(qlog.LossTimerUpdated{
+			Type:      qlog.LossTimerUpdateTypeSet,
+			TimerType: qlog.TimerTypeACK,
+			EncLevel:  protocol.EncryptionInitial,
+			Time:      time.Now().Add(250 * time.Millisecond),
+		})
+		producer.Close()
+	})
+
+	_, events := readTrace(t, buf.Bytes())
+	require.Len(t, events, 1)
+	ev := events[0].Event.(qlog.LossTimerUpdated)
+	require.Equal(t, qlog.LossTimerUpdateTypeSet, ev.Type)
+	require.Equal(t, qlog.TimerTypeACK, ev.TimerType)
+	require.Equal(t, protocol.EncryptionInitial, ev.EncLevel)
+	require.True(t, ev.Time.Equal(start.Add(time.Second+250*time.Millisecond)))
+}
+
+func TestReaderQUICFrames(t *testing.T) {
+	connID := protocol.ParseConnectionID([]byte{1, 2, 3, 4})
+	token := protocol.StatelessResetToken{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	frames := []qlog.Frame{
+		{Frame: &qlog.PingFrame{}},
+		{Frame: &qlog.AckFrame{AckRanges: []qlog.AckRange{{Smallest: 1, Largest: 1}}}},
+		{Frame: &qlog.ResetStreamFrame{StreamID: 4, ErrorCode: 42, FinalSize: 1000}},
+		{Frame: &qlog.ResetStreamFrame{StreamID: 8, ErrorCode: 43, FinalSize: 1000, ReliableSize: 500}},
+		{Frame: &qlog.StopSendingFrame{StreamID: 4, ErrorCode: 1337}},
+		{Frame: &qlog.CryptoFrame{Offset: 1, Length: 2}},
+		{Frame: &qlog.NewTokenFrame{Token: []byte("foobar")}},
+		{Frame: &qlog.StreamFrame{StreamID: 3, Offset: 4, Length: 5}},
+		{Frame: &qlog.MaxDataFrame{MaximumData: 1 << 20}},
+		{Frame: &qlog.MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 1 << 16}},
+		{Frame: &qlog.MaxStreamsFrame{Type: protocol.StreamTypeUni, MaxStreamNum: 10}},
+		{Frame: &qlog.DataBlockedFrame{MaximumData: 1 << 20}},
+		{Frame: &qlog.StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 1 << 16}},
+		{Frame: &qlog.StreamsBlockedFrame{Type: protocol.StreamTypeBidi, StreamLimit: 100}},
+		{Frame: &qlog.NewConnectionIDFrame{SequenceNumber: 3, RetirePriorTo: 1, ConnectionID: connID, StatelessResetToken: token}},
+		{Frame: &qlog.RetireConnectionIDFrame{SequenceNumber: 2}},
+		{Frame: &qlog.PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}},
+		{Frame: &qlog.PathResponseFrame{Data: [8]byte{8, 7, 6, 5, 4, 3, 2, 1}}},
+		{Frame: &qlog.ConnectionCloseFrame{ErrorCode: uint64(qerr.ProtocolViolation), ReasonPhrase: "foo"}},
+		{Frame: &qlog.ConnectionCloseFrame{IsApplicationError: true, ErrorCode: 1337, ReasonPhrase: "bar"}},
+		{Frame: &qlog.HandshakeDoneFrame{}},
+		{Frame: &qlog.DatagramFrame{Length: 100}},
+		{Frame: &qlog.AckFrequencyFrame{SequenceNumber: 1, AckElicitingThreshold: 2, RequestMaxAckDelay: 25 * time.Millisecond, ReorderingThreshold: 3}},
+		{Frame: &qlog.ImmediateAckFrame{}},
+		{Frame: &qlog.PathAckFrame{PathID: 1, AckFrame: qlog.AckFrame{AckRanges: []qlog.AckRange{{Smallest: 3, Largest: 10}}}}},
+		{Frame: &qlog.PathAbandonFrame{PathID: 2, ErrorCode: 5}},
+		{Frame: &qlog.PathStatusFrame{PathID: 3, SequenceNumber: 4, Backup: true}},
+		{Frame: &qlog.PathStatusFrame{PathID: 3, SequenceNumber: 5}},
+		{Frame: &qlog.PathNewConnectionIDFrame{PathID: 1, SequenceNumber: 2, RetirePriorTo: 1, ConnectionID: connID, StatelessResetToken: token}},
+		{Frame: &qlog.PathRetireConnectionIDFrame{PathID: 1, SequenceNumber: 2}},
+		{Frame: &qlog.MaxPathIDFrame{MaximumPathID: 10}},
+		{Frame: &qlog.PathsBlockedFrame{MaximumPathID: 10}},
+		{Frame: &qlog.PathConnectionIDsBlockedFrame{PathID: 1, NextSequenceNumber: 4}},
+	}
+	ev := qlog.PacketSent{
+		Header: qlog.PacketHeader{PacketType: qlog.PacketType1RTT, PacketNumber: 1, KeyPhaseBit: qlog.KeyPhaseZero},
+		Raw:    qlog.RawInfo{Length: 1000},
+		Frames: frames,
+	}
+	_, data := writeTrace(t, ev)
+	_, events := readTrace(t, data)
+	require.Len(t, events, 1)
+	require.Equal(t, ev, events[0].Event)
+}
+
+func TestReaderHTTP3Events(t *testing.T) {
+	datagram := true
+	for _, ev := range []qlogwriter.Event{
+		h3qlog.FrameCreated{
+			StreamID: 4,
+			Raw:      h3qlog.RawInfo{Length: 10, PayloadLength: 8},
+			Frame:    h3qlog.Frame{Frame: h3qlog.DataFrame{}},
+		},
+		h3qlog.FrameParsed{
+			StreamID: 0,
+			Frame: h3qlog.Frame{Frame: h3qlog.HeadersFrame{HeaderFields: []h3qlog.HeaderField{
+				{Name: ":method", Value: "GET"},
+				{Name: ":path", Value: "/"},
+			}}},
+		},
+		h3qlog.FrameParsed{StreamID: 3, Frame: h3qlog.Frame{Frame: h3qlog.GoAwayFrame{StreamID: 100}}},
+		h3qlog.FrameCreated{StreamID: 3, Frame: h3qlog.Frame{Frame: h3qlog.SettingsFrame{
+			Datagram: &datagram,
+			Other:    map[uint64]uint64{0x1337: 42},
+		}}},
+		h3qlog.FrameCreated{StreamID: 0, Frame: h3qlog.Frame{Frame: h3qlog.PushPromiseFrame{}}},
+		h3qlog.FrameCreated{StreamID: 3, Frame: h3qlog.Frame{Frame: h3qlog.CancelPushFrame{}}},
+		h3qlog.FrameCreated{StreamID: 3, Frame: h3qlog.Frame{Frame: h3qlog.MaxPushIDFrame{}}},
+		h3qlog.FrameParsed{StreamID: 0, Frame: h3qlog.Frame{Frame: h3qlog.ReservedFrame{Type: 0x21}}},
+		h3qlog.FrameParsed{StreamID: 0, Frame: h3qlog.Frame{Frame: h3qlog.UnknownFrame{Type: 0x1234}}},
+		h3qlog.DatagramCreated{QuaterStreamID: 1, Raw: h3qlog.RawInfo{Length: 100}},
+		h3qlog.DatagramParsed{QuaterStreamID: 2, Raw: h3qlog.RawInfo{PayloadLength: 50}},
+	} {
+		t.Run(ev.Name(), func(t *testing.T) {
+			_, data := writeTrace(t, ev)
+			_, events := readTrace(t, data)
+			require.Len(t, events, 1)
+			require.Equal(t, ev, events[0].Event)
+		})
+	}
+}
+
+type unknownEvent struct{}
+
+func (unknownEvent) Name() string { return "foo:bar" }
+
+func (unknownEvent) Encode(enc *jsontext.Encoder, _ time.Time) error {
+	for _, tok := range []jsontext.Token{
+		jsontext.BeginObject,
+		jsontext.String("foo"), jsontext.Int(-42),
+		jsontext.String("bar"), jsontext.BeginArray, jsontext.Float(1.5), jsontext.Uint(1 << 63), jsontext.Null, jsontext.EndArray,
+		jsontext.String("baz"), jsontext.True,
+		jsontext.EndObject,
+	} {
+		if err := enc.WriteToken(tok); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestReaderUnknownEvents(t *testing.T) {
+	_, data := writeTrace(t,
+		unknownEvent{},
+		// known event name, but unknown frame type
+		qlog.PacketSent{
+			Header: qlog.PacketHeader{PacketType: qlog.PacketType1RTT, PacketNumber: 1},
+			Frames: []qlog.Frame{{Frame: &qlog.PingFrame{}}},
+		},
+	)
+	data = bytes.Replace(data, []byte(`"frame_type":"ping"`), []byte(`"frame_type":"foobar"`), 1)
+
+	_, events := readTrace(t, data)
+	require.Len(t, events, 2)
+	raw, ok := events[0].Event.(RawEvent)
+	require.True(t, ok)
+	require.Equal(t, "foo:bar", raw.Name())
+	require.JSONEq(t, `{"foo":-42,"bar":[1.5,9223372036854775808,null],"baz":true}`, string(raw.Data))
+
+	raw, ok = events[1].Event.(RawEvent)
+	require.True(t, ok)
+	require.Equal(t, "transport:packet_sent", raw.Name())
+	require.Contains(t, string(raw.Data), "foobar")
+
+	// raw events can be encoded again
+	_, data = writeTrace(t, events[0].Event)
+	_, events = readTrace(t, data)
+	require.Len(t, events, 1)
+	require.Equal(t, "foo:bar", events[0].Event.Name())
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(events[0].Event.(RawEvent).Data, &m))
+	require.Equal(t, map[string]any{"foo": -42.0, "bar": []any{1.5, 9223372036854775808.0, nil}, "baz": true}, m)
+}
+
+func TestReaderTruncatedTrace(t *testing.T) {
+	_, data := writeTrace(t,
+		qlog.ALPNInformation{ChosenALPN: "h3"},
+		qlog.MTUUpdated{Value: 1400},
+	)
+
+	r, err := NewReader(bytes.NewReader(data[:len(data)-5]))
+	require.NoError(t, err)
+	ev, err := r.ReadEvent()
+	require.NoError(t, err)
+	require.Equal(t, qlog.ALPNInformation{ChosenALPN: "h3"}, ev.Event)
+	_, err = r.ReadEvent()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestReaderInvalidHeader(t *testing.T) {
+	_, err := NewReader(bytes.NewReader(nil))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = NewReader(bytes.NewReader([]byte("\x1e{\"foo\":\"bar\"}\n")))
+	require.ErrorContains(t, err, "missing trace")
+}
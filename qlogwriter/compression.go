@@ -0,0 +1,62 @@
+package qlogwriter
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// A Compression compresses qlog output.
+//
+// gzip is implemented by this package.
+// zstd is implemented by the github.com/quic-go/quic-go/qlogwriter/zstd module,
+// which is a separate module to avoid adding a dependency to quic-go.
+// Other algorithms can be used by implementing this interface.
+type Compression interface {
+	// NewWriter returns a writer that compresses the data written to it, and writes it to w.
+	// Closing the returned writer must flush all buffered data, but must not close w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	// FileExtension is the file extension for compressed files, e.g. ".gz".
+	FileExtension() string
+}
+
+// Gzip is the gzip Compression.
+type Gzip struct {
+	// Level is the compression level, see compress/gzip.
+	// If zero, gzip.DefaultCompression is used.
+	Level int
+}
+
+var _ Compression = Gzip{}
+
+func (c Gzip) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+func (Gzip) FileExtension() string { return ".gz" }
+
+type compressedWriter struct {
+	io.WriteCloser // the compressing writer
+	w              io.Closer
+}
+
+// NewCompressedWriter returns a writer that compresses the data written to it, and writes it to w.
+// Closing the returned writer flushes all buffered data, and then closes w.
+func NewCompressedWriter(w io.WriteCloser, c Compression) (io.WriteCloser, error) {
+	cw, err := c.NewWriter(w)
+	if err != nil {
+		return nil, err
+	}
+	return &compressedWriter{WriteCloser: cw, w: w}, nil
+}
+
+func (w *compressedWriter) Close() error {
+	err := w.WriteCloser.Close()
+	if cerr := w.w.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
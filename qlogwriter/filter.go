@@ -0,0 +1,138 @@
+package qlogwriter
+
+import (
+	"slices"
+	"strings"
+)
+
+// Importance is the importance level of an event, as defined by the qlog main schema,
+// https://www.ietf.org/archive/id/draft-ietf-quic-qlog-main-schema-12.html#section-8.3
+type Importance uint8
+
+const (
+	// ImportanceCore is the importance of events that should be present in every qlog trace.
+	ImportanceCore Importance = 1 + iota
+	// ImportanceBase is the importance of events that are useful for most debugging scenarios.
+	ImportanceBase
+	// ImportanceExtra is the importance of events that are only useful for specific debugging scenarios.
+	ImportanceExtra
+)
+
+func (i Importance) String() string {
+	switch i {
+	case ImportanceCore:
+		return "core"
+	case ImportanceBase:
+		return "base"
+	case ImportanceExtra:
+		return "extra"
+	default:
+		return "unknown importance"
+	}
+}
+
+// An ImportanceEvent is an Event that reports its importance level.
+// Events that don't implement this interface are treated as ImportanceBase.
+type ImportanceEvent interface {
+	Event
+	Importance() Importance
+}
+
+func eventImportance(ev Event) Importance {
+	if e, ok := ev.(ImportanceEvent); ok {
+		return e.Importance()
+	}
+	return ImportanceBase
+}
+
+// A Filter selects the events that are recorded to a trace.
+// The zero value records all events.
+type Filter struct {
+	// Events is the list of event names that are recorded, e.g. "transport:packet_sent".
+	// An entry of the form "<category>:*" matches all events of that category, e.g. "recovery:*".
+	// If empty, all events are recorded.
+	Events []string
+	// ExcludeEvents is the list of event names that are not recorded.
+	// It takes precedence over Events, and uses the same syntax.
+	ExcludeEvents []string
+	// Schemas is the list of event schemas that the trace reports as supported.
+	// Event producers only record events for supported schemas, see Trace.SupportsSchemas.
+	// If empty, the schemas of the underlying trace are used.
+	// This field has no effect for a Recorder created by NewFilteredRecorder.
+	Schemas []string
+	// Importance is the least important level of events that are recorded.
+	// For example, ImportanceBase records core and base events, but drops extra events.
+	// If zero, events of all importance levels are recorded.
+	Importance Importance
+}
+
+func (f *Filter) allows(ev Event) bool {
+	if f.Importance != 0 && eventImportance(ev) > f.Importance {
+		return false
+	}
+	name := ev.Name()
+	if len(f.Events) > 0 && !matchesEventName(f.Events, name) {
+		return false
+	}
+	return !matchesEventName(f.ExcludeEvents, name)
+}
+
+func matchesEventName(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if category, ok := strings.CutSuffix(p, ":*"); ok {
+			if c, _, ok := strings.Cut(name, ":"); ok && c == category {
+				return true
+			}
+			continue
+		}
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+type filteredTrace struct {
+	trace  Trace
+	filter Filter
+}
+
+var _ Trace = &filteredTrace{}
+
+// NewFilteredTrace wraps a Trace, such that only events allowed by the filter are recorded.
+func NewFilteredTrace(t Trace, f Filter) Trace {
+	return &filteredTrace{trace: t, filter: f}
+}
+
+func (t *filteredTrace) AddProducer() Recorder {
+	r := t.trace.AddProducer()
+	if r == nil {
+		return nil
+	}
+	return NewFilteredRecorder(r, t.filter)
+}
+
+func (t *filteredTrace) SupportsSchemas(schema string) bool {
+	if len(t.filter.Schemas) > 0 && !slices.Contains(t.filter.Schemas, schema) {
+		return false
+	}
+	return t.trace.SupportsSchemas(schema)
+}
+
+type filteredRecorder struct {
+	Recorder
+	filter Filter
+}
+
+// NewFilteredRecorder wraps a Recorder, such that only events allowed by the filter are recorded.
+// This is useful to filter the events recorded by the quic.Transport's Tracer.
+func NewFilteredRecorder(r Recorder, f Filter) Recorder {
+	return &filteredRecorder{Recorder: r, filter: f}
+}
+
+func (r *filteredRecorder) RecordEvent(ev Event) {
+	if !r.filter.allows(ev) {
+		return
+	}
+	r.Recorder.RecordEvent(ev)
+}
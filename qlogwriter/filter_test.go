@@ -0,0 +1,146 @@
+package qlogwriter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/qlogwriter/jsontext"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testImportanceEvent struct {
+	name       string
+	importance Importance
+}
+
+func (e testImportanceEvent) Name() string           { return e.name }
+func (e testImportanceEvent) Importance() Importance { return e.importance }
+func (e testImportanceEvent) Encode(enc *jsontext.Encoder, _ time.Time) error {
+	h := encoderHelper{enc: enc}
+	h.WriteToken(jsontext.BeginObject)
+	h.WriteToken(jsontext.EndObject)
+	return h.err
+}
+
+type eventRecorder struct {
+	events []string
+	closed bool
+}
+
+func (r *eventRecorder) RecordEvent(ev Event) { r.events = append(r.events, ev.Name()) }
+func (r *eventRecorder) Close() error         { r.closed = true; return nil }
+
+func TestFilter(t *testing.T) {
+	events := []Event{
+		testImportanceEvent{name: "transport:packet_sent", importance: ImportanceCore},
+		testImportanceEvent{name: "transport:packet_dropped", importance: ImportanceBase},
+		testImportanceEvent{name: "recovery:metrics_updated", importance: ImportanceCore},
+		testImportanceEvent{name: "recovery:loss_timer_updated", importance: ImportanceExtra},
+		testEvent{message: "no importance"}, // transport:test_event
+	}
+
+	for _, tc := range []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{
+			name:   "zero value",
+			filter: Filter{},
+			expected: []string{
+				"transport:packet_sent",
+				"transport:packet_dropped",
+				"recovery:metrics_updated",
+				"recovery:loss_timer_updated",
+				"transport:test_event",
+			},
+		},
+		{
+			name:     "event names",
+			filter:   Filter{Events: []string{"transport:packet_sent", "recovery:loss_timer_updated"}},
+			expected: []string{"transport:packet_sent", "recovery:loss_timer_updated"},
+		},
+		{
+			name:     "category",
+			filter:   Filter{Events: []string{"recovery:*"}},
+			expected: []string{"recovery:metrics_updated", "recovery:loss_timer_updated"},
+		},
+		{
+			name:     "excluded events",
+			filter:   Filter{Events: []string{"transport:*"}, ExcludeEvents: []string{"transport:packet_dropped"}},
+			expected: []string{"transport:packet_sent", "transport:test_event"},
+		},
+		{
+			name:     "excluded category",
+			filter:   Filter{ExcludeEvents: []string{"transport:*"}},
+			expected: []string{"recovery:metrics_updated", "recovery:loss_timer_updated"},
+		},
+		{
+			name:     "core importance",
+			filter:   Filter{Importance: ImportanceCore},
+			expected: []string{"transport:packet_sent", "recovery:metrics_updated"},
+		},
+		{
+			name:   "base importance",
+			filter: Filter{Importance: ImportanceBase},
+			// events that don't report their importance are treated as base events
+			expected: []string{"transport:packet_sent", "transport:packet_dropped", "recovery:metrics_updated", "transport:test_event"},
+		},
+		{
+			name:     "importance and event names",
+			filter:   Filter{Importance: ImportanceCore, Events: []string{"transport:*"}},
+			expected: []string{"transport:packet_sent"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &eventRecorder{}
+			r := NewFilteredRecorder(rec, tc.filter)
+			for _, ev := range events {
+				r.RecordEvent(ev)
+			}
+			require.Equal(t, tc.expected, rec.events)
+			require.NoError(t, r.Close())
+			require.True(t, rec.closed)
+		})
+	}
+}
+
+func TestFilteredTrace(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fileSeq := NewConnectionFileSeq(
+		nopWriteCloser(buf),
+		true,
+		protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef}),
+		[]string{"urn:ietf:params:qlog:events:foo", "urn:ietf:params:qlog:events:bar"},
+	)
+	go fileSeq.Run()
+	require.True(t, fileSeq.SupportsSchemas("urn:ietf:params:qlog:events:foo"))
+	require.True(t, fileSeq.SupportsSchemas("urn:ietf:params:qlog:events:bar"))
+	require.False(t, fileSeq.SupportsSchemas("urn:ietf:params:qlog:events:baz"))
+
+	trace := NewFilteredTrace(fileSeq, Filter{
+		Schemas:       []string{"urn:ietf:params:qlog:events:foo", "urn:ietf:params:qlog:events:baz"},
+		ExcludeEvents: []string{"transport:test_event"},
+	})
+	require.True(t, trace.SupportsSchemas("urn:ietf:params:qlog:events:foo"))
+	require.False(t, trace.SupportsSchemas("urn:ietf:params:qlog:events:bar"))
+	// the underlying trace doesn't support this schema
+	require.False(t, trace.SupportsSchemas("urn:ietf:params:qlog:events:baz"))
+
+	producer := trace.AddProducer()
+	producer.RecordEvent(testEvent{message: "foobar"})
+	producer.RecordEvent(testImportanceEvent{name: "transport:packet_sent", importance: ImportanceCore})
+	require.NoError(t, producer.Close())
+
+	records := bytes.Split(buf.Bytes(), recordSeparator)[1:]
+	require.Len(t, records, 2) // header and one event
+	var ev map[string]any
+	require.NoError(t, unmarshal(records[1], &ev))
+	require.Equal(t, "transport:packet_sent", ev["name"])
+
+	// the trace is closed, so no new producers can be added
+	require.Nil(t, trace.AddProducer())
+}
@@ -60,10 +60,18 @@ const eventChanSize = 50
 // qlog event producers can be created by calling AddProducer.
 // The underlying io.WriteCloser is closed when the last producer is removed.
 type FileSeq struct {
-	w             io.WriteCloser
+	w             *countingWriteCloser
 	enc           *jsontext.Encoder
 	referenceTime time.Time
 
+	vantagePoint string
+	odcid        *ConnectionID
+
+	// only set for rotating traces
+	open      func(index int) (io.WriteCloser, error)
+	rotation  Rotation
+	fileIndex int
+
 	runStopped chan struct{}
 	encodeErr  error
 	events     chan event
@@ -91,31 +99,66 @@ func NewConnectionFileSeq(w io.WriteCloser, isClient bool, odcid ConnectionID, e
 	return newFileSeq(w, pers, &odcid, eventSchemas)
 }
 
+// Rotation configures when a rotating trace starts writing to a new file.
+// Rotation is checked before an event is written, so no new file is started while no events are recorded.
+type Rotation struct {
+	// MaxSize is the size in bytes after which a new file is started.
+	// For compressed output, this is the size before compression.
+	// If zero, the size of the files is not limited.
+	MaxSize int64
+	// MaxAge is the duration after which a new file is started.
+	// If zero, the age of the files is not limited.
+	MaxAge time.Duration
+}
+
+// NewRotatingFileSeq creates a new JSON-SEQ qlog trace to log transport events,
+// which is written to a sequence of files.
+// open is called to create each file, with the index of the file, starting at 0.
+// Every file starts with the trace header, and can be parsed independently of the others.
+func NewRotatingFileSeq(open func(index int) (io.WriteCloser, error), r Rotation) (*FileSeq, error) {
+	w, err := open(0)
+	if err != nil {
+		return nil, err
+	}
+	t := newFileSeq(w, "transport", nil, nil)
+	t.open = open
+	t.rotation = r
+	return t, nil
+}
+
 func newFileSeq(w io.WriteCloser, pers string, odcid *ConnectionID, eventSchemas []string) *FileSeq {
 	now := time.Now()
+	cw := &countingWriteCloser{WriteCloser: w}
+	t := &FileSeq{
+		w:             cw,
+		referenceTime: now,
+		vantagePoint:  pers,
+		odcid:         odcid,
+		eventSchemas:  eventSchemas,
+		enc:           jsontext.NewEncoder(cw),
+		runStopped:    make(chan struct{}),
+		events:        make(chan event, eventChanSize),
+	}
+	t.encodeErr = t.writeHeader()
+	return t
+}
+
+func (t *FileSeq) writeHeader() error {
 	buf := &bytes.Buffer{}
 	enc := jsontext.NewEncoder(buf)
 	if _, err := buf.Write(recordSeparator); err != nil {
 		panic(fmt.Sprintf("qlog encoding into a bytes.Buffer failed: %s", err))
 	}
 	if err := (&traceHeader{
-		VantagePointType: pers,
-		GroupID:          odcid,
-		ReferenceTime:    now,
-		EventSchemas:     eventSchemas,
+		VantagePointType: t.vantagePoint,
+		GroupID:          t.odcid,
+		ReferenceTime:    t.referenceTime,
+		EventSchemas:     t.eventSchemas,
 	}).Encode(enc); err != nil {
 		panic(fmt.Sprintf("qlog encoding into a bytes.Buffer failed: %s", err))
 	}
-	_, encodeErr := w.Write(buf.Bytes())
-
-	return &FileSeq{
-		w:             w,
-		referenceTime: now,
-		enc:           jsontext.NewEncoder(w),
-		runStopped:    make(chan struct{}),
-		encodeErr:     encodeErr,
-		events:        make(chan event, eventChanSize),
-	}
+	_, err := t.w.Write(buf.Bytes())
+	return err
 }
 
 func (t *FileSeq) SupportsSchemas(schema string) bool {
@@ -154,6 +197,13 @@ func (t *FileSeq) Run() {
 		if t.encodeErr != nil { // if encoding failed, just continue draining the event channel
 			continue
 		}
+		if t.shouldRotate(e.Time) {
+			if err := t.rotate(e.Time); err != nil {
+				t.encodeErr = err
+				continue
+			}
+			enc = jsontext.NewEncoder(t.w)
+		}
 		if _, err := t.w.Write(recordSeparator); err != nil {
 			t.encodeErr = err
 			continue
@@ -177,6 +227,35 @@ func (t *FileSeq) Run() {
 	}
 }
 
+func (t *FileSeq) shouldRotate(now time.Time) bool {
+	if t.open == nil {
+		return false
+	}
+	if t.rotation.MaxSize > 0 && t.w.n >= t.rotation.MaxSize {
+		return true
+	}
+	return t.rotation.MaxAge > 0 && now.Sub(t.referenceTime) >= t.rotation.MaxAge
+}
+
+// rotate closes the current file and starts a new one.
+// The reference time of the new file is the time of the first event written to it.
+// The current file is only closed once the new file was opened successfully,
+// so that t.w always points to a file that still needs to be closed.
+func (t *FileSeq) rotate(now time.Time) error {
+	w, err := t.open(t.fileIndex + 1)
+	if err != nil {
+		return err
+	}
+	t.fileIndex++
+	old := t.w
+	t.w = &countingWriteCloser{WriteCloser: w}
+	t.referenceTime = now
+	if err := old.Close(); err != nil {
+		return err
+	}
+	return t.writeHeader()
+}
+
 func (t *FileSeq) removeProducer() {
 	t.mx.Lock()
 	defer t.mx.Unlock()
@@ -201,6 +280,17 @@ func (t *FileSeq) close() {
 	}
 }
 
+type countingWriteCloser struct {
+	io.WriteCloser
+	n int64
+}
+
+func (w *countingWriteCloser) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
 type Writer struct {
 	t *FileSeq
 }
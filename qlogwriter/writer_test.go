@@ -2,6 +2,7 @@ package qlogwriter
 
 import (
 	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/quic-go/quic-go/internal/synctest"
 	"github.com/quic-go/quic-go/qlogwriter/jsontext"
 
 	"github.com/stretchr/testify/require"
@@ -70,3 +72,166 @@ func TestWritingStopping(t *testing.T) {
 	writer.RecordEvent(testEvent{message: "foobar"})
 	require.Empty(t, logBuf.String())
 }
+
+type rotatedFile struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (f *rotatedFile) Close() error { f.closed = true; return nil }
+
+// parseRotatedFile returns the times (in ms) and messages of the events recorded in a file
+func parseRotatedFile(t *testing.T, f *rotatedFile) ([]float64, []string) {
+	t.Helper()
+	records := bytes.Split(f.Bytes(), recordSeparator)[1:]
+	require.NotEmpty(t, records)
+	var hdr map[string]any
+	require.NoError(t, unmarshal(records[0], &hdr))
+	require.Contains(t, hdr, "trace")
+
+	var times []float64
+	var messages []string
+	for _, record := range records[1:] {
+		var ev struct {
+			Time float64 `json:"time"`
+			Data struct {
+				Message string `json:"message"`
+			} `json:"data"`
+		}
+		require.NoError(t, unmarshal(record, &ev))
+		times = append(times, ev.Time)
+		messages = append(messages, ev.Data.Message)
+	}
+	return times, messages
+}
+
+func TestRotationBySize(t *testing.T) {
+	var files []*rotatedFile
+	fileSeq, err := NewRotatingFileSeq(func(index int) (io.WriteCloser, error) {
+		require.Equal(t, len(files), index)
+		f := &rotatedFile{}
+		files = append(files, f)
+		return f, nil
+	}, Rotation{MaxSize: 700})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	headerSize := files[0].Len()
+	require.Less(t, headerSize, 700)
+
+	go fileSeq.Run()
+	producer := fileSeq.AddProducer()
+	for i := range 10 {
+		// each event is roughly 100 bytes
+		producer.RecordEvent(testEvent{message: fmt.Sprintf("%060d", i)})
+	}
+	producer.Close()
+
+	var messages []string
+	for i, f := range files {
+		require.True(t, f.closed)
+		_, msgs := parseRotatedFile(t, f)
+		require.NotEmpty(t, msgs)
+		if i < len(files)-1 {
+			require.GreaterOrEqual(t, f.Len(), 700)
+		}
+		messages = append(messages, msgs...)
+	}
+	require.Greater(t, len(files), 1)
+	require.Len(t, messages, 10)
+	for i, msg := range messages {
+		require.Equal(t, fmt.Sprintf("%060d", i), msg)
+	}
+}
+
+func TestRotationByAge(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var files []*rotatedFile
+		fileSeq, err := NewRotatingFileSeq(func(int) (io.WriteCloser, error) {
+			f := &rotatedFile{}
+			files = append(files, f)
+			return f, nil
+		}, Rotation{MaxAge: 10 * time.Second})
+		require.NoError(t, err)
+
+		go fileSeq.Run()
+		producer := fileSeq.AddProducer()
+		for i := range 6 {
+			time.Sleep(4 * time.Second)
+			producer.RecordEvent(testEvent{message: fmt.Sprintf("event %d", i)})
+			synctest.Wait()
+		}
+		producer.Close()
+
+		require.Len(t, files, 3)
+		times, messages := parseRotatedFile(t, files[0])
+		require.Equal(t, []float64{4000, 8000}, times)
+		require.Equal(t, []string{"event 0", "event 1"}, messages)
+		// the reference time of a new file is the time of the first event
+		times, messages = parseRotatedFile(t, files[1])
+		require.Equal(t, []float64{0, 4000, 8000}, times)
+		require.Equal(t, []string{"event 2", "event 3", "event 4"}, messages)
+		times, messages = parseRotatedFile(t, files[2])
+		require.Equal(t, []float64{0}, times)
+		require.Equal(t, []string{"event 5"}, messages)
+	})
+}
+
+func TestRotationOpenError(t *testing.T) {
+	_, err := NewRotatingFileSeq(func(int) (io.WriteCloser, error) {
+		return nil, errors.New("open failed")
+	}, Rotation{MaxSize: 100})
+	require.EqualError(t, err, "open failed")
+
+	var numOpened int
+	f := &closeCountingFile{}
+	fileSeq, err := NewRotatingFileSeq(func(int) (io.WriteCloser, error) {
+		numOpened++
+		if numOpened > 1 {
+			return nil, errors.New("open failed")
+		}
+		return f, nil
+	}, Rotation{MaxSize: 1})
+	require.NoError(t, err)
+	go fileSeq.Run()
+	producer := fileSeq.AddProducer()
+	producer.RecordEvent(testEvent{message: "foo"})
+	producer.RecordEvent(testEvent{message: "bar"})
+
+	var logBuf bytes.Buffer
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(os.Stdout)
+	producer.Close()
+	require.Contains(t, logBuf.String(), "open failed")
+	require.Equal(t, 2, numOpened)
+	// the first file is closed exactly once
+	require.Equal(t, 1, f.numClosed)
+}
+
+type closeCountingFile struct {
+	rotatedFile
+	numClosed int
+}
+
+func (f *closeCountingFile) Close() error { f.numClosed++; return f.rotatedFile.Close() }
+
+func TestCompression(t *testing.T) {
+	buf := &bytes.Buffer{}
+	f := &rotatedFile{}
+	w, err := NewCompressedWriter(f, Gzip{Level: gzip.BestCompression})
+	require.NoError(t, err)
+	fileSeq := NewFileSeq(w)
+	go fileSeq.Run()
+	producer := fileSeq.AddProducer()
+	producer.RecordEvent(testEvent{message: "foobar"})
+	producer.Close()
+	require.True(t, f.closed)
+	require.Equal(t, ".gz", Gzip{}.FileExtension())
+
+	r, err := gzip.NewReader(&f.Buffer)
+	require.NoError(t, err)
+	_, err = io.Copy(buf, r)
+	require.NoError(t, err)
+	records := bytes.Split(buf.Bytes(), recordSeparator)[1:]
+	require.Len(t, records, 2)
+	require.Contains(t, string(records[1]), "foobar")
+}
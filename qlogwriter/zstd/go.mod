@@ -0,0 +1,19 @@
+module github.com/quic-go/quic-go/qlogwriter/zstd
+
+go 1.24
+
+// The version doesn't matter here, as we're replacing it with the currently checked out code anyway.
+require (
+	github.com/klauspost/compress v1.18.0
+	github.com/quic-go/quic-go v0.54.0
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/quic-go/quic-go => ../../
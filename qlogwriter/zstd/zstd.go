@@ -0,0 +1,31 @@
+// Package zstd implements zstd compression for qlog files.
+//
+// It lives in a separate module, so that quic-go itself doesn't depend on github.com/klauspost/compress.
+package zstd
+
+import (
+	"io"
+
+	"github.com/quic-go/quic-go/qlogwriter"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the zstd qlogwriter.Compression.
+type Compression struct {
+	// Level is the zstd compression level, between 1 and 22.
+	// If zero, the default level of github.com/klauspost/compress/zstd is used.
+	Level int
+}
+
+var _ qlogwriter.Compression = Compression{}
+
+func (c Compression) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	level := zstd.SpeedDefault
+	if c.Level != 0 {
+		level = zstd.EncoderLevelFromZstd(c.Level)
+	}
+	return zstd.NewWriter(w, zstd.WithEncoderLevel(level))
+}
+
+func (Compression) FileExtension() string { return ".zst" }
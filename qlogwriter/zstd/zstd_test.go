@@ -0,0 +1,49 @@
+package zstd
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/qlogwriter"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufferCloser) Close() error { b.closed = true; return nil }
+
+func TestCompression(t *testing.T) {
+	t.Run("default level", func(t *testing.T) {
+		testCompression(t, Compression{})
+	})
+	t.Run("best compression", func(t *testing.T) {
+		testCompression(t, Compression{Level: 22})
+	})
+}
+
+func testCompression(t *testing.T, c Compression) {
+	require.Equal(t, ".zst", c.FileExtension())
+
+	f := &bufferCloser{}
+	w, err := qlogwriter.NewCompressedWriter(f, c)
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte("foobar"), 1000)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.True(t, f.closed)
+	require.Less(t, f.Len(), len(data))
+
+	r, err := zstd.NewReader(&f.Buffer)
+	require.NoError(t, err)
+	defer r.Close()
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+}
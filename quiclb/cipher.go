@@ -0,0 +1,120 @@
+package quiclb
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+)
+
+// The cidCipher encrypts and decrypts the server ID and nonce portion of a connection ID.
+// If the plaintext is exactly one AES block long, it is encrypted using a single AES-ECB operation.
+// Otherwise, a four-pass Feistel network using AES-ECB as the round function is used.
+type cidCipher struct {
+	block        cipher.Block
+	plaintextLen int
+}
+
+func newCIDCipher(key []byte, plaintextLen int) (*cidCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &cidCipher{block: block, plaintextLen: plaintextLen}, nil
+}
+
+func (c *cidCipher) isSinglePass() bool { return c.plaintextLen == aes.BlockSize }
+
+func (c *cidCipher) halfLen() int { return (c.plaintextLen + 1) / 2 }
+
+func (c *cidCipher) isOdd() bool { return c.plaintextLen%2 == 1 }
+
+// Encrypt encrypts src into dst. Both must be plaintextLen bytes long.
+func (c *cidCipher) Encrypt(dst, src []byte) {
+	if c.isSinglePass() {
+		c.block.Encrypt(dst, src)
+		return
+	}
+	left, right := c.split(src)
+	right = c.xorRight(right, left, 1)
+	left = c.xorLeft(left, right, 2)
+	right = c.xorRight(right, left, 3)
+	left = c.xorLeft(left, right, 4)
+	c.join(dst, left, right)
+}
+
+// Decrypt decrypts src into dst. Both must be plaintextLen bytes long.
+func (c *cidCipher) Decrypt(dst, src []byte) {
+	if c.isSinglePass() {
+		c.block.Decrypt(dst, src)
+		return
+	}
+	left, right := c.split(src)
+	left = c.xorLeft(left, right, 4)
+	right = c.xorRight(right, left, 3)
+	left = c.xorLeft(left, right, 2)
+	right = c.xorRight(right, left, 1)
+	c.join(dst, left, right)
+}
+
+// split splits the input into two halves of halfLen bytes.
+// If the input has an odd length, the middle byte is split:
+// its 4 most significant bits belong to the left half, the 4 least significant bits to the right half.
+func (c *cidCipher) split(b []byte) (left, right []byte) {
+	halfLen := c.halfLen()
+	left = make([]byte, halfLen)
+	right = make([]byte, halfLen)
+	copy(left, b[:halfLen])
+	copy(right, b[len(b)-halfLen:])
+	if c.isOdd() {
+		left[halfLen-1] &= 0xf0
+		right[0] &= 0x0f
+	}
+	return left, right
+}
+
+func (c *cidCipher) join(dst, left, right []byte) {
+	halfLen := c.halfLen()
+	copy(dst[len(dst)-halfLen:], right)
+	if c.isOdd() {
+		copy(dst, left[:halfLen-1])
+		dst[halfLen-1] = left[halfLen-1] | right[0]
+		return
+	}
+	copy(dst, left)
+}
+
+// roundFunction encrypts the expanded half using AES-ECB.
+// The half is expanded to a full AES block: half || 0x00... || plaintextLen || pass.
+func (c *cidCipher) roundFunction(half []byte, pass byte) [aes.BlockSize]byte {
+	var block [aes.BlockSize]byte
+	copy(block[:], half)
+	block[aes.BlockSize-2] = byte(c.plaintextLen)
+	block[aes.BlockSize-1] = pass
+	c.block.Encrypt(block[:], block[:])
+	return block
+}
+
+// xorLeft returns left XOR truncate(AES(expand(right, pass))).
+func (c *cidCipher) xorLeft(left, right []byte, pass byte) []byte {
+	block := c.roundFunction(right, pass)
+	out := make([]byte, len(left))
+	for i := range out {
+		out[i] = left[i] ^ block[i]
+	}
+	if c.isOdd() {
+		out[len(out)-1] &= 0xf0
+	}
+	return out
+}
+
+// xorRight returns right XOR truncate(AES(expand(left, pass))).
+func (c *cidCipher) xorRight(right, left []byte, pass byte) []byte {
+	block := c.roundFunction(left, pass)
+	out := make([]byte, len(right))
+	for i := range out {
+		out[i] = right[i] ^ block[i]
+	}
+	if c.isOdd() {
+		out[0] &= 0x0f
+	}
+	return out
+}
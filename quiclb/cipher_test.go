@@ -0,0 +1,66 @@
+package quiclb
+
+import (
+	"crypto/aes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipherTestVector(t *testing.T) {
+	// test vector from draft-ietf-quic-load-balancers, Appendix B
+	key, err := hex.DecodeString("fdf726a9893ec05c0632d3956680baf0")
+	require.NoError(t, err)
+	c, err := newCIDCipher(key, 7)
+	require.NoError(t, err)
+	plaintext, err := hex.DecodeString("31441a" + "9c69c275")
+	require.NoError(t, err)
+	ciphertext := make([]byte, 7)
+	c.Encrypt(ciphertext, plaintext)
+	require.Equal(t, "67947d29be054a", hex.EncodeToString(ciphertext))
+
+	decrypted := make([]byte, 7)
+	c.Decrypt(decrypted, ciphertext)
+	require.Equal(t, plaintext, decrypted)
+}
+
+func TestCipherSinglePass(t *testing.T) {
+	key := make([]byte, 16)
+	rand.Read(key)
+	c, err := newCIDCipher(key, 16)
+	require.NoError(t, err)
+	plaintext := make([]byte, 16)
+	rand.Read(plaintext)
+	ciphertext := make([]byte, 16)
+	c.Encrypt(ciphertext, plaintext)
+
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	expected := make([]byte, 16)
+	block.Encrypt(expected, plaintext)
+	require.Equal(t, expected, ciphertext)
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	key := make([]byte, 16)
+	rand.Read(key)
+	for l := minServerIDLen + minNonceLen; l <= maxServerIDAndNonceLen; l++ {
+		t.Run(fmt.Sprintf("length %d", l), func(t *testing.T) {
+			c, err := newCIDCipher(key, l)
+			require.NoError(t, err)
+			for range 100 {
+				plaintext := make([]byte, l)
+				rand.Read(plaintext)
+				ciphertext := make([]byte, l)
+				c.Encrypt(ciphertext, plaintext)
+				require.NotEqual(t, plaintext, ciphertext)
+				decrypted := make([]byte, l)
+				c.Decrypt(decrypted, ciphertext)
+				require.Equal(t, plaintext, decrypted)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Package quiclb implements QUIC-LB connection ID encoding, as specified in
+// https://datatracker.ietf.org/doc/html/draft-ietf-quic-load-balancers-21.
+//
+// Servers use a ConnectionIDGenerator as the quic.Transport.ConnectionIDGenerator to encode
+// their server ID into every connection ID they issue. Load balancers use a Decoder to
+// extract the server ID from incoming packets, allowing them to route packets to the correct
+// server, even after a client migrated to a new path or switched to a new connection ID.
+//
+// Three encoding modes are supported:
+//   - plaintext: the server ID is encoded in the clear,
+//   - single-pass encryption: if the server ID and the nonce together are 16 bytes long,
+//     they are encrypted using a single AES-128-ECB operation,
+//   - four-pass encryption: for all other lengths, a four-pass Feistel network based on AES-128-ECB is used.
+package quiclb
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxConfigID is the largest config ID that can be used.
+	// The config ID 0b111 is reserved for unroutable connection IDs.
+	MaxConfigID = 6
+	// unroutableConfigID is used by connection IDs that don't encode a server ID.
+	unroutableConfigID = 7
+
+	minServerIDLen = 1
+	maxServerIDLen = 15
+	minNonceLen    = 4
+	maxNonceLen    = 18
+	// The first octet of the connection ID is not part of the server ID and nonce.
+	maxServerIDAndNonceLen = 19
+
+	keyLen = 16
+)
+
+// ErrUnknownConfig is returned by the Decoder when a connection ID uses a config ID that
+// the Decoder doesn't have a configuration for, or the config ID reserved for unroutable connection IDs.
+var ErrUnknownConfig = errors.New("quiclb: unknown config ID")
+
+// A Config is a QUIC-LB configuration.
+// It needs to be shared between the load balancer and all servers behind it.
+type Config struct {
+	// ConfigID is the config rotation codepoint, encoded in the 3 most significant bits of
+	// the first octet of the connection ID.
+	// It allows load balancers to use multiple configurations at the same time, e.g. during key rotation.
+	// Valid values are 0 to MaxConfigID.
+	ConfigID uint8
+	// ServerIDLen is the length of the server ID, between 1 and 15 bytes.
+	ServerIDLen int
+	// NonceLen is the length of the nonce, between 4 and 18 bytes.
+	// ServerIDLen and NonceLen combined must not exceed 19 bytes.
+	NonceLen int
+	// Key is the 16 byte AES-128 key used to encrypt the server ID and the nonce.
+	// If it is not set, connection IDs are generated in plaintext mode,
+	// and the server ID can be read by any on-path observer.
+	Key []byte
+	// LengthSelfEncoding encodes the length of the connection ID into the 5 least significant
+	// bits of the first octet. Otherwise, these bits are chosen randomly.
+	LengthSelfEncoding bool
+}
+
+// ConnectionIDLen returns the length of connection IDs using this configuration.
+func (c *Config) ConnectionIDLen() int {
+	return 1 + c.ServerIDLen + c.NonceLen
+}
+
+func (c *Config) validate() error {
+	if c.ConfigID > MaxConfigID {
+		return fmt.Errorf("quiclb: invalid config ID %d (maximum %d)", c.ConfigID, MaxConfigID)
+	}
+	if c.ServerIDLen < minServerIDLen || c.ServerIDLen > maxServerIDLen {
+		return fmt.Errorf("quiclb: invalid server ID length %d (must be between %d and %d)", c.ServerIDLen, minServerIDLen, maxServerIDLen)
+	}
+	if c.NonceLen < minNonceLen || c.NonceLen > maxNonceLen {
+		return fmt.Errorf("quiclb: invalid nonce length %d (must be between %d and %d)", c.NonceLen, minNonceLen, maxNonceLen)
+	}
+	if c.ServerIDLen+c.NonceLen > maxServerIDAndNonceLen {
+		return fmt.Errorf("quiclb: server ID and nonce too long (%d bytes, maximum %d)", c.ServerIDLen+c.NonceLen, maxServerIDAndNonceLen)
+	}
+	if c.Key != nil && len(c.Key) != keyLen {
+		return fmt.Errorf("quiclb: invalid key length %d (must be %d)", len(c.Key), keyLen)
+	}
+	return nil
+}
+
+// firstOctet returns the first octet of the connection ID.
+// random is used for the 5 least significant bits if length self-encoding is not used.
+func (c *Config) firstOctet(random byte) byte {
+	b := c.ConfigID << 5
+	if c.LengthSelfEncoding {
+		return b | byte(c.ConnectionIDLen()-1)
+	}
+	return b | (random & 0x1f)
+}
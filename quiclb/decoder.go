@@ -0,0 +1,108 @@
+package quiclb
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/internal/wire"
+)
+
+type decoderConfig struct {
+	config Config
+	cipher *cidCipher // nil in plaintext mode
+}
+
+// A Decoder extracts the server ID from QUIC-LB connection IDs.
+// It is intended to be used by load balancers.
+// It is safe for concurrent use.
+type Decoder struct {
+	configs [MaxConfigID + 1]*decoderConfig
+}
+
+// NewDecoder creates a new Decoder.
+// It can decode connection IDs using any of the given configs.
+// Every config must use a different config ID.
+func NewDecoder(configs ...*Config) (*Decoder, error) {
+	d := &Decoder{}
+	for _, conf := range configs {
+		if err := conf.validate(); err != nil {
+			return nil, err
+		}
+		if d.configs[conf.ConfigID] != nil {
+			return nil, fmt.Errorf("quiclb: duplicate config ID %d", conf.ConfigID)
+		}
+		dc := &decoderConfig{config: *conf}
+		if conf.Key != nil {
+			c, err := newCIDCipher(conf.Key, conf.ServerIDLen+conf.NonceLen)
+			if err != nil {
+				return nil, err
+			}
+			dc.cipher = c
+		}
+		d.configs[conf.ConfigID] = dc
+	}
+	return d, nil
+}
+
+// ServerID returns the server ID encoded in the connection ID.
+// If the connection ID uses a config ID that the decoder doesn't know about, ErrUnknownConfig is returned.
+// In that case, the load balancer should fall back to routing the packet using a different mechanism,
+// for example using a hash of the 4-tuple.
+func (d *Decoder) ServerID(connID quic.ConnectionID) ([]byte, error) {
+	if connID.Len() == 0 {
+		return nil, ErrUnknownConfig
+	}
+	b := connID.Bytes()
+	conf, err := d.configFor(b[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < conf.config.ConnectionIDLen() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	plaintext := make([]byte, conf.config.ServerIDLen+conf.config.NonceLen)
+	if conf.cipher == nil {
+		copy(plaintext, b[1:])
+	} else {
+		conf.cipher.Decrypt(plaintext, b[1:1+len(plaintext)])
+	}
+	return plaintext[:conf.config.ServerIDLen], nil
+}
+
+// ServerIDFromPacket returns the server ID encoded in the Destination Connection ID of a QUIC packet.
+// Both long and short header packets are supported.
+// For short header packets, the length of the connection ID is derived from the config ID.
+func (d *Decoder) ServerIDFromPacket(packet []byte) ([]byte, error) {
+	if len(packet) == 0 {
+		return nil, io.EOF
+	}
+	var shortHeaderConnIDLen int
+	if !wire.IsLongHeaderPacket(packet[0]) {
+		if len(packet) < 2 {
+			return nil, io.EOF
+		}
+		conf, err := d.configFor(packet[1])
+		if err != nil {
+			return nil, err
+		}
+		shortHeaderConnIDLen = conf.config.ConnectionIDLen()
+	}
+	connID, err := wire.ParseConnectionID(packet, shortHeaderConnIDLen)
+	if err != nil {
+		return nil, err
+	}
+	return d.ServerID(connID)
+}
+
+func (d *Decoder) configFor(firstOctet byte) (*decoderConfig, error) {
+	configID := firstOctet >> 5
+	if configID == unroutableConfigID {
+		return nil, ErrUnknownConfig
+	}
+	conf := d.configs[configID]
+	if conf == nil {
+		return nil, ErrUnknownConfig
+	}
+	return conf, nil
+}
@@ -0,0 +1,142 @@
+package quiclb
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoderRoundTrip(t *testing.T) {
+	key := make([]byte, 16)
+	rand.Read(key)
+
+	for _, encrypted := range []bool{false, true} {
+		for serverIDLen := minServerIDLen; serverIDLen <= maxServerIDLen; serverIDLen++ {
+			for nonceLen := minNonceLen; serverIDLen+nonceLen <= maxServerIDAndNonceLen; nonceLen++ {
+				t.Run(fmt.Sprintf("encrypted: %t, server ID: %d, nonce: %d", encrypted, serverIDLen, nonceLen), func(t *testing.T) {
+					conf := &Config{ConfigID: 3, ServerIDLen: serverIDLen, NonceLen: nonceLen}
+					if encrypted {
+						conf.Key = key
+					}
+					serverID := make([]byte, serverIDLen)
+					rand.Read(serverID)
+					g, err := NewConnectionIDGenerator(conf, serverID)
+					require.NoError(t, err)
+					d, err := NewDecoder(conf)
+					require.NoError(t, err)
+
+					for range 10 {
+						connID, err := g.GenerateConnectionID()
+						require.NoError(t, err)
+						decoded, err := d.ServerID(connID)
+						require.NoError(t, err)
+						require.Equal(t, serverID, decoded)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestDecoderConfigRotation(t *testing.T) {
+	oldKey := make([]byte, 16)
+	rand.Read(oldKey)
+	newKey := make([]byte, 16)
+	rand.Read(newKey)
+	oldConf := &Config{ConfigID: 0, ServerIDLen: 4, NonceLen: 8, Key: oldKey}
+	newConf := &Config{ConfigID: 1, ServerIDLen: 2, NonceLen: 6, Key: newKey}
+
+	oldGen, err := NewConnectionIDGenerator(oldConf, []byte{1, 2, 3, 4})
+	require.NoError(t, err)
+	newGen, err := NewConnectionIDGenerator(newConf, []byte{5, 6})
+	require.NoError(t, err)
+
+	d, err := NewDecoder(oldConf, newConf)
+	require.NoError(t, err)
+	oldConnID, err := oldGen.GenerateConnectionID()
+	require.NoError(t, err)
+	newConnID, err := newGen.GenerateConnectionID()
+	require.NoError(t, err)
+
+	serverID, err := d.ServerID(oldConnID)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3, 4}, serverID)
+	serverID, err = d.ServerID(newConnID)
+	require.NoError(t, err)
+	require.Equal(t, []byte{5, 6}, serverID)
+
+	// after the old config was retired, its connection IDs can't be decoded anymore
+	d, err = NewDecoder(newConf)
+	require.NoError(t, err)
+	_, err = d.ServerID(oldConnID)
+	require.ErrorIs(t, err, ErrUnknownConfig)
+
+	_, err = NewDecoder(oldConf, &Config{ConfigID: 0, ServerIDLen: 1, NonceLen: 4})
+	require.ErrorContains(t, err, "duplicate config ID 0")
+}
+
+func TestDecoderInvalidConnectionIDs(t *testing.T) {
+	d, err := NewDecoder(&Config{ConfigID: 0, ServerIDLen: 4, NonceLen: 8})
+	require.NoError(t, err)
+
+	_, err = d.ServerID(quic.ConnectionID{})
+	require.ErrorIs(t, err, ErrUnknownConfig)
+	// unroutable connection ID
+	_, err = d.ServerID(quic.ConnectionIDFromBytes([]byte{0xe0, 1, 2, 3, 4, 5, 6, 7, 8}))
+	require.ErrorIs(t, err, ErrUnknownConfig)
+	// too short
+	_, err = d.ServerID(quic.ConnectionIDFromBytes([]byte{0x00, 1, 2, 3, 4, 5, 6, 7}))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestDecoderPackets(t *testing.T) {
+	key := make([]byte, 16)
+	rand.Read(key)
+	conf := &Config{ConfigID: 4, ServerIDLen: 5, NonceLen: 7, Key: key}
+	serverID := []byte{1, 2, 3, 4, 5}
+	g, err := NewConnectionIDGenerator(conf, serverID)
+	require.NoError(t, err)
+	d, err := NewDecoder(conf)
+	require.NoError(t, err)
+	connID, err := g.GenerateConnectionID()
+	require.NoError(t, err)
+
+	t.Run("short header", func(t *testing.T) {
+		packet := append([]byte{0x40}, connID.Bytes()...)
+		packet = append(packet, []byte("foobar")...)
+		decoded, err := d.ServerIDFromPacket(packet)
+		require.NoError(t, err)
+		require.Equal(t, serverID, decoded)
+	})
+
+	t.Run("long header", func(t *testing.T) {
+		packet := []byte{0xc0, 0, 0, 0, 1, byte(connID.Len())}
+		packet = append(packet, connID.Bytes()...)
+		packet = append(packet, 4, 0xde, 0xad, 0xbe, 0xef)
+		packet = append(packet, []byte("foobar")...)
+		decoded, err := d.ServerIDFromPacket(packet)
+		require.NoError(t, err)
+		require.Equal(t, serverID, decoded)
+	})
+
+	t.Run("client-chosen connection ID", func(t *testing.T) {
+		// the first Initial uses a connection ID chosen by the client
+		packet := []byte{0xc0, 0, 0, 0, 1, 8, 0xff, 2, 3, 4, 5, 6, 7, 8, 0}
+		_, err := d.ServerIDFromPacket(packet)
+		require.ErrorIs(t, err, ErrUnknownConfig)
+	})
+
+	t.Run("truncated packets", func(t *testing.T) {
+		_, err := d.ServerIDFromPacket(nil)
+		require.ErrorIs(t, err, io.EOF)
+		_, err = d.ServerIDFromPacket([]byte{0x40})
+		require.ErrorIs(t, err, io.EOF)
+		_, err = d.ServerIDFromPacket(append([]byte{0x40}, connID.Bytes()[:5]...))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,90 @@
+package quiclb
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+
+	"github.com/quic-go/quic-go"
+)
+
+// A ConnectionIDGenerator generates QUIC-LB connection IDs that encode the server ID.
+// It can be used as the quic.Transport.ConnectionIDGenerator.
+type ConnectionIDGenerator struct {
+	config   Config
+	serverID []byte
+	cipher   *cidCipher // nil in plaintext mode
+
+	mx sync.Mutex
+	// In the encrypted modes, the nonce is a counter, initialized to a random value.
+	// This guarantees that connection IDs are unique, while the encryption makes sure
+	// that connection IDs can't be linked by an observer.
+	nonce []byte
+}
+
+var _ quic.ConnectionIDGenerator = &ConnectionIDGenerator{}
+
+// NewConnectionIDGenerator creates a new ConnectionIDGenerator for the given server ID.
+// The length of the server ID must match the ServerIDLen of the config.
+func NewConnectionIDGenerator(conf *Config, serverID []byte) (*ConnectionIDGenerator, error) {
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	if len(serverID) != conf.ServerIDLen {
+		return nil, fmt.Errorf("quiclb: invalid server ID length %d (config requires %d)", len(serverID), conf.ServerIDLen)
+	}
+	g := &ConnectionIDGenerator{
+		config:   *conf,
+		serverID: append([]byte(nil), serverID...),
+	}
+	if conf.Key != nil {
+		c, err := newCIDCipher(conf.Key, conf.ServerIDLen+conf.NonceLen)
+		if err != nil {
+			return nil, err
+		}
+		g.cipher = c
+		g.nonce = make([]byte, conf.NonceLen)
+		if _, err := rand.Read(g.nonce); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// GenerateConnectionID generates a new connection ID.
+func (g *ConnectionIDGenerator) GenerateConnectionID() (quic.ConnectionID, error) {
+	b := make([]byte, g.config.ConnectionIDLen())
+	if _, err := rand.Read(b[:1]); err != nil {
+		return quic.ConnectionID{}, err
+	}
+	b[0] = g.config.firstOctet(b[0])
+	copy(b[1:], g.serverID)
+	nonce := b[1+g.config.ServerIDLen:]
+	if g.cipher == nil {
+		if _, err := rand.Read(nonce); err != nil {
+			return quic.ConnectionID{}, err
+		}
+		return quic.ConnectionIDFromBytes(b), nil
+	}
+	g.mx.Lock()
+	incrementNonce(g.nonce)
+	copy(nonce, g.nonce)
+	g.mx.Unlock()
+	g.cipher.Encrypt(b[1:], b[1:])
+	return quic.ConnectionIDFromBytes(b), nil
+}
+
+// ConnectionIDLen returns the length of the generated connection IDs.
+func (g *ConnectionIDGenerator) ConnectionIDLen() int {
+	return g.config.ConnectionIDLen()
+}
+
+// incrementNonce increments the big-endian nonce by one, wrapping around on overflow.
+func incrementNonce(nonce []byte) {
+	for i := len(nonce) - 1; i >= 0; i-- {
+		nonce[i]++
+		if nonce[i] != 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package quiclb
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/quic-go/quic-go"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		conf Config
+		err  string
+	}{
+		{name: "unroutable config ID", conf: Config{ConfigID: 7, ServerIDLen: 4, NonceLen: 8}, err: "invalid config ID 7"},
+		{name: "server ID too short", conf: Config{ServerIDLen: 0, NonceLen: 8}, err: "invalid server ID length 0"},
+		{name: "server ID too long", conf: Config{ServerIDLen: 16, NonceLen: 4}, err: "invalid server ID length 16"},
+		{name: "nonce too short", conf: Config{ServerIDLen: 4, NonceLen: 3}, err: "invalid nonce length 3"},
+		{name: "connection ID too long", conf: Config{ServerIDLen: 10, NonceLen: 10}, err: "server ID and nonce too long"},
+		{name: "invalid key", conf: Config{ServerIDLen: 4, NonceLen: 8, Key: make([]byte, 32)}, err: "invalid key length 32"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewConnectionIDGenerator(&tc.conf, make([]byte, tc.conf.ServerIDLen))
+			require.ErrorContains(t, err, tc.err)
+			_, err = NewDecoder(&tc.conf)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	_, err := NewConnectionIDGenerator(&Config{ServerIDLen: 4, NonceLen: 8}, []byte{1, 2, 3})
+	require.ErrorContains(t, err, "invalid server ID length 3 (config requires 4)")
+}
+
+func TestGeneratorPlaintext(t *testing.T) {
+	serverID := []byte{0xde, 0xad, 0xbe, 0xef}
+	g, err := NewConnectionIDGenerator(&Config{ConfigID: 2, ServerIDLen: 4, NonceLen: 6, LengthSelfEncoding: true}, serverID)
+	require.NoError(t, err)
+	require.Equal(t, 11, g.ConnectionIDLen())
+
+	connID, err := g.GenerateConnectionID()
+	require.NoError(t, err)
+	require.Equal(t, 11, connID.Len())
+	b := connID.Bytes()
+	require.Equal(t, byte(2<<5|10), b[0])
+	require.Equal(t, serverID, b[1:5])
+
+	connID2, err := g.GenerateConnectionID()
+	require.NoError(t, err)
+	require.NotEqual(t, connID, connID2)
+}
+
+func TestGeneratorFirstOctet(t *testing.T) {
+	g, err := NewConnectionIDGenerator(&Config{ConfigID: 5, ServerIDLen: 2, NonceLen: 4}, []byte{1, 2})
+	require.NoError(t, err)
+	lengthBits := make(map[byte]struct{})
+	for range 200 {
+		connID, err := g.GenerateConnectionID()
+		require.NoError(t, err)
+		require.Equal(t, byte(5), connID.Bytes()[0]>>5)
+		lengthBits[connID.Bytes()[0]&0x1f] = struct{}{}
+	}
+	// without length self-encoding, the low bits are random
+	require.Greater(t, len(lengthBits), 1)
+}
+
+func TestGeneratorEncryptedUniqueness(t *testing.T) {
+	key := make([]byte, 16)
+	rand.Read(key)
+	serverID := []byte{1, 2, 3}
+	g, err := NewConnectionIDGenerator(&Config{ServerIDLen: 3, NonceLen: 4, Key: key}, serverID)
+	require.NoError(t, err)
+
+	seen := make(map[quic.ConnectionID]struct{})
+	for range 1000 {
+		connID, err := g.GenerateConnectionID()
+		require.NoError(t, err)
+		require.NotEqual(t, serverID, connID.Bytes()[1:4])
+		_, ok := seen[connID]
+		require.False(t, ok, "duplicate connection ID")
+		seen[connID] = struct{}{}
+	}
+}
+
+func TestIncrementNonce(t *testing.T) {
+	nonce := []byte{0x00, 0xfe, 0xff}
+	incrementNonce(nonce)
+	require.Equal(t, []byte{0x00, 0xff, 0x00}, nonce)
+	nonce = []byte{0xff, 0xff}
+	incrementNonce(nonce)
+	require.Equal(t, []byte{0x00, 0x00}, nonce)
+}
@@ -50,6 +50,22 @@ type ReceiveStream struct {
 	deadline monotime.Time
 
 	flowController flowcontrol.StreamFlowController
+
+	creationTime  monotime.Time
+	eofReadTime   monotime.Time
+	bytesReceived protocol.ByteCount
+}
+
+// ReceiveStreamStats contains statistics about a ReceiveStream.
+type ReceiveStreamStats struct {
+	// BytesReceived is the number of bytes of stream data received,
+	// including data that was received more than once.
+	BytesReceived uint64
+	// BytesRead is the number of bytes read from the stream by the application.
+	BytesRead uint64
+	// TimeToEOF is the time from opening the stream until the application read the io.EOF.
+	// It is 0 if this hasn't happened (yet).
+	TimeToEOF time.Duration
 }
 
 var (
@@ -70,6 +86,7 @@ func newReceiveStream(
 		readChan:       make(chan struct{}, 1),
 		readOnce:       make(chan struct{}, 1),
 		finalOffset:    protocol.MaxByteCount,
+		creationTime:   monotime.Now(),
 	}
 }
 
@@ -129,6 +146,7 @@ func (s *ReceiveStream) isNewlyCompleted() bool {
 func (s *ReceiveStream) readImpl(p []byte) (hasStreamWindowUpdate bool, hasConnWindowUpdate bool, _ int, _ error) {
 	if s.currentFrameIsLast && s.currentFrame == nil {
 		s.errorRead = true
+		s.markEOFRead()
 		return false, false, 0, io.EOF
 	}
 	if s.cancelledLocally || (s.cancelledRemotely && s.readPos >= s.reliableSize) {
@@ -226,6 +244,7 @@ func (s *ReceiveStream) readImpl(p []byte) (hasStreamWindowUpdate bool, hasConnW
 				s.currentFrameDone()
 			}
 			s.errorRead = true
+			s.markEOFRead()
 			return hasStreamWindowUpdate, hasConnWindowUpdate, bytesRead, io.EOF
 		}
 	}
@@ -236,6 +255,12 @@ func (s *ReceiveStream) readImpl(p []byte) (hasStreamWindowUpdate bool, hasConnW
 	return hasStreamWindowUpdate, hasConnWindowUpdate, bytesRead, nil
 }
 
+func (s *ReceiveStream) markEOFRead() {
+	if s.eofReadTime.IsZero() {
+		s.eofReadTime = monotime.Now()
+	}
+}
+
 func (s *ReceiveStream) dequeueNextFrame() {
 	var offset protocol.ByteCount
 	// We're done with the last frame. Release the buffer.
@@ -301,6 +326,7 @@ func (s *ReceiveStream) handleStreamFrameImpl(frame *wire.StreamFrame, now monot
 	if err := s.flowController.UpdateHighestReceived(maxOffset, frame.Fin, now); err != nil {
 		return err
 	}
+	s.bytesReceived += frame.DataLen()
 	if frame.Fin {
 		s.finalOffset = maxOffset
 	}
@@ -381,6 +407,22 @@ func (s *ReceiveStream) getControlFrame(now monotime.Time) (_ ackhandler.Frame,
 	}, true, false
 }
 
+// Stats returns statistics about the stream.
+func (s *ReceiveStream) Stats() ReceiveStreamStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var timeToEOF time.Duration
+	if !s.eofReadTime.IsZero() {
+		timeToEOF = s.eofReadTime.Sub(s.creationTime)
+	}
+	return ReceiveStreamStats{
+		BytesReceived: uint64(s.bytesReceived),
+		BytesRead:     uint64(s.readPos),
+		TimeToEOF:     timeToEOF,
+	}
+}
+
 // SetReadDeadline sets the deadline for future Read calls and
 // any currently-blocked Read call.
 // A zero value for t means Read will not time out.
@@ -330,6 +330,37 @@ func TestReceiveStreamEOFWithData(t *testing.T) {
 	require.ErrorIs(t, err, io.EOF)
 }
 
+func TestReceiveStreamStats(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockFC := mocks.NewMockStreamFlowController(mockCtrl)
+		mockSender := NewMockStreamSender(mockCtrl)
+		str := newReceiveStream(42, mockSender, mockFC)
+
+		now := monotime.Now()
+		mockFC.EXPECT().UpdateHighestReceived(protocol.ByteCount(4), false, now).Times(2)
+		require.NoError(t, str.handleStreamFrame(&wire.StreamFrame{Data: []byte("foob")}, now))
+		// duplicate data is counted as well
+		require.NoError(t, str.handleStreamFrame(&wire.StreamFrame{Data: []byte("foob")}, now))
+		require.Equal(t, ReceiveStreamStats{BytesReceived: 8}, str.Stats())
+
+		mockFC.EXPECT().AddBytesRead(protocol.ByteCount(4))
+		b := make([]byte, 4)
+		_, err := (&readerWithTimeout{Reader: str, Timeout: time.Second}).Read(b)
+		require.NoError(t, err)
+		require.Equal(t, ReceiveStreamStats{BytesReceived: 8, BytesRead: 4}, str.Stats())
+
+		time.Sleep(time.Second)
+		mockFC.EXPECT().UpdateHighestReceived(protocol.ByteCount(6), true, gomock.Any())
+		require.NoError(t, str.handleStreamFrame(&wire.StreamFrame{Offset: 4, Data: []byte("ar"), Fin: true}, monotime.Now()))
+		mockFC.EXPECT().AddBytesRead(protocol.ByteCount(2))
+		mockSender.EXPECT().onStreamCompleted(protocol.StreamID(42))
+		_, err = (&readerWithTimeout{Reader: str, Timeout: time.Second}).Read(b)
+		require.ErrorIs(t, err, io.EOF)
+		require.Equal(t, ReceiveStreamStats{BytesReceived: 10, BytesRead: 6, TimeToEOF: time.Second}, str.Stats())
+	})
+}
+
 func TestReceiveStreamImmediateFINs(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	mockFC := mocks.NewMockStreamFlowController(mockCtrl)
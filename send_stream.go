@@ -38,6 +38,8 @@ type SendStream struct {
 	resetErr               *StreamError
 	queuedResetStreamFrame *wire.ResetStreamFrame
 
+	priority StreamPriority
+
 	supportsResetStreamAt bool
 	finishedWriting       bool // set once Close() is called
 	finSent               bool // set when a STREAM_FRAME with FIN bit has been sent
@@ -55,6 +57,27 @@ type SendStream struct {
 	deadline  monotime.Time
 
 	flowController flowcontrol.StreamFlowController
+
+	creationTime       monotime.Time
+	finAckedTime       monotime.Time
+	bytesRetransmitted protocol.ByteCount
+}
+
+// SendStreamStats contains statistics about a SendStream.
+type SendStreamStats struct {
+	// BytesWritten is the number of bytes written to the stream by the application.
+	BytesWritten uint64
+	// BytesSent is the number of bytes of stream data sent, not including retransmissions.
+	BytesSent uint64
+	// BytesRetransmitted is the number of bytes of stream data that were retransmitted.
+	BytesRetransmitted uint64
+	// FlowControlBlockedTime is the total time the stream was blocked by stream-level flow control.
+	// If the stream is currently blocked, this includes the time it has been blocked for so far.
+	FlowControlBlockedTime time.Duration
+	// TimeToFinAcked is the time from opening the stream until the peer acknowledged
+	// all stream data, including the FIN.
+	// It is 0 if this hasn't happened (yet).
+	TimeToFinAcked time.Duration
 }
 
 var (
@@ -63,6 +86,8 @@ var (
 	_ sendStreamFrameHandler   = &SendStream{}
 )
 
+var defaultStreamPriority = StreamPriority{Urgency: DefaultStreamUrgency, Incremental: true}
+
 func newSendStream(
 	ctx context.Context,
 	streamID protocol.StreamID,
@@ -76,7 +101,9 @@ func newSendStream(
 		flowController:        flowController,
 		writeChan:             make(chan struct{}, 1),
 		writeOnce:             make(chan struct{}, 1), // cap: 1, to protect against concurrent use of Write
+		priority:              defaultStreamPriority,
 		supportsResetStreamAt: supportsResetStreamAt,
+		creationTime:          monotime.Now(),
 	}
 	s.ctx, s.ctxCancel = context.WithCancelCause(ctx)
 	return s
@@ -87,6 +114,31 @@ func (s *SendStream) StreamID() StreamID {
 	return s.streamID // same for receiveStream and sendStream
 }
 
+// SetPriority sets the priority of the stream.
+// The priority determines the order in which data is sent on the streams of a connection,
+// see [StreamPriority] for details.
+// It can be changed at any time, and takes effect for all data that hasn't been sent yet.
+func (s *SendStream) SetPriority(p StreamPriority) {
+	p.Urgency = min(p.Urgency, MaxStreamUrgency)
+	s.mutex.Lock()
+	changed := s.priority != p
+	s.priority = p
+	hasStreamData := s.dataForWriting != nil || s.nextFrame != nil || len(s.retransmissionQueue) > 0 ||
+		(s.finishedWriting && !s.finSent)
+	s.mutex.Unlock()
+	// If the stream is already scheduled for sending, it needs to be rescheduled.
+	if changed && hasStreamData {
+		s.sender.onHasStreamData(s.streamID, s) // must be called without holding the mutex
+	}
+}
+
+// Priority returns the priority of the stream.
+func (s *SendStream) Priority() StreamPriority {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.priority
+}
+
 // Write writes data to the stream.
 // Write can be made to time out using [SendStream.SetWriteDeadline].
 // If the stream was canceled, the error is a [StreamError].
@@ -220,9 +272,9 @@ func (s *SendStream) canBufferStreamFrame() bool {
 
 // popStreamFrame returns the next STREAM frame that is supposed to be sent on this stream
 // maxBytes is the maximum length this frame (including frame header) will have.
-func (s *SendStream) popStreamFrame(maxBytes protocol.ByteCount, v protocol.Version) (_ ackhandler.StreamFrame, _ *wire.StreamDataBlockedFrame, hasMore bool) {
+func (s *SendStream) popStreamFrame(maxBytes protocol.ByteCount, now monotime.Time, v protocol.Version) (_ ackhandler.StreamFrame, _ *wire.StreamDataBlockedFrame, hasMore bool) {
 	s.mutex.Lock()
-	f, blocked, hasMoreData := s.popNewOrRetransmittedStreamFrame(maxBytes, v)
+	f, blocked, hasMoreData := s.popNewOrRetransmittedStreamFrame(maxBytes, now, v)
 	if f != nil {
 		s.numOutstandingFrames++
 	}
@@ -237,7 +289,7 @@ func (s *SendStream) popStreamFrame(maxBytes protocol.ByteCount, v protocol.Vers
 	}, blocked, hasMoreData
 }
 
-func (s *SendStream) popNewOrRetransmittedStreamFrame(maxBytes protocol.ByteCount, v protocol.Version) (_ *wire.StreamFrame, _ *wire.StreamDataBlockedFrame, hasMoreData bool) {
+func (s *SendStream) popNewOrRetransmittedStreamFrame(maxBytes protocol.ByteCount, now monotime.Time, v protocol.Version) (_ *wire.StreamFrame, _ *wire.StreamDataBlockedFrame, hasMoreData bool) {
 	if s.shutdownErr != nil {
 		return nil, nil, false
 	}
@@ -254,6 +306,7 @@ func (s *SendStream) popNewOrRetransmittedStreamFrame(maxBytes protocol.ByteCoun
 			if f == nil {
 				return nil, nil, true
 			}
+			s.bytesRetransmitted += f.DataLen()
 			// We always claim that we have more data to send.
 			// This might be incorrect, in which case there'll be a spurious call to popStreamFrame in the future.
 			return f, nil, true
@@ -297,7 +350,7 @@ func (s *SendStream) popNewOrRetransmittedStreamFrame(maxBytes protocol.ByteCoun
 	var blocked *wire.StreamDataBlockedFrame
 	// If the entire send window is used, the stream might have become blocked on stream-level flow control.
 	// This is not guaranteed though, because the stream might also have been blocked on connection-level flow control.
-	if f.DataLen() == maxDataLen && s.flowController.IsNewlyBlocked() {
+	if f.DataLen() == maxDataLen && s.flowController.IsNewlyBlocked(now) {
 		blocked = &wire.StreamDataBlockedFrame{StreamID: s.streamID, MaximumStreamData: s.writeOffset}
 	}
 	f.Fin = s.finishedWriting && s.dataForWriting == nil && s.nextFrame == nil && !s.finSent
@@ -545,8 +598,8 @@ func (s *SendStream) enableResetStreamAt() {
 	s.mutex.Unlock()
 }
 
-func (s *SendStream) updateSendWindow(limit protocol.ByteCount) {
-	updated := s.flowController.UpdateSendWindow(limit)
+func (s *SendStream) updateSendWindow(limit protocol.ByteCount, now monotime.Time) {
+	updated := s.flowController.UpdateSendWindow(limit, now)
 	if !updated { // duplicate or reordered MAX_STREAM_DATA frame
 		return
 	}
@@ -613,6 +666,29 @@ func (s *SendStream) reliableOffset() protocol.ByteCount {
 	return s.reliableSize
 }
 
+// Stats returns statistics about the stream.
+func (s *SendStream) Stats() SendStreamStats {
+	now := monotime.Now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	bytesWritten := s.writeOffset
+	if s.nextFrame != nil {
+		bytesWritten += s.nextFrame.DataLen()
+	}
+	var timeToFinAcked time.Duration
+	if !s.finAckedTime.IsZero() {
+		timeToFinAcked = s.finAckedTime.Sub(s.creationTime)
+	}
+	return SendStreamStats{
+		BytesWritten:           uint64(bytesWritten),
+		BytesSent:              uint64(s.writeOffset),
+		BytesRetransmitted:     uint64(s.bytesRetransmitted),
+		FlowControlBlockedTime: s.flowController.BlockedTime(now),
+		TimeToFinAcked:         timeToFinAcked,
+	}
+}
+
 // The Context is canceled as soon as the write-side of the stream is closed.
 // This happens when Close() or CancelWrite() is called, or when the peer
 // cancels the read-side of their stream.
@@ -674,6 +750,9 @@ func (s *sendStreamAckHandler) OnAcked(f wire.Frame) {
 		panic("numOutStandingFrames negative")
 	}
 	completed := (*SendStream)(s).isNewlyCompleted()
+	if completed && s.finSent {
+		s.finAckedTime = monotime.Now()
+	}
 	s.mutex.Unlock()
 
 	if completed {
@@ -76,7 +76,7 @@ func TestSendStreamWriteData(t *testing.T) {
 
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(6))
-	frame, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.False(t, hasMore)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Data: []byte("foobar"), DataLenPresent: true},
@@ -85,7 +85,7 @@ func TestSendStreamWriteData(t *testing.T) {
 	require.True(t, mockCtrl.Satisfied())
 
 	// nothing more to send at this point
-	_, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	_, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.False(t, hasMore)
 	require.True(t, mockCtrl.Satisfied())
 
@@ -112,7 +112,7 @@ func TestSendStreamWriteData(t *testing.T) {
 
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(4))
-	frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.False(t, hasMore)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 42, Offset: 6, Data: []byte{0xde, 0xad, 0xbe, 0xef}, DataLenPresent: true},
@@ -126,16 +126,16 @@ func TestSendStreamWriteData(t *testing.T) {
 	require.Equal(t, 6, n)
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount).Times(3)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(3)).Times(2)
-	frame, _, hasMore = str.popStreamFrame(expectedFrameHeaderLen(streamID, 10), protocol.Version1)
+	frame, _, hasMore = str.popStreamFrame(expectedFrameHeaderLen(streamID, 10), monotime.Now(), protocol.Version1)
 	require.Nil(t, frame.Frame)
 	require.True(t, hasMore)
-	frame, _, hasMore = str.popStreamFrame(expectedFrameHeaderLen(streamID, 10)+3, protocol.Version1)
+	frame, _, hasMore = str.popStreamFrame(expectedFrameHeaderLen(streamID, 10)+3, monotime.Now(), protocol.Version1)
 	require.True(t, hasMore)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Offset: 10, Data: []byte("foo"), DataLenPresent: true},
 		frame.Frame,
 	)
-	frame, _, hasMore = str.popStreamFrame(expectedFrameHeaderLen(streamID, 13)+3, protocol.Version1)
+	frame, _, hasMore = str.popStreamFrame(expectedFrameHeaderLen(streamID, 13)+3, monotime.Now(), protocol.Version1)
 	require.False(t, hasMore)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Offset: 13, Data: []byte("baz"), DataLenPresent: true},
@@ -168,7 +168,7 @@ func TestSendStreamLargeWrites(t *testing.T) {
 		var offset protocol.ByteCount
 		const size = 40
 		for offset+size < protocol.ByteCount(len(data))-protocol.MaxPacketBufferSize {
-			frame, _, hasMore := str.popStreamFrame(size+expectedFrameHeaderLen(streamID, offset), protocol.Version1)
+			frame, _, hasMore := str.popStreamFrame(size+expectedFrameHeaderLen(streamID, offset), monotime.Now(), protocol.Version1)
 			require.NotNil(t, frame.Frame)
 			require.True(t, hasMore)
 			require.Equal(t, offset, frame.Frame.Offset)
@@ -185,12 +185,12 @@ func TestSendStreamLargeWrites(t *testing.T) {
 		}
 
 		// empty frames are not sent
-		frame, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, offset), protocol.Version1)
+		frame, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, offset), monotime.Now(), protocol.Version1)
 		require.Nil(t, frame.Frame)
 		require.True(t, hasMore)
 
 		mockSender.EXPECT().onHasStreamData(streamID, str) // from the Close call
-		frame, _, hasMore = str.popStreamFrame(size+expectedFrameHeaderLen(streamID, offset), protocol.Version1)
+		frame, _, hasMore = str.popStreamFrame(size+expectedFrameHeaderLen(streamID, offset), monotime.Now(), protocol.Version1)
 		require.NotNil(t, frame.Frame)
 		require.True(t, hasMore)
 		require.Equal(t, data[offset:offset+size], frame.Frame.Data)
@@ -205,7 +205,7 @@ func TestSendStreamLargeWrites(t *testing.T) {
 			t.Fatal("write should have returned")
 		}
 
-		frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.NotNil(t, frame.Frame)
 		require.False(t, hasMore)
 		require.Equal(t, data[offset:], frame.Frame.Data)
@@ -234,7 +234,7 @@ func TestSendStreamLargeWriteBlocking(t *testing.T) {
 
 		mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount).Times(2)
 		mockFC.EXPECT().AddBytesSent(protocol.ByteCount(3))
-		frame, _, hasMoreData := str.popStreamFrame(expectedFrameHeaderLen(streamID, 0)+3, protocol.Version1)
+		frame, _, hasMoreData := str.popStreamFrame(expectedFrameHeaderLen(streamID, 0)+3, monotime.Now(), protocol.Version1)
 		require.NotNil(t, frame.Frame)
 		require.True(t, hasMoreData)
 		require.Equal(t, []byte("foo"), frame.Frame.Data)
@@ -248,7 +248,7 @@ func TestSendStreamLargeWriteBlocking(t *testing.T) {
 		}
 
 		mockFC.EXPECT().AddBytesSent(protocol.ByteCount(3))
-		frame, _, hasMoreData = str.popStreamFrame(expectedFrameHeaderLen(streamID, 3)+3, protocol.Version1)
+		frame, _, hasMoreData = str.popStreamFrame(expectedFrameHeaderLen(streamID, 3)+3, monotime.Now(), protocol.Version1)
 		require.NotNil(t, frame.Frame)
 		require.True(t, hasMoreData)
 		require.Equal(t, []byte("bar"), frame.Frame.Data)
@@ -278,7 +278,7 @@ func TestSendStreamCopyData(t *testing.T) {
 	require.NoError(t, err)
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(gomock.Any())
-	frame, _, _ := str.popStreamFrame(protocol.MaxPacketBufferSize, protocol.Version1)
+	frame, _, _ := str.popStreamFrame(protocol.MaxPacketBufferSize, monotime.Now(), protocol.Version1)
 	data[1] = 'e' // modify the data after it has been written
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Data: []byte("foobar"), DataLenPresent: true},
@@ -361,7 +361,7 @@ func TestSendStreamDeadlineRemoval(t *testing.T) {
 
 		mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 		mockFC.EXPECT().AddBytesSent(gomock.Any())
-		frame, _, hasMoreData := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, hasMoreData := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.NotNil(t, frame.Frame)
 		require.False(t, hasMoreData)
 		require.Equal(t, []byte("foobar"), frame.Frame.Data)
@@ -406,7 +406,7 @@ func TestSendStreamDeadlineExtension(t *testing.T) {
 			t.Fatal("timeout")
 		}
 
-		frame, _, hasMoreData := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, hasMoreData := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.Nil(t, frame.Frame)
 		require.False(t, hasMoreData)
 	})
@@ -433,14 +433,14 @@ func TestSendStreamClose(t *testing.T) {
 
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount).Times(2)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(3)).Times(2)
-	frame, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 0)+3, protocol.Version1)
+	frame, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 0)+3, monotime.Now(), protocol.Version1)
 	require.NotNil(t, frame.Frame)
 	require.True(t, hasMore)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Offset: 0, Data: []byte("foo"), DataLenPresent: true}, // no FIN yet
 		frame.Frame,
 	)
-	frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.False(t, hasMore)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Offset: 3, Fin: true, Data: []byte("bar"), DataLenPresent: true},
@@ -451,13 +451,13 @@ func TestSendStreamClose(t *testing.T) {
 	// further calls to Write return an error
 	_, err = strWithTimeout.Write([]byte("foobar"))
 	require.ErrorContains(t, err, "write on closed stream 1234")
-	frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, frame.Frame)
 	require.False(t, hasMore)
 
 	// further calls to Close don't do anything
 	require.NoError(t, str.Close())
-	frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, frame.Frame)
 	require.False(t, hasMore)
 	require.True(t, mockCtrl.Satisfied())
@@ -476,7 +476,7 @@ func TestSendStreamImmediateClose(t *testing.T) {
 	str := newSendStream(context.Background(), streamID, mockSender, mockFC, false)
 	mockSender.EXPECT().onHasStreamData(streamID, str)
 	require.NoError(t, str.Close())
-	frame, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 13)+3, protocol.Version1)
+	frame, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 13)+3, monotime.Now(), protocol.Version1)
 	require.False(t, hasMore)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Fin: true, DataLenPresent: true},
@@ -498,8 +498,8 @@ func TestSendStreamFlowControlBlocked(t *testing.T) {
 	mockFC.EXPECT().SendWindowSize().Return(protocol.ByteCount(3))
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(3))
 	mockFC.EXPECT().SendWindowSize().Return(protocol.ByteCount(0))
-	mockFC.EXPECT().IsNewlyBlocked().Return(true)
-	frame, blocked, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	mockFC.EXPECT().IsNewlyBlocked(gomock.Any()).Return(true)
+	frame, blocked, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.True(t, hasMore)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Data: []byte("foo"), DataLenPresent: true},
@@ -507,7 +507,7 @@ func TestSendStreamFlowControlBlocked(t *testing.T) {
 	)
 	require.Equal(t, &wire.StreamDataBlockedFrame{StreamID: streamID, MaximumStreamData: 3}, blocked)
 
-	frame, blocked, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, blocked, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, frame.Frame)
 	require.Nil(t, blocked)
 	require.True(t, hasMore)
@@ -559,7 +559,7 @@ func TestSendStreamCloseForShutdown(t *testing.T) {
 		require.NoError(t, str.Close())
 
 		// no STREAM frames popped
-		frame, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.Nil(t, frame.Frame)
 		require.False(t, hasMore)
 
@@ -582,14 +582,40 @@ func TestSendStreamUpdateSendWindow(t *testing.T) {
 	require.True(t, mockCtrl.Satisfied())
 
 	// no calls to onHasStreamData if the window size wasn't increased
-	mockFC.EXPECT().UpdateSendWindow(protocol.ByteCount(41)).Return(false)
-	str.updateSendWindow(41)
+	mockFC.EXPECT().UpdateSendWindow(protocol.ByteCount(41), gomock.Any()).Return(false)
+	str.updateSendWindow(41, monotime.Now())
 
 	gomock.InOrder(
-		mockFC.EXPECT().UpdateSendWindow(protocol.ByteCount(123)).Return(true),
+		mockFC.EXPECT().UpdateSendWindow(protocol.ByteCount(123), gomock.Any()).Return(true),
 		mockSender.EXPECT().onHasStreamData(protocol.StreamID(42), str),
 	)
-	str.updateSendWindow(123)
+	str.updateSendWindow(123, monotime.Now())
+}
+
+func TestSendStreamPriority(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockFC := mocks.NewMockStreamFlowController(mockCtrl)
+	mockSender := NewMockStreamSender(mockCtrl)
+	str := newSendStream(context.Background(), 42, mockSender, mockFC, false)
+	require.Equal(t, StreamPriority{Urgency: DefaultStreamUrgency, Incremental: true}, str.Priority())
+
+	// no calls to onHasStreamData if there's no data to send
+	str.SetPriority(StreamPriority{Urgency: 1})
+	require.Equal(t, StreamPriority{Urgency: 1}, str.Priority())
+	str.SetPriority(StreamPriority{Urgency: 42})
+	require.Equal(t, StreamPriority{Urgency: MaxStreamUrgency}, str.Priority())
+
+	mockSender.EXPECT().onHasStreamData(protocol.StreamID(42), str)
+	_, err := str.Write([]byte("foobar"))
+	require.NoError(t, err)
+	require.True(t, mockCtrl.Satisfied())
+
+	// the stream is rescheduled when the priority changes...
+	mockSender.EXPECT().onHasStreamData(protocol.StreamID(42), str)
+	str.SetPriority(StreamPriority{Urgency: 0, Incremental: true})
+	require.True(t, mockCtrl.Satisfied())
+	// ... but not if it stays the same
+	str.SetPriority(StreamPriority{Urgency: 0, Incremental: true})
 }
 
 func TestSendStreamCancellation(t *testing.T) {
@@ -606,7 +632,7 @@ func TestSendStreamCancellation(t *testing.T) {
 		require.NoError(t, err)
 		mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 		mockFC.EXPECT().AddBytesSent(protocol.ByteCount(3))
-		frame, _, hasMore := str.popStreamFrame(3+expectedFrameHeaderLen(streamID, 0), protocol.Version1)
+		frame, _, hasMore := str.popStreamFrame(3+expectedFrameHeaderLen(streamID, 0), monotime.Now(), protocol.Version1)
 		require.NotNil(t, frame.Frame)
 		require.True(t, hasMore)
 		require.Equal(t, []byte("foo"), frame.Frame.Data)
@@ -661,20 +687,20 @@ func TestSendStreamCancellation(t *testing.T) {
 		}
 
 		// no data to send
-		frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.Nil(t, frame.Frame)
 		require.False(t, hasMore)
 
 		// future calls to Write should return an error
 		_, err = strWithTimeout.Write([]byte("foo"))
 		require.ErrorIs(t, err, &StreamError{StreamID: streamID, ErrorCode: 1234, Remote: false})
-		frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.Nil(t, frame.Frame)
 		require.False(t, hasMore)
 
 		// Close has no effect
 		require.ErrorContains(t, str.Close(), "close called for canceled stream")
-		frame, _, _ = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, _ = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.Nil(t, frame.Frame)
 		_, err = strWithTimeout.Write([]byte("foobar"))
 		require.Error(t, err)
@@ -705,7 +731,7 @@ func TestSendStreamCancellationAfterClose(t *testing.T) {
 	mockSender.EXPECT().onHasStreamControlFrame(streamID, str)
 	str.CancelWrite(1337)
 
-	frame, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, frame.Frame)
 	require.False(t, hasMore)
 
@@ -741,10 +767,10 @@ func testSendStreamCancellationStreamRetransmission(t *testing.T, remote bool) {
 
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount).Times(2)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(3)).Times(2)
-	f1, _, hasMore := str.popStreamFrame(3+expectedFrameHeaderLen(streamID, 0), protocol.Version1)
+	f1, _, hasMore := str.popStreamFrame(3+expectedFrameHeaderLen(streamID, 0), monotime.Now(), protocol.Version1)
 	require.NotNil(t, f1.Frame)
 	require.True(t, hasMore)
-	f2, _, hasMore := str.popStreamFrame(3+expectedFrameHeaderLen(streamID, 3), protocol.Version1)
+	f2, _, hasMore := str.popStreamFrame(3+expectedFrameHeaderLen(streamID, 3), monotime.Now(), protocol.Version1)
 	require.NotNil(t, f2.Frame)
 	require.False(t, hasMore)
 
@@ -762,7 +788,7 @@ func testSendStreamCancellationStreamRetransmission(t *testing.T, remote bool) {
 	// it doesn't matter if the STREAM frames are acked or lost
 	f1.Handler.OnAcked(f1.Frame)
 	f2.Handler.OnLost(f2.Frame)
-	frame, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, frame.Frame)
 	require.False(t, hasMore)
 	// if CancelWrite was called, the stream is completed as soon as the RESET_STREAM frame is acked
@@ -834,7 +860,7 @@ func testSendStreamStopSendingAfterWrite(t *testing.T, completeBy string) {
 	require.NoError(t, err)
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(gomock.Any())
-	frame, _, _ := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.NotNil(t, frame.Frame)
 	require.True(t, mockCtrl.Satisfied())
 
@@ -865,7 +891,7 @@ func testSendStreamStopSendingAfterWrite(t *testing.T, completeBy string) {
 	// error code and remote flag are unchanged
 	_, err = (&writerWithTimeout{Writer: str, Timeout: time.Second}).Write([]byte("foobar"))
 	require.ErrorIs(t, err, &StreamError{StreamID: streamID, ErrorCode: 1337, Remote: true})
-	frame, _, _ = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	frame, _, _ = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, frame.Frame)
 	_, ok, _ = str.getControlFrame(monotime.Now())
 	require.False(t, ok)
@@ -884,7 +910,7 @@ func TestSendStreamStopSendingDuringWrite(t *testing.T) {
 		require.NoError(t, err)
 		mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 		mockFC.EXPECT().AddBytesSent(gomock.Any())
-		frame, _, _ := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.NotNil(t, frame.Frame)
 		require.True(t, mockCtrl.Satisfied())
 
@@ -925,7 +951,7 @@ func TestSendStreamStopSendingDuringWrite(t *testing.T) {
 		// calls to Write should return an error
 		_, err = (&writerWithTimeout{Writer: str, Timeout: time.Second}).Write([]byte("foobar"))
 		require.ErrorIs(t, err, &StreamError{StreamID: streamID, ErrorCode: 1337, Remote: true})
-		frame, _, _ = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, _ = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.Nil(t, frame.Frame)
 
 		// calls to CancelWrite have no effect
@@ -938,7 +964,7 @@ func TestSendStreamStopSendingDuringWrite(t *testing.T) {
 
 		// Close has no effect
 		require.ErrorContains(t, str.Close(), "close called for canceled stream")
-		frame, _, _ = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+		frame, _, _ = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 		require.Nil(t, frame.Frame)
 		_, err = (&writerWithTimeout{Writer: str, Timeout: time.Second}).Write([]byte("foobar"))
 		require.Error(t, err)
@@ -975,7 +1001,7 @@ func TestSendStreamConcurrentWriteAndCancel(t *testing.T) {
 
 		done := make(chan struct{}, 2)
 		go func() {
-			str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+			str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 			done <- struct{}{}
 		}()
 		go func() {
@@ -1015,7 +1041,7 @@ func TestSendStreamRetransmissions(t *testing.T) {
 
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(3))
-	f1, _, _ := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f1, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Data: []byte("foo"), DataLenPresent: true},
 		f1.Frame,
@@ -1035,7 +1061,7 @@ func TestSendStreamRetransmissions(t *testing.T) {
 	require.True(t, mockCtrl.Satisfied())
 
 	// when popping a new frame, we first get the retransmission...
-	f2, _, hasMoreData := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f2, _, hasMoreData := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t, &wire.StreamFrame{StreamID: streamID, Data: []byte("foo"), DataLenPresent: true}, f2.Frame)
 	require.True(t, hasMoreData)
 	require.True(t, mockCtrl.Satisfied())
@@ -1043,7 +1069,7 @@ func TestSendStreamRetransmissions(t *testing.T) {
 	// ... then we get the new data
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(3))
-	f3, _, hasMoreData := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f3, _, hasMoreData := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t, &wire.StreamFrame{StreamID: streamID, Offset: 3, Fin: true, Data: []byte("bar"), DataLenPresent: true}, f3.Frame)
 	require.False(t, hasMoreData)
 	require.True(t, mockCtrl.Satisfied())
@@ -1055,6 +1081,51 @@ func TestSendStreamRetransmissions(t *testing.T) {
 	f3.Handler.OnAcked(f3.Frame)
 }
 
+func TestSendStreamStats(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		const streamID protocol.StreamID = 1000
+		mockCtrl := gomock.NewController(t)
+		mockFC := mocks.NewMockStreamFlowController(mockCtrl)
+		mockSender := NewMockStreamSender(mockCtrl)
+		str := newSendStream(context.Background(), streamID, mockSender, mockFC, false)
+
+		mockSender.EXPECT().onHasStreamData(streamID, str).Times(2)
+		_, err := (&writerWithTimeout{Writer: str, Timeout: time.Second}).Write([]byte("foobar"))
+		require.NoError(t, err)
+		require.NoError(t, str.Close())
+
+		mockFC.EXPECT().BlockedTime(gomock.Any()).Return(time.Duration(0))
+		stats := str.Stats()
+		require.Equal(t, SendStreamStats{BytesWritten: 6}, stats)
+
+		time.Sleep(time.Second)
+		mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
+		mockFC.EXPECT().AddBytesSent(protocol.ByteCount(6))
+		f1, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
+		require.NotNil(t, f1.Frame)
+
+		// lose the frame, and retransmit it
+		mockSender.EXPECT().onHasStreamData(streamID, str)
+		f1.Handler.OnLost(f1.Frame)
+		f2, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
+		require.NotNil(t, f2.Frame)
+
+		mockFC.EXPECT().BlockedTime(gomock.Any()).Return(250 * time.Millisecond)
+		stats = str.Stats()
+		require.Equal(t, uint64(6), stats.BytesWritten)
+		require.Equal(t, uint64(6), stats.BytesSent)
+		require.Equal(t, uint64(6), stats.BytesRetransmitted)
+		require.Equal(t, 250*time.Millisecond, stats.FlowControlBlockedTime)
+		require.Zero(t, stats.TimeToFinAcked)
+
+		time.Sleep(time.Second)
+		mockSender.EXPECT().onStreamCompleted(streamID)
+		f2.Handler.OnAcked(f2.Frame)
+		mockFC.EXPECT().BlockedTime(gomock.Any()).Return(250 * time.Millisecond)
+		require.Equal(t, 2*time.Second, str.Stats().TimeToFinAcked)
+	})
+}
+
 func TestSendStreamRetransmissionFraming(t *testing.T) {
 	const streamID protocol.StreamID = 1000
 	mockCtrl := gomock.NewController(t)
@@ -1068,7 +1139,7 @@ func TestSendStreamRetransmissionFraming(t *testing.T) {
 
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(6))
-	f, _, _ := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.NotNil(t, f.Frame)
 
 	// lose the frame
@@ -1076,18 +1147,18 @@ func TestSendStreamRetransmissionFraming(t *testing.T) {
 	f.Handler.OnLost(f.Frame)
 
 	// retransmission doesn't fit
-	f, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 0), protocol.Version1)
+	f, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 0), monotime.Now(), protocol.Version1)
 	require.Nil(t, f.Frame)
 	require.True(t, hasMore)
 
 	// split the retransmission
-	r1, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 0)+3, protocol.Version1)
+	r1, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 0)+3, monotime.Now(), protocol.Version1)
 	require.True(t, hasMore)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: streamID, Data: []byte("foo"), DataLenPresent: true},
 		r1.Frame,
 	)
-	r2, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 3)+3, protocol.Version1)
+	r2, _, hasMore := str.popStreamFrame(expectedFrameHeaderLen(streamID, 3)+3, monotime.Now(), protocol.Version1)
 	require.True(t, hasMore)
 	// When popping a retransmission, we always claim that there's more data to send.
 	// We accept that this might be incorrect.
@@ -1096,7 +1167,7 @@ func TestSendStreamRetransmissionFraming(t *testing.T) {
 		&wire.StreamFrame{StreamID: streamID, Offset: 3, Data: []byte("bar"), DataLenPresent: true},
 		r2.Frame,
 	)
-	_, _, hasMore = str.popStreamFrame(expectedFrameHeaderLen(streamID, 3)+3, protocol.Version1)
+	_, _, hasMore = str.popStreamFrame(expectedFrameHeaderLen(streamID, 3)+3, monotime.Now(), protocol.Version1)
 	require.False(t, hasMore)
 }
 
@@ -1116,7 +1187,7 @@ func TestSendStreamRetransmitDataUntilAcknowledged(t *testing.T) {
 	mockFC.EXPECT().SendWindowSize().DoAndReturn(func() protocol.ByteCount {
 		return protocol.ByteCount(mrand.IntN(500)) + 50
 	}).AnyTimes()
-	mockFC.EXPECT().IsNewlyBlocked().Return(false).AnyTimes()
+	mockFC.EXPECT().IsNewlyBlocked(gomock.Any()).Return(false).AnyTimes()
 	mockFC.EXPECT().AddBytesSent(gomock.Any()).AnyTimes()
 
 	data := make([]byte, dataLen)
@@ -1141,7 +1212,7 @@ func TestSendStreamRetransmitDataUntilAcknowledged(t *testing.T) {
 		if counter > 1e6 {
 			t.Fatal("stream should have completed")
 		}
-		f, _, _ := str.popStreamFrame(protocol.ByteCount(mrand.IntN(300)+100), protocol.Version1)
+		f, _, _ := str.popStreamFrame(protocol.ByteCount(mrand.IntN(300)+100), monotime.Now(), protocol.Version1)
 		var dequeuedFrame bool
 		if f.Frame != nil {
 			frameQueue = append(frameQueue, f)
@@ -1191,8 +1262,8 @@ func TestSendStreamResetStreamAtCancelBeforeSend(t *testing.T) {
 
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(6))
-	mockFC.EXPECT().IsNewlyBlocked()
-	f, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	mockFC.EXPECT().IsNewlyBlocked(gomock.Any())
+	f, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Data: []byte("foobar"), DataLenPresent: true},
 		f.Frame,
@@ -1206,14 +1277,14 @@ func TestSendStreamResetStreamAtCancelBeforeSend(t *testing.T) {
 	f.Handler.OnLost(f.Frame)
 	require.True(t, mockCtrl.Satisfied())
 
-	retransmission, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	retransmission, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Data: []byte("foobar"), DataLenPresent: true},
 		retransmission.Frame,
 	)
 	require.True(t, hasMore) // hasMore is always true when dequeuing a retransmission
 	require.True(t, mockCtrl.Satisfied())
-	f, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, f.Frame)
 	require.False(t, hasMore)
 	require.True(t, mockCtrl.Satisfied())
@@ -1239,7 +1310,7 @@ func TestSendStreamResetStreamAtCancelAfterSend(t *testing.T) {
 
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(9))
-	f, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Data: []byte("foobarbaz"), DataLenPresent: true},
 		f.Frame,
@@ -1260,14 +1331,14 @@ func TestSendStreamResetStreamAtCancelAfterSend(t *testing.T) {
 	mockSender.EXPECT().onHasStreamData(protocol.StreamID(1337), str)
 	f.Handler.OnLost(f.Frame)
 	// only the first 6 bytes need to be retransmitted
-	retransmission1, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	retransmission1, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Data: []byte("foobar"), DataLenPresent: true},
 		retransmission1.Frame,
 	)
 	require.True(t, hasMore) // hasMore is always true when dequeuing a retransmission
 	require.True(t, mockCtrl.Satisfied())
-	f, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, f.Frame)
 	require.False(t, hasMore)
 	require.True(t, mockCtrl.Satisfied())
@@ -1275,14 +1346,14 @@ func TestSendStreamResetStreamAtCancelAfterSend(t *testing.T) {
 	// lose the retransmission as well
 	mockSender.EXPECT().onHasStreamData(protocol.StreamID(1337), str)
 	retransmission1.Handler.OnLost(retransmission1.Frame)
-	retransmission2, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	retransmission2, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Data: []byte("foobar"), DataLenPresent: true},
 		retransmission2.Frame,
 	)
 	require.True(t, hasMore) // hasMore is always true when dequeuing a retransmission
 	require.True(t, mockCtrl.Satisfied())
-	f, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f, _, hasMore = str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, f.Frame)
 	require.False(t, hasMore)
 	require.True(t, mockCtrl.Satisfied())
@@ -1308,7 +1379,7 @@ func TestSendStreamResetStreamAtRetransmissions(t *testing.T) {
 	mockFC.EXPECT().AddBytesSent(gomock.Any()).AnyTimes()
 	_, err := str.Write([]byte("lorem"))
 	require.NoError(t, err)
-	f1, _, _ := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f1, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Data: []byte("lorem"), DataLenPresent: true},
 		f1.Frame,
@@ -1318,21 +1389,21 @@ func TestSendStreamResetStreamAtRetransmissions(t *testing.T) {
 	str.SetReliableBoundary()
 	_, err = str.Write([]byte("dolor"))
 	require.NoError(t, err)
-	f2, _, _ := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f2, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Offset: 5, Data: []byte("ipsumdolor"), DataLenPresent: true},
 		f2.Frame,
 	)
 	_, err = str.Write([]byte("sit"))
 	require.NoError(t, err)
-	f3, _, _ := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f3, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Offset: 15, Data: []byte("sit"), DataLenPresent: true},
 		f3.Frame,
 	)
 	_, err = str.Write([]byte("amet"))
 	require.NoError(t, err)
-	f4, _, _ := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f4, _, _ := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Offset: 18, Data: []byte("amet"), DataLenPresent: true},
 		f4.Frame,
@@ -1359,20 +1430,20 @@ func TestSendStreamResetStreamAtRetransmissions(t *testing.T) {
 	cf.Handler.OnAcked(cf.Frame)
 
 	// // the retransmission of f1 should be truncated to 6 bytes
-	r1, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	r1, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Offset: 5, Data: []byte("ipsum"), DataLenPresent: true},
 		r1.Frame,
 	)
 	require.True(t, hasMore)
-	r2, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	r2, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.EqualExportedValues(t,
 		&wire.StreamFrame{StreamID: 1337, Data: []byte("lorem"), DataLenPresent: true},
 		r2.Frame,
 	)
 	require.True(t, hasMore) // hasMore is always true when dequeuing a retransmission
 	require.True(t, mockCtrl.Satisfied())
-	r3, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	r3, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Nil(t, r3.Frame)
 	require.False(t, hasMore)
 	require.True(t, mockCtrl.Satisfied())
@@ -1403,7 +1474,7 @@ func TestSendStreamResetStreamAtStopSendingBeforeCancelation(t *testing.T) {
 	// send out a STREAM frame with all the data written so far
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(9))
-	f, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Equal(t, protocol.ByteCount(9), f.Frame.DataLen())
 	require.False(t, hasMore)
 	require.True(t, mockCtrl.Satisfied())
@@ -1449,7 +1520,7 @@ func testSendStreamResetStreamAtStopSendingAfterCancelation(t *testing.T, loseRe
 	// send out a STREAM frame with all the data written so far
 	mockFC.EXPECT().SendWindowSize().Return(protocol.MaxByteCount)
 	mockFC.EXPECT().AddBytesSent(protocol.ByteCount(9))
-	f, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, protocol.Version1)
+	f, _, hasMore := str.popStreamFrame(protocol.MaxByteCount, monotime.Now(), protocol.Version1)
 	require.Equal(t, protocol.ByteCount(9), f.Frame.DataLen())
 	require.False(t, hasMore)
 	require.True(t, mockCtrl.Satisfied())
@@ -1512,7 +1583,7 @@ func TestSendStreamResetStreamAtRandomized(t *testing.T) {
 	mockFC.EXPECT().SendWindowSize().DoAndReturn(func() protocol.ByteCount {
 		return protocol.ByteCount(mrand.IntN(500)) + 50
 	}).AnyTimes()
-	mockFC.EXPECT().IsNewlyBlocked().Return(false).AnyTimes()
+	mockFC.EXPECT().IsNewlyBlocked(gomock.Any()).Return(false).AnyTimes()
 	mockFC.EXPECT().AddBytesSent(gomock.Any()).AnyTimes()
 
 	data := make([]byte, dataLen)
@@ -1563,7 +1634,7 @@ func TestSendStreamResetStreamAtRandomized(t *testing.T) {
 			receivedResetStreamAt = true
 			require.Equal(t, protocol.ByteCount(reliableOffset), cf.Frame.(*wire.ResetStreamFrame).ReliableSize)
 		} else {
-			f, _, _ := str.popStreamFrame(protocol.ByteCount(mrand.IntN(300)+100), protocol.Version1)
+			f, _, _ := str.popStreamFrame(protocol.ByteCount(mrand.IntN(300)+100), monotime.Now(), protocol.Version1)
 			if f.Frame != nil {
 				// make sure that only retransmissions are sent once the RESET_STREAM_AT frame is sent
 				if receivedResetStreamAt {
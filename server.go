@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	internalcongestion "github.com/quic-go/quic-go/internal/congestion"
 	"github.com/quic-go/quic-go/internal/handshake"
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
@@ -86,7 +87,8 @@ type baseServer struct {
 		*tls.Config,
 		*handshake.TokenGenerator,
 		bool, /* client address validated by an address validation token */
-		time.Duration,
+		time.Duration, /* initial RTT */
+		internalcongestion.Bandwidth, /* initial bandwidth estimate */
 		qlogwriter.Trace,
 		utils.Logger,
 		protocol.Version,
@@ -112,6 +114,12 @@ type baseServer struct {
 	handshakingCount        sync.WaitGroup
 
 	verifySourceAddress func(net.Addr) bool
+	// nil if no admission control limits are configured
+	admission *admissionController
+	// nil if Transport.PathMetricsCache is not set
+	pathMetrics PathMetricsCache
+	// nil if Transport.MaxUnvalidatedHandshakes is not set
+	adaptiveRetry *adaptiveRetry
 
 	connQueue chan *Conn
 
@@ -246,9 +254,12 @@ func newServer(
 	config *Config,
 	qlogger qlogwriter.Recorder,
 	onClose func(),
-	tokenGeneratorKey TokenGeneratorKey,
+	tokenGeneratorKeys TokenGeneratorKeyRing,
 	maxTokenAge time.Duration,
 	verifySourceAddress func(net.Addr) bool,
+	maxUnvalidatedHandshakes int,
+	admission *admissionController,
+	pathMetrics PathMetricsCache,
 	disableVersionNegotiation bool,
 	acceptEarly bool,
 ) *baseServer {
@@ -258,9 +269,11 @@ func newServer(
 		tr:                        tr,
 		tlsConf:                   tlsConf,
 		config:                    config,
-		tokenGenerator:            handshake.NewTokenGenerator(tokenGeneratorKey),
+		tokenGenerator:            handshake.NewTokenGeneratorWithKeyRing(tokenGeneratorKeys),
 		maxTokenAge:               maxTokenAge,
 		verifySourceAddress:       verifySourceAddress,
+		admission:                 admission,
+		pathMetrics:               pathMetrics,
 		connIDGenerator:           connIDGenerator,
 		statelessResetter:         statelessResetter,
 		connQueue:                 make(chan *Conn, protocol.MaxAcceptQueueSize),
@@ -282,6 +295,9 @@ func newServer(
 	if acceptEarly {
 		s.zeroRTTQueues = map[protocol.ConnectionID]*zeroRTTQueue{}
 	}
+	if maxUnvalidatedHandshakes > 0 {
+		s.adaptiveRetry = newAdaptiveRetry(maxUnvalidatedHandshakes, qlogger)
+	}
 	go s.run()
 	go s.runSendQueue()
 	s.logger.Debugf("Listening for %s connections on %s", conn.LocalAddr().Network(), conn.LocalAddr().String())
@@ -740,18 +756,33 @@ func (s *baseServer) handleInitialImpl(p receivedPacket, hdr *wire.Header) error
 		}
 	}
 
-	if token == nil && s.verifySourceAddress != nil && s.verifySourceAddress(p.remoteAddr) {
-		// Retry invalidates all 0-RTT packets sent.
-		delete(s.zeroRTTQueues, hdr.DestConnectionID)
-		select {
-		case s.retryQueue <- rejectedPacket{receivedPacket: p, hdr: hdr}:
-		default:
-			// drop packet if we can't send out Retry packets fast enough
-			p.buffer.Release()
-		}
+	if token == nil && s.shouldVerifySourceAddress(p.remoteAddr) {
+		s.queueRetry(p, hdr)
 		return nil
 	}
 
+	if s.admission != nil {
+		admitted, reason, sendRetry := s.admission.admit(p.remoteAddr, clientAddrVerified, monotime.Now())
+		if !admitted {
+			if s.qlogger != nil {
+				remoteAddr, _ := p.remoteAddr.(*net.UDPAddr)
+				s.qlogger.RecordEvent(qlog.ConnectionRejected{
+					Remote: toPathEndpointInfo(remoteAddr),
+					Reason: reason,
+					Retry:  sendRetry,
+				})
+			}
+			if sendRetry {
+				s.logger.Debugf("Sending Retry to %s due to admission control: %s", p.remoteAddr, reason)
+				s.queueRetry(p, hdr)
+			} else {
+				s.logger.Debugf("Rejecting new connection from %s due to admission control: %s", p.remoteAddr, reason)
+				s.refuseNewConn(p, hdr)
+			}
+			return nil
+		}
+	}
+
 	// restore RTT from token
 	var rtt time.Duration
 	if token != nil && !token.IsRetryToken {
@@ -767,11 +798,24 @@ func (s *baseServer) handleInitialImpl(p receivedPacket, hdr *wire.Header) error
 		conf, err := s.config.GetConfigForClient(clientInfo)
 		if err != nil {
 			s.logger.Debugf("Rejecting new connection due to GetConfigForClient callback")
+			s.abortAdmission()
 			s.refuseNewConn(p, hdr)
 			return nil
 		}
 		config = populateConfig(conf)
+		if config.PreferredAddress != nil {
+			if err := validatePreferredAddress(config.PreferredAddress, s.connIDGenerator.ConnectionIDLen()); err != nil {
+				s.logger.Errorf("Rejecting new connection, config returned by GetConfigForClient is invalid: %s", err)
+				s.abortAdmission()
+				s.refuseNewConn(p, hdr)
+				return nil
+			}
+		}
+	}
+	if rtt == 0 {
+		rtt = initialRTTForPath(s.pathMetrics, p.remoteAddr, config)
 	}
+	bandwidth := initialBandwidthForPath(s.pathMetrics, p.remoteAddr)
 
 	var conn *wrappedConn
 	var cancel context.CancelCauseFunc
@@ -782,6 +826,7 @@ func (s *baseServer) handleInitialImpl(p receivedPacket, hdr *wire.Header) error
 		if err != nil {
 			cancel1(err)
 			s.logger.Debugf("Rejecting new connection due to ConnContext callback: %s", err)
+			s.abortAdmission()
 			s.refuseNewConn(p, hdr)
 			return nil
 		}
@@ -812,6 +857,7 @@ func (s *baseServer) handleInitialImpl(p receivedPacket, hdr *wire.Header) error
 	}
 	connID, err := s.connIDGenerator.GenerateConnectionID()
 	if err != nil {
+		s.abortAdmission()
 		return err
 	}
 	s.logger.Debugf("Changing connection ID to %s.", connID)
@@ -832,6 +878,7 @@ func (s *baseServer) handleInitialImpl(p receivedPacket, hdr *wire.Header) error
 		s.tokenGenerator,
 		clientAddrVerified,
 		rtt,
+		bandwidth,
 		qlogTrace,
 		s.logger,
 		hdr.Version,
@@ -843,6 +890,7 @@ func (s *baseServer) handleInitialImpl(p receivedPacket, hdr *wire.Header) error
 	// The only time this collision will occur if we receive the two Initial packets at the same time.
 	if added := s.tr.AddWithConnID(hdr.DestConnectionID, connID, conn); !added {
 		delete(s.zeroRTTQueues, hdr.DestConnectionID)
+		s.abortAdmission()
 		conn.closeWithTransportError(ConnectionRefused)
 		return nil
 	}
@@ -859,10 +907,63 @@ func (s *baseServer) handleInitialImpl(p receivedPacket, hdr *wire.Header) error
 		defer s.handshakingCount.Done()
 		s.handleNewConn(conn)
 	}()
-	go conn.run()
+	unvalidated := s.adaptiveRetry != nil && !clientAddrVerified
+	if unvalidated {
+		s.adaptiveRetry.handshakeStarted()
+	}
+	if s.admission != nil || unvalidated {
+		go s.trackHandshake(conn, unvalidated)
+	}
+	go func() {
+		conn.run()
+		conn.savePathMetrics(s.pathMetrics)
+	}()
 	return nil
 }
 
+func (s *baseServer) shouldVerifySourceAddress(addr net.Addr) bool {
+	if s.adaptiveRetry != nil && s.adaptiveRetry.shouldRetry() {
+		return true
+	}
+	return s.verifySourceAddress != nil && s.verifySourceAddress(addr)
+}
+
+func (s *baseServer) queueRetry(p receivedPacket, hdr *wire.Header) {
+	// Retry invalidates all 0-RTT packets sent.
+	delete(s.zeroRTTQueues, hdr.DestConnectionID)
+	select {
+	case s.retryQueue <- rejectedPacket{receivedPacket: p, hdr: hdr}:
+	default:
+		// drop packet if we can't send out Retry packets fast enough
+		p.buffer.Release()
+	}
+}
+
+func (s *baseServer) abortAdmission() {
+	if s.admission != nil {
+		s.admission.abort()
+	}
+}
+
+// trackHandshake releases the connection's admission control slots
+// once the handshake completes and once the connection is closed.
+// If the client's address wasn't validated, it also updates the adaptive Retry policy.
+func (s *baseServer) trackHandshake(conn *wrappedConn, unvalidated bool) {
+	select {
+	case <-conn.HandshakeComplete():
+	case <-conn.Context().Done():
+	}
+	if unvalidated {
+		s.adaptiveRetry.handshakeDone()
+	}
+	if s.admission == nil {
+		return
+	}
+	s.admission.handshakeDone()
+	<-conn.Context().Done()
+	s.admission.connClosed()
+}
+
 func (s *baseServer) refuseNewConn(p receivedPacket, hdr *wire.Header) {
 	delete(s.zeroRTTQueues, hdr.DestConnectionID)
 	select {
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	internalcongestion "github.com/quic-go/quic-go/internal/congestion"
 	"github.com/quic-go/quic-go/internal/handshake"
 	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
@@ -32,6 +33,8 @@ type serverOpts struct {
 	tokenGeneratorKey         TokenGeneratorKey
 	maxTokenAge               time.Duration
 	useRetry                  bool
+	maxUnvalidatedHandshakes  int
+	admission                 *admissionController
 	disableVersionNegotiation bool
 	acceptEarly               bool
 	newConn                   func(
@@ -51,6 +54,7 @@ type serverOpts struct {
 		*handshake.TokenGenerator,
 		bool, /* client address validated by an address validation token */
 		time.Duration,
+		internalcongestion.Bandwidth,
 		qlogwriter.Trace,
 		utils.Logger,
 		protocol.Version,
@@ -75,9 +79,12 @@ func newTestServer(t *testing.T, serverOpts *serverOpts) *testServer {
 		config,
 		serverOpts.eventRecorder,
 		func() {},
-		serverOpts.tokenGeneratorKey,
+		NewKeyRing(serverOpts.tokenGeneratorKey),
 		serverOpts.maxTokenAge,
 		verifySourceAddress,
+		serverOpts.maxUnvalidatedHandshakes,
+		serverOpts.admission,
+		nil,
 		serverOpts.disableVersionNegotiation,
 		serverOpts.acceptEarly,
 	)
@@ -677,6 +684,7 @@ func (r *connConstructorRecorder) NewConn(
 	_ *handshake.TokenGenerator,
 	_ bool,
 	_ time.Duration,
+	_ internalcongestion.Bandwidth,
 	_ qlogwriter.Trace,
 	_ utils.Logger,
 	_ protocol.Version,
@@ -884,6 +892,141 @@ func TestServerGetConfigForClientReject(t *testing.T) {
 	checkConnectionClose(t, conn, &eventRecorder, destConnID, srcConnID, qerr.ConnectionRefused)
 }
 
+func TestServerAdmissionControl(t *testing.T) {
+	t.Run("refusing", func(t *testing.T) {
+		testServerAdmissionControl(t, false)
+	})
+	t.Run("retry", func(t *testing.T) {
+		testServerAdmissionControl(t, true)
+	})
+}
+
+func testServerAdmissionControl(t *testing.T, retryOnLimit bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handshakeComplete := make(chan struct{})
+	recorder := newConnConstructorRecorder(
+		&connTestHooks{
+			context:           func() context.Context { return ctx },
+			handshakeComplete: func() <-chan struct{} { return handshakeComplete },
+		},
+		&connTestHooks{},
+	)
+	var eventRecorder events.Recorder
+	admission := newAdmissionController(0, 1, nil, retryOnLimit)
+	server := newTestServer(t, &serverOpts{
+		eventRecorder: &eventRecorder,
+		admission:     admission,
+		newConn:       recorder.NewConn,
+	})
+
+	server.handlePacket(getValidInitialPacket(t,
+		&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 42},
+		randConnID(6),
+		randConnID(8),
+	))
+	select {
+	case <-recorder.Args():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	require.Equal(t, AdmissionStats{Connections: 1, Handshakes: 1}, admission.currentStats())
+
+	// the second connection exceeds the handshake limit
+	conn := newUDPConnLocalhost(t)
+	srcConnID := randConnID(6)
+	destConnID := randConnID(8)
+	server.handlePacket(getValidInitialPacket(t, conn.LocalAddr(), srcConnID, destConnID))
+	if retryOnLimit {
+		checkRetry(t, conn, &eventRecorder, srcConnID)
+	} else {
+		checkConnectionClose(t, conn, &eventRecorder, destConnID, srcConnID, qerr.ConnectionRefused)
+	}
+	require.Equal(t,
+		[]qlogwriter.Event{
+			qlog.ConnectionRejected{
+				Remote: qlog.PathEndpointInfo{IPv4: conn.LocalAddr().(*net.UDPAddr).AddrPort()},
+				Reason: qlog.ConnectionRejectionMaxHandshakes,
+				Retry:  retryOnLimit,
+			},
+		},
+		eventRecorder.Events(qlog.ConnectionRejected{}),
+	)
+
+	// completing the handshake frees up the handshake slot, closing the connection the connection slot
+	close(handshakeComplete)
+	require.Eventually(t, func() bool {
+		return admission.currentStats().Handshakes == 0
+	}, time.Second, time.Millisecond)
+	require.Equal(t, 1, admission.currentStats().Connections)
+	cancel()
+	require.Eventually(t, func() bool {
+		return admission.currentStats().Connections == 0
+	}, time.Second, time.Millisecond)
+
+	server.handlePacket(getValidInitialPacket(t, conn.LocalAddr(), randConnID(6), randConnID(8)))
+	select {
+	case <-recorder.Args():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestServerAdaptiveRetry(t *testing.T) {
+	handshakeComplete := make(chan struct{})
+	recorder := newConnConstructorRecorder(
+		&connTestHooks{handshakeComplete: func() <-chan struct{} { return handshakeComplete }},
+		&connTestHooks{handshakeComplete: func() <-chan struct{} { return make(chan struct{}) }},
+		&connTestHooks{},
+	)
+	var eventRecorder events.Recorder
+	server := newTestServer(t, &serverOpts{
+		eventRecorder:            &eventRecorder,
+		maxUnvalidatedHandshakes: 2,
+		newConn:                  recorder.NewConn,
+	})
+
+	for range 2 {
+		server.handlePacket(getValidInitialPacket(t,
+			&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 42},
+			randConnID(6),
+			randConnID(8),
+		))
+		select {
+		case <-recorder.Args():
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+	require.Equal(t,
+		[]qlogwriter.Event{qlog.RetryStateUpdated{Enabled: true, UnvalidatedHandshakes: 2}},
+		eventRecorder.Events(qlog.RetryStateUpdated{}),
+	)
+
+	// the threshold was reached, the next client is sent a Retry
+	conn := newUDPConnLocalhost(t)
+	srcConnID := randConnID(6)
+	server.handlePacket(getValidInitialPacket(t, conn.LocalAddr(), srcConnID, randConnID(8)))
+	checkRetry(t, conn, &eventRecorder, srcConnID)
+
+	// completing a handshake disables Retry again
+	eventRecorder.Clear()
+	close(handshakeComplete)
+	require.Eventually(t, func() bool {
+		return len(eventRecorder.Events(qlog.RetryStateUpdated{})) > 0
+	}, time.Second, time.Millisecond)
+	require.Equal(t,
+		[]qlogwriter.Event{qlog.RetryStateUpdated{Enabled: false, UnvalidatedHandshakes: 1}},
+		eventRecorder.Events(qlog.RetryStateUpdated{}),
+	)
+
+	server.handlePacket(getValidInitialPacket(t, conn.LocalAddr(), randConnID(6), randConnID(8)))
+	select {
+	case <-recorder.Args():
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
+
 func TestServerReceiveQueue(t *testing.T) {
 	var eventRecorder events.Recorder
 	acceptConn := make(chan struct{})
@@ -908,6 +1051,7 @@ func TestServerReceiveQueue(t *testing.T) {
 			_ *handshake.TokenGenerator,
 			_ bool,
 			_ time.Duration,
+			_ internalcongestion.Bandwidth,
 			_ qlogwriter.Trace,
 			_ utils.Logger,
 			_ protocol.Version,
@@ -10,33 +10,67 @@ import (
 	"github.com/quic-go/quic-go/internal/protocol"
 )
 
+type staticStatelessResetKey StatelessResetKey
+
+func (k staticStatelessResetKey) Keys() (StatelessResetKey, []StatelessResetKey) {
+	return StatelessResetKey(k), nil
+}
+
 type statelessResetter struct {
+	keys StatelessResetKeyRing
+
 	mx sync.Mutex
-	h  hash.Hash
+	// the HMAC for the current key, it is recreated when the current key changes
+	key StatelessResetKey
+	h   hash.Hash
 }
 
 // newStatelessRetter creates a new stateless reset generator.
 // It is valid to use a nil key. In that case, a random key will be used.
 // This makes is impossible for on-path attackers to shut down established connections.
 func newStatelessResetter(key *StatelessResetKey) *statelessResetter {
-	var h hash.Hash
 	if key != nil {
-		h = hmac.New(sha256.New, key[:])
-	} else {
-		b := make([]byte, 32)
-		_, _ = rand.Read(b)
-		h = hmac.New(sha256.New, b)
+		return newStatelessResetterWithKeyRing(staticStatelessResetKey(*key))
 	}
-	return &statelessResetter{h: h}
+	var k StatelessResetKey
+	_, _ = rand.Read(k[:])
+	return newStatelessResetterWithKeyRing(staticStatelessResetKey(k))
+}
+
+func newStatelessResetterWithKeyRing(keys StatelessResetKeyRing) *statelessResetter {
+	return &statelessResetter{keys: keys}
 }
 
+// GetStatelessResetToken derives the stateless reset token from the current key.
 func (r *statelessResetter) GetStatelessResetToken(connID protocol.ConnectionID) protocol.StatelessResetToken {
+	current, _ := r.keys.Keys()
+
 	r.mx.Lock()
 	defer r.mx.Unlock()
 
+	if r.h == nil || r.key != current {
+		r.key = current
+		r.h = hmac.New(sha256.New, current[:])
+	}
 	var token protocol.StatelessResetToken
 	r.h.Write(connID.Bytes())
 	copy(token[:], r.h.Sum(nil))
 	r.h.Reset()
 	return token
 }
+
+// GetStatelessResetTokens derives the stateless reset tokens from the current and all previous keys.
+// The token derived from the current key is the first element.
+func (r *statelessResetter) GetStatelessResetTokens(connID protocol.ConnectionID) []protocol.StatelessResetToken {
+	_, previous := r.keys.Keys()
+	tokens := make([]protocol.StatelessResetToken, 0, 1+len(previous))
+	tokens = append(tokens, r.GetStatelessResetToken(connID))
+	for _, key := range previous {
+		h := hmac.New(sha256.New, key[:])
+		h.Write(connID.Bytes())
+		var token protocol.StatelessResetToken
+		copy(token[:], h.Sum(nil))
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
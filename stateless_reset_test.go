@@ -39,4 +39,22 @@ func TestStatelessResetter(t *testing.T) {
 		connID2 := protocol.ParseConnectionID(b)
 		require.NotEqual(t, token, m.GetStatelessResetToken(connID2))
 	})
+
+	t.Run("key ring", func(t *testing.T) {
+		key1 := StatelessResetKey{1, 2, 3, 4}
+		key2 := StatelessResetKey{5, 6, 7, 8}
+		connID := protocol.ParseConnectionID([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+		token1 := newStatelessResetter(&key1).GetStatelessResetToken(connID)
+		token2 := newStatelessResetter(&key2).GetStatelessResetToken(connID)
+
+		keys := NewKeyRing(key1)
+		m := newStatelessResetterWithKeyRing(keys)
+		require.Equal(t, token1, m.GetStatelessResetToken(connID))
+		require.Equal(t, []protocol.StatelessResetToken{token1}, m.GetStatelessResetTokens(connID))
+
+		// rotate the key
+		keys.SetKeys(key2, key1)
+		require.Equal(t, token2, m.GetStatelessResetToken(connID))
+		require.Equal(t, []protocol.StatelessResetToken{token2, token1}, m.GetStatelessResetTokens(connID))
+	})
 }
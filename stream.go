@@ -124,6 +124,17 @@ func (s *Stream) Write(p []byte) (int, error) {
 	return s.sendStr.Write(p)
 }
 
+// SetPriority sets the priority of the send direction of the stream.
+// See [SendStream.SetPriority] for more details.
+func (s *Stream) SetPriority(p StreamPriority) {
+	s.sendStr.SetPriority(p)
+}
+
+// Priority returns the priority of the send direction of the stream.
+func (s *Stream) Priority() StreamPriority {
+	return s.sendStr.Priority()
+}
+
 // CancelWrite aborts sending on this stream.
 // See [SendStream.CancelWrite] for more details.
 func (s *Stream) CancelWrite(errorCode StreamErrorCode) {
@@ -142,6 +153,21 @@ func (s *Stream) Context() context.Context {
 	return s.sendStr.Context()
 }
 
+// StreamStats contains statistics about a bidirectional stream.
+type StreamStats struct {
+	Send    SendStreamStats
+	Receive ReceiveStreamStats
+}
+
+// Stats returns statistics about both directions of the stream.
+// See [SendStream.Stats] and [ReceiveStream.Stats] for more details.
+func (s *Stream) Stats() StreamStats {
+	return StreamStats{
+		Send:    s.sendStr.Stats(),
+		Receive: s.receiveStr.Stats(),
+	}
+}
+
 // Close closes the send-direction of the stream.
 // It does not close the receive-direction of the stream.
 func (s *Stream) Close() error {
@@ -160,16 +186,16 @@ func (s *Stream) handleStopSendingFrame(frame *wire.StopSendingFrame) {
 	s.sendStr.handleStopSendingFrame(frame)
 }
 
-func (s *Stream) updateSendWindow(limit protocol.ByteCount) {
-	s.sendStr.updateSendWindow(limit)
+func (s *Stream) updateSendWindow(limit protocol.ByteCount, now monotime.Time) {
+	s.sendStr.updateSendWindow(limit, now)
 }
 
 func (s *Stream) enableResetStreamAt() {
 	s.sendStr.enableResetStreamAt()
 }
 
-func (s *Stream) popStreamFrame(maxBytes protocol.ByteCount, v protocol.Version) (_ ackhandler.StreamFrame, _ *wire.StreamDataBlockedFrame, hasMore bool) {
-	return s.sendStr.popStreamFrame(maxBytes, v)
+func (s *Stream) popStreamFrame(maxBytes protocol.ByteCount, now monotime.Time, v protocol.Version) (_ ackhandler.StreamFrame, _ *wire.StreamDataBlockedFrame, hasMore bool) {
+	return s.sendStr.popStreamFrame(maxBytes, now, v)
 }
 
 func (s *Stream) getControlFrame(now monotime.Time) (_ ackhandler.Frame, ok, hasMore bool) {
@@ -190,7 +190,7 @@ func (m *streamsMap) HandleMaxStreamsFrame(f *wire.MaxStreamsFrame) {
 }
 
 type sendStreamFrameHandler interface {
-	updateSendWindow(protocol.ByteCount)
+	updateSendWindow(protocol.ByteCount, monotime.Time)
 	handleStopSendingFrame(*wire.StopSendingFrame)
 }
 
@@ -226,7 +226,7 @@ func (m *streamsMap) getSendStream(id protocol.StreamID) (sendStreamFrameHandler
 	panic("unreachable")
 }
 
-func (m *streamsMap) HandleMaxStreamDataFrame(f *wire.MaxStreamDataFrame) error {
+func (m *streamsMap) HandleMaxStreamDataFrame(f *wire.MaxStreamDataFrame, now monotime.Time) error {
 	str, err := m.getSendStream(f.StreamID)
 	if err != nil {
 		return err
@@ -234,7 +234,7 @@ func (m *streamsMap) HandleMaxStreamDataFrame(f *wire.MaxStreamDataFrame) error
 	if str == nil { // stream already deleted
 		return nil
 	}
-	str.updateSendWindow(f.MaximumStreamData)
+	str.updateSendWindow(f.MaximumStreamData, now)
 	return nil
 }
 
@@ -317,13 +317,13 @@ func (m *streamsMap) HandleStreamFrame(f *wire.StreamFrame, rcvTime monotime.Tim
 	return str.handleStreamFrame(f, rcvTime)
 }
 
-func (m *streamsMap) HandleTransportParameters(p *wire.TransportParameters) {
+func (m *streamsMap) HandleTransportParameters(p *wire.TransportParameters, now monotime.Time) {
 	m.supportsResetStreamAt = p.EnableResetStreamAt
 	m.outgoingBidiStreams.EnableResetStreamAt()
 	m.outgoingUniStreams.EnableResetStreamAt()
-	m.outgoingBidiStreams.UpdateSendWindow(p.InitialMaxStreamDataBidiRemote)
+	m.outgoingBidiStreams.UpdateSendWindow(p.InitialMaxStreamDataBidiRemote, now)
 	m.outgoingBidiStreams.SetMaxStream(p.MaxBidiStreamNum.StreamID(protocol.StreamTypeBidi, m.perspective))
-	m.outgoingUniStreams.UpdateSendWindow(p.InitialMaxStreamDataUni)
+	m.outgoingUniStreams.UpdateSendWindow(p.InitialMaxStreamDataUni, now)
 	m.outgoingUniStreams.SetMaxStream(p.MaxUniStreamNum.StreamID(protocol.StreamTypeUni, m.perspective))
 }
 
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/internal/synctest"
@@ -29,7 +30,7 @@ func (s *mockStream) closeForShutdown(err error) {
 	s.closeErr = err
 }
 
-func (s *mockStream) updateSendWindow(limit protocol.ByteCount) {
+func (s *mockStream) updateSendWindow(limit protocol.ByteCount, _ monotime.Time) {
 	s.sendWindow = limit
 }
 
@@ -6,13 +6,14 @@ import (
 	"slices"
 	"sync"
 
+	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/internal/wire"
 )
 
 type outgoingStream interface {
-	updateSendWindow(protocol.ByteCount)
+	updateSendWindow(protocol.ByteCount, monotime.Time)
 	enableResetStreamAt()
 	closeForShutdown(error)
 }
@@ -197,10 +198,10 @@ func (m *outgoingStreamsMap[T]) SetMaxStream(id protocol.StreamID) {
 // UpdateSendWindow is called when the peer's transport parameters are received.
 // Only in the case of a 0-RTT handshake will we have open streams at this point.
 // We might need to update the send window, in case the server increased it.
-func (m *outgoingStreamsMap[T]) UpdateSendWindow(limit protocol.ByteCount) {
+func (m *outgoingStreamsMap[T]) UpdateSendWindow(limit protocol.ByteCount, now monotime.Time) {
 	m.mutex.Lock()
 	for _, str := range m.streams {
-		str.updateSendWindow(limit)
+		str.updateSendWindow(limit, now)
 	}
 	m.mutex.Unlock()
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/quic-go/quic-go/internal/monotime"
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/internal/synctest"
@@ -51,7 +52,7 @@ func testStreamsMapOutgoingOpenAndDelete(t *testing.T, perspective protocol.Pers
 	require.Equal(t, firstStream+4, str2.id)
 
 	// update send window
-	m.UpdateSendWindow(1000)
+	m.UpdateSendWindow(1000, monotime.Now())
 	require.Equal(t, protocol.ByteCount(1000), str1.sendWindow)
 	require.Equal(t, protocol.ByteCount(1000), str2.sendWindow)
 
@@ -62,7 +62,7 @@ func testStreamsMapCreatingStreams(t *testing.T,
 	m.HandleTransportParameters(&wire.TransportParameters{
 		MaxBidiStreamNum: protocol.MaxStreamCount,
 		MaxUniStreamNum:  protocol.MaxStreamCount,
-	})
+	}, monotime.Now())
 
 	// opening streams
 	str1, err := m.OpenStream()
@@ -139,7 +139,7 @@ func testStreamsMapDeletingStreams(t *testing.T,
 	m.HandleTransportParameters(&wire.TransportParameters{
 		MaxBidiStreamNum: 10,
 		MaxUniStreamNum:  10,
-	})
+	}, monotime.Now())
 
 	_, err := m.OpenStream()
 	require.NoError(t, err)
@@ -216,7 +216,7 @@ func testStreamsMapStreamLimits(t *testing.T, perspective protocol.Perspective)
 		func(frame wire.Frame) { frameQueue = append(frameQueue, frame) },
 		func(protocol.StreamID) flowcontrol.StreamFlowController {
 			fc := mocks.NewMockStreamFlowController(mockCtrl)
-			fc.EXPECT().UpdateSendWindow(gomock.Any()).AnyTimes()
+			fc.EXPECT().UpdateSendWindow(gomock.Any(), gomock.Any()).AnyTimes()
 			return fc
 		},
 		100,
@@ -228,7 +228,7 @@ func testStreamsMapStreamLimits(t *testing.T, perspective protocol.Perspective)
 	_, err := m.OpenStream()
 	require.ErrorIs(t, err, &StreamLimitReachedError{})
 	require.ErrorContains(t, err, "too many open streams")
-	m.HandleTransportParameters(&wire.TransportParameters{MaxBidiStreamNum: 1})
+	m.HandleTransportParameters(&wire.TransportParameters{MaxBidiStreamNum: 1}, monotime.Now())
 	_, err = m.OpenStream()
 	require.NoError(t, err)
 	_, err = m.OpenStream()
@@ -236,7 +236,7 @@ func testStreamsMapStreamLimits(t *testing.T, perspective protocol.Perspective)
 
 	_, err = m.OpenUniStream()
 	require.ErrorIs(t, err, &StreamLimitReachedError{})
-	m.HandleTransportParameters(&wire.TransportParameters{MaxUniStreamNum: 1})
+	m.HandleTransportParameters(&wire.TransportParameters{MaxUniStreamNum: 1}, monotime.Now())
 	_, err = m.OpenUniStream()
 	require.NoError(t, err)
 	_, err = m.OpenUniStream()
@@ -262,7 +262,7 @@ func testStreamsMapStreamLimits(t *testing.T, perspective protocol.Perspective)
 	require.ErrorIs(t, err, &StreamLimitReachedError{})
 
 	// decrease via transport parameters
-	m.HandleTransportParameters(&wire.TransportParameters{MaxBidiStreamNum: 0})
+	m.HandleTransportParameters(&wire.TransportParameters{MaxBidiStreamNum: 0}, monotime.Now())
 	_, err = m.OpenStream()
 	require.ErrorIs(t, err, &StreamLimitReachedError{})
 }
@@ -405,7 +405,7 @@ func TestStreamsMapHandleSendStreamFrames(t *testing.T) {
 				testStreamsMapHandleSendStreamFrames(t,
 					pers,
 					func(m *streamsMap, id protocol.StreamID) error {
-						return m.HandleMaxStreamDataFrame(&wire.MaxStreamDataFrame{StreamID: id, MaximumStreamData: 1000})
+						return m.HandleMaxStreamDataFrame(&wire.MaxStreamDataFrame{StreamID: id, MaximumStreamData: 1000}, monotime.Now())
 					},
 				)
 			})
@@ -425,7 +425,7 @@ func testStreamsMapHandleSendStreamFrames(t *testing.T, pers protocol.Perspectiv
 		func(id protocol.StreamID) flowcontrol.StreamFlowController {
 			streamsCreated = append(streamsCreated, id)
 			fc := mocks.NewMockStreamFlowController(mockCtrl)
-			fc.EXPECT().UpdateSendWindow(gomock.Any()).AnyTimes()
+			fc.EXPECT().UpdateSendWindow(gomock.Any(), gomock.Any()).AnyTimes()
 			return fc
 		},
 		100,
@@ -548,21 +548,21 @@ func TestStreamsMap0RTT(t *testing.T) {
 	m.HandleTransportParameters(&wire.TransportParameters{
 		MaxBidiStreamNum: 1,
 		MaxUniStreamNum:  1,
-	})
+	}, monotime.Now())
 	_, err := m.OpenStream()
 	require.NoError(t, err)
 	_, err = m.OpenUniStream()
 	require.NoError(t, err)
 
-	fcBidi.EXPECT().UpdateSendWindow(protocol.ByteCount(1234))
-	fcUni.EXPECT().UpdateSendWindow(protocol.ByteCount(4321))
+	fcBidi.EXPECT().UpdateSendWindow(protocol.ByteCount(1234), gomock.Any())
+	fcUni.EXPECT().UpdateSendWindow(protocol.ByteCount(4321), gomock.Any())
 	// new transport parameters
 	m.HandleTransportParameters(&wire.TransportParameters{
 		MaxBidiStreamNum:               1000,
 		InitialMaxStreamDataBidiRemote: 1234,
 		MaxUniStreamNum:                1000,
 		InitialMaxStreamDataUni:        4321,
-	})
+	}, monotime.Now())
 }
 
 func TestStreamsMap0RTTRejection(t *testing.T) {
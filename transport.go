@@ -14,6 +14,7 @@ import (
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/utils"
 	"github.com/quic-go/quic-go/internal/wire"
+	"github.com/quic-go/quic-go/pcapng"
 	"github.com/quic-go/quic-go/qlog"
 	"github.com/quic-go/quic-go/qlogwriter"
 )
@@ -47,6 +48,11 @@ type closePacket struct {
 	info    packetInfo
 }
 
+type pathProbePacket struct {
+	buf  *packetBuffer
+	addr net.Addr
+}
+
 // The Transport is the central point to manage incoming and outgoing QUIC connections.
 // QUIC demultiplexes connections based on their QUIC Connection IDs, not based on the 4-tuple.
 // This means that a single UDP socket can be used for listening for incoming connections, as well as
@@ -80,6 +86,7 @@ type Transport struct {
 	// which allows routing / load balancing based on connection IDs.
 	// All Connection IDs returned by the ConnectionIDGenerator MUST
 	// have the same length.
+	// The quiclb package provides a generator implementing QUIC-LB (draft-ietf-quic-load-balancers).
 	ConnectionIDGenerator ConnectionIDGenerator
 
 	// The StatelessResetKey is used to generate stateless reset tokens.
@@ -88,12 +95,25 @@ type Transport struct {
 	// allow the peer to quickly recover from crashes and reboots of this node.
 	// See section 10.3 of RFC 9000 for details.
 	StatelessResetKey *StatelessResetKey
+	// StatelessResetKeys can be used instead of the StatelessResetKey to allow rotating keys.
+	// Tokens for new connection IDs are derived from the current key,
+	// and stateless resets are sent using tokens derived from all keys,
+	// such that peers can validate them regardless of which key was current when the connection ID was issued.
+	// Keys can be replaced at any time, for example by using a KeyRing.
+	// It is invalid to set both StatelessResetKey and StatelessResetKeys.
+	StatelessResetKeys StatelessResetKeyRing
 
 	// The TokenGeneratorKey is used to encrypt session resumption tokens.
 	// If no key is configured, a random key will be generated.
 	// If multiple servers are authoritative for the same domain, they should use the same key,
 	// see section 8.1.3 of RFC 9000 for details.
 	TokenGeneratorKey *TokenGeneratorKey
+	// TokenGeneratorKeys can be used instead of the TokenGeneratorKey to allow rotating keys.
+	// New Retry and session resumption tokens are encrypted using the current key,
+	// and tokens encrypted using any of the previous keys are still accepted.
+	// Keys can be replaced at any time, for example by using a KeyRing.
+	// It is invalid to set both TokenGeneratorKey and TokenGeneratorKeys.
+	TokenGeneratorKeys TokenGeneratorKeyRing
 
 	// MaxTokenAge is the maximum age of the resumption token presented during the handshake.
 	// These tokens allow skipping address resumption when resuming a QUIC connection,
@@ -118,6 +138,42 @@ type Transport struct {
 	// implementation of this callback (negating its return value).
 	VerifySourceAddress func(net.Addr) bool
 
+	// MaxUnvalidatedHandshakes is the number of concurrent handshakes with clients whose source address
+	// hasn't been validated, at which the server starts validating the source address of all new
+	// connection attempts using QUIC's Retry mechanism.
+	// Once the number of these handshakes has dropped to half this value, Retry is disabled again.
+	// Enabling and disabling Retry is reported to the Tracer as a qlog RetryStateUpdated event.
+	// Independent of this setting, VerifySourceAddress can request a Retry for a connection attempt.
+	// If not set, Retry is only used when requested by VerifySourceAddress.
+	MaxUnvalidatedHandshakes int
+
+	// MaxConnections is the maximum number of concurrent incoming connections,
+	// including connections that are still handshaking.
+	// New connections exceeding this limit are refused with a CONNECTION_REFUSED error,
+	// unless RetryOnLimit is set.
+	// If not set, the number of connections is not limited.
+	MaxConnections int
+
+	// MaxHandshakes is the maximum number of incoming connections that are handshaking concurrently.
+	// New connections exceeding this limit are refused with a CONNECTION_REFUSED error,
+	// unless RetryOnLimit is set.
+	// If not set, the number of handshakes is not limited.
+	MaxHandshakes int
+
+	// ConnectionRateLimit limits the rate of new incoming connections per source address prefix.
+	// New connections exceeding this limit are refused with a CONNECTION_REFUSED error,
+	// unless RetryOnLimit is set.
+	// Note that the source address of a connection attempt might be spoofed,
+	// allowing an attacker to use up the limit of other clients, unless RetryOnLimit is set.
+	ConnectionRateLimit *ConnectionRateLimit
+
+	// RetryOnLimit makes the server send a Retry packet to clients that exceed one of the
+	// admission control limits (MaxConnections, MaxHandshakes and ConnectionRateLimit),
+	// if the client's source address hasn't been validated yet.
+	// Once a client has validated its address, it is refused if it still exceeds a limit.
+	// Every rejection is reported to the Tracer as a qlog ConnectionRejected event.
+	RetryOnLimit bool
+
 	// ConnContext is called when the server accepts a new connection. To reject a connection return
 	// a non-nil error.
 	// The context is closed when the connection is closed, or when the handshake fails for any reason.
@@ -130,10 +186,27 @@ type Transport struct {
 	// It is not used for dialed connections.
 	ConnContext func(context.Context, *ClientInfo) (context.Context, error)
 
+	// PathMetricsCache caches the metrics of the network paths to remote addresses, such as the RTT.
+	// When a connection is closed, the metrics of its path are saved to the cache.
+	// New connections to the same remote address then use the cached RTT as their initial RTT estimate,
+	// and the cached bandwidth estimate to choose their initial congestion window,
+	// allowing them to start with more accurate PTO and pacing values.
+	// New paths (see Conn.AddPath) to the same remote address use the cached RTT.
+	// This applies to both dialed and accepted connections.
+	// If nil, no path metrics are cached.
+	PathMetricsCache PathMetricsCache
+
 	// A Tracer traces events that don't belong to a single QUIC connection.
 	// Recorder.Close is called when the transport is closed.
 	Tracer qlogwriter.Recorder
 
+	// PacketCapture, if set, captures all UDP datagrams sent and received on this Transport.
+	// The TLS secrets of all connections are written to the capture as well,
+	// allowing Wireshark to decrypt the capture without a separate key log file.
+	// It's the application's responsibility to flush and close the underlying file after closing the Transport.
+	// This should only be used for debugging, since anyone with access to the capture can decrypt the traffic.
+	PacketCapture *pcapng.Writer
+
 	mutex       sync.Mutex
 	handlers    map[protocol.ConnectionID]packetHandler
 	resetTokens map[protocol.StatelessResetToken]packetHandler
@@ -147,13 +220,19 @@ type Transport struct {
 	// If no ConnectionIDGenerator is set, this is set to a default.
 	connIDGenerator   ConnectionIDGenerator
 	statelessResetter *statelessResetter
+	// Set in init.
+	// If no TokenGeneratorKeys are set, this is a key ring that only contains the TokenGeneratorKey.
+	tokenGeneratorKeys TokenGeneratorKeyRing
 
 	server *baseServer
+	// Set when the first listener is created, if any admission control limits are configured.
+	admission *admissionController
 
 	conn rawConn
 
 	closeQueue          chan closePacket
 	statelessResetQueue chan receivedPacket
+	pathProbeQueue      chan pathProbePacket
 
 	listening   chan struct{} // is closed when listen returns
 	closeErr    error
@@ -195,6 +274,11 @@ func (t *Transport) createServer(tlsConf *tls.Config, conf *Config, allow0RTT bo
 	if err := validateConfig(conf); err != nil {
 		return nil, err
 	}
+	if t.ConnectionRateLimit != nil {
+		if err := t.ConnectionRateLimit.validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
@@ -205,10 +289,21 @@ func (t *Transport) createServer(tlsConf *tls.Config, conf *Config, allow0RTT bo
 	if t.server != nil {
 		return nil, errListenerAlreadySet
 	}
+	if t.admission == nil && (t.MaxConnections > 0 || t.MaxHandshakes > 0 || t.ConnectionRateLimit != nil) {
+		t.admission = newAdmissionController(t.MaxConnections, t.MaxHandshakes, t.ConnectionRateLimit, t.RetryOnLimit)
+	}
 	conf = populateConfig(conf)
 	if err := t.init(false); err != nil {
 		return nil, err
 	}
+	if conf.PreferredAddress != nil {
+		if err := validatePreferredAddress(conf.PreferredAddress, t.connIDLen); err != nil {
+			return nil, err
+		}
+	}
+	if t.PacketCapture != nil {
+		tlsConf = serverTLSConfigWithKeyLogWriter(tlsConf, t.PacketCapture.KeyLogWriter())
+	}
 	maxTokenAge := t.MaxTokenAge
 	if maxTokenAge == 0 {
 		maxTokenAge = 24 * time.Hour
@@ -223,9 +318,12 @@ func (t *Transport) createServer(tlsConf *tls.Config, conf *Config, allow0RTT bo
 		conf,
 		t.Tracer,
 		t.closeServer,
-		*t.TokenGeneratorKey,
+		t.tokenGeneratorKeys,
 		maxTokenAge,
 		t.VerifySourceAddress,
+		t.MaxUnvalidatedHandshakes,
+		t.admission,
+		t.PathMetricsCache,
 		t.DisableVersionNegotiationPackets,
 		allow0RTT,
 	)
@@ -233,6 +331,19 @@ func (t *Transport) createServer(tlsConf *tls.Config, conf *Config, allow0RTT bo
 	return s, nil
 }
 
+// AdmissionStats returns statistics about the admission control of incoming connections.
+// The statistics are only collected if at least one of MaxConnections, MaxHandshakes
+// or ConnectionRateLimit is set.
+func (t *Transport) AdmissionStats() AdmissionStats {
+	t.mutex.Lock()
+	admission := t.admission
+	t.mutex.Unlock()
+	if admission == nil {
+		return AdmissionStats{}
+	}
+	return admission.currentStats()
+}
+
 // Dial dials a new connection to a remote host (not using 0-RTT).
 func (t *Transport) Dial(ctx context.Context, addr net.Addr, tlsConf *tls.Config, conf *Config) (*Conn, error) {
 	return t.dial(ctx, addr, "", tlsConf, conf, false)
@@ -252,6 +363,9 @@ func (t *Transport) dial(ctx context.Context, addr net.Addr, host string, tlsCon
 	}
 	conf = populateConfig(conf)
 	tlsConf = tlsConf.Clone()
+	if t.PacketCapture != nil {
+		addKeyLogWriter(tlsConf, t.PacketCapture.KeyLogWriter())
+	}
 	setTLSConfigServerName(tlsConf, addr, host)
 	return t.doDial(ctx,
 		newSendConn(t.conn, addr, packetInfo{}, utils.DefaultLogger),
@@ -310,6 +424,8 @@ func (t *Transport) doDial(
 		t.statelessResetter,
 		config,
 		tlsConf,
+		initialRTTForPath(t.PathMetricsCache, sendConn.RemoteAddr(), config),
+		initialBandwidthForPath(t.PathMetricsCache, sendConn.RemoteAddr()),
 		initialPacketNumber,
 		use0RTT,
 		hasNegotiatedVersion,
@@ -327,6 +443,7 @@ func (t *Transport) doDial(
 	recreateChan := make(chan errCloseForRecreating, 1)
 	go func() {
 		err := conn.run()
+		conn.savePathMetrics(t.PathMetricsCache)
 		var recreateErr *errCloseForRecreating
 		if errors.As(err, &recreateErr) {
 			recreateChan <- *recreateErr
@@ -377,6 +494,14 @@ func (t *Transport) doDial(
 
 func (t *Transport) init(allowZeroLengthConnIDs bool) error {
 	t.initOnce.Do(func() {
+		if t.StatelessResetKey != nil && t.StatelessResetKeys != nil {
+			t.initErr = errors.New("quic: StatelessResetKey and StatelessResetKeys can't both be set")
+			return
+		}
+		if t.TokenGeneratorKey != nil && t.TokenGeneratorKeys != nil {
+			t.initErr = errors.New("quic: TokenGeneratorKey and TokenGeneratorKeys can't both be set")
+			return
+		}
 		var conn rawConn
 		if c, ok := t.Conn.(rawConn); ok {
 			conn = c
@@ -389,6 +514,10 @@ func (t *Transport) init(allowZeroLengthConnIDs bool) error {
 			}
 		}
 
+		if t.PacketCapture != nil {
+			conn = newCapturingConn(conn, t.PacketCapture)
+		}
+
 		t.logger = utils.DefaultLogger // TODO: make this configurable
 		t.conn = conn
 		t.handlers = make(map[protocol.ConnectionID]packetHandler)
@@ -397,13 +526,19 @@ func (t *Transport) init(allowZeroLengthConnIDs bool) error {
 
 		t.closeQueue = make(chan closePacket, 4)
 		t.statelessResetQueue = make(chan receivedPacket, 4)
-		if t.TokenGeneratorKey == nil {
-			var key TokenGeneratorKey
-			if _, err := rand.Read(key[:]); err != nil {
-				t.initErr = err
-				return
+		t.pathProbeQueue = make(chan pathProbePacket, 4)
+		if t.TokenGeneratorKeys != nil {
+			t.tokenGeneratorKeys = t.TokenGeneratorKeys
+		} else {
+			if t.TokenGeneratorKey == nil {
+				var key TokenGeneratorKey
+				if _, err := rand.Read(key[:]); err != nil {
+					t.initErr = err
+					return
+				}
+				t.TokenGeneratorKey = &key
 			}
-			t.TokenGeneratorKey = &key
+			t.tokenGeneratorKeys = NewKeyRing(*t.TokenGeneratorKey)
 		}
 
 		if t.ConnectionIDGenerator != nil {
@@ -417,7 +552,11 @@ func (t *Transport) init(allowZeroLengthConnIDs bool) error {
 			t.connIDLen = connIDLen
 			t.connIDGenerator = &protocol.DefaultConnectionIDGenerator{ConnLen: t.connIDLen}
 		}
-		t.statelessResetter = newStatelessResetter(t.StatelessResetKey)
+		if t.StatelessResetKeys != nil {
+			t.statelessResetter = newStatelessResetterWithKeyRing(t.StatelessResetKeys)
+		} else {
+			t.statelessResetter = newStatelessResetter(t.StatelessResetKey)
+		}
 
 		go func() {
 			defer close(t.listening)
@@ -449,10 +588,28 @@ func (t *Transport) runSendQueue() {
 			t.conn.WritePacket(p.payload, p.addr, p.info.OOB(), 0, protocol.ECNUnsupported)
 		case p := <-t.statelessResetQueue:
 			t.sendStatelessReset(p)
+		case p := <-t.pathProbeQueue:
+			if _, err := t.conn.WritePacket(p.buf.Data, p.addr, nil, 0, protocol.ECNUnsupported); err != nil {
+				t.logger.Debugf("Error sending path probe packet to %s: %s", p.addr, err)
+			}
+			p.buf.Release()
 		}
 	}
 }
 
+// sendPathProbe sends a path probe packet from this Transport.
+// This is used by connections that were accepted on a different Transport,
+// for the path to the server's preferred address.
+// If the queue is full, the packet is dropped. Path probes are retransmitted when lost.
+func (t *Transport) sendPathProbe(buf *packetBuffer, addr net.Addr) {
+	select {
+	case t.pathProbeQueue <- pathProbePacket{buf: buf, addr: addr}:
+	default:
+		t.logger.Debugf("Dropping path probe packet to %s: send queue full", addr)
+		buf.Release()
+	}
+}
+
 // Close stops listening for UDP datagrams on the Transport.Conn.
 // It abruptly terminates all existing connections, without sending a CONNECTION_CLOSE
 // to the peers. It is the application's responsibility to cleanly terminate existing
@@ -633,7 +790,7 @@ func (t *Transport) handlePacket(p receivedPacket) {
 }
 
 func (t *Transport) maybeSendStatelessReset(p receivedPacket) (statelessResetQueued bool) {
-	if t.StatelessResetKey == nil {
+	if t.StatelessResetKey == nil && t.StatelessResetKeys == nil {
 		return false
 	}
 
@@ -660,14 +817,23 @@ func (t *Transport) sendStatelessReset(p receivedPacket) {
 		t.logger.Errorf("error parsing connection ID on packet from %s: %s", p.remoteAddr, err)
 		return
 	}
-	token := t.statelessResetter.GetStatelessResetToken(connID)
-	t.logger.Debugf("Sending stateless reset to %s (connection ID: %s). Token: %#x", p.remoteAddr, connID, token)
-	data := make([]byte, protocol.MinStatelessResetSize-16, protocol.MinStatelessResetSize)
-	rand.Read(data)
-	data[0] = (data[0] & 0x7f) | 0x40
-	data = append(data, token[:]...)
-	if _, err := t.conn.WritePacket(data, p.remoteAddr, p.info.OOB(), 0, protocol.ECNUnsupported); err != nil {
-		t.logger.Debugf("Error sending Stateless Reset to %s: %s", p.remoteAddr, err)
+	tokens := t.statelessResetter.GetStatelessResetTokens(connID)
+	// When rotating keys, we don't know which key was used to derive the token for this connection ID,
+	// so we send one stateless reset per key.
+	// To prevent amplification, we never send more bytes than we received.
+	if n := len(p.data) / protocol.MinStatelessResetSize; len(tokens) > n {
+		tokens = tokens[:n]
+	}
+	for _, token := range tokens {
+		t.logger.Debugf("Sending stateless reset to %s (connection ID: %s). Token: %#x", p.remoteAddr, connID, token)
+		data := make([]byte, protocol.MinStatelessResetSize-16, protocol.MinStatelessResetSize)
+		rand.Read(data)
+		data[0] = (data[0] & 0x7f) | 0x40
+		data = append(data, token[:]...)
+		if _, err := t.conn.WritePacket(data, p.remoteAddr, p.info.OOB(), 0, protocol.ECNUnsupported); err != nil {
+			t.logger.Debugf("Error sending Stateless Reset to %s: %s", p.remoteAddr, err)
+			return
+		}
 	}
 }
 
@@ -14,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	internalcongestion "github.com/quic-go/quic-go/internal/congestion"
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/internal/synctest"
@@ -285,6 +286,76 @@ func TestTransportStatelessResetSending(t *testing.T) {
 	})
 }
 
+func TestTransportStatelessResetSendingKeyRotation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		const rtt = 10 * time.Millisecond
+		clientConn, serverConn, closeFn := newSimnetLink(t, rtt)
+		defer closeFn()
+
+		key1 := StatelessResetKey{1, 2, 3, 4}
+		key2 := StatelessResetKey{5, 6, 7, 8}
+		tr := &Transport{
+			Conn:               serverConn,
+			ConnectionIDLength: 4,
+			StatelessResetKeys: NewKeyRing(key2, key1),
+		}
+		require.NoError(t, tr.init(true))
+		defer tr.Close()
+
+		connID := protocol.ParseConnectionID([]byte{9, 10, 11, 12})
+		b, err := wire.AppendShortHeader(nil, connID, 1337, 2, protocol.KeyPhaseOne)
+		require.NoError(t, err)
+
+		readStatelessResets := func() [][]byte {
+			var resets [][]byte
+			for {
+				clientConn.SetReadDeadline(time.Now().Add(rtt * 2))
+				p := make([]byte, 1024)
+				n, _, err := clientConn.ReadFrom(p)
+				if err != nil {
+					return resets
+				}
+				resets = append(resets, p[:n])
+			}
+		}
+
+		// one stateless reset is sent per key
+		_, err = clientConn.WriteTo(append(b, make([]byte, 2*protocol.MinStatelessResetSize-len(b))...), tr.Conn.LocalAddr())
+		require.NoError(t, err)
+		resets := readStatelessResets()
+		require.Len(t, resets, 2)
+		token2 := newStatelessResetter(&key2).GetStatelessResetToken(connID)
+		token1 := newStatelessResetter(&key1).GetStatelessResetToken(connID)
+		require.Equal(t, token2[:], resets[0][len(resets[0])-16:])
+		require.Equal(t, token1[:], resets[1][len(resets[1])-16:])
+
+		// but never more bytes than were received
+		_, err = clientConn.WriteTo(append(b, make([]byte, 2*protocol.MinStatelessResetSize-len(b)-1)...), tr.Conn.LocalAddr())
+		require.NoError(t, err)
+		resets = readStatelessResets()
+		require.Len(t, resets, 1)
+		require.Equal(t, token2[:], resets[0][len(resets[0])-16:])
+	})
+}
+
+func TestTransportKeyConfiguration(t *testing.T) {
+	tr := &Transport{
+		Conn:               newUDPConnLocalhost(t),
+		StatelessResetKey:  &StatelessResetKey{},
+		StatelessResetKeys: NewKeyRing(StatelessResetKey{}),
+	}
+	_, err := tr.Listen(&tls.Config{}, nil)
+	require.EqualError(t, err, "quic: StatelessResetKey and StatelessResetKeys can't both be set")
+
+	tr = &Transport{
+		Conn:               newUDPConnLocalhost(t),
+		TokenGeneratorKey:  &TokenGeneratorKey{},
+		TokenGeneratorKeys: NewKeyRing(TokenGeneratorKey{}),
+	}
+	_, err = tr.Listen(&tls.Config{}, nil)
+	require.EqualError(t, err, "quic: TokenGeneratorKey and TokenGeneratorKeys can't both be set")
+}
+
 func TestTransportUnparseableQUICPackets(t *testing.T) {
 	synctest.Test(t, func(t *testing.T) {
 		const rtt = 10 * time.Millisecond
@@ -381,6 +452,27 @@ func TestTransportListening(t *testing.T) {
 	})
 }
 
+func TestTransportListenPreferredAddress(t *testing.T) {
+	tr := &Transport{Conn: newUDPConnLocalhost(t), ConnectionIDLength: 5}
+	defer tr.Close()
+	preferredTr := &Transport{Conn: newUDPConnLocalhost(t), ConnectionIDLength: 4}
+	defer preferredTr.Close()
+	preferredAddr := preferredTr.Conn.LocalAddr().(*net.UDPAddr).AddrPort()
+
+	_, err := tr.Listen(&tls.Config{}, &Config{
+		PreferredAddress: &PreferredAddress{IPv4: preferredAddr, Transport: preferredTr},
+	})
+	require.EqualError(t, err, "invalid preferred address: connection ID length mismatch: 4 vs. 5")
+
+	tr2 := &Transport{Conn: newUDPConnLocalhost(t), ConnectionIDLength: 4}
+	defer tr2.Close()
+	ln, err := tr2.Listen(&tls.Config{}, &Config{
+		PreferredAddress: &PreferredAddress{IPv4: preferredAddr, Transport: preferredTr},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+}
+
 func TestTransportNonQUICPackets(t *testing.T) {
 	synctest.Test(t, func(t *testing.T) {
 		const rtt = 10 * time.Millisecond
@@ -530,6 +622,8 @@ func testTransportDial(t *testing.T, early bool) {
 			_ *statelessResetter,
 			_ *Config,
 			_ *tls.Config,
+			_ time.Duration,
+			_ internalcongestion.Bandwidth,
 			_ protocol.PacketNumber,
 			_ bool,
 			_ bool,
@@ -606,6 +700,8 @@ func TestTransportDialingVersionNegotiation(t *testing.T) {
 		_ *statelessResetter,
 		_ *Config,
 		_ *tls.Config,
+		_ time.Duration,
+		_ internalcongestion.Bandwidth,
 		pn protocol.PacketNumber,
 		_ bool,
 		hasNegotiatedVersion bool,